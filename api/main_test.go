@@ -36,6 +36,14 @@ func (m *mockBalanceClient) GetBalance(ctx context.Context, in *balancepb.Accoun
 	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
 }
 
+func (m *mockBalanceClient) OpenAccount(ctx context.Context, in *balancepb.OpenAccountRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
 func (m *mockBalanceClient) AuthorizeDebit(ctx context.Context, in *balancepb.AuthorizeDebitRequest, opts ...grpc.CallOption) (*balancepb.DebitResult, error) {
 	args := m.Called(ctx, in)
 	if args.Get(0) == nil {
@@ -52,6 +60,70 @@ func (m *mockBalanceClient) CreditAccount(ctx context.Context, in *balancepb.Cre
 	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
 }
 
+func (m *mockBalanceClient) ListLedgerEntries(ctx context.Context, in *balancepb.ListLedgerEntriesRequest, opts ...grpc.CallOption) (*balancepb.LedgerEntries, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.LedgerEntries), args.Error(1)
+}
+
+func (m *mockBalanceClient) CaptureHold(ctx context.Context, in *balancepb.CaptureHoldRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) ReleaseHold(ctx context.Context, in *balancepb.HoldID, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) ReduceHold(ctx context.Context, in *balancepb.ReduceHoldRequest, opts ...grpc.CallOption) (*balancepb.ReduceHoldResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.ReduceHoldResult), args.Error(1)
+}
+
+func (m *mockBalanceClient) ExpireHolds(ctx context.Context, in *balancepb.ExpireHoldsRequest, opts ...grpc.CallOption) (*balancepb.ExpireHoldsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.ExpireHoldsResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) SetOverdraftLimit(ctx context.Context, in *balancepb.SetOverdraftLimitRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) AccrueOverdraftCharges(ctx context.Context, in *balancepb.AccrueOverdraftChargesRequest, opts ...grpc.CallOption) (*balancepb.AccrueOverdraftChargesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.AccrueOverdraftChargesResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) Transfer(ctx context.Context, in *balancepb.TransferRequest, opts ...grpc.CallOption) (*balancepb.TransferResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.TransferResult), args.Error(1)
+}
+
 type mockFeedClient struct{ mock.Mock }
 
 func (m *mockFeedClient) AddFeedItem(ctx context.Context, in *feedpb.AddFeedItemRequest, opts ...grpc.CallOption) (*feedpb.FeedItem, error) {
@@ -112,6 +184,14 @@ func (m *mockTransactionsClient) UpdateTransaction(ctx context.Context, in *tran
 	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
 }
 
+func (m *mockTransactionsClient) GetTransactionByAuthCode(ctx context.Context, in *transactionspb.AuthCodeQuery, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
 type mockMerchantClient struct{ mock.Mock }
 
 func (m *mockMerchantClient) GetMerchant(ctx context.Context, in *merchantpb.MerchantID, opts ...grpc.CallOption) (*merchantpb.MerchantData, error) {
@@ -164,6 +244,142 @@ func (m *mockCardsClient) UpdateCardStatus(ctx context.Context, in *cardspb.Upda
 	return args.Get(0).(*cardspb.Card), args.Error(1)
 }
 
+func (m *mockCardsClient) GetCardHistory(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.CardHistory, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardHistory), args.Error(1)
+}
+
+func (m *mockCardsClient) ListVirtualCards(ctx context.Context, in *cardspb.ListVirtualCardsRequest, opts ...grpc.CallOption) (*cardspb.VirtualCards, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.VirtualCards), args.Error(1)
+}
+
+func (m *mockCardsClient) LockCardToMerchant(ctx context.Context, in *cardspb.LockCardToMerchantRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) UseSingleUseCard(ctx context.Context, in *cardspb.UseSingleUseCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) SetCardNickname(ctx context.Context, in *cardspb.SetCardNicknameRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) SetDefaultCard(ctx context.Context, in *cardspb.SetDefaultCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) GetCardControls(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.CardControls, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardControls), args.Error(1)
+}
+
+func (m *mockCardsClient) UpdateCardControls(ctx context.Context, in *cardspb.CardControls, opts ...grpc.CallOption) (*cardspb.CardControls, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardControls), args.Error(1)
+}
+
+func (m *mockCardsClient) ReissueCard(ctx context.Context, in *cardspb.ReissueCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) AddRecurringMerchant(ctx context.Context, in *cardspb.RecurringMerchant, opts ...grpc.CallOption) (*cardspb.RecurringMerchant, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RecurringMerchant), args.Error(1)
+}
+
+func (m *mockCardsClient) ListRecurringMerchants(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.RecurringMerchants, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RecurringMerchants), args.Error(1)
+}
+
+func (m *mockCardsClient) GetCardByPanToken(ctx context.Context, in *cardspb.GetCardByPanTokenRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) ListCardsByUser(ctx context.Context, in *cardspb.ListCardsByUserRequest, opts ...grpc.CallOption) (*cardspb.CardList, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardList), args.Error(1)
+}
+
+func (m *mockCardsClient) SetPin(ctx context.Context, in *cardspb.SetPinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
+func (m *mockCardsClient) ChangePin(ctx context.Context, in *cardspb.ChangePinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
+func (m *mockCardsClient) RevealPin(ctx context.Context, in *cardspb.RevealPinRequest, opts ...grpc.CallOption) (*cardspb.RevealPinResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RevealPinResponse), args.Error(1)
+}
+
+func (m *mockCardsClient) VerifyPin(ctx context.Context, in *cardspb.VerifyPinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
 type mockDiscoClient struct{ mock.Mock }
 
 func (m *mockDiscoClient) CreateSession(ctx context.Context, in *discopb.CreateSessionRequest, opts ...grpc.CallOption) (*discopb.CreateSessionResponse, error) {
@@ -487,7 +703,7 @@ func TestFreezeCardHandler_Success(t *testing.T) {
 	s, _, _, _, _, mockCards, _ := newTestServer(t)
 
 	cardID := "card-to-freeze"
-	expectedResp := &cardspb.Card{Id: cardID, UserId: "user-1", Status: "FROZEN", LastFour: "1111"}
+	expectedResp := &cardspb.Card{CardId: cardID, UserId: "user-1", Status: "FROZEN", LastFour: "1111"}
 
 	// Mock UpdateCardStatus call
 	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN"}).
@@ -511,7 +727,7 @@ func TestFreezeCardHandler_Success(t *testing.T) {
 	var resp cardspb.Card
 	err = json.Unmarshal(rec.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedResp.Id, resp.Id)
+	assert.Equal(t, expectedResp.CardId, resp.CardId)
 	assert.Equal(t, expectedResp.Status, resp.Status)
 
 	mockCards.AssertExpectations(t)
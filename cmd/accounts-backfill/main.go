@@ -0,0 +1,53 @@
+// Command accounts-backfill opens a personal account for every user from before the accounts service
+// existed, whose money the balance service holds under their user ID. It is run once, after the
+// accounts service has migrated its database, and can be run again if it fails part way.
+//
+// It reads the accounts service's configuration, such as ACCOUNTS_DB_DSN, and opens balances through
+// the balance service at ACCOUNTS_BALANCE_ADDR, which must be running. It never reads or writes the
+// balance service's database.
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/manifoldfinance/disco2/v2/internal/accounts/config"
+	"github.com/manifoldfinance/disco2/v2/internal/accounts/db"
+	"github.com/manifoldfinance/disco2/v2/internal/accounts/service"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	database, err := db.Connect(cfg.DBDSN)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	balanceConn, err := grpc.Dial(cfg.BalanceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to balance service: %v", err)
+	}
+	defer balanceConn.Close()
+
+	// Stop between users on SIGINT/SIGTERM; running again picks up where this run stopped
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	accountsService := service.NewAccountsService(database, balancepb.NewBalanceClient(balanceConn))
+	opened, err := accountsService.BackfillPersonalAccounts(ctx)
+	if err != nil {
+		log.Fatalf("Backfill stopped after opening %d accounts: %v", opened, err)
+	}
+	log.Printf("Backfilled %d personal accounts", opened)
+}
@@ -0,0 +1,226 @@
+// Package main is the entry point for the accounts service
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/internal/accounts/config"
+	"github.com/manifoldfinance/disco2/v2/internal/accounts/db"
+	"github.com/manifoldfinance/disco2/v2/internal/accounts/service"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Database connection setup
+	database, err := db.Connect(cfg.DBDSN)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	// Run database migrations
+	if err := db.RunMigrations(cfg.DBDSN, "file://migrations/accounts"); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Connect to the balance service, which holds the money in accounts
+	balanceConn, err := grpc.Dial(cfg.BalanceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to balance service: %v", err)
+	}
+	defer balanceConn.Close()
+
+	// Create accounts service
+	accountsService := service.NewAccountsService(database, balancepb.NewBalanceClient(balanceConn))
+
+	// Create HTTP server
+	h := &handlers{svc: accountsService}
+	e := echo.New()
+	e.POST("/users", h.createUser)
+	e.GET("/users/:user_id", h.getUser)
+	e.GET("/users/:user_id/accounts", h.listAccountsByUser)
+	e.POST("/accounts", h.createAccount)
+	e.GET("/accounts/:account_id", h.getAccount)
+	e.PUT("/accounts/:account_id/holders/:user_id", h.addAccountHolder)
+	e.DELETE("/accounts/:account_id/holders/:user_id", h.removeAccountHolder)
+	e.PUT("/cards/:card_id/account", h.linkCard)
+
+	// Start HTTP server in a goroutine
+	httpServer := &http.Server{
+		Addr:    cfg.HTTPPort,
+		Handler: e,
+	}
+	go func() {
+		log.Printf("HTTP server starting on %s", cfg.HTTPPort)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start HTTP server: %v", err)
+		}
+	}()
+
+	// Create gRPC server
+	grpcServer := grpc.NewServer()
+	pb.RegisterAccountsServer(grpcServer, accountsService)
+
+	// Start gRPC server in a goroutine
+	lis, err := net.Listen("tcp", cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", cfg.GRPCPort, err)
+	}
+	go func() {
+		log.Printf("gRPC server listening on %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve gRPC: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shut down the servers
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down servers...")
+
+	// Shutdown HTTP server
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Fatalf("Server shutdown error: %v", err)
+	}
+
+	// Shutdown gRPC server
+	grpcServer.GracefulStop()
+
+	log.Println("Servers successfully shut down.")
+}
+
+// handlers serves the accounts HTTP API
+type handlers struct {
+	svc *service.AccountsService
+}
+
+func (h *handlers) createUser(c echo.Context) error {
+	req := new(pb.CreateUserRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	user, err := h.svc.CreateUser(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, user)
+}
+
+func (h *handlers) getUser(c echo.Context) error {
+	user, err := h.svc.GetUser(c.Request().Context(), &pb.GetUserRequest{UserId: c.Param("user_id")})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *handlers) listAccountsByUser(c echo.Context) error {
+	includeClosed, _ := strconv.ParseBool(c.QueryParam("include_closed"))
+	req := &pb.ListAccountsByUserRequest{UserId: c.Param("user_id"), IncludeClosed: includeClosed}
+
+	accounts, err := h.svc.ListAccountsByUser(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, accounts)
+}
+
+func (h *handlers) createAccount(c echo.Context) error {
+	req := new(pb.CreateAccountRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	account, err := h.svc.CreateAccount(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, account)
+}
+
+func (h *handlers) getAccount(c echo.Context) error {
+	account, err := h.svc.GetAccount(c.Request().Context(), &pb.GetAccountRequest{AccountId: c.Param("account_id")})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+func (h *handlers) addAccountHolder(c echo.Context) error {
+	req := &pb.AccountHolderRequest{AccountId: c.Param("account_id"), UserId: c.Param("user_id")}
+
+	account, err := h.svc.AddAccountHolder(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+func (h *handlers) removeAccountHolder(c echo.Context) error {
+	req := &pb.AccountHolderRequest{AccountId: c.Param("account_id"), UserId: c.Param("user_id")}
+
+	account, err := h.svc.RemoveAccountHolder(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+func (h *handlers) linkCard(c echo.Context) error {
+	req := new(pb.LinkCardRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.CardId = c.Param("card_id")
+
+	cardAccount, err := h.svc.LinkCard(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, cardAccount)
+}
+
+// errorResponse maps a gRPC error from the accounts service to an HTTP error response
+func errorResponse(c echo.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.PermissionDenied:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": st.Message()})
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
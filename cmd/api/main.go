@@ -19,22 +19,30 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
 	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
 	cardspb "github.com/manifoldfinance/disco2/v2/pkg/pb/cards"
 	discopb "github.com/manifoldfinance/disco2/v2/pkg/pb/disco"
 	feedpb "github.com/manifoldfinance/disco2/v2/pkg/pb/feed"
 	merchantpb "github.com/manifoldfinance/disco2/v2/pkg/pb/merchant"
+	schedulerpb "github.com/manifoldfinance/disco2/v2/pkg/pb/scheduler"
 	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
 )
 
+// userIDHeader is the header the authenticating proxy in front of the gateway sets to the ID of the
+// user making a request
+const userIDHeader = "X-User-Id"
+
 // apiServer holds gRPC client connections to all the services
 type apiServer struct {
+	accountsClient     accountspb.AccountsClient
 	balanceClient      balancepb.BalanceClient
 	feedClient         feedpb.FeedClient
 	transactionsClient transactionspb.TransactionsClient
 	merchantClient     merchantpb.MerchantClient
 	cardsClient        cardspb.CardsClient
 	discoClient        discopb.DiscoPaymentGatewayClient
+	schedulerClient    schedulerpb.SchedulerClient
 }
 
 func main() {
@@ -44,6 +52,14 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Set up gRPC client for Accounts service
+	accountsConn, err := grpc.Dial(cfg.ServicesURLs["accounts"], grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to Accounts service: %v", err)
+	}
+	defer accountsConn.Close()
+	accountsClient := accountspb.NewAccountsClient(accountsConn)
+
 	// Set up gRPC client for Balance service
 	balanceConn, err := grpc.Dial(cfg.ServicesURLs["balance"], grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -92,13 +108,23 @@ func main() {
 	defer discoConn.Close()
 	discoClient := discopb.NewDiscoPaymentGatewayClient(discoConn)
 
+	// Set up gRPC client for Scheduler service
+	schedulerConn, err := grpc.Dial(cfg.ServicesURLs["scheduler"], grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to Scheduler service: %v", err)
+	}
+	defer schedulerConn.Close()
+	schedulerClient := schedulerpb.NewSchedulerClient(schedulerConn)
+
 	s := &apiServer{
+		accountsClient:     accountsClient,
 		balanceClient:      balanceClient,
 		feedClient:         feedClient,
 		transactionsClient: transactionsClient,
 		merchantClient:     merchantClient,
 		cardsClient:        cardsClient,
 		discoClient:        discoClient,
+		schedulerClient:    schedulerClient,
 	}
 
 	// Set up Echo HTTP server
@@ -106,7 +132,16 @@ func main() {
 	// Add HTTP routes here
 	e.GET("/account/balance/:account_id", s.getBalanceHandler)
 	e.GET("/feed/:account_id", s.getFeedHandler)
-	e.POST("/cards/:id/freeze", s.freezeCardHandler)
+
+	// Add routes for managing the caller's own cards
+	e.GET("/cards", s.listCardsHandler)
+	e.POST("/cards/:id/freeze", s.freezeCardHandler, s.requireCardOwner)
+	e.GET("/cards/:id/controls", s.getCardControlsHandler, s.requireCardOwner)
+	e.PUT("/cards/:id/controls", s.updateCardControlsHandler, s.requireCardOwner)
+	e.POST("/cards/:id/cancel", s.cancelCardHandler, s.requireCardOwner)
+	e.POST("/cards/:id/replace", s.replaceCardHandler, s.requireCardOwner)
+	e.PUT("/cards/:id/nickname", s.setCardNicknameHandler, s.requireCardOwner)
+	e.POST("/cards/:id/default", s.setDefaultCardHandler, s.requireCardOwner)
 
 	// Add Disco Payment Gateway routes
 	discoGroup := e.Group("/payments/disco")
@@ -116,6 +151,13 @@ func main() {
 	discoGroup.POST("/estimate", s.estimateDiscoPaymentAmountHandler)
 	discoGroup.POST("/wallet", s.createDiscoWalletHandler)
 
+	// Add scheduled payment routes for accounts the caller holds
+	e.POST("/accounts/:account_id/schedules", s.createScheduleHandler, s.requireAccountHolder)
+	e.GET("/accounts/:account_id/schedules", s.listSchedulesHandler, s.requireAccountHolder)
+	e.GET("/schedules/:schedule_id", s.getScheduleHandler, s.requireScheduleHolder)
+	e.DELETE("/schedules/:schedule_id", s.cancelScheduleHandler, s.requireScheduleHolder)
+	e.GET("/schedules/:schedule_id/runs", s.listScheduleRunsHandler, s.requireScheduleHolder)
+
 	// Start HTTP server in a goroutine
 	httpServer := &http.Server{
 		Addr:    cfg.HTTPPort,
@@ -310,12 +352,14 @@ func (s *apiServer) freezeCardHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "card ID path parameter is required"})
 	}
 
-	// Assume the request body is empty or contains minimal info, the action is implied by the endpoint
-	// In a real API, you might validate user ownership of the card here using auth context
+	// Assume the request body is empty or contains minimal info, the action is implied by the endpoint.
+	// requireCardOwner has already checked the caller owns the card.
 
 	req := &cardspb.UpdateCardStatusRequest{
 		CardId:    cardID,
 		NewStatus: "FROZEN", // Hardcode status to FROZEN
+		Reason:    "frozen by cardholder",
+		Actor:     c.Request().Header.Get(userIDHeader),
 	}
 
 	card, err := s.cardsClient.UpdateCardStatus(c.Request().Context(), req)
@@ -328,6 +372,8 @@ func (s *apiServer) freezeCardHandler(c echo.Context) error {
 				return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
 			case codes.InvalidArgument:
 				return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+			case codes.FailedPrecondition:
+				return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
 			case codes.Internal:
 				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 			default:
@@ -342,6 +388,178 @@ func (s *apiServer) freezeCardHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, card)
 }
 
+func (s *apiServer) getCardControlsHandler(c echo.Context) error {
+	cardID := c.Param("id")
+	if cardID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "card ID path parameter is required"})
+	}
+
+	controls, err := s.cardsClient.GetCardControls(c.Request().Context(), &cardspb.GetCardRequest{CardId: cardID})
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, controls)
+}
+
+// updateCardControlsHandler replaces a card's spending controls with those in the request body.
+// Controls left out of the body are cleared.
+func (s *apiServer) updateCardControlsHandler(c echo.Context) error {
+	cardID := c.Param("id")
+	if cardID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "card ID path parameter is required"})
+	}
+
+	req := new(cardspb.CardControls)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.CardId = cardID
+
+	controls, err := s.cardsClient.UpdateCardControls(c.Request().Context(), req)
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, controls)
+}
+
+// requireCardOwner only lets requests about a card through to next if the caller owns the card.
+// Other users' cards are reported as not found, so their IDs can't be probed.
+func (s *apiServer) requireCardOwner(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID := c.Request().Header.Get(userIDHeader)
+		if userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "caller not authenticated"})
+		}
+		cardID := c.Param("id")
+		if cardID == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "card ID path parameter is required"})
+		}
+
+		card, err := s.cardsClient.GetCard(c.Request().Context(), &cardspb.GetCardRequest{CardId: cardID})
+		if err != nil {
+			return cardsErrorResponse(c, err)
+		}
+		if card.GetUserId() != userID {
+			log.Printf("user %s asked about card %s of another user", userID, cardID)
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "card not found"})
+		}
+		return next(c)
+	}
+}
+
+// listCardsHandler lists the caller's cards, newest first. Repeat the status parameter to list
+// cards with any of several statuses; before_id is the next_before_id of the previous page.
+func (s *apiServer) listCardsHandler(c echo.Context) error {
+	userID := c.Request().Header.Get(userIDHeader)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "caller not authenticated"})
+	}
+
+	req := &cardspb.ListCardsByUserRequest{
+		UserId:   userID,
+		Statuses: c.QueryParams()["status"],
+		BeforeId: c.QueryParam("before_id"),
+	}
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.ParseUint(limitStr, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit parameter"})
+		}
+		req.Limit = uint32(limit)
+	}
+
+	cards, err := s.cardsClient.ListCardsByUser(c.Request().Context(), req)
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, cards)
+}
+
+// cancelCardHandler closes one of the caller's cards for good, without replacing it
+func (s *apiServer) cancelCardHandler(c echo.Context) error {
+	req := &cardspb.UpdateCardStatusRequest{
+		CardId:    c.Param("id"),
+		NewStatus: "CLOSED",
+		Reason:    "cancelled by cardholder",
+		Actor:     c.Request().Header.Get(userIDHeader),
+	}
+
+	card, err := s.cardsClient.UpdateCardStatus(c.Request().Context(), req)
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, card)
+}
+
+// replaceCardHandler cancels one of the caller's cards and issues its replacement, which is
+// returned with the last four digits of its number; the number and CVV stay in the vault. The
+// body gives the reason, e.g. {"reason": "LOST"}.
+func (s *apiServer) replaceCardHandler(c echo.Context) error {
+	var replaceReq struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&replaceReq); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	req := &cardspb.ReissueCardRequest{
+		CardId: c.Param("id"),
+		Reason: replaceReq.Reason,
+		Actor:  c.Request().Header.Get(userIDHeader),
+	}
+
+	card, err := s.cardsClient.ReissueCard(c.Request().Context(), req)
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, card)
+}
+
+// setCardNicknameHandler names one of the caller's cards; an empty nickname removes its name
+func (s *apiServer) setCardNicknameHandler(c echo.Context) error {
+	var nicknameReq struct {
+		Nickname string `json:"nickname"`
+	}
+	if err := c.Bind(&nicknameReq); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	card, err := s.cardsClient.SetCardNickname(c.Request().Context(), &cardspb.SetCardNicknameRequest{CardId: c.Param("id"), Nickname: nicknameReq.Nickname})
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, card)
+}
+
+// setDefaultCardHandler makes one of the caller's cards their default card
+func (s *apiServer) setDefaultCardHandler(c echo.Context) error {
+	card, err := s.cardsClient.SetDefaultCard(c.Request().Context(), &cardspb.SetDefaultCardRequest{CardId: c.Param("id")})
+	if err != nil {
+		return cardsErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, card)
+}
+
+// cardsErrorResponse maps a gRPC error from the cards service to an HTTP error response
+func cardsErrorResponse(c echo.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		log.Printf("unexpected error from cards service: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		log.Printf("gRPC error from cards service: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
 // --- Disco Payment Gateway Handlers ---
 
 func (s *apiServer) createDiscoSessionHandler(c echo.Context) error {
@@ -443,3 +661,161 @@ func (s *apiServer) createDiscoWalletHandler(c echo.Context) error {
 	}
 	return c.JSON(http.StatusCreated, resp)
 }
+
+// --- Scheduled Payment Handlers ---
+
+// requireAccountHolder only lets requests about an account through to next if the caller holds
+// the account. Accounts the caller doesn't hold are reported as not found, so their IDs can't be probed.
+func (s *apiServer) requireAccountHolder(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID := c.Request().Header.Get(userIDHeader)
+		if userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "caller not authenticated"})
+		}
+		accountID := c.Param("account_id")
+		if accountID == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "account_id path parameter is required"})
+		}
+
+		if err := s.checkAccountHolder(c.Request().Context(), userID, accountID); err != nil {
+			return accountsErrorResponse(c, err)
+		}
+		return next(c)
+	}
+}
+
+// requireScheduleHolder only lets requests about a schedule through to next if the caller holds
+// the account it pays from. Other schedules are reported as not found.
+func (s *apiServer) requireScheduleHolder(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID := c.Request().Header.Get(userIDHeader)
+		if userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "caller not authenticated"})
+		}
+
+		schedule, err := s.schedulerClient.GetSchedule(c.Request().Context(), &schedulerpb.ScheduleID{ScheduleId: c.Param("schedule_id")})
+		if err != nil {
+			return schedulerErrorResponse(c, err)
+		}
+		if err := s.checkAccountHolder(c.Request().Context(), userID, schedule.GetAccountId()); err != nil {
+			if status.Code(err) == codes.NotFound {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "schedule not found"})
+			}
+			return accountsErrorResponse(c, err)
+		}
+		return next(c)
+	}
+}
+
+// checkAccountHolder returns a NotFound error unless the user holds the account
+func (s *apiServer) checkAccountHolder(ctx context.Context, userID, accountID string) error {
+	account, err := s.accountsClient.GetAccount(ctx, &accountspb.GetAccountRequest{AccountId: accountID})
+	if status.Code(err) == codes.InvalidArgument {
+		return status.Errorf(codes.NotFound, "account not found")
+	}
+	if err != nil {
+		return err
+	}
+	for _, holder := range account.GetHolderUserIds() {
+		if holder == userID {
+			return nil
+		}
+	}
+	log.Printf("user %s asked about account %s they don't hold", userID, accountID)
+	return status.Errorf(codes.NotFound, "account not found")
+}
+
+// accountsErrorResponse maps a gRPC error from the accounts service to an HTTP error response
+func accountsErrorResponse(c echo.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		log.Printf("unexpected error from accounts service: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	default:
+		log.Printf("gRPC error from accounts service: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+func (s *apiServer) createScheduleHandler(c echo.Context) error {
+	req := new(schedulerpb.CreateScheduleRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.AccountId = c.Param("account_id")
+
+	schedule, err := s.schedulerClient.CreateSchedule(c.Request().Context(), req)
+	if err != nil {
+		return schedulerErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+func (s *apiServer) listSchedulesHandler(c echo.Context) error {
+	includeInactive, _ := strconv.ParseBool(c.QueryParam("include_inactive"))
+	req := &schedulerpb.ListSchedulesRequest{AccountId: c.Param("account_id"), IncludeInactive: includeInactive}
+
+	schedules, err := s.schedulerClient.ListSchedules(c.Request().Context(), req)
+	if err != nil {
+		return schedulerErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, schedules)
+}
+
+func (s *apiServer) getScheduleHandler(c echo.Context) error {
+	schedule, err := s.schedulerClient.GetSchedule(c.Request().Context(), &schedulerpb.ScheduleID{ScheduleId: c.Param("schedule_id")})
+	if err != nil {
+		return schedulerErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (s *apiServer) cancelScheduleHandler(c echo.Context) error {
+	schedule, err := s.schedulerClient.CancelSchedule(c.Request().Context(), &schedulerpb.ScheduleID{ScheduleId: c.Param("schedule_id")})
+	if err != nil {
+		return schedulerErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (s *apiServer) listScheduleRunsHandler(c echo.Context) error {
+	limit := uint32(0)
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsedLimit, err := strconv.ParseUint(limitStr, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit parameter"})
+		}
+		limit = uint32(parsedLimit)
+	}
+	req := &schedulerpb.ListScheduleRunsRequest{ScheduleId: c.Param("schedule_id"), Limit: limit}
+
+	runs, err := s.schedulerClient.ListScheduleRuns(c.Request().Context(), req)
+	if err != nil {
+		return schedulerErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, runs)
+}
+
+// schedulerErrorResponse maps a gRPC error from the scheduler service to an HTTP error response
+func schedulerErrorResponse(c echo.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		log.Printf("unexpected error from scheduler service: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		log.Printf("gRPC error from scheduler service: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	discopb "github.com/manifoldfinance/disco2/v2/disco_payment_gateway/disco_payment_gateway"
 	feedpb "github.com/manifoldfinance/disco2/v2/feed/feed"
 	merchantpb "github.com/manifoldfinance/disco2/v2/merchant/merchant"
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	schedulerpb "github.com/manifoldfinance/disco2/v2/pkg/pb/scheduler"
 	transactionspb "github.com/manifoldfinance/disco2/v2/transactions/transactions"
 )
 
@@ -36,6 +38,14 @@ func (m *mockBalanceClient) GetBalance(ctx context.Context, in *balancepb.Accoun
 	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
 }
 
+func (m *mockBalanceClient) OpenAccount(ctx context.Context, in *balancepb.OpenAccountRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
 func (m *mockBalanceClient) AuthorizeDebit(ctx context.Context, in *balancepb.AuthorizeDebitRequest, opts ...grpc.CallOption) (*balancepb.DebitResult, error) {
 	args := m.Called(ctx, in)
 	if args.Get(0) == nil {
@@ -52,6 +62,70 @@ func (m *mockBalanceClient) CreditAccount(ctx context.Context, in *balancepb.Cre
 	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
 }
 
+func (m *mockBalanceClient) ListLedgerEntries(ctx context.Context, in *balancepb.ListLedgerEntriesRequest, opts ...grpc.CallOption) (*balancepb.LedgerEntries, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.LedgerEntries), args.Error(1)
+}
+
+func (m *mockBalanceClient) CaptureHold(ctx context.Context, in *balancepb.CaptureHoldRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) ReleaseHold(ctx context.Context, in *balancepb.HoldID, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) ReduceHold(ctx context.Context, in *balancepb.ReduceHoldRequest, opts ...grpc.CallOption) (*balancepb.ReduceHoldResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.ReduceHoldResult), args.Error(1)
+}
+
+func (m *mockBalanceClient) ExpireHolds(ctx context.Context, in *balancepb.ExpireHoldsRequest, opts ...grpc.CallOption) (*balancepb.ExpireHoldsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.ExpireHoldsResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) SetOverdraftLimit(ctx context.Context, in *balancepb.SetOverdraftLimitRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) AccrueOverdraftCharges(ctx context.Context, in *balancepb.AccrueOverdraftChargesRequest, opts ...grpc.CallOption) (*balancepb.AccrueOverdraftChargesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.AccrueOverdraftChargesResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) Transfer(ctx context.Context, in *balancepb.TransferRequest, opts ...grpc.CallOption) (*balancepb.TransferResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.TransferResult), args.Error(1)
+}
+
 type mockFeedClient struct{ mock.Mock }
 
 func (m *mockFeedClient) AddFeedItem(ctx context.Context, in *feedpb.AddFeedItemRequest, opts ...grpc.CallOption) (*feedpb.FeedItem, error) {
@@ -112,6 +186,14 @@ func (m *mockTransactionsClient) UpdateTransaction(ctx context.Context, in *tran
 	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
 }
 
+func (m *mockTransactionsClient) GetTransactionByAuthCode(ctx context.Context, in *transactionspb.AuthCodeQuery, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
 type mockMerchantClient struct{ mock.Mock }
 
 func (m *mockMerchantClient) GetMerchant(ctx context.Context, in *merchantpb.MerchantID, opts ...grpc.CallOption) (*merchantpb.MerchantData, error) {
@@ -164,6 +246,142 @@ func (m *mockCardsClient) UpdateCardStatus(ctx context.Context, in *cardspb.Upda
 	return args.Get(0).(*cardspb.Card), args.Error(1)
 }
 
+func (m *mockCardsClient) GetCardHistory(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.CardHistory, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardHistory), args.Error(1)
+}
+
+func (m *mockCardsClient) ListVirtualCards(ctx context.Context, in *cardspb.ListVirtualCardsRequest, opts ...grpc.CallOption) (*cardspb.VirtualCards, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.VirtualCards), args.Error(1)
+}
+
+func (m *mockCardsClient) LockCardToMerchant(ctx context.Context, in *cardspb.LockCardToMerchantRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) UseSingleUseCard(ctx context.Context, in *cardspb.UseSingleUseCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) SetCardNickname(ctx context.Context, in *cardspb.SetCardNicknameRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) SetDefaultCard(ctx context.Context, in *cardspb.SetDefaultCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) GetCardControls(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.CardControls, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardControls), args.Error(1)
+}
+
+func (m *mockCardsClient) UpdateCardControls(ctx context.Context, in *cardspb.CardControls, opts ...grpc.CallOption) (*cardspb.CardControls, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardControls), args.Error(1)
+}
+
+func (m *mockCardsClient) ReissueCard(ctx context.Context, in *cardspb.ReissueCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) AddRecurringMerchant(ctx context.Context, in *cardspb.RecurringMerchant, opts ...grpc.CallOption) (*cardspb.RecurringMerchant, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RecurringMerchant), args.Error(1)
+}
+
+func (m *mockCardsClient) ListRecurringMerchants(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.RecurringMerchants, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RecurringMerchants), args.Error(1)
+}
+
+func (m *mockCardsClient) GetCardByPanToken(ctx context.Context, in *cardspb.GetCardByPanTokenRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) ListCardsByUser(ctx context.Context, in *cardspb.ListCardsByUserRequest, opts ...grpc.CallOption) (*cardspb.CardList, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardList), args.Error(1)
+}
+
+func (m *mockCardsClient) SetPin(ctx context.Context, in *cardspb.SetPinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
+func (m *mockCardsClient) ChangePin(ctx context.Context, in *cardspb.ChangePinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
+func (m *mockCardsClient) RevealPin(ctx context.Context, in *cardspb.RevealPinRequest, opts ...grpc.CallOption) (*cardspb.RevealPinResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RevealPinResponse), args.Error(1)
+}
+
+func (m *mockCardsClient) VerifyPin(ctx context.Context, in *cardspb.VerifyPinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
 type mockDiscoClient struct{ mock.Mock }
 
 func (m *mockDiscoClient) CreateSession(ctx context.Context, in *discopb.CreateSessionRequest, opts ...grpc.CallOption) (*discopb.CreateSessionResponse, error) {
@@ -214,8 +432,52 @@ func (m *mockDiscoClient) EstimatePaymentAmount(ctx context.Context, in *discopb
 	return args.Get(0).(*discopb.EstimatePaymentAmountResponse), args.Error(1)
 }
 
+// mockSchedulerClient only mocks the scheduler RPCs the handlers under test call
+type mockSchedulerClient struct {
+	mock.Mock
+	schedulerpb.SchedulerClient
+}
+
+func (m *mockSchedulerClient) CreateSchedule(ctx context.Context, in *schedulerpb.CreateScheduleRequest, opts ...grpc.CallOption) (*schedulerpb.Schedule, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schedulerpb.Schedule), args.Error(1)
+}
+
+func (m *mockSchedulerClient) GetSchedule(ctx context.Context, in *schedulerpb.ScheduleID, opts ...grpc.CallOption) (*schedulerpb.Schedule, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schedulerpb.Schedule), args.Error(1)
+}
+
+func (m *mockSchedulerClient) CancelSchedule(ctx context.Context, in *schedulerpb.ScheduleID, opts ...grpc.CallOption) (*schedulerpb.Schedule, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schedulerpb.Schedule), args.Error(1)
+}
+
+// mockAccountsClient only mocks the accounts RPCs the handlers under test call
+type mockAccountsClient struct {
+	mock.Mock
+	accountspb.AccountsClient
+}
+
+func (m *mockAccountsClient) GetAccount(ctx context.Context, in *accountspb.GetAccountRequest, opts ...grpc.CallOption) (*accountspb.Account, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*accountspb.Account), args.Error(1)
+}
+
 // Helper function to create a server instance with mocks
-func newTestServer(t *testing.T) (*server, *mockBalanceClient, *mockFeedClient, *mockTransactionsClient, *mockMerchantClient, *mockCardsClient, *mockDiscoClient) {
+func newTestServer(t *testing.T) (*apiServer, *mockBalanceClient, *mockFeedClient, *mockTransactionsClient, *mockMerchantClient, *mockCardsClient, *mockDiscoClient) {
 	mockBalance := new(mockBalanceClient)
 	mockFeed := new(mockFeedClient)
 	mockTxn := new(mockTransactionsClient)
@@ -223,7 +485,7 @@ func newTestServer(t *testing.T) (*server, *mockBalanceClient, *mockFeedClient,
 	mockCards := new(mockCardsClient)
 	mockDisco := new(mockDiscoClient)
 
-	s := &server{
+	s := &apiServer{
 		balanceClient:      mockBalance,
 		feedClient:         mockFeed,
 		transactionsClient: mockTxn,
@@ -305,10 +567,10 @@ func TestFreezeCardHandler_Success(t *testing.T) {
 	s, _, _, _, _, mockCards, _ := newTestServer(t)
 
 	cardID := "card-to-freeze"
-	expectedResp := &cardspb.Card{Id: cardID, UserId: "user-1", Status: "FROZEN", LastFour: "1111"}
+	expectedResp := &cardspb.Card{CardId: cardID, UserId: "user-1", Status: "FROZEN", LastFour: "1111"}
 
 	// Mock UpdateCardStatus call
-	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN"}).
+	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN", Reason: "frozen by cardholder"}).
 		Return(expectedResp, nil).Once()
 
 	// Setup Echo context
@@ -329,12 +591,29 @@ func TestFreezeCardHandler_Success(t *testing.T) {
 	var resp cardspb.Card
 	err = json.Unmarshal(rec.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedResp.Id, resp.Id)
+	assert.Equal(t, expectedResp.CardId, resp.CardId)
 	assert.Equal(t, expectedResp.Status, resp.Status)
 
 	mockCards.AssertExpectations(t)
 }
 
+func TestFreezeCardHandler_NotOwner(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "ACTIVE"}, nil).Once()
+
+	c, rec := newCardOwnerContext(http.MethodPost, "/cards/"+cardID+"/freeze", "", "user-2", cardID)
+
+	err := s.requireCardOwner(s.freezeCardHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockCards.AssertNotCalled(t, "UpdateCardStatus", mock.Anything, mock.Anything)
+	mockCards.AssertExpectations(t)
+}
+
 func TestFreezeCardHandler_NotFound(t *testing.T) {
 	s, _, _, _, _, mockCards, _ := newTestServer(t)
 
@@ -342,7 +621,7 @@ func TestFreezeCardHandler_NotFound(t *testing.T) {
 	expectedError := status.Error(codes.NotFound, "card not found")
 
 	// Mock UpdateCardStatus call
-	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN"}).
+	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN", Reason: "frozen by cardholder"}).
 		Return(nil, expectedError).Once()
 
 	// Setup Echo context
@@ -363,6 +642,28 @@ func TestFreezeCardHandler_NotFound(t *testing.T) {
 	mockCards.AssertExpectations(t)
 }
 
+func TestFreezeCardHandler_Closed(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-closed"
+	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN", Reason: "frozen by cardholder"}).
+		Return(nil, status.Error(codes.FailedPrecondition, "card is closed")).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/cards/"+cardID+"/freeze", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(cardID)
+
+	err := s.freezeCardHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "card is closed")
+	mockCards.AssertExpectations(t)
+}
+
 func TestFreezeCardHandler_GrpcError(t *testing.T) {
 	s, _, _, _, _, mockCards, _ := newTestServer(t)
 
@@ -370,7 +671,7 @@ func TestFreezeCardHandler_GrpcError(t *testing.T) {
 	expectedError := status.Error(codes.Internal, "internal cards error")
 
 	// Mock UpdateCardStatus call
-	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN"}).
+	mockCards.On("UpdateCardStatus", mock.Anything, &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "FROZEN", Reason: "frozen by cardholder"}).
 		Return(nil, expectedError).Once()
 
 	// Setup Echo context
@@ -391,8 +692,267 @@ func TestFreezeCardHandler_GrpcError(t *testing.T) {
 	mockCards.AssertExpectations(t)
 }
 
+func TestGetCardControlsHandler(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.CardControls{CardId: cardID, DailyLimit: 5000, BlockedMccs: []int32{7995}}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/cards/"+cardID+"/controls", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(cardID)
+
+	err := s.getCardControlsHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp cardspb.CardControls
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(5000), resp.DailyLimit)
+	assert.Equal(t, []int32{7995}, resp.BlockedMccs)
+
+	mockCards.AssertExpectations(t)
+}
+
+func TestUpdateCardControlsHandler(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	expectedReq := &cardspb.CardControls{CardId: cardID, MonthlyLimit: 100000, BlockedMccs: []int32{7995}, OnlineDisabled: true}
+	mockCards.On("UpdateCardControls", mock.Anything, expectedReq).
+		Return(expectedReq, nil).Once()
+
+	// The card in the path is used, whatever the body says
+	body := `{"card_id": "card-2", "monthly_limit": 100000, "blocked_mccs": [7995], "online_disabled": true}`
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/cards/"+cardID+"/controls", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(cardID)
+
+	err := s.updateCardControlsHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockCards.AssertExpectations(t)
+}
+
+func TestUpdateCardControlsHandler_Invalid(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("UpdateCardControls", mock.Anything, &cardspb.CardControls{CardId: cardID, DailyLimit: -1}).
+		Return(nil, status.Error(codes.InvalidArgument, "limits can't be negative")).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/cards/"+cardID+"/controls", strings.NewReader(`{"daily_limit": -1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(cardID)
+
+	err := s.updateCardControlsHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "limits can't be negative")
+	mockCards.AssertExpectations(t)
+}
+
+func TestCardControlsHandlers_NotOwner(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "ACTIVE"}, nil).Twice()
+
+	// Another user can neither read nor change the card's controls
+	c, rec := newCardOwnerContext(http.MethodGet, "/cards/"+cardID+"/controls", "", "user-2", cardID)
+	err := s.requireCardOwner(s.getCardControlsHandler)(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	c, rec = newCardOwnerContext(http.MethodPut, "/cards/"+cardID+"/controls", `{"daily_limit": 1000000}`, "user-2", cardID)
+	err = s.requireCardOwner(s.updateCardControlsHandler)(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockCards.AssertNotCalled(t, "GetCardControls", mock.Anything, mock.Anything)
+	mockCards.AssertNotCalled(t, "UpdateCardControls", mock.Anything, mock.Anything)
+	mockCards.AssertExpectations(t)
+}
+
 // --- Disco Handlers ---
 
+func TestListCardsHandler(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	expectedReq := &cardspb.ListCardsByUserRequest{UserId: "user-1", Statuses: []string{"ACTIVE", "FROZEN"}, Limit: 10, BeforeId: "card-9"}
+	mockCards.On("ListCardsByUser", mock.Anything, expectedReq).
+		Return(&cardspb.CardList{Cards: []*cardspb.Card{{CardId: "card-8", UserId: "user-1", Status: "ACTIVE"}}}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/cards?status=ACTIVE&status=FROZEN&limit=10&before_id=card-9", nil)
+	req.Header.Set(userIDHeader, "user-1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.listCardsHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "card-8")
+	mockCards.AssertExpectations(t)
+}
+
+func TestListCardsHandler_Unauthenticated(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/cards", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.listCardsHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockCards.AssertNotCalled(t, "ListCardsByUser", mock.Anything, mock.Anything)
+}
+
+// newCardOwnerContext returns the context of a request about a card made by userID
+func newCardOwnerContext(method, path, body, userID, cardID string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if userID != "" {
+		req.Header.Set(userIDHeader, userID)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(cardID)
+	return c, rec
+}
+
+func TestCancelCardHandler(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "ACTIVE"}, nil).Once()
+	expectedReq := &cardspb.UpdateCardStatusRequest{CardId: cardID, NewStatus: "CLOSED", Reason: "cancelled by cardholder", Actor: "user-1"}
+	mockCards.On("UpdateCardStatus", mock.Anything, expectedReq).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "CLOSED"}, nil).Once()
+
+	c, rec := newCardOwnerContext(http.MethodPost, "/cards/"+cardID+"/cancel", "", "user-1", cardID)
+
+	err := s.requireCardOwner(s.cancelCardHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "CLOSED")
+	mockCards.AssertExpectations(t)
+}
+
+func TestCancelCardHandler_NotOwner(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-2", Status: "ACTIVE"}, nil).Once()
+
+	c, rec := newCardOwnerContext(http.MethodPost, "/cards/"+cardID+"/cancel", "", "user-1", cardID)
+
+	err := s.requireCardOwner(s.cancelCardHandler)(c)
+
+	// Someone else's card looks the same as one that doesn't exist
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockCards.AssertNotCalled(t, "UpdateCardStatus", mock.Anything, mock.Anything)
+	mockCards.AssertExpectations(t)
+}
+
+func TestCancelCardHandler_Unauthenticated(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	c, rec := newCardOwnerContext(http.MethodPost, "/cards/card-1/cancel", "", "", "card-1")
+
+	err := s.requireCardOwner(s.cancelCardHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockCards.AssertNotCalled(t, "GetCard", mock.Anything, mock.Anything)
+}
+
+func TestReplaceCardHandler(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "FROZEN"}, nil).Once()
+	mockCards.On("ReissueCard", mock.Anything, &cardspb.ReissueCardRequest{CardId: cardID, Reason: "STOLEN", Actor: "user-1"}).
+		Return(&cardspb.Card{CardId: "card-2", UserId: "user-1", Status: "INACTIVE", ReplacesCardId: cardID, LastFour: "4321", PanToken: "token-2"}, nil).Once()
+
+	c, rec := newCardOwnerContext(http.MethodPost, "/cards/"+cardID+"/replace", `{"reason": "STOLEN"}`, "user-1", cardID)
+
+	err := s.requireCardOwner(s.replaceCardHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), "card-2")
+	assert.Contains(t, rec.Body.String(), "4321")
+	assert.NotContains(t, rec.Body.String(), `"pan"`)
+	assert.NotContains(t, rec.Body.String(), `"cvv"`)
+	mockCards.AssertExpectations(t)
+}
+
+func TestSetCardNicknameHandler(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "ACTIVE"}, nil).Once()
+	mockCards.On("SetCardNickname", mock.Anything, &cardspb.SetCardNicknameRequest{CardId: cardID, Nickname: "Travel"}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "ACTIVE", Nickname: "Travel"}, nil).Once()
+
+	c, rec := newCardOwnerContext(http.MethodPut, "/cards/"+cardID+"/nickname", `{"nickname": "Travel"}`, "user-1", cardID)
+
+	err := s.requireCardOwner(s.setCardNicknameHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Travel")
+	mockCards.AssertExpectations(t)
+}
+
+func TestSetDefaultCardHandler_Closed(t *testing.T) {
+	s, _, _, _, _, mockCards, _ := newTestServer(t)
+
+	cardID := "card-1"
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: cardID}).
+		Return(&cardspb.Card{CardId: cardID, UserId: "user-1", Status: "CLOSED"}, nil).Once()
+	mockCards.On("SetDefaultCard", mock.Anything, &cardspb.SetDefaultCardRequest{CardId: cardID}).
+		Return(nil, status.Error(codes.FailedPrecondition, "card is closed")).Once()
+
+	c, rec := newCardOwnerContext(http.MethodPost, "/cards/"+cardID+"/default", "", "user-1", cardID)
+
+	err := s.requireCardOwner(s.setDefaultCardHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "card is closed")
+	mockCards.AssertExpectations(t)
+}
+
 func TestCreateDiscoSessionHandler_Success(t *testing.T) {
 	s, _, _, _, _, _, mockDisco := newTestServer(t)
 
@@ -570,3 +1130,135 @@ func TestCreateDiscoWalletHandler_Success(t *testing.T) {
 
 	mockDisco.AssertExpectations(t)
 }
+
+func TestCreateScheduleHandler_Success(t *testing.T) {
+	s, _, _, _, _, _, _ := newTestServer(t)
+	mockScheduler := new(mockSchedulerClient)
+	s.schedulerClient = mockScheduler
+	mockAccounts := new(mockAccountsClient)
+	s.accountsClient = mockAccounts
+
+	accountID := "acc-123"
+	requestBody := `{"amount":50000,"currency":"GBP","payee":"Landlord","frequency":"MONTHLY","start_date":"2026-11-01"}`
+	expectedGrpcReq := &schedulerpb.CreateScheduleRequest{AccountId: accountID, Amount: 50000, Currency: "GBP", Payee: "Landlord", Frequency: "MONTHLY", StartDate: "2026-11-01"}
+	expectedGrpcResp := &schedulerpb.Schedule{Id: "sched-123", AccountId: accountID, Amount: 50000, Frequency: "MONTHLY", NextRunDate: "2026-11-01", Status: "ACTIVE"}
+
+	mockAccounts.On("GetAccount", mock.Anything, &accountspb.GetAccountRequest{AccountId: accountID}).
+		Return(&accountspb.Account{AccountId: accountID, HolderUserIds: []string{"user-1"}}, nil).Once()
+	mockScheduler.On("CreateSchedule", mock.Anything, expectedGrpcReq).Return(expectedGrpcResp, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID+"/schedules", strings.NewReader(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(userIDHeader, "user-1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("account_id")
+	c.SetParamValues(accountID)
+
+	err := s.requireAccountHolder(s.createScheduleHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp schedulerpb.Schedule
+	err = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedGrpcResp.Id, resp.Id)
+	assert.Equal(t, expectedGrpcResp.NextRunDate, resp.NextRunDate)
+
+	mockScheduler.AssertExpectations(t)
+	mockAccounts.AssertExpectations(t)
+}
+
+func TestCreateScheduleHandler_NotAccountHolder(t *testing.T) {
+	s, _, _, _, _, _, _ := newTestServer(t)
+	mockScheduler := new(mockSchedulerClient)
+	s.schedulerClient = mockScheduler
+	mockAccounts := new(mockAccountsClient)
+	s.accountsClient = mockAccounts
+
+	accountID := "acc-123"
+	requestBody := `{"amount":50000,"currency":"GBP","payee":"Landlord","frequency":"MONTHLY","start_date":"2026-11-01"}`
+	mockAccounts.On("GetAccount", mock.Anything, &accountspb.GetAccountRequest{AccountId: accountID}).
+		Return(&accountspb.Account{AccountId: accountID, HolderUserIds: []string{"user-1"}}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID+"/schedules", strings.NewReader(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(userIDHeader, "user-2")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("account_id")
+	c.SetParamValues(accountID)
+
+	err := s.requireAccountHolder(s.createScheduleHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockScheduler.AssertNotCalled(t, "CreateSchedule", mock.Anything, mock.Anything)
+	mockAccounts.AssertExpectations(t)
+}
+
+func TestCancelScheduleHandler_AlreadyCompleted(t *testing.T) {
+	s, _, _, _, _, _, _ := newTestServer(t)
+	mockScheduler := new(mockSchedulerClient)
+	s.schedulerClient = mockScheduler
+	mockAccounts := new(mockAccountsClient)
+	s.accountsClient = mockAccounts
+
+	scheduleID := "sched-123"
+	grpcErr := status.Error(codes.FailedPrecondition, "schedule is COMPLETED")
+
+	mockScheduler.On("GetSchedule", mock.Anything, &schedulerpb.ScheduleID{ScheduleId: scheduleID}).
+		Return(&schedulerpb.Schedule{Id: scheduleID, AccountId: "acc-123", Status: "COMPLETED"}, nil).Once()
+	mockAccounts.On("GetAccount", mock.Anything, &accountspb.GetAccountRequest{AccountId: "acc-123"}).
+		Return(&accountspb.Account{AccountId: "acc-123", HolderUserIds: []string{"user-1"}}, nil).Once()
+	mockScheduler.On("CancelSchedule", mock.Anything, &schedulerpb.ScheduleID{ScheduleId: scheduleID}).Return(nil, grpcErr).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/schedules/"+scheduleID, nil)
+	req.Header.Set(userIDHeader, "user-1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("schedule_id")
+	c.SetParamValues(scheduleID)
+
+	err := s.requireScheduleHolder(s.cancelScheduleHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "schedule is COMPLETED")
+
+	mockScheduler.AssertExpectations(t)
+	mockAccounts.AssertExpectations(t)
+}
+
+func TestCancelScheduleHandler_NotAccountHolder(t *testing.T) {
+	s, _, _, _, _, _, _ := newTestServer(t)
+	mockScheduler := new(mockSchedulerClient)
+	s.schedulerClient = mockScheduler
+	mockAccounts := new(mockAccountsClient)
+	s.accountsClient = mockAccounts
+
+	scheduleID := "sched-123"
+	mockScheduler.On("GetSchedule", mock.Anything, &schedulerpb.ScheduleID{ScheduleId: scheduleID}).
+		Return(&schedulerpb.Schedule{Id: scheduleID, AccountId: "acc-123", Status: "ACTIVE"}, nil).Once()
+	mockAccounts.On("GetAccount", mock.Anything, &accountspb.GetAccountRequest{AccountId: "acc-123"}).
+		Return(&accountspb.Account{AccountId: "acc-123", HolderUserIds: []string{"user-1"}}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/schedules/"+scheduleID, nil)
+	req.Header.Set(userIDHeader, "user-2")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("schedule_id")
+	c.SetParamValues(scheduleID)
+
+	err := s.requireScheduleHolder(s.cancelScheduleHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "schedule not found")
+	mockScheduler.AssertNotCalled(t, "CancelSchedule", mock.Anything, mock.Anything)
+	mockAccounts.AssertExpectations(t)
+}
@@ -3,12 +3,12 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -17,6 +17,9 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	feedpb "github.com/manifoldfinance/disco2/v2/feed"
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	"github.com/manifoldfinance/disco2/v2/pkg/streamconsumer"
 )
 
 type server struct {
@@ -77,11 +80,19 @@ func main() {
 		}
 	}()
 
-	// Start Redis event consumer
-	go s.startEventConsumer(ctx)
+	// Stop consuming on SIGINT/SIGTERM, finishing notifications already being sent
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Keep main goroutine alive
-	select {}
+	// Run Redis event consumer until shutdown
+	s.startEventConsumer(ctx)
+	log.Println("Event consumer stopped, shutting down HTTP server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Server shutdown error: %v", err)
+	}
 }
 
 func (s *server) registerDeviceHandler(c echo.Context) error {
@@ -130,112 +141,58 @@ func (s *server) registerDeviceHandler(c echo.Context) error {
 func (s *server) startEventConsumer(ctx context.Context) {
 	log.Println("Starting Redis event consumer...")
 
-	consumerGroup := "apns-consumer-group"
-	streamName := "feed:item.created"
+	err := events.Subscribe(ctx, s.redisClient, events.StreamFeedItemCreated, "apns-consumer-group", s.handleFeedItemCreated,
+		streamconsumer.WithConsumerName(streamconsumer.ConsumerName("apns")),
+		streamconsumer.WithConcurrency(4), // notifications are independent of each other
+	)
+	if err != nil && ctx.Err() == nil {
+		log.Fatalf("failed to consume %s events: %v", events.StreamFeedItemCreated, err)
+	}
+}
+
+// handleFeedItemCreated notifies the account holder's devices of a new feed item.
+// Returning an error leaves the event pending so it is retried later.
+func (s *server) handleFeedItemCreated(ctx context.Context, event *eventspb.FeedItemCreated) error {
+	log.Printf("Processing feed item event for feed item ID: %s, account ID: %s", event.GetFeedItemId(), event.GetAccountId())
 
-	// Create consumer group if it doesn't exist
-	if _, err := s.redisClient.XGroupCreateMkStream(ctx, streamName, consumerGroup, "0").Result(); err != nil {
-		// Ignore BUSYGROUP error if group already exists
-		if !strings.Contains(err.Error(), "BUSYGROUP") {
-			log.Fatalf("failed to create Redis consumer group %s: %v", consumerGroup, err)
-		}
+	// Fetch feed item details from Feed service
+	feedItemReq := &feedpb.FeedItemIDs{Ids: []string{event.GetFeedItemId()}}
+	feedItemsResp, err := s.feedClient.GetFeedItemsByID(ctx, feedItemReq)
+	if err != nil {
+		return fmt.Errorf("failed to get feed item %s from Feed service: %w", event.GetFeedItemId(), err)
 	}
-	log.Printf("Redis consumer group '%s' created or already exists", consumerGroup)
-
-	for {
-		// Read messages from the stream using the consumer group
-		messages, err := s.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
-			Group:    consumerGroup,
-			Consumer: "apns-instance-1",
-			Streams:  []string{streamName, ">"},
-			Count:    10,
-			Block:    0,
-			NoAck:    false,
-		}).Result()
-		if err != nil {
-			log.Printf("error reading from Redis stream %s: %v", streamName, err)
-			time.Sleep(time.Second) // Wait before retrying
-			continue
-		}
 
-		for _, stream := range messages {
-			for _, message := range stream.Messages {
-				log.Printf("Received message %s from stream %s", message.ID, stream.Stream)
-
-				// Process the message
-				payload, ok := message.Values["payload"].(string)
-				if !ok {
-					log.Printf("message %s has no 'payload' field or it's not a string", message.ID)
-					// Acknowledge the message to prevent reprocessing
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				var event struct {
-					FeedItemId string `json:"feed_item_id"`
-					AccountId  string `json:"account_id"`
-				}
-				if err := json.Unmarshal([]byte(payload), &event); err != nil {
-					log.Printf("failed to unmarshal event payload for message %s: %v", message.ID, err)
-					// Acknowledge the message to prevent reprocessing
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				log.Printf("Processing feed item event for feed item ID: %s, account ID: %s", event.FeedItemId, event.AccountId)
-
-				// Fetch feed item details from Feed service
-				feedItemReq := &feedpb.FeedItemIDs{Ids: []string{event.FeedItemId}}
-				feedItemsResp, err := s.feedClient.GetFeedItemsByID(ctx, feedItemReq)
-				if err != nil {
-					log.Printf("failed to get feed item %s from Feed service: %v", event.FeedItemId, err)
-					// Do NOT acknowledge the message, it will be retried later
-					continue
-				}
-
-				if len(feedItemsResp.GetItems()) == 0 {
-					log.Printf("feed item %s not found in Feed service", event.FeedItemId)
-					// Acknowledge the message as we can't process it without the feed item
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				feedItem := feedItemsResp.GetItems()[0]
-				notificationMessage := feedItem.GetContent()
-
-				// Fetch device tokens for the user
-				deviceTokens, err := s.getDeviceTokensForUser(ctx, feedItem.GetAccountId())
-				if err != nil {
-					log.Printf("failed to get device tokens for user %s: %v", feedItem.GetAccountId(), err)
-					// Do NOT acknowledge the message, it will be retried later
-					continue
-				}
-
-				if len(deviceTokens) == 0 {
-					log.Printf("no active device tokens found for user %s", feedItem.GetAccountId())
-					// Acknowledge the message as there's no one to notify
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				// Send push notification to each device token
-				for _, token := range deviceTokens {
-					if err := s.sendAPNSNotification(ctx, token, notificationMessage); err != nil {
-						log.Printf("failed to send APNS notification to token %s: %v", token, err)
-					} else {
-						log.Printf("Successfully sent APNS notification to token %s", token)
-					}
-				}
-
-				// Acknowledge the message after processing all tokens
-				if _, err := s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID).Result(); err != nil {
-					log.Printf("failed to acknowledge message %s: %v", message.ID, err)
-				} else {
-					log.Printf("Acknowledged message %s", message.ID)
-				}
-			}
+	if len(feedItemsResp.GetItems()) == 0 {
+		// We can't process the event without the feed item
+		log.Printf("feed item %s not found in Feed service", event.GetFeedItemId())
+		return nil
+	}
+
+	feedItem := feedItemsResp.GetItems()[0]
+	notificationMessage := feedItem.GetContent()
+
+	// Fetch device tokens for the user
+	deviceTokens, err := s.getDeviceTokensForUser(ctx, feedItem.GetAccountId())
+	if err != nil {
+		return fmt.Errorf("failed to get device tokens for user %s: %w", feedItem.GetAccountId(), err)
+	}
+
+	if len(deviceTokens) == 0 {
+		// There's no one to notify
+		log.Printf("no active device tokens found for user %s", feedItem.GetAccountId())
+		return nil
+	}
+
+	// Send push notification to each device token
+	for _, token := range deviceTokens {
+		if err := s.sendAPNSNotification(ctx, token, notificationMessage); err != nil {
+			log.Printf("failed to send APNS notification to token %s: %v", token, err)
+		} else {
+			log.Printf("Successfully sent APNS notification to token %s", token)
 		}
 	}
+
+	return nil
 }
 
 // Helper function to get device tokens for a user from the database
@@ -8,16 +8,21 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/labstack/echo/v4"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/manifoldfinance/disco2/v2/internal/balance/config"
 	"github.com/manifoldfinance/disco2/v2/internal/balance/db"
+	"github.com/manifoldfinance/disco2/v2/internal/balance/fx"
 	"github.com/manifoldfinance/disco2/v2/internal/balance/service"
+	"github.com/manifoldfinance/disco2/v2/pkg/outbox"
 	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
 )
 
@@ -54,12 +59,36 @@ func main() {
 	log.Println("Connected to Redis")
 	defer rdb.Close()
 
+	// Load exchange rates for converting payments in other currencies
+	rates := fx.StaticRates{}
+	if cfg.FXRatesFile != "" {
+		rates, err = fx.LoadFile(cfg.FXRatesFile)
+		if err != nil {
+			log.Fatalf("Failed to load exchange rates: %v", err)
+		}
+		log.Printf("Loaded %d exchange rates from %s", len(rates), cfg.FXRatesFile)
+	}
+
 	// Create balance service
-	balanceService := service.NewBalanceService(database, rdb)
+	balanceService := service.NewBalanceService(database,
+		service.WithHoldTTL(cfg.HoldTTL),
+		service.WithRateProvider(rates),
+		service.WithFXFee(cfg.FXFeeBps),
+		service.WithOverdraftInterest(cfg.OverdraftInterestBps),
+		service.WithOverdraftFee(cfg.OverdraftDailyFee),
+	)
+
+	// Release expired authorization holds, charge overdrafts and relay outbox events in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go runHoldSweeper(backgroundCtx, balanceService, cfg.HoldSweepInterval)
+	go runOverdraftAccrual(backgroundCtx, balanceService, cfg.OverdraftAccrualInterval)
+	go outbox.NewRelay(database, rdb).Run(backgroundCtx)
 
 	// Create HTTP server
 	e := echo.New()
 	e.GET("/balance/:account_id", createBalanceHandler(balanceService))
+	e.GET("/balance/:account_id/ledger", createLedgerHandler(balanceService))
 
 	// Start HTTP server in a goroutine
 	httpServer := &http.Server{
@@ -104,10 +133,45 @@ func main() {
 
 	// Shutdown gRPC server
 	grpcServer.GracefulStop()
+	stopBackground()
 
 	log.Println("Servers successfully shut down.")
 }
 
+// runHoldSweeper periodically expires authorization holds until ctx is cancelled
+func runHoldSweeper(ctx context.Context, svc *service.BalanceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := svc.ExpireHolds(ctx, &pb.ExpireHoldsRequest{Limit: 500}); err != nil {
+				log.Printf("failed to expire holds: %v", err)
+			}
+		}
+	}
+}
+
+// runOverdraftAccrual periodically charges accounts that closed yesterday overdrawn until ctx is cancelled
+func runOverdraftAccrual(ctx context.Context, svc *service.BalanceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := svc.AccrueOverdraftCharges(ctx, &pb.AccrueOverdraftChargesRequest{}); err != nil {
+				log.Printf("failed to accrue overdraft charges: %v", err)
+			}
+		}
+	}
+}
+
 // createBalanceHandler creates an HTTP handler for balance endpoint
 func createBalanceHandler(svc *service.BalanceService) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -127,3 +191,33 @@ func createBalanceHandler(svc *service.BalanceService) echo.HandlerFunc {
 		return c.JSON(http.StatusOK, balanceResp)
 	}
 }
+
+// createLedgerHandler creates an HTTP handler for the ledger audit endpoint
+func createLedgerHandler(svc *service.BalanceService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		limit := uint32(0)
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			parsedLimit, err := strconv.ParseUint(limitStr, 10, 32)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit parameter"})
+			}
+			limit = uint32(parsedLimit)
+		}
+
+		req := &pb.ListLedgerEntriesRequest{
+			AccountId: c.Param("account_id"),
+			Limit:     limit,
+			BeforeId:  c.QueryParam("before_id"),
+		}
+
+		entries, err := svc.ListLedgerEntries(c.Request().Context(), req)
+		if err != nil {
+			if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		}
+
+		return c.JSON(http.StatusOK, entries)
+	}
+}
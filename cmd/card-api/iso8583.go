@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cardprocessingpb "github.com/manifoldfinance/disco2/v2/card_processing"
+	"github.com/manifoldfinance/disco2/v2/pkg/iso8583"
+	"github.com/manifoldfinance/disco2/v2/pkg/pan"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// Field 43 gives the merchant's name, then city, and ends with its country code
+const (
+	merchantNameLength    = 23
+	merchantCountryOffset = 38
+)
+
+// cashWithdrawal is the transaction type, the first two digits of the processing code, of an ATM withdrawal
+const cashWithdrawal = "01"
+
+// posEntryModeChannels gives the channel of a payment by how the card details were read, the first
+// two digits of the point of service entry mode
+var posEntryModeChannels = map[string]string{
+	"01": "ONLINE", // keyed in, as for card-not-present payments
+	"02": "MAGSTRIPE",
+	"05": "CHIP",
+	"07": "CONTACTLESS",
+	"10": "ONLINE", // credentials on file
+	"81": "ONLINE", // e-commerce
+	"90": "MAGSTRIPE",
+	"91": "CONTACTLESS", // contactless magnetic stripe data
+}
+
+// echoedFields are copied from a request to its response, so the network can match them up
+var echoedFields = []int{
+	iso8583.FieldPAN,
+	iso8583.FieldProcessingCode,
+	iso8583.FieldAmount,
+	iso8583.FieldTransmissionTime,
+	iso8583.FieldSTAN,
+	iso8583.FieldLocalTime,
+	iso8583.FieldLocalDate,
+	iso8583.FieldRRN,
+	iso8583.FieldTerminalID,
+	iso8583.FieldMerchantID,
+	iso8583.FieldCurrency,
+	iso8583.FieldOriginalData,
+}
+
+// serveISO8583 accepts connections from a card network on lis and answers the messages sent over
+// them until lis is closed
+func (s *server) serveISO8583(lis net.Listener, spec *iso8583.Spec) error {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handleISO8583Conn(conn, spec)
+	}
+}
+
+// handleISO8583Conn answers each message read from conn in turn, closing conn when the network
+// hangs up or sends something that cannot be parsed
+func (s *server) handleISO8583Conn(conn net.Conn, spec *iso8583.Spec) {
+	defer conn.Close()
+	log.Printf("ISO 8583 connection from %s", conn.RemoteAddr())
+
+	for {
+		b, err := iso8583.ReadFrame(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("failed to read ISO 8583 message from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		req, err := spec.Unpack(b)
+		if err != nil {
+			log.Printf("failed to unpack ISO 8583 message from %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+
+		resp := s.handleISO8583Message(context.Background(), req)
+
+		b, err = spec.Pack(resp)
+		if err != nil {
+			log.Printf("failed to pack ISO 8583 %s response: %v", resp.MTI, err)
+			return
+		}
+		if err := iso8583.WriteFrame(conn, b); err != nil {
+			log.Printf("failed to send ISO 8583 %s response to %s: %v", resp.MTI, conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// handleISO8583Message answers a single request message
+func (s *server) handleISO8583Message(ctx context.Context, req *iso8583.Message) *iso8583.Message {
+	log.Printf("Received ISO 8583 %s message: %+v", req.MTI, loggedFields(req))
+
+	resp := iso8583.NewMessage(responseMTI(req.MTI))
+	for _, field := range echoedFields {
+		if req.Has(field) {
+			resp.Set(field, req.Get(field))
+		}
+	}
+
+	switch req.MTI {
+	case "0100", "0200":
+		s.isoAuthorize(ctx, req, resp)
+	case "0400", "0401":
+		s.isoReverse(ctx, req, resp)
+	default:
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseInvalidTransaction)
+	}
+	return resp
+}
+
+// isoAuthorize authorizes the transaction in a 0100 or 0200 request
+func (s *server) isoAuthorize(ctx context.Context, req, resp *iso8583.Message) {
+	amount, err := strconv.ParseInt(req.Get(iso8583.FieldAmount), 10, 64)
+	if err != nil || amount <= 0 {
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseInvalidAmount)
+		return
+	}
+	currency, ok := iso8583.CurrencyAlpha(req.Get(iso8583.FieldCurrency))
+	if !ok {
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseFormatError)
+		return
+	}
+	merchantName, merchantCountry := req.Get(iso8583.FieldMerchantName), ""
+	if len(merchantName) > merchantCountryOffset {
+		merchantCountry = strings.TrimSpace(merchantName[merchantCountryOffset:])
+	}
+	if len(merchantName) > merchantNameLength {
+		merchantName = merchantName[:merchantNameLength]
+	}
+	mcc, _ := strconv.ParseInt(req.Get(iso8583.FieldMCC), 10, 32) // optional, 0 if absent
+	panToken := s.panToken(ctx, req, resp)
+	if panToken == "" {
+		return
+	}
+
+	grpcResp, err := s.cardProcessingClient.AuthorizeCardTransaction(ctx, &cardprocessingpb.CardAuthRequest{
+		PanToken:        panToken,
+		Amount:          amount,
+		Currency:        currency,
+		MerchantId:      strings.TrimSpace(req.Get(iso8583.FieldMerchantID)),
+		MerchantName:    strings.TrimSpace(merchantName),
+		MerchantCountry: merchantCountry,
+		Channel:         isoChannel(req),
+		Mcc:             int32(mcc),
+		PinBlock:        req.Get(iso8583.FieldPINData),
+	})
+	if err != nil {
+		log.Printf("failed to authorize ISO 8583 %s message: %v", req.MTI, err)
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseSystemError)
+		return
+	}
+	if !grpcResp.GetApproved() {
+		resp.Set(iso8583.FieldResponseCode, declineResponseCode(grpcResp.GetDeclineCode()))
+		return
+	}
+	resp.Set(iso8583.FieldAuthCode, grpcResp.GetAuthCode())
+	resp.Set(iso8583.FieldResponseCode, iso8583.ResponseApproved)
+}
+
+// isoReverse reverses the authorization a 0400 request refers to by its card and auth code
+func (s *server) isoReverse(ctx context.Context, req, resp *iso8583.Message) {
+	panToken := s.panToken(ctx, req, resp)
+	if panToken == "" {
+		return
+	}
+
+	_, err := s.cardProcessingClient.ReverseAuthorization(ctx, &cardprocessingpb.ReversalRequest{
+		PanToken: panToken,
+		AuthCode: strings.TrimSpace(req.Get(iso8583.FieldAuthCode)),
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseApproved)
+	case codes.NotFound:
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseNoOriginal)
+	case codes.FailedPrecondition, codes.InvalidArgument:
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseInvalidTransaction)
+	default:
+		log.Printf("failed to reverse ISO 8583 %s message: %v", req.MTI, err)
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseSystemError)
+	}
+}
+
+// panToken returns the vault token of the card number in a request, so the number itself goes no
+// further than card-api. It sets the response code and returns "" if there is no token.
+func (s *server) panToken(ctx context.Context, req, resp *iso8583.Message) string {
+	token, err := s.vaultClient.LookupToken(ctx, &vaultpb.LookupTokenRequest{Pan: req.Get(iso8583.FieldPAN)})
+	switch status.Code(err) {
+	case codes.OK:
+		return token.GetToken()
+	case codes.NotFound, codes.InvalidArgument:
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseInvalidCard)
+	default:
+		log.Printf("failed to look up card number token for ISO 8583 %s message: %v", req.MTI, err)
+		resp.Set(iso8583.FieldResponseCode, iso8583.ResponseSystemError)
+	}
+	return ""
+}
+
+// loggedFields returns the fields of a message with the card number masked and the PIN block
+// removed, for logging
+func loggedFields(m *iso8583.Message) map[int]string {
+	fields := make(map[int]string, len(m.Fields))
+	for field, value := range m.Fields {
+		fields[field] = value
+	}
+	delete(fields, iso8583.FieldPINData)
+	if number, ok := fields[iso8583.FieldPAN]; ok {
+		fields[iso8583.FieldPAN] = "..." + pan.LastFour(number)
+	}
+	return fields
+}
+
+// isoChannel returns how the card was used in a request, or "" if it doesn't say. Cash
+// withdrawals are ATM payments however the card was read.
+func isoChannel(req *iso8583.Message) string {
+	if strings.HasPrefix(req.Get(iso8583.FieldProcessingCode), cashWithdrawal) {
+		return "ATM"
+	}
+	mode := req.Get(iso8583.FieldPOSEntryMode)
+	if len(mode) < 2 {
+		return ""
+	}
+	return posEntryModeChannels[mode[:2]]
+}
+
+// responseMTI is the message type of the response to a request, e.g. 0110 for 0100
+func responseMTI(mti string) string {
+	if len(mti) != 4 || mti[2] == '9' {
+		return mti
+	}
+	return mti[:2] + string(mti[2]+1) + mti[3:]
+}
+
+// declineResponseCodes maps the code Card-Processing declined an authorization with to the
+// response code sent to the network. Codes not listed are sent as "do not honour".
+var declineResponseCodes = map[cardprocessingpb.DeclineCode]string{
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN:              iso8583.ResponseRestrictedCard,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED:              iso8583.ResponseInvalidCard,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_NOT_FOUND:           iso8583.ResponseInvalidCard,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_INACTIVE:            iso8583.ResponseRestrictedCard,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS:       iso8583.ResponseInsufficientFunds,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED:           iso8583.ResponseExceedsLimit,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED:         iso8583.ResponseNotPermitted,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD:          iso8583.ResponseSuspectedFraud,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_INVALID_CURRENCY:         iso8583.ResponseInvalidTransaction,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_SYSTEM_ERROR:             iso8583.ResponseSystemError,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_AUTHENTICATION_REQUIRED:  iso8583.ResponseAuthenticationRequired,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CHANNEL_DISABLED:         iso8583.ResponseNotPermitted,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN:            iso8583.ResponseIncorrectPIN,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_PIN_LOCKED:               iso8583.ResponsePINTriesExceeded,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_EXPIRED:             iso8583.ResponseExpiredCard,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED:          iso8583.ResponseNotPermitted,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_ACCOUNT_UNAVAILABLE:      iso8583.ResponseNotPermitted,
+	cardprocessingpb.DeclineCode_DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED: iso8583.ResponseExceedsLimit,
+}
+
+// declineResponseCode returns the response code for a decline
+func declineResponseCode(code cardprocessingpb.DeclineCode) string {
+	if responseCode, ok := declineResponseCodes[code]; ok {
+		return responseCode
+	}
+	return iso8583.ResponseDoNotHonour
+}
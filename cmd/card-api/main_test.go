@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -16,6 +18,16 @@ import (
 	"google.golang.org/grpc/status"
 
 	cardprocessingpb "github.com/manifoldfinance/disco2/v2/card_processing/card_processing"
+	"github.com/manifoldfinance/disco2/v2/pkg/iso8583"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// Card numbers in the test vault, with their tokens
+const (
+	testPAN       = "4599650012345675"
+	testPANToken  = "token-123"
+	otherPAN      = "4599650098765432"
+	otherPANToken = "token-456"
 )
 
 // Mock CardProcessingClient
@@ -31,11 +43,103 @@ func (m *mockCardProcessingClient) AuthorizeCardTransaction(ctx context.Context,
 	return args.Get(0).(*cardprocessingpb.CardAuthReply), args.Error(1)
 }
 
+func (m *mockCardProcessingClient) ReverseAuthorization(ctx context.Context, in *cardprocessingpb.ReversalRequest, opts ...grpc.CallOption) (*cardprocessingpb.ReversalReply, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardprocessingpb.ReversalReply), args.Error(1)
+}
+
+func (m *mockCardProcessingClient) PartialReversal(ctx context.Context, in *cardprocessingpb.PartialReversalRequest, opts ...grpc.CallOption) (*cardprocessingpb.ReversalReply, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardprocessingpb.ReversalReply), args.Error(1)
+}
+
+func (m *mockCardProcessingClient) RefundTransaction(ctx context.Context, in *cardprocessingpb.RefundRequest, opts ...grpc.CallOption) (*cardprocessingpb.RefundReply, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardprocessingpb.RefundReply), args.Error(1)
+}
+
+// Mock VaultClient
+type mockVaultClient struct {
+	mock.Mock
+}
+
+func (m *mockVaultClient) Tokenize(ctx context.Context, in *vaultpb.TokenizeRequest, opts ...grpc.CallOption) (*vaultpb.Token, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.Token), args.Error(1)
+}
+
+func (m *mockVaultClient) IssueCardNumber(ctx context.Context, in *vaultpb.IssueCardNumberRequest, opts ...grpc.CallOption) (*vaultpb.IssuedCardNumber, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.IssuedCardNumber), args.Error(1)
+}
+
+func (m *mockVaultClient) LookupToken(ctx context.Context, in *vaultpb.LookupTokenRequest, opts ...grpc.CallOption) (*vaultpb.Token, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.Token), args.Error(1)
+}
+
+func (m *mockVaultClient) Detokenize(ctx context.Context, in *vaultpb.DetokenizeRequest, opts ...grpc.CallOption) (*vaultpb.CardData, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.CardData), args.Error(1)
+}
+
+func (m *mockVaultClient) RotateKeys(ctx context.Context, in *vaultpb.RotateKeysRequest, opts ...grpc.CallOption) (*vaultpb.RotateKeysResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.RotateKeysResponse), args.Error(1)
+}
+
+func (m *mockVaultClient) SetPinBlock(ctx context.Context, in *vaultpb.SetPinBlockRequest, opts ...grpc.CallOption) (*vaultpb.SetPinBlockResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.SetPinBlockResponse), args.Error(1)
+}
+
+func (m *mockVaultClient) VerifyPinBlock(ctx context.Context, in *vaultpb.VerifyPinBlockRequest, opts ...grpc.CallOption) (*vaultpb.VerifyPinBlockResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.VerifyPinBlockResponse), args.Error(1)
+}
+
 // Helper function to create a server instance with mocks
 func newTestServer(t *testing.T) (*server, *mockCardProcessingClient) {
 	mockClient := new(mockCardProcessingClient)
+
+	// The vault holds the test card numbers
+	mockVault := new(mockVaultClient)
+	mockVault.On("LookupToken", mock.Anything, &vaultpb.LookupTokenRequest{Pan: testPAN}).Return(&vaultpb.Token{Token: testPANToken}, nil).Maybe()
+	mockVault.On("LookupToken", mock.Anything, &vaultpb.LookupTokenRequest{Pan: otherPAN}).Return(&vaultpb.Token{Token: otherPANToken}, nil).Maybe()
+
 	s := &server{
 		cardProcessingClient: mockClient,
+		vaultClient:          mockVault,
 	}
 	return s, mockClient
 }
@@ -50,7 +154,7 @@ func TestCardAuthHandler_Approved(t *testing.T) {
 		Currency:     "GBP",
 		MerchantName: "Test Shop",
 	}
-	expectedGrpcResp := &cardprocessingpb.CardAuthReply{Approved: true}
+	expectedGrpcResp := &cardprocessingpb.CardAuthReply{Approved: true, AuthCode: "K7Q2ZD"}
 
 	// Mock AuthorizeCardTransaction call
 	mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
@@ -75,7 +179,38 @@ func TestCardAuthHandler_Approved(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, true, resp["approved"])
 	assert.Equal(t, "", resp["reason"]) // Ensure reason is empty on success
+	assert.Equal(t, "", resp["decline_code"])
+	assert.Equal(t, "00", resp["response_code"])
+	assert.Equal(t, "K7Q2ZD", resp["auth_code"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCardAuthHandler_PinBlock(t *testing.T) {
+	s, mockClient := newTestServer(t)
+
+	// A chip and PIN payment carries its PIN on to Card Processing to be checked
+	requestBody := `{"card_id":"card-123", "amount":1000, "currency":"GBP", "channel":"CHIP", "pin_block":"1412345FFFFFFFFF"}`
+	expectedGrpcReq := &cardprocessingpb.CardAuthRequest{
+		CardId:   "card-123",
+		Amount:   1000,
+		Currency: "GBP",
+		Channel:  "CHIP",
+		PinBlock: "1412345FFFFFFFFF",
+	}
+	mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
+		Return(&cardprocessingpb.CardAuthReply{Approved: true, AuthCode: "K7Q2ZD"}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/cardAuth", strings.NewReader(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.cardAuthHandler(c)
 
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
 	mockClient.AssertExpectations(t)
 }
 
@@ -90,7 +225,11 @@ func TestCardAuthHandler_Declined(t *testing.T) {
 		MerchantName: "Another Shop",
 	}
 	declineReason := "insufficient funds"
-	expectedGrpcResp := &cardprocessingpb.CardAuthReply{Approved: false, DeclineReason: declineReason}
+	expectedGrpcResp := &cardprocessingpb.CardAuthReply{
+		Approved:      false,
+		DeclineReason: declineReason,
+		DeclineCode:   cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS,
+	}
 
 	// Mock AuthorizeCardTransaction call
 	mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
@@ -115,6 +254,8 @@ func TestCardAuthHandler_Declined(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, false, resp["approved"])
 	assert.Equal(t, declineReason, resp["reason"])
+	assert.Equal(t, "DECLINE_CODE_INSUFFICIENT_FUNDS", resp["decline_code"])
+	assert.Equal(t, "51", resp["response_code"])
 
 	mockClient.AssertExpectations(t)
 }
@@ -176,3 +317,372 @@ func TestCardAuthHandler_InvalidBody(t *testing.T) {
 	assert.NoError(t, err) // Echo handles binding errors internally
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestReversalHandler(t *testing.T) {
+	s, mockClient := newTestServer(t)
+
+	mockClient.On("ReverseAuthorization", mock.Anything, &cardprocessingpb.ReversalRequest{TransactionId: "txn-xyz"}).
+		Return(&cardprocessingpb.ReversalReply{TransactionId: "txn-xyz", Status: "REVERSED"}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/reversal", strings.NewReader(`{"transaction_id":"txn-xyz"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.reversalHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "REVERSED", resp["status"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestPartialReversalHandler(t *testing.T) {
+	s, mockClient := newTestServer(t)
+
+	expectedGrpcReq := &cardprocessingpb.PartialReversalRequest{TransactionId: "txn-xyz", Amount: 300, IdempotencyKey: "rev-1"}
+	mockClient.On("PartialReversal", mock.Anything, expectedGrpcReq).
+		Return(&cardprocessingpb.ReversalReply{TransactionId: "txn-xyz", Status: "AUTHORIZED", Amount: 700}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/partialReversal", strings.NewReader(`{"transaction_id":"txn-xyz", "amount":300, "idempotency_key":"rev-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.partialReversalHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "AUTHORIZED", resp["status"])
+	assert.Equal(t, float64(700), resp["amount"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRefundHandler(t *testing.T) {
+	s, mockClient := newTestServer(t)
+
+	expectedGrpcReq := &cardprocessingpb.RefundRequest{TransactionId: "txn-xyz", Amount: 400, IdempotencyKey: "refund-1"}
+	mockClient.On("RefundTransaction", mock.Anything, expectedGrpcReq).
+		Return(&cardprocessingpb.RefundReply{RefundTransactionId: "txn-refund", OriginalTransactionId: "txn-xyz", Amount: 400, OriginalStatus: "PARTIALLY_REFUNDED"}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/refund", strings.NewReader(`{"transaction_id":"txn-xyz", "amount":400, "idempotency_key":"refund-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.refundHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-refund", resp["refund_transaction_id"])
+	assert.Equal(t, "PARTIALLY_REFUNDED", resp["original_status"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRefundHandler_ExceedsOriginal(t *testing.T) {
+	s, mockClient := newTestServer(t)
+
+	mockClient.On("RefundTransaction", mock.Anything, mock.AnythingOfType("*card_processing.RefundRequest")).
+		Return(nil, status.Error(codes.FailedPrecondition, "refund exceeds the 600 left to refund")).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/refund", strings.NewReader(`{"transaction_id":"txn-xyz", "amount":1000, "idempotency_key":"refund-2"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.refundHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRefundHandler_MissingIdempotencyKey(t *testing.T) {
+	s, mockClient := newTestServer(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/refund", strings.NewReader(`{"transaction_id":"txn-xyz", "amount":400}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := s.refundHandler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockClient.AssertNotCalled(t, "RefundTransaction", mock.Anything, mock.Anything)
+}
+
+// startISO8583 serves ISO 8583 on a local port and returns a simulated network connected to it
+func startISO8583(t *testing.T, s *server) *iso8583.Client {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go s.serveISO8583(lis, iso8583.DefaultSpec())
+	t.Cleanup(func() { lis.Close() })
+
+	client, err := iso8583.Dial(lis.Addr().String(), iso8583.DefaultSpec())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestISO8583_Authorize(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	expectedGrpcReq := &cardprocessingpb.CardAuthRequest{
+		PanToken:     testPANToken,
+		Amount:       1000,
+		Currency:     "GBP",
+		MerchantId:   "M1",
+		MerchantName: "Test Shop",
+	}
+	mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
+		Return(&cardprocessingpb.CardAuthReply{Approved: true, AuthCode: "K7Q2ZD"}, nil).Once()
+
+	resp, err := client.Authorize(testPAN, 1000, "GBP", "M1", "Test Shop")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0110", resp.MTI)
+	assert.Equal(t, iso8583.ResponseApproved, resp.Get(iso8583.FieldResponseCode))
+	assert.Equal(t, "K7Q2ZD", resp.Get(iso8583.FieldAuthCode))
+	assert.Equal(t, testPAN, resp.Get(iso8583.FieldPAN))
+	assert.Equal(t, "000001", resp.Get(iso8583.FieldSTAN))
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestISO8583_Declined(t *testing.T) {
+	tests := []struct {
+		code         cardprocessingpb.DeclineCode
+		responseCode string
+	}{
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS, responseCode: iso8583.ResponseInsufficientFunds},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_NOT_FOUND, responseCode: iso8583.ResponseInvalidCard},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN, responseCode: iso8583.ResponseRestrictedCard},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED, responseCode: iso8583.ResponseExceedsLimit},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED, responseCode: iso8583.ResponseNotPermitted},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD, responseCode: iso8583.ResponseSuspectedFraud},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_SYSTEM_ERROR, responseCode: iso8583.ResponseSystemError},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_AUTHENTICATION_REQUIRED, responseCode: iso8583.ResponseAuthenticationRequired},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN, responseCode: iso8583.ResponseIncorrectPIN},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_PIN_LOCKED, responseCode: iso8583.ResponsePINTriesExceeded},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_EXPIRED, responseCode: iso8583.ResponseExpiredCard},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, responseCode: iso8583.ResponseNotPermitted},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_ACCOUNT_UNAVAILABLE, responseCode: iso8583.ResponseNotPermitted},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED, responseCode: iso8583.ResponseExceedsLimit},
+		{code: cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, responseCode: iso8583.ResponseDoNotHonour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			s, mockClient := newTestServer(t)
+			client := startISO8583(t, s)
+
+			mockClient.On("AuthorizeCardTransaction", mock.Anything, mock.AnythingOfType("*card_processing.CardAuthRequest")).
+				Return(&cardprocessingpb.CardAuthReply{Approved: false, DeclineCode: tt.code}, nil).Once()
+
+			resp, err := client.Purchase(otherPAN, 5000, "USD", "", "Another Shop")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "0210", resp.MTI)
+			assert.Equal(t, tt.responseCode, resp.Get(iso8583.FieldResponseCode))
+			assert.False(t, resp.Has(iso8583.FieldAuthCode))
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestISO8583_MerchantLocation(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	expectedGrpcReq := &cardprocessingpb.CardAuthRequest{
+		PanToken:        testPANToken,
+		Amount:          1000,
+		Currency:        "EUR",
+		MerchantName:    "Cafe de Flore",
+		MerchantCountry: "FR",
+	}
+	mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
+		Return(&cardprocessingpb.CardAuthReply{Approved: true, AuthCode: "K7Q2ZD"}, nil).Once()
+
+	// Field 43 gives the name, city and country of the merchant
+	resp, err := client.Authorize(testPAN, 1000, "EUR", "", "Cafe de Flore          Paris          FR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, iso8583.ResponseApproved, resp.Get(iso8583.FieldResponseCode))
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestISO8583_ChannelAndMCC(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	tests := []struct {
+		name           string
+		processingCode string
+		posEntryMode   string
+		mcc            string
+		channel        string
+	}{
+		{"contactless", "000000", "071", "5812", "CONTACTLESS"},
+		{"e-commerce", "000000", "812", "7995", "ONLINE"},
+		{"swiped", "000000", "901", "5411", "MAGSTRIPE"},
+		{"cash withdrawal", "010000", "051", "6011", "ATM"},
+		{"entry mode not given", "000000", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := iso8583.NewMessage("0100")
+			m.Set(iso8583.FieldPAN, testPAN)
+			m.Set(iso8583.FieldProcessingCode, tt.processingCode)
+			m.Set(iso8583.FieldAmount, "1000")
+			m.Set(iso8583.FieldCurrency, "826")
+			if tt.posEntryMode != "" {
+				m.Set(iso8583.FieldPOSEntryMode, tt.posEntryMode)
+			}
+			if tt.mcc != "" {
+				m.Set(iso8583.FieldMCC, tt.mcc)
+			}
+			mcc, _ := strconv.Atoi(tt.mcc)
+
+			expectedGrpcReq := &cardprocessingpb.CardAuthRequest{PanToken: testPANToken, Amount: 1000, Currency: "GBP", Channel: tt.channel, Mcc: int32(mcc)}
+			mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
+				Return(&cardprocessingpb.CardAuthReply{Approved: true, AuthCode: "K7Q2ZD"}, nil).Once()
+
+			resp, err := client.Send(m)
+
+			assert.NoError(t, err)
+			assert.Equal(t, iso8583.ResponseApproved, resp.Get(iso8583.FieldResponseCode))
+		})
+	}
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestISO8583_PinBlock(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	m := iso8583.NewMessage("0100")
+	m.Set(iso8583.FieldPAN, testPAN)
+	m.Set(iso8583.FieldProcessingCode, "000000")
+	m.Set(iso8583.FieldAmount, "1000")
+	m.Set(iso8583.FieldCurrency, "826")
+	m.Set(iso8583.FieldPOSEntryMode, "051")
+	m.Set(iso8583.FieldPINData, "141234A5B6C7D8E9")
+
+	expectedGrpcReq := &cardprocessingpb.CardAuthRequest{PanToken: testPANToken, Amount: 1000, Currency: "GBP", Channel: "CHIP", PinBlock: "141234A5B6C7D8E9"}
+	mockClient.On("AuthorizeCardTransaction", mock.Anything, expectedGrpcReq).
+		Return(&cardprocessingpb.CardAuthReply{Approved: false, DeclineCode: cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN}, nil).Once()
+
+	resp, err := client.Send(m)
+
+	assert.NoError(t, err)
+	assert.Equal(t, iso8583.ResponseIncorrectPIN, resp.Get(iso8583.FieldResponseCode))
+	assert.False(t, resp.Has(iso8583.FieldPINData), "PIN block is not echoed")
+	mockClient.AssertExpectations(t)
+}
+
+func TestISO8583_InvalidRequest(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	// Unknown currency
+	m := iso8583.NewMessage("0100")
+	m.Set(iso8583.FieldPAN, testPAN)
+	m.Set(iso8583.FieldAmount, "1000")
+	m.Set(iso8583.FieldCurrency, "999")
+	resp, err := client.Send(m)
+	assert.NoError(t, err)
+	assert.Equal(t, iso8583.ResponseFormatError, resp.Get(iso8583.FieldResponseCode))
+
+	// Zero amount
+	resp, err = client.Authorize(testPAN, 0, "GBP", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, iso8583.ResponseInvalidAmount, resp.Get(iso8583.FieldResponseCode))
+
+	// Unsupported message type
+	resp, err = client.Send(iso8583.NewMessage("0800"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0810", resp.MTI)
+	assert.Equal(t, iso8583.ResponseInvalidTransaction, resp.Get(iso8583.FieldResponseCode))
+
+	mockClient.AssertNotCalled(t, "AuthorizeCardTransaction", mock.Anything, mock.Anything)
+}
+
+func TestISO8583_UnknownCard(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	unknownPAN := "4599650011111111"
+	s.vaultClient.(*mockVaultClient).On("LookupToken", mock.Anything, &vaultpb.LookupTokenRequest{Pan: unknownPAN}).
+		Return(nil, status.Error(codes.NotFound, "card number not in vault")).Once()
+
+	resp, err := client.Authorize(unknownPAN, 1000, "GBP", "", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, iso8583.ResponseInvalidCard, resp.Get(iso8583.FieldResponseCode))
+	mockClient.AssertNotCalled(t, "AuthorizeCardTransaction", mock.Anything, mock.Anything)
+}
+
+func TestLoggedFields(t *testing.T) {
+	m := iso8583.NewMessage("0100")
+	m.Set(iso8583.FieldPAN, testPAN)
+	m.Set(iso8583.FieldAmount, "1000")
+	m.Set(iso8583.FieldPINData, "141234A5B6C7D8E9")
+
+	fields := loggedFields(m)
+
+	assert.Equal(t, "...5675", fields[iso8583.FieldPAN])
+	assert.Equal(t, "1000", fields[iso8583.FieldAmount])
+	assert.NotContains(t, fields, iso8583.FieldPINData)
+	assert.Equal(t, testPAN, m.Get(iso8583.FieldPAN), "message is not modified")
+}
+
+func TestISO8583_Reverse(t *testing.T) {
+	s, mockClient := newTestServer(t)
+	client := startISO8583(t, s)
+
+	mockClient.On("ReverseAuthorization", mock.Anything, &cardprocessingpb.ReversalRequest{PanToken: testPANToken, AuthCode: "K7Q2ZD"}).
+		Return(&cardprocessingpb.ReversalReply{TransactionId: "txn-xyz", Status: "REVERSED"}, nil).Once()
+	mockClient.On("ReverseAuthorization", mock.Anything, &cardprocessingpb.ReversalRequest{PanToken: testPANToken, AuthCode: "ZZZZZZ"}).
+		Return(nil, status.Error(codes.NotFound, "transaction not found")).Once()
+
+	resp, err := client.Reverse(testPAN, "K7Q2ZD", 1000, "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, "0410", resp.MTI)
+	assert.Equal(t, iso8583.ResponseApproved, resp.Get(iso8583.FieldResponseCode))
+
+	resp, err = client.Reverse(testPAN, "ZZZZZZ", 1000, "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, iso8583.ResponseNoOriginal, resp.Get(iso8583.FieldResponseCode))
+
+	mockClient.AssertExpectations(t)
+}
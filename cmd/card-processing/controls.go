@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	cardprocessingpb "github.com/manifoldfinance/disco2/v2/card_processing"
+	cardspb "github.com/manifoldfinance/disco2/v2/cards"
+)
+
+// Channels a card can be used through, as given in CardAuthRequest.channel
+const (
+	channelChip        = "CHIP"
+	channelContactless = "CONTACTLESS"
+	channelMagstripe   = "MAGSTRIPE"
+	channelOnline      = "ONLINE"
+	channelATM         = "ATM"
+)
+
+// lifetimePeriod is the spending period covering everything a card has spent, which spend caps apply to
+const lifetimePeriod = "ALL"
+
+// dayPeriod returns the spending period of the UTC day containing at
+func dayPeriod(at time.Time) string {
+	return "D" + at.UTC().Format("2006-01-02")
+}
+
+// monthPeriod returns the spending period of the UTC month containing at
+func monthPeriod(at time.Time) string {
+	return "M" + at.UTC().Format("2006-01")
+}
+
+// spendPeriods returns every period a payment made at a time counts towards
+func spendPeriods(at time.Time) []string {
+	return []string{dayPeriod(at), monthPeriod(at), lifetimePeriod}
+}
+
+// cardPayment is a payment counted towards its card's spending totals
+type cardPayment struct {
+	transactionID string
+	cardID        string
+	at            time.Time
+	amount        int64 // in the account's currency
+}
+
+// spendLimit caps what a card may spend in one period
+type spendLimit struct {
+	period string
+	limit  int64
+	reason string // given when a payment would take the card over the limit
+}
+
+// spendStore keeps running totals of what each card has spent in each period. A payment counts
+// from when it is reserved, while its transaction is still PENDING, until it is released.
+type spendStore interface {
+	// reserve adds payment to its card's totals, unless that would take one of them over its limit
+	// in limits, in which case nothing is added and that limit's reason returned. Reservations of
+	// one card are made one at a time, so payments made at the same moment can't both slip under a
+	// limit. A payment that was already reserved isn't added again.
+	reserve(ctx context.Context, payment cardPayment, limits []spendLimit) (string, error)
+	// release lowers what a transaction's payment counts towards its card's totals to remaining,
+	// 0 once it's no longer spent at all. Payments never reserved, or already counting no more than
+	// remaining, are left alone, so releasing again changes nothing.
+	release(ctx context.Context, transactionID string, remaining int64) error
+}
+
+// pgSpendStore keeps spending totals in the card_spend table, and the payments that make them up in
+// card_spend_payments
+type pgSpendStore struct {
+	db *sql.DB
+}
+
+func (p *pgSpendStore) reserve(ctx context.Context, payment cardPayment, limits []spendLimit) (string, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO card_spend_payments (transaction_id, card_id, spent_at, amount) VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (transaction_id) DO NOTHING`, payment.transactionID, payment.cardID, payment.at, payment.amount)
+	if err != nil {
+		return "", err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		// Reserved by an earlier attempt
+		return "", err
+	}
+
+	// Each total stays locked until the transaction ends, so another reservation for the card waits
+	// to see it. Totals are always locked in the same order, so reservations can't deadlock.
+	for _, period := range spendPeriods(payment.at) {
+		var total int64
+		err := tx.QueryRowContext(ctx, `INSERT INTO card_spend (card_id, period, amount) VALUES ($1, $2, $3)
+				  ON CONFLICT (card_id, period) DO UPDATE SET amount = card_spend.amount + EXCLUDED.amount
+				  RETURNING amount`, payment.cardID, period, payment.amount).Scan(&total)
+		if err != nil {
+			return "", err
+		}
+		for _, limit := range limits {
+			if limit.period == period && total > limit.limit {
+				return limit.reason, nil
+			}
+		}
+	}
+	return "", tx.Commit()
+}
+
+func (p *pgSpendStore) release(ctx context.Context, transactionID string, remaining int64) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var cardID string
+	var at time.Time
+	var reserved int64
+	err = tx.QueryRowContext(ctx, `SELECT card_id, spent_at, amount FROM card_spend_payments WHERE transaction_id = $1 FOR UPDATE`,
+		transactionID).Scan(&cardID, &at, &reserved)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if reserved <= remaining {
+		return nil
+	}
+	amount := reserved - remaining
+
+	if _, err := tx.ExecContext(ctx, `UPDATE card_spend_payments SET amount = amount - $1 WHERE transaction_id = $2`, amount, transactionID); err != nil {
+		return err
+	}
+	for _, period := range spendPeriods(at) {
+		if _, err := tx.ExecContext(ctx, `UPDATE card_spend SET amount = amount - $1 WHERE card_id = $2 AND period = $3`, amount, cardID, period); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// channelDisabled reports whether controls turn off payments made through channel. Chip and PIN
+// payments, and those that don't say how the card was used, can't be turned off.
+func channelDisabled(controls *cardspb.CardControls, channel string) bool {
+	switch channel {
+	case channelOnline:
+		return controls.GetOnlineDisabled()
+	case channelContactless:
+		return controls.GetContactlessDisabled()
+	case channelATM:
+		return controls.GetAtmDisabled()
+	case channelMagstripe:
+		return controls.GetMagstripeDisabled()
+	default:
+		return false
+	}
+}
+
+// checkControls returns the decline code and reason if controls forbid a payment because of how or
+// where it was made, or DECLINE_CODE_UNSPECIFIED if they allow it
+func checkControls(controls *cardspb.CardControls, req *cardprocessingpb.CardAuthRequest) (cardprocessingpb.DeclineCode, string) {
+	if channelDisabled(controls, req.GetChannel()) {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_CHANNEL_DISABLED, fmt.Sprintf("%s payments disabled", strings.ToLower(req.GetChannel()))
+	}
+	if req.GetMcc() != 0 {
+		for _, mcc := range controls.GetBlockedMccs() {
+			if mcc == req.GetMcc() {
+				return cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED, fmt.Sprintf("merchant category %04d blocked", mcc)
+			}
+		}
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""
+}
+
+// reserveSpend counts a payment of amount, in the account's currency, towards its card's spending
+// totals. If it would take the card over one of its limits, or a virtual card over its spend cap,
+// it isn't counted and the reason is returned instead. Days and months are UTC.
+func (s *server) reserveSpend(ctx context.Context, controls *cardspb.CardControls, card *cardspb.Card, transactionID, cardID string, amount int64) (string, error) {
+	if limit := controls.GetPerTransactionLimit(); limit > 0 && amount > limit {
+		return "over per-transaction limit", nil
+	}
+
+	now := s.now().UTC()
+	var limits []spendLimit
+	if limit := controls.GetDailyLimit(); limit > 0 {
+		limits = append(limits, spendLimit{period: dayPeriod(now), limit: limit, reason: "over daily limit"})
+	}
+	if limit := controls.GetMonthlyLimit(); limit > 0 {
+		limits = append(limits, spendLimit{period: monthPeriod(now), limit: limit, reason: "over monthly limit"})
+	}
+	if limit := card.GetSpendCap(); limit > 0 {
+		limits = append(limits, spendLimit{period: lifetimePeriod, limit: limit, reason: "over spend cap"})
+	}
+	return s.spend.reserve(ctx, cardPayment{transactionID: transactionID, cardID: cardID, at: now, amount: amount}, limits)
+}
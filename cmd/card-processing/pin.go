@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	cardprocessingpb "github.com/manifoldfinance/disco2/v2/card_processing"
+	cardspb "github.com/manifoldfinance/disco2/v2/cards"
+)
+
+// pinChannels are the channels a PIN entered with a payment is checked for. Chip and PIN terminals
+// and ATMs are the only ones that take a PIN.
+var pinChannels = map[string]bool{
+	channelChip: true,
+	channelATM:  true,
+}
+
+// checkPin checks the PIN entered with a chip and PIN or ATM payment. It returns the decline code
+// and reason if the PIN is wrong or locked, or DECLINE_CODE_UNSPECIFIED if it is right or there is
+// nothing to check. Wrong PINs count towards locking the card's PIN, which Cards does.
+func (s *server) checkPin(ctx context.Context, req *cardprocessingpb.CardAuthRequest) (cardprocessingpb.DeclineCode, string, error) {
+	if req.GetPinBlock() == "" || !pinChannels[req.GetChannel()] {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", nil
+	}
+
+	pin, err := s.cardsClient.VerifyPin(ctx, &cardspb.VerifyPinRequest{CardId: req.GetCardId(), PinBlock: req.GetPinBlock()})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound, codes.FailedPrecondition:
+		// A PIN was entered for a card that has none, so it can't be right
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN, "card has no PIN", nil
+	case codes.InvalidArgument:
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN, "invalid PIN block", nil
+	default:
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", fmt.Errorf("failed to verify PIN of card %s: %w", req.GetCardId(), err)
+	}
+
+	switch {
+	case pin.GetVerified():
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", nil
+	case pin.GetLocked():
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_PIN_LOCKED, "PIN locked", nil
+	default:
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN, "incorrect PIN", nil
+	}
+}
+
+// withoutPin returns req with any PIN block removed, for logging
+func withoutPin(req *cardprocessingpb.CardAuthRequest) *cardprocessingpb.CardAuthRequest {
+	if req.GetPinBlock() == "" {
+		return req
+	}
+	logged := proto.Clone(req).(*cardprocessingpb.CardAuthRequest)
+	logged.PinBlock = ""
+	return logged
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cardspb "github.com/manifoldfinance/disco2/v2/cards/cards"
+)
+
+// maxMCC is the largest ISO 18245 merchant category code
+const maxMCC = 9999
+
+func (s *server) GetCardControls(ctx context.Context, req *cardspb.GetCardRequest) (*cardspb.CardControls, error) {
+	log.Printf("Received GetCardControls request: %+v", req)
+
+	// A card without a card_controls row has no controls
+	query := `SELECT c.card_id, COALESCE(cc.daily_limit, 0), COALESCE(cc.monthly_limit, 0), COALESCE(cc.per_transaction_limit, 0),
+			  COALESCE(cc.blocked_mccs, ''), COALESCE(cc.online_disabled, FALSE), COALESCE(cc.contactless_disabled, FALSE),
+			  COALESCE(cc.atm_disabled, FALSE), COALESCE(cc.magstripe_disabled, FALSE)
+			  FROM cards c LEFT JOIN card_controls cc ON cc.card_id = c.card_id WHERE c.card_id = $1`
+
+	var controls cardspb.CardControls
+	var blockedMCCs string
+	err := s.db.QueryRowContext(ctx, query, req.GetCardId()).Scan(
+		&controls.CardId,
+		&controls.DailyLimit,
+		&controls.MonthlyLimit,
+		&controls.PerTransactionLimit,
+		&blockedMCCs,
+		&controls.OnlineDisabled,
+		&controls.ContactlessDisabled,
+		&controls.AtmDisabled,
+		&controls.MagstripeDisabled,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to get card controls: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get card controls")
+	}
+	if controls.BlockedMccs, err = parseMCCs(blockedMCCs); err != nil {
+		log.Printf("failed to parse blocked merchant categories of card %s: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to get card controls")
+	}
+
+	return &controls, nil
+}
+
+func (s *server) UpdateCardControls(ctx context.Context, req *cardspb.CardControls) (*cardspb.CardControls, error) {
+	log.Printf("Received UpdateCardControls request: %+v", req)
+
+	if req.GetDailyLimit() < 0 || req.GetMonthlyLimit() < 0 || req.GetPerTransactionLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limits can't be negative")
+	}
+	for _, mcc := range req.GetBlockedMccs() {
+		if mcc <= 0 || mcc > maxMCC {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid merchant category code: %d", mcc)
+		}
+	}
+
+	controls := &cardspb.CardControls{
+		CardId:              req.GetCardId(),
+		DailyLimit:          req.GetDailyLimit(),
+		MonthlyLimit:        req.GetMonthlyLimit(),
+		PerTransactionLimit: req.GetPerTransactionLimit(),
+		BlockedMccs:         normalizeMCCs(req.GetBlockedMccs()),
+		OnlineDisabled:      req.GetOnlineDisabled(),
+		ContactlessDisabled: req.GetContactlessDisabled(),
+		AtmDisabled:         req.GetAtmDisabled(),
+		MagstripeDisabled:   req.GetMagstripeDisabled(),
+	}
+
+	// Selecting from cards inserts nothing for a card that doesn't exist
+	query := `INSERT INTO card_controls (card_id, daily_limit, monthly_limit, per_transaction_limit, blocked_mccs,
+			  online_disabled, contactless_disabled, atm_disabled, magstripe_disabled, updated_at)
+			  SELECT card_id, $2, $3, $4, $5, $6, $7, $8, $9, NOW() FROM cards WHERE card_id = $1
+			  ON CONFLICT (card_id) DO UPDATE SET daily_limit = EXCLUDED.daily_limit, monthly_limit = EXCLUDED.monthly_limit,
+			  per_transaction_limit = EXCLUDED.per_transaction_limit, blocked_mccs = EXCLUDED.blocked_mccs,
+			  online_disabled = EXCLUDED.online_disabled, contactless_disabled = EXCLUDED.contactless_disabled,
+			  atm_disabled = EXCLUDED.atm_disabled, magstripe_disabled = EXCLUDED.magstripe_disabled, updated_at = EXCLUDED.updated_at
+			  RETURNING card_id`
+
+	var cardID string
+	err := s.db.QueryRowContext(ctx, query, controls.GetCardId(), controls.GetDailyLimit(), controls.GetMonthlyLimit(),
+		controls.GetPerTransactionLimit(), formatMCCs(controls.GetBlockedMccs()), controls.GetOnlineDisabled(),
+		controls.GetContactlessDisabled(), controls.GetAtmDisabled(), controls.GetMagstripeDisabled()).Scan(&cardID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found for controls update: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to update card controls: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update card controls")
+	}
+
+	return controls, nil
+}
+
+// normalizeMCCs sorts merchant category codes and drops duplicates
+func normalizeMCCs(mccs []int32) []int32 {
+	seen := make(map[int32]bool, len(mccs))
+	var normalized []int32
+	for _, mcc := range mccs {
+		if !seen[mcc] {
+			seen[mcc] = true
+			normalized = append(normalized, mcc)
+		}
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] < normalized[j] })
+	return normalized
+}
+
+// formatMCCs stores merchant category codes as a comma-separated list
+func formatMCCs(mccs []int32) string {
+	parts := make([]string, len(mccs))
+	for i, mcc := range mccs {
+		parts[i] = strconv.Itoa(int(mcc))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseMCCs reads merchant category codes stored by formatMCCs
+func parseMCCs(s string) ([]int32, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var mccs []int32
+	for _, part := range strings.Split(s, ",") {
+		mcc, err := strconv.ParseInt(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merchant category code %q: %w", part, err)
+		}
+		mccs = append(mccs, int32(mcc))
+	}
+	return mccs, nil
+}
+
+func (s *server) getCardControlsHandler(c echo.Context) error {
+	cardID := c.Param("id")
+	req := &cardspb.GetCardRequest{CardId: cardID}
+
+	controls, err := s.GetCardControls(c.Request().Context(), req)
+	if err != nil {
+		// Handle gRPC errors
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+			case codes.Internal:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+			default:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "unknown gRPC error"})
+			}
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, controls)
+}
+
+func (s *server) updateCardControlsHandler(c echo.Context) error {
+	req := new(cardspb.CardControls)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.CardId = c.Param("id")
+
+	controls, err := s.UpdateCardControls(c.Request().Context(), req)
+	if err != nil {
+		// Handle gRPC errors
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+			case codes.InvalidArgument:
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+			case codes.Internal:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+			default:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "unknown gRPC error"})
+			}
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, controls)
+}
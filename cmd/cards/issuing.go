@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/pan"
+)
+
+// Card types
+const (
+	cardTypePhysical = "physical"
+	cardTypeVirtual  = "virtual"
+)
+
+// binRange is a block of card numbers cards of one type are issued from
+type binRange struct {
+	CardType       string `json:"card_type"`
+	BIN            string `json:"bin"`
+	PANLength      int    `json:"pan_length"`
+	ValidityMonths int    `json:"validity_months"` // how long a card is valid for from the month it was issued
+}
+
+// issuingConfig is the card issuing configuration file
+type issuingConfig struct {
+	BINRanges []binRange `json:"bin_ranges"`
+}
+
+// loadIssuingConfig reads the card issuing configuration from a JSON file
+func loadIssuingConfig(path string) (*issuingConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg issuingConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, r := range cfg.BINRanges {
+		if r.CardType != cardTypePhysical && r.CardType != cardTypeVirtual {
+			return nil, fmt.Errorf("%s: BIN %s has unknown card type %q", path, r.BIN, r.CardType)
+		}
+		if _, err := pan.Generate(r.BIN, r.PANLength); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if r.ValidityMonths <= 0 {
+			return nil, fmt.Errorf("%s: BIN %s must be valid for a positive number of months", path, r.BIN)
+		}
+	}
+	return &cfg, nil
+}
+
+// activationCodeDigits is the length of the code physical cards are activated with
+const activationCodeDigits = 6
+
+// issuedCard holds the details of a newly issued card chosen by Cards. Its number and CVV are
+// generated from its BIN range by the vault, and never leave it.
+type issuedCard struct {
+	binRange       binRange
+	expiryMonth    int32
+	expiryYear     int32
+	activationCode string // physical cards only, sent with the card; Cards only keeps its hash
+}
+
+// issuer picks the BIN ranges and expiry dates of new cards
+type issuer struct {
+	ranges []binRange
+	now    func() time.Time
+}
+
+// issue picks the details of a new card of cardType
+func (i *issuer) issue(cardType string) (*issuedCard, error) {
+	r, err := i.binRange(cardType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Physical cards spend time in the post, so they are only usable once the cardholder who
+	// received them activates them
+	var activationCode string
+	if cardType == cardTypePhysical {
+		code, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate activation code: %w", err)
+		}
+		activationCode = fmt.Sprintf("%0*d", activationCodeDigits, code.Int64())
+	}
+
+	// Cards are valid until the end of their expiry month
+	expiry := i.now().UTC().AddDate(0, r.ValidityMonths, 0)
+	return &issuedCard{
+		binRange:       r,
+		expiryMonth:    int32(expiry.Month()),
+		expiryYear:     int32(expiry.Year()),
+		activationCode: activationCode,
+	}, nil
+}
+
+// binRange picks one of the ranges cards of cardType are issued from at random, spreading cards across them
+func (i *issuer) binRange(cardType string) (binRange, error) {
+	var ranges []binRange
+	for _, r := range i.ranges {
+		if r.CardType == cardType {
+			ranges = append(ranges, r)
+		}
+	}
+	if len(ranges) == 0 {
+		return binRange{}, fmt.Errorf("no BIN range for %s cards", cardType)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(ranges))))
+	if err != nil {
+		return binRange{}, fmt.Errorf("failed to pick BIN range: %w", err)
+	}
+	return ranges[n.Int64()], nil
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+	"github.com/manifoldfinance/disco2/v2/pkg/pinblock"
+	"github.com/manifoldfinance/disco2/v2/pkg/sca"
+
+	cardspb "github.com/manifoldfinance/disco2/v2/cards/cards"
+)
+
+// maxPinAttempts is how many wrong PINs in a row lock a card's PIN
+const maxPinAttempts = 3
+
+// PIN reveals are detokenize requests to the vault for this purpose. The vault grants them to Cards
+// by its client certificate.
+const vaultPurposePinReveal = "pin_reveal"
+
+// revealPinAction is the action an SCA token must allow to reveal a card's PIN
+func revealPinAction(cardID string) string {
+	return "reveal_pin:" + cardID
+}
+
+// pinState is the state of a card's PIN, locked for update
+type pinState struct {
+	userID         string
+	panToken       string
+	failedAttempts int32
+	locked         bool
+}
+
+// SetPin gives a card its first PIN. Changing a PIN takes the current one, see ChangePin. Like
+// the other PIN methods, it passes PIN blocks on to the vault and never logs them.
+func (s *server) SetPin(ctx context.Context, req *cardspb.SetPinRequest) (*cardspb.PinStatus, error) {
+	log.Printf("Received SetPin request for card %s", req.GetCardId())
+
+	if _, err := pinblock.Decode(req.GetPinBlock()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid PIN block")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	var panToken, cardStatus string
+	query := `SELECT COALESCE(pan_token, ''), status FROM cards WHERE card_id = $1`
+	err = tx.QueryRowContext(ctx, query, req.GetCardId()).Scan(&panToken, &cardStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found to set PIN: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to get card to set PIN: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+	if cardStatus == "CLOSED" || cardStatus == "USED" {
+		return nil, status.Errorf(codes.FailedPrecondition, "card is %s", strings.ToLower(cardStatus))
+	}
+	if panToken == "" {
+		// Cards issued before card numbers were generated have nothing in the vault to hold a PIN
+		return nil, status.Errorf(codes.FailedPrecondition, "card has no card number")
+	}
+
+	insert := `INSERT INTO card_pins (card_id, failed_attempts, locked, updated_at) VALUES ($1, 0, FALSE, NOW())
+			   ON CONFLICT (card_id) DO NOTHING`
+	result, err := tx.ExecContext(ctx, insert, req.GetCardId())
+	if err != nil {
+		log.Printf("failed to insert card PIN state: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to insert card PIN state: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+	if n == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "card already has a PIN")
+	}
+
+	if _, err := s.vaultClient.SetPinBlock(ctx, &vaultpb.SetPinBlockRequest{Token: panToken, PinBlock: req.GetPinBlock()}); err != nil {
+		log.Printf("failed to store PIN of card %s in vault: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+
+	log.Printf("PIN set for card %s", req.GetCardId())
+	return &cardspb.PinStatus{CardId: req.GetCardId(), AttemptsRemaining: maxPinAttempts}, nil
+}
+
+// ChangePin replaces a card's PIN if the current one given is right. A wrong one counts towards
+// locking the PIN, as it would at a terminal.
+func (s *server) ChangePin(ctx context.Context, req *cardspb.ChangePinRequest) (*cardspb.PinStatus, error) {
+	log.Printf("Received ChangePin request for card %s", req.GetCardId())
+
+	if _, err := pinblock.Decode(req.GetCurrentPinBlock()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid current PIN block")
+	}
+	if _, err := pinblock.Decode(req.GetNewPinBlock()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid new PIN block")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to change PIN")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	pin, err := s.lockPin(ctx, tx, req.GetCardId())
+	if err != nil {
+		return nil, pinError(err, req.GetCardId(), "failed to change PIN")
+	}
+	if pin.locked {
+		return nil, status.Errorf(codes.FailedPrecondition, "PIN is locked")
+	}
+
+	pinStatus, err := s.checkPin(ctx, tx, req.GetCardId(), pin, req.GetCurrentPinBlock())
+	if err != nil {
+		return nil, pinError(err, req.GetCardId(), "failed to change PIN")
+	}
+	if pinStatus.GetVerified() {
+		if _, err := s.vaultClient.SetPinBlock(ctx, &vaultpb.SetPinBlockRequest{Token: pin.panToken, PinBlock: req.GetNewPinBlock()}); err != nil {
+			log.Printf("failed to store PIN of card %s in vault: %v", req.GetCardId(), err)
+			return nil, status.Errorf(codes.Internal, "failed to change PIN")
+		}
+	}
+
+	// A wrong PIN is committed too, so it counts
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to change PIN")
+	}
+
+	if !pinStatus.GetVerified() {
+		if pinStatus.GetLocked() {
+			return nil, status.Errorf(codes.PermissionDenied, "incorrect PIN, PIN is now locked")
+		}
+		return nil, status.Errorf(codes.PermissionDenied, "incorrect PIN, %d attempts remaining", pinStatus.GetAttemptsRemaining())
+	}
+	log.Printf("PIN changed for card %s", req.GetCardId())
+	return pinStatus, nil
+}
+
+// RevealPin returns a card's PIN to its cardholder, who must have just authenticated strongly. As
+// the cardholder then knows their PIN, revealing it also unlocks it.
+func (s *server) RevealPin(ctx context.Context, req *cardspb.RevealPinRequest) (*cardspb.RevealPinResponse, error) {
+	log.Printf("Received RevealPin request for card %s", req.GetCardId())
+
+	if req.GetScaToken() == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "strong customer authentication required")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reveal PIN")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	pin, err := s.lockPin(ctx, tx, req.GetCardId())
+	if err != nil {
+		return nil, pinError(err, req.GetCardId(), "failed to reveal PIN")
+	}
+	if err := sca.Verify(s.scaKey, req.GetScaToken(), pin.userID, revealPinAction(req.GetCardId()), s.now()); err != nil {
+		log.Printf("rejected SCA token to reveal PIN of card %s: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Unauthenticated, "strong customer authentication failed")
+	}
+
+	data, err := s.vaultClient.Detokenize(ctx, &vaultpb.DetokenizeRequest{Token: pin.panToken, Purpose: vaultPurposePinReveal})
+	if err != nil {
+		log.Printf("failed to get PIN of card %s from vault: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to reveal PIN")
+	}
+	revealed, err := pinblock.Decode(data.GetPinBlock())
+	if err != nil {
+		log.Printf("failed to decode PIN block of card %s: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to reveal PIN")
+	}
+
+	if pin.locked || pin.failedAttempts > 0 {
+		if _, err := tx.ExecContext(ctx, `UPDATE card_pins SET failed_attempts = 0, locked = FALSE, updated_at = NOW() WHERE card_id = $1`, req.GetCardId()); err != nil {
+			log.Printf("failed to unlock PIN: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to reveal PIN")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reveal PIN")
+	}
+
+	log.Printf("PIN revealed for card %s", req.GetCardId())
+	return &cardspb.RevealPinResponse{Pin: revealed}, nil
+}
+
+// VerifyPin checks a PIN entered at a terminal or ATM. A wrong one counts towards locking the PIN,
+// and once it is locked no PIN is checked until the cardholder unlocks it.
+func (s *server) VerifyPin(ctx context.Context, req *cardspb.VerifyPinRequest) (*cardspb.PinStatus, error) {
+	log.Printf("Received VerifyPin request for card %s", req.GetCardId())
+
+	if _, err := pinblock.Decode(req.GetPinBlock()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid PIN block")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to verify PIN")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	pin, err := s.lockPin(ctx, tx, req.GetCardId())
+	if err != nil {
+		return nil, pinError(err, req.GetCardId(), "failed to verify PIN")
+	}
+	if pin.locked {
+		return &cardspb.PinStatus{CardId: req.GetCardId(), Locked: true}, nil
+	}
+
+	pinStatus, err := s.checkPin(ctx, tx, req.GetCardId(), pin, req.GetPinBlock())
+	if err != nil {
+		return nil, pinError(err, req.GetCardId(), "failed to verify PIN")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to verify PIN")
+	}
+
+	return pinStatus, nil
+}
+
+// lockPin reads the PIN state of a card, locking it until tx ends so attempts are counted one at a
+// time. It returns sql.ErrNoRows if the card has no PIN.
+func (s *server) lockPin(ctx context.Context, tx *sql.Tx, cardID string) (*pinState, error) {
+	query := `SELECT c.user_id, COALESCE(c.pan_token, ''), p.failed_attempts, p.locked FROM card_pins p
+			  JOIN cards c ON c.card_id = p.card_id WHERE p.card_id = $1 FOR UPDATE OF p`
+	var pin pinState
+	err := tx.QueryRowContext(ctx, query, cardID).Scan(&pin.userID, &pin.panToken, &pin.failedAttempts, &pin.locked)
+	if err != nil {
+		return nil, err
+	}
+	return &pin, nil
+}
+
+// checkPin checks pinBlock against the PIN of a card that isn't locked. A right PIN clears the count
+// of wrong ones; a wrong one adds to it, and the last one allowed locks the PIN and enqueues a
+// "card:pin_locked" event in tx.
+func (s *server) checkPin(ctx context.Context, tx *sql.Tx, cardID string, pin *pinState, pinBlock string) (*cardspb.PinStatus, error) {
+	match, err := s.vaultClient.VerifyPinBlock(ctx, &vaultpb.VerifyPinBlockRequest{Token: pin.panToken, PinBlock: pinBlock})
+	if err != nil {
+		return nil, err
+	}
+
+	if match.GetMatch() {
+		pin.failedAttempts = 0
+	} else {
+		pin.failedAttempts++
+		pin.locked = pin.failedAttempts >= maxPinAttempts
+	}
+	update := `UPDATE card_pins SET failed_attempts = $2, locked = $3, updated_at = NOW() WHERE card_id = $1`
+	if _, err := tx.ExecContext(ctx, update, cardID, pin.failedAttempts, pin.locked); err != nil {
+		return nil, err
+	}
+
+	if pin.locked {
+		log.Printf("PIN of card %s locked after %d wrong attempts", cardID, pin.failedAttempts)
+		event := &eventspb.CardPinLocked{
+			CardId:    cardID,
+			UserId:    pin.userID,
+			Timestamp: s.now().UTC().Format(time.RFC3339),
+		}
+		if err := events.Enqueue(ctx, tx, events.StreamCardPinLocked, cardID, event); err != nil {
+			return nil, err
+		}
+	}
+
+	remaining := maxPinAttempts - pin.failedAttempts
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &cardspb.PinStatus{
+		CardId:            cardID,
+		Verified:          match.GetMatch(),
+		Locked:            pin.locked,
+		AttemptsRemaining: remaining,
+	}, nil
+}
+
+// pinError returns the gRPC error for err from lockPin or checkPin
+func pinError(err error, cardID, message string) error {
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "card has no PIN")
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		// The vault has no PIN for the card, though Cards thinks it has one
+		log.Printf("vault has no PIN for card %s: %v", cardID, err)
+		return status.Errorf(codes.FailedPrecondition, "card has no PIN")
+	}
+	log.Printf("%s for card %s: %v", message, cardID, err)
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+
+	cardspb "github.com/manifoldfinance/disco2/v2/cards/cards"
+)
+
+// reissueReasons are the reasons a card can be replaced for
+var reissueReasons = map[string]bool{"LOST": true, "STOLEN": true, "DAMAGED": true, "EXPIRING": true}
+
+// ReissueCard replaces a card with a new one of the same type with a new number. The old card is
+// closed, and its controls, recurring merchants, nickname and being the user's default card carry
+// over to the new card, all in one transaction. The account the old card paid from carries over too.
+func (s *server) ReissueCard(ctx context.Context, req *cardspb.ReissueCardRequest) (*cardspb.Card, error) {
+	log.Printf("Received ReissueCard request: %+v", req)
+
+	if !reissueReasons[req.GetReason()] {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid reissue reason: %s", req.GetReason())
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Lock the old card so it can't be reissued twice at once. Its virtual card rules carry over.
+	var userID, cardType, cardStatus, nickname string
+	var rules virtualRules
+	var isDefault bool
+	query := `SELECT user_id, card_type, status, COALESCE(virtual_type, ''), spend_cap, expires_at, COALESCE(locked_merchant_id, ''),
+			  COALESCE(nickname, ''), is_default FROM cards WHERE card_id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, req.GetCardId()).Scan(&userID, &cardType, &cardStatus,
+		&rules.virtualType, &rules.spendCap, &rules.expiresAt, &rules.lockedMerchantID, &nickname, &isDefault)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found for reissue: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to get card to reissue: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+	if err := checkTransition(cardStatus, "CLOSED"); err != nil {
+		return nil, err
+	}
+
+	newCard, err := s.insertCard(ctx, tx, userID, cardType, rules, req.GetCardId(), req.GetActor())
+	if err != nil {
+		log.Printf("failed to insert replacement card: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	// Accounts keeps the link even if the reissue then fails, which is harmless for a card that never existed
+	if _, err := s.accountsClient.CarryCardLink(ctx, &accountspb.CarryCardLinkRequest{
+		CardId:         newCard.GetCardId(),
+		ReplacesCardId: req.GetCardId(),
+	}); err != nil {
+		log.Printf("failed to carry account link over to replacement card: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	closeCard := `UPDATE cards SET status = 'CLOSED', is_default = FALSE, updated_at = NOW() WHERE card_id = $1`
+	if _, err := tx.ExecContext(ctx, closeCard, req.GetCardId()); err != nil {
+		log.Printf("failed to close reissued card: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+	if err := recordStatusChange(ctx, tx, req.GetCardId(), cardStatus, "CLOSED", "reissued: "+req.GetReason(), req.GetActor()); err != nil {
+		log.Printf("failed to record card status change: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	// The old card stopped being the default above, so the new one can take over
+	if nickname != "" || isDefault {
+		carryOver := `UPDATE cards SET nickname = $2, is_default = $3 WHERE card_id = $1`
+		if _, err := tx.ExecContext(ctx, carryOver, newCard.GetCardId(), sql.NullString{String: nickname, Valid: nickname != ""}, isDefault); err != nil {
+			log.Printf("failed to carry nickname and default over to replacement card: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to reissue card")
+		}
+		newCard.Nickname = nickname
+		newCard.IsDefault = isDefault
+	}
+
+	moveControls := `INSERT INTO card_controls (card_id, daily_limit, monthly_limit, per_transaction_limit, blocked_mccs,
+				  online_disabled, contactless_disabled, atm_disabled, magstripe_disabled, updated_at)
+				  SELECT $2, daily_limit, monthly_limit, per_transaction_limit, blocked_mccs,
+				  online_disabled, contactless_disabled, atm_disabled, magstripe_disabled, NOW()
+				  FROM card_controls WHERE card_id = $1`
+	if _, err := tx.ExecContext(ctx, moveControls, req.GetCardId(), newCard.GetCardId()); err != nil {
+		log.Printf("failed to move card controls: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	moveMerchants := `UPDATE recurring_merchants SET card_id = $2 WHERE card_id = $1`
+	if _, err := tx.ExecContext(ctx, moveMerchants, req.GetCardId(), newCard.GetCardId()); err != nil {
+		log.Printf("failed to move recurring merchants: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	// Enqueue "card:status_changed" for the old card and "card:created" for the new one
+	closed := &eventspb.CardStatusChanged{
+		CardId:    req.GetCardId(),
+		UserId:    userID,
+		NewStatus: "CLOSED",
+	}
+	if err := events.Enqueue(ctx, tx, events.StreamCardStatusChanged, req.GetCardId(), closed); err != nil {
+		log.Printf("failed to enqueue card:status_changed event: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+	created := &eventspb.CardCreated{
+		CardId: newCard.GetCardId(),
+		UserId: newCard.GetUserId(),
+		Status: newCard.GetStatus(),
+	}
+	if err := events.Enqueue(ctx, tx, events.StreamCardCreated, newCard.GetCardId(), created); err != nil {
+		log.Printf("failed to enqueue card:created event: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reissue card")
+	}
+
+	log.Printf("Card %s reissued as %s (%s)", req.GetCardId(), newCard.GetCardId(), req.GetReason())
+	return newCard, nil
+}
+
+func (s *server) AddRecurringMerchant(ctx context.Context, req *cardspb.RecurringMerchant) (*cardspb.RecurringMerchant, error) {
+	log.Printf("Received AddRecurringMerchant request: %+v", req)
+
+	if req.GetMerchantId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "merchant_id is required")
+	}
+
+	// Selecting from cards inserts nothing for a card that doesn't exist
+	query := `INSERT INTO recurring_merchants (card_id, merchant_id, merchant_name, created_at)
+			  SELECT card_id, $2, $3, NOW() FROM cards WHERE card_id = $1
+			  ON CONFLICT (card_id, merchant_id) DO UPDATE SET merchant_name = EXCLUDED.merchant_name
+			  RETURNING card_id`
+
+	var cardID string
+	err := s.db.QueryRowContext(ctx, query, req.GetCardId(), req.GetMerchantId(), req.GetMerchantName()).Scan(&cardID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found for recurring merchant: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to add recurring merchant: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to add recurring merchant")
+	}
+
+	return &cardspb.RecurringMerchant{CardId: cardID, MerchantId: req.GetMerchantId(), MerchantName: req.GetMerchantName()}, nil
+}
+
+func (s *server) ListRecurringMerchants(ctx context.Context, req *cardspb.GetCardRequest) (*cardspb.RecurringMerchants, error) {
+	log.Printf("Received ListRecurringMerchants request: %+v", req)
+
+	query := `SELECT card_id, merchant_id, merchant_name FROM recurring_merchants WHERE card_id = $1 ORDER BY created_at`
+	rows, err := s.db.QueryContext(ctx, query, req.GetCardId())
+	if err != nil {
+		log.Printf("failed to list recurring merchants: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list recurring merchants")
+	}
+	defer rows.Close()
+
+	merchants := &cardspb.RecurringMerchants{}
+	for rows.Next() {
+		var merchant cardspb.RecurringMerchant
+		if err := rows.Scan(&merchant.CardId, &merchant.MerchantId, &merchant.MerchantName); err != nil {
+			log.Printf("failed to scan recurring merchant: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list recurring merchants")
+		}
+		merchants.Merchants = append(merchants.Merchants, &merchant)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to list recurring merchants: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list recurring merchants")
+	}
+
+	return merchants, nil
+}
+
+func (s *server) reissueCardHandler(c echo.Context) error {
+	var reissueReq struct {
+		Reason string `json:"reason"`
+		Actor  string `json:"actor"`
+	}
+	if err := c.Bind(&reissueReq); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	req := &cardspb.ReissueCardRequest{
+		CardId: c.Param("id"),
+		Reason: reissueReq.Reason,
+		Actor:  reissueReq.Actor,
+	}
+
+	card, err := s.ReissueCard(c.Request().Context(), req)
+	if err != nil {
+		// Handle gRPC errors
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+			case codes.InvalidArgument:
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+			case codes.FailedPrecondition:
+				return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+			case codes.Internal:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+			default:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "unknown gRPC error"})
+			}
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	return c.JSON(http.StatusCreated, card)
+}
+
+func (s *server) listRecurringMerchantsHandler(c echo.Context) error {
+	req := &cardspb.GetCardRequest{CardId: c.Param("id")}
+
+	merchants, err := s.ListRecurringMerchants(c.Request().Context(), req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, merchants)
+}
@@ -0,0 +1,364 @@
+// Command clearing settles card transactions from a card network settlement (clearing) file.
+//
+// Each record is matched to the transaction authorized with the same card and auth code. A matched
+// AUTHORIZED transaction has its balance hold captured at the settled amount, which may differ from
+// the authorized amount (tips, partial shipments), and is then marked SETTLED. Records that cannot be
+// settled automatically, such as force-posts and offline transactions without an auth code, are
+// published to the "clearing:review" stream for operations to review.
+//
+// Settlement files are CSV with a header row naming the columns, in any order:
+//
+//	network_reference  the network's unique reference for the record
+//	card_id            card the transaction was made with
+//	auth_code          auth code returned when the transaction was authorized; empty if there was none
+//	amount             settled amount in minor units of currency
+//	currency           ISO 4217 code
+//	merchant_name      merchant name as presented by the network
+//	transaction_date   YYYY-MM-DD
+//
+// Lines starting with # are ignored. See testdata/settlement.csv for a sample. Ingesting a file
+// again is safe: records already settled are skipped.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
+)
+
+const usage = `Usage: clearing [flags] <settlement file>
+
+Settles authorized card transactions from a settlement file and sends records that
+cannot be settled to the clearing:review stream.
+
+Flags:
+`
+
+// Transaction statuses clearing reads and sets
+const (
+	txnAuthorized = "AUTHORIZED"
+	txnSettled    = "SETTLED"
+)
+
+// columns are the header names a settlement file must have
+var columns = []string{"network_reference", "card_id", "auth_code", "amount", "currency", "merchant_name", "transaction_date"}
+
+// record is one line of a settlement file
+type record struct {
+	line             int
+	networkReference string
+	cardID           string
+	authCode         string
+	amount           int64
+	currency         string
+	merchantName     string
+	transactionDate  string
+}
+
+// summary counts what happened to the records of a file
+type summary struct {
+	settled        int
+	alreadySettled int
+	review         int
+}
+
+type clearer struct {
+	transactionsClient transactionspb.TransactionsClient
+	balanceClient      balancepb.BalanceClient
+	redisClient        *redis.Client
+	// maxOverPercent is how far above the authorized amount a record may settle, e.g. for tips
+	maxOverPercent int64
+	out            io.Writer
+}
+
+func main() {
+	transactionsAddr := flag.String("transactions", "localhost:50052", "Transactions service address")
+	balanceAddr := flag.String("balance", "localhost:50053", "Balance service address")
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
+	maxOver := flag.Int64("max-over", 20, "percentage above the authorized amount a record may settle for")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	transactionsConn, err := grpc.Dial(*transactionsAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clearing: failed to connect to Transactions service: %v\n", err)
+		os.Exit(1)
+	}
+	defer transactionsConn.Close()
+
+	balanceConn, err := grpc.Dial(*balanceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clearing: failed to connect to Balance service: %v\n", err)
+		os.Exit(1)
+	}
+	defer balanceConn.Close()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr: *redisAddr,
+		DB:   0,
+	})
+	defer rdb.Close()
+
+	// Stop between records on SIGINT/SIGTERM; the file can be ingested again to finish it
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	c := &clearer{
+		transactionsClient: transactionspb.NewTransactionsClient(transactionsConn),
+		balanceClient:      balancepb.NewBalanceClient(balanceConn),
+		redisClient:        rdb,
+		maxOverPercent:     *maxOver,
+		out:                os.Stdout,
+	}
+	if err := c.ingestFile(ctx, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "clearing: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// ingestFile settles the records of the settlement file at path
+func (c *clearer) ingestFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sum, err := c.ingest(ctx, filepath.Base(path), f)
+	fmt.Fprintf(c.out, "%d settled, %d already settled, %d sent for review\n", sum.settled, sum.alreadySettled, sum.review)
+	return err
+}
+
+// ingest settles the records read from r. It stops at the first error that is not the fault of
+// a record, such as a service being unavailable, so that the file can be ingested again later.
+func (c *clearer) ingest(ctx context.Context, file string, r io.Reader) (summary, error) {
+	var sum summary
+
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1 // Malformed records are sent for review rather than failing the file
+	header, err := cr.Read()
+	if err != nil {
+		return sum, fmt.Errorf("failed to read header: %w", err)
+	}
+	index, err := columnIndex(header)
+	if err != nil {
+		return sum, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return sum, err
+		}
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return sum, nil
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return sum, fmt.Errorf("failed to read line %d: %w", line, err)
+			}
+			if err := c.sendForReview(ctx, file, record{line: line}, "malformed record: "+parseErr.Err.Error(), ""); err != nil {
+				return sum, err
+			}
+			sum.review++
+			continue
+		}
+
+		rec, err := parseRecord(fields, index, line)
+		if err != nil {
+			if err := c.sendForReview(ctx, file, rec, "malformed record: "+err.Error(), ""); err != nil {
+				return sum, err
+			}
+			sum.review++
+			continue
+		}
+
+		outcome, err := c.settle(ctx, file, rec)
+		if err != nil {
+			return sum, fmt.Errorf("line %d (%s): %w", rec.line, rec.networkReference, err)
+		}
+		switch outcome {
+		case outcomeSettled:
+			sum.settled++
+		case outcomeAlreadySettled:
+			sum.alreadySettled++
+		case outcomeReview:
+			sum.review++
+		}
+	}
+}
+
+// columnIndex maps each required column to its position in header
+func columnIndex(header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	for _, name := range columns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("header has no %s column", name)
+		}
+	}
+	return index, nil
+}
+
+// parseRecord reads the fields of one line. On error the record holds whatever could be read.
+func parseRecord(fields []string, index map[string]int, line int) (record, error) {
+	field := func(name string) string {
+		if i := index[name]; i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+	rec := record{
+		line:             line,
+		networkReference: field("network_reference"),
+		cardID:           field("card_id"),
+		authCode:         strings.ToUpper(field("auth_code")),
+		currency:         strings.ToUpper(field("currency")),
+		merchantName:     field("merchant_name"),
+		transactionDate:  field("transaction_date"),
+	}
+
+	if len(fields) != len(index) {
+		return rec, fmt.Errorf("expected %d fields, got %d", len(index), len(fields))
+	}
+	if rec.networkReference == "" || rec.cardID == "" {
+		return rec, errors.New("network_reference and card_id are required")
+	}
+	amount, err := strconv.ParseInt(field("amount"), 10, 64)
+	if err != nil || amount <= 0 {
+		return rec, fmt.Errorf("invalid amount %q", field("amount"))
+	}
+	rec.amount = amount
+	if len(rec.currency) != 3 {
+		return rec, fmt.Errorf("invalid currency %q", rec.currency)
+	}
+	if _, err := time.Parse("2006-01-02", rec.transactionDate); err != nil {
+		return rec, fmt.Errorf("invalid transaction_date %q", rec.transactionDate)
+	}
+	return rec, nil
+}
+
+// Outcomes of settling a record
+const (
+	outcomeSettled        = "SETTLED"
+	outcomeAlreadySettled = "ALREADY_SETTLED"
+	outcomeReview         = "REVIEW"
+)
+
+// settle matches rec to its authorization, captures the hold at the settled amount and marks the
+// transaction SETTLED. Records that cannot be settled are sent for review. Every step is safe to
+// repeat, so a record whose settlement was interrupted is finished when the file is ingested again.
+func (c *clearer) settle(ctx context.Context, file string, rec record) (string, error) {
+	review := func(reason, transactionID string) (string, error) {
+		if err := c.sendForReview(ctx, file, rec, reason, transactionID); err != nil {
+			return "", err
+		}
+		return outcomeReview, nil
+	}
+
+	if rec.authCode == "" {
+		return review("no auth code, force-post or offline transaction", "")
+	}
+
+	txn, err := c.transactionsClient.GetTransactionByAuthCode(ctx, &transactionspb.AuthCodeQuery{CardId: rec.cardID, AuthCode: rec.authCode})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return review("no authorization with this card and auth code", "")
+		}
+		return "", fmt.Errorf("failed to find transaction: %w", err)
+	}
+
+	switch {
+	case txn.GetStatus() == txnSettled:
+		return outcomeAlreadySettled, nil
+	case txn.GetStatus() != txnAuthorized:
+		return review(fmt.Sprintf("transaction is %s", txn.GetStatus()), txn.GetId())
+	case txn.GetCurrency() != rec.currency:
+		return review(fmt.Sprintf("currency %s differs from authorized %s", rec.currency, txn.GetCurrency()), txn.GetId())
+	case rec.amount*100 > txn.GetAmount()*(100+c.maxOverPercent):
+		return review(fmt.Sprintf("amount %d is more than %d%% over authorized %d", rec.amount, c.maxOverPercent, txn.GetAmount()), txn.GetId())
+	case txn.GetHoldId() == "":
+		return review("authorization has no hold to capture", txn.GetId())
+	}
+
+	// Capture the hold at the settled amount, debiting the account for any difference
+	_, err = c.balanceClient.CaptureHold(ctx, &balancepb.CaptureHoldRequest{HoldId: txn.GetHoldId(), Amount: rec.amount})
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch {
+		case st.Code() == codes.FailedPrecondition && st.Message() == "hold is CAPTURED":
+			// Captured by an earlier, interrupted ingestion
+		case st.Code() == codes.FailedPrecondition || st.Code() == codes.NotFound:
+			// The hold expired or was released before the network settled
+			return review(st.Message(), txn.GetId())
+		default:
+			return "", fmt.Errorf("failed to capture hold %s: %w", txn.GetHoldId(), err)
+		}
+	}
+
+	update := &transactionspb.UpdateTransactionRequest{Id: txn.GetId(), Status: txnSettled}
+	if rec.amount != txn.GetAmount() {
+		update.Amount = rec.amount
+	}
+	if _, err := c.transactionsClient.UpdateTransaction(ctx, update); err != nil {
+		return "", fmt.Errorf("failed to settle transaction %s: %w", txn.GetId(), err)
+	}
+
+	return outcomeSettled, nil
+}
+
+// sendForReview publishes rec to the review queue
+func (c *clearer) sendForReview(ctx context.Context, file string, rec record, reason, transactionID string) error {
+	event := &eventspb.ClearingRecordUnmatched{
+		File:             file,
+		Line:             int32(rec.line),
+		NetworkReference: rec.networkReference,
+		CardId:           rec.cardID,
+		AuthCode:         rec.authCode,
+		Amount:           rec.amount,
+		Currency:         rec.currency,
+		MerchantName:     rec.merchantName,
+		TransactionDate:  rec.transactionDate,
+		Reason:           reason,
+		TransactionId:    transactionID,
+		Timestamp:        time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, err := events.Publish(ctx, c.redisClient, events.StreamClearingReview, event); err != nil {
+		return fmt.Errorf("failed to send line %d for review: %w", rec.line, err)
+	}
+	fmt.Fprintf(c.out, "line %d (%s): sent for review: %s\n", rec.line, rec.networkReference, reason)
+	return nil
+}
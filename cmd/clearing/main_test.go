@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
+)
+
+const testCardID = "6f1c2a8e-3b7d-4c52-9a0e-1d2f3c4b5a69"
+
+// mockTransactionsClient only mocks the RPCs clearing calls
+type mockTransactionsClient struct {
+	mock.Mock
+	transactionspb.TransactionsClient
+}
+
+func (m *mockTransactionsClient) GetTransactionByAuthCode(ctx context.Context, in *transactionspb.AuthCodeQuery, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
+func (m *mockTransactionsClient) UpdateTransaction(ctx context.Context, in *transactionspb.UpdateTransactionRequest, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
+// mockBalanceClient only mocks the RPCs clearing calls
+type mockBalanceClient struct {
+	mock.Mock
+	balancepb.BalanceClient
+}
+
+func (m *mockBalanceClient) CaptureHold(ctx context.Context, in *balancepb.CaptureHoldRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func newTestClearer() (*clearer, *mockTransactionsClient, *mockBalanceClient, redismock.ClientMock) {
+	redisClient, mockRedis := redismock.NewClientMock()
+	mockTxn := new(mockTransactionsClient)
+	mockBalance := new(mockBalanceClient)
+	c := &clearer{
+		transactionsClient: mockTxn,
+		balanceClient:      mockBalance,
+		redisClient:        redisClient,
+		maxOverPercent:     20,
+		out:                &bytes.Buffer{},
+	}
+	return c, mockTxn, mockBalance, mockRedis
+}
+
+// expectReview expects a record to be published to the review queue for reason
+func expectReview(mockRedis redismock.ClientMock, line int32, reason string) {
+	mockRedis.CustomMatch(func(expected, actual []interface{}) error {
+		values := map[string]interface{}{}
+		for i := 3; i+1 < len(actual); i += 2 {
+			values[fmt.Sprint(actual[i])] = actual[i+1]
+		}
+		var event eventspb.ClearingRecordUnmatched
+		if actual[1] != events.StreamClearingReview || events.Decode(values, &event) != nil {
+			return fmt.Errorf("expected a %s event, got %v", events.StreamClearingReview, actual)
+		}
+		if event.Line != line || event.Reason != reason {
+			return fmt.Errorf("expected line %d sent for review: %s, got line %d: %s", line, reason, event.Line, event.Reason)
+		}
+		return nil
+	}).ExpectXAdd(&redis.XAddArgs{
+		Stream: events.StreamClearingReview,
+		// Only the number of fields is compared with the call; their values are checked above
+		Values: map[string]interface{}{"payload": "", "type": "", "schema_version": ""},
+	}).SetVal("1-0")
+}
+
+func TestIngest_SampleFile(t *testing.T) {
+	c, mockTxn, mockBalance, mockRedis := newTestClearer()
+
+	// Settled at the authorized amount
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, &transactionspb.AuthCodeQuery{CardId: testCardID, AuthCode: "K7Q2ZD"}).
+		Return(&transactionspb.Transaction{Id: "txn-1", Amount: 1250, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}, nil).Once()
+	mockBalance.On("CaptureHold", mock.Anything, &balancepb.CaptureHoldRequest{HoldId: "hold-1", Amount: 1250}).
+		Return(&balancepb.BalanceResponse{}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-1", Status: "SETTLED"}).
+		Return(&transactionspb.Transaction{Id: "txn-1", Status: "SETTLED"}, nil).Once()
+
+	// Settled with a tip on top of the authorized amount
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, &transactionspb.AuthCodeQuery{CardId: testCardID, AuthCode: "X91BTA"}).
+		Return(&transactionspb.Transaction{Id: "txn-2", Amount: 5000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-2"}, nil).Once()
+	mockBalance.On("CaptureHold", mock.Anything, &balancepb.CaptureHoldRequest{HoldId: "hold-2", Amount: 5750}).
+		Return(&balancepb.BalanceResponse{}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-2", Status: "SETTLED", Amount: 5750}).
+		Return(&transactionspb.Transaction{Id: "txn-2", Status: "SETTLED"}, nil).Once()
+
+	// The offline transaction has no authorization to match
+	expectReview(mockRedis, 6, "no auth code, force-post or offline transaction")
+
+	f, err := os.Open("testdata/settlement.csv")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	sum, err := c.ingest(context.Background(), "settlement.csv", f)
+
+	assert.NoError(t, err)
+	assert.Equal(t, summary{settled: 2, review: 1}, sum)
+	mockTxn.AssertExpectations(t)
+	mockBalance.AssertExpectations(t)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestIngest_Repeated(t *testing.T) {
+	c, mockTxn, mockBalance, _ := newTestClearer()
+
+	input := `network_reference,card_id,auth_code,amount,currency,merchant_name,transaction_date
+NR0001,card-1,AAAAAA,1000,GBP,SHOP,2026-10-14
+NR0002,card-1,BBBBBB,2000,GBP,SHOP,2026-10-14
+`
+	// Settled by an earlier ingestion
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, &transactionspb.AuthCodeQuery{CardId: "card-1", AuthCode: "AAAAAA"}).
+		Return(&transactionspb.Transaction{Id: "txn-1", Amount: 1000, Currency: "GBP", Status: "SETTLED", HoldId: "hold-1"}, nil).Once()
+
+	// Interrupted after the hold was captured, before the transaction was marked SETTLED
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, &transactionspb.AuthCodeQuery{CardId: "card-1", AuthCode: "BBBBBB"}).
+		Return(&transactionspb.Transaction{Id: "txn-2", Amount: 2000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-2"}, nil).Once()
+	mockBalance.On("CaptureHold", mock.Anything, &balancepb.CaptureHoldRequest{HoldId: "hold-2", Amount: 2000}).
+		Return(nil, status.Error(codes.FailedPrecondition, "hold is CAPTURED")).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-2", Status: "SETTLED"}).
+		Return(&transactionspb.Transaction{Id: "txn-2", Status: "SETTLED"}, nil).Once()
+
+	sum, err := c.ingest(context.Background(), "settlement.csv", strings.NewReader(input))
+
+	assert.NoError(t, err)
+	assert.Equal(t, summary{settled: 1, alreadySettled: 1}, sum)
+	mockTxn.AssertExpectations(t)
+	mockBalance.AssertExpectations(t)
+}
+
+func TestSettle_SentForReview(t *testing.T) {
+	rec := record{line: 2, networkReference: "NR0001", cardID: "card-1", authCode: "AAAAAA", amount: 1300, currency: "GBP", transactionDate: "2026-10-14"}
+	authorized := func(amount int64) *transactionspb.Transaction {
+		return &transactionspb.Transaction{Id: "txn-1", Amount: amount, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}
+	}
+
+	tests := []struct {
+		name    string
+		txn     *transactionspb.Transaction
+		findErr error
+		capture error
+		reason  string
+	}{
+		{name: "unknown auth code", findErr: status.Error(codes.NotFound, "transaction not found"), reason: "no authorization with this card and auth code"},
+		{name: "reversed", txn: &transactionspb.Transaction{Id: "txn-1", Amount: 1300, Currency: "GBP", Status: "REVERSED"}, reason: "transaction is REVERSED"},
+		{name: "too far over", txn: authorized(1000), reason: "amount 1300 is more than 20% over authorized 1000"},
+		{name: "hold expired", txn: authorized(1200), capture: status.Error(codes.FailedPrecondition, "hold is EXPIRED"), reason: "hold is EXPIRED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mockTxn, mockBalance, mockRedis := newTestClearer()
+			if tt.findErr != nil {
+				mockTxn.On("GetTransactionByAuthCode", mock.Anything, mock.Anything).Return(nil, tt.findErr).Once()
+			} else {
+				mockTxn.On("GetTransactionByAuthCode", mock.Anything, mock.Anything).Return(tt.txn, nil).Once()
+			}
+			if tt.capture != nil {
+				mockBalance.On("CaptureHold", mock.Anything, &balancepb.CaptureHoldRequest{HoldId: "hold-1", Amount: rec.amount}).Return(nil, tt.capture).Once()
+			}
+			expectReview(mockRedis, 2, tt.reason)
+
+			outcome, err := c.settle(context.Background(), "settlement.csv", rec)
+
+			assert.NoError(t, err)
+			assert.Equal(t, outcomeReview, outcome)
+			mockTxn.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything)
+			mockBalance.AssertExpectations(t)
+			assert.NoError(t, mockRedis.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSettle_UnavailableStopsIngestion(t *testing.T) {
+	c, mockTxn, _, _ := newTestClearer()
+
+	input := `network_reference,card_id,auth_code,amount,currency,merchant_name,transaction_date
+NR0001,card-1,AAAAAA,1000,GBP,SHOP,2026-10-14
+`
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, mock.Anything).
+		Return(nil, status.Error(codes.Unavailable, "connection refused")).Once()
+
+	_, err := c.ingest(context.Background(), "settlement.csv", strings.NewReader(input))
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestIngest_MalformedRecords(t *testing.T) {
+	c, _, _, mockRedis := newTestClearer()
+
+	input := `card_id,network_reference,auth_code,amount,currency,merchant_name,transaction_date
+card-1,NR0001,AAAAAA,ten pounds,GBP,SHOP,2026-10-14
+card-1,NR0002,BBBBBB,1000,GBP,SHOP
+`
+	expectReview(mockRedis, 2, `malformed record: invalid amount "ten pounds"`)
+	expectReview(mockRedis, 3, "malformed record: expected 7 fields, got 6")
+
+	sum, err := c.ingest(context.Background(), "settlement.csv", strings.NewReader(input))
+
+	assert.NoError(t, err)
+	assert.Equal(t, summary{review: 2}, sum)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+
+	_, err = c.ingest(context.Background(), "settlement.csv", strings.NewReader("card_id,amount\n"))
+	assert.EqualError(t, err, "header has no network_reference column")
+}
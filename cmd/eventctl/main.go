@@ -0,0 +1,586 @@
+// Command eventctl inspects and re-drives the Redis streams the services exchange events over.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	_ "github.com/manifoldfinance/disco2/v2/pkg/pb/events" // Register event types for decoding
+	"github.com/manifoldfinance/disco2/v2/pkg/streamconsumer"
+)
+
+const usage = `Usage: eventctl [-redis addr] <command> [flags] <stream>
+
+Commands:
+  groups <stream>                          list consumer groups with their pending and lag counts
+  pending -group G [-count N] <stream>     list messages delivered to group G but not acknowledged
+  tail [-from ID|TIME] [-count N] [-follow] <stream>
+                                           print messages, oldest first
+  dlq [-count N] <stream>                  list messages dead-lettered from <stream>
+  requeue [-all] <stream> [DLQ-ID ...]     move dead-lettered messages back onto <stream>
+  reset -group G -to ID|TIME|0|$ <stream>  make group G redeliver messages from ID or TIME onwards
+                                           (0 redelivers everything, $ skips to the end)
+  replay -group G -from ID|TIME [-to ID|TIME] <stream>
+                                           create group G holding the messages in the range, for backfills
+
+IDs are stream IDs such as 1760605200000-0; TIMEs are RFC 3339, such as 2025-10-16T09:00:00Z.
+
+Streams and groups in use:
+  transaction:created   enrichment-consumer-group, feed-generator-consumer-group
+  feed:item.created     apns-consumer-group
+  balance:updated, card:created, card:status_changed, merchant:updated
+  clearing:review       settlement records clearing could not settle, for operations to review
+`
+
+// replayConsumer owns the messages replay queues for a new group until its consumers reclaim them
+const replayConsumer = "eventctl-replay"
+
+// eventJSON prints events with the same keys as legacy JSON payloads
+var eventJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+type cli struct {
+	redisClient *redis.Client
+	out         io.Writer
+}
+
+func main() {
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr: *redisAddr,
+		DB:   0,
+	})
+	defer rdb.Close()
+
+	// Stop following a stream on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	c := &cli{redisClient: rdb, out: os.Stdout}
+	if err := c.run(ctx, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "eventctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "groups":
+		return c.groups(ctx, args)
+	case "pending":
+		return c.pending(ctx, args)
+	case "tail":
+		return c.tail(ctx, args)
+	case "dlq":
+		return c.dlq(ctx, args)
+	case "requeue":
+		return c.requeue(ctx, args)
+	case "reset":
+		return c.reset(ctx, args)
+	case "replay":
+		return c.replay(ctx, args)
+	default:
+		return fmt.Errorf("unknown command %q, run eventctl -h for usage", command)
+	}
+}
+
+// parseArgs parses a command's flags and returns its stream and any further arguments
+func parseArgs(fs *flag.FlagSet, args []string) (string, []string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", nil, err
+	}
+	if fs.NArg() < 1 {
+		return "", nil, fmt.Errorf("%s: missing stream name", fs.Name())
+	}
+	return fs.Arg(0), fs.Args()[1:], nil
+}
+
+// groups lists the consumer groups of a stream. XINFO GROUPS is sent raw because Redis 7
+// adds fields to the reply that the client's typed XInfoGroups rejects.
+func (c *cli) groups(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("groups", flag.ContinueOnError)
+	stream, _, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	reply, err := c.redisClient.Do(ctx, "XINFO", "GROUPS", stream).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list groups of %s: %w", stream, err)
+	}
+	groups, _ := reply.([]interface{})
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tCONSUMERS\tPENDING\tLAST DELIVERED\tLAG")
+	for _, g := range groups {
+		kv, _ := g.([]interface{})
+		info := map[string]interface{}{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			if k, ok := kv[i].(string); ok {
+				info[k] = kv[i+1]
+			}
+		}
+		lag := "-" // Not reported before Redis 7
+		if v, ok := info["lag"]; ok && v != nil {
+			lag = fmt.Sprint(v)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%s\n", info["name"], info["consumers"], info["pending"], info["last-delivered-id"], lag)
+	}
+	return w.Flush()
+}
+
+// pending lists the messages delivered to a group that have not been acknowledged
+func (c *cli) pending(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pending", flag.ContinueOnError)
+	group := fs.String("group", "", "consumer group")
+	count := fs.Int64("count", 100, "maximum number of entries to list")
+	stream, _, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *group == "" {
+		return errors.New("pending: -group is required")
+	}
+
+	entries, err := c.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  *group,
+		Start:  "-",
+		End:    "+",
+		Count:  *count,
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list pending messages of %s: %w", *group, err)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCONSUMER\tIDLE\tDELIVERIES")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", e.ID, e.Consumer, e.Idle.Round(time.Second), e.RetryCount)
+	}
+	return w.Flush()
+}
+
+// tail prints the latest messages of a stream, or those from a given ID or time, and optionally follows it
+func (c *cli) tail(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	from := fs.String("from", "", "print messages from this ID or time instead of the latest ones")
+	count := fs.Int64("count", 10, "number of messages to print")
+	follow := fs.Bool("follow", false, "keep printing new messages until interrupted")
+	stream, _, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var msgs []redis.XMessage
+	if *from != "" {
+		start, err := resolveID(*from, false)
+		if err != nil {
+			return err
+		}
+		msgs, err = c.redisClient.XRangeN(ctx, stream, start.String(), "+", *count).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", stream, err)
+		}
+	} else {
+		msgs, err = c.redisClient.XRevRangeN(ctx, stream, "+", "-", *count).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", stream, err)
+		}
+		for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+			msgs[i], msgs[j] = msgs[j], msgs[i]
+		}
+	}
+
+	last := "$"
+	for _, msg := range msgs {
+		c.printMessage(msg)
+		last = msg.ID
+	}
+	if !*follow {
+		return nil
+	}
+
+	for ctx.Err() == nil {
+		streams, err := c.redisClient.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{stream, last},
+			Count:   100,
+			Block:   5 * time.Second,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("failed to read %s: %w", stream, err)
+		}
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				c.printMessage(msg)
+				last = msg.ID
+			}
+		}
+	}
+	return nil
+}
+
+// dlq lists the messages dead-lettered from a stream
+func (c *cli) dlq(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dlq", flag.ContinueOnError)
+	count := fs.Int64("count", 100, "maximum number of messages to list")
+	stream, _, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := c.redisClient.XRangeN(ctx, streamconsumer.DeadLetterStream(stream), "-", "+", *count).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read dead-letter stream of %s: %w", stream, err)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DLQ ID\tORIGINAL ID\tGROUP\tATTEMPTS\tERROR\tEVENT")
+	for _, msg := range msgs {
+		fmt.Fprintf(w, "%s\t%v\t%v\t%v\t%v\t%s\n", msg.ID,
+			msg.Values[streamconsumer.FieldOriginalID],
+			msg.Values[streamconsumer.FieldGroup],
+			msg.Values[streamconsumer.FieldAttempts],
+			msg.Values[streamconsumer.FieldError],
+			describe(originalValues(msg.Values)))
+	}
+	return w.Flush()
+}
+
+// requeue adds dead-lettered messages back onto their stream and removes them from the dead-letter
+// stream. Every group reading the stream receives them again; consumers already tolerate duplicates.
+func (c *cli) requeue(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("requeue", flag.ContinueOnError)
+	all := fs.Bool("all", false, "requeue every dead-lettered message")
+	stream, ids, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if !*all && len(ids) == 0 {
+		return errors.New("requeue: pass dead-letter IDs or -all")
+	}
+
+	dlq := streamconsumer.DeadLetterStream(stream)
+	var msgs []redis.XMessage
+	if *all {
+		msgs, err = c.redisClient.XRange(ctx, dlq, "-", "+").Result()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", dlq, err)
+		}
+	} else {
+		for _, id := range ids {
+			found, err := c.redisClient.XRangeN(ctx, dlq, id, id, 1).Result()
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", dlq, err)
+			}
+			if len(found) == 0 {
+				return fmt.Errorf("message %s not found in %s", id, dlq)
+			}
+			msgs = append(msgs, found[0])
+		}
+	}
+
+	for _, msg := range msgs {
+		newID, err := c.redisClient.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			Values: sortedFields(originalValues(msg.Values)),
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("failed to requeue %s: %w", msg.ID, err)
+		}
+		if err := c.redisClient.XDel(ctx, dlq, msg.ID).Err(); err != nil {
+			return fmt.Errorf("requeued %s as %s but failed to remove it from %s: %w", msg.ID, newID, dlq, err)
+		}
+		fmt.Fprintf(c.out, "Requeued %s as %s\n", msg.ID, newID)
+	}
+	return nil
+}
+
+// reset moves a group's last delivered ID so it redelivers messages from a point onwards
+func (c *cli) reset(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	group := fs.String("group", "", "consumer group")
+	to := fs.String("to", "", "first message to redeliver, as an ID or time; 0 for everything, $ for nothing")
+	stream, _, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *group == "" || *to == "" {
+		return errors.New("reset: -group and -to are required")
+	}
+
+	lastDelivered := *to
+	if *to != "0" && *to != "$" {
+		first, err := resolveID(*to, false)
+		if err != nil {
+			return err
+		}
+		lastDelivered = first.before().String()
+	}
+
+	if err := c.redisClient.XGroupSetID(ctx, stream, *group, lastDelivered).Err(); err != nil {
+		return fmt.Errorf("failed to reset %s: %w", *group, err)
+	}
+	fmt.Fprintf(c.out, "Group %s on %s will next receive messages after %s\n", *group, stream, lastDelivered)
+	return nil
+}
+
+// replay creates a new group whose pending list holds the messages in a range. They are owned by
+// a placeholder consumer, so the group's real consumers reclaim and process them, while messages
+// outside the range are never delivered to the group.
+func (c *cli) replay(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	group := fs.String("group", "", "name of the new consumer group")
+	from := fs.String("from", "", "first message to replay, as an ID or time")
+	to := fs.String("to", "", "last message to replay, as an ID or time (default: the latest message)")
+	stream, _, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *group == "" || *from == "" {
+		return errors.New("replay: -group and -from are required")
+	}
+
+	start, err := resolveID(*from, false)
+	if err != nil {
+		return err
+	}
+	var end *streamID
+	if *to != "" {
+		id, err := resolveID(*to, true)
+		if err != nil {
+			return err
+		}
+		end = &id
+	}
+
+	// Position the group just before the range; creating it fails if the group already exists
+	if err := c.redisClient.XGroupCreate(ctx, stream, *group, start.before().String()).Err(); err != nil {
+		return fmt.Errorf("failed to create group %s: %w", *group, err)
+	}
+
+	queued := 0
+	for done := false; !done; {
+		streams, err := c.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    *group,
+			Consumer: replayConsumer,
+			Streams:  []string{stream, ">"},
+			Count:    100,
+			Block:    -1, // Don't wait for new messages
+		}).Result()
+		if err == redis.Nil {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s into %s: %w", stream, *group, err)
+		}
+
+		done = true
+		var beyond []string
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				done = false
+				id, err := parseStreamID(msg.ID)
+				if err != nil {
+					return err
+				}
+				if end != nil && id.compare(*end) > 0 {
+					beyond = append(beyond, msg.ID)
+					done = true
+					continue
+				}
+				queued++
+			}
+		}
+		if len(beyond) > 0 {
+			// Past the range: acknowledge rather than replay
+			if err := c.redisClient.XAck(ctx, stream, *group, beyond...).Err(); err != nil {
+				return fmt.Errorf("failed to skip messages after the range: %w", err)
+			}
+		}
+	}
+
+	// Skip everything after the range, including messages added while replaying
+	if err := c.redisClient.XGroupSetID(ctx, stream, *group, "$").Err(); err != nil {
+		return fmt.Errorf("failed to move %s past the range: %w", *group, err)
+	}
+
+	fmt.Fprintf(c.out, "Created group %s on %s with %d messages pending; consumers in the group reclaim them from %s once idle\n",
+		*group, stream, queued, replayConsumer)
+	return nil
+}
+
+func (c *cli) printMessage(msg redis.XMessage) {
+	var extra []string
+	for _, k := range sortedKeys(msg.Values) {
+		switch k {
+		case "payload", "type", "schema_version":
+		default:
+			extra = append(extra, fmt.Sprintf("%s=%v", k, msg.Values[k]))
+		}
+	}
+
+	line := fmt.Sprintf("%s  %s  %s", msg.ID, idTime(msg.ID), describe(msg.Values))
+	if len(extra) > 0 {
+		line += "  " + strings.Join(extra, " ")
+	}
+	fmt.Fprintln(c.out, line)
+}
+
+// describe renders a message's event as JSON when its type is known, and its raw payload otherwise
+func describe(values map[string]interface{}) string {
+	if typeName, _ := values["type"].(string); typeName != "" {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(typeName))
+		if err == nil {
+			event := mt.New().Interface()
+			if err := events.Decode(values, event); err == nil {
+				if b, err := eventJSON.Marshal(event); err == nil {
+					return typeName + " " + string(b)
+				}
+			}
+		}
+	}
+	payload, _ := values["payload"].(string)
+	return payload
+}
+
+// originalValues returns a dead-lettered message's fields without those added by the dead-letter queue
+func originalValues(values map[string]interface{}) map[string]interface{} {
+	original := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if !strings.HasPrefix(k, "dlq_") {
+			original[k] = v
+		}
+	}
+	return original
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedFields flattens values into XADD arguments in a stable order
+func sortedFields(values map[string]interface{}) []interface{} {
+	fields := make([]interface{}, 0, 2*len(values))
+	for _, k := range sortedKeys(values) {
+		fields = append(fields, k, values[k])
+	}
+	return fields
+}
+
+// streamID is a parsed Redis stream ID: a millisecond timestamp and a sequence number
+type streamID struct {
+	ms  uint64
+	seq uint64
+}
+
+func parseStreamID(s string) (streamID, error) {
+	msPart, seqPart, hasSeq := strings.Cut(s, "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return streamID{}, fmt.Errorf("invalid stream ID %q", s)
+	}
+	id := streamID{ms: ms}
+	if hasSeq {
+		if id.seq, err = strconv.ParseUint(seqPart, 10, 64); err != nil {
+			return streamID{}, fmt.Errorf("invalid stream ID %q", s)
+		}
+	}
+	return id, nil
+}
+
+// resolveID parses a stream ID or an RFC 3339 time. A time stands for the first ID in its
+// millisecond, or the last one when last is set.
+func resolveID(s string, last bool) (streamID, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		id := streamID{ms: uint64(t.UnixMilli())}
+		if last {
+			id.seq = ^uint64(0)
+		}
+		return id, nil
+	}
+	id, err := parseStreamID(s)
+	if err != nil {
+		return streamID{}, fmt.Errorf("%q is neither a stream ID nor an RFC 3339 time", s)
+	}
+	if last && !strings.Contains(s, "-") {
+		id.seq = ^uint64(0)
+	}
+	return id, nil
+}
+
+func (id streamID) String() string {
+	return fmt.Sprintf("%d-%d", id.ms, id.seq)
+}
+
+// before returns the ID immediately preceding id
+func (id streamID) before() streamID {
+	switch {
+	case id.seq > 0:
+		return streamID{ms: id.ms, seq: id.seq - 1}
+	case id.ms > 0:
+		return streamID{ms: id.ms - 1, seq: ^uint64(0)}
+	default:
+		return id
+	}
+}
+
+func (id streamID) compare(other streamID) int {
+	switch {
+	case id.ms != other.ms:
+		if id.ms < other.ms {
+			return -1
+		}
+		return 1
+	case id.seq != other.seq:
+		if id.seq < other.seq {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+// idTime returns the time a message ID was generated at
+func idTime(s string) string {
+	id, err := parseStreamID(s)
+	if err != nil {
+		return "-"
+	}
+	return time.UnixMilli(int64(id.ms)).UTC().Format(time.RFC3339)
+}
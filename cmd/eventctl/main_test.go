@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+)
+
+func newTestCLI() (*cli, redismock.ClientMock, *bytes.Buffer) {
+	redisClient, mockRedis := redismock.NewClientMock()
+	out := &bytes.Buffer{}
+	return &cli{redisClient: redisClient, out: out}, mockRedis, out
+}
+
+func TestResolveID(t *testing.T) {
+	tests := []struct {
+		in     string
+		last   bool
+		want   string
+		before string
+	}{
+		{in: "1760605200000-3", want: "1760605200000-3", before: "1760605200000-2"},
+		{in: "1760605200000", want: "1760605200000-0", before: "1760605199999-18446744073709551615"},
+		{in: "1760605200000", last: true, want: "1760605200000-18446744073709551615", before: "1760605200000-18446744073709551614"},
+		{in: "2025-10-16T09:00:00Z", want: "1760605200000-0", before: "1760605199999-18446744073709551615"},
+		{in: "0-0", want: "0-0", before: "0-0"},
+	}
+	for _, tt := range tests {
+		id, err := resolveID(tt.in, tt.last)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, id.String(), tt.in)
+		assert.Equal(t, tt.before, id.before().String(), tt.in)
+	}
+
+	_, err := resolveID("yesterday", false)
+	assert.Error(t, err)
+}
+
+func TestGroups(t *testing.T) {
+	c, mockRedis, out := newTestCLI()
+	mockRedis.ExpectDo("XINFO", "GROUPS", "transaction:created").SetVal([]interface{}{
+		[]interface{}{"name", "enrichment-consumer-group", "consumers", int64(2), "pending", int64(1),
+			"last-delivered-id", "5-0", "entries-read", int64(5), "lag", int64(0)},
+		[]interface{}{"name", "feed-generator-consumer-group", "consumers", int64(1), "pending", int64(0),
+			"last-delivered-id", "3-0", "entries-read", nil, "lag", nil},
+	})
+
+	err := c.run(context.Background(), "groups", []string{"transaction:created"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "enrichment-consumer-group      2          1        5-0             0")
+	assert.Contains(t, out.String(), "feed-generator-consumer-group  1          0        3-0             -")
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestRequeue(t *testing.T) {
+	c, mockRedis, out := newTestCLI()
+	payload, _, err := events.Encode(&eventspb.TransactionCreated{Id: "txn-123"})
+	assert.NoError(t, err)
+
+	mockRedis.ExpectXRangeN("transaction:created:dlq", "9-0", "9-0", 1).SetVal([]redis.XMessage{{
+		ID: "9-0",
+		Values: map[string]interface{}{
+			"payload": payload, "schema_version": "1", "type": "TransactionCreated",
+			"dlq_original_id": "1-0", "dlq_group": "enrichment-consumer-group", "dlq_error": "merchant service unavailable", "dlq_attempts": "5",
+		},
+	}})
+	mockRedis.ExpectXAdd(&redis.XAddArgs{
+		Stream: "transaction:created",
+		Values: []interface{}{"payload", payload, "schema_version", "1", "type", "TransactionCreated"},
+	}).SetVal("12-0")
+	mockRedis.ExpectXDel("transaction:created:dlq", "9-0").SetVal(1)
+
+	err = c.run(context.Background(), "requeue", []string{"transaction:created", "9-0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Requeued 9-0 as 12-0\n", out.String())
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+
+	err = c.run(context.Background(), "requeue", []string{"transaction:created"})
+	assert.EqualError(t, err, "requeue: pass dead-letter IDs or -all")
+}
+
+func TestReset(t *testing.T) {
+	c, mockRedis, _ := newTestCLI()
+	mockRedis.ExpectXGroupSetID("transaction:created", "feed-generator-consumer-group", "1760605199999-18446744073709551615").SetVal("OK")
+	mockRedis.ExpectXGroupSetID("transaction:created", "feed-generator-consumer-group", "0").SetVal("OK")
+
+	ctx := context.Background()
+	assert.NoError(t, c.run(ctx, "reset", []string{"-group", "feed-generator-consumer-group", "-to", "2025-10-16T09:00:00Z", "transaction:created"}))
+	assert.NoError(t, c.run(ctx, "reset", []string{"-group", "feed-generator-consumer-group", "-to", "0", "transaction:created"}))
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestReplay(t *testing.T) {
+	c, mockRedis, out := newTestCLI()
+	readArgs := &redis.XReadGroupArgs{
+		Group:    "enrichment-backfill",
+		Consumer: replayConsumer,
+		Streams:  []string{"transaction:created", ">"},
+		Count:    100,
+		Block:    -1,
+	}
+	mockRedis.ExpectXGroupCreate("transaction:created", "enrichment-backfill", "1-18446744073709551615").SetVal("OK")
+	mockRedis.ExpectXReadGroup(readArgs).SetVal([]redis.XStream{{
+		Stream:   "transaction:created",
+		Messages: []redis.XMessage{{ID: "2-0"}, {ID: "3-0"}, {ID: "3-1"}, {ID: "4-0"}},
+	}})
+	mockRedis.ExpectXAck("transaction:created", "enrichment-backfill", "4-0").SetVal(1)
+	mockRedis.ExpectXGroupSetID("transaction:created", "enrichment-backfill", "$").SetVal("OK")
+
+	err := c.run(context.Background(), "replay", []string{"-group", "enrichment-backfill", "-from", "2", "-to", "3", "transaction:created"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "with 3 messages pending")
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestDescribe(t *testing.T) {
+	payload, headers, err := events.Encode(&eventspb.CardCreated{CardId: "card-123", UserId: "user-123", Status: "ACTIVE"})
+	assert.NoError(t, err)
+	values := map[string]interface{}{"payload": payload}
+	for k, v := range headers {
+		values[k] = v
+	}
+
+	described := describe(values)
+	assert.Contains(t, described, "CardCreated {")
+	assert.Contains(t, described, `"card_id":`)
+	assert.Contains(t, described, `"card-123"`)
+	// Legacy messages have no type, so their payload is shown as is
+	assert.Equal(t, `{"card_id": "card-123"}`, describe(map[string]interface{}{"payload": `{"card_id": "card-123"}`}))
+}
@@ -2,17 +2,21 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
-	"time"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	feedpb "github.com/manifoldfinance/disco2/v2/feed"
+	"github.com/manifoldfinance/disco2/v2/pkg/declines"
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	"github.com/manifoldfinance/disco2/v2/pkg/streamconsumer"
 	transactionspb "github.com/manifoldfinance/disco2/v2/transactions"
 )
 
@@ -58,85 +62,88 @@ func main() {
 		feedClient:         feedClient,
 	}
 
-	// Start Redis event consumer
-	go s.startEventConsumer(ctx)
+	// Stop consuming on SIGINT/SIGTERM, finishing events already being processed
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Keep main goroutine alive
-	select {}
+	// Run Redis event consumer until shutdown
+	s.startEventConsumer(ctx)
+	log.Println("Event consumer stopped")
 }
 
 func (s *server) startEventConsumer(ctx context.Context) {
-	log.Println("Starting Redis event consumer...")
+	log.Println("Starting Redis event consumers...")
 
-	consumerGroup := "feed-generator-consumer-group"
-	streamName := "transaction:created"
+	var wg sync.WaitGroup
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		err := events.Subscribe(ctx, s.redisClient, events.StreamTransactionCreated, "feed-generator-consumer-group",
+			func(ctx context.Context, event *eventspb.TransactionCreated) error {
+				log.Printf("Processing transaction created event for transaction ID: %s", event.GetId())
 
-	// Create consumer group if it doesn't exist
-	if _, err := s.redisClient.XGroupCreateMkStream(ctx, streamName, consumerGroup, "0").Result(); err != nil {
-		// Ignore BUSYGROUP error if group already exists
-		if !strings.Contains(err.Error(), "BUSYGROUP") {
-			log.Fatalf("failed to create Redis consumer group %s: %v", consumerGroup, err)
+				// Attempt to generate feed item for the transaction
+				return s.generateFeedItemForTransaction(ctx, event.GetId())
+			},
+			streamconsumer.WithConsumerName(streamconsumer.ConsumerName("feed-generator")),
+		)
+		if err != nil && ctx.Err() == nil {
+			log.Fatalf("failed to consume %s events: %v", events.StreamTransactionCreated, err)
 		}
-	}
-	log.Printf("Redis consumer group '%s' created or already exists", consumerGroup)
-
-	for {
-		// Read messages from the stream using the consumer group
-		messages, err := s.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
-			Group:    consumerGroup,
-			Consumer: "feed-generator-instance-1",
-			Streams:  []string{streamName, ">"},
-			Count:    10,
-			Block:    0,
-			NoAck:    false,
-		}).Result()
-		if err != nil {
-			log.Printf("error reading from Redis stream %s: %v", streamName, err)
-			time.Sleep(time.Second) // Wait before retrying
-			continue
+	}()
+	go func() {
+		defer wg.Done()
+		err := events.Subscribe(ctx, s.redisClient, events.StreamPotMoved, "feed-generator-consumer-group",
+			func(ctx context.Context, event *eventspb.PotMoved) error {
+				log.Printf("Processing pot moved event for move ID: %s", event.GetMoveId())
+				return s.generateFeedItemForPotMove(ctx, event)
+			},
+			streamconsumer.WithConsumerName(streamconsumer.ConsumerName("feed-generator")),
+		)
+		if err != nil && ctx.Err() == nil {
+			log.Fatalf("failed to consume %s events: %v", events.StreamPotMoved, err)
 		}
-
-		for _, stream := range messages {
-			for _, message := range stream.Messages {
-				log.Printf("Received message %s from stream %s", message.ID, stream.Stream)
-
-				// Process the message
-				payload, ok := message.Values["payload"].(string)
-				if !ok {
-					log.Printf("message %s has no 'payload' field or it's not a string", message.ID)
-					// Acknowledge the message to prevent reprocessing
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				var event struct {
-					Id string `json:"id"`
-				}
-				if err := json.Unmarshal([]byte(payload), &event); err != nil {
-					log.Printf("failed to unmarshal event payload for message %s: %v", message.ID, err)
-					// Acknowledge the message to prevent reprocessing
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				log.Printf("Processing transaction created event for transaction ID: %s", event.Id)
-
-				// Attempt to generate feed item for the transaction
-				if err := s.generateFeedItemForTransaction(ctx, event.Id); err != nil {
-					log.Printf("failed to generate feed item for transaction %s: %v", event.Id, err)
-					// Do NOT acknowledge the message, it will be retried later
-					continue
-				}
-
-				// Acknowledge the message after successful processing
-				if _, err := s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID).Result(); err != nil {
-					log.Printf("failed to acknowledge message %s: %v", message.ID, err)
-				} else {
-					log.Printf("Acknowledged message %s", message.ID)
-				}
-			}
+	}()
+	go func() {
+		defer wg.Done()
+		err := events.Subscribe(ctx, s.redisClient, events.StreamScheduleRun, "feed-generator-consumer-group",
+			func(ctx context.Context, event *eventspb.ScheduledPaymentRun) error {
+				log.Printf("Processing schedule run event for run ID: %s", event.GetRunId())
+				return s.generateFeedItemForScheduleRun(ctx, event)
+			},
+			streamconsumer.WithConsumerName(streamconsumer.ConsumerName("feed-generator")),
+		)
+		if err != nil && ctx.Err() == nil {
+			log.Fatalf("failed to consume %s events: %v", events.StreamScheduleRun, err)
 		}
-	}
+	}()
+	go func() {
+		defer wg.Done()
+		err := events.Subscribe(ctx, s.redisClient, events.StreamCardAuthDeclined, "feed-generator-consumer-group",
+			func(ctx context.Context, event *eventspb.CardAuthDeclined) error {
+				log.Printf("Processing card auth declined event for card ID: %s", event.GetCardId())
+				return s.generateFeedItemForDecline(ctx, event)
+			},
+			streamconsumer.WithConsumerName(streamconsumer.ConsumerName("feed-generator")),
+		)
+		if err != nil && ctx.Err() == nil {
+			log.Fatalf("failed to consume %s events: %v", events.StreamCardAuthDeclined, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		err := events.Subscribe(ctx, s.redisClient, events.StreamCardPinLocked, "feed-generator-consumer-group",
+			func(ctx context.Context, event *eventspb.CardPinLocked) error {
+				log.Printf("Processing card PIN locked event for card ID: %s", event.GetCardId())
+				return s.generateFeedItemForPinLock(ctx, event)
+			},
+			streamconsumer.WithConsumerName(streamconsumer.ConsumerName("feed-generator")),
+		)
+		if err != nil && ctx.Err() == nil {
+			log.Fatalf("failed to consume %s events: %v", events.StreamCardPinLocked, err)
+		}
+	}()
+	wg.Wait()
 }
 
 func (s *server) generateFeedItemForTransaction(ctx context.Context, transactionID string) error {
@@ -161,13 +168,19 @@ func (s *server) generateFeedItemForTransaction(ctx context.Context, transaction
 	}
 
 	// Format amount (assuming cents)
+	feedItemType := "TRANSACTION"
 	amount := float64(txn.GetAmount()) / 100.0
 	content := fmt.Sprintf("Spent %.2f %s at %s", amount, txn.GetCurrency(), merchantName)
+	if txn.GetOriginalTransactionId() != "" {
+		// Refunds are recorded with a negative amount, e.g. "Refund of £4.00 from Test Shop"
+		feedItemType = "REFUND"
+		content = fmt.Sprintf("Refund of %s from %s", formatAmount(-txn.GetAmount(), txn.GetCurrency()), merchantName)
+	}
 
 	// 3. Add feed item to Feed service
 	addFeedItemReq := &feedpb.AddFeedItemRequest{
 		AccountId: txn.GetAccountId(),
-		Type:      "TRANSACTION",
+		Type:      feedItemType,
 		Content:   content,
 		RefId:     transactionID,
 		Timestamp: txn.GetTimestamp(),
@@ -181,23 +194,154 @@ func (s *server) generateFeedItemForTransaction(ctx context.Context, transaction
 	log.Printf("Generated and added feed item %s for transaction %s", feedItem.GetId(), transactionID)
 
 	// 4. Publish "feed.item.created" event to Redis
-	eventPayload := fmt.Sprintf(`{"feed_item_id": "%s", "account_id": "%s", "type": "%s", "transaction_id": "%s"}`,
-		feedItem.GetId(),
-		feedItem.GetAccountId(),
-		feedItem.GetType(),
-		transactionID,
-	)
-	if _, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
-		Stream: "feed:item.created",
-		MaxLen: 0, // No limit
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
-	}).Result(); err != nil {
+	s.publishFeedItemCreated(ctx, feedItem, transactionID)
+
+	return nil // Successfully generated and added feed item
+}
+
+// generateFeedItemForPotMove adds a feed item for money moved in or out of a pot,
+// e.g. "Moved £25.00 to Holiday"
+func (s *server) generateFeedItemForPotMove(ctx context.Context, event *eventspb.PotMoved) error {
+	content := fmt.Sprintf("Moved %s to %s", formatAmount(event.GetAmount(), event.GetCurrency()), event.GetPotName())
+	if event.GetDirection() == "WITHDRAWAL" {
+		content = fmt.Sprintf("Moved %s from %s", formatAmount(event.GetAmount(), event.GetCurrency()), event.GetPotName())
+	}
+
+	addFeedItemReq := &feedpb.AddFeedItemRequest{
+		AccountId: event.GetAccountId(),
+		Type:      "POT",
+		Content:   content,
+		RefId:     event.GetMoveId(),
+		Timestamp: event.GetTimestamp(),
+	}
+	feedItem, err := s.feedClient.AddFeedItem(ctx, addFeedItemReq)
+	if err != nil {
+		log.Printf("failed to add feed item for pot move %s: %v", event.GetMoveId(), err)
+		return fmt.Errorf("failed to add feed item: %w", err)
+	}
+
+	log.Printf("Generated and added feed item %s for pot move %s", feedItem.GetId(), event.GetMoveId())
+
+	s.publishFeedItemCreated(ctx, feedItem, "")
+
+	return nil
+}
+
+// generateFeedItemForScheduleRun adds a feed item for a scheduled payment that was paid or failed,
+// e.g. "Scheduled payment of £500.00 to Landlord failed: insufficient funds"
+func (s *server) generateFeedItemForScheduleRun(ctx context.Context, event *eventspb.ScheduledPaymentRun) error {
+	content := fmt.Sprintf("Scheduled payment of %s to %s", formatAmount(event.GetAmount(), event.GetCurrency()), event.GetPayee())
+	if event.GetStatus() == "PAID" {
+		content += " paid"
+	} else {
+		content += " failed: " + event.GetDeclineReason()
+	}
+
+	addFeedItemReq := &feedpb.AddFeedItemRequest{
+		AccountId: event.GetAccountId(),
+		Type:      "SCHEDULED_PAYMENT",
+		Content:   content,
+		RefId:     event.GetRunId(),
+		Timestamp: event.GetTimestamp(),
+	}
+	feedItem, err := s.feedClient.AddFeedItem(ctx, addFeedItemReq)
+	if err != nil {
+		log.Printf("failed to add feed item for schedule run %s: %v", event.GetRunId(), err)
+		return fmt.Errorf("failed to add feed item: %w", err)
+	}
+
+	log.Printf("Generated and added feed item %s for schedule run %s", feedItem.GetId(), event.GetRunId())
+
+	s.publishFeedItemCreated(ctx, feedItem, event.GetTransactionId())
+
+	return nil
+}
+
+// generateFeedItemForDecline adds a feed item explaining why a card payment was declined, e.g.
+// "Your £12.00 payment at Test Shop was declined. Your card is frozen. Unfreeze it in the app to
+// start using it again."
+func (s *server) generateFeedItemForDecline(ctx context.Context, event *eventspb.CardAuthDeclined) error {
+	merchantName := event.GetMerchantName()
+	if merchantName == "" {
+		merchantName = "an unknown place"
+	}
+	content := fmt.Sprintf("Your %s payment at %s was declined. %s",
+		formatAmount(event.GetAmount(), event.GetCurrency()), merchantName, declines.Explanation(declines.Parse(event.GetDeclineCode())))
+
+	addFeedItemReq := &feedpb.AddFeedItemRequest{
+		AccountId: event.GetAccountId(),
+		Type:      "DECLINED",
+		Content:   content,
+		RefId:     event.GetTransactionId(),
+		Timestamp: event.GetTimestamp(),
+	}
+	feedItem, err := s.feedClient.AddFeedItem(ctx, addFeedItemReq)
+	if err != nil {
+		log.Printf("failed to add feed item for declined payment on card %s: %v", event.GetCardId(), err)
+		return fmt.Errorf("failed to add feed item: %w", err)
+	}
+
+	log.Printf("Generated and added feed item %s for declined payment on card %s", feedItem.GetId(), event.GetCardId())
+
+	s.publishFeedItemCreated(ctx, feedItem, event.GetTransactionId())
+
+	return nil
+}
+
+// pinLockedContent is the feed item added when too many wrong PINs lock a card's PIN
+const pinLockedContent = "Your card's PIN is locked after too many wrong attempts. View your PIN in the app to unlock it."
+
+// generateFeedItemForPinLock adds a feed item telling the cardholder their PIN is locked and how to
+// unlock it. Like card-processing, it takes the cardholder's user ID to be their account ID.
+func (s *server) generateFeedItemForPinLock(ctx context.Context, event *eventspb.CardPinLocked) error {
+	addFeedItemReq := &feedpb.AddFeedItemRequest{
+		AccountId: event.GetUserId(),
+		Type:      "PIN_LOCKED",
+		Content:   pinLockedContent,
+		RefId:     event.GetCardId(),
+		Timestamp: event.GetTimestamp(),
+	}
+	feedItem, err := s.feedClient.AddFeedItem(ctx, addFeedItemReq)
+	if err != nil {
+		log.Printf("failed to add feed item for PIN lock on card %s: %v", event.GetCardId(), err)
+		return fmt.Errorf("failed to add feed item: %w", err)
+	}
+
+	log.Printf("Generated and added feed item %s for PIN lock on card %s", feedItem.GetId(), event.GetCardId())
+
+	s.publishFeedItemCreated(ctx, feedItem, "")
+
+	return nil
+}
+
+// publishFeedItemCreated publishes a "feed:item.created" event. The feed item already
+// exists, so a failure to publish is only logged.
+func (s *server) publishFeedItemCreated(ctx context.Context, feedItem *feedpb.FeedItem, transactionID string) {
+	event := &eventspb.FeedItemCreated{
+		FeedItemId:    feedItem.GetId(),
+		AccountId:     feedItem.GetAccountId(),
+		Type:          feedItem.GetType(),
+		TransactionId: transactionID,
+	}
+	if _, err := events.Publish(ctx, s.redisClient, events.StreamFeedItemCreated, event); err != nil {
 		log.Printf("failed to publish feed:item.created event for feed item %s: %v", feedItem.GetId(), err)
 	} else {
 		log.Printf("Published feed:item.created event for feed item %s", feedItem.GetId())
 	}
+}
 
-	return nil // Successfully generated and added feed item
+// currencySymbols are the symbols amounts are shown with in feed items
+var currencySymbols = map[string]string{
+	"GBP": "£",
+	"EUR": "€",
+	"USD": "$",
+}
+
+// formatAmount formats an amount in cents for a feed item, e.g. "£12.34", or "12.34 SEK"
+// for currencies without a symbol
+func formatAmount(amount int64, currency string) string {
+	if symbol, ok := currencySymbols[currency]; ok {
+		return fmt.Sprintf("%s%.2f", symbol, float64(amount)/100.0)
+	}
+	return fmt.Sprintf("%.2f %s", float64(amount)/100.0, currency)
 }
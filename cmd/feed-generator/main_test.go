@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc"
 
 	feedpb "github.com/manifoldfinance/disco2/v2/feed/feed"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
 	transactionspb "github.com/manifoldfinance/disco2/v2/transactions/transactions"
 )
 
@@ -47,6 +48,14 @@ func (m *mockTransactionsClient) UpdateTransaction(ctx context.Context, in *tran
 	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
 }
 
+func (m *mockTransactionsClient) GetTransactionByAuthCode(ctx context.Context, in *transactionspb.AuthCodeQuery, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
 // Mock FeedClient
 type mockFeedClient struct {
 	mock.Mock
@@ -137,6 +146,40 @@ func TestGenerateFeedItemForTransaction_Success(t *testing.T) {
 	mockFeedClient.AssertExpectations(t)
 }
 
+func TestGenerateFeedItemForTransaction_Refund(t *testing.T) {
+	s, mockTxnClient, mockFeedClient := newTestServer(t)
+
+	timestamp := time.Now().Format(time.RFC3339)
+
+	// A refund is recorded with a negative amount, linked to the transaction it refunds
+	mockTxnClient.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-refund"}).
+		Return(&transactionspb.Transaction{
+			Id:                    "txn-refund",
+			AccountId:             "acc-1",
+			Amount:                -400,
+			Currency:              "GBP",
+			MerchantRaw:           "Test Shop",
+			Timestamp:             timestamp,
+			OriginalTransactionId: "txn-123",
+		}, nil).Once()
+
+	expectedAddFeedReq := &feedpb.AddFeedItemRequest{
+		AccountId: "acc-1",
+		Type:      "REFUND",
+		Content:   "Refund of £4.00 from Test Shop",
+		RefId:     "txn-refund",
+		Timestamp: timestamp,
+	}
+	mockFeedClient.On("AddFeedItem", mock.Anything, expectedAddFeedReq).
+		Return(&feedpb.FeedItem{Id: "feed-refund", AccountId: "acc-1", Type: "REFUND", Content: expectedAddFeedReq.Content}, nil).Once()
+
+	err := s.generateFeedItemForTransaction(context.Background(), "txn-refund")
+
+	assert.NoError(t, err)
+	mockTxnClient.AssertExpectations(t)
+	mockFeedClient.AssertExpectations(t)
+}
+
 func TestGenerateFeedItemForTransaction_GetTransactionFails(t *testing.T) {
 	s, mockTxnClient, mockFeedClient := newTestServer(t)
 
@@ -191,5 +234,145 @@ func TestGenerateFeedItemForTransaction_AddFeedItemFails(t *testing.T) {
 	mockFeedClient.AssertExpectations(t)
 }
 
+func TestGenerateFeedItemForPotMove(t *testing.T) {
+	s, mockTxnClient, mockFeedClient := newTestServer(t)
+
+	timestamp := time.Now().Format(time.RFC3339)
+	tests := []struct {
+		direction string
+		amount    int64
+		currency  string
+		content   string
+	}{
+		{direction: "DEPOSIT", amount: 2500, currency: "GBP", content: "Moved £25.00 to Holiday"},
+		{direction: "WITHDRAWAL", amount: 1050, currency: "EUR", content: "Moved €10.50 from Holiday"},
+		{direction: "DEPOSIT", amount: 99, currency: "SEK", content: "Moved 0.99 SEK to Holiday"},
+	}
+	for _, tt := range tests {
+		event := &eventspb.PotMoved{
+			MoveId:    "move-" + tt.direction,
+			PotId:     "pot-1",
+			AccountId: "acc-1",
+			PotName:   "Holiday",
+			Direction: tt.direction,
+			Amount:    tt.amount,
+			Currency:  tt.currency,
+			Timestamp: timestamp,
+		}
+		expectedAddFeedReq := &feedpb.AddFeedItemRequest{
+			AccountId: "acc-1",
+			Type:      "POT",
+			Content:   tt.content,
+			RefId:     event.MoveId,
+			Timestamp: timestamp,
+		}
+		mockFeedClient.On("AddFeedItem", mock.Anything, expectedAddFeedReq).
+			Return(&feedpb.FeedItem{Id: "feed-" + event.MoveId, AccountId: "acc-1", Type: "POT", Content: tt.content}, nil).Once()
+
+		err := s.generateFeedItemForPotMove(context.Background(), event)
+		assert.NoError(t, err, tt.content)
+	}
+
+	mockFeedClient.AssertExpectations(t)
+	mockTxnClient.AssertNotCalled(t, "GetTransaction", mock.Anything, mock.Anything)
+}
+
+func TestGenerateFeedItemForPotMove_AddFeedItemFails(t *testing.T) {
+	s, _, mockFeedClient := newTestServer(t)
+
+	mockFeedClient.On("AddFeedItem", mock.Anything, mock.AnythingOfType("*feed.AddFeedItemRequest")).
+		Return(nil, errors.New("feed service error")).Once()
+
+	err := s.generateFeedItemForPotMove(context.Background(), &eventspb.PotMoved{MoveId: "move-1", PotName: "Holiday", Amount: 100, Currency: "GBP"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to add feed item")
+	mockFeedClient.AssertExpectations(t)
+}
+
+func TestGenerateFeedItemForScheduleRun(t *testing.T) {
+	s, _, mockFeedClient := newTestServer(t)
+
+	timestamp := time.Now().Format(time.RFC3339)
+	for _, tt := range []struct {
+		status  string
+		reason  string
+		content string
+	}{
+		{status: "PAID", content: "Scheduled payment of £500.00 to Landlord paid"},
+		{status: "DECLINED", reason: "insufficient funds", content: "Scheduled payment of £500.00 to Landlord failed: insufficient funds"},
+	} {
+		event := &eventspb.ScheduledPaymentRun{
+			RunId:         "run-" + tt.status,
+			ScheduleId:    "schedule-1",
+			AccountId:     "acc-1",
+			Payee:         "Landlord",
+			Amount:        50000,
+			Currency:      "GBP",
+			Status:        tt.status,
+			DeclineReason: tt.reason,
+			Timestamp:     timestamp,
+		}
+		mockFeedClient.On("AddFeedItem", mock.Anything, &feedpb.AddFeedItemRequest{
+			AccountId: "acc-1",
+			Type:      "SCHEDULED_PAYMENT",
+			Content:   tt.content,
+			RefId:     event.RunId,
+			Timestamp: timestamp,
+		}).Return(&feedpb.FeedItem{Id: "feed-" + event.RunId, AccountId: "acc-1", Type: "SCHEDULED_PAYMENT"}, nil).Once()
+
+		err := s.generateFeedItemForScheduleRun(context.Background(), event)
+		assert.NoError(t, err, tt.status)
+	}
+
+	mockFeedClient.AssertExpectations(t)
+}
+
+func TestGenerateFeedItemForDecline(t *testing.T) {
+	s, _, mockFeedClient := newTestServer(t)
+
+	timestamp := time.Now().Format(time.RFC3339)
+	event := &eventspb.CardAuthDeclined{
+		CardId:        "card-123",
+		AccountId:     "acc-1",
+		Amount:        1200,
+		Currency:      "GBP",
+		MerchantName:  "Test Shop",
+		DeclineCode:   "DECLINE_CODE_CARD_FROZEN",
+		DeclineReason: "card is frozen",
+		Timestamp:     timestamp,
+	}
+	mockFeedClient.On("AddFeedItem", mock.Anything, &feedpb.AddFeedItemRequest{
+		AccountId: "acc-1",
+		Type:      "DECLINED",
+		Content:   "Your £12.00 payment at Test Shop was declined. Your card is frozen. Unfreeze it in the app to start using it again.",
+		Timestamp: timestamp,
+	}).Return(&feedpb.FeedItem{Id: "feed-1", AccountId: "acc-1", Type: "DECLINED"}, nil).Once()
+
+	err := s.generateFeedItemForDecline(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockFeedClient.AssertExpectations(t)
+}
+
+func TestGenerateFeedItemForPinLock(t *testing.T) {
+	s, _, mockFeedClient := newTestServer(t)
+
+	timestamp := time.Now().Format(time.RFC3339)
+	event := &eventspb.CardPinLocked{CardId: "card-123", UserId: "user-1", Timestamp: timestamp}
+	mockFeedClient.On("AddFeedItem", mock.Anything, &feedpb.AddFeedItemRequest{
+		AccountId: "user-1",
+		Type:      "PIN_LOCKED",
+		Content:   "Your card's PIN is locked after too many wrong attempts. View your PIN in the app to unlock it.",
+		RefId:     "card-123",
+		Timestamp: timestamp,
+	}).Return(&feedpb.FeedItem{Id: "feed-1", AccountId: "user-1", Type: "PIN_LOCKED"}, nil).Once()
+
+	err := s.generateFeedItemForPinLock(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockFeedClient.AssertExpectations(t)
+}
+
 // Note: Testing the Redis publish failure is less critical as the feed item is already created.
 // We could add a test, but it would look similar to the success case, just asserting the log message.
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -13,6 +15,9 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+
 	merchantpb "github.com/manifoldfinance/disco2/v2/merchant/merchant"
 )
 
@@ -30,6 +35,21 @@ func newTestServer(t *testing.T) (*server, sqlmock.Sqlmock, redismock.ClientMock
 	return s, mockDb, mockRedisClient
 }
 
+// matchXAdd matches XAdd commands on stream and fields, ignoring field order
+func matchXAdd(expected, actual []interface{}) error {
+	fields := func(args []interface{}) map[interface{}]interface{} {
+		m := map[interface{}]interface{}{}
+		for i := 3; i+1 < len(args); i += 2 {
+			m[args[i]] = args[i+1]
+		}
+		return m
+	}
+	if len(expected) != len(actual) || expected[1] != actual[1] || !reflect.DeepEqual(fields(expected), fields(actual)) {
+		return fmt.Errorf("expected %v, got %v", expected, actual)
+	}
+	return nil
+}
+
 func TestGetMerchant_Found(t *testing.T) {
 	s, mockDb, _ := newTestServer(t)
 	defer s.db.Close()
@@ -156,13 +176,22 @@ func TestUpdateMerchant_Success(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"merchant_id", "name", "category", "logo_url", "mcc"}).
 			AddRow(req.MerchantId, req.Name, sql.NullString{String: req.Category, Valid: true}, sql.NullString{String: req.LogoUrl, Valid: true}, sql.NullInt32{Int32: req.Mcc, Valid: true}))
 
-	// Mock Redis XAdd command with any payload value
-	eventPayload := `{"merchant_id": "merch-to-update", "name": "Updated Name", "category": "Updated Category", "logo_url": "http://new.logo/url.png", "mcc": 1111}`
-	mockRedis.ExpectXAdd(&redis.XAddArgs{
+	// Mock Redis XAdd command with the encoded merchant:updated event
+	payload, headers, err := events.Encode(&eventspb.MerchantUpdated{
+		MerchantId: req.MerchantId,
+		Name:       req.Name,
+		Category:   req.Category,
+		LogoUrl:    req.LogoUrl,
+		Mcc:        req.Mcc,
+	})
+	assert.NoError(t, err)
+	values := map[string]interface{}{"payload": payload}
+	for k, v := range headers {
+		values[k] = v
+	}
+	mockRedis.CustomMatch(matchXAdd).ExpectXAdd(&redis.XAddArgs{
 		Stream: "merchant:updated",
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
+		Values: values,
 	}).SetVal("some-stream-id")
 
 	ctx := context.Background()
@@ -0,0 +1,239 @@
+// Package main is the entry point for the pots service
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/internal/pots/config"
+	"github.com/manifoldfinance/disco2/v2/internal/pots/db"
+	"github.com/manifoldfinance/disco2/v2/internal/pots/service"
+	"github.com/manifoldfinance/disco2/v2/pkg/outbox"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/pots"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Database connection setup
+	database, err := db.Connect(cfg.DBDSN)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	// Run database migrations
+	if err := db.RunMigrations(cfg.DBDSN, "file://migrations/pots"); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Redis client setup
+	rdb := redis.NewClient(&redis.Options{
+		Addr: cfg.RedisAddr,
+		DB:   0, // use default DB
+	})
+
+	// Ping Redis to check connection
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	log.Println("Connected to Redis")
+	defer rdb.Close()
+
+	// Connect to the balance service, which holds the money in pots
+	balanceConn, err := grpc.Dial(cfg.BalanceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to balance service: %v", err)
+	}
+	defer balanceConn.Close()
+
+	// Create pots service
+	potsService := service.NewPotsService(database, balancepb.NewBalanceClient(balanceConn))
+
+	// Relay outbox events in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go outbox.NewRelay(database, rdb).Run(backgroundCtx)
+
+	// Create HTTP server
+	h := &handlers{svc: potsService}
+	e := echo.New()
+	e.GET("/accounts/:account_id/pots", h.listPots)
+	e.POST("/accounts/:account_id/pots", h.createPot)
+	e.GET("/pots/:pot_id", h.getPot)
+	e.PATCH("/pots/:pot_id", h.updatePot)
+	e.DELETE("/pots/:pot_id", h.closePot)
+	e.POST("/pots/:pot_id/deposit", h.deposit)
+	e.POST("/pots/:pot_id/withdraw", h.withdraw)
+
+	// Start HTTP server in a goroutine
+	httpServer := &http.Server{
+		Addr:    cfg.HTTPPort,
+		Handler: e,
+	}
+	go func() {
+		log.Printf("HTTP server starting on %s", cfg.HTTPPort)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start HTTP server: %v", err)
+		}
+	}()
+
+	// Create gRPC server
+	grpcServer := grpc.NewServer()
+	pb.RegisterPotsServer(grpcServer, potsService)
+
+	// Start gRPC server in a goroutine
+	lis, err := net.Listen("tcp", cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", cfg.GRPCPort, err)
+	}
+	go func() {
+		log.Printf("gRPC server listening on %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve gRPC: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shut down the servers
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down servers...")
+
+	// Shutdown HTTP server
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Fatalf("Server shutdown error: %v", err)
+	}
+
+	// Shutdown gRPC server
+	grpcServer.GracefulStop()
+	stopBackground()
+
+	log.Println("Servers successfully shut down.")
+}
+
+// handlers serves the pots HTTP API
+type handlers struct {
+	svc *service.PotsService
+}
+
+func (h *handlers) listPots(c echo.Context) error {
+	includeClosed, _ := strconv.ParseBool(c.QueryParam("include_closed"))
+	req := &pb.ListPotsRequest{AccountId: c.Param("account_id"), IncludeClosed: includeClosed}
+
+	pots, err := h.svc.ListPots(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, pots)
+}
+
+func (h *handlers) createPot(c echo.Context) error {
+	req := new(pb.CreatePotRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.AccountId = c.Param("account_id")
+
+	pot, err := h.svc.CreatePot(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, pot)
+}
+
+func (h *handlers) getPot(c echo.Context) error {
+	pot, err := h.svc.GetPot(c.Request().Context(), &pb.PotID{PotId: c.Param("pot_id")})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, pot)
+}
+
+func (h *handlers) updatePot(c echo.Context) error {
+	req := new(pb.UpdatePotRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.PotId = c.Param("pot_id")
+
+	pot, err := h.svc.UpdatePot(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, pot)
+}
+
+func (h *handlers) closePot(c echo.Context) error {
+	pot, err := h.svc.ClosePot(c.Request().Context(), &pb.PotID{PotId: c.Param("pot_id")})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, pot)
+}
+
+func (h *handlers) deposit(c echo.Context) error {
+	return h.move(c, h.svc.Deposit)
+}
+
+func (h *handlers) withdraw(c echo.Context) error {
+	return h.move(c, h.svc.Withdraw)
+}
+
+// move handles a deposit or withdrawal. A declined move is returned with 422 Unprocessable Entity.
+func (h *handlers) move(c echo.Context, rpc func(context.Context, *pb.MoveRequest) (*pb.MoveResult, error)) error {
+	req := new(pb.MoveRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.PotId = c.Param("pot_id")
+
+	result, err := rpc(c.Request().Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if !result.Success {
+		return c.JSON(http.StatusUnprocessableEntity, result)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// errorResponse maps a gRPC error from the pots service to an HTTP error response
+func errorResponse(c echo.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,129 @@
+// Package main is the entry point for the scheduler service
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/manifoldfinance/disco2/v2/internal/scheduler/config"
+	"github.com/manifoldfinance/disco2/v2/internal/scheduler/db"
+	"github.com/manifoldfinance/disco2/v2/internal/scheduler/service"
+	"github.com/manifoldfinance/disco2/v2/pkg/outbox"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/scheduler"
+	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Database connection setup
+	database, err := db.Connect(cfg.DBDSN)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	// Run database migrations
+	if err := db.RunMigrations(cfg.DBDSN, "file://migrations/scheduler"); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Redis client setup
+	rdb := redis.NewClient(&redis.Options{
+		Addr: cfg.RedisAddr,
+		DB:   0, // use default DB
+	})
+
+	// Ping Redis to check connection
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	log.Println("Connected to Redis")
+	defer rdb.Close()
+
+	// Connect to the balance and transactions services, which scheduled payments are made through
+	balanceConn, err := grpc.Dial(cfg.BalanceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to balance service: %v", err)
+	}
+	defer balanceConn.Close()
+
+	transactionsConn, err := grpc.Dial(cfg.TransactionsAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to transactions service: %v", err)
+	}
+	defer transactionsConn.Close()
+
+	// Create scheduler service
+	schedulerService := service.NewSchedulerService(database,
+		balancepb.NewBalanceClient(balanceConn),
+		transactionspb.NewTransactionsClient(transactionsConn),
+		service.WithRetryAfter(cfg.RetryAfter),
+	)
+
+	// Run due schedules and relay outbox events in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go runScheduler(backgroundCtx, schedulerService, cfg.RunInterval)
+	go outbox.NewRelay(database, rdb).Run(backgroundCtx)
+
+	// Create gRPC server
+	grpcServer := grpc.NewServer()
+	pb.RegisterSchedulerServer(grpcServer, schedulerService)
+
+	// Start gRPC server in a goroutine
+	lis, err := net.Listen("tcp", cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", cfg.GRPCPort, err)
+	}
+	go func() {
+		log.Printf("gRPC server listening on %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve gRPC: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shut down the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	// Shutdown gRPC server and stop running schedules
+	grpcServer.GracefulStop()
+	stopBackground()
+
+	log.Println("Server successfully shut down.")
+}
+
+// runScheduler periodically pays due schedules until ctx is cancelled
+func runScheduler(ctx context.Context, svc *service.SchedulerService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := svc.RunDueSchedules(ctx, &pb.RunDueSchedulesRequest{Limit: 500}); err != nil {
+				log.Printf("failed to run due schedules: %v", err)
+			}
+		}
+	}
+}
@@ -2,17 +2,19 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	merchantpb "github.com/manifoldfinance/disco2/v2/merchant/merchant"
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	"github.com/manifoldfinance/disco2/v2/pkg/streamconsumer"
 	transactionspb "github.com/manifoldfinance/disco2/v2/transactions/transactions"
 )
 
@@ -58,84 +60,29 @@ func main() {
 		merchantClient:     merchantClient,
 	}
 
-	// Start Redis event consumer
-	go s.startEventConsumer(ctx)
+	// Stop consuming on SIGINT/SIGTERM, finishing events already being processed
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Keep main goroutine alive
-	select {}
+	// Run Redis event consumer until shutdown
+	s.startEventConsumer(ctx)
+	log.Println("Event consumer stopped")
 }
 
 func (s *server) startEventConsumer(ctx context.Context) {
 	log.Println("Starting Redis event consumer...")
 
-	consumerGroup := "enrichment-consumer-group"
-	streamName := "transaction:created"
-
-	// Create consumer group if it doesn't exist
-	if _, err := s.redisClient.XGroupCreateMkStream(ctx, streamName, consumerGroup, "0").Result(); err != nil {
-		// Ignore BUSYGROUP error if group already exists
-		if !strings.Contains(err.Error(), "BUSYGROUP") {
-			log.Fatalf("failed to create Redis consumer group %s: %v", consumerGroup, err)
-		}
-	}
-	log.Printf("Redis consumer group '%s' created or already exists", consumerGroup)
-
-	for {
-		// Read messages from the stream using the consumer group
-		messages, err := s.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
-			Group:    consumerGroup,
-			Consumer: "enrichment-instance-1",
-			Streams:  []string{streamName, ">"},
-			Count:    10,
-			Block:    0,
-			NoAck:    false,
-		}).Result()
-		if err != nil {
-			log.Printf("error reading from Redis stream %s: %v", streamName, err)
-			time.Sleep(time.Second) // Wait before retrying
-			continue
-		}
-
-		for _, stream := range messages {
-			for _, message := range stream.Messages {
-				log.Printf("Received message %s from stream %s", message.ID, stream.Stream)
-
-				// Process the message
-				payload, ok := message.Values["payload"].(string)
-				if !ok {
-					log.Printf("message %s has no 'payload' field or it's not a string", message.ID)
-					// Acknowledge the message to prevent reprocessing
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				var event struct {
-					Id string `json:"id"`
-				}
-				if err := json.Unmarshal([]byte(payload), &event); err != nil {
-					log.Printf("failed to unmarshal event payload for message %s: %v", message.ID, err)
-					// Acknowledge the message to prevent reprocessing
-					s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID)
-					continue
-				}
-
-				log.Printf("Processing transaction created event for transaction ID: %s", event.Id)
-
-				// Attempt to enrich the transaction
-				if err := s.enrichTransaction(ctx, event.Id); err != nil {
-					log.Printf("failed to enrich transaction %s: %v", event.Id, err)
-					// Do NOT acknowledge the message, it will be retried later
-					continue
-				}
-
-				// Acknowledge the message after successful processing
-				if _, err := s.redisClient.XAck(ctx, streamName, consumerGroup, message.ID).Result(); err != nil {
-					log.Printf("failed to acknowledge message %s: %v", message.ID, err)
-				} else {
-					log.Printf("Acknowledged message %s", message.ID)
-				}
-			}
-		}
+	err := events.Subscribe(ctx, s.redisClient, events.StreamTransactionCreated, "enrichment-consumer-group",
+		func(ctx context.Context, event *eventspb.TransactionCreated) error {
+			log.Printf("Processing transaction created event for transaction ID: %s", event.GetId())
+
+			// Attempt to enrich the transaction
+			return s.enrichTransaction(ctx, event.GetId())
+		},
+		streamconsumer.WithConsumerName(streamconsumer.ConsumerName("enrichment")),
+	)
+	if err != nil && ctx.Err() == nil {
+		log.Fatalf("failed to consume %s events: %v", events.StreamTransactionCreated, err)
 	}
 }
 
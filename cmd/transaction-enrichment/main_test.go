@@ -45,6 +45,14 @@ func (m *mockTransactionsClient) UpdateTransaction(ctx context.Context, in *tran
 	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
 }
 
+func (m *mockTransactionsClient) GetTransactionByAuthCode(ctx context.Context, in *transactionspb.AuthCodeQuery, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
 // Mock MerchantClient
 type mockMerchantClient struct {
 	mock.Mock
@@ -3,6 +3,7 @@ package main
 import (
 	"context" // Import context
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -28,10 +29,25 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" // PostgreSQL driver
 	_ "github.com/golang-migrate/migrate/v4/source/file"       // File source
 
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	"github.com/manifoldfinance/disco2/v2/pkg/idempotency"
+	"github.com/manifoldfinance/disco2/v2/pkg/outbox"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+
 	// Import generated protobuf code
 	transactionspb "github.com/manifoldfinance/disco2/v2/transactions/transactions"
 )
 
+// recordTransactionScope is the idempotency key scope for RecordTransaction
+const recordTransactionScope = "RecordTransaction"
+
+// Statuses of a settled transaction that refunds have been recorded against
+const (
+	statusSettled           = "SETTLED"
+	statusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	statusReversed          = "REVERSED"
+)
+
 // Config holds the application configuration
 type Config struct {
 	DBDSN     string `koanf:"db_dsn"`
@@ -70,8 +86,7 @@ func LoadConfig() (*Config, error) {
 
 type Server struct {
 	transactionspb.UnimplementedTransactionsServer
-	db          *sql.DB
-	redisClient *redis.Client
+	db *sql.DB
 }
 
 func main() {
@@ -114,7 +129,10 @@ func main() {
 	}
 	log.Println("Database migrations applied successfully")
 
-	s := &server{db: db, redisClient: rdb}
+	s := &server{db: db}
+
+	// Relay queued events from the outbox to Redis
+	go outbox.NewRelay(db, rdb).Run(ctx)
 
 	// Set up Echo HTTP server
 	e := echo.New()
@@ -149,21 +167,54 @@ func main() {
 func (s *server) RecordTransaction(ctx context.Context, req *transactionspb.TransactionInput) (*transactionspb.Transaction, error) {
 	log.Printf("Received RecordTransaction request: %+v", req)
 
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to record transaction")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Replay the original transaction if this is a retry
+	if req.GetIdempotencyKey() != "" {
+		var previous transactionspb.Transaction
+		replayed, err := idempotency.Claim(ctx, tx, recordTransactionScope, req.GetIdempotencyKey(), req, &previous)
+		if err != nil {
+			if errors.Is(err, idempotency.ErrKeyReused) {
+				return nil, status.Errorf(codes.AlreadyExists, "idempotency key already used with a different request")
+			}
+			log.Printf("failed to claim idempotency key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to record transaction")
+		}
+		if replayed {
+			log.Printf("Replaying RecordTransaction result for idempotency key %s", req.GetIdempotencyKey())
+			return &previous, nil
+		}
+	}
+
 	transactionID := uuid.New().String()
 	now := time.Now()
 	timestampStr := now.Format(time.RFC3339) // Format timestamp
 
-	query := `INSERT INTO transactions (id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, created_at)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			  RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, created_at`
+	// A refund is checked against, and counted towards, the transaction it refunds
+	if req.GetOriginalTransactionId() != "" {
+		if err := applyRefund(ctx, tx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `INSERT INTO transactions (id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, hold_id, original_transaction_id, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			  RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, hold_id, original_transaction_id, created_at`
 
 	var createdTxn transactionspb.Transaction
 	var cardID sql.NullString
 	var merchantID sql.NullString
 	var merchantRaw sql.NullString
+	var holdID sql.NullString
+	var originalTransactionID sql.NullString
 	var createdAt time.Time
 
-	err := s.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		transactionID,
 		req.GetAccountId(),
 		sql.NullString{String: req.GetCardId(), Valid: req.GetCardId() != ""},
@@ -172,6 +223,8 @@ func (s *server) RecordTransaction(ctx context.Context, req *transactionspb.Tran
 		sql.NullString{String: req.GetMerchantId(), Valid: req.GetMerchantId() != ""},
 		sql.NullString{String: req.GetMerchantRaw(), Valid: req.GetMerchantRaw() != ""},
 		req.GetStatus(),
+		sql.NullString{String: req.GetHoldId(), Valid: req.GetHoldId() != ""},
+		sql.NullString{String: req.GetOriginalTransactionId(), Valid: req.GetOriginalTransactionId() != ""},
 		now,
 	).Scan(
 		&createdTxn.Id,
@@ -182,6 +235,8 @@ func (s *server) RecordTransaction(ctx context.Context, req *transactionspb.Tran
 		&merchantID,
 		&merchantRaw,
 		&createdTxn.Status,
+		&holdID,
+		&originalTransactionID,
 		&createdAt,
 	)
 	if err != nil {
@@ -192,39 +247,90 @@ func (s *server) RecordTransaction(ctx context.Context, req *transactionspb.Tran
 	createdTxn.CardId = cardID.String
 	createdTxn.MerchantId = merchantID.String
 	createdTxn.MerchantRaw = merchantRaw.String
+	createdTxn.HoldId = holdID.String
+	createdTxn.OriginalTransactionId = originalTransactionID.String
 	createdTxn.Timestamp = createdAt.Format(time.RFC3339) // Use the DB timestamp
 
-	// Publish "transaction:created" event to Redis
-	// Event payload could be JSON or protobuf binary
-	eventPayload := fmt.Sprintf(`{"id": "%s", "account_id": "%s", "amount": %d, "currency": "%s", "status": "%s", "timestamp": "%s"}`,
-		createdTxn.GetId(),
-		createdTxn.GetAccountId(),
-		createdTxn.GetAmount(),
-		createdTxn.GetCurrency(),
-		createdTxn.GetStatus(),
-		createdTxn.GetTimestamp(),
-	)
-	if _, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
-		Stream: "transaction:created",
-		MaxLen: 0, // No limit
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
-	}).Result(); err != nil {
-		log.Printf("failed to publish transaction:created event: %v", err)
-		// Depending on requirements, failure to publish event might be critical or just logged
-		// For now, just log the error
-	} else {
-		log.Printf("Published transaction:created event for transaction %s", createdTxn.GetId())
+	if req.GetIdempotencyKey() != "" {
+		if err := idempotency.Complete(ctx, tx, recordTransactionScope, req.GetIdempotencyKey(), &createdTxn); err != nil {
+			log.Printf("failed to store idempotency key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to record transaction")
+		}
+	}
+
+	// Queue "transaction:created" event in the same transaction
+	event := &eventspb.TransactionCreated{
+		Id:          createdTxn.GetId(),
+		AccountId:   createdTxn.GetAccountId(),
+		Amount:      createdTxn.GetAmount(),
+		Currency:    createdTxn.GetCurrency(),
+		Status:      createdTxn.GetStatus(),
+		Timestamp:   createdTxn.GetTimestamp(),
+		CardId:      createdTxn.GetCardId(),
+		MerchantRaw: createdTxn.GetMerchantRaw(),
+	}
+	if err := events.Enqueue(ctx, tx, events.StreamTransactionCreated, createdTxn.GetId(), event); err != nil {
+		log.Printf("failed to enqueue transaction:created event: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to record transaction")
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to record transaction")
 	}
 
 	return &createdTxn, nil
 }
 
+// applyRefund locks the transaction a refund refers to, checks the refund fits within what is left
+// to refund and adds it to the refunded amount. A transaction refunded in full is REVERSED.
+func applyRefund(ctx context.Context, tx *sql.Tx, req *transactionspb.TransactionInput) error {
+	if req.GetAmount() >= 0 {
+		return status.Errorf(codes.InvalidArgument, "a refund amount must be negative")
+	}
+
+	var accountID, currency, originalStatus string
+	var amount, refunded int64
+	query := `SELECT account_id, currency, amount, status, refunded_amount FROM transactions WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRowContext(ctx, query, req.GetOriginalTransactionId()).Scan(&accountID, &currency, &amount, &originalStatus, &refunded)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("original transaction not found for refund: %s", req.GetOriginalTransactionId())
+			return status.Errorf(codes.NotFound, "original transaction not found")
+		}
+		log.Printf("failed to lock original transaction %s: %v", req.GetOriginalTransactionId(), err)
+		return status.Errorf(codes.Internal, "failed to record transaction")
+	}
+
+	if originalStatus != statusSettled && originalStatus != statusPartiallyRefunded {
+		return status.Errorf(codes.FailedPrecondition, "original transaction is %s", originalStatus)
+	}
+	if accountID != req.GetAccountId() || currency != req.GetCurrency() {
+		return status.Errorf(codes.InvalidArgument, "a refund must be to the account and in the currency of the original transaction")
+	}
+	if refunded-req.GetAmount() > amount {
+		return status.Errorf(codes.FailedPrecondition, "refund exceeds the %d left to refund", amount-refunded)
+	}
+
+	refunded -= req.GetAmount()
+	newStatus := statusPartiallyRefunded
+	if refunded == amount {
+		newStatus = statusReversed
+	}
+	updateQuery := `UPDATE transactions SET refunded_amount = $1, status = $2 WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, updateQuery, refunded, newStatus, req.GetOriginalTransactionId()); err != nil {
+		log.Printf("failed to update refunded amount: %v", err)
+		return status.Errorf(codes.Internal, "failed to record transaction")
+	}
+
+	return nil
+}
+
 func (s *server) GetTransaction(ctx context.Context, req *transactionspb.TransactionQuery) (*transactionspb.Transaction, error) {
 	log.Printf("Received GetTransaction request: %+v", req)
 
-	query := `SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at
+	query := `SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at
 			  FROM transactions WHERE id = $1`
 
 	var transaction transactionspb.Transaction
@@ -232,6 +338,13 @@ func (s *server) GetTransaction(ctx context.Context, req *transactionspb.Transac
 	var merchantID sql.NullString
 	var merchantRaw sql.NullString
 	var category sql.NullString
+	var holdID sql.NullString
+	var billingCurrency sql.NullString
+	var billingAmount sql.NullInt64
+	var fxRate sql.NullString
+	var fxFee sql.NullInt64
+	var authCode sql.NullString
+	var originalTransactionID sql.NullString
 	var createdAt time.Time
 
 	err := s.db.QueryRowContext(ctx, query, req.GetId()).Scan(
@@ -244,6 +357,14 @@ func (s *server) GetTransaction(ctx context.Context, req *transactionspb.Transac
 		&merchantRaw,
 		&category,
 		&transaction.Status,
+		&holdID,
+		&billingCurrency,
+		&billingAmount,
+		&fxRate,
+		&fxFee,
+		&authCode,
+		&originalTransactionID,
+		&transaction.RefundedAmount,
 		&createdAt,
 	)
 	if err != nil {
@@ -259,6 +380,13 @@ func (s *server) GetTransaction(ctx context.Context, req *transactionspb.Transac
 	transaction.MerchantId = merchantID.String
 	transaction.MerchantRaw = merchantRaw.String
 	transaction.Category = category.String
+	transaction.HoldId = holdID.String
+	transaction.BillingCurrency = billingCurrency.String
+	transaction.BillingAmount = billingAmount.Int64
+	transaction.FxRate = fxRate.String
+	transaction.FxFee = fxFee.Int64
+	transaction.AuthCode = authCode.String
+	transaction.OriginalTransactionId = originalTransactionID.String
 	transaction.Timestamp = createdAt.Format(time.RFC3339)
 
 	return &transaction, nil
@@ -267,7 +395,7 @@ func (s *server) GetTransaction(ctx context.Context, req *transactionspb.Transac
 func (s *server) ListTransactions(ctx context.Context, req *transactionspb.TransactionsQuery) (*transactionspb.TransactionsList, error) {
 	log.Printf("Received ListTransactions request: %+v", req)
 
-	query := `SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at
+	query := `SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at
 			  FROM transactions WHERE account_id = $1`
 	args := []interface{}{req.GetAccountId()}
 
@@ -310,6 +438,13 @@ func (s *server) ListTransactions(ctx context.Context, req *transactionspb.Trans
 		var merchantID sql.NullString
 		var merchantRaw sql.NullString
 		var category sql.NullString
+		var holdID sql.NullString
+		var billingCurrency sql.NullString
+		var billingAmount sql.NullInt64
+		var fxRate sql.NullString
+		var fxFee sql.NullInt64
+		var authCode sql.NullString
+		var originalTransactionID sql.NullString
 		var createdAt time.Time
 
 		if err := rows.Scan(
@@ -322,6 +457,14 @@ func (s *server) ListTransactions(ctx context.Context, req *transactionspb.Trans
 			&merchantRaw,
 			&category,
 			&transaction.Status,
+			&holdID,
+			&billingCurrency,
+			&billingAmount,
+			&fxRate,
+			&fxFee,
+			&authCode,
+			&originalTransactionID,
+			&transaction.RefundedAmount,
 			&createdAt,
 		); err != nil {
 			log.Printf("failed to scan transaction row: %v", err)
@@ -332,6 +475,13 @@ func (s *server) ListTransactions(ctx context.Context, req *transactionspb.Trans
 		transaction.MerchantId = merchantID.String
 		transaction.MerchantRaw = merchantRaw.String
 		transaction.Category = category.String
+		transaction.HoldId = holdID.String
+		transaction.BillingCurrency = billingCurrency.String
+		transaction.BillingAmount = billingAmount.Int64
+		transaction.FxRate = fxRate.String
+		transaction.FxFee = fxFee.Int64
+		transaction.AuthCode = authCode.String
+		transaction.OriginalTransactionId = originalTransactionID.String
 		transaction.Timestamp = createdAt.Format(time.RFC3339)
 
 		transactions = append(transactions, &transaction)
@@ -373,12 +523,35 @@ func (s *server) UpdateTransaction(ctx context.Context, req *transactionspb.Upda
 		args = append(args, req.GetStatus())
 		argIndex++
 	}
+	if req.GetHoldId() != "" {
+		updates = append(updates, fmt.Sprintf("hold_id = $%d", argIndex))
+		args = append(args, req.GetHoldId())
+		argIndex++
+	}
+	if req.GetBillingCurrency() != "" {
+		// A currency conversion is recorded as a whole
+		updates = append(updates, fmt.Sprintf("billing_currency = $%d, billing_amount = $%d, fx_rate = $%d, fx_fee = $%d",
+			argIndex, argIndex+1, argIndex+2, argIndex+3))
+		args = append(args, req.GetBillingCurrency(), req.GetBillingAmount(), req.GetFxRate(), req.GetFxFee())
+		argIndex += 4
+	}
+	if req.GetAuthCode() != "" {
+		updates = append(updates, fmt.Sprintf("auth_code = $%d", argIndex))
+		args = append(args, req.GetAuthCode())
+		argIndex++
+	}
+	if req.GetAmount() != 0 {
+		// Settlement may differ from the authorized amount, e.g. a tip added after authorization
+		updates = append(updates, fmt.Sprintf("amount = $%d", argIndex))
+		args = append(args, req.GetAmount())
+		argIndex++
+	}
 
 	if len(updates) == 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "no fields to update")
 	}
 
-	query := fmt.Sprintf(`UPDATE transactions SET %s WHERE id = $%d RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at`,
+	query := fmt.Sprintf(`UPDATE transactions SET %s WHERE id = $%d RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at`,
 		strings.Join(updates, ", "), argIndex)
 	args = append(args, req.GetId())
 
@@ -387,6 +560,13 @@ func (s *server) UpdateTransaction(ctx context.Context, req *transactionspb.Upda
 	var merchantID sql.NullString
 	var merchantRaw sql.NullString
 	var category sql.NullString
+	var holdID sql.NullString
+	var billingCurrency sql.NullString
+	var billingAmount sql.NullInt64
+	var fxRate sql.NullString
+	var fxFee sql.NullInt64
+	var authCode sql.NullString
+	var originalTransactionID sql.NullString
 	var createdAt time.Time
 
 	err := s.db.QueryRowContext(ctx, query, args...).Scan(
@@ -399,6 +579,14 @@ func (s *server) UpdateTransaction(ctx context.Context, req *transactionspb.Upda
 		&merchantRaw,
 		&category,
 		&updatedTxn.Status,
+		&holdID,
+		&billingCurrency,
+		&billingAmount,
+		&fxRate,
+		&fxFee,
+		&authCode,
+		&originalTransactionID,
+		&updatedTxn.RefundedAmount,
 		&createdAt,
 	)
 	if err != nil {
@@ -414,11 +602,43 @@ func (s *server) UpdateTransaction(ctx context.Context, req *transactionspb.Upda
 	updatedTxn.MerchantId = merchantID.String
 	updatedTxn.MerchantRaw = merchantRaw.String
 	updatedTxn.Category = category.String
+	updatedTxn.HoldId = holdID.String
+	updatedTxn.BillingCurrency = billingCurrency.String
+	updatedTxn.BillingAmount = billingAmount.Int64
+	updatedTxn.FxRate = fxRate.String
+	updatedTxn.FxFee = fxFee.Int64
+	updatedTxn.AuthCode = authCode.String
+	updatedTxn.OriginalTransactionId = originalTransactionID.String
 	updatedTxn.Timestamp = createdAt.Format(time.RFC3339)
 
 	return &updatedTxn, nil
 }
 
+// GetTransactionByAuthCode finds the card transaction a settlement record refers to, by the
+// authorization code issued when the transaction was authorized
+func (s *server) GetTransactionByAuthCode(ctx context.Context, req *transactionspb.AuthCodeQuery) (*transactionspb.Transaction, error) {
+	log.Printf("Received GetTransactionByAuthCode request: %+v", req)
+
+	if req.GetCardId() == "" || req.GetAuthCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "card_id and auth_code are required")
+	}
+
+	// Auth codes are only unique per card for a while, so the most recent transaction is the one meant
+	var transactionID string
+	query := `SELECT id FROM transactions WHERE card_id = $1 AND auth_code = $2 ORDER BY created_at DESC LIMIT 1`
+	err := s.db.QueryRowContext(ctx, query, req.GetCardId(), req.GetAuthCode()).Scan(&transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("no transaction for card %s with auth code %s", req.GetCardId(), req.GetAuthCode())
+			return nil, status.Errorf(codes.NotFound, "transaction not found")
+		}
+		log.Printf("failed to find transaction by auth code: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get transaction")
+	}
+
+	return s.GetTransaction(ctx, &transactionspb.TransactionQuery{Id: transactionID})
+}
+
 // Implement HTTP handlers here
 
 func (s *server) listTransactionsHandler(c echo.Context) error {
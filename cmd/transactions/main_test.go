@@ -9,30 +9,26 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/go-redis/redismock/v8"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/manifoldfinance/disco2/v2/pkg/idempotency"
 	transactionspb "github.com/manifoldfinance/disco2/v2/transactions/transactions"
 )
 
 // Helper function to create a server instance with mocks
-func newTestServer(t *testing.T) (*Server, sqlmock.Sqlmock, redismock.ClientMock) {
+func newTestServer(t *testing.T) (*Server, sqlmock.Sqlmock) {
 	db, mockDb, err := sqlmock.New()
 	assert.NoError(t, err)
 
-	mockRedis, mockRedisClient := redismock.NewClientMock()
-
-	s := &Server{
-		db:          db,
-		redisClient: mockRedisClient,
-	}
-	return s, mockDb, mockRedisClient
+	s := &Server{db: db}
+	return s, mockDb
 }
 
 func TestRecordTransaction(t *testing.T) {
-	s, mockDb, mockRedis := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	now := time.Now()
@@ -43,19 +39,20 @@ func TestRecordTransaction(t *testing.T) {
 		Currency:    "GBP",
 		MerchantRaw: "Test Merchant",
 		Status:      "AUTHORIZED",
+		HoldId:      "hold-1",
 	}
 
 	// Mock DB INSERT query
-	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO transactions (id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, created_at`)).
-		WithArgs(sqlmock.AnyArg(), req.AccountId, sql.NullString{String: req.CardId, Valid: true}, req.Amount, req.Currency, sql.NullString{Valid: false}, sql.NullString{String: req.MerchantRaw, Valid: true}, req.Status, sqlmock.AnyArg()).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "status", "created_at"}).
-			AddRow("txn-xyz", req.AccountId, sql.NullString{String: req.CardId, Valid: true}, req.Amount, req.Currency, sql.NullString{Valid: false}, sql.NullString{String: req.MerchantRaw, Valid: true}, req.Status, now))
-
-	// Mock Redis XAdd command
-	mockRedis.ExpectXAdd(&redis.XAddArgs{
-		Stream: "transaction:created",
-		Values: map[string]interface{}{"payload": redismock.AnyValue},
-	}).SetVal("some-stream-id")
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO transactions (id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, hold_id, original_transaction_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, status, hold_id, original_transaction_id, created_at`)).
+		WithArgs(sqlmock.AnyArg(), req.AccountId, sql.NullString{String: req.CardId, Valid: true}, req.Amount, req.Currency, sql.NullString{Valid: false}, sql.NullString{String: req.MerchantRaw, Valid: true}, req.Status, sql.NullString{String: req.HoldId, Valid: true}, sql.NullString{}, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "status", "hold_id", "original_transaction_id", "created_at"}).
+			AddRow("txn-xyz", req.AccountId, sql.NullString{String: req.CardId, Valid: true}, req.Amount, req.Currency, sql.NullString{Valid: false}, sql.NullString{String: req.MerchantRaw, Valid: true}, req.Status, sql.NullString{String: req.HoldId, Valid: true}, sql.NullString{}, now))
+	// Mock the transaction:created event queued in the outbox
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs("transaction:created", "txn-xyz", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
 
 	ctx := context.Background()
 	resp, err := s.RecordTransaction(ctx, req)
@@ -69,14 +66,148 @@ func TestRecordTransaction(t *testing.T) {
 	assert.Equal(t, req.Currency, resp.Currency)
 	assert.Equal(t, req.MerchantRaw, resp.MerchantRaw)
 	assert.Equal(t, req.Status, resp.Status)
+	assert.Equal(t, req.HoldId, resp.HoldId)
 	assert.Equal(t, now.Format(time.RFC3339), resp.Timestamp)
 
 	assert.NoError(t, mockDb.ExpectationsWereMet())
-	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestRecordTransaction_Refund(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	now := time.Now()
+	req := &transactionspb.TransactionInput{
+		AccountId:             "acc-123",
+		CardId:                "card-abc",
+		Amount:                -2000,
+		Currency:              "GBP",
+		MerchantRaw:           "Test Merchant",
+		Status:                "PENDING",
+		OriginalTransactionId: "txn-orig",
+	}
+
+	mockDb.ExpectBegin()
+	// 20.00 of the 50.00 original has already been refunded, so this refund leaves 10.00
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT account_id, currency, amount, status, refunded_amount FROM transactions WHERE id = $1 FOR UPDATE`)).
+		WithArgs(req.OriginalTransactionId).
+		WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "amount", "status", "refunded_amount"}).
+			AddRow(req.AccountId, req.Currency, int64(5000), "PARTIALLY_REFUNDED", int64(2000)))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE transactions SET refunded_amount = $1, status = $2 WHERE id = $3`)).
+		WithArgs(int64(4000), "PARTIALLY_REFUNDED", req.OriginalTransactionId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO transactions`)).
+		WithArgs(sqlmock.AnyArg(), req.AccountId, sql.NullString{String: req.CardId, Valid: true}, req.Amount, req.Currency, sql.NullString{Valid: false}, sql.NullString{String: req.MerchantRaw, Valid: true}, req.Status, sql.NullString{}, sql.NullString{String: req.OriginalTransactionId, Valid: true}, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "status", "hold_id", "original_transaction_id", "created_at"}).
+			AddRow("txn-refund", req.AccountId, sql.NullString{String: req.CardId, Valid: true}, req.Amount, req.Currency, sql.NullString{Valid: false}, sql.NullString{String: req.MerchantRaw, Valid: true}, req.Status, sql.NullString{}, sql.NullString{String: req.OriginalTransactionId, Valid: true}, now))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs("transaction:created", "txn-refund", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.RecordTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-refund", resp.Id)
+	assert.Equal(t, req.OriginalTransactionId, resp.OriginalTransactionId)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRecordTransaction_RefundExceedsOriginal(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &transactionspb.TransactionInput{
+		AccountId:             "acc-123",
+		Amount:                -3500,
+		Currency:              "GBP",
+		Status:                "PENDING",
+		OriginalTransactionId: "txn-orig",
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT account_id, currency, amount, status, refunded_amount FROM transactions WHERE id = $1 FOR UPDATE`)).
+		WithArgs(req.OriginalTransactionId).
+		WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "amount", "status", "refunded_amount"}).
+			AddRow(req.AccountId, req.Currency, int64(5000), "PARTIALLY_REFUNDED", int64(2000)))
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.RecordTransaction(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRecordTransaction_IdempotentReplay(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &transactionspb.TransactionInput{
+		AccountId:      "acc-123",
+		Amount:         12345,
+		Currency:       "GBP",
+		Status:         "AUTHORIZED",
+		IdempotencyKey: "auth-1",
+	}
+	requestHash, err := idempotency.Hash(req)
+	assert.NoError(t, err)
+	stored, err := proto.Marshal(&transactionspb.Transaction{Id: "txn-xyz", AccountId: req.AccountId, Amount: req.Amount})
+	assert.NoError(t, err)
+
+	// The key was claimed by an earlier attempt, so nothing is inserted and no event is published
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`)).
+		WithArgs(recordTransactionScope, req.IdempotencyKey, requestHash).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT request_hash, response FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`)).
+		WithArgs(recordTransactionScope, req.IdempotencyKey).
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow(requestHash, stored))
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.RecordTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-xyz", resp.Id)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRecordTransaction_IdempotencyKeyReused(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &transactionspb.TransactionInput{AccountId: "acc-123", Amount: 500, Currency: "GBP", Status: "AUTHORIZED", IdempotencyKey: "auth-1"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`)).
+		WithArgs(recordTransactionScope, req.IdempotencyKey, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT request_hash, response FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`)).
+		WithArgs(recordTransactionScope, req.IdempotencyKey).
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow("hash-of-another-request", []byte{}))
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.RecordTransaction(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
 }
 
 func TestGetTransaction_Found(t *testing.T) {
-	s, mockDb, _ := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	now := time.Now()
@@ -95,10 +226,10 @@ func TestGetTransaction_Found(t *testing.T) {
 	}
 
 	// Mock DB SELECT query
-	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at FROM transactions WHERE id = $1`)).
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at FROM transactions WHERE id = $1`)).
 		WithArgs(req.Id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "created_at"}).
-			AddRow(expectedTxn.Id, expectedTxn.AccountId, sql.NullString{String: expectedTxn.CardId, Valid: true}, expectedTxn.Amount, expectedTxn.Currency, sql.NullString{String: expectedTxn.MerchantId, Valid: true}, sql.NullString{String: expectedTxn.MerchantRaw, Valid: true}, sql.NullString{String: expectedTxn.Category, Valid: true}, expectedTxn.Status, now))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "hold_id", "billing_currency", "billing_amount", "fx_rate", "fx_fee", "auth_code", "original_transaction_id", "refunded_amount", "created_at"}).
+			AddRow(expectedTxn.Id, expectedTxn.AccountId, sql.NullString{String: expectedTxn.CardId, Valid: true}, expectedTxn.Amount, expectedTxn.Currency, sql.NullString{String: expectedTxn.MerchantId, Valid: true}, sql.NullString{String: expectedTxn.MerchantRaw, Valid: true}, sql.NullString{String: expectedTxn.Category, Valid: true}, expectedTxn.Status, sql.NullString{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullString{}, int64(0), now))
 
 	ctx := context.Background()
 	resp, err := s.GetTransaction(ctx, req)
@@ -111,13 +242,13 @@ func TestGetTransaction_Found(t *testing.T) {
 }
 
 func TestGetTransaction_NotFound(t *testing.T) {
-	s, mockDb, _ := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	req := &transactionspb.TransactionQuery{Id: "txn-unknown"}
 
 	// Mock DB SELECT query to return no rows
-	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at FROM transactions WHERE id = $1`)).
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at FROM transactions WHERE id = $1`)).
 		WithArgs(req.Id).
 		WillReturnError(sql.ErrNoRows)
 
@@ -134,18 +265,18 @@ func TestGetTransaction_NotFound(t *testing.T) {
 }
 
 func TestListTransactions(t *testing.T) {
-	s, mockDb, _ := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	now := time.Now()
 	req := &transactionspb.TransactionsQuery{AccountId: "acc-123", Limit: 10}
 
 	// Mock DB SELECT query
-	rows := sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "created_at"}).
-		AddRow("txn-1", req.AccountId, sql.NullString{String: "card-abc", Valid: true}, 5000, "GBP", sql.NullString{}, sql.NullString{String: "Merchant 1", Valid: true}, sql.NullString{}, "SETTLED", now.Add(-1*time.Hour)).
-		AddRow("txn-2", req.AccountId, sql.NullString{String: "card-abc", Valid: true}, 2500, "GBP", sql.NullString{}, sql.NullString{String: "Merchant 2", Valid: true}, sql.NullString{}, "AUTHORIZED", now)
+	rows := sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "hold_id", "billing_currency", "billing_amount", "fx_rate", "fx_fee", "auth_code", "original_transaction_id", "refunded_amount", "created_at"}).
+		AddRow("txn-1", req.AccountId, sql.NullString{String: "card-abc", Valid: true}, 5000, "GBP", sql.NullString{}, sql.NullString{String: "Merchant 1", Valid: true}, sql.NullString{}, "SETTLED", sql.NullString{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullString{}, int64(0), now.Add(-1*time.Hour)).
+		AddRow("txn-2", req.AccountId, sql.NullString{String: "card-abc", Valid: true}, 2500, "GBP", sql.NullString{}, sql.NullString{String: "Merchant 2", Valid: true}, sql.NullString{}, "AUTHORIZED", sql.NullString{String: "hold-2", Valid: true}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullString{}, int64(0), now)
 
-	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at FROM transactions WHERE account_id = $1 ORDER BY created_at DESC LIMIT 10`)).
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at FROM transactions WHERE account_id = $1 ORDER BY created_at DESC LIMIT 10`)).
 		WithArgs(req.AccountId).
 		WillReturnRows(rows)
 
@@ -162,7 +293,7 @@ func TestListTransactions(t *testing.T) {
 }
 
 func TestUpdateTransaction(t *testing.T) {
-	s, mockDb, _ := newTestServer(t) // No Redis mock needed for Update
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	now := time.Now()
@@ -177,10 +308,10 @@ func TestUpdateTransaction(t *testing.T) {
 	// Mock DB UPDATE query
 	// Note: The query is built dynamically, so matching exactly is tricky.
 	// We'll match the core part and check arguments.
-	mockDb.ExpectQuery(`UPDATE transactions SET merchant_id = \$1, merchant_name = \$2, category = \$3, status = \$4 WHERE id = \$5 RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, created_at`). // Use regex for flexibility
-																															WithArgs(req.MerchantId, req.MerchantName, req.Category, req.Status, req.Id).
-																															WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "created_at"}).
-																																AddRow(req.Id, "acc-123", sql.NullString{String: "card-abc", Valid: true}, 5000, "GBP", sql.NullString{String: req.MerchantId, Valid: true}, sql.NullString{String: "Raw Name", Valid: true}, sql.NullString{String: req.Category, Valid: true}, req.Status, now))
+	mockDb.ExpectQuery(`UPDATE transactions SET merchant_id = \$1, merchant_name = \$2, category = \$3, status = \$4 WHERE id = \$5 RETURNING id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at`). // Use regex for flexibility
+																																														WithArgs(req.MerchantId, req.MerchantName, req.Category, req.Status, req.Id).
+																																														WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "hold_id", "billing_currency", "billing_amount", "fx_rate", "fx_fee", "auth_code", "original_transaction_id", "refunded_amount", "created_at"}).
+																																															AddRow(req.Id, "acc-123", sql.NullString{String: "card-abc", Valid: true}, 5000, "GBP", sql.NullString{String: req.MerchantId, Valid: true}, sql.NullString{String: "Raw Name", Valid: true}, sql.NullString{String: req.Category, Valid: true}, req.Status, sql.NullString{String: "hold-1", Valid: true}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullString{}, int64(0), now))
 
 	ctx := context.Background()
 	resp, err := s.UpdateTransaction(ctx, req)
@@ -194,3 +325,109 @@ func TestUpdateTransaction(t *testing.T) {
 
 	assert.NoError(t, mockDb.ExpectationsWereMet())
 }
+
+func TestUpdateTransaction_RecordsConversion(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	now := time.Now()
+	req := &transactionspb.UpdateTransactionRequest{
+		Id:              "txn-abc",
+		Status:          "AUTHORIZED",
+		HoldId:          "hold-1",
+		BillingCurrency: "GBP",
+		BillingAmount:   1075,
+		FxRate:          "0.8712",
+		FxFee:           30,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`UPDATE transactions SET status = $1, hold_id = $2, billing_currency = $3, billing_amount = $4, fx_rate = $5, fx_fee = $6 WHERE id = $7 RETURNING`)).
+		WithArgs(req.Status, req.HoldId, req.BillingCurrency, req.BillingAmount, req.FxRate, req.FxFee, req.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "hold_id", "billing_currency", "billing_amount", "fx_rate", "fx_fee", "auth_code", "original_transaction_id", "refunded_amount", "created_at"}).
+			AddRow(req.Id, "acc-123", sql.NullString{String: "card-abc", Valid: true}, 1234, "EUR", sql.NullString{}, sql.NullString{String: "Raw Name", Valid: true}, sql.NullString{}, req.Status, sql.NullString{String: req.HoldId, Valid: true},
+				sql.NullString{String: "GBP", Valid: true}, sql.NullInt64{Int64: 1075, Valid: true}, sql.NullString{String: "0.8712", Valid: true}, sql.NullInt64{Int64: 30, Valid: true}, sql.NullString{}, sql.NullString{}, int64(0), now))
+
+	ctx := context.Background()
+	resp, err := s.UpdateTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", resp.Currency)
+	assert.Equal(t, "GBP", resp.BillingCurrency)
+	assert.Equal(t, int64(1075), resp.BillingAmount)
+	assert.Equal(t, "0.8712", resp.FxRate)
+	assert.Equal(t, int64(30), resp.FxFee)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateTransaction_SettlesAmount(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	now := time.Now()
+	req := &transactionspb.UpdateTransactionRequest{
+		Id:     "txn-abc",
+		Status: "SETTLED",
+		Amount: 5750,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`UPDATE transactions SET status = $1, amount = $2 WHERE id = $3 RETURNING`)).
+		WithArgs(req.Status, req.Amount, req.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "hold_id", "billing_currency", "billing_amount", "fx_rate", "fx_fee", "auth_code", "original_transaction_id", "refunded_amount", "created_at"}).
+			AddRow(req.Id, "acc-123", sql.NullString{String: "card-abc", Valid: true}, req.Amount, "GBP", sql.NullString{}, sql.NullString{String: "Raw Name", Valid: true}, sql.NullString{}, req.Status, sql.NullString{String: "hold-1", Valid: true},
+				sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{String: "A1B2C3", Valid: true}, sql.NullString{}, int64(0), now))
+
+	ctx := context.Background()
+	resp, err := s.UpdateTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5750), resp.Amount)
+	assert.Equal(t, "SETTLED", resp.Status)
+	assert.Equal(t, "A1B2C3", resp.AuthCode)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetTransactionByAuthCode(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	now := time.Now()
+	req := &transactionspb.AuthCodeQuery{CardId: "card-abc", AuthCode: "A1B2C3"}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM transactions WHERE card_id = $1 AND auth_code = $2 ORDER BY created_at DESC LIMIT 1`)).
+		WithArgs(req.CardId, req.AuthCode).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("txn-abc"))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_id, card_id, amount, currency, merchant_id, merchant_raw, category, status, hold_id, billing_currency, billing_amount, fx_rate, fx_fee, auth_code, original_transaction_id, refunded_amount, created_at FROM transactions WHERE id = $1`)).
+		WithArgs("txn-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "card_id", "amount", "currency", "merchant_id", "merchant_raw", "category", "status", "hold_id", "billing_currency", "billing_amount", "fx_rate", "fx_fee", "auth_code", "original_transaction_id", "refunded_amount", "created_at"}).
+			AddRow("txn-abc", "acc-123", sql.NullString{String: req.CardId, Valid: true}, 5000, "GBP", sql.NullString{}, sql.NullString{String: "Raw Name", Valid: true}, sql.NullString{}, "AUTHORIZED", sql.NullString{String: "hold-1", Valid: true},
+				sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{String: req.AuthCode, Valid: true}, sql.NullString{}, int64(0), now))
+
+	ctx := context.Background()
+	resp, err := s.GetTransactionByAuthCode(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-abc", resp.Id)
+	assert.Equal(t, "hold-1", resp.HoldId)
+	assert.Equal(t, req.AuthCode, resp.AuthCode)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetTransactionByAuthCode_NotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &transactionspb.AuthCodeQuery{CardId: "card-abc", AuthCode: "ZZZZZZ"}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM transactions WHERE card_id = $1 AND auth_code = $2`)).
+		WithArgs(req.CardId, req.AuthCode).
+		WillReturnError(sql.ErrNoRows)
+
+	ctx := context.Background()
+	_, err := s.GetTransactionByAuthCode(ctx, req)
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/mtls"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// detokenizePolicy grants callers the card details they need for each of their purposes, and no more.
+// Anything not granted is denied.
+type detokenizePolicy struct {
+	Grants []detokenizeGrant `json:"grants"`
+}
+
+type detokenizeGrant struct {
+	Caller   string   `json:"caller"`
+	Purposes []string `json:"purposes"`
+	Fields   []string `json:"fields"` // card details the caller gets: "pan", "cvv" and "pin"
+}
+
+// Card details a grant can give
+const (
+	fieldPAN = "pan"
+	fieldCVV = "cvv"
+	fieldPIN = "pin"
+)
+
+var grantableFields = map[string]bool{fieldPAN: true, fieldCVV: true, fieldPIN: true}
+
+// loadDetokenizePolicy reads the detokenize policy from a JSON file
+func loadDetokenizePolicy(path string) (*detokenizePolicy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy detokenizePolicy
+	if err := json.Unmarshal(b, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, g := range policy.Grants {
+		if g.Caller == "" || len(g.Purposes) == 0 || len(g.Fields) == 0 {
+			return nil, fmt.Errorf("%s: grants need a caller, at least one purpose and at least one field", path)
+		}
+		for _, field := range g.Fields {
+			if !grantableFields[field] {
+				return nil, fmt.Errorf("%s: unknown field %q granted to %s", path, field, g.Caller)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// grant returns what caller is granted for purpose, if anything
+func (p *detokenizePolicy) grant(caller, purpose string) (detokenizeGrant, bool) {
+	for _, g := range p.Grants {
+		if g.Caller != caller {
+			continue
+		}
+		for _, granted := range g.Purposes {
+			if granted == purpose {
+				return g, true
+			}
+		}
+	}
+	return detokenizeGrant{}, false
+}
+
+// allows reports whether g gives the caller field
+func (g detokenizeGrant) allows(field string) bool {
+	for _, granted := range g.Fields {
+		if granted == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Detokenize returns the card details behind a token to a caller granted them for the purpose given.
+// The caller is the service named by the client certificate it connected with, see pkg/mtls.
+// Every request is recorded in the audit trail before it is answered, whether or not it is granted,
+// and nothing is returned if it can't be recorded.
+func (s *server) Detokenize(ctx context.Context, req *vaultpb.DetokenizeRequest) (*vaultpb.CardData, error) {
+	caller, ok := mtls.PeerIdentity(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "a client certificate is required")
+	}
+	log.Printf("Received Detokenize request: token %s, caller %s, purpose %s", req.GetToken(), caller, req.GetPurpose())
+
+	if req.GetToken() == "" || req.GetPurpose() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token and purpose are required")
+	}
+
+	grant, allowed := s.policy.grant(caller, req.GetPurpose())
+	query := `INSERT INTO detokenize_audit (token, caller, purpose, allowed, created_at) VALUES ($1, $2, $3, $4, NOW())`
+	if _, err := s.db.ExecContext(ctx, query, req.GetToken(), caller, req.GetPurpose(), allowed); err != nil {
+		log.Printf("failed to record detokenize audit entry: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to detokenize")
+	}
+	if !allowed {
+		log.Printf("denied detokenize of %s to %s for %s", req.GetToken(), caller, req.GetPurpose())
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not granted card details for %s", caller, req.GetPurpose())
+	}
+
+	data, err := s.loadCardData(ctx, s.db, `SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1`, req.GetToken())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "token not found")
+		}
+		log.Printf("failed to get vault entry %s: %v", req.GetToken(), err)
+		return nil, status.Errorf(codes.Internal, "failed to detokenize")
+	}
+
+	granted := &vaultpb.CardData{}
+	if grant.allows(fieldPAN) {
+		granted.Pan = data.GetPan()
+	}
+	if grant.allows(fieldCVV) {
+		granted.Cvv = data.GetCvv()
+	}
+	if grant.allows(fieldPIN) {
+		granted.PinBlock = data.GetPinBlock()
+	}
+	return granted, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/mtls"
+	"github.com/manifoldfinance/disco2/v2/pkg/pan"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// cvvPattern matches a card verification value
+var cvvPattern = regexp.MustCompile(`^[0-9]{3,4}$`)
+
+// The vault has no HTTP routes: card details only leave it over gRPC, to callers that are granted them.
+// Requests are never logged in full, as they carry card numbers and PIN blocks.
+type server struct {
+	vaultpb.UnimplementedVaultServer
+	db             *sql.DB
+	kms            kms
+	fingerprintKey []byte
+	policy         *detokenizePolicy
+	newToken       func() string
+}
+
+func main() {
+	keyFilePath := flag.String("key-file", "", "JSON file with the key-encryption keys, the local stand-in for a KMS")
+	addKey := flag.Bool("add-key", false, "add a new key-encryption key version to -key-file and exit; call RotateKeys once the vault is restarted")
+	fingerprintKeyPath := flag.String("fingerprint-key", "", "file with the secret key card numbers are hashed with to look them up")
+	policyPath := flag.String("detokenize-policy", "vault/detokenize.json", "JSON file granting callers card details")
+	tlsCertPath := flag.String("tls-cert", "", "file with the vault's TLS certificate")
+	tlsKeyPath := flag.String("tls-key", "", "file with the key of -tls-cert")
+	clientCAPath := flag.String("client-ca", "", "file with the CA certificate callers' client certificates are signed with")
+	flag.Parse()
+
+	if *keyFilePath == "" {
+		log.Fatalf("-key-file is required")
+	}
+	if *addKey {
+		version, err := addKeyVersion(*keyFilePath)
+		if err != nil {
+			log.Fatalf("failed to add key: %v", err)
+		}
+		log.Printf("Added key version %d to %s", version, *keyFilePath)
+		return
+	}
+
+	keys, err := loadLocalKMS(*keyFilePath)
+	if err != nil {
+		log.Fatalf("failed to load keys: %v", err)
+	}
+	if *fingerprintKeyPath == "" {
+		log.Fatalf("-fingerprint-key is required")
+	}
+	fingerprintKey, err := os.ReadFile(*fingerprintKeyPath)
+	if err != nil {
+		log.Fatalf("failed to read fingerprint key: %v", err)
+	}
+	policy, err := loadDetokenizePolicy(*policyPath)
+	if err != nil {
+		log.Fatalf("failed to load detokenize policy: %v", err)
+	}
+	// Callers are only ever known by their client certificates
+	if *tlsCertPath == "" || *tlsKeyPath == "" || *clientCAPath == "" {
+		log.Fatalf("-tls-cert, -tls-key and -client-ca are required")
+	}
+	creds, err := mtls.ServerCredentials(*tlsCertPath, *tlsKeyPath, *clientCAPath)
+	if err != nil {
+		log.Fatalf("failed to load TLS credentials: %v", err)
+	}
+
+	// Database connection setup (placeholder)
+	db, err := sql.Open("postgres", "user=user dbname=vault sslmode=disable")
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Auto-migrate schema (for development/testing)
+	// In production, use proper schema migration tools
+	schemaSQL, err := os.ReadFile("vault/schema.sql")
+	if err != nil {
+		log.Fatalf("failed to read schema file: %v", err)
+	}
+	if _, err := db.Exec(string(schemaSQL)); err != nil {
+		log.Fatalf("failed to execute schema: %v", err)
+	}
+	log.Println("Database schema applied successfully")
+
+	s := &server{
+		db:             db,
+		kms:            keys,
+		fingerprintKey: fingerprintKey,
+		policy:         policy,
+		newToken:       func() string { return uuid.New().String() },
+	}
+
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	vaultpb.RegisterVaultServer(grpcServer, s)
+
+	lis, err := net.Listen("tcp", ":50060")
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	log.Printf("gRPC server listening on %v (primary key version %d)", lis.Addr(), keys.PrimaryVersion())
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
+
+func (s *server) Tokenize(ctx context.Context, req *vaultpb.TokenizeRequest) (*vaultpb.Token, error) {
+	log.Printf("Received Tokenize request for card ending %s", pan.LastFour(req.GetPan()))
+
+	if !pan.Valid(req.GetPan()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid card number")
+	}
+	if !cvvPattern.MatchString(req.GetCvv()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid CVV")
+	}
+
+	token, err := s.storeCardData(ctx, req.GetPan(), req.GetCvv())
+	if err != nil {
+		if err == errCardNumberTaken {
+			return nil, status.Errorf(codes.AlreadyExists, "card number already in vault")
+		}
+		log.Printf("failed to store card data: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to tokenize card")
+	}
+
+	return &vaultpb.Token{Token: token}, nil
+}
+
+// errCardNumberTaken is returned by storeCardData for a card number that is already in the vault
+var errCardNumberTaken = errors.New("card number already in vault")
+
+// storeCardData encrypts a card's number and CVV and stores them under a new token, which it returns
+func (s *server) storeCardData(ctx context.Context, number, cvv string) (string, error) {
+	token := s.newToken()
+	ciphertext, wrappedKey, keyVersion, err := s.sealCardData(token, &vaultpb.CardData{Pan: number, Cvv: cvv})
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt card data: %w", err)
+	}
+
+	query := `INSERT INTO vault_entries (token, fingerprint, ciphertext, wrapped_key, key_version, created_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW()) ON CONFLICT (fingerprint) DO NOTHING RETURNING token`
+
+	var inserted string
+	err = s.db.QueryRowContext(ctx, query, token, s.fingerprint(number), ciphertext, wrappedKey, keyVersion).Scan(&inserted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errCardNumberTaken
+		}
+		return "", fmt.Errorf("failed to insert vault entry: %w", err)
+	}
+	return inserted, nil
+}
+
+func (s *server) LookupToken(ctx context.Context, req *vaultpb.LookupTokenRequest) (*vaultpb.Token, error) {
+	log.Printf("Received LookupToken request for card ending %s", pan.LastFour(req.GetPan()))
+
+	if !pan.Valid(req.GetPan()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid card number")
+	}
+
+	var token string
+	err := s.db.QueryRowContext(ctx, `SELECT token FROM vault_entries WHERE fingerprint = $1`, s.fingerprint(req.GetPan())).Scan(&token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "card number not in vault")
+		}
+		log.Printf("failed to look up token: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to look up token")
+	}
+
+	return &vaultpb.Token{Token: token}, nil
+}
+
+// fingerprint returns the keyed hash of a card number, so it can be found without decrypting every entry
+func (s *server) fingerprint(number string) string {
+	mac := hmac.New(sha256.New, s.fingerprintKey)
+	mac.Write([]byte(number))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sealCardData encrypts data with a new data key, and the data key with the primary key-encryption
+// key. The ciphertext is bound to token, so it can't be moved to another entry.
+func (s *server) sealCardData(token string, data *vaultpb.CardData) (ciphertext, wrappedKey []byte, keyVersion int32, err error) {
+	plaintext, err := proto.Marshal(data)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	dataKey, err := newKey()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if ciphertext, err = seal(dataKey, plaintext, []byte(token)); err != nil {
+		return nil, nil, 0, err
+	}
+	if wrappedKey, keyVersion, err = s.kms.WrapKey(dataKey); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return ciphertext, wrappedKey, keyVersion, nil
+}
+
+// queryRower runs a query for a single row, in or outside a transaction
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// loadCardData reads the entry with token with query, which selects its ciphertext, wrapped key and
+// key version, and decrypts its card data. It returns sql.ErrNoRows if there is no such entry.
+func (s *server) loadCardData(ctx context.Context, q queryRower, query, token string) (*vaultpb.CardData, error) {
+	var ciphertext, wrappedKey []byte
+	var keyVersion int32
+	if err := q.QueryRowContext(ctx, query, token).Scan(&ciphertext, &wrappedKey, &keyVersion); err != nil {
+		return nil, err
+	}
+	data, err := s.openCardData(token, ciphertext, wrappedKey, keyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return data, nil
+}
+
+// openCardData decrypts card data sealed by sealCardData
+func (s *server) openCardData(token string, ciphertext, wrappedKey []byte, keyVersion int32) (*vaultpb.CardData, error) {
+	dataKey, err := s.kms.UnwrapKey(wrappedKey, keyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	plaintext, err := unseal(dataKey, ciphertext, []byte(token))
+	if err != nil {
+		return nil, err
+	}
+	var data vaultpb.CardData
+	if err := proto.Unmarshal(plaintext, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
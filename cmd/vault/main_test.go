@@ -0,0 +1,493 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"database/sql/driver"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/pan"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// testPAN is a valid card number
+const testPAN = "4599650012345675"
+
+// testPinBlock is a PIN block of the PIN 1234
+const testPinBlock = "141234A5B6C7D8E9"
+
+// Helper function to create a server instance with mocks. Its KMS has key versions 1 and 2.
+func newTestServer(t *testing.T) (*server, sqlmock.Sqlmock) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	s := &server{
+		db:             db,
+		kms:            newTestKMS(t, 1, 2),
+		fingerprintKey: []byte("test-key"),
+		policy: &detokenizePolicy{Grants: []detokenizeGrant{
+			{Caller: "card-production", Purposes: []string{"emboss"}, Fields: []string{fieldPAN, fieldCVV}},
+			{Caller: "disputes", Purposes: []string{"chargeback"}, Fields: []string{fieldPAN}},
+			{Caller: "cards", Purposes: []string{"pin_reveal"}, Fields: []string{fieldPIN}},
+		}},
+		newToken: func() string { return "token-1" },
+	}
+	return s, mockDb
+}
+
+// newTestKMS returns a KMS with random keys of the versions given
+func newTestKMS(t *testing.T, versions ...int32) *localKMS {
+	k := &localKMS{keys: map[int32][]byte{}}
+	for _, v := range versions {
+		key, err := newKey()
+		assert.NoError(t, err)
+		k.keys[v] = key
+		if v > k.primary {
+			k.primary = v
+		}
+	}
+	return k
+}
+
+// capture is a query argument that matches anything and keeps the value it was given
+type capture struct{ value []byte }
+
+func (c *capture) Match(v driver.Value) bool {
+	c.value, _ = v.([]byte)
+	return true
+}
+
+func TestTokenize(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	ciphertext, wrappedKey := &capture{}, &capture{}
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO vault_entries (token, fingerprint, ciphertext, wrapped_key, key_version, created_at)`)).
+		WithArgs("token-1", s.fingerprint(testPAN), ciphertext, wrappedKey, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"token"}).AddRow("token-1"))
+
+	resp, err := s.Tokenize(context.Background(), &vaultpb.TokenizeRequest{Pan: testPAN, Cvv: "123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", resp.Token)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+
+	// Only ciphertext is stored, and it opens to the card details under the entry's token alone
+	assert.NotContains(t, string(ciphertext.value), testPAN)
+	data, err := s.openCardData("token-1", ciphertext.value, wrappedKey.value, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, testPAN, data.Pan)
+	assert.Equal(t, "123", data.Cvv)
+	_, err = s.openCardData("token-2", ciphertext.value, wrappedKey.value, 2)
+	assert.Error(t, err)
+}
+
+func TestTokenize_AlreadyInVault(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO vault_entries`)).
+		WillReturnRows(sqlmock.NewRows([]string{"token"}))
+
+	resp, err := s.Tokenize(context.Background(), &vaultpb.TokenizeRequest{Pan: testPAN, Cvv: "123"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestTokenize_Invalid(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	for _, req := range []*vaultpb.TokenizeRequest{
+		{Pan: "4599650012345674", Cvv: "123"}, // wrong check digit
+		{Pan: testPAN, Cvv: "12"},
+		{Pan: testPAN, Cvv: "abc"},
+	} {
+		resp, err := s.Tokenize(context.Background(), req)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%+v", req)
+	}
+}
+
+func TestIssueCardNumber(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// The first number generated already belongs to another card, so another is generated
+	ciphertext, wrappedKey := &capture{}, &capture{}
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO vault_entries`)).
+		WillReturnRows(sqlmock.NewRows([]string{"token"}))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO vault_entries`)).
+		WithArgs("token-1", sqlmock.AnyArg(), ciphertext, wrappedKey, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"token"}).AddRow("token-1"))
+
+	resp, err := s.IssueCardNumber(context.Background(), &vaultpb.IssueCardNumberRequest{Bin: "45996500", PanLength: 16})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", resp.Token)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+
+	// The card's number and CVV are only in the vault
+	data, err := s.openCardData("token-1", ciphertext.value, wrappedKey.value, 2)
+	assert.NoError(t, err)
+	assert.True(t, pan.Valid(data.Pan), data.Pan)
+	assert.Regexp(t, `^45996500[0-9]{8}$`, data.Pan)
+	assert.Equal(t, data.Pan[12:], resp.LastFour)
+	assert.Regexp(t, `^[0-9]{3}$`, data.Cvv)
+}
+
+func TestIssueCardNumber_Collisions(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Cards aren't issued numbers forever
+	for i := 0; i < maxIssueAttempts; i++ {
+		mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO vault_entries`)).
+			WillReturnRows(sqlmock.NewRows([]string{"token"}))
+	}
+
+	resp, err := s.IssueCardNumber(context.Background(), &vaultpb.IssueCardNumberRequest{Bin: "45996500", PanLength: 16})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestIssueCardNumber_InvalidBIN(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	for _, req := range []*vaultpb.IssueCardNumberRequest{
+		{Bin: "", PanLength: 16},
+		{Bin: "4599x500", PanLength: 16},
+		{Bin: "45996500", PanLength: 8},
+	} {
+		resp, err := s.IssueCardNumber(context.Background(), req)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%+v", req)
+	}
+}
+
+func TestLookupToken(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	query := regexp.QuoteMeta(`SELECT token FROM vault_entries WHERE fingerprint = $1`)
+	mockDb.ExpectQuery(query).
+		WithArgs(s.fingerprint(testPAN)).
+		WillReturnRows(sqlmock.NewRows([]string{"token"}).AddRow("token-1"))
+	mockDb.ExpectQuery(query).
+		WithArgs(s.fingerprint("4111111111111111")).
+		WillReturnError(sql.ErrNoRows)
+
+	resp, err := s.LookupToken(context.Background(), &vaultpb.LookupTokenRequest{Pan: testPAN})
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", resp.Token)
+
+	resp, err = s.LookupToken(context.Background(), &vaultpb.LookupTokenRequest{Pan: "4111111111111111"})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// callerContext returns the context of a call from the service named by its client certificate
+func callerContext(caller string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: caller}}
+	authInfo := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: authInfo})
+}
+
+// expectDetokenizeAudit expects a Detokenize request to be recorded in the audit trail
+func expectDetokenizeAudit(mockDb sqlmock.Sqlmock, token, caller, purpose string, allowed bool) *sqlmock.ExpectedExec {
+	return mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO detokenize_audit (token, caller, purpose, allowed, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs(token, caller, purpose, allowed)
+}
+
+func TestDetokenize(t *testing.T) {
+	// Callers only get the fields they are granted
+	tests := []struct {
+		caller  string
+		purpose string
+		want    *vaultpb.CardData
+	}{
+		{"card-production", "emboss", &vaultpb.CardData{Pan: testPAN, Cvv: "123"}},
+		{"disputes", "chargeback", &vaultpb.CardData{Pan: testPAN}},
+		{"cards", "pin_reveal", &vaultpb.CardData{PinBlock: testPinBlock}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caller, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			ciphertext, wrappedKey, keyVersion, err := s.sealCardData("token-1", &vaultpb.CardData{Pan: testPAN, Cvv: "123", PinBlock: testPinBlock})
+			assert.NoError(t, err)
+
+			expectDetokenizeAudit(mockDb, "token-1", tt.caller, tt.purpose, true).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1`)).
+				WithArgs("token-1").
+				WillReturnRows(sqlmock.NewRows([]string{"ciphertext", "wrapped_key", "key_version"}).AddRow(ciphertext, wrappedKey, keyVersion))
+
+			resp, err := s.Detokenize(callerContext(tt.caller), &vaultpb.DetokenizeRequest{Token: "token-1", Purpose: tt.purpose})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Pan, resp.Pan)
+			assert.Equal(t, tt.want.Cvv, resp.Cvv)
+			assert.Equal(t, tt.want.PinBlock, resp.PinBlock)
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDetokenize_Denied(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Callers are only granted card details for their own purposes, and denials are audited too
+	expectDetokenizeAudit(mockDb, "token-1", "disputes", "emboss", false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectDetokenizeAudit(mockDb, "token-1", "card-api", "emboss", false).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	resp, err := s.Detokenize(callerContext("disputes"), &vaultpb.DetokenizeRequest{Token: "token-1", Purpose: "emboss"})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	resp, err = s.Detokenize(callerContext("card-api"), &vaultpb.DetokenizeRequest{Token: "token-1", Purpose: "emboss"})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestDetokenize_NoClientCertificate(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Callers are only known by their client certificates, so calls without one are refused outright
+	resp, err := s.Detokenize(context.Background(), &vaultpb.DetokenizeRequest{Token: "token-1", Purpose: "emboss"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestDetokenize_AuditFails(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Nothing is returned that isn't in the audit trail
+	expectDetokenizeAudit(mockDb, "token-1", "card-production", "emboss", true).
+		WillReturnError(sql.ErrConnDone)
+
+	resp, err := s.Detokenize(callerContext("card-production"), &vaultpb.DetokenizeRequest{Token: "token-1", Purpose: "emboss"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRotateKeys(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// The entry was sealed when key version 1 was the primary
+	keys := s.kms.(*localKMS)
+	keys.primary = 1
+	ciphertext, wrappedKey, keyVersion, err := s.sealCardData("token-1", &vaultpb.CardData{Pan: testPAN, Cvv: "123"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), keyVersion)
+	keys.primary = 2
+
+	newCiphertext, newWrappedKey := &capture{}, &capture{}
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT token, ciphertext, wrapped_key, key_version FROM vault_entries WHERE key_version <> $1`)).
+		WithArgs(2, defaultRotateBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"token", "ciphertext", "wrapped_key", "key_version"}).AddRow("token-1", ciphertext, wrappedKey, keyVersion))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE vault_entries SET ciphertext = $2, wrapped_key = $3, key_version = $4, rotated_at = NOW() WHERE token = $1`)).
+		WithArgs("token-1", newCiphertext, newWrappedKey, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM vault_entries WHERE key_version <> $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	resp, err := s.RotateKeys(context.Background(), &vaultpb.RotateKeysRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), resp.KeyVersion)
+	assert.Equal(t, int32(1), resp.Reencrypted)
+	assert.Equal(t, int64(0), resp.Remaining)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+
+	// The entry opens without the old key
+	delete(keys.keys, 1)
+	data, err := s.openCardData("token-1", newCiphertext.value, newWrappedKey.value, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, testPAN, data.Pan)
+	assert.NotEqual(t, ciphertext, newCiphertext.value)
+}
+
+func TestRotateKeys_InvalidBatchSize(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	resp, err := s.RotateKeys(context.Background(), &vaultpb.RotateKeysRequest{BatchSize: maxRotateBatchSize + 1})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLocalKMS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	// Adding a key creates the key file, and each key added becomes the primary
+	version, err := addKeyVersion(path)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), version)
+	k1, err := loadLocalKMS(path)
+	assert.NoError(t, err)
+	wrapped, wrappedVersion, err := k1.WrapKey([]byte("data key"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), wrappedVersion)
+
+	version, err = addKeyVersion(path)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), version)
+	k2, err := loadLocalKMS(path)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), k2.PrimaryVersion())
+
+	// Keys wrapped under older versions can still be unwrapped
+	dataKey, err := k2.UnwrapKey(wrapped, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data key"), dataKey)
+	_, err = k2.UnwrapKey(wrapped, 2)
+	assert.Error(t, err)
+	_, err = k2.UnwrapKey(wrapped, 3)
+	assert.Error(t, err)
+}
+
+func TestLoadDetokenizePolicy(t *testing.T) {
+	policy, err := loadDetokenizePolicy("../../internal/vault/detokenize.json")
+	assert.NoError(t, err)
+
+	grant, ok := policy.grant("card-production", "emboss")
+	assert.True(t, ok)
+	assert.True(t, grant.allows(fieldCVV))
+	assert.False(t, grant.allows(fieldPIN))
+	_, ok = policy.grant("card-production", "chargeback")
+	assert.False(t, ok)
+
+	// Fields the vault doesn't hold can't be granted
+	path := filepath.Join(t.TempDir(), "detokenize.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"grants": [{"caller": "disputes", "purposes": ["chargeback"], "fields": ["expiry"]}]}`), 0o600))
+	_, err = loadDetokenizePolicy(path)
+	assert.Error(t, err)
+}
+
+// expectEntry expects a vault entry to be read with query, returning data sealed under token
+func expectEntry(t *testing.T, s *server, mockDb sqlmock.Sqlmock, query, token string, data *vaultpb.CardData) {
+	ciphertext, wrappedKey, keyVersion, err := s.sealCardData(token, data)
+	assert.NoError(t, err)
+	mockDb.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(token).
+		WillReturnRows(sqlmock.NewRows([]string{"ciphertext", "wrapped_key", "key_version"}).AddRow(ciphertext, wrappedKey, keyVersion))
+}
+
+func TestSetPinBlock(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// The PIN block is sealed in with the card's other details
+	newCiphertext, newWrappedKey := &capture{}, &capture{}
+	mockDb.ExpectBegin()
+	expectEntry(t, s, mockDb, `SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1 FOR UPDATE`, "token-1",
+		&vaultpb.CardData{Pan: testPAN, Cvv: "123"})
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE vault_entries SET ciphertext = $2, wrapped_key = $3, key_version = $4 WHERE token = $1`)).
+		WithArgs("token-1", newCiphertext, newWrappedKey, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	resp, err := s.SetPinBlock(context.Background(), &vaultpb.SetPinBlockRequest{Token: "token-1", PinBlock: testPinBlock})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+
+	data, err := s.openCardData("token-1", newCiphertext.value, newWrappedKey.value, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, testPAN, data.Pan)
+	assert.Equal(t, "123", data.Cvv)
+	assert.Equal(t, testPinBlock, data.PinBlock)
+}
+
+func TestSetPinBlock_Invalid(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	for _, block := range []string{"", "1234", "041234FFFFFFFFFF"} {
+		resp, err := s.SetPinBlock(context.Background(), &vaultpb.SetPinBlockRequest{Token: "token-1", PinBlock: block})
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), block)
+	}
+}
+
+func TestVerifyPinBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		pinBlock string
+		match    bool
+	}{
+		{"same block", testPinBlock, true},
+		{"same PIN, other fill", "1412340000000000", true},
+		{"other PIN", "141235A5B6C7D8E9", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			expectEntry(t, s, mockDb, `SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1`, "token-1",
+				&vaultpb.CardData{Pan: testPAN, Cvv: "123", PinBlock: testPinBlock})
+
+			resp, err := s.VerifyPinBlock(context.Background(), &vaultpb.VerifyPinBlockRequest{Token: "token-1", PinBlock: tt.pinBlock})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.match, resp.Match)
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestVerifyPinBlock_NoPin(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	expectEntry(t, s, mockDb, `SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1`, "token-1",
+		&vaultpb.CardData{Pan: testPAN, Cvv: "123"})
+
+	resp, err := s.VerifyPinBlock(context.Background(), &vaultpb.VerifyPinBlockRequest{Token: "token-1", PinBlock: testPinBlock})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+	"github.com/manifoldfinance/disco2/v2/pkg/pinblock"
+)
+
+// SetPinBlock seals a PIN block into a card's entry with its other details, so key rotation and the
+// detokenize audit trail cover it too. Whether a card may have its PIN set is up to Cards.
+func (s *server) SetPinBlock(ctx context.Context, req *vaultpb.SetPinBlockRequest) (*vaultpb.SetPinBlockResponse, error) {
+	log.Printf("Received SetPin request for token %s", req.GetToken())
+
+	if _, err := pinblock.Decode(req.GetPinBlock()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid PIN block")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	query := `SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1 FOR UPDATE`
+	data, err := s.loadCardData(ctx, tx, query, req.GetToken())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "token not found")
+		}
+		log.Printf("failed to get vault entry %s: %v", req.GetToken(), err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+
+	data.PinBlock = req.GetPinBlock()
+	ciphertext, wrappedKey, keyVersion, err := s.sealCardData(req.GetToken(), data)
+	if err != nil {
+		log.Printf("failed to encrypt vault entry %s: %v", req.GetToken(), err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+	update := `UPDATE vault_entries SET ciphertext = $2, wrapped_key = $3, key_version = $4 WHERE token = $1`
+	if _, err := tx.ExecContext(ctx, update, req.GetToken(), ciphertext, wrappedKey, keyVersion); err != nil {
+		log.Printf("failed to update vault entry %s: %v", req.GetToken(), err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set PIN")
+	}
+
+	return &vaultpb.SetPinBlockResponse{}, nil
+}
+
+// VerifyPinBlock reports whether a PIN block carries a card's PIN. Counting wrong PINs is up to Cards.
+func (s *server) VerifyPinBlock(ctx context.Context, req *vaultpb.VerifyPinBlockRequest) (*vaultpb.VerifyPinBlockResponse, error) {
+	log.Printf("Received VerifyPin request for token %s", req.GetToken())
+
+	if _, err := pinblock.Decode(req.GetPinBlock()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid PIN block")
+	}
+
+	data, err := s.loadCardData(ctx, s.db, `SELECT ciphertext, wrapped_key, key_version FROM vault_entries WHERE token = $1`, req.GetToken())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "token not found")
+		}
+		log.Printf("failed to get vault entry %s: %v", req.GetToken(), err)
+		return nil, status.Errorf(codes.Internal, "failed to verify PIN")
+	}
+	if data.GetPinBlock() == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "card has no PIN")
+	}
+
+	return &vaultpb.VerifyPinBlockResponse{Match: pinblock.Match(data.GetPinBlock(), req.GetPinBlock())}, nil
+}
@@ -0,0 +1,603 @@
+// Package service contains the business logic for the accounts service
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+// Account types
+const (
+	accountPersonal = "personal"
+	accountJoint    = "joint"
+	accountBusiness = "business"
+)
+
+// accountOpen is the status of an open account; closed accounts are "CLOSED"
+const accountOpen = "OPEN"
+
+// jointHolders is how many holders a joint account has
+const jointHolders = 2
+
+// defaultCurrency is the currency of accounts created without one
+const defaultCurrency = "GBP"
+
+// selectAccountQuery selects the columns read by scanAccount
+const selectAccountQuery = `SELECT account_id, account_type, name, currency, status, created_at FROM accounts`
+
+// selectCardAccountQuery selects the account a card is linked to, if the cardholder still holds it
+const selectCardAccountQuery = `SELECT a.account_id, a.account_type, a.currency, a.status FROM card_accounts c
+		JOIN accounts a ON a.account_id = c.account_id
+		JOIN account_holders h ON h.account_id = c.account_id AND h.user_id = $2
+		WHERE c.card_id = $1`
+
+// selectDefaultAccountQuery selects the account of a card that isn't linked to one: its holder's
+// oldest open personal or joint account. Business accounts are only used by cards linked to them.
+const selectDefaultAccountQuery = `SELECT a.account_id, a.account_type, a.currency, a.status FROM accounts a
+		JOIN account_holders h ON h.account_id = a.account_id
+		WHERE h.user_id = $1 AND a.status = 'OPEN' AND a.account_type IN ('personal', 'joint')
+		ORDER BY a.created_at, a.account_id LIMIT 1`
+
+// AccountsService implements the Accounts service functionality.
+//
+// An account's money is held by the balance service under the account's ID. The balance is opened
+// when the account is created, so it exists before any money moves in or out of the account.
+type AccountsService struct {
+	pb.UnimplementedAccountsServer
+	db      *sql.DB
+	balance balancepb.BalanceClient
+}
+
+// NewAccountsService creates a new accounts service instance
+func NewAccountsService(db *sql.DB, balance balancepb.BalanceClient) *AccountsService {
+	return &AccountsService{db: db, balance: balance}
+}
+
+// CreateUser creates a user. Emails are compared ignoring case.
+func (s *AccountsService) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	log.Printf("Received CreateUser request: %+v", req)
+
+	name := strings.TrimSpace(req.GetName())
+	email := strings.ToLower(strings.TrimSpace(req.GetEmail()))
+	if name == "" || email == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name and email are required")
+	}
+	if !strings.Contains(email, "@") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid email")
+	}
+
+	user := &pb.User{UserId: uuid.New().String(), Name: name, Email: email}
+
+	insertQuery := `INSERT INTO users (user_id, name, email, created_at) VALUES ($1, $2, $3, NOW())
+					ON CONFLICT (email) DO NOTHING RETURNING created_at`
+	var createdAt time.Time
+	err := s.db.QueryRowContext(ctx, insertQuery, user.UserId, user.Name, user.Email).Scan(&createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.AlreadyExists, "email already in use")
+		}
+		log.Printf("failed to insert user: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create user")
+	}
+	user.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+
+	log.Printf("Created user %s", user.UserId)
+
+	return user, nil
+}
+
+// GetUser retrieves a user
+func (s *AccountsService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	log.Printf("Received GetUser request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetUserId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id")
+	}
+
+	user := &pb.User{}
+	var createdAt time.Time
+	query := `SELECT user_id, name, email, created_at FROM users WHERE user_id = $1`
+	err := s.db.QueryRowContext(ctx, query, req.GetUserId()).Scan(&user.UserId, &user.Name, &user.Email, &createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		log.Printf("failed to query user: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get user")
+	}
+	user.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return user, nil
+}
+
+// CreateAccount opens an account for its holders and opens its balance in the balance service.
+// The account is only stored once its balance is open; if storing it then fails, the balance is
+// left open and empty, and no one can use it.
+func (s *AccountsService) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.Account, error) {
+	log.Printf("Received CreateAccount request: %+v", req)
+
+	name := strings.TrimSpace(req.GetName())
+	holders := req.GetHolderUserIds()
+	switch req.GetAccountType() {
+	case accountPersonal:
+		if len(holders) != 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "a personal account has one holder")
+		}
+		name = ""
+	case accountJoint:
+		if len(holders) != jointHolders {
+			return nil, status.Errorf(codes.InvalidArgument, "a joint account has %d holders", jointHolders)
+		}
+		name = ""
+	case accountBusiness:
+		if len(holders) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "a business account needs a holder")
+		}
+		if name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "a business account needs a name")
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_type: %s", req.GetAccountType())
+	}
+	seen := make(map[string]bool, len(holders))
+	for _, userID := range holders {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid holder user_id: %s", userID)
+		}
+		if seen[userID] {
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate holder user_id: %s", userID)
+		}
+		seen[userID] = true
+	}
+	currency := strings.ToUpper(req.GetCurrency())
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	if len(currency) != 3 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid currency: %s", req.GetCurrency())
+	}
+
+	account := &pb.Account{
+		AccountId:     uuid.New().String(),
+		AccountType:   req.GetAccountType(),
+		Name:          name,
+		Currency:      currency,
+		Status:        accountOpen,
+		HolderUserIds: append([]string(nil), holders...),
+	}
+	// Holders added together are listed in ID order, see listHolders
+	sort.Strings(account.HolderUserIds)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create account")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	for _, userID := range holders {
+		if err := checkUserExists(ctx, tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	insertQuery := `INSERT INTO accounts (account_id, account_type, name, currency, status, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) RETURNING created_at`
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx, insertQuery, account.AccountId, account.AccountType, account.Name, account.Currency, account.Status).Scan(&createdAt)
+	if err != nil {
+		log.Printf("failed to insert account: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create account")
+	}
+	account.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+
+	holderQuery := `INSERT INTO account_holders (account_id, user_id, created_at) VALUES ($1, $2, NOW())`
+	for _, userID := range holders {
+		if _, err := tx.ExecContext(ctx, holderQuery, account.AccountId, userID); err != nil {
+			log.Printf("failed to insert account holder: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to create account")
+		}
+	}
+
+	_, err = s.balance.OpenAccount(ctx, &balancepb.OpenAccountRequest{AccountId: account.AccountId, Currency: account.Currency})
+	if err != nil {
+		log.Printf("failed to open balance of account %s: %v", account.AccountId, err)
+		return nil, status.Errorf(codes.Internal, "failed to create account")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create account")
+	}
+
+	log.Printf("Created %s account %s in %s", account.AccountType, account.AccountId, account.Currency)
+
+	return account, nil
+}
+
+// GetAccount retrieves an account and its holders
+func (s *AccountsService) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.Account, error) {
+	log.Printf("Received GetAccount request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetAccountId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id")
+	}
+
+	account, err := scanAccount(s.db.QueryRowContext(ctx, selectAccountQuery+` WHERE account_id = $1`, req.GetAccountId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		log.Printf("failed to query account: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get account")
+	}
+	if account.HolderUserIds, err = listHolders(ctx, s.db, account.AccountId); err != nil {
+		log.Printf("failed to query account holders: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get account")
+	}
+	return account, nil
+}
+
+// ListAccountsByUser lists the accounts a user holds, oldest first
+func (s *AccountsService) ListAccountsByUser(ctx context.Context, req *pb.ListAccountsByUserRequest) (*pb.AccountList, error) {
+	log.Printf("Received ListAccountsByUser request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetUserId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id")
+	}
+
+	query := `SELECT a.account_id, a.account_type, a.name, a.currency, a.status, a.created_at FROM accounts a
+			  JOIN account_holders h ON h.account_id = a.account_id WHERE h.user_id = $1`
+	if !req.GetIncludeClosed() {
+		query += ` AND a.status = 'OPEN'`
+	}
+	query += ` ORDER BY a.created_at, a.account_id`
+
+	rows, err := s.db.QueryContext(ctx, query, req.GetUserId())
+	if err != nil {
+		log.Printf("failed to query accounts: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list accounts")
+	}
+	defer rows.Close()
+
+	accounts := []*pb.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			log.Printf("failed to scan account: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list accounts")
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating accounts: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list accounts")
+	}
+
+	for _, account := range accounts {
+		if account.HolderUserIds, err = listHolders(ctx, s.db, account.AccountId); err != nil {
+			log.Printf("failed to query account holders: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list accounts")
+		}
+	}
+
+	return &pb.AccountList{Accounts: accounts}, nil
+}
+
+// AddAccountHolder adds a holder to a joint account with one holder left, or to a business account.
+// Adding a user who already holds the account returns it unchanged.
+func (s *AccountsService) AddAccountHolder(ctx context.Context, req *pb.AccountHolderRequest) (*pb.Account, error) {
+	log.Printf("Received AddAccountHolder request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetUserId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id")
+	}
+
+	tx, account, err := s.lockAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	for _, userID := range account.HolderUserIds {
+		if userID == req.GetUserId() {
+			return account, nil
+		}
+	}
+	switch {
+	case account.AccountType == accountPersonal:
+		return nil, status.Errorf(codes.FailedPrecondition, "a personal account has one holder")
+	case account.AccountType == accountJoint && len(account.HolderUserIds) >= jointHolders:
+		return nil, status.Errorf(codes.FailedPrecondition, "a joint account has %d holders", jointHolders)
+	}
+	if err := checkUserExists(ctx, tx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	insertQuery := `INSERT INTO account_holders (account_id, user_id, created_at) VALUES ($1, $2, NOW())`
+	if _, err := tx.ExecContext(ctx, insertQuery, account.AccountId, req.GetUserId()); err != nil {
+		log.Printf("failed to insert account holder: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to add account holder")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to add account holder")
+	}
+	account.HolderUserIds = append(account.HolderUserIds, req.GetUserId())
+
+	log.Printf("Added holder %s to account %s", req.GetUserId(), account.AccountId)
+
+	return account, nil
+}
+
+// RemoveAccountHolder removes a holder from an account. The last holder can't be removed. Cards
+// of the removed holder linked to the account stop paying from it.
+func (s *AccountsService) RemoveAccountHolder(ctx context.Context, req *pb.AccountHolderRequest) (*pb.Account, error) {
+	log.Printf("Received RemoveAccountHolder request: %+v", req)
+
+	tx, account, err := s.lockAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	remaining := make([]string, 0, len(account.HolderUserIds))
+	for _, userID := range account.HolderUserIds {
+		if userID != req.GetUserId() {
+			remaining = append(remaining, userID)
+		}
+	}
+	if len(remaining) == len(account.HolderUserIds) {
+		return nil, status.Errorf(codes.NotFound, "user does not hold the account")
+	}
+	if len(remaining) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "an account can't be left without a holder")
+	}
+
+	deleteQuery := `DELETE FROM account_holders WHERE account_id = $1 AND user_id = $2`
+	if _, err := tx.ExecContext(ctx, deleteQuery, account.AccountId, req.GetUserId()); err != nil {
+		log.Printf("failed to delete account holder: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to remove account holder")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to remove account holder")
+	}
+	account.HolderUserIds = remaining
+
+	log.Printf("Removed holder %s from account %s", req.GetUserId(), account.AccountId)
+
+	return account, nil
+}
+
+// LinkCard makes a card pay from an open account its holder holds, instead of the account it paid
+// from before. Accounts doesn't know who holds which card; the caller checks the card is the user's.
+func (s *AccountsService) LinkCard(ctx context.Context, req *pb.LinkCardRequest) (*pb.CardAccount, error) {
+	log.Printf("Received LinkCard request: %+v", req)
+
+	if req.GetCardId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "card_id is required")
+	}
+	if _, err := uuid.Parse(req.GetUserId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id")
+	}
+
+	account, err := s.GetAccount(ctx, &pb.GetAccountRequest{AccountId: req.GetAccountId()})
+	if err != nil {
+		return nil, err
+	}
+	isHolder := false
+	for _, userID := range account.HolderUserIds {
+		isHolder = isHolder || userID == req.GetUserId()
+	}
+	if !isHolder {
+		return nil, status.Errorf(codes.PermissionDenied, "user does not hold the account")
+	}
+	if account.Status != accountOpen {
+		return nil, status.Errorf(codes.FailedPrecondition, "account is closed")
+	}
+
+	upsertQuery := `INSERT INTO card_accounts (card_id, account_id, created_at, updated_at) VALUES ($1, $2, NOW(), NOW())
+					ON CONFLICT (card_id) DO UPDATE SET account_id = EXCLUDED.account_id, updated_at = NOW()`
+	if _, err := s.db.ExecContext(ctx, upsertQuery, req.GetCardId(), account.AccountId); err != nil {
+		log.Printf("failed to link card: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to link card")
+	}
+
+	log.Printf("Linked card %s to account %s", req.GetCardId(), account.AccountId)
+
+	return &pb.CardAccount{
+		CardId:      req.GetCardId(),
+		AccountId:   account.AccountId,
+		AccountType: account.AccountType,
+		Currency:    account.Currency,
+	}, nil
+}
+
+// CarryCardLink links a replacement card to the account the card it replaces was linked to, so the
+// link follows a card through any number of replacements. A card that wasn't linked leaves its
+// replacement unlinked too. Carrying a link again is harmless, so Cards can retry a reissue.
+func (s *AccountsService) CarryCardLink(ctx context.Context, req *pb.CarryCardLinkRequest) (*pb.CarriedCardLink, error) {
+	log.Printf("Received CarryCardLink request: %+v", req)
+
+	if req.GetCardId() == "" || req.GetReplacesCardId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "card_id and replaces_card_id are required")
+	}
+
+	carryQuery := `INSERT INTO card_accounts (card_id, account_id, created_at, updated_at)
+				   SELECT $1, account_id, NOW(), NOW() FROM card_accounts WHERE card_id = $2
+				   ON CONFLICT (card_id) DO UPDATE SET account_id = EXCLUDED.account_id, updated_at = NOW()
+				   RETURNING account_id`
+	var accountID string
+	err := s.db.QueryRowContext(ctx, carryQuery, req.GetCardId(), req.GetReplacesCardId()).Scan(&accountID)
+	if err == sql.ErrNoRows {
+		return &pb.CarriedCardLink{CardId: req.GetCardId()}, nil
+	}
+	if err != nil {
+		log.Printf("failed to carry card link: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to carry card link")
+	}
+
+	log.Printf("Linked replacement card %s to account %s", req.GetCardId(), accountID)
+	return &pb.CarriedCardLink{CardId: req.GetCardId(), AccountId: accountID}, nil
+}
+
+// ResolveAccountForCard returns the account a card's payments are taken from. A card linked to an
+// account that has since closed, directly or through the card it replaces, has none.
+func (s *AccountsService) ResolveAccountForCard(ctx context.Context, req *pb.ResolveAccountForCardRequest) (*pb.CardAccount, error) {
+	log.Printf("Received ResolveAccountForCard request: %+v", req)
+
+	if req.GetCardId() == "" || req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "card_id and user_id are required")
+	}
+	if _, err := uuid.Parse(req.GetUserId()); err != nil {
+		// Every user has a UUID, so no one holds an account for this card
+		return nil, status.Errorf(codes.NotFound, "no account for card")
+	}
+
+	cardIDs := []string{req.GetCardId()}
+	if req.GetReplacesCardId() != "" {
+		cardIDs = append(cardIDs, req.GetReplacesCardId())
+	}
+	for _, cardID := range cardIDs {
+		account, err := scanCardAccount(s.db.QueryRowContext(ctx, selectCardAccountQuery, cardID, req.GetUserId()))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			log.Printf("failed to query card account: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to resolve account")
+		}
+		if account.status != accountOpen {
+			return nil, status.Errorf(codes.FailedPrecondition, "card's account is closed")
+		}
+		account.CardId = req.GetCardId()
+		return account.CardAccount, nil
+	}
+
+	account, err := scanCardAccount(s.db.QueryRowContext(ctx, selectDefaultAccountQuery, req.GetUserId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "no account for card")
+		}
+		log.Printf("failed to query default account: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to resolve account")
+	}
+	account.CardId = req.GetCardId()
+	return account.CardAccount, nil
+}
+
+// lockAccount begins a transaction and locks an account in it, so its holders can't change
+// until the transaction ends. The caller rolls back or commits the transaction.
+func (s *AccountsService) lockAccount(ctx context.Context, accountID string) (*sql.Tx, *pb.Account, error) {
+	if _, err := uuid.Parse(accountID); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid account_id")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, nil, status.Errorf(codes.Internal, "failed to update account")
+	}
+
+	account, err := scanAccount(tx.QueryRowContext(ctx, selectAccountQuery+` WHERE account_id = $1 FOR UPDATE`, accountID))
+	if err == nil && account.Status != accountOpen {
+		err = status.Errorf(codes.FailedPrecondition, "account is closed")
+	}
+	if err == nil {
+		account.HolderUserIds, err = listHolders(ctx, tx, accountID)
+	}
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, nil, err
+		}
+		log.Printf("failed to lock account: %v", err)
+		return nil, nil, status.Errorf(codes.Internal, "failed to update account")
+	}
+	return tx, account, nil
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// checkUserExists returns NotFound if there is no user with the ID
+func checkUserExists(ctx context.Context, q querier, userID string) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		log.Printf("failed to query user: %v", err)
+		return status.Errorf(codes.Internal, "failed to get user")
+	}
+	if !exists {
+		return status.Errorf(codes.NotFound, "user not found: %s", userID)
+	}
+	return nil
+}
+
+// listHolders lists the users holding an account, oldest holder first. Holders added at the same
+// time, such as those the account was created with, are in ID order.
+func listHolders(ctx context.Context, q querier, accountID string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `SELECT user_id FROM account_holders WHERE account_id = $1 ORDER BY created_at, user_id`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holders := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		holders = append(holders, userID)
+	}
+	return holders, rows.Err()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAccount reads an account selected by selectAccountQuery, without its holders
+func scanAccount(row scanner) (*pb.Account, error) {
+	var account pb.Account
+	var createdAt time.Time
+	if err := row.Scan(&account.AccountId, &account.AccountType, &account.Name, &account.Currency, &account.Status, &createdAt); err != nil {
+		return nil, err
+	}
+	account.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return &account, nil
+}
+
+// cardAccount is a card's account and whether the account is open
+type cardAccount struct {
+	*pb.CardAccount
+	status string
+}
+
+// scanCardAccount reads an account selected by selectCardAccountQuery or selectDefaultAccountQuery
+func scanCardAccount(row scanner) (*cardAccount, error) {
+	account := &cardAccount{CardAccount: &pb.CardAccount{}}
+	if err := row.Scan(&account.AccountId, &account.AccountType, &account.Currency, &account.status); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
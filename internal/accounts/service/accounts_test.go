@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+const (
+	testUserID      = "1c9e4b2a-6d3f-4a8e-9b7c-5f2d1e0a3c4b"
+	testOtherUserID = "8f7a6b5c-4d3e-4f2a-9b1c-0d9e8f7a6b5c"
+	testAccountID   = "3b2f6c4e-8d1a-4f5e-9c7b-2a1d0e9f8c7b"
+	testCardID      = "card-123"
+)
+
+// Mock BalanceClient. Only the RPCs the accounts service calls are mocked; the embedded
+// interface is nil, so calling any other RPC panics.
+type mockBalanceClient struct {
+	mock.Mock
+	balancepb.BalanceClient
+}
+
+func (m *mockBalanceClient) OpenAccount(ctx context.Context, in *balancepb.OpenAccountRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) GetBalance(ctx context.Context, in *balancepb.AccountID, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+// Helper function to create a server instance with mocks
+func newTestServer(t *testing.T) (*AccountsService, sqlmock.Sqlmock, *mockBalanceClient) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	balanceClient := new(mockBalanceClient)
+	return NewAccountsService(db, balanceClient), mockDb, balanceClient
+}
+
+// accountColumns are the columns selected by selectAccountQuery
+var accountColumns = []string{"account_id", "account_type", "name", "currency", "status", "created_at"}
+
+// cardAccountColumns are the columns selected by selectCardAccountQuery and selectDefaultAccountQuery
+var cardAccountColumns = []string{"account_id", "account_type", "currency", "status"}
+
+var testCreatedAt = time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+// expectUserExists sets up the mock expectation for checking a user exists
+func expectUserExists(mockDb sqlmock.Sqlmock, userID string, exists bool) {
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1)`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+// expectLockAccount sets up the mock expectations for locking an account and listing its holders
+func expectLockAccount(mockDb sqlmock.Sqlmock, accountType string, holders ...string) {
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectAccountQuery + ` WHERE account_id = $1 FOR UPDATE`)).
+		WithArgs(testAccountID).
+		WillReturnRows(sqlmock.NewRows(accountColumns).AddRow(testAccountID, accountType, "", "GBP", "OPEN", testCreatedAt))
+	expectHolders(mockDb, holders...)
+}
+
+// expectHolders sets up the mock expectation for listing an account's holders
+func expectHolders(mockDb sqlmock.Sqlmock, holders ...string) {
+	rows := sqlmock.NewRows([]string{"user_id"})
+	for _, userID := range holders {
+		rows.AddRow(userID)
+	}
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT user_id FROM account_holders WHERE account_id = $1`)).
+		WithArgs(testAccountID).
+		WillReturnRows(rows)
+}
+
+func TestCreateUser(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO users (user_id, name, email, created_at) VALUES ($1, $2, $3, NOW())`)
+	mockDb.ExpectQuery(insertQuery).
+		WithArgs(sqlmock.AnyArg(), "Ada Lovelace", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(testCreatedAt))
+	mockDb.ExpectQuery(insertQuery).
+		WithArgs(sqlmock.AnyArg(), "Ada", "ada@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	user, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: " Ada Lovelace ", Email: "Ada@Example.com"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, user.UserId)
+	assert.Equal(t, "ada@example.com", user.Email)
+	assert.Equal(t, "2026-01-05T09:00:00Z", user.CreatedAt)
+
+	// The email is already in use
+	_, err = s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Ada", Email: "not-an-email"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreateAccount(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	req := &pb.CreateAccountRequest{AccountType: "joint", HolderUserIds: []string{testOtherUserID, testUserID}, Currency: "eur"}
+
+	mockDb.ExpectBegin()
+	expectUserExists(mockDb, testOtherUserID, true)
+	expectUserExists(mockDb, testUserID, true)
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO accounts (account_id, account_type, name, currency, status, created_at, updated_at)`)).
+		WithArgs(sqlmock.AnyArg(), "joint", "", "EUR", "OPEN").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(testCreatedAt))
+	for _, userID := range req.HolderUserIds {
+		mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO account_holders (account_id, user_id, created_at) VALUES ($1, $2, NOW())`)).
+			WithArgs(sqlmock.AnyArg(), userID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	balanceClient.On("OpenAccount", mock.Anything, mock.MatchedBy(func(req *balancepb.OpenAccountRequest) bool {
+		return req.Currency == "EUR"
+	})).Return(&balancepb.BalanceResponse{Currency: "EUR"}, nil)
+	mockDb.ExpectCommit()
+
+	account, err := s.CreateAccount(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "joint", account.AccountType)
+	assert.Equal(t, "EUR", account.Currency)
+	assert.Equal(t, []string{testUserID, testOtherUserID}, account.HolderUserIds)
+	// The balance is opened under the account's ID
+	assert.Equal(t, account.AccountId, balanceClient.Calls[0].Arguments[1].(*balancepb.OpenAccountRequest).AccountId)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestCreateAccount_BalanceFails(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectBegin()
+	expectUserExists(mockDb, testUserID, true)
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO accounts`)).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(testCreatedAt))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO account_holders`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	balanceClient.On("OpenAccount", mock.Anything, mock.Anything).Return(nil, status.Error(codes.Unavailable, "balance down"))
+	mockDb.ExpectRollback() // the account isn't stored without its balance
+
+	_, err := s.CreateAccount(context.Background(), &pb.CreateAccountRequest{AccountType: "personal", HolderUserIds: []string{testUserID}})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreateAccount_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.CreateAccountRequest
+	}{
+		{"unknown type", &pb.CreateAccountRequest{AccountType: "savings", HolderUserIds: []string{testUserID}}},
+		{"personal with two holders", &pb.CreateAccountRequest{AccountType: "personal", HolderUserIds: []string{testUserID, testOtherUserID}}},
+		{"joint with one holder", &pb.CreateAccountRequest{AccountType: "joint", HolderUserIds: []string{testUserID}}},
+		{"joint with the same holder twice", &pb.CreateAccountRequest{AccountType: "joint", HolderUserIds: []string{testUserID, testUserID}}},
+		{"business without a name", &pb.CreateAccountRequest{AccountType: "business", HolderUserIds: []string{testUserID}}},
+		{"invalid holder", &pb.CreateAccountRequest{AccountType: "personal", HolderUserIds: []string{"user-123"}}},
+		{"invalid currency", &pb.CreateAccountRequest{AccountType: "personal", HolderUserIds: []string{testUserID}, Currency: "POUNDS"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newTestServer(t) // No DB interaction expected
+			defer s.db.Close()
+
+			_, err := s.CreateAccount(context.Background(), tt.req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestCreateAccount_UserNotFound(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectBegin()
+	expectUserExists(mockDb, testUserID, false)
+	mockDb.ExpectRollback()
+
+	_, err := s.CreateAccount(context.Background(), &pb.CreateAccountRequest{AccountType: "personal", HolderUserIds: []string{testUserID}})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListAccountsByUser(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`JOIN account_holders h ON h.account_id = a.account_id WHERE h.user_id = $1 AND a.status = 'OPEN' ORDER BY a.created_at, a.account_id`)).
+		WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows(accountColumns).AddRow(testAccountID, "joint", "", "GBP", "OPEN", testCreatedAt))
+	expectHolders(mockDb, testUserID, testOtherUserID)
+
+	accounts, err := s.ListAccountsByUser(context.Background(), &pb.ListAccountsByUserRequest{UserId: testUserID})
+
+	assert.NoError(t, err)
+	assert.Len(t, accounts.Accounts, 1)
+	assert.Equal(t, []string{testUserID, testOtherUserID}, accounts.Accounts[0].HolderUserIds)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAddAccountHolder(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	expectLockAccount(mockDb, "joint", testUserID)
+	expectUserExists(mockDb, testOtherUserID, true)
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO account_holders (account_id, user_id, created_at) VALUES ($1, $2, NOW())`)).
+		WithArgs(testAccountID, testOtherUserID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+
+	account, err := s.AddAccountHolder(context.Background(), &pb.AccountHolderRequest{AccountId: testAccountID, UserId: testOtherUserID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testUserID, testOtherUserID}, account.HolderUserIds)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAddAccountHolder_AccountFull(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountType string
+		holders     []string
+	}{
+		{"personal", "personal", []string{testUserID}},
+		{"full joint", "joint", []string{testUserID, "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb, _ := newTestServer(t)
+			defer s.db.Close()
+
+			expectLockAccount(mockDb, tt.accountType, tt.holders...)
+			mockDb.ExpectRollback()
+
+			_, err := s.AddAccountHolder(context.Background(), &pb.AccountHolderRequest{AccountId: testAccountID, UserId: testOtherUserID})
+
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRemoveAccountHolder(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	expectLockAccount(mockDb, "joint", testUserID, testOtherUserID)
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM account_holders WHERE account_id = $1 AND user_id = $2`)).
+		WithArgs(testAccountID, testUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	account, err := s.RemoveAccountHolder(context.Background(), &pb.AccountHolderRequest{AccountId: testAccountID, UserId: testUserID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testOtherUserID}, account.HolderUserIds)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRemoveAccountHolder_LastHolder(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	expectLockAccount(mockDb, "personal", testUserID)
+	mockDb.ExpectRollback()
+
+	_, err := s.RemoveAccountHolder(context.Background(), &pb.AccountHolderRequest{AccountId: testAccountID, UserId: testUserID})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestLinkCard(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectAccountQuery + ` WHERE account_id = $1`)).
+		WithArgs(testAccountID).
+		WillReturnRows(sqlmock.NewRows(accountColumns).AddRow(testAccountID, "business", "Acme Ltd", "GBP", "OPEN", testCreatedAt))
+	expectHolders(mockDb, testUserID)
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO card_accounts (card_id, account_id, created_at, updated_at)`)).
+		WithArgs(testCardID, testAccountID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	cardAccount, err := s.LinkCard(context.Background(), &pb.LinkCardRequest{CardId: testCardID, UserId: testUserID, AccountId: testAccountID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, testAccountID, cardAccount.AccountId)
+	assert.Equal(t, "business", cardAccount.AccountType)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestLinkCard_NotHolder(t *testing.T) {
+	s, mockDb, _ := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectAccountQuery + ` WHERE account_id = $1`)).
+		WithArgs(testAccountID).
+		WillReturnRows(sqlmock.NewRows(accountColumns).AddRow(testAccountID, "personal", "", "GBP", "OPEN", testCreatedAt))
+	expectHolders(mockDb, testOtherUserID)
+
+	_, err := s.LinkCard(context.Background(), &pb.LinkCardRequest{CardId: testCardID, UserId: testUserID, AccountId: testAccountID})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCarryCardLink(t *testing.T) {
+	const replacedCardID = "card-100"
+	tests := []struct {
+		name          string
+		linked        bool // whether the replaced card is linked
+		wantAccountID string
+	}{
+		{name: "replaced card linked", linked: true, wantAccountID: testAccountID},
+		{name: "replaced card unlinked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb, _ := newTestServer(t)
+			defer s.db.Close()
+
+			q := mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO card_accounts (card_id, account_id, created_at, updated_at)`)).
+				WithArgs(testCardID, replacedCardID)
+			if tt.linked {
+				q.WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(testAccountID))
+			} else {
+				q.WillReturnError(sql.ErrNoRows)
+			}
+
+			link, err := s.CarryCardLink(context.Background(), &pb.CarryCardLinkRequest{CardId: testCardID, ReplacesCardId: replacedCardID})
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCardID, link.CardId)
+			assert.Equal(t, tt.wantAccountID, link.AccountId)
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+// expectBackfilledAccount sets up the mock expectations for storing a user's backfilled account
+func expectBackfilledAccount(mockDb sqlmock.Sqlmock, userID, currency string) {
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO accounts (account_id, account_type, currency, status, created_at, updated_at)`)).
+		WithArgs(userID, "personal", currency, "OPEN", testCreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO account_holders (account_id, user_id, created_at) VALUES ($1, $1, $2)`)).
+		WithArgs(userID, testCreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+}
+
+func TestBackfillPersonalAccounts(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectUsersWithoutAccountQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at"}).
+			AddRow(testUserID, testCreatedAt).
+			AddRow(testOtherUserID, testCreatedAt))
+	// The first user already has a balance, the second was never credited
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testUserID}).
+		Return(&balancepb.BalanceResponse{AccountId: testUserID, Currency: "EUR"}, nil).Once()
+	expectBackfilledAccount(mockDb, testUserID, "EUR")
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testOtherUserID}).
+		Return(nil, status.Error(codes.NotFound, "account not found")).Once()
+	balanceClient.On("OpenAccount", mock.Anything, &balancepb.OpenAccountRequest{AccountId: testOtherUserID, Currency: "GBP"}).
+		Return(&balancepb.BalanceResponse{AccountId: testOtherUserID, Currency: "GBP"}, nil).Once()
+	expectBackfilledAccount(mockDb, testOtherUserID, "GBP")
+
+	opened, err := s.BackfillPersonalAccounts(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, opened)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestBackfillPersonalAccounts_BalanceFails(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectUsersWithoutAccountQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at"}).
+			AddRow(testUserID, testCreatedAt).
+			AddRow(testOtherUserID, testCreatedAt))
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testUserID}).
+		Return(nil, status.Error(codes.NotFound, "account not found")).Once()
+	balanceClient.On("OpenAccount", mock.Anything, mock.Anything).
+		Return(nil, status.Error(codes.Unavailable, "balance unavailable")).Once()
+
+	opened, err := s.BackfillPersonalAccounts(context.Background())
+
+	// The user's account isn't stored without its balance, so the next run opens it
+	assert.Error(t, err)
+	assert.Equal(t, 0, opened)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestResolveAccountForCard(t *testing.T) {
+	const replacedCardID = "card-100"
+	account := func(accountType, accountStatus string) *sqlmock.Rows {
+		return sqlmock.NewRows(cardAccountColumns).AddRow(testAccountID, accountType, "GBP", accountStatus)
+	}
+
+	tests := []struct {
+		name         string
+		cardLink     *sqlmock.Rows // nil if the card isn't linked
+		replacedLink *sqlmock.Rows // nil if the replaced card isn't linked
+		fallback     *sqlmock.Rows // nil if the user has no open personal or joint account
+		wantType     string
+		wantCode     codes.Code
+	}{
+		{name: "linked card", cardLink: account("business", "OPEN"), wantType: "business"},
+		{name: "replaced card linked", replacedLink: account("business", "OPEN"), wantType: "business"},
+		{name: "unlinked card", fallback: account("personal", "OPEN"), wantType: "personal"},
+		{name: "linked account closed", cardLink: account("business", "CLOSED"), wantCode: codes.FailedPrecondition},
+		{name: "no account", wantCode: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb, _ := newTestServer(t)
+			defer s.db.Close()
+
+			expectLink := func(cardID string, rows *sqlmock.Rows) bool {
+				q := mockDb.ExpectQuery(regexp.QuoteMeta(selectCardAccountQuery)).WithArgs(cardID, testUserID)
+				if rows == nil {
+					q.WillReturnError(sql.ErrNoRows)
+					return false
+				}
+				q.WillReturnRows(rows)
+				return true
+			}
+			if !expectLink(testCardID, tt.cardLink) && !expectLink(replacedCardID, tt.replacedLink) {
+				q := mockDb.ExpectQuery(regexp.QuoteMeta(selectDefaultAccountQuery)).WithArgs(testUserID)
+				if tt.fallback == nil {
+					q.WillReturnError(sql.ErrNoRows)
+				} else {
+					q.WillReturnRows(tt.fallback)
+				}
+			}
+
+			cardAccount, err := s.ResolveAccountForCard(context.Background(), &pb.ResolveAccountForCardRequest{
+				CardId:         testCardID,
+				UserId:         testUserID,
+				ReplacesCardId: replacedCardID,
+			})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, testCardID, cardAccount.CardId)
+				assert.Equal(t, testAccountID, cardAccount.AccountId)
+				assert.Equal(t, tt.wantType, cardAccount.AccountType)
+			}
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+// selectUsersWithoutAccountQuery selects the users who hold no account, oldest first
+const selectUsersWithoutAccountQuery = `SELECT user_id, created_at FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM account_holders h WHERE h.user_id = u.user_id)
+		ORDER BY created_at, user_id`
+
+// userWithoutAccount is a user BackfillPersonalAccounts opens an account for
+type userWithoutAccount struct {
+	userID    string
+	createdAt time.Time
+}
+
+// BackfillPersonalAccounts opens a personal account for every user who holds none, and returns how
+// many it opened. Users from before accounts existed had their money held by the balance service
+// under their user ID, so their account takes that ID and the currency of the balance already there.
+// Users who were never credited have their balance opened in the default currency, as CreateAccount
+// would. The account is dated to when its holder signed up, so it stays their oldest personal
+// account and their cards, which aren't linked to an account, keep paying from it.
+//
+// Backfilling again only opens accounts for users who still hold none, so a failed run can be retried.
+func (s *AccountsService) BackfillPersonalAccounts(ctx context.Context) (int, error) {
+	rows, err := s.db.QueryContext(ctx, selectUsersWithoutAccountQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query users without an account: %w", err)
+	}
+	var users []userWithoutAccount
+	for rows.Next() {
+		var user userWithoutAccount
+		if err := rows.Scan(&user.userID, &user.createdAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	for i, user := range users {
+		currency, err := s.openExistingBalance(ctx, user.userID)
+		if err != nil {
+			return i, fmt.Errorf("failed to open balance of user %s: %w", user.userID, err)
+		}
+		if err := s.insertPersonalAccount(ctx, user, currency); err != nil {
+			return i, fmt.Errorf("failed to store account of user %s: %w", user.userID, err)
+		}
+		log.Printf("Backfilled personal account of user %s in %s", user.userID, currency)
+	}
+	return len(users), nil
+}
+
+// openExistingBalance returns the currency of the balance the balance service holds under a
+// user's ID, opening it in the default currency if the user was never credited.
+func (s *AccountsService) openExistingBalance(ctx context.Context, userID string) (string, error) {
+	existing, err := s.balance.GetBalance(ctx, &balancepb.AccountID{AccountId: userID})
+	if err == nil {
+		return existing.GetCurrency(), nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return "", err
+	}
+	opened, err := s.balance.OpenAccount(ctx, &balancepb.OpenAccountRequest{AccountId: userID, Currency: defaultCurrency})
+	if err != nil {
+		return "", err
+	}
+	return opened.GetCurrency(), nil
+}
+
+// insertPersonalAccount stores a user's backfilled personal account, with the user as its holder
+func (s *AccountsService) insertPersonalAccount(ctx context.Context, user userWithoutAccount, currency string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	insertQuery := `INSERT INTO accounts (account_id, account_type, currency, status, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, $5)`
+	if _, err := tx.ExecContext(ctx, insertQuery, user.userID, accountPersonal, currency, accountOpen, user.createdAt); err != nil {
+		return err
+	}
+	holderQuery := `INSERT INTO account_holders (account_id, user_id, created_at) VALUES ($1, $1, $2)`
+	if _, err := tx.ExecContext(ctx, holderQuery, user.userID, user.createdAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
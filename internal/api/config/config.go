@@ -24,12 +24,14 @@ func Load() (*Config, error) {
 	// Set default values
 	k.Set("http_port", ":8080")
 	k.Set("services_urls", map[string]string{
+		"accounts":     "localhost:50061",
 		"balance":      "localhost:50053",
 		"transactions": "localhost:50052",
 		"cards":        "localhost:50051",
 		"merchant":     "localhost:50054",
 		"feed":         "localhost:50055",
 		"disco":        "localhost:50057",
+		"scheduler":    "localhost:50059",
 	})
 
 	// Load from .env file if exists (optional)
@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/knadh/koanf/parsers/dotenv"
 	"github.com/knadh/koanf/providers/env"
@@ -17,6 +18,24 @@ type Config struct {
 	RedisAddr string `koanf:"redis_addr"`
 	HTTPPort  string `koanf:"http_port"`
 	GRPCPort  string `koanf:"grpc_port"`
+
+	// HoldTTL is how long an authorization hold stays active before it expires
+	HoldTTL time.Duration `koanf:"hold_ttl"`
+	// HoldSweepInterval is how often expired holds are released
+	HoldSweepInterval time.Duration `koanf:"hold_sweep_interval"`
+
+	// FXRatesFile is a JSON rate table used to convert debits and credits in currencies an account
+	// holds no balance in, e.g. {"EUR/GBP": "0.8712"}. Without it, such payments are refused.
+	FXRatesFile string `koanf:"fx_rates_file"`
+	// FXFeeBps is the fee charged on converted debits, in basis points of the converted amount
+	FXFeeBps int64 `koanf:"fx_fee_bps"`
+
+	// OverdraftInterestBps is the annual interest rate charged on overdrawn balances, in basis points
+	OverdraftInterestBps int64 `koanf:"overdraft_interest_bps"`
+	// OverdraftDailyFee is charged for each day an account closes overdrawn, in minor units of its currency
+	OverdraftDailyFee int64 `koanf:"overdraft_daily_fee"`
+	// OverdraftAccrualInterval is how often accounts that closed yesterday overdrawn are looked for and charged
+	OverdraftAccrualInterval time.Duration `koanf:"overdraft_accrual_interval"`
 }
 
 // Load loads configuration from environment variables with defaults
@@ -28,6 +47,13 @@ func Load() (*Config, error) {
 	k.Set("redis_addr", "localhost:6379")
 	k.Set("http_port", ":8082")
 	k.Set("grpc_port", ":50053")
+	k.Set("hold_ttl", "168h")
+	k.Set("hold_sweep_interval", "1m")
+	k.Set("fx_rates_file", "")
+	k.Set("fx_fee_bps", 0)
+	k.Set("overdraft_interest_bps", 0)
+	k.Set("overdraft_daily_fee", 0)
+	k.Set("overdraft_accrual_interval", "1h")
 
 	// Load from .env file if exists (optional)
 	if err := k.Load(file.Provider(".env"), dotenv.Parser()); err != nil {
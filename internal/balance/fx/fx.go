@@ -0,0 +1,156 @@
+// Package fx converts amounts between currencies for the balance service
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// ErrRateNotFound is returned when no exchange rate is known for a currency pair
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// RateProvider looks up exchange rates
+type RateProvider interface {
+	// Rate returns the number of units of to that one unit of from buys
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// StaticRates is a fixed rate table keyed by currency pair, e.g. "EUR/GBP": "0.8712".
+// A pair missing from the table is derived from its inverse if that is present.
+type StaticRates map[string]string
+
+// Rate implements RateProvider
+func (r StaticRates) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	if s, ok := r[from+"/"+to]; ok {
+		return ParseRate(s)
+	}
+	if s, ok := r[to+"/"+from]; ok {
+		inverse, err := ParseRate(s)
+		if err != nil {
+			return nil, err
+		}
+		return inverse.Inv(inverse), nil
+	}
+	return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+}
+
+// LoadFile reads a rate table from a JSON object of currency pairs to decimal rates,
+// e.g. {"EUR/GBP": "0.8712", "USD/GBP": "0.7904"}
+func LoadFile(path string) (StaticRates, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %w", err)
+	}
+	var rates StaticRates
+	if err := json.Unmarshal(b, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file %s: %w", path, err)
+	}
+	for pair, s := range rates {
+		from, to, ok := strings.Cut(pair, "/")
+		if !ok || len(from) != 3 || len(to) != 3 {
+			return nil, fmt.Errorf("invalid currency pair %q in %s", pair, path)
+		}
+		if _, err := ParseRate(s); err != nil {
+			return nil, fmt.Errorf("invalid rate for %s in %s: %w", pair, path, err)
+		}
+	}
+	return rates, nil
+}
+
+// ParseRate parses a positive decimal exchange rate
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q", s)
+	}
+	return rate, nil
+}
+
+// FormatRate formats a rate as a decimal string, as recorded on holds and transactions
+func FormatRate(rate *big.Rat) string {
+	s := strings.TrimRight(rate.FloatString(8), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MinorUnits returns the number of decimal places in the minor unit of an ISO 4217 currency
+func MinorUnits(currency string) int {
+	switch currency {
+	case "JPY", "KRW", "ISK", "CLP", "VND", "XAF", "XOF":
+		return 0
+	case "BHD", "KWD", "OMR", "JOD", "TND":
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Conversion is an amount converted from one currency into another
+type Conversion struct {
+	From      string
+	To        string
+	Amount    int64 // in minor units of From
+	Rate      *big.Rat
+	Converted int64 // Amount in minor units of To, excluding Fee
+	Fee       int64 // in minor units of To
+}
+
+// Total is the converted amount including the fee
+func (c Conversion) Total() int64 {
+	return c.Converted + c.Fee
+}
+
+// Convert converts amount minor units of from into to at rate, charging feeBps basis points
+// of the converted amount. Results are rounded half away from zero to the nearest minor unit.
+func Convert(amount int64, from, to string, rate *big.Rat, feeBps int64) Conversion {
+	converted := new(big.Rat).Mul(big.NewRat(amount, 1), rate)
+	converted.Mul(converted, scale(MinorUnits(to)-MinorUnits(from)))
+	convertedAmount := round(converted)
+
+	fee := big.NewRat(convertedAmount*feeBps, 10000)
+
+	return Conversion{
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Rate:      rate,
+		Converted: convertedAmount,
+		Fee:       round(fee),
+	}
+}
+
+// scale returns 10^exp
+func scale(exp int) *big.Rat {
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exp))), nil)
+	if exp < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), p)
+	}
+	return new(big.Rat).SetInt(p)
+}
+
+// round rounds r half away from zero
+func round(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if new(big.Int).Mul(m, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
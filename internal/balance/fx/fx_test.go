@@ -0,0 +1,72 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRates(t *testing.T) {
+	rates := StaticRates{"EUR/GBP": "0.8", "USD/GBP": "0.79"}
+	ctx := context.Background()
+
+	rate, err := rates.Rate(ctx, "EUR", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.8", FormatRate(rate))
+
+	// Derived from the inverse pair
+	rate, err = rates.Rate(ctx, "GBP", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.25", FormatRate(rate))
+
+	rate, err = rates.Rate(ctx, "GBP", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", FormatRate(rate))
+
+	_, err = rates.Rate(ctx, "EUR", "USD")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestConvert(t *testing.T) {
+	rate, err := ParseRate("0.8712")
+	assert.NoError(t, err)
+
+	// 12.34 EUR at 0.8712 is 10.750608 GBP, with a 2.75% fee of 0.2956...
+	conv := Convert(1234, "EUR", "GBP", rate, 275)
+	assert.Equal(t, int64(1075), conv.Converted)
+	assert.Equal(t, int64(30), conv.Fee)
+	assert.Equal(t, int64(1105), conv.Total())
+
+	// Currencies with different minor units: 1000 JPY at 0.0053 is 5.30 GBP
+	rate, err = ParseRate("0.0053")
+	assert.NoError(t, err)
+	conv = Convert(1000, "JPY", "GBP", rate, 0)
+	assert.Equal(t, int64(530), conv.Converted)
+	assert.Equal(t, int64(0), conv.Fee)
+
+	// Halves round away from zero: 0.05 GBP at 1.5 is 0.075 USD
+	rate, err = ParseRate("1.5")
+	assert.NoError(t, err)
+	conv = Convert(5, "GBP", "USD", rate, 0)
+	assert.Equal(t, int64(8), conv.Converted)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"EUR/GBP": "0.8712"}`), 0o600))
+
+	rates, err := LoadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticRates{"EUR/GBP": "0.8712"}, rates)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"EURGBP": "0.8712"}`), 0o600))
+	_, err = LoadFile(path)
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"EUR/GBP": "-1"}`), 0o600))
+	_, err = LoadFile(path)
+	assert.Error(t, err)
+}
@@ -4,58 +4,220 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/manifoldfinance/disco2/v2/internal/balance/fx"
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	"github.com/manifoldfinance/disco2/v2/pkg/idempotency"
 	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
 )
 
+// Idempotency key scopes, one per money-moving RPC
+const (
+	authorizeDebitScope = "AuthorizeDebit"
+	creditAccountScope  = "CreditAccount"
+	reduceHoldScope     = "ReduceHold"
+	transferScope       = "Transfer"
+)
+
+// defaultHoldTTL is how long an authorization hold stays active before it can be expired
+const defaultHoldTTL = 7 * 24 * time.Hour
+
+// defaultCurrency is the currency of accounts opened by a credit that names none
+const defaultCurrency = "GBP"
+
 // BalanceService implements the Balance service functionality
 type BalanceService struct {
 	pb.UnimplementedBalanceServer
-	db          *sql.DB
-	redisClient *redis.Client
+	db       *sql.DB
+	holdTTL  time.Duration
+	rates    fx.RateProvider
+	fxFeeBps int64
+
+	overdraftInterestBps int64
+	overdraftFee         int64
+}
+
+// Option configures optional BalanceService settings
+type Option func(*BalanceService)
+
+// WithHoldTTL sets how long authorization holds stay active before they expire
+func WithHoldTTL(ttl time.Duration) Option {
+	return func(s *BalanceService) {
+		if ttl > 0 {
+			s.holdTTL = ttl
+		}
+	}
+}
+
+// WithRateProvider sets where exchange rates for converting debits and credits come from.
+// Without one, only currencies an account holds a balance in are accepted.
+func WithRateProvider(rates fx.RateProvider) Option {
+	return func(s *BalanceService) {
+		if rates != nil {
+			s.rates = rates
+		}
+	}
+}
+
+// WithFXFee sets the fee charged on converted debits, in basis points of the converted amount
+func WithFXFee(bps int64) Option {
+	return func(s *BalanceService) {
+		if bps > 0 {
+			s.fxFeeBps = bps
+		}
+	}
+}
+
+// WithOverdraftInterest sets the annual interest rate charged on overdrawn balances, in basis points.
+// Interest accrues daily on the balance an account closes the day overdrawn by.
+func WithOverdraftInterest(bps int64) Option {
+	return func(s *BalanceService) {
+		if bps > 0 {
+			s.overdraftInterestBps = bps
+		}
+	}
+}
+
+// WithOverdraftFee sets the fee charged for each day an account closes overdrawn, in minor units of its currency
+func WithOverdraftFee(daily int64) Option {
+	return func(s *BalanceService) {
+		if daily > 0 {
+			s.overdraftFee = daily
+		}
+	}
 }
 
 // NewBalanceService creates a new balance service instance
-func NewBalanceService(db *sql.DB, redisClient *redis.Client) *BalanceService {
-	return &BalanceService{
-		db:          db,
-		redisClient: redisClient,
+func NewBalanceService(db *sql.DB, opts ...Option) *BalanceService {
+	s := &BalanceService{
+		db:      db,
+		holdTTL: defaultHoldTTL,
+		rates:   fx.StaticRates{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// GetBalance retrieves the balance for an account
+// OpenAccount opens an account with an empty balance in its currency. Opening an account that is
+// already open in the same currency changes nothing, so callers can retry it.
+func (s *BalanceService) OpenAccount(ctx context.Context, req *pb.OpenAccountRequest) (*pb.BalanceResponse, error) {
+	log.Printf("Received OpenAccount request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetAccountId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id")
+	}
+	currency := strings.ToUpper(req.GetCurrency())
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	if len(currency) != 3 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid currency: %s", req.GetCurrency())
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to open account")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	accountCurrency, err := lookupAccountCurrency(ctx, tx, req.GetAccountId())
+	switch {
+	case err == sql.ErrNoRows:
+		if err := openAccount(ctx, tx, req.GetAccountId(), currency); err != nil {
+			log.Printf("failed to open account: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to open account")
+		}
+		if err := tx.Commit(); err != nil {
+			log.Printf("failed to commit transaction: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to open account")
+		}
+		log.Printf("Opened account %s in %s", req.GetAccountId(), currency)
+	case err != nil:
+		log.Printf("failed to get account currency: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to open account")
+	case accountCurrency != currency:
+		return nil, status.Errorf(codes.AlreadyExists, "account already open in %s", accountCurrency)
+	}
+
+	return s.GetBalance(ctx, &pb.AccountID{AccountId: req.GetAccountId()})
+}
+
+// GetBalance retrieves the balances of an account. The top-level balance is in the account's currency.
 func (s *BalanceService) GetBalance(ctx context.Context, req *pb.AccountID) (*pb.BalanceResponse, error) {
 	log.Printf("Received GetBalance request: %+v", req)
 
-	query := `SELECT balance FROM accounts WHERE account_id = $1`
+	query := `SELECT a.currency, a.overdraft_limit, b.currency, b.balance, b.held FROM accounts a
+			  JOIN balances b ON b.account_id = a.account_id WHERE a.account_id = $1 ORDER BY b.currency`
 
-	var balance int64
-	err := s.db.QueryRowContext(ctx, query, req.GetAccountId()).Scan(&balance)
+	rows, err := s.db.QueryContext(ctx, query, req.GetAccountId())
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("account not found: %s", req.GetAccountId())
-			return nil, status.Errorf(codes.NotFound, "account not found")
-		}
 		log.Printf("failed to get balance: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to get balance")
 	}
+	defer rows.Close()
 
-	return &pb.BalanceResponse{
-		AccountId:      req.GetAccountId(),
-		CurrentBalance: balance,
-	}, nil
+	resp := &pb.BalanceResponse{AccountId: req.GetAccountId()}
+	for rows.Next() {
+		var accountCurrency, currency string
+		var bal accountBalance
+		if err := rows.Scan(&accountCurrency, &bal.overdraftLimit, &currency, &bal.balance, &bal.held); err != nil {
+			log.Printf("failed to scan balance row: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to get balance")
+		}
+
+		// Only the balance in the account's currency can be overdrawn
+		if currency != accountCurrency {
+			bal.overdraftLimit = 0
+		}
+		resp.Balances = append(resp.Balances, &pb.CurrencyBalance{
+			Currency:         currency,
+			CurrentBalance:   bal.balance,
+			AvailableBalance: bal.available(),
+			OverdraftLimit:   bal.overdraftLimit,
+		})
+		if currency == accountCurrency {
+			resp.Currency = currency
+			resp.CurrentBalance = bal.balance
+			resp.AvailableBalance = bal.available()
+			resp.OverdraftLimit = bal.overdraftLimit
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("rows error during getting balance: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get balance")
+	}
+
+	if len(resp.Balances) == 0 {
+		log.Printf("account not found: %s", req.GetAccountId())
+		return nil, status.Errorf(codes.NotFound, "account not found")
+	}
+
+	return resp, nil
 }
 
-// AuthorizeDebit authorizes and performs a debit operation on an account
+// AuthorizeDebit authorizes a debit by placing a hold on the account. The hold lowers the
+// available balance straight away; the ledger balance only moves when the hold is captured.
 func (s *BalanceService) AuthorizeDebit(ctx context.Context, req *pb.AuthorizeDebitRequest) (*pb.DebitResult, error) {
 	log.Printf("Received AuthorizeDebit request: %+v", req)
 
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("failed to begin transaction: %v", err)
@@ -63,31 +225,100 @@ func (s *BalanceService) AuthorizeDebit(ctx context.Context, req *pb.AuthorizeDe
 	}
 	defer tx.Rollback() // Rollback if not committed
 
-	// Select balance with FOR UPDATE to lock the row
-	query := `SELECT balance FROM accounts WHERE account_id = $1 FOR UPDATE`
-	var currentBalance int64
-	err = tx.QueryRowContext(ctx, query, req.GetAccountId()).Scan(&currentBalance)
+	// Replay the original result if this is a retry
+	if req.GetIdempotencyKey() != "" {
+		var previous pb.DebitResult
+		replayed, err := idempotency.Claim(ctx, tx, authorizeDebitScope, req.GetIdempotencyKey(), req, &previous)
+		if err != nil {
+			return nil, idempotencyError(err, "failed to authorize debit")
+		}
+		if replayed {
+			log.Printf("Replaying AuthorizeDebit result for idempotency key %s", req.GetIdempotencyKey())
+			return &previous, nil
+		}
+	}
+
+	accountCurrency, err := lookupAccountCurrency(ctx, tx, req.GetAccountId())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("account not found for debit: %s", req.GetAccountId())
-			return &pb.DebitResult{Success: false, ErrorMessage: "account not found"}, nil
+			return &pb.DebitResult{Success: false, ErrorMessage: "account not found", DeclineReason: pb.DebitDeclineReason_DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND}, nil
+		}
+		log.Printf("failed to get account currency: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize debit")
+	}
+	currency := strings.ToUpper(req.GetCurrency())
+	if currency == "" {
+		currency = accountCurrency
+	}
+
+	// Debit the balance in the requested currency, or convert into the account's currency if it holds none
+	amount := req.GetAmount()
+	var conv *fx.Conversion
+	bal, err := lockBalance(ctx, tx, req.GetAccountId(), currency)
+	if err == sql.ErrNoRows && currency != accountCurrency {
+		c, convErr := s.convert(ctx, amount, currency, accountCurrency, s.fxFeeBps)
+		if convErr != nil {
+			if errors.Is(convErr, fx.ErrRateNotFound) {
+				log.Printf("cannot convert debit for account %s: %v", req.GetAccountId(), convErr)
+				return &pb.DebitResult{Success: false, ErrorMessage: "currency not supported", DeclineReason: pb.DebitDeclineReason_DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED}, nil
+			}
+			log.Printf("failed to get exchange rate: %v", convErr)
+			return nil, status.Errorf(codes.Internal, "failed to authorize debit")
 		}
+		conv = &c
+		amount, currency = c.Total(), accountCurrency
+		bal, err = lockBalance(ctx, tx, req.GetAccountId(), currency)
+	}
+	if err != nil {
 		log.Printf("failed to get balance with lock: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to authorize debit")
 	}
 
-	// Check if sufficient funds
-	if currentBalance < req.GetAmount() {
-		log.Printf("insufficient funds for account %s: current=%d, requested=%d", req.GetAccountId(), currentBalance, req.GetAmount())
-		return &pb.DebitResult{Success: false, ErrorMessage: "insufficient funds"}, nil
+	// Check if sufficient available funds, including any arranged overdraft
+	if bal.available() < amount {
+		result := &pb.DebitResult{Success: false, ErrorMessage: "insufficient funds", DeclineReason: pb.DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS}
+		if bal.overdraftLimit > 0 {
+			result.ErrorMessage, result.DeclineReason = "over overdraft limit", pb.DebitDeclineReason_DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT
+		}
+		log.Printf("%s for account %s: available=%d, requested=%d %s", result.ErrorMessage, req.GetAccountId(), bal.available(), amount, currency)
+		return result, nil
+	}
+
+	// Place the hold
+	holdID := uuid.New().String()
+	insertQuery := `INSERT INTO holds (hold_id, account_id, currency, amount, status, original_currency, original_amount, fx_rate, fx_fee, expires_at, created_at)
+					VALUES ($1, $2, $3, $4, 'ACTIVE', $5, $6, $7, $8, $9, NOW())`
+	originalCurrency, originalAmount, fxRate, fxFee := conversionColumns(conv)
+	if _, err := tx.ExecContext(ctx, insertQuery, holdID, req.GetAccountId(), currency, amount,
+		originalCurrency, originalAmount, fxRate, fxFee, time.Now().Add(s.holdTTL)); err != nil {
+		log.Printf("failed to insert hold: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize debit")
+	}
+
+	bal.held += amount
+	updateQuery := `UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`
+	if _, err := tx.ExecContext(ctx, updateQuery, bal.held, req.GetAccountId(), currency); err != nil {
+		log.Printf("failed to update held amount: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize debit")
 	}
 
-	// Debit the account
-	newBalance := currentBalance - req.GetAmount()
-	updateQuery := `UPDATE accounts SET balance = $1, updated_at = NOW() WHERE account_id = $2`
-	_, err = tx.ExecContext(ctx, updateQuery, newBalance, req.GetAccountId())
-	if err != nil {
-		log.Printf("failed to update balance: %v", err)
+	result := &pb.DebitResult{Success: true, NewBalance: bal.available(), HoldId: holdID, Currency: currency}
+	if conv != nil {
+		result.FxRate = fx.FormatRate(conv.Rate)
+		result.ConvertedAmount = conv.Converted
+		result.FxFee = conv.Fee
+	}
+	if req.GetIdempotencyKey() != "" {
+		if err := idempotency.Complete(ctx, tx, authorizeDebitScope, req.GetIdempotencyKey(), result); err != nil {
+			log.Printf("failed to store idempotency key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to authorize debit")
+		}
+	}
+
+	// Queue "balance.updated" event in the same transaction
+	if err := enqueueBalanceUpdateEvent(ctx, tx, req.GetAccountId(), currency, bal); err != nil {
+		log.Printf("failed to enqueue balance:updated event after debit: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to authorize debit")
 	}
 
@@ -97,23 +328,19 @@ func (s *BalanceService) AuthorizeDebit(ctx context.Context, req *pb.AuthorizeDe
 		return nil, status.Errorf(codes.Internal, "failed to authorize debit")
 	}
 
-	log.Printf("Successfully debited account %s. New balance: %d", req.GetAccountId(), newBalance)
-
-	// Publish "balance.updated" event to Redis
-	if err := s.publishBalanceUpdateEvent(ctx, req.GetAccountId(), newBalance); err != nil {
-		log.Printf("failed to publish balance:updated event after debit: %v", err)
-		// Log the error but continue - don't fail the operation due to event publishing
-	} else {
-		log.Printf("Published balance:updated event for account %s", req.GetAccountId())
-	}
+	log.Printf("Placed hold %s of %d %s on account %s. Available balance: %d", holdID, amount, currency, req.GetAccountId(), bal.available())
 
-	return &pb.DebitResult{Success: true, NewBalance: newBalance}, nil
+	return result, nil
 }
 
-// CreditAccount adds credit to an account
+// CreditAccount adds credit to an account opened with OpenAccount
 func (s *BalanceService) CreditAccount(ctx context.Context, req *pb.CreditRequest) (*pb.BalanceResponse, error) {
 	log.Printf("Received CreditAccount request: %+v", req)
 
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("failed to begin transaction: %v", err)
@@ -121,75 +348,273 @@ func (s *BalanceService) CreditAccount(ctx context.Context, req *pb.CreditReques
 	}
 	defer tx.Rollback() // Rollback if not committed
 
-	// Select balance with FOR UPDATE to lock the row
-	query := `SELECT balance FROM accounts WHERE account_id = $1 FOR UPDATE`
-	var currentBalance int64
-	err = tx.QueryRowContext(ctx, query, req.GetAccountId()).Scan(&currentBalance)
+	// Replay the original result if this is a retry
+	if req.GetIdempotencyKey() != "" {
+		var previous pb.BalanceResponse
+		replayed, err := idempotency.Claim(ctx, tx, creditAccountScope, req.GetIdempotencyKey(), req, &previous)
+		if err != nil {
+			return nil, idempotencyError(err, "failed to credit account")
+		}
+		if replayed {
+			log.Printf("Replaying CreditAccount result for idempotency key %s", req.GetIdempotencyKey())
+			return &previous, nil
+		}
+	}
+
+	// Only an opened account can be credited, so money sent to a mistyped account ID isn't kept
+	// in an account no one holds
+	currency := strings.ToUpper(req.GetCurrency())
+	accountCurrency, err := lookupAccountCurrency(ctx, tx, req.GetAccountId())
 	if err != nil {
 		if err == sql.ErrNoRows {
-			// If account not found, create it with the credit amount
-			insertQuery := `INSERT INTO accounts (account_id, balance, updated_at) VALUES ($1, $2, NOW())`
-			_, insertErr := tx.ExecContext(ctx, insertQuery, req.GetAccountId(), req.GetAmount())
-			if insertErr != nil {
-				log.Printf("failed to create account and credit: %v", insertErr)
-				return nil, status.Errorf(codes.Internal, "failed to credit account")
-			}
-			newBalance := req.GetAmount()
-			if err := tx.Commit(); err != nil {
-				log.Printf("failed to commit transaction after create and credit: %v", err)
-				return nil, status.Errorf(codes.Internal, "failed to credit account")
-			}
-			log.Printf("Successfully created and credited account %s. New balance: %d", req.GetAccountId(), newBalance)
+			log.Printf("account not found for credit: %s", req.GetAccountId())
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		log.Printf("failed to get account currency: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to credit account")
+	}
+	if currency == "" {
+		currency = accountCurrency
+	}
 
-			// Publish event after successful account creation and credit
-			if err := s.publishBalanceUpdateEvent(ctx, req.GetAccountId(), newBalance); err != nil {
-				log.Printf("failed to publish balance:updated event after new account credit: %v", err)
-				// Log the error but continue - don't fail the operation due to event publishing
+	// Credit the balance in the credited currency, or convert into the account's currency if it holds none.
+	// Credits are converted without a fee.
+	amount := req.GetAmount()
+	var conv *fx.Conversion
+	bal, err := lockBalance(ctx, tx, req.GetAccountId(), currency)
+	if err == sql.ErrNoRows && currency != accountCurrency {
+		c, convErr := s.convert(ctx, amount, currency, accountCurrency, 0)
+		if convErr != nil {
+			if errors.Is(convErr, fx.ErrRateNotFound) {
+				log.Printf("cannot convert credit for account %s: %v", req.GetAccountId(), convErr)
+				return nil, status.Errorf(codes.InvalidArgument, "currency %s not supported", currency)
 			}
-
-			return &pb.BalanceResponse{AccountId: req.GetAccountId(), CurrentBalance: newBalance}, nil
+			log.Printf("failed to get exchange rate: %v", convErr)
+			return nil, status.Errorf(codes.Internal, "failed to credit account")
 		}
+		conv = &c
+		amount, currency = c.Total(), accountCurrency
+		bal, err = lockBalance(ctx, tx, req.GetAccountId(), currency)
+	}
+	if err != nil {
 		log.Printf("failed to get balance with lock: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to credit account")
 	}
 
 	// Credit the account
-	newBalance := currentBalance + req.GetAmount()
-	updateQuery := `UPDATE accounts SET balance = $1, updated_at = NOW() WHERE account_id = $2`
-	_, err = tx.ExecContext(ctx, updateQuery, newBalance, req.GetAccountId())
+	bal.balance += amount
+	updateQuery := `UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`
+	_, err = tx.ExecContext(ctx, updateQuery, bal.balance, req.GetAccountId(), currency)
 	if err != nil {
 		log.Printf("failed to update balance: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to credit account")
 	}
 
+	// Record the credit in the ledger against the funding account
+	postings := []posting{customerPosting(req.GetAccountId(), currency, amount, bal.balance, "credit")}
+	if conv != nil {
+		postings = append(postings, exchangePostings(*conv, fundingAccountID, false, "credit")...)
+	} else {
+		postings = append(postings, systemPosting(fundingAccountID, currency, -amount, "credit"))
+	}
+	if _, err := postJournal(ctx, tx, postings...); err != nil {
+		log.Printf("failed to post credit to ledger: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to credit account")
+	}
+	if err := checkLedgerBalance(ctx, tx, req.GetAccountId(), currency, bal.balance); err != nil {
+		log.Printf("ledger check failed after credit: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to credit account")
+	}
+
+	resp := bal.response(req.GetAccountId(), currency)
+	if req.GetIdempotencyKey() != "" {
+		if err := idempotency.Complete(ctx, tx, creditAccountScope, req.GetIdempotencyKey(), resp); err != nil {
+			log.Printf("failed to store idempotency key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to credit account")
+		}
+	}
+
+	// Queue "balance.updated" event in the same transaction
+	if err := enqueueBalanceUpdateEvent(ctx, tx, req.GetAccountId(), currency, bal); err != nil {
+		log.Printf("failed to enqueue balance:updated event after credit: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to credit account")
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		log.Printf("failed to commit transaction: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to credit account")
 	}
 
-	log.Printf("Successfully credited account %s. New balance: %d", req.GetAccountId(), newBalance)
+	log.Printf("Successfully credited account %s. New balance: %d %s", req.GetAccountId(), bal.balance, currency)
 
-	// Publish "balance.updated" event to Redis
-	if err := s.publishBalanceUpdateEvent(ctx, req.GetAccountId(), newBalance); err != nil {
-		log.Printf("failed to publish balance:updated event after credit: %v", err)
-		// Log the error but continue - don't fail the operation due to event publishing
-	} else {
-		log.Printf("Published balance:updated event for account %s", req.GetAccountId())
+	return resp, nil
+}
+
+// ListLedgerEntries returns the ledger entries of an account, newest first
+func (s *BalanceService) ListLedgerEntries(ctx context.Context, req *pb.ListLedgerEntriesRequest) (*pb.LedgerEntries, error) {
+	log.Printf("Received ListLedgerEntries request: %+v", req)
+
+	if req.GetAccountId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "account_id is required")
+	}
+
+	query := `SELECT entry_id, journal_id, account_id, currency, amount, balance_after, description, created_at
+			  FROM ledger_entries WHERE account_id = $1`
+	args := []interface{}{req.GetAccountId()}
+
+	// Add pagination
+	if req.GetBeforeId() != "" {
+		beforeID, err := strconv.ParseInt(req.GetBeforeId(), 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid before_id: %s", req.GetBeforeId())
+		}
+		query += ` AND entry_id < $2`
+		args = append(args, beforeID)
 	}
 
-	return &pb.BalanceResponse{AccountId: req.GetAccountId(), CurrentBalance: newBalance}, nil
+	query += ` ORDER BY entry_id DESC`
+
+	if req.GetLimit() > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, req.GetLimit())
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to list ledger entries: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list ledger entries")
+	}
+	defer rows.Close()
+
+	var entries []*pb.LedgerEntry
+	for rows.Next() {
+		var entry pb.LedgerEntry
+		var entryID int64
+		var balanceAfter sql.NullInt64
+		var description sql.NullString
+		var createdAt time.Time
+
+		if err := rows.Scan(
+			&entryID,
+			&entry.JournalId,
+			&entry.AccountId,
+			&entry.Currency,
+			&entry.Amount,
+			&balanceAfter,
+			&description,
+			&createdAt,
+		); err != nil {
+			log.Printf("failed to scan ledger entry row: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list ledger entries")
+		}
+
+		entry.EntryId = strconv.FormatInt(entryID, 10)
+		entry.BalanceAfter = balanceAfter.Int64
+		entry.Description = description.String
+		entry.CreatedAt = createdAt.Format(time.RFC3339)
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("rows error during listing ledger entries: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list ledger entries")
+	}
+
+	return &pb.LedgerEntries{Entries: entries}, nil
+}
+
+// idempotencyError maps a failed idempotency key claim to a gRPC error
+func idempotencyError(err error, msg string) error {
+	if errors.Is(err, idempotency.ErrKeyReused) {
+		return status.Errorf(codes.AlreadyExists, "idempotency key already used with a different request")
+	}
+	log.Printf("failed to claim idempotency key: %v", err)
+	return status.Errorf(codes.Internal, "%s", msg)
+}
+
+// lookupAccountCurrency returns the currency of an account, or sql.ErrNoRows if it does not exist
+func lookupAccountCurrency(ctx context.Context, tx *sql.Tx, accountID string) (string, error) {
+	var currency string
+	err := tx.QueryRowContext(ctx, `SELECT currency FROM accounts WHERE account_id = $1`, accountID).Scan(&currency)
+	return currency, err
+}
+
+// openAccount creates an account with an empty balance in its currency
+func openAccount(ctx context.Context, tx *sql.Tx, accountID, currency string) error {
+	insertQuery := `INSERT INTO accounts (account_id, currency, updated_at) VALUES ($1, $2, NOW())`
+	if _, err := tx.ExecContext(ctx, insertQuery, accountID, currency); err != nil {
+		return fmt.Errorf("failed to insert account: %w", err)
+	}
+	balanceQuery := `INSERT INTO balances (account_id, currency, balance, held, updated_at) VALUES ($1, $2, 0, 0, NOW())`
+	if _, err := tx.ExecContext(ctx, balanceQuery, accountID, currency); err != nil {
+		return fmt.Errorf("failed to insert balance: %w", err)
+	}
+	return nil
+}
+
+// accountBalance is an account's balance in one currency
+type accountBalance struct {
+	balance        int64
+	held           int64
+	overdraftLimit int64 // arranged overdraft, only on the balance in the account's currency
+}
+
+// available is what can still be debited: the balance minus active holds, plus the overdraft limit
+func (b accountBalance) available() int64 {
+	return b.balance - b.held + b.overdraftLimit
+}
+
+// response builds the BalanceResponse reporting the balance
+func (b accountBalance) response(accountID, currency string) *pb.BalanceResponse {
+	return &pb.BalanceResponse{
+		AccountId:        accountID,
+		CurrentBalance:   b.balance,
+		AvailableBalance: b.available(),
+		Currency:         currency,
+		OverdraftLimit:   b.overdraftLimit,
+	}
+}
+
+// lockBalance selects an account's balance in currency, and the account along with it, with FOR UPDATE,
+// returning sql.ErrNoRows if the account holds no balance in it
+func lockBalance(ctx context.Context, tx *sql.Tx, accountID, currency string) (accountBalance, error) {
+	query := `SELECT b.balance, b.held, CASE WHEN a.currency = b.currency THEN a.overdraft_limit ELSE 0 END
+			  FROM balances b JOIN accounts a ON a.account_id = b.account_id
+			  WHERE b.account_id = $1 AND b.currency = $2 FOR UPDATE`
+	var bal accountBalance
+	if err := tx.QueryRowContext(ctx, query, accountID, currency).Scan(&bal.balance, &bal.held, &bal.overdraftLimit); err != nil {
+		return accountBalance{}, err
+	}
+	return bal, nil
+}
+
+// convert quotes the conversion of amount from one currency into another at the current rate
+func (s *BalanceService) convert(ctx context.Context, amount int64, from, to string, feeBps int64) (fx.Conversion, error) {
+	rate, err := s.rates.Rate(ctx, from, to)
+	if err != nil {
+		return fx.Conversion{}, err
+	}
+	return fx.Convert(amount, from, to, rate, feeBps), nil
+}
+
+// conversionColumns returns the hold columns recording a conversion, all NULL if there was none
+func conversionColumns(conv *fx.Conversion) (sql.NullString, sql.NullInt64, sql.NullString, sql.NullInt64) {
+	if conv == nil {
+		return sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}
+	}
+	return sql.NullString{String: conv.From, Valid: true},
+		sql.NullInt64{Int64: conv.Amount, Valid: true},
+		sql.NullString{String: fx.FormatRate(conv.Rate), Valid: true},
+		sql.NullInt64{Int64: conv.Fee, Valid: true}
 }
 
-// publishBalanceUpdateEvent publishes a balance update event to Redis
-func (s *BalanceService) publishBalanceUpdateEvent(ctx context.Context, accountID string, newBalance int64) error {
-	eventPayload := fmt.Sprintf(`{"account_id": "%s", "new_balance": %d}`, accountID, newBalance)
-	_, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
-		Stream: "balance:updated",
-		MaxLen: 0, // No limit
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
-	}).Result()
-	return err
+// enqueueBalanceUpdateEvent writes a balance update event to the outbox within tx
+func enqueueBalanceUpdateEvent(ctx context.Context, tx *sql.Tx, accountID, currency string, bal accountBalance) error {
+	return events.Enqueue(ctx, tx, events.StreamBalanceUpdated, accountID, &eventspb.BalanceUpdated{
+		AccountId:        accountID,
+		NewBalance:       bal.balance,
+		AvailableBalance: bal.available(),
+		Currency:         currency,
+	})
 }
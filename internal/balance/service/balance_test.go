@@ -0,0 +1,1183 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/internal/balance/fx"
+	"github.com/manifoldfinance/disco2/v2/pkg/idempotency"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+// Helper function to create a server instance with mocks
+func newTestServer(t *testing.T, opts ...Option) (*BalanceService, sqlmock.Sqlmock) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	s := NewBalanceService(db, opts...)
+	return s, mockDb
+}
+
+// expectBalanceEvent sets up the mock expectation for queueing a balance:updated event in the outbox
+func expectBalanceEvent(mockDb sqlmock.Sqlmock, accountID string) {
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs("balance:updated", accountID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// capturedArg is a sqlmock argument matcher that records the value it was called with
+type capturedArg struct{ dst *[]byte }
+
+func captureArg(dst *[]byte) sqlmock.Argument { return capturedArg{dst: dst} }
+
+func (c capturedArg) Match(v driver.Value) bool {
+	b, ok := v.([]byte)
+	*c.dst = b
+	return ok
+}
+
+// ledgerInsertQuery is the statement that posts one leg of a journal
+var ledgerInsertQuery = regexp.QuoteMeta(`INSERT INTO ledger_entries (journal_id, account_id, currency, amount, balance_after, description, created_at) VALUES ($1, $2, $3, $4, $5, $6, NOW())`)
+
+// expectJournal sets up the mock expectations for posting a two-legged journal
+// and the ledger check that follows it
+func expectJournal(mockDb sqlmock.Sqlmock, accountID, currency string, amount, balanceAfter int64, systemAccountID, description string) {
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), accountID, currency, amount, sql.NullInt64{Int64: balanceAfter, Valid: true}, description).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), systemAccountID, currency, -amount, sql.NullInt64{}, description).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	expectLedgerCheck(mockDb, accountID, currency, balanceAfter)
+}
+
+// expectSystemLeg sets up the mock expectation for posting a leg to a system account
+func expectSystemLeg(mockDb sqlmock.Sqlmock, systemAccountID, currency string, amount int64, description string) {
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), systemAccountID, currency, amount, sql.NullInt64{}, description).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// expectLedgerCheck sets up the mock expectation for summing an account's ledger entries in currency
+func expectLedgerCheck(mockDb sqlmock.Sqlmock, accountID, currency string, sum int64) {
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(amount), 0) FROM ledger_entries WHERE account_id = $1 AND currency = $2`)).
+		WithArgs(accountID, currency).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(sum))
+}
+
+// expectAccountCurrency sets up the mock expectation for looking up an account's currency
+func expectAccountCurrency(mockDb sqlmock.Sqlmock, accountID, currency string) {
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT currency FROM accounts WHERE account_id = $1`)).
+		WithArgs(accountID).
+		WillReturnRows(sqlmock.NewRows([]string{"currency"}).AddRow(currency))
+}
+
+// expectHoldInsert sets up the mock expectation for placing a hold that was not converted
+func expectHoldInsert(mockDb sqlmock.Sqlmock, accountID, currency string, amount int64) {
+	mockDb.ExpectExec(holdInsertQuery).
+		WithArgs(sqlmock.AnyArg(), accountID, currency, amount, sql.NullString{}, sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// holdInsertQuery places a hold
+var holdInsertQuery = regexp.QuoteMeta(`INSERT INTO holds (hold_id, account_id, currency, amount, status, original_currency, original_amount, fx_rate, fx_fee, expires_at, created_at)
+					VALUES ($1, $2, $3, $4, 'ACTIVE', $5, $6, $7, $8, $9, NOW())`)
+
+// lockBalanceQuery locks an account's balance in one currency
+var lockBalanceQuery = regexp.QuoteMeta(`SELECT b.balance, b.held, CASE WHEN a.currency = b.currency THEN a.overdraft_limit ELSE 0 END
+			  FROM balances b JOIN accounts a ON a.account_id = b.account_id
+			  WHERE b.account_id = $1 AND b.currency = $2 FOR UPDATE`)
+
+// expectLockBalance sets up the mock expectation for locking an account's balance in currency, without an overdraft
+func expectLockBalance(mockDb sqlmock.Sqlmock, accountID, currency string, balance, held int64) {
+	expectLockOverdrawnBalance(mockDb, accountID, currency, balance, held, 0)
+}
+
+// expectLockOverdrawnBalance sets up the mock expectation for locking an account's balance in currency with an overdraft limit
+func expectLockOverdrawnBalance(mockDb sqlmock.Sqlmock, accountID, currency string, balance, held, overdraftLimit int64) {
+	mockDb.ExpectQuery(lockBalanceQuery).
+		WithArgs(accountID, currency).
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "held", "overdraft_limit"}).AddRow(balance, held, overdraftLimit))
+}
+
+// getBalanceQuery selects every balance of an account along with the account's currency
+var getBalanceQuery = regexp.QuoteMeta(`SELECT a.currency, a.overdraft_limit, b.currency, b.balance, b.held FROM accounts a
+			  JOIN balances b ON b.account_id = a.account_id WHERE a.account_id = $1 ORDER BY b.currency`)
+
+func TestOpenAccount(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.OpenAccountRequest{AccountId: "5d7c9e1a-2b3f-4c6d-8e9f-0a1b2c3d4e5f", Currency: "eur"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT currency FROM accounts WHERE account_id = $1`)).
+		WithArgs(req.AccountId).
+		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO accounts (account_id, currency, updated_at) VALUES ($1, $2, NOW())`)).
+		WithArgs(req.AccountId, "EUR").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO balances (account_id, currency, balance, held, updated_at) VALUES ($1, $2, 0, 0, NOW())`)).
+		WithArgs(req.AccountId, "EUR").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+	mockDb.ExpectQuery(getBalanceQuery).
+		WithArgs(req.AccountId).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "overdraft_limit", "currency", "balance", "held"}).
+			AddRow("EUR", int64(0), "EUR", int64(0), int64(0)))
+
+	resp, err := s.OpenAccount(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", resp.Currency)
+	assert.Equal(t, int64(0), resp.CurrentBalance)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestOpenAccount_AlreadyOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		code     codes.Code
+	}{
+		{"same currency", "GBP", codes.OK},
+		{"another currency", "USD", codes.AlreadyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			req := &balancepb.OpenAccountRequest{AccountId: "5d7c9e1a-2b3f-4c6d-8e9f-0a1b2c3d4e5f", Currency: tt.currency}
+
+			mockDb.ExpectBegin()
+			expectAccountCurrency(mockDb, req.AccountId, "GBP")
+			if tt.code == codes.OK {
+				mockDb.ExpectQuery(getBalanceQuery).
+					WithArgs(req.AccountId).
+					WillReturnRows(sqlmock.NewRows([]string{"currency", "overdraft_limit", "currency", "balance", "held"}).
+						AddRow("GBP", int64(0), "GBP", int64(1500), int64(0)))
+			}
+			mockDb.ExpectRollback()
+
+			resp, err := s.OpenAccount(context.Background(), req)
+
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.OK {
+				// The balance already in the account is untouched
+				assert.Equal(t, int64(1500), resp.CurrentBalance)
+			}
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOpenAccount_InvalidRequest(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	for _, req := range []*balancepb.OpenAccountRequest{
+		{AccountId: "acc-123"},
+		{AccountId: "5d7c9e1a-2b3f-4c6d-8e9f-0a1b2c3d4e5f", Currency: "POUNDS"},
+	} {
+		resp, err := s.OpenAccount(context.Background(), req)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestGetBalance_Found(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AccountID{AccountId: "acc-123"}
+	expectedBalance := int64(10000) // 100.00
+	held := int64(2500)             // 25.00 held by pending authorizations
+
+	mockDb.ExpectQuery(getBalanceQuery).
+		WithArgs(req.AccountId).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "overdraft_limit", "currency", "balance", "held"}).
+			AddRow("GBP", int64(0), "EUR", int64(3000), int64(0)).
+			AddRow("GBP", int64(0), "GBP", expectedBalance, held))
+
+	ctx := context.Background()
+	resp, err := s.GetBalance(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, req.AccountId, resp.AccountId)
+	assert.Equal(t, "GBP", resp.Currency)
+	assert.Equal(t, expectedBalance, resp.CurrentBalance)
+	assert.Equal(t, expectedBalance-held, resp.AvailableBalance)
+	assert.Len(t, resp.Balances, 2)
+	assert.Equal(t, "EUR", resp.Balances[0].Currency)
+	assert.Equal(t, int64(3000), resp.Balances[0].AvailableBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetBalance_NotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AccountID{AccountId: "acc-unknown"}
+
+	mockDb.ExpectQuery(getBalanceQuery).
+		WithArgs(req.AccountId).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "overdraft_limit", "currency", "balance", "held"}))
+
+	ctx := context.Background()
+	resp, err := s.GetBalance(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_Success(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 5000} // Debit 50.00
+	currentBalance := int64(10000)                                              // 100.00
+	held := int64(1000)                                                         // 10.00 already held
+	newHeld := held + req.Amount
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockBalance(mockDb, req.AccountId, "GBP", currentBalance, held)
+	expectHoldInsert(mockDb, req.AccountId, "GBP", req.Amount)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(newHeld, req.AccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.True(t, resp.Success)
+	assert.Equal(t, currentBalance-newHeld, resp.NewBalance)
+	assert.NotEmpty(t, resp.HoldId)
+	assert.Empty(t, resp.ErrorMessage)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_InsufficientFunds(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 15000} // Debit 150.00
+	currentBalance := int64(10000)                                               // 100.00
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockBalance(mockDb, req.AccountId, "GBP", currentBalance, int64(0))
+	mockDb.ExpectRollback() // Expect rollback because funds are insufficient
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err) // Business error, not gRPC error
+	assert.NotNil(t, resp)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "insufficient funds", resp.ErrorMessage)
+	assert.Equal(t, balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS, resp.DeclineReason)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_FundsHeld(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 5000}
+	currentBalance := int64(10000)
+	held := int64(6000) // Ledger balance covers the debit, but not once existing holds are taken into account
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockBalance(mockDb, req.AccountId, "GBP", currentBalance, held)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "insufficient funds", resp.ErrorMessage)
+	assert.Equal(t, balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS, resp.DeclineReason)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_WithinOverdraft(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// 10.00 in credit with a 50.00 overdraft covers a 40.00 debit
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 4000}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockOverdrawnBalance(mockDb, req.AccountId, "GBP", 1000, 0, 5000)
+	expectHoldInsert(mockDb, req.AccountId, "GBP", req.Amount)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(req.Amount, req.AccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, int64(2000), resp.NewBalance) // 1000 - 4000 held + 5000 overdraft
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_OverOverdraftLimit(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Already 40.00 overdrawn on a 50.00 overdraft
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 2000}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockOverdrawnBalance(mockDb, req.AccountId, "GBP", -4000, 0, 5000)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "over overdraft limit", resp.ErrorMessage)
+	assert.Equal(t, balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT, resp.DeclineReason)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_AccountNotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-unknown", Amount: 5000}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT currency FROM accounts WHERE account_id = $1`)).
+		WithArgs(req.AccountId).
+		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err) // Business error
+	assert.NotNil(t, resp)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "account not found", resp.ErrorMessage)
+	assert.Equal(t, balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND, resp.DeclineReason)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_IdempotentRetry(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 5000, IdempotencyKey: "auth-1"}
+	currentBalance := int64(10000)
+
+	// First attempt claims the key and stores its result
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`)).
+		WithArgs(authorizeDebitScope, req.IdempotencyKey, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockBalance(mockDb, req.AccountId, "GBP", currentBalance, int64(0))
+	expectHoldInsert(mockDb, req.AccountId, "GBP", req.Amount)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(req.Amount, req.AccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	var storedResult []byte
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE idempotency_keys SET response = $1 WHERE scope = $2 AND idempotency_key = $3`)).
+		WithArgs(captureArg(&storedResult), authorizeDebitScope, req.IdempotencyKey).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	first, err := s.AuthorizeDebit(ctx, req)
+	assert.NoError(t, err)
+	assert.True(t, first.Success)
+
+	// The retry finds the key already claimed and replays the stored result without placing another hold
+	requestHash, err := idempotency.Hash(req)
+	assert.NoError(t, err)
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`)).
+		WithArgs(authorizeDebitScope, req.IdempotencyKey, requestHash).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT request_hash, response FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`)).
+		WithArgs(authorizeDebitScope, req.IdempotencyKey).
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow(requestHash, storedResult))
+	mockDb.ExpectRollback()
+
+	second, err := s.AuthorizeDebit(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, first.HoldId, second.HoldId)
+	assert.Equal(t, first.NewBalance, second.NewBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_ConvertsCurrency(t *testing.T) {
+	s, mockDb := newTestServer(t, WithRateProvider(fx.StaticRates{"EUR/GBP": "0.8712"}), WithFXFee(275))
+	defer s.db.Close()
+
+	// 12.34 EUR is 10.75 GBP, plus a 2.75% fee of 0.30
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 1234, Currency: "eur"}
+	currentBalance := int64(10000)
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	mockDb.ExpectQuery(lockBalanceQuery).
+		WithArgs(req.AccountId, "EUR").
+		WillReturnError(sql.ErrNoRows) // No EUR balance
+	expectLockBalance(mockDb, req.AccountId, "GBP", currentBalance, 0)
+	mockDb.ExpectExec(holdInsertQuery).
+		WithArgs(sqlmock.AnyArg(), req.AccountId, "GBP", int64(1105),
+			sql.NullString{String: "EUR", Valid: true}, sql.NullInt64{Int64: 1234, Valid: true},
+			sql.NullString{String: "0.8712", Valid: true}, sql.NullInt64{Int64: 30, Valid: true}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(int64(1105), req.AccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "GBP", resp.Currency)
+	assert.Equal(t, currentBalance-1105, resp.NewBalance)
+	assert.Equal(t, "0.8712", resp.FxRate)
+	assert.Equal(t, int64(1075), resp.ConvertedAmount)
+	assert.Equal(t, int64(30), resp.FxFee)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_UnsupportedCurrency(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 1000, Currency: "USD"}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	mockDb.ExpectQuery(lockBalanceQuery).
+		WithArgs(req.AccountId, "USD").
+		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "currency not supported", resp.ErrorMessage)
+	assert.Equal(t, balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED, resp.DeclineReason)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAuthorizeDebit_ForeignCurrencyBalance(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// The account holds EUR, so the debit comes out of that balance without conversion
+	req := &balancepb.AuthorizeDebitRequest{AccountId: "acc-123", Amount: 1234, Currency: "EUR"}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockBalance(mockDb, req.AccountId, "EUR", 5000, 0)
+	expectHoldInsert(mockDb, req.AccountId, "EUR", req.Amount)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(req.Amount, req.AccountId, "EUR").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeDebit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "EUR", resp.Currency)
+	assert.Equal(t, int64(5000-1234), resp.NewBalance)
+	assert.Empty(t, resp.FxRate)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreditAccount_IdempotencyKeyReused(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.CreditRequest{AccountId: "acc-123", Amount: 2000, IdempotencyKey: "credit-1"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`)).
+		WithArgs(creditAccountScope, req.IdempotencyKey, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT request_hash, response FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`)).
+		WithArgs(creditAccountScope, req.IdempotencyKey).
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow("hash-of-another-request", []byte{}))
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.CreditAccount(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreditAccount_ExistingAccount(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.CreditRequest{AccountId: "acc-123", Amount: 2000} // Credit 20.00
+	currentBalance := int64(10000)                                      // 100.00
+	newBalance := currentBalance + req.Amount
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockBalance(mockDb, req.AccountId, "GBP", currentBalance, int64(0))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(newBalance, req.AccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournal(mockDb, req.AccountId, "GBP", req.Amount, newBalance, fundingAccountID, "credit")
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.CreditAccount(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, req.AccountId, resp.AccountId)
+	assert.Equal(t, newBalance, resp.CurrentBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreditAccount_NonPositiveAmount(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// A zero or negative credit would move money out of the account without a debit's checks
+	for _, amount := range []int64{0, -5000} {
+		resp, err := s.CreditAccount(context.Background(), &balancepb.CreditRequest{AccountId: "acc-123", Amount: amount})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "amount %d", amount)
+	}
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreditAccount_AccountNotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.CreditRequest{AccountId: "acc-unknown", Amount: 5000}
+
+	// The account isn't opened on its first credit
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT currency FROM accounts WHERE account_id = $1`)).
+		WithArgs(req.AccountId).
+		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.CreditAccount(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// lockHoldQuery selects a hold for update
+var lockHoldQuery = regexp.QuoteMeta(`SELECT hold_id, account_id, currency, amount, status, original_currency, original_amount, fx_rate, fx_fee
+			  FROM holds WHERE hold_id = $1 FOR UPDATE`)
+
+// holdColumns are the columns selected by lockHoldQuery
+var holdColumns = []string{"hold_id", "account_id", "currency", "amount", "status", "original_currency", "original_amount", "fx_rate", "fx_fee"}
+
+func TestCreditAccount_ConvertsCurrency(t *testing.T) {
+	s, mockDb := newTestServer(t, WithRateProvider(fx.StaticRates{"GBP/USD": "1.25"}), WithFXFee(275))
+	defer s.db.Close()
+
+	// 25.00 USD credited to a GBP account is 20.00 GBP, without a fee
+	req := &balancepb.CreditRequest{AccountId: "acc-123", Amount: 2500, Currency: "USD"}
+	newBalance := int64(10000 + 2000)
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	mockDb.ExpectQuery(lockBalanceQuery).
+		WithArgs(req.AccountId, "USD").
+		WillReturnError(sql.ErrNoRows)
+	expectLockBalance(mockDb, req.AccountId, "GBP", 10000, 0)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(newBalance, req.AccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), req.AccountId, "GBP", int64(2000), sql.NullInt64{Int64: newBalance, Valid: true}, "credit").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectSystemLeg(mockDb, fxAccountID, "GBP", -2000, "credit")
+	expectSystemLeg(mockDb, fxAccountID, "USD", 2500, "credit")
+	expectSystemLeg(mockDb, fundingAccountID, "USD", -2500, "credit")
+	expectLedgerCheck(mockDb, req.AccountId, "GBP", newBalance)
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.CreditAccount(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "GBP", resp.Currency)
+	assert.Equal(t, newBalance, resp.CurrentBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// expectLockHold sets up the mock expectations for locking an unconverted GBP hold and its balance
+func expectLockHold(mockDb sqlmock.Sqlmock, holdID, accountID string, amount int64, holdStatus string, balance, held int64) {
+	mockDb.ExpectQuery(lockHoldQuery).
+		WithArgs(holdID).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(holdID, accountID, "GBP", amount, holdStatus, nil, nil, nil, nil))
+	expectLockBalance(mockDb, accountID, "GBP", balance, held)
+}
+
+func TestCaptureHold_Success(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Settle for less than was authorized, e.g. a tip adjustment or partial shipment
+	req := &balancepb.CaptureHoldRequest{HoldId: "hold-1", Amount: 4500}
+	holdAmount := int64(5000)
+	currentBalance := int64(10000)
+	held := int64(6000)
+	newBalance := currentBalance - req.Amount
+	newHeld := held - holdAmount
+
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, req.HoldId, "acc-123", holdAmount, holdActive, currentBalance, held)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, held = $2, updated_at = NOW() WHERE account_id = $3 AND currency = $4`)).
+		WithArgs(newBalance, newHeld, "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournal(mockDb, "acc-123", "GBP", -req.Amount, newBalance, settlementAccountID, "capture hold hold-1")
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE holds SET status = $1, captured_amount = $2, journal_id = $3, updated_at = NOW() WHERE hold_id = $4`)).
+		WithArgs(holdCaptured, req.Amount, sqlmock.AnyArg(), req.HoldId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, "acc-123")
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.CaptureHold(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, "acc-123", resp.AccountId)
+	assert.Equal(t, newBalance, resp.CurrentBalance)
+	assert.Equal(t, newBalance-newHeld, resp.AvailableBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCaptureHold_LedgerMismatch(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.CaptureHoldRequest{HoldId: "hold-1"}
+	currentBalance := int64(10000)
+	holdAmount := int64(5000)
+	newBalance := currentBalance - holdAmount
+
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, req.HoldId, "acc-123", holdAmount, holdActive, currentBalance, holdAmount)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, held = $2, updated_at = NOW() WHERE account_id = $3 AND currency = $4`)).
+		WithArgs(newBalance, int64(0), "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).WillReturnResult(sqlmock.NewResult(2, 1))
+	expectLedgerCheck(mockDb, "acc-123", "GBP", int64(1)) // Ledger has drifted from the cached balance
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.CaptureHold(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCaptureHold_Converted(t *testing.T) {
+	s, mockDb := newTestServer(t, WithFXFee(275))
+	defer s.db.Close()
+
+	// Authorized as 12.34 EUR for 11.05 GBP, but settled for 10.00 EUR at the same rate:
+	// 8.71 GBP plus a fee of 0.24
+	req := &balancepb.CaptureHoldRequest{HoldId: "hold-1", Amount: 1000}
+	currentBalance := int64(10000)
+	newBalance := currentBalance - 895
+	description := "capture hold hold-1"
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(lockHoldQuery).
+		WithArgs(req.HoldId).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(req.HoldId, "acc-123", "GBP", int64(1105), holdActive, "EUR", int64(1234), "0.8712", int64(30)))
+	expectLockBalance(mockDb, "acc-123", "GBP", currentBalance, 1105)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, held = $2, updated_at = NOW() WHERE account_id = $3 AND currency = $4`)).
+		WithArgs(newBalance, int64(0), "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), "acc-123", "GBP", int64(-895), sql.NullInt64{Int64: newBalance, Valid: true}, description).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectSystemLeg(mockDb, fxAccountID, "GBP", 871, description)
+	expectSystemLeg(mockDb, fxAccountID, "EUR", -1000, description)
+	expectSystemLeg(mockDb, settlementAccountID, "EUR", 1000, description)
+	expectSystemLeg(mockDb, feesAccountID, "GBP", 24, description)
+	expectLedgerCheck(mockDb, "acc-123", "GBP", newBalance)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE holds SET status = $1, captured_amount = $2, journal_id = $3, updated_at = NOW() WHERE hold_id = $4`)).
+		WithArgs(holdCaptured, int64(895), sqlmock.AnyArg(), req.HoldId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, "acc-123")
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.CaptureHold(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "GBP", resp.Currency)
+	assert.Equal(t, newBalance, resp.CurrentBalance)
+	assert.Equal(t, newBalance, resp.AvailableBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReleaseHold_Success(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.HoldID{HoldId: "hold-1"}
+	currentBalance := int64(10000)
+	holdAmount := int64(5000)
+
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, req.HoldId, "acc-123", holdAmount, holdActive, currentBalance, holdAmount)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(int64(0), "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE holds SET status = $1, updated_at = NOW() WHERE hold_id = $2`)).
+		WithArgs(holdReleased, req.HoldId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, "acc-123")
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.ReleaseHold(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, currentBalance, resp.CurrentBalance)
+	assert.Equal(t, currentBalance, resp.AvailableBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReleaseHold_AlreadyCaptured(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.HoldID{HoldId: "hold-1"}
+
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, req.HoldId, "acc-123", 5000, holdCaptured, 5000, 0)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.ReleaseHold(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReduceHold_Converted(t *testing.T) {
+	s, mockDb := newTestServer(t, WithFXFee(275))
+	defer s.db.Close()
+
+	// Authorized as 12.34 EUR for 11.05 GBP, then 2.34 EUR reversed: the remaining
+	// 10.00 EUR is held as 8.71 GBP plus a fee of 0.24 at the authorization's rate
+	req := &balancepb.ReduceHoldRequest{HoldId: "hold-1", Amount: 234}
+	currentBalance := int64(10000)
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(lockHoldQuery).
+		WithArgs(req.HoldId).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(req.HoldId, "acc-123", "GBP", int64(1105), holdActive, "EUR", int64(1234), "0.8712", int64(30)))
+	expectLockBalance(mockDb, "acc-123", "GBP", currentBalance, 1105)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(int64(895), "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE holds SET amount = $1, original_amount = $2, fx_fee = $3, updated_at = NOW() WHERE hold_id = $4`)).
+		WithArgs(int64(895), sql.NullInt64{Int64: 1000, Valid: true}, sql.NullInt64{Int64: 24, Valid: true}, req.HoldId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, "acc-123")
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.ReduceHold(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), resp.RemainingAmount)
+	assert.Equal(t, currentBalance, resp.Balance.CurrentBalance)
+	assert.Equal(t, currentBalance-895, resp.Balance.AvailableBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReduceHold_WholeAmount(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Reducing a hold by everything it holds is a release
+	req := &balancepb.ReduceHoldRequest{HoldId: "hold-1", Amount: 5000}
+
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, req.HoldId, "acc-123", 5000, holdActive, 10000, 5000)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.ReduceHold(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestExpireHolds(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.ExpireHoldsRequest{Limit: 10}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT hold_id FROM holds WHERE status = 'ACTIVE' AND expires_at < NOW() ORDER BY expires_at LIMIT 10`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hold_id"}).AddRow("hold-1").AddRow("hold-2"))
+
+	// hold-1 expires
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, "hold-1", "acc-123", 5000, holdActive, 10000, 5000)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(int64(0), "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE holds SET status = $1, updated_at = NOW() WHERE hold_id = $2`)).
+		WithArgs(holdExpired, "hold-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, "acc-123")
+	mockDb.ExpectCommit()
+
+	// hold-2 was captured after it was listed, so it is skipped
+	mockDb.ExpectBegin()
+	expectLockHold(mockDb, "hold-2", "acc-456", 2000, holdCaptured, 8000, 0)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.ExpireHolds(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.Expired)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListLedgerEntries(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.ListLedgerEntriesRequest{AccountId: "acc-123", Limit: 2, BeforeId: "10"}
+	now := time.Now()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, journal_id, account_id, currency, amount, balance_after, description, created_at
+			  FROM ledger_entries WHERE account_id = $1 AND entry_id < $2 ORDER BY entry_id DESC LIMIT 2`)).
+		WithArgs(req.AccountId, int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"entry_id", "journal_id", "account_id", "currency", "amount", "balance_after", "description", "created_at"}).
+			AddRow(int64(9), "journal-2", req.AccountId, "GBP", int64(-5000), int64(5000), "debit", now).
+			AddRow(int64(7), "journal-1", req.AccountId, "GBP", int64(10000), int64(10000), "credit", now))
+
+	ctx := context.Background()
+	resp, err := s.ListLedgerEntries(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Entries, 2)
+	assert.Equal(t, "9", resp.Entries[0].EntryId)
+	assert.Equal(t, int64(-5000), resp.Entries[0].Amount)
+	assert.Equal(t, int64(5000), resp.Entries[0].BalanceAfter)
+	assert.Equal(t, "GBP", resp.Entries[0].Currency)
+	assert.Equal(t, "journal-1", resp.Entries[1].JournalId)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListLedgerEntries_InvalidCursor(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.ListLedgerEntriesRequest{AccountId: "acc-123", BeforeId: "not-a-number"}
+
+	ctx := context.Background()
+	resp, err := s.ListLedgerEntries(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestSetOverdraftLimit(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &balancepb.SetOverdraftLimitRequest{AccountId: "acc-123", Limit: 50000}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.AccountId, "GBP")
+	expectLockOverdrawnBalance(mockDb, req.AccountId, "GBP", -2000, 500, 10000)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE accounts SET overdraft_limit = $1, updated_at = NOW() WHERE account_id = $2`)).
+		WithArgs(req.Limit, req.AccountId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, req.AccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.SetOverdraftLimit(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-2000), resp.CurrentBalance)
+	assert.Equal(t, int64(-2000-500+50000), resp.AvailableBalance)
+	assert.Equal(t, req.Limit, resp.OverdraftLimit)
+	assert.Equal(t, "GBP", resp.Currency)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+
+	_, err = s.SetOverdraftLimit(ctx, &balancepb.SetOverdraftLimitRequest{AccountId: "acc-123", Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// overdrawnAccountsQuery selects the accounts that closed a day overdrawn and have not been charged for it
+var overdrawnAccountsQuery = regexp.QuoteMeta(`SELECT a.account_id, a.currency, SUM(l.amount) FROM accounts a`)
+
+func TestAccrueOverdraftCharges(t *testing.T) {
+	s, mockDb := newTestServer(t, WithOverdraftInterest(3990), WithOverdraftFee(50))
+	defer s.db.Close()
+
+	req := &balancepb.AccrueOverdraftChargesRequest{Date: "2025-01-14", Limit: 100}
+	// A day's interest on 1000.00 at 39.9% a year is 1.09, plus the 0.50 fee
+	closing := int64(-100000)
+	newBalance := closing - 159
+
+	mockDb.ExpectQuery(overdrawnAccountsQuery).
+		WithArgs(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), req.Date).
+		WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "sum"}).
+			AddRow("acc-123", "GBP", closing).
+			AddRow("acc-456", "GBP", int64(-5000)))
+
+	mockDb.ExpectBegin()
+	expectLockOverdrawnBalance(mockDb, "acc-123", "GBP", closing, 0, 150000)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(newBalance, "acc-123", "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), "acc-123", "GBP", int64(-159), sql.NullInt64{Int64: newBalance, Valid: true}, "overdraft charges for 2025-01-14").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectSystemLeg(mockDb, interestAccountID, "GBP", 109, "overdraft interest for 2025-01-14")
+	expectSystemLeg(mockDb, feesAccountID, "GBP", 50, "overdraft fee for 2025-01-14")
+	expectLedgerCheck(mockDb, "acc-123", "GBP", newBalance)
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO overdraft_accruals`)).
+		WithArgs("acc-123", req.Date, -closing, int64(109), int64(50), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectBalanceEvent(mockDb, "acc-123")
+	mockDb.ExpectCommit()
+
+	// The second account was charged for the day by a concurrent run
+	mockDb.ExpectBegin()
+	expectLockOverdrawnBalance(mockDb, "acc-456", "GBP", -5000, 0, 10000)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).WillReturnResult(sqlmock.NewResult(2, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).WillReturnResult(sqlmock.NewResult(3, 1))
+	expectLedgerCheck(mockDb, "acc-456", "GBP", -5000-55)
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO overdraft_accruals`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.AccrueOverdraftCharges(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.Charged)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestAccrueOverdraftCharges_InvalidDate(t *testing.T) {
+	s, mockDb := newTestServer(t, WithOverdraftFee(50))
+	defer s.db.Close()
+
+	ctx := context.Background()
+	today := time.Now().UTC().Format("2006-01-02")
+	for _, date := range []string{"14/01/2025", today} {
+		_, err := s.AccrueOverdraftCharges(ctx, &balancepb.AccrueOverdraftChargesRequest{Date: date})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), date)
+	}
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestTransfer_NewAccount(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Move 25.00 from an account into a pot that has never held money
+	req := &balancepb.TransferRequest{FromAccountId: "acc-123", ToAccountId: "pot-1", Amount: 2500, Description: "deposit to pot pot-1"}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.FromAccountId, "GBP")
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT currency FROM accounts WHERE account_id = $1`)).
+		WithArgs(req.ToAccountId).
+		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO accounts (account_id, currency, updated_at) VALUES ($1, $2, NOW())`)).
+		WithArgs(req.ToAccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO balances (account_id, currency, balance, held, updated_at) VALUES ($1, $2, 0, 0, NOW())`)).
+		WithArgs(req.ToAccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockBalance(mockDb, req.FromAccountId, "GBP", 10000, 1000)
+	expectLockBalance(mockDb, req.ToAccountId, "GBP", 0, 0)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(int64(7500), req.FromAccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`)).
+		WithArgs(int64(2500), req.ToAccountId, "GBP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), req.FromAccountId, "GBP", int64(-2500), sql.NullInt64{Int64: 7500, Valid: true}, req.Description).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(ledgerInsertQuery).
+		WithArgs(sqlmock.AnyArg(), req.ToAccountId, "GBP", int64(2500), sql.NullInt64{Int64: 2500, Valid: true}, req.Description).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	expectLedgerCheck(mockDb, req.FromAccountId, "GBP", 7500)
+	expectLedgerCheck(mockDb, req.ToAccountId, "GBP", 2500)
+	expectBalanceEvent(mockDb, req.FromAccountId)
+	expectBalanceEvent(mockDb, req.ToAccountId)
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	resp, err := s.Transfer(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.NotEmpty(t, resp.JournalId)
+	assert.Equal(t, int64(7500), resp.From.CurrentBalance)
+	assert.Equal(t, int64(6500), resp.From.AvailableBalance)
+	assert.Equal(t, int64(2500), resp.To.CurrentBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestTransfer_InsufficientFunds(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// The overdraft cannot be moved into a pot
+	req := &balancepb.TransferRequest{FromAccountId: "acc-123", ToAccountId: "pot-1", Amount: 2500}
+
+	mockDb.ExpectBegin()
+	expectAccountCurrency(mockDb, req.FromAccountId, "GBP")
+	expectAccountCurrency(mockDb, req.ToAccountId, "GBP")
+	expectLockOverdrawnBalance(mockDb, req.FromAccountId, "GBP", 2000, 0, 50000)
+	expectLockBalance(mockDb, req.ToAccountId, "GBP", 0, 0)
+	mockDb.ExpectRollback()
+
+	ctx := context.Background()
+	resp, err := s.Transfer(ctx, req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "insufficient funds", resp.ErrorMessage)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestTransfer_InvalidArgument(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	ctx := context.Background()
+	for _, req := range []*balancepb.TransferRequest{
+		{FromAccountId: "acc-123", ToAccountId: "pot-1", Amount: 0},
+		{FromAccountId: "acc-123", Amount: 100},
+		{FromAccountId: "acc-123", ToAccountId: "acc-123", Amount: 100},
+	} {
+		_, err := s.Transfer(ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), req.String())
+	}
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/internal/balance/fx"
+	"github.com/manifoldfinance/disco2/v2/pkg/idempotency"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+// Hold statuses
+const (
+	holdActive   = "ACTIVE"
+	holdCaptured = "CAPTURED"
+	holdReleased = "RELEASED"
+	holdExpired  = "EXPIRED"
+)
+
+// hold is an authorization hold locked for update within a transaction
+type hold struct {
+	id         string
+	accountID  string
+	currency   string
+	amount     int64
+	status     string
+	conversion *fx.Conversion // set when the debit was converted into currency
+}
+
+// lockHold selects a hold and the balance it is held against with FOR UPDATE
+func lockHold(ctx context.Context, tx *sql.Tx, holdID string) (*hold, accountBalance, error) {
+	var h hold
+	var originalCurrency, fxRate sql.NullString
+	var originalAmount, fxFee sql.NullInt64
+	query := `SELECT hold_id, account_id, currency, amount, status, original_currency, original_amount, fx_rate, fx_fee
+			  FROM holds WHERE hold_id = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, holdID).Scan(&h.id, &h.accountID, &h.currency, &h.amount, &h.status,
+		&originalCurrency, &originalAmount, &fxRate, &fxFee); err != nil {
+		return nil, accountBalance{}, err
+	}
+
+	if originalCurrency.Valid {
+		rate, err := fx.ParseRate(fxRate.String)
+		if err != nil {
+			return nil, accountBalance{}, fmt.Errorf("hold %s: %w", h.id, err)
+		}
+		h.conversion = &fx.Conversion{
+			From:      originalCurrency.String,
+			To:        h.currency,
+			Amount:    originalAmount.Int64,
+			Rate:      rate,
+			Converted: h.amount - fxFee.Int64,
+			Fee:       fxFee.Int64,
+		}
+	}
+
+	bal, err := lockBalance(ctx, tx, h.accountID, h.currency)
+	if err != nil {
+		return nil, accountBalance{}, fmt.Errorf("failed to lock %s balance of account %s: %w", h.currency, h.accountID, err)
+	}
+
+	return &h, bal, nil
+}
+
+// CaptureHold settles a hold, debiting the captured amount from the ledger balance.
+// The captured amount may differ from the held amount, e.g. when a card payment settles for a different total.
+// For a converted debit it is in the currency the debit was requested in, and is converted at the authorization's rate.
+func (s *BalanceService) CaptureHold(ctx context.Context, req *pb.CaptureHoldRequest) (*pb.BalanceResponse, error) {
+	log.Printf("Received CaptureHold request: %+v", req)
+
+	if req.GetAmount() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must not be negative")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	h, bal, err := lockHold(ctx, tx, req.GetHoldId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("hold not found: %s", req.GetHoldId())
+			return nil, status.Errorf(codes.NotFound, "hold not found")
+		}
+		log.Printf("failed to lock hold %s: %v", req.GetHoldId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+	if h.status != holdActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "hold is %s", h.status)
+	}
+
+	captureAmount := req.GetAmount()
+	conv := h.conversion
+	switch {
+	case captureAmount == 0:
+		captureAmount = h.amount
+	case conv != nil:
+		if captureAmount != conv.Amount {
+			settled := fx.Convert(captureAmount, conv.From, conv.To, conv.Rate, s.fxFeeBps)
+			conv = &settled
+		}
+		captureAmount = conv.Total()
+	}
+
+	bal.balance -= captureAmount
+	bal.held -= h.amount
+	updateQuery := `UPDATE balances SET balance = $1, held = $2, updated_at = NOW() WHERE account_id = $3 AND currency = $4`
+	if _, err := tx.ExecContext(ctx, updateQuery, bal.balance, bal.held, h.accountID, h.currency); err != nil {
+		log.Printf("failed to update balance: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+
+	// Record the debit in the ledger against the settlement account
+	description := "capture hold " + h.id
+	postings := []posting{customerPosting(h.accountID, h.currency, -captureAmount, bal.balance, description)}
+	if conv != nil {
+		postings = append(postings, exchangePostings(*conv, settlementAccountID, true, description)...)
+	} else {
+		postings = append(postings, systemPosting(settlementAccountID, h.currency, captureAmount, description))
+	}
+	journalID, err := postJournal(ctx, tx, postings...)
+	if err != nil {
+		log.Printf("failed to post capture to ledger: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+	if err := checkLedgerBalance(ctx, tx, h.accountID, h.currency, bal.balance); err != nil {
+		log.Printf("ledger check failed after capture: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+
+	holdQuery := `UPDATE holds SET status = $1, captured_amount = $2, journal_id = $3, updated_at = NOW() WHERE hold_id = $4`
+	if _, err := tx.ExecContext(ctx, holdQuery, holdCaptured, captureAmount, journalID, h.id); err != nil {
+		log.Printf("failed to update hold status: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+
+	// Queue "balance.updated" event in the same transaction
+	if err := enqueueBalanceUpdateEvent(ctx, tx, h.accountID, h.currency, bal); err != nil {
+		log.Printf("failed to enqueue balance:updated event after capture: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to capture hold")
+	}
+
+	log.Printf("Captured %d of hold %s (held %d) on account %s. New balance: %d %s", captureAmount, h.id, h.amount, h.accountID, bal.balance, h.currency)
+
+	return bal.response(h.accountID, h.currency), nil
+}
+
+// ReleaseHold cancels a hold without moving money, restoring the available balance
+func (s *BalanceService) ReleaseHold(ctx context.Context, req *pb.HoldID) (*pb.BalanceResponse, error) {
+	log.Printf("Received ReleaseHold request: %+v", req)
+
+	return s.finishHold(ctx, req.GetHoldId(), holdReleased)
+}
+
+// ReduceHold releases part of an active hold, e.g. when a merchant reverses part of an authorization.
+// The amount is in the currency the debit was authorized in; a converted hold keeps its authorization rate.
+func (s *BalanceService) ReduceHold(ctx context.Context, req *pb.ReduceHoldRequest) (*pb.ReduceHoldResult, error) {
+	log.Printf("Received ReduceHold request: %+v", req)
+
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Replay the original result if this is a retry
+	if req.GetIdempotencyKey() != "" {
+		var previous pb.ReduceHoldResult
+		replayed, err := idempotency.Claim(ctx, tx, reduceHoldScope, req.GetIdempotencyKey(), req, &previous)
+		if err != nil {
+			return nil, idempotencyError(err, "failed to reduce hold")
+		}
+		if replayed {
+			log.Printf("Replaying ReduceHold result for idempotency key %s", req.GetIdempotencyKey())
+			return &previous, nil
+		}
+	}
+
+	h, bal, err := lockHold(ctx, tx, req.GetHoldId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("hold not found: %s", req.GetHoldId())
+			return nil, status.Errorf(codes.NotFound, "hold not found")
+		}
+		log.Printf("failed to lock hold %s: %v", req.GetHoldId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+	}
+	if h.status != holdActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "hold is %s", h.status)
+	}
+
+	authorized := h.amount
+	if h.conversion != nil {
+		authorized = h.conversion.Amount
+	}
+	if req.GetAmount() >= authorized {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be less than the authorized %d, release the hold instead", authorized)
+	}
+
+	remaining := authorized - req.GetAmount()
+	heldAmount := remaining
+	var conv *fx.Conversion
+	if h.conversion != nil {
+		reduced := fx.Convert(remaining, h.conversion.From, h.conversion.To, h.conversion.Rate, s.fxFeeBps)
+		conv = &reduced
+		heldAmount = conv.Total()
+	}
+
+	bal.held -= h.amount - heldAmount
+	updateQuery := `UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`
+	if _, err := tx.ExecContext(ctx, updateQuery, bal.held, h.accountID, h.currency); err != nil {
+		log.Printf("failed to update held amount: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+	}
+
+	_, originalAmount, _, fxFee := conversionColumns(conv)
+	holdQuery := `UPDATE holds SET amount = $1, original_amount = $2, fx_fee = $3, updated_at = NOW() WHERE hold_id = $4`
+	if _, err := tx.ExecContext(ctx, holdQuery, heldAmount, originalAmount, fxFee, h.id); err != nil {
+		log.Printf("failed to update hold amount: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+	}
+
+	// Queue "balance.updated" event in the same transaction
+	if err := enqueueBalanceUpdateEvent(ctx, tx, h.accountID, h.currency, bal); err != nil {
+		log.Printf("failed to enqueue balance:updated event after reducing hold: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+	}
+
+	result := &pb.ReduceHoldResult{Balance: bal.response(h.accountID, h.currency), RemainingAmount: remaining}
+	if req.GetIdempotencyKey() != "" {
+		if err := idempotency.Complete(ctx, tx, reduceHoldScope, req.GetIdempotencyKey(), result); err != nil {
+			log.Printf("failed to store idempotency key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to reduce hold")
+	}
+
+	log.Printf("Reduced hold %s on account %s from %d to %d. Available balance: %d", h.id, h.accountID, h.amount, heldAmount, bal.available())
+
+	return result, nil
+}
+
+// ExpireHolds releases active holds that have passed their expiry time
+func (s *BalanceService) ExpireHolds(ctx context.Context, req *pb.ExpireHoldsRequest) (*pb.ExpireHoldsResponse, error) {
+	log.Printf("Received ExpireHolds request: %+v", req)
+
+	query := `SELECT hold_id FROM holds WHERE status = 'ACTIVE' AND expires_at < NOW() ORDER BY expires_at`
+	if req.GetLimit() > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, req.GetLimit())
+	}
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("failed to list expired holds: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to expire holds")
+	}
+	var holdIDs []string
+	for rows.Next() {
+		var holdID string
+		if err := rows.Scan(&holdID); err != nil {
+			rows.Close()
+			log.Printf("failed to scan hold row: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to expire holds")
+		}
+		holdIDs = append(holdIDs, holdID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("rows error during listing expired holds: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to expire holds")
+	}
+
+	var expired uint32
+	for _, holdID := range holdIDs {
+		if _, err := s.finishHold(ctx, holdID, holdExpired); err != nil {
+			// A hold captured or released concurrently is no longer active; skip it
+			if status.Code(err) == codes.FailedPrecondition {
+				continue
+			}
+			return nil, err
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		log.Printf("Expired %d holds", expired)
+	}
+
+	return &pb.ExpireHoldsResponse{Expired: expired}, nil
+}
+
+// finishHold moves an active hold to a final status without touching the ledger balance
+func (s *BalanceService) finishHold(ctx context.Context, holdID, newStatus string) (*pb.BalanceResponse, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to release hold")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	h, bal, err := lockHold(ctx, tx, holdID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("hold not found: %s", holdID)
+			return nil, status.Errorf(codes.NotFound, "hold not found")
+		}
+		log.Printf("failed to lock hold %s: %v", holdID, err)
+		return nil, status.Errorf(codes.Internal, "failed to release hold")
+	}
+	if h.status != holdActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "hold is %s", h.status)
+	}
+
+	bal.held -= h.amount
+	updateQuery := `UPDATE balances SET held = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`
+	if _, err := tx.ExecContext(ctx, updateQuery, bal.held, h.accountID, h.currency); err != nil {
+		log.Printf("failed to update held amount: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to release hold")
+	}
+
+	holdQuery := `UPDATE holds SET status = $1, updated_at = NOW() WHERE hold_id = $2`
+	if _, err := tx.ExecContext(ctx, holdQuery, newStatus, h.id); err != nil {
+		log.Printf("failed to update hold status: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to release hold")
+	}
+
+	// Queue "balance.updated" event in the same transaction
+	if err := enqueueBalanceUpdateEvent(ctx, tx, h.accountID, h.currency, bal); err != nil {
+		log.Printf("failed to enqueue balance:updated event after releasing hold: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to release hold")
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to release hold")
+	}
+
+	log.Printf("Hold %s on account %s is now %s. Available balance: %d", h.id, h.accountID, newStatus, bal.available())
+
+	return bal.response(h.accountID, h.currency), nil
+}
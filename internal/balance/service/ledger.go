@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/manifoldfinance/disco2/v2/internal/balance/fx"
+)
+
+// System ledger accounts that sit on the other side of customer postings
+const (
+	// fundingAccountID is where money credited to customer accounts comes from
+	fundingAccountID = "system:funding"
+	// settlementAccountID is where money debited from customer accounts goes to
+	settlementAccountID = "system:settlement"
+	// fxAccountID exchanges one currency for another when a debit or credit is converted
+	fxAccountID = "system:fx"
+	// feesAccountID collects conversion and overdraft fees
+	feesAccountID = "system:fees"
+	// interestAccountID collects overdraft interest
+	interestAccountID = "system:interest"
+)
+
+// posting is a single leg of a journal
+type posting struct {
+	accountID    string
+	currency     string
+	amount       int64 // in minor units of currency, positive for credit, negative for debit
+	balanceAfter sql.NullInt64
+	description  string
+}
+
+// customerPosting builds the leg for a customer account, recording its resulting balance
+func customerPosting(accountID, currency string, amount, balanceAfter int64, description string) posting {
+	return posting{
+		accountID:    accountID,
+		currency:     currency,
+		amount:       amount,
+		balanceAfter: sql.NullInt64{Int64: balanceAfter, Valid: true},
+		description:  description,
+	}
+}
+
+// systemPosting builds the leg for a system account
+func systemPosting(accountID, currency string, amount int64, description string) posting {
+	return posting{accountID: accountID, currency: currency, amount: amount, description: description}
+}
+
+// exchangePostings builds the legs that exchange conv.Amount of conv.From for conv.Total() of conv.To
+// through the FX account, crediting the fee to the fees account. For an outgoing debit the counterparty
+// receives conv.Amount, for a credit it pays it. The caller adds the customer's leg in conv.To.
+func exchangePostings(conv fx.Conversion, counterpartyID string, outgoing bool, description string) []posting {
+	sign := int64(1)
+	if !outgoing {
+		sign = -1
+	}
+	postings := []posting{
+		systemPosting(fxAccountID, conv.To, sign*conv.Converted, description),
+		systemPosting(fxAccountID, conv.From, -sign*conv.Amount, description),
+		systemPosting(counterpartyID, conv.From, sign*conv.Amount, description),
+	}
+	if conv.Fee != 0 {
+		postings = append(postings, systemPosting(feesAccountID, conv.To, sign*conv.Fee, description))
+	}
+	return postings
+}
+
+// postJournal writes a set of postings that balances in each currency to the ledger within tx
+// and returns the journal ID
+func postJournal(ctx context.Context, tx *sql.Tx, postings ...posting) (string, error) {
+	if len(postings) < 2 {
+		return "", fmt.Errorf("unbalanced journal: %d postings", len(postings))
+	}
+	sums := make(map[string]int64)
+	for _, p := range postings {
+		sums[p.currency] += p.amount
+	}
+	for currency, sum := range sums {
+		if sum != 0 {
+			return "", fmt.Errorf("unbalanced journal: %s postings summing to %d", currency, sum)
+		}
+	}
+
+	journalID := uuid.New().String()
+	query := `INSERT INTO ledger_entries (journal_id, account_id, currency, amount, balance_after, description, created_at) VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+	for _, p := range postings {
+		if _, err := tx.ExecContext(ctx, query, journalID, p.accountID, p.currency, p.amount, p.balanceAfter, p.description); err != nil {
+			return "", fmt.Errorf("failed to insert ledger entry for %s: %w", p.accountID, err)
+		}
+	}
+
+	return journalID, nil
+}
+
+// checkLedgerBalance verifies that the ledger entries of an account's balance in currency add up to the expected balance
+func checkLedgerBalance(ctx context.Context, tx *sql.Tx, accountID, currency string, expected int64) error {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM ledger_entries WHERE account_id = $1 AND currency = $2`
+	var ledgerBalance int64
+	if err := tx.QueryRowContext(ctx, query, accountID, currency).Scan(&ledgerBalance); err != nil {
+		return fmt.Errorf("failed to sum ledger entries: %w", err)
+	}
+	if ledgerBalance != expected {
+		return fmt.Errorf("ledger mismatch for account %s in %s: ledger=%d, balance=%d", accountID, currency, ledgerBalance, expected)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+// accrualDateLayout is the format of the days overdraft charges are accrued for
+const accrualDateLayout = "2006-01-02"
+
+// SetOverdraftLimit sets the arranged overdraft of an account, letting the balance in its currency go negative
+// down to minus the limit. The limit may be lowered below what the account is overdrawn by, after which debits
+// are declined until the account is back within it.
+func (s *BalanceService) SetOverdraftLimit(ctx context.Context, req *pb.SetOverdraftLimitRequest) (*pb.BalanceResponse, error) {
+	log.Printf("Received SetOverdraftLimit request: %+v", req)
+
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	currency, err := lookupAccountCurrency(ctx, tx, req.GetAccountId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("account not found: %s", req.GetAccountId())
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		log.Printf("failed to get account currency: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit")
+	}
+	bal, err := lockBalance(ctx, tx, req.GetAccountId(), currency)
+	if err != nil {
+		log.Printf("failed to get balance with lock: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit")
+	}
+
+	updateQuery := `UPDATE accounts SET overdraft_limit = $1, updated_at = NOW() WHERE account_id = $2`
+	if _, err := tx.ExecContext(ctx, updateQuery, req.GetLimit(), req.GetAccountId()); err != nil {
+		log.Printf("failed to update overdraft limit: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit")
+	}
+	bal.overdraftLimit = req.GetLimit()
+
+	// The available balance changes with the limit
+	if err := enqueueBalanceUpdateEvent(ctx, tx, req.GetAccountId(), currency, bal); err != nil {
+		log.Printf("failed to enqueue balance:updated event after setting overdraft limit: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit")
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit")
+	}
+
+	log.Printf("Set overdraft limit of account %s to %d %s. Available balance: %d", req.GetAccountId(), bal.overdraftLimit, currency, bal.available())
+
+	return bal.response(req.GetAccountId(), currency), nil
+}
+
+// AccrueOverdraftCharges charges interest and the daily fee to every account that closed the given day
+// overdrawn, going by its ledger balance at midnight UTC. Each account is charged at most once per day,
+// so the call can be repeated until no accounts are left to charge.
+func (s *BalanceService) AccrueOverdraftCharges(ctx context.Context, req *pb.AccrueOverdraftChargesRequest) (*pb.AccrueOverdraftChargesResponse, error) {
+	log.Printf("Received AccrueOverdraftCharges request: %+v", req)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := today.AddDate(0, 0, -1)
+	if req.GetDate() != "" {
+		var err error
+		day, err = time.Parse(accrualDateLayout, req.GetDate())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date: %s", req.GetDate())
+		}
+		if !day.Before(today) {
+			return nil, status.Errorf(codes.InvalidArgument, "date must be before today")
+		}
+	}
+	if s.overdraftInterestBps == 0 && s.overdraftFee == 0 {
+		return &pb.AccrueOverdraftChargesResponse{}, nil
+	}
+
+	// Closing balances come from the ledger, which is never posted to in the past
+	query := `SELECT a.account_id, a.currency, SUM(l.amount) FROM accounts a
+			  JOIN ledger_entries l ON l.account_id = a.account_id::TEXT AND l.currency = a.currency AND l.created_at < $1
+			  WHERE NOT EXISTS (SELECT 1 FROM overdraft_accruals o WHERE o.account_id = a.account_id AND o.accrued_on = $2)
+			  GROUP BY a.account_id, a.currency HAVING SUM(l.amount) < 0 ORDER BY a.account_id`
+	if req.GetLimit() > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, req.GetLimit())
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, day.AddDate(0, 0, 1), day.Format(accrualDateLayout))
+	if err != nil {
+		log.Printf("failed to list overdrawn accounts: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to accrue overdraft charges")
+	}
+	type overdrawnAccount struct {
+		accountID string
+		currency  string
+		closing   int64
+	}
+	var accounts []overdrawnAccount
+	for rows.Next() {
+		var a overdrawnAccount
+		if err := rows.Scan(&a.accountID, &a.currency, &a.closing); err != nil {
+			rows.Close()
+			log.Printf("failed to scan overdrawn account row: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to accrue overdraft charges")
+		}
+		accounts = append(accounts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("rows error during listing overdrawn accounts: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to accrue overdraft charges")
+	}
+
+	var charged uint32
+	for _, a := range accounts {
+		ok, err := s.chargeOverdraft(ctx, a.accountID, a.currency, day, -a.closing)
+		if err != nil {
+			log.Printf("failed to charge overdraft of account %s for %s: %v", a.accountID, day.Format(accrualDateLayout), err)
+			return nil, status.Errorf(codes.Internal, "failed to accrue overdraft charges")
+		}
+		if ok {
+			charged++
+		}
+	}
+
+	if charged > 0 {
+		log.Printf("Charged overdraft interest and fees to %d accounts for %s", charged, day.Format(accrualDateLayout))
+	}
+
+	return &pb.AccrueOverdraftChargesResponse{Charged: charged}, nil
+}
+
+// chargeOverdraft debits a day's overdraft interest and fee from an account that closed the day overdrawn by
+// the given amount. It reports false if there was nothing to charge or the day was already charged.
+func (s *BalanceService) chargeOverdraft(ctx context.Context, accountID, currency string, day time.Time, overdrawn int64) (bool, error) {
+	interest := dailyInterest(overdrawn, s.overdraftInterestBps)
+	fee := s.overdraftFee
+	if interest+fee == 0 {
+		return false, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	bal, err := lockBalance(ctx, tx, accountID, currency)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock balance: %w", err)
+	}
+
+	bal.balance -= interest + fee
+	updateQuery := `UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`
+	if _, err := tx.ExecContext(ctx, updateQuery, bal.balance, accountID, currency); err != nil {
+		return false, fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	// Record the charges in the ledger against the interest and fees accounts
+	date := day.Format(accrualDateLayout)
+	postings := []posting{customerPosting(accountID, currency, -(interest + fee), bal.balance, "overdraft charges for "+date)}
+	if interest != 0 {
+		postings = append(postings, systemPosting(interestAccountID, currency, interest, "overdraft interest for "+date))
+	}
+	if fee != 0 {
+		postings = append(postings, systemPosting(feesAccountID, currency, fee, "overdraft fee for "+date))
+	}
+	journalID, err := postJournal(ctx, tx, postings...)
+	if err != nil {
+		return false, fmt.Errorf("failed to post overdraft charges to ledger: %w", err)
+	}
+	if err := checkLedgerBalance(ctx, tx, accountID, currency, bal.balance); err != nil {
+		return false, err
+	}
+
+	// A concurrent run that charged the day first wins
+	accrualQuery := `INSERT INTO overdraft_accruals (account_id, accrued_on, overdrawn, interest, fee, journal_id, created_at)
+					 VALUES ($1, $2, $3, $4, $5, $6, NOW()) ON CONFLICT (account_id, accrued_on) DO NOTHING`
+	result, err := tx.ExecContext(ctx, accrualQuery, accountID, date, overdrawn, interest, fee, journalID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record overdraft accrual: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record overdraft accrual: %w", err)
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	// Queue "balance.updated" event in the same transaction
+	if err := enqueueBalanceUpdateEvent(ctx, tx, accountID, currency, bal); err != nil {
+		return false, fmt.Errorf("failed to enqueue balance:updated event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Charged account %s overdraft interest %d and fee %d %s for %s. New balance: %d", accountID, interest, fee, currency, date, bal.balance)
+
+	return true, nil
+}
+
+// dailyInterest is one day's interest on amount at an annual rate in basis points, rounded half up
+func dailyInterest(amount, annualBps int64) int64 {
+	const divisor = 10000 * 365
+	return (amount*annualBps + divisor/2) / divisor
+}
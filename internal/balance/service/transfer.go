@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/idempotency"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+// Transfer moves money between two accounts in a single ledger journal, e.g. from an account into one of its pots.
+// Only money the source account holds can be moved, never its overdraft. The destination account is opened in
+// the source account's currency if it does not exist yet, as CreditAccount does.
+func (s *BalanceService) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResult, error) {
+	log.Printf("Received Transfer request: %+v", req)
+
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+	if req.GetFromAccountId() == "" || req.GetToAccountId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "from_account_id and to_account_id are required")
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot transfer to the same account")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to transfer")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Replay the original result if this is a retry
+	if req.GetIdempotencyKey() != "" {
+		var previous pb.TransferResult
+		replayed, err := idempotency.Claim(ctx, tx, transferScope, req.GetIdempotencyKey(), req, &previous)
+		if err != nil {
+			return nil, idempotencyError(err, "failed to transfer")
+		}
+		if replayed {
+			log.Printf("Replaying Transfer result for idempotency key %s", req.GetIdempotencyKey())
+			return &previous, nil
+		}
+	}
+
+	fromID, toID := req.GetFromAccountId(), req.GetToAccountId()
+	currency, err := lookupAccountCurrency(ctx, tx, fromID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("account not found for transfer: %s", fromID)
+			return &pb.TransferResult{Success: false, ErrorMessage: "account not found"}, nil
+		}
+		log.Printf("failed to get account currency: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to transfer")
+	}
+	if _, err := lookupAccountCurrency(ctx, tx, toID); err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("failed to get account currency: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+		if err := openAccount(ctx, tx, toID, currency); err != nil {
+			log.Printf("failed to create account for transfer: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+		log.Printf("Created account %s in %s on first transfer", toID, currency)
+	}
+
+	// Lock both balances in a fixed order, so that opposite transfers between the same accounts cannot deadlock
+	balances := make(map[string]*accountBalance, 2)
+	ids := []string{fromID, toID}
+	sort.Strings(ids)
+	for _, id := range ids {
+		bal, err := lockBalance(ctx, tx, id, currency)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("account %s holds no %s balance for transfer", id, currency)
+				return nil, status.Errorf(codes.FailedPrecondition, "account %s holds no %s balance", id, currency)
+			}
+			log.Printf("failed to get balance with lock: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+		balances[id] = &bal
+	}
+	from, to := balances[fromID], balances[toID]
+
+	// Check the source holds the money, leaving its overdraft out
+	if from.balance-from.held < req.GetAmount() {
+		log.Printf("insufficient funds for transfer from account %s: available=%d, requested=%d %s", fromID, from.balance-from.held, req.GetAmount(), currency)
+		return &pb.TransferResult{Success: false, ErrorMessage: "insufficient funds"}, nil
+	}
+
+	from.balance -= req.GetAmount()
+	to.balance += req.GetAmount()
+	updateQuery := `UPDATE balances SET balance = $1, updated_at = NOW() WHERE account_id = $2 AND currency = $3`
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, updateQuery, balances[id].balance, id, currency); err != nil {
+			log.Printf("failed to update balance: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+	}
+
+	// Record the transfer in the ledger
+	description := req.GetDescription()
+	if description == "" {
+		description = "transfer"
+	}
+	journalID, err := postJournal(ctx, tx,
+		customerPosting(fromID, currency, -req.GetAmount(), from.balance, description),
+		customerPosting(toID, currency, req.GetAmount(), to.balance, description),
+	)
+	if err != nil {
+		log.Printf("failed to post transfer to ledger: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to transfer")
+	}
+	for _, id := range ids {
+		if err := checkLedgerBalance(ctx, tx, id, currency, balances[id].balance); err != nil {
+			log.Printf("ledger check failed after transfer: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+	}
+
+	result := &pb.TransferResult{
+		Success:   true,
+		From:      from.response(fromID, currency),
+		To:        to.response(toID, currency),
+		JournalId: journalID,
+	}
+	if req.GetIdempotencyKey() != "" {
+		if err := idempotency.Complete(ctx, tx, transferScope, req.GetIdempotencyKey(), result); err != nil {
+			log.Printf("failed to store idempotency key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+	}
+
+	// Queue "balance.updated" events in the same transaction
+	for _, id := range ids {
+		if err := enqueueBalanceUpdateEvent(ctx, tx, id, currency, *balances[id]); err != nil {
+			log.Printf("failed to enqueue balance:updated event after transfer: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to transfer")
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to transfer")
+	}
+
+	log.Printf("Transferred %d %s from account %s to account %s", req.GetAmount(), currency, fromID, toID)
+
+	return result, nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"net"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -10,14 +12,25 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 
+	"github.com/manifoldfinance/disco2/v2/pkg/iso8583"
+	"github.com/manifoldfinance/disco2/v2/pkg/mtls"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
 	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing"
 )
 
 type server struct {
 	cardProcessingClient cardprocessingpb.CardProcessingClient
+	vaultClient          vaultpb.VaultClient
 }
 
 func main() {
+	isoAddr := flag.String("iso8583-addr", ":8583", "address for ISO 8583 connections from the card network, empty to disable")
+	isoSpecPath := flag.String("iso8583-spec", "", "JSON file with the ISO 8583 field spec, empty for the default")
+	tlsCertPath := flag.String("tls-cert", "", "file with the client certificate Card-API presents to the vault")
+	tlsKeyPath := flag.String("tls-key", "", "file with the key of -tls-cert")
+	vaultCAPath := flag.String("vault-ca", "", "file with the CA certificate the vault's certificate is signed with")
+	flag.Parse()
+
 	// Set up gRPC client for Card-Processing service
 	cardProcessingConn, err := grpc.Dial("localhost:50050", grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -26,13 +39,49 @@ func main() {
 	defer cardProcessingConn.Close()
 	cardProcessingClient := cardprocessingpb.NewCardProcessingClient(cardProcessingConn)
 
+	// Set up gRPC client for the Vault service, to swap card numbers from the network for tokens
+	vaultCreds, err := mtls.ClientCredentials(*tlsCertPath, *tlsKeyPath, *vaultCAPath)
+	if err != nil {
+		log.Fatalf("failed to load vault TLS credentials: %v", err)
+	}
+	vaultConn, err := grpc.Dial("localhost:50060", grpc.WithTransportCredentials(vaultCreds))
+	if err != nil {
+		log.Fatalf("failed to connect to Vault service: %v", err)
+	}
+	defer vaultConn.Close()
+
 	s := &server{
 		cardProcessingClient: cardProcessingClient,
+		vaultClient:          vaultpb.NewVaultClient(vaultConn),
 	}
 
 	// Set up Echo HTTP server
 	e := echo.New()
 	e.POST("/cardAuth", s.cardAuthHandler)
+	e.POST("/reversal", s.reversalHandler)
+	e.POST("/partialReversal", s.partialReversalHandler)
+	e.POST("/refund", s.refundHandler)
+
+	// Start ISO 8583 listener
+	if *isoAddr != "" {
+		spec := iso8583.DefaultSpec()
+		if *isoSpecPath != "" {
+			spec, err = iso8583.LoadSpec(*isoSpecPath)
+			if err != nil {
+				log.Fatalf("failed to load ISO 8583 spec: %v", err)
+			}
+		}
+		lis, err := net.Listen("tcp", *isoAddr)
+		if err != nil {
+			log.Fatalf("failed to listen for ISO 8583: %v", err)
+		}
+		log.Printf("ISO 8583 listener at %v", lis.Addr())
+		go func() {
+			if err := s.serveISO8583(lis, spec); err != nil {
+				log.Fatalf("failed to serve ISO 8583: %v", err)
+			}
+		}()
+	}
 
 	// Start HTTP server
 	if err := e.Start(":8086"); err != nil && err != http.ErrServerClosed {
@@ -49,6 +98,15 @@ func (s *server) cardAuthHandler(c echo.Context) error {
 		Currency     string `json:"currency"`
 		MerchantId   string `json:"merchant_id"`
 		MerchantName string `json:"merchant_name"`
+		// MerchantCountry is the ISO 3166-1 alpha-2 code of the merchant's country, if known
+		MerchantCountry string `json:"merchant_country"`
+		// Channel is how the card was used: CHIP, CONTACTLESS, MAGSTRIPE, ONLINE or ATM, if known
+		Channel string `json:"channel"`
+		Mcc     int32  `json:"mcc"`
+		// Recurring marks a payment the merchant initiated with card details stored for recurring payments
+		Recurring bool `json:"recurring"`
+		// PinBlock is the PIN entered for a CHIP or ATM payment, as a hex ISO 9564 format 1 PIN block
+		PinBlock string `json:"pin_block"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
@@ -56,11 +114,16 @@ func (s *server) cardAuthHandler(c echo.Context) error {
 
 	// Create the gRPC request
 	grpcReq := &cardprocessingpb.CardAuthRequest{
-		CardId:       req.CardId,
-		Amount:       req.Amount,
-		Currency:     req.Currency,
-		MerchantId:   req.MerchantId,
-		MerchantName: req.MerchantName,
+		CardId:          req.CardId,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		MerchantId:      req.MerchantId,
+		MerchantName:    req.MerchantName,
+		MerchantCountry: req.MerchantCountry,
+		Channel:         req.Channel,
+		Mcc:             req.Mcc,
+		Recurring:       req.Recurring,
+		PinBlock:        req.PinBlock,
 	}
 
 	// Call the Card-Processing service
@@ -85,8 +148,116 @@ func (s *server) cardAuthHandler(c echo.Context) error {
 	}
 
 	// Return the result based on the gRPC response
+	// Declines carry their code and the response code a network would be sent for it
+	declineCode, responseCode := "", iso8583.ResponseApproved
+	if !grpcResp.GetApproved() {
+		declineCode = grpcResp.GetDeclineCode().String()
+		responseCode = declineResponseCode(grpcResp.GetDeclineCode())
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"approved":      grpcResp.GetApproved(),
+		"reason":        grpcResp.GetDeclineReason(),
+		"decline_code":  declineCode,
+		"response_code": responseCode,
+		"auth_code":     grpcResp.GetAuthCode(),
+	})
+}
+
+func (s *server) reversalHandler(c echo.Context) error {
+	var req struct {
+		TransactionId string `json:"transaction_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	grpcResp, err := s.cardProcessingClient.ReverseAuthorization(c.Request().Context(), &cardprocessingpb.ReversalRequest{
+		TransactionId: req.TransactionId,
+	})
+	if err != nil {
+		return reversalErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"transaction_id": grpcResp.GetTransactionId(),
+		"status":         grpcResp.GetStatus(),
+		"amount":         grpcResp.GetAmount(),
+	})
+}
+
+func (s *server) partialReversalHandler(c echo.Context) error {
+	var req struct {
+		TransactionId  string `json:"transaction_id"`
+		Amount         int64  `json:"amount"`
+		IdempotencyKey string `json:"idempotency_key"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	grpcResp, err := s.cardProcessingClient.PartialReversal(c.Request().Context(), &cardprocessingpb.PartialReversalRequest{
+		TransactionId:  req.TransactionId,
+		Amount:         req.Amount,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return reversalErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"transaction_id": grpcResp.GetTransactionId(),
+		"status":         grpcResp.GetStatus(),
+		"amount":         grpcResp.GetAmount(),
+	})
+}
+
+func (s *server) refundHandler(c echo.Context) error {
+	var req struct {
+		TransactionId  string `json:"transaction_id"`
+		Amount         int64  `json:"amount"`
+		IdempotencyKey string `json:"idempotency_key"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.IdempotencyKey == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "idempotency_key is required"})
+	}
+
+	grpcResp, err := s.cardProcessingClient.RefundTransaction(c.Request().Context(), &cardprocessingpb.RefundRequest{
+		TransactionId:  req.TransactionId,
+		Amount:         req.Amount,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return reversalErrorResponse(c, err)
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"approved": grpcResp.GetApproved(),
-		"reason":   grpcResp.GetDeclineReason(),
+		"refund_transaction_id":   grpcResp.GetRefundTransactionId(),
+		"original_transaction_id": grpcResp.GetOriginalTransactionId(),
+		"amount":                  grpcResp.GetAmount(),
+		"original_status":         grpcResp.GetOriginalStatus(),
 	})
 }
+
+// reversalErrorResponse maps a gRPC error from a reversal or refund to an HTTP response
+func reversalErrorResponse(c echo.Context, err error) error {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+		case codes.NotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+		case codes.FailedPrecondition, codes.AlreadyExists: // e.g. the transaction has settled, or the refund exceeds what is left
+			return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+		case codes.Internal:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "unknown gRPC error"})
+		}
+	}
+	log.Printf("unexpected gRPC error from card-processing: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	balancepb "github.com/sambacha/monzo/v2/balance"
+	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing"
+)
+
+// cardStatusDeclineCodes gives the decline code for a card that isn't ACTIVE. Cards in any
+// other status are declined as inactive.
+var cardStatusDeclineCodes = map[string]cardprocessingpb.DeclineCode{
+	"FROZEN": cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN,
+	"CLOSED": cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED,
+	"USED":   cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED, // a single-use card that has paid
+}
+
+// debitDeclineCodes gives the decline code for each reason Balance declines a debit with.
+// Any other reason, such as a card without an account, is a system error.
+var debitDeclineCodes = map[balancepb.DebitDeclineReason]cardprocessingpb.DeclineCode{
+	balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS:     cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS,
+	balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT:   cardprocessingpb.DeclineCode_DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED,
+	balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED: cardprocessingpb.DeclineCode_DECLINE_CODE_INVALID_CURRENCY,
+}
+
+// cardStatusDeclineCode returns the decline code for a card in cardStatus
+func cardStatusDeclineCode(cardStatus string) cardprocessingpb.DeclineCode {
+	if code, ok := cardStatusDeclineCodes[cardStatus]; ok {
+		return code
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_INACTIVE
+}
+
+// debitDeclineCode returns the decline code for a debit Balance declined for reason
+func debitDeclineCode(reason balancepb.DebitDeclineReason) cardprocessingpb.DeclineCode {
+	if code, ok := debitDeclineCodes[reason]; ok {
+		return code
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_SYSTEM_ERROR
+}
+
+// decline returns the reply to a declined authorization. If the account is known a
+// "card:auth_declined" event is published too, so the account holder can be told why; the
+// decline stands even if publishing fails.
+func (s *server) decline(ctx context.Context, req *cardprocessingpb.CardAuthRequest, accountID, transactionID string, code cardprocessingpb.DeclineCode, reason string) *cardprocessingpb.CardAuthReply {
+	if accountID != "" {
+		event := &eventspb.CardAuthDeclined{
+			CardId:        req.GetCardId(),
+			AccountId:     accountID,
+			Amount:        req.GetAmount(),
+			Currency:      req.GetCurrency(),
+			MerchantName:  req.GetMerchantName(),
+			DeclineCode:   code.String(),
+			DeclineReason: reason,
+			TransactionId: transactionID,
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		}
+		if _, err := events.Publish(ctx, s.redisClient, events.StreamCardAuthDeclined, event); err != nil {
+			log.Printf("failed to publish card:auth_declined event for card %s: %v", req.GetCardId(), err)
+		}
+	}
+	return &cardprocessingpb.CardAuthReply{Approved: false, DeclineReason: reason, DeclineCode: code}
+}
@@ -2,30 +2,81 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"net"
+	"os"
 	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
 	balancepb "github.com/sambacha/monzo/v2/balance"
 	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing"
 	cardspb "github.com/sambacha/monzo/v2/cards"
 	transactionspb "github.com/sambacha/monzo/v2/transactions"
 )
 
+// maxAttempts bounds how often a downstream call that failed with a transient error is tried
+const maxAttempts = 3
+
+// retryBackoff is the delay before the first retry, growing linearly with each attempt
+var retryBackoff = 50 * time.Millisecond
+
 type server struct {
 	cardprocessingpb.UnimplementedCardProcessingServer
 	cardsClient        cardspb.CardsClient
+	accountsClient     accountspb.AccountsClient
 	balanceClient      balancepb.BalanceClient
 	transactionsClient transactionspb.TransactionsClient
+	redisClient        *redis.Client
+	sagas              sagaStore
+	spend              spendStore
+	risk               *riskEngine
+	newSagaID          func() string
+	now                func() time.Time
 }
 
 func main() {
+	// Database connection setup (placeholder)
+	db, err := sql.Open("postgres", "user=user dbname=card_processing sslmode=disable")
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Auto-migrate schema (for development/testing)
+	// In production, use proper schema migration tools
+	schemaSQL, err := os.ReadFile("card-processing/schema.sql")
+	if err != nil {
+		log.Fatalf("failed to read schema file: %v", err)
+	}
+	if _, err := db.Exec(string(schemaSQL)); err != nil {
+		log.Fatalf("failed to execute schema: %v", err)
+	}
+	log.Println("Database schema applied successfully")
+
+	riskConfig, err := loadRiskConfig("card-processing/risk_rules.json")
+	if err != nil {
+		log.Fatalf("failed to load risk rules: %v", err)
+	}
+
+	// Redis client for publishing decline events
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+		DB:   0,
+	})
+	defer rdb.Close()
+
 	// Set up gRPC client for Cards service
 	cardsConn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -34,6 +85,14 @@ func main() {
 	defer cardsConn.Close()
 	cardsClient := cardspb.NewCardsClient(cardsConn)
 
+	// Set up gRPC client for Accounts service
+	accountsConn, err := grpc.Dial("localhost:50061", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to Accounts service: %v", err)
+	}
+	defer accountsConn.Close()
+	accountsClient := accountspb.NewAccountsClient(accountsConn)
+
 	// Set up gRPC client for Balance service
 	balanceConn, err := grpc.Dial("localhost:50053", grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -52,10 +111,22 @@ func main() {
 
 	s := &server{
 		cardsClient:        cardsClient,
+		accountsClient:     accountsClient,
 		balanceClient:      balanceClient,
 		transactionsClient: transactionsClient,
+		redisClient:        rdb,
+		sagas:              &pgSagaStore{db: db},
+		spend:              &pgSpendStore{db: db},
+		risk:               newRiskEngine(riskConfig, &pgRiskStore{db: db}, func() string { return uuid.New().String() }),
+		newSagaID:          func() string { return uuid.New().String() },
+		now:                time.Now,
 	}
 
+	// Resume or roll back authorizations left half-finished by a previous run
+	recoveryCtx, stopRecovery := context.WithCancel(context.Background())
+	defer stopRecovery()
+	go s.runSagaRecovery(recoveryCtx, time.Minute)
+
 	// Set up gRPC server
 	grpcServer := grpc.NewServer()
 	cardprocessingpb.RegisterCardProcessingServer(grpcServer, s)
@@ -74,73 +145,286 @@ func main() {
 // Implement gRPC methods here
 
 func (s *server) AuthorizeCardTransaction(ctx context.Context, req *cardprocessingpb.CardAuthRequest) (*cardprocessingpb.CardAuthReply, error) {
-	log.Printf("Received AuthorizeCardTransaction request: %+v", req)
+	log.Printf("Received AuthorizeCardTransaction request: %+v", withoutPin(req))
+
+	// A saga still running after this, and the compensation that may follow, could be taken for
+	// abandoned and recovered, see sagaRecoveryAge
+	ctx, cancel := context.WithTimeout(ctx, authorizationTimeout)
+	defer cancel()
 
 	// 1. Check card status via Cards service
-	getCardReq := &cardspb.GetCardRequest{CardId: req.GetCardId()}
-	card, err := s.cardsClient.GetCard(ctx, getCardReq)
+	card, err := s.lookupCard(ctx, req.GetCardId(), req.GetPanToken())
 	if err != nil {
 		// Handle errors from Cards service
 		st, ok := status.FromError(err)
 		if ok && st.Code() == codes.NotFound {
 			log.Printf("card not found: %s", req.GetCardId())
-			return &cardprocessingpb.CardAuthReply{Approved: false, DeclineReason: "card not found"}, nil
+			return s.decline(ctx, req, "", "", cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_NOT_FOUND, "card not found"), nil
 		}
 		log.Printf("failed to get card %s: %v", req.GetCardId(), err)
 		return nil, status.Errorf(codes.Internal, "failed to authorize transaction")
 	}
+	if req.GetCardId() == "" {
+		// The card came from its token; the rest of the authorization refers to it by its ID
+		req = proto.Clone(req).(*cardprocessingpb.CardAuthRequest)
+		req.CardId = card.GetCardId()
+	}
+
+	// Find the account the card pays from; empty if it has none
+	accountID, err := s.resolveAccount(ctx, card, req.GetCardId())
+	if err != nil {
+		log.Printf("failed to resolve account of card %s: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize transaction")
+	}
 
 	// Check card status (e.g., FROZEN, CLOSED)
 	if card.GetStatus() != "ACTIVE" {
 		log.Printf("card %s is not active (status: %s)", req.GetCardId(), card.GetStatus())
-		return &cardprocessingpb.CardAuthReply{Approved: false, DeclineReason: fmt.Sprintf("card is %s", strings.ToLower(card.GetStatus()))}, nil
+		return s.decline(ctx, req, accountID, "", cardStatusDeclineCode(card.GetStatus()), fmt.Sprintf("card is %s", strings.ToLower(card.GetStatus()))), nil
+	}
+	if accountID == "" {
+		log.Printf("card %s has no open account", req.GetCardId())
+		return s.decline(ctx, req, "", "", cardprocessingpb.DeclineCode_DECLINE_CODE_ACCOUNT_UNAVAILABLE, "no open account for card"), nil
 	}
 
-	// Assuming user_id from card is the account_id for balance/transactions
-	accountID := card.GetUserId()
+	// Apply the cardholder's controls on how and where the card can be used
+	controls, err := s.cardsClient.GetCardControls(ctx, &cardspb.GetCardRequest{CardId: req.GetCardId()})
+	if err != nil {
+		log.Printf("failed to get controls of card %s: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize transaction")
+	}
+	if code, reason := checkControls(controls, req); code != cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED {
+		log.Printf("card %s controls decline transaction: %s", req.GetCardId(), reason)
+		return s.decline(ctx, req, accountID, "", code, reason), nil
+	}
 
-	// 2. Authorize debit via Balance service
-	authorizeDebitReq := &balancepb.AuthorizeDebitRequest{
-		AccountId: accountID,
-		Amount:    req.GetAmount(),
+	// Check the PIN if one was entered at a terminal or ATM
+	code, reason, err := s.checkPin(ctx, req)
+	if err != nil {
+		log.Printf("%v", err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize transaction")
 	}
-	debitResult, err := s.balanceClient.AuthorizeDebit(ctx, authorizeDebitReq)
+	if code != cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED {
+		log.Printf("card %s PIN declines transaction: %s", req.GetCardId(), reason)
+		return s.decline(ctx, req, accountID, "", code, reason), nil
+	}
+
+	// Apply the rules a virtual card was created with
+	if code, reason := checkVirtualCard(card, req, s.now().UTC()); code != cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED {
+		log.Printf("card %s virtual card rules decline transaction: %s", req.GetCardId(), reason)
+		return s.decline(ctx, req, accountID, "", code, reason), nil
+	}
+
+	// Score the authorization for fraud before any money is held
+	assessment, err := s.risk.assess(ctx, riskInput{
+		cardID:          req.GetCardId(),
+		accountID:       accountID,
+		amount:          req.GetAmount(),
+		currency:        req.GetCurrency(),
+		merchantID:      req.GetMerchantId(),
+		merchantName:    req.GetMerchantName(),
+		merchantCountry: req.GetMerchantCountry(),
+	})
 	if err != nil {
-		log.Printf("failed to authorize debit for account %s: %v", accountID, err)
+		log.Printf("failed to assess risk for card %s: %v", req.GetCardId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to authorize transaction")
+	}
+	if assessment.decision != riskApprove {
+		log.Printf("risk decision %s for card %s: score %d, rules %v", assessment.decision, req.GetCardId(), assessment.score, assessment.triggeredRules)
+		reason := "suspected fraud"
+		if assessment.decision == riskStepUp {
+			reason = "authentication required"
+		}
+		return s.decline(ctx, req, accountID, "", riskDeclineCode(assessment.decision), reason), nil
+	}
+
+	// Persist the saga before touching Balance or Transactions, so a crash at any later step can be
+	// recovered. The saga ID doubles as the idempotency key for every downstream call.
+	saga := &authSaga{
+		id:           s.newSagaID(),
+		cardID:       req.GetCardId(),
+		accountID:    accountID,
+		amount:       req.GetAmount(),
+		currency:     req.GetCurrency(),
+		merchantID:   req.GetMerchantId(),
+		merchantName: req.GetMerchantName(),
+		step:         stepStarted,
+		status:       sagaInProgress,
+	}
+	if err := s.sagas.create(ctx, saga); err != nil {
+		log.Printf("failed to create saga for card %s: %v", req.GetCardId(), err)
 		return nil, status.Errorf(codes.Internal, "failed to authorize transaction")
 	}
 
+	// 2. Record a PENDING transaction via Transactions service
+	txn, err := s.recordPending(ctx, saga)
+	if err != nil {
+		log.Printf("failed to record transaction for account %s: %v", accountID, err)
+		return nil, s.rollBack(ctx, saga)
+	}
+	saga.transactionID = txn.GetId()
+	if err := s.advance(ctx, saga, stepTransactionRecorded, sagaInProgress); err != nil {
+		log.Printf("%v", err)
+		return nil, s.rollBack(ctx, saga)
+	}
+
+	// 3. Authorize debit via Balance service
+	debitResult, err := s.authorizeDebit(ctx, saga)
+	if err != nil {
+		log.Printf("failed to authorize debit for account %s: %v", accountID, err)
+		return nil, s.rollBack(ctx, saga)
+	}
+	saga.holdID = debitResult.GetHoldId()
+	saga.declineReason = debitResult.GetErrorMessage()
+	if debitResult.GetFxRate() != "" {
+		saga.billingCurrency = debitResult.GetCurrency()
+		saga.billingAmount = debitResult.GetConvertedAmount()
+		saga.fxRate = debitResult.GetFxRate()
+		saga.fxFee = debitResult.GetFxFee()
+	}
+
 	if !debitResult.GetSuccess() {
 		log.Printf("debit not authorized for account %s: %s", accountID, debitResult.GetErrorMessage())
-		return &cardprocessingpb.CardAuthReply{Approved: false, DeclineReason: debitResult.GetErrorMessage()}, nil
+		saga.step = stepDebitAuthorized
+		// The decline stands even if marking the transaction DECLINED fails; recovery will finish it
+		if err := s.abandon(ctx, saga); err != nil {
+			log.Printf("failed to compensate declined saga %s: %v", saga.id, err)
+		}
+		return s.decline(ctx, req, accountID, saga.transactionID, debitDeclineCode(debitResult.GetDeclineReason()), debitResult.GetErrorMessage()), nil
+	}
+	if err := s.advance(ctx, saga, stepDebitAuthorized, sagaInProgress); err != nil {
+		log.Printf("%v", err)
+		return nil, s.rollBack(ctx, saga)
 	}
 
-	// 3. Record transaction via Transactions service
-	recordTxnReq := &transactionspb.TransactionInput{
-		AccountId:   accountID,
-		CardId:      req.GetCardId(),
-		Amount:      req.GetAmount(),
-		Currency:    req.GetCurrency(),
-		MerchantId:  req.GetMerchantId(),
-		MerchantRaw: req.GetMerchantName(), // Use raw name from auth request
-		Status:      "AUTHORIZED",          // Initial status
+	// Spending limits are in the account's currency, so they're checked once the debit has been
+	// converted. The payment counts towards them from now on, until compensation or a reversal.
+	limitReason, err := s.reserveSpend(ctx, controls, card, saga.transactionID, saga.cardID, saga.heldAmount())
+	if err != nil {
+		log.Printf("failed to check spending limits of card %s: %v", req.GetCardId(), err)
+		return nil, s.rollBack(ctx, saga)
+	}
+	if limitReason != "" {
+		log.Printf("card %s limits decline transaction: %s", req.GetCardId(), limitReason)
+		saga.declineReason = limitReason
+		if err := s.abandon(ctx, saga); err != nil {
+			log.Printf("failed to compensate declined saga %s: %v", saga.id, err)
+		}
+		return s.decline(ctx, req, accountID, saga.transactionID, cardprocessingpb.DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED, limitReason), nil
 	}
-	_, err = s.transactionsClient.RecordTransaction(ctx, recordTxnReq)
+	if err := s.advance(ctx, saga, stepSpendReserved, sagaInProgress); err != nil {
+		log.Printf("%v", err)
+		return nil, s.rollBack(ctx, saga)
+	}
+
+	// A merchant-locked card belongs to the first merchant a payment is approved for, and a single-use
+	// card to the first payment, so they are only claimed once nothing else can decline the payment
+	code, reason, err = s.claimVirtualCard(ctx, card, req, saga.transactionID)
 	if err != nil {
-		log.Printf("failed to record transaction for account %s: %v", accountID, err)
-		// IMPORTANT: If recording transaction fails AFTER debiting balance, we have an inconsistency.
-		// In a real system, this would require a compensating transaction (credit back the debit)
-		// or a saga pattern. For this spec, we'll log the error and return internal error,
-		// assuming monitoring will detect the balance/transaction mismatch.
-		// A more robust approach would be to record the transaction first (with pending status),
-		// then debit, then update transaction status to completed. Or use a transactional outbox.
-		// Sticking to the spec's implied flow for now.
-		return nil, status.Errorf(codes.Internal, "failed to record transaction after debit")
+		log.Printf("%v", err)
+		return nil, s.rollBack(ctx, saga)
+	}
+	if code != cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED {
+		log.Printf("card %s virtual card rules decline transaction: %s", req.GetCardId(), reason)
+		saga.declineReason = reason
+		if err := s.abandon(ctx, saga); err != nil {
+			log.Printf("failed to compensate declined saga %s: %v", saga.id, err)
+		}
+		return s.decline(ctx, req, accountID, saga.transactionID, code, reason), nil
+	}
+	if err := s.advance(ctx, saga, stepCardClaimed, sagaInProgress); err != nil {
+		log.Printf("%v", err)
+		return nil, s.rollBack(ctx, saga)
+	}
+
+	// 4. Confirm the transaction against the hold
+	if err := s.confirm(ctx, saga); err != nil {
+		log.Printf("failed to confirm saga %s: %v", saga.id, err)
+		return nil, s.rollBack(ctx, saga)
 	}
 
-	log.Printf("Transaction authorized and recorded for account %s, card %s, amount %d %s",
-		accountID, req.GetCardId(), req.GetAmount(), req.GetCurrency())
+	log.Printf("Transaction %s authorized for account %s, card %s, amount %d %s",
+		saga.transactionID, accountID, req.GetCardId(), req.GetAmount(), req.GetCurrency())
 
-	// If all steps succeed, return approved
-	return &cardprocessingpb.CardAuthReply{Approved: true}, nil
+	// Remember merchants taking recurring payments, so they carry over to the card's replacement.
+	// The payment stands even if this fails.
+	if req.GetRecurring() && req.GetMerchantId() != "" {
+		_, err := s.cardsClient.AddRecurringMerchant(ctx, &cardspb.RecurringMerchant{
+			CardId:       req.GetCardId(),
+			MerchantId:   req.GetMerchantId(),
+			MerchantName: req.GetMerchantName(),
+		})
+		if err != nil {
+			log.Printf("failed to add recurring merchant %s to card %s: %v", req.GetMerchantId(), req.GetCardId(), err)
+		}
+	}
+
+	// If all steps succeed, return approved with the code the network quotes back at settlement
+	return &cardprocessingpb.CardAuthReply{Approved: true, AuthCode: authCode(saga.id)}, nil
+}
+
+// lookupCard gets the card a request is for, by its ID or, as card networks identify it, by the
+// vault token of its number
+func (s *server) lookupCard(ctx context.Context, cardID, panToken string) (*cardspb.Card, error) {
+	if cardID == "" && panToken != "" {
+		return s.cardsClient.GetCardByPanToken(ctx, &cardspb.GetCardByPanTokenRequest{PanToken: panToken})
+	}
+	return s.cardsClient.GetCard(ctx, &cardspb.GetCardRequest{CardId: cardID})
+}
+
+// resolveAccount returns the ID of the account a card's payments are taken from, as Accounts
+// resolves it, or "" if the card has no open account
+func (s *server) resolveAccount(ctx context.Context, card *cardspb.Card, cardID string) (string, error) {
+	account, err := s.accountsClient.ResolveAccountForCard(ctx, &accountspb.ResolveAccountForCardRequest{
+		CardId:         cardID,
+		UserId:         card.GetUserId(),
+		ReplacesCardId: card.GetReplacesCardId(),
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return account.GetAccountId(), nil
+	case codes.NotFound, codes.FailedPrecondition:
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+// rollBack compensates a failed saga and returns the error to report to the caller.
+// If compensation fails too, the saga is left for recovery to roll back later.
+func (s *server) rollBack(ctx context.Context, saga *authSaga) error {
+	if err := s.abandon(ctx, saga); err != nil {
+		log.Printf("failed to compensate saga %s, leaving it for recovery: %v", saga.id, err)
+	}
+	return status.Errorf(codes.Internal, "failed to authorize transaction")
+}
+
+// withRetry calls fn until it succeeds, fails with a non-transient error or runs out of attempts.
+// fn must be safe to repeat, e.g. by sending the same idempotency key on every attempt.
+func withRetry(ctx context.Context, name string, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = fn()
+		if err == nil || !retryable(err) || attempt == maxAttempts {
+			return err
+		}
+		log.Printf("%s attempt %d failed, retrying: %v", name, attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+	}
+	return err
+}
+
+// retryable reports whether a downstream error is transient
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
 }
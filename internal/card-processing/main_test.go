@@ -3,14 +3,21 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
 	balancepb "github.com/sambacha/monzo/v2/balance/balance"
 	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing/card_processing"
 	cardspb "github.com/sambacha/monzo/v2/cards/cards"
@@ -38,6 +45,142 @@ func (m *mockCardsClient) UpdateCardStatus(ctx context.Context, in *cardspb.Upda
 	return args.Get(0).(*cardspb.Card), args.Error(1)
 }
 
+func (m *mockCardsClient) GetCardHistory(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.CardHistory, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardHistory), args.Error(1)
+}
+
+func (m *mockCardsClient) ListVirtualCards(ctx context.Context, in *cardspb.ListVirtualCardsRequest, opts ...grpc.CallOption) (*cardspb.VirtualCards, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.VirtualCards), args.Error(1)
+}
+
+func (m *mockCardsClient) LockCardToMerchant(ctx context.Context, in *cardspb.LockCardToMerchantRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) UseSingleUseCard(ctx context.Context, in *cardspb.UseSingleUseCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) SetCardNickname(ctx context.Context, in *cardspb.SetCardNicknameRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) SetDefaultCard(ctx context.Context, in *cardspb.SetDefaultCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) GetCardControls(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.CardControls, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardControls), args.Error(1)
+}
+
+func (m *mockCardsClient) UpdateCardControls(ctx context.Context, in *cardspb.CardControls, opts ...grpc.CallOption) (*cardspb.CardControls, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardControls), args.Error(1)
+}
+
+func (m *mockCardsClient) ReissueCard(ctx context.Context, in *cardspb.ReissueCardRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) AddRecurringMerchant(ctx context.Context, in *cardspb.RecurringMerchant, opts ...grpc.CallOption) (*cardspb.RecurringMerchant, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RecurringMerchant), args.Error(1)
+}
+
+func (m *mockCardsClient) ListRecurringMerchants(ctx context.Context, in *cardspb.GetCardRequest, opts ...grpc.CallOption) (*cardspb.RecurringMerchants, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RecurringMerchants), args.Error(1)
+}
+
+func (m *mockCardsClient) GetCardByPanToken(ctx context.Context, in *cardspb.GetCardByPanTokenRequest, opts ...grpc.CallOption) (*cardspb.Card, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.Card), args.Error(1)
+}
+
+func (m *mockCardsClient) ListCardsByUser(ctx context.Context, in *cardspb.ListCardsByUserRequest, opts ...grpc.CallOption) (*cardspb.CardList, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.CardList), args.Error(1)
+}
+
+func (m *mockCardsClient) SetPin(ctx context.Context, in *cardspb.SetPinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
+func (m *mockCardsClient) ChangePin(ctx context.Context, in *cardspb.ChangePinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
+func (m *mockCardsClient) RevealPin(ctx context.Context, in *cardspb.RevealPinRequest, opts ...grpc.CallOption) (*cardspb.RevealPinResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.RevealPinResponse), args.Error(1)
+}
+
+func (m *mockCardsClient) VerifyPin(ctx context.Context, in *cardspb.VerifyPinRequest, opts ...grpc.CallOption) (*cardspb.PinStatus, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cardspb.PinStatus), args.Error(1)
+}
+
 // Mock BalanceClient
 type mockBalanceClient struct{ mock.Mock }
 
@@ -46,6 +189,14 @@ func (m *mockBalanceClient) GetBalance(ctx context.Context, in *balancepb.Accoun
 	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
 }
 
+func (m *mockBalanceClient) OpenAccount(ctx context.Context, in *balancepb.OpenAccountRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
 func (m *mockBalanceClient) AuthorizeDebit(ctx context.Context, in *balancepb.AuthorizeDebitRequest, opts ...grpc.CallOption) (*balancepb.DebitResult, error) {
 	args := m.Called(ctx, in)
 	if args.Get(0) == nil {
@@ -59,6 +210,70 @@ func (m *mockBalanceClient) CreditAccount(ctx context.Context, in *balancepb.Cre
 	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
 }
 
+func (m *mockBalanceClient) ListLedgerEntries(ctx context.Context, in *balancepb.ListLedgerEntriesRequest, opts ...grpc.CallOption) (*balancepb.LedgerEntries, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.LedgerEntries), args.Error(1)
+}
+
+func (m *mockBalanceClient) CaptureHold(ctx context.Context, in *balancepb.CaptureHoldRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) ReleaseHold(ctx context.Context, in *balancepb.HoldID, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) ReduceHold(ctx context.Context, in *balancepb.ReduceHoldRequest, opts ...grpc.CallOption) (*balancepb.ReduceHoldResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.ReduceHoldResult), args.Error(1)
+}
+
+func (m *mockBalanceClient) ExpireHolds(ctx context.Context, in *balancepb.ExpireHoldsRequest, opts ...grpc.CallOption) (*balancepb.ExpireHoldsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.ExpireHoldsResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) SetOverdraftLimit(ctx context.Context, in *balancepb.SetOverdraftLimitRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) AccrueOverdraftCharges(ctx context.Context, in *balancepb.AccrueOverdraftChargesRequest, opts ...grpc.CallOption) (*balancepb.AccrueOverdraftChargesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.AccrueOverdraftChargesResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) Transfer(ctx context.Context, in *balancepb.TransferRequest, opts ...grpc.CallOption) (*balancepb.TransferResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.TransferResult), args.Error(1)
+}
+
 // Mock TransactionsClient (copied from previous tests)
 type mockTransactionsClient struct{ mock.Mock }
 
@@ -91,20 +306,197 @@ func (m *mockTransactionsClient) UpdateTransaction(ctx context.Context, in *tran
 	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
 }
 
+func (m *mockTransactionsClient) GetTransactionByAuthCode(ctx context.Context, in *transactionspb.AuthCodeQuery, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
+// userAccountsClient resolves every card to an account whose ID is its holder's user ID, so tests
+// not about resolving accounts don't need to set it up
+type userAccountsClient struct{ accountspb.AccountsClient }
+
+func (userAccountsClient) ResolveAccountForCard(ctx context.Context, in *accountspb.ResolveAccountForCardRequest, opts ...grpc.CallOption) (*accountspb.CardAccount, error) {
+	return &accountspb.CardAccount{CardId: in.GetCardId(), AccountId: in.GetUserId()}, nil
+}
+
+// Mock AccountsClient. Only ResolveAccountForCard is mocked; the embedded interface is nil, so
+// calling any other RPC panics.
+type mockAccountsClient struct {
+	mock.Mock
+	accountspb.AccountsClient
+}
+
+func (m *mockAccountsClient) ResolveAccountForCard(ctx context.Context, in *accountspb.ResolveAccountForCardRequest, opts ...grpc.CallOption) (*accountspb.CardAccount, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*accountspb.CardAccount), args.Error(1)
+}
+
+// memorySagaStore keeps sagas in memory for tests
+type memorySagaStore struct {
+	sagas map[string]authSaga
+}
+
+func newMemorySagaStore() *memorySagaStore {
+	return &memorySagaStore{sagas: map[string]authSaga{}}
+}
+
+func (m *memorySagaStore) create(ctx context.Context, saga *authSaga) error {
+	m.sagas[saga.id] = *saga
+	return nil
+}
+
+func (m *memorySagaStore) update(ctx context.Context, saga *authSaga) error {
+	m.sagas[saga.id] = *saga
+	return nil
+}
+
+func (m *memorySagaStore) claimRecoverable(ctx context.Context, idleFor time.Duration, limit int) ([]*authSaga, error) {
+	var sagas []*authSaga
+	for _, saga := range m.sagas {
+		if saga.status == sagaInProgress || saga.status == sagaCompensating {
+			saga := saga
+			sagas = append(sagas, &saga)
+		}
+	}
+	return sagas, nil
+}
+
+// memorySpendStore keeps spending totals in memory for tests
+type memorySpendStore struct {
+	totals   map[string]int64       // by card ID and period, see spent
+	payments map[string]cardPayment // by transaction ID
+}
+
+func newMemorySpendStore() *memorySpendStore {
+	return &memorySpendStore{totals: map[string]int64{}, payments: map[string]cardPayment{}}
+}
+
+func (m *memorySpendStore) reserve(ctx context.Context, payment cardPayment, limits []spendLimit) (string, error) {
+	if _, ok := m.payments[payment.transactionID]; ok {
+		return "", nil
+	}
+	for _, period := range spendPeriods(payment.at) {
+		for _, limit := range limits {
+			if limit.period == period && m.spent(payment.cardID, period)+payment.amount > limit.limit {
+				return limit.reason, nil
+			}
+		}
+	}
+	m.payments[payment.transactionID] = payment
+	for _, period := range spendPeriods(payment.at) {
+		m.totals[payment.cardID+"/"+period] += payment.amount
+	}
+	return "", nil
+}
+
+func (m *memorySpendStore) release(ctx context.Context, transactionID string, remaining int64) error {
+	payment, ok := m.payments[transactionID]
+	if !ok || payment.amount <= remaining {
+		return nil
+	}
+	for _, period := range spendPeriods(payment.at) {
+		m.totals[payment.cardID+"/"+period] -= payment.amount - remaining
+	}
+	payment.amount = remaining
+	m.payments[transactionID] = payment
+	return nil
+}
+
+// spent returns the card's total spend in period
+func (m *memorySpendStore) spent(cardID, period string) int64 {
+	return m.totals[cardID+"/"+period]
+}
+
+// memoryRiskStore keeps risk decisions in memory for tests
+type memoryRiskStore struct {
+	records []riskRecord // oldest first
+}
+
+func (m *memoryRiskStore) save(ctx context.Context, record *riskRecord) error {
+	m.records = append(m.records, *record)
+	return nil
+}
+
+func (m *memoryRiskStore) recent(ctx context.Context, accountID string, limit int) ([]riskRecord, error) {
+	var records []riskRecord
+	for i := len(m.records) - 1; i >= 0 && len(records) < limit; i-- {
+		if m.records[i].accountID == accountID {
+			records = append(records, m.records[i])
+		}
+	}
+	return records, nil
+}
+
+// testRiskConfig runs every rule, with the thresholds of the shipped configuration
+var testRiskConfig = &riskConfig{
+	StepUpScore:     50,
+	DeclineScore:    80,
+	Velocity:        &velocityRule{Score: 40, MaxAuths: 5, WindowSeconds: 60},
+	AmountAnomaly:   &amountAnomalyRule{Score: 30, Multiplier: 5, MinHistory: 5},
+	NewMerchant:     &newMerchantRule{Score: 20, Threshold: 50000},
+	CountryMismatch: &countryMismatchRule{Score: 50, WindowSeconds: 7200},
+	CardTesting:     &cardTestingRule{Score: 80, SmallAmount: 200, MaxSmallAuths: 3, WindowSeconds: 600},
+}
+
+// testNow is the time the test server runs at
+var testNow = time.Date(2025, time.March, 14, 15, 30, 0, 0, time.UTC)
+
 // Helper function to create a server instance with mocks
 func newTestServer(t *testing.T) (*server, *mockCardsClient, *mockBalanceClient, *mockTransactionsClient) {
 	mockCards := new(mockCardsClient)
 	mockBalance := new(mockBalanceClient)
 	mockTxn := new(mockTransactionsClient)
+	// Tests that check published events replace this client with their own mock
+	redisClient, _ := redismock.NewClientMock()
 
 	s := &server{
 		cardsClient:        mockCards,
+		accountsClient:     userAccountsClient{}, // Tests of resolving accounts replace this with their own mock
 		balanceClient:      mockBalance,
 		transactionsClient: mockTxn,
+		redisClient:        redisClient,
+		sagas:              newMemorySagaStore(),
+		spend:              newMemorySpendStore(),
+		risk:               newRiskEngine(testRiskConfig, &memoryRiskStore{}, func() string { return "decision-1" }),
+		newSagaID:          func() string { return "saga-1" },
+		now:                func() time.Time { return testNow },
 	}
 	return s, mockCards, mockBalance, mockTxn
 }
 
+// expectDeclined expects a card:auth_declined event to be published for accountID with code
+func expectDeclined(mockRedis redismock.ClientMock, accountID string, code cardprocessingpb.DeclineCode) {
+	mockRedis.CustomMatch(func(expected, actual []interface{}) error {
+		values := map[string]interface{}{}
+		for i := 3; i+1 < len(actual); i += 2 {
+			values[fmt.Sprint(actual[i])] = actual[i+1]
+		}
+		var event eventspb.CardAuthDeclined
+		if actual[1] != events.StreamCardAuthDeclined || events.Decode(values, &event) != nil {
+			return fmt.Errorf("expected a %s event, got %v", events.StreamCardAuthDeclined, actual)
+		}
+		if event.AccountId != accountID || event.DeclineCode != code.String() {
+			return fmt.Errorf("expected %s declined for %s, got %s declined for %s", accountID, code, event.AccountId, event.DeclineCode)
+		}
+		return nil
+	}).ExpectXAdd(&redis.XAddArgs{
+		Stream: events.StreamCardAuthDeclined,
+		// Only the number of fields is compared with the call; their values are checked above
+		Values: map[string]interface{}{"payload": "", "type": "", "schema_version": ""},
+	}).SetVal("1-0")
+}
+
+// sagaState returns the persisted state of a saga in the test server's store
+func sagaState(s *server, id string) authSaga {
+	return s.sagas.(*memorySagaStore).sagas[id]
+}
+
 func TestAuthorizeCardTransaction_Success(t *testing.T) {
 	s, mockCards, mockBalance, mockTxn := newTestServer(t)
 
@@ -118,25 +510,32 @@ func TestAuthorizeCardTransaction_Success(t *testing.T) {
 
 	// Mock GetCard call
 	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
-		Return(&cardspb.Card{Id: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
 
-	// Mock AuthorizeDebit call
-	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount}).
-		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000}, nil).Once()
-
-	// Mock RecordTransaction call
+	// Mock RecordTransaction call, recording the transaction as PENDING first
 	expectedTxnInput := &transactionspb.TransactionInput{
-		AccountId:   userID,
-		CardId:      req.CardId,
-		Amount:      req.Amount,
-		Currency:    req.Currency,
-		MerchantId:  req.MerchantId,
-		MerchantRaw: req.MerchantName,
-		Status:      "AUTHORIZED",
+		AccountId:      userID,
+		CardId:         req.CardId,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		MerchantId:     req.MerchantId,
+		MerchantRaw:    req.MerchantName,
+		Status:         "PENDING",
+		IdempotencyKey: "saga-1",
 	}
 	mockTxn.On("RecordTransaction", mock.Anything, expectedTxnInput).
 		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
 
+	// Mock AuthorizeDebit call
+	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount, Currency: req.Currency, IdempotencyKey: "saga-1"}).
+		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000, HoldId: "hold-1"}, nil).Once()
+
+	// Mock UpdateTransaction call confirming the transaction against the hold
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "AUTHORIZED", HoldId: "hold-1", AuthCode: authCode("saga-1")}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "AUTHORIZED"}, nil).Once()
+
 	ctx := context.Background()
 	resp, err := s.AuthorizeCardTransaction(ctx, req)
 
@@ -144,51 +543,217 @@ func TestAuthorizeCardTransaction_Success(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.True(t, resp.Approved)
 	assert.Empty(t, resp.DeclineReason)
+	assert.Len(t, resp.AuthCode, 6)
+
+	saga := sagaState(s, "saga-1")
+	assert.Equal(t, sagaCompleted, saga.status)
+	assert.Equal(t, stepConfirmed, saga.step)
+	assert.Equal(t, req.Amount, s.spend.(*memorySpendStore).spent(req.CardId, dayPeriod(testNow)))
 
 	mockCards.AssertExpectations(t)
 	mockBalance.AssertExpectations(t)
 	mockTxn.AssertExpectations(t)
 }
 
-func TestAuthorizeCardTransaction_CardNotFound(t *testing.T) {
+func TestAuthorizeCardTransaction_RetriesWithSameKey(t *testing.T) {
 	s, mockCards, mockBalance, mockTxn := newTestServer(t)
 
-	req := &cardprocessingpb.CardAuthRequest{CardId: "card-unknown"}
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1000, Currency: "GBP"}
+	userID := "user-abc"
+	debitReq := &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount, Currency: req.Currency, IdempotencyKey: "saga-1"}
 
-	// Mock GetCard call to return NotFound
 	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
-		Return(nil, status.Error(codes.NotFound, "card not found")).Once()
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+
+	// The first attempt times out; the retry carries the same key, so Balance replays rather than debiting twice
+	mockBalance.On("AuthorizeDebit", mock.Anything, debitReq).
+		Return(nil, status.Error(codes.DeadlineExceeded, "timeout")).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, debitReq).
+		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000, HoldId: "hold-1"}, nil).Once()
+
+	mockTxn.On("UpdateTransaction", mock.Anything, mock.AnythingOfType("*transactions.UpdateTransactionRequest")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
 
 	ctx := context.Background()
 	resp, err := s.AuthorizeCardTransaction(ctx, req)
 
-	assert.NoError(t, err) // Business decline, not gRPC error
-	assert.NotNil(t, resp)
-	assert.False(t, resp.Approved)
-	assert.Equal(t, "card not found", resp.DeclineReason)
+	assert.NoError(t, err)
+	assert.True(t, resp.Approved)
 
 	mockCards.AssertExpectations(t)
-	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
-	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+	mockBalance.AssertExpectations(t)
+	mockBalance.AssertNumberOfCalls(t, "AuthorizeDebit", 2)
+	mockTxn.AssertExpectations(t)
 }
 
-func TestAuthorizeCardTransaction_CardInactive(t *testing.T) {
+func TestAuthorizeCardTransaction_ForeignCurrency(t *testing.T) {
 	s, mockCards, mockBalance, mockTxn := newTestServer(t)
 
-	req := &cardprocessingpb.CardAuthRequest{CardId: "card-frozen"}
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1234, Currency: "EUR"}
 	userID := "user-abc"
 
-	// Mock GetCard call to return FROZEN card
 	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
-		Return(&cardspb.Card{Id: req.CardId, UserId: userID, Status: "FROZEN"}, nil).Once()
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
 
-	ctx := context.Background()
+	// Balance converts the EUR amount into the account's GBP
+	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount, Currency: "EUR", IdempotencyKey: "saga-1"}).
+		Return(&balancepb.DebitResult{Success: true, NewBalance: 8895, HoldId: "hold-1", Currency: "GBP", FxRate: "0.8712", ConvertedAmount: 1075, FxFee: 30}, nil).Once()
+
+	// The conversion is recorded on the transaction
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{
+		Id: "txn-xyz", Status: "AUTHORIZED", HoldId: "hold-1", AuthCode: authCode("saga-1"),
+		BillingCurrency: "GBP", BillingAmount: 1075, FxRate: "0.8712", FxFee: 30,
+	}).Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "AUTHORIZED"}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeCardTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Approved)
+
+	saga := sagaState(s, "saga-1")
+	assert.Equal(t, "GBP", saga.billingCurrency)
+	assert.Equal(t, "0.8712", saga.fxRate)
+
+	mockCards.AssertExpectations(t)
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_ResolvesAccount(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	mockAccounts := new(mockAccountsClient)
+	s.accountsClient = mockAccounts
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-456", Amount: 1000, Currency: "GBP"}
+	accountID := "acc-joint"
+
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: "user-abc", Status: "ACTIVE", ReplacesCardId: "card-123"}, nil).Once()
+	// The replacement card pays from the account of the card it replaces
+	mockAccounts.On("ResolveAccountForCard", mock.Anything, &accountspb.ResolveAccountForCardRequest{CardId: req.CardId, UserId: "user-abc", ReplacesCardId: "card-123"}).
+		Return(&accountspb.CardAccount{CardId: req.CardId, AccountId: accountID, AccountType: "joint", Currency: "GBP"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.MatchedBy(func(in *transactionspb.TransactionInput) bool {
+		return in.AccountId == accountID
+	})).Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: accountID, Amount: req.Amount, Currency: req.Currency, IdempotencyKey: "saga-1"}).
+		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000, HoldId: "hold-1"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, mock.Anything).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "AUTHORIZED"}, nil).Once()
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Approved)
+
+	mockAccounts.AssertExpectations(t)
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_AccountUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"no account", status.Error(codes.NotFound, "no account for card")},
+		{"account closed", status.Error(codes.FailedPrecondition, "card's account is closed")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockCards, mockBalance, mockTxn := newTestServer(t)
+			mockAccounts := new(mockAccountsClient)
+			s.accountsClient = mockAccounts
+
+			req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1000, Currency: "GBP"}
+
+			mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+				Return(&cardspb.Card{CardId: req.CardId, UserId: "user-abc", Status: "ACTIVE"}, nil).Once()
+			mockAccounts.On("ResolveAccountForCard", mock.Anything, mock.Anything).Return(nil, tt.err).Once()
+
+			resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+			assert.NoError(t, err)
+			assert.False(t, resp.Approved)
+			assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_ACCOUNT_UNAVAILABLE, resp.DeclineCode)
+			mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+			mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthorizeCardTransaction_AccountsFails(t *testing.T) {
+	s, mockCards, _, _ := newTestServer(t)
+	mockAccounts := new(mockAccountsClient)
+	s.accountsClient = mockAccounts
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1000, Currency: "GBP"}
+
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: "user-abc", Status: "ACTIVE"}, nil).Once()
+	mockAccounts.On("ResolveAccountForCard", mock.Anything, mock.Anything).
+		Return(nil, status.Error(codes.Unavailable, "accounts down")).Once()
+
+	// Without knowing the account, the payment can't be authorized or declined
+	_, err := s.AuthorizeCardTransaction(context.Background(), req)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestAuthorizeCardTransaction_CardNotFound(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-unknown"}
+
+	// Mock GetCard call to return NotFound
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(nil, status.Error(codes.NotFound, "card not found")).Once()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeCardTransaction(ctx, req)
+
+	assert.NoError(t, err) // Business decline, not gRPC error
+	assert.NotNil(t, resp)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, "card not found", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_NOT_FOUND, resp.DeclineCode)
+
+	mockCards.AssertExpectations(t)
+	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+}
+
+func TestAuthorizeCardTransaction_CardInactive(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-frozen"}
+	userID := "user-abc"
+
+	// Mock GetCard call to return FROZEN card
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "FROZEN"}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN)
+
+	ctx := context.Background()
 	resp, err := s.AuthorizeCardTransaction(ctx, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.False(t, resp.Approved)
 	assert.Equal(t, "card is frozen", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN, resp.DeclineCode)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
 
 	mockCards.AssertExpectations(t)
 	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
@@ -203,11 +768,20 @@ func TestAuthorizeCardTransaction_InsufficientFunds(t *testing.T) {
 
 	// Mock GetCard call
 	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
-		Return(&cardspb.Card{Id: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
 
 	// Mock AuthorizeDebit call to return insufficient funds
-	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount}).
-		Return(&balancepb.DebitResult{Success: false, ErrorMessage: "insufficient funds"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount, Currency: req.Currency, IdempotencyKey: "saga-1"}).
+		Return(&balancepb.DebitResult{Success: false, ErrorMessage: "insufficient funds", DeclineReason: balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS}, nil).Once()
+
+	// The pending transaction is marked DECLINED
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "DECLINED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "DECLINED"}, nil).Once()
 
 	ctx := context.Background()
 	resp, err := s.AuthorizeCardTransaction(ctx, req)
@@ -216,10 +790,54 @@ func TestAuthorizeCardTransaction_InsufficientFunds(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.False(t, resp.Approved)
 	assert.Equal(t, "insufficient funds", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS, resp.DeclineCode)
+	assert.Equal(t, sagaCompensated, sagaState(s, "saga-1").status)
 
 	mockCards.AssertExpectations(t)
 	mockBalance.AssertExpectations(t)
-	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+	mockBalance.AssertNotCalled(t, "ReleaseHold", mock.Anything, mock.Anything)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_OverOverdraftLimit(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 10000}
+	userID := "user-abc"
+
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+
+	// An account with an arranged overdraft is declined with a reason of its own
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: false, ErrorMessage: "over overdraft limit", DeclineReason: balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "DECLINED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "DECLINED"}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeCardTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, "over overdraft limit", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED, resp.DeclineCode)
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestDeclineCodes(t *testing.T) {
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED, cardStatusDeclineCode("CLOSED"))
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED, cardStatusDeclineCode("USED"))
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_INACTIVE, cardStatusDeclineCode("INACTIVE"))
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_INVALID_CURRENCY, debitDeclineCode(balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED))
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_SYSTEM_ERROR, debitDeclineCode(balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND))
+	// Balance's wording of a decline doesn't change its code
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_SYSTEM_ERROR, debitDeclineCode(balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_UNSPECIFIED))
 }
 
 func TestAuthorizeCardTransaction_RecordTransactionFails(t *testing.T) {
@@ -231,15 +849,13 @@ func TestAuthorizeCardTransaction_RecordTransactionFails(t *testing.T) {
 
 	// Mock GetCard call
 	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
-		Return(&cardspb.Card{Id: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
 
-	// Mock AuthorizeDebit call
-	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: userID, Amount: req.Amount}).
-		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000}, nil).Once()
-
-	// Mock RecordTransaction call to fail
+	// Mock RecordTransaction call to fail, both on the first attempt and when compensation tries to resolve it
 	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
-		Return(nil, expectedError).Once()
+		Return(nil, expectedError).Twice()
 
 	ctx := context.Background()
 	resp, err := s.AuthorizeCardTransaction(ctx, req)
@@ -249,9 +865,1018 @@ func TestAuthorizeCardTransaction_RecordTransactionFails(t *testing.T) {
 	st, ok := status.FromError(err)
 	assert.True(t, ok)
 	assert.Equal(t, codes.Internal, st.Code())
-	assert.Contains(t, st.Message(), "failed to record transaction after debit")
+
+	// No money moved, and the saga is left for recovery to roll back
+	assert.Equal(t, sagaCompensating, sagaState(s, "saga-1").status)
+
+	mockCards.AssertExpectations(t)
+	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_ConfirmFailsReleasesHold(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1000}
+	userID := "user-abc"
+
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000, HoldId: "hold-1"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "AUTHORIZED", HoldId: "hold-1", AuthCode: authCode("saga-1")}).
+		Return(nil, errors.New("txn db error")).Once()
+
+	// Compensation credits back the hold and reverses the transaction
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{AccountId: userID, CurrentBalance: 10000, AvailableBalance: 10000}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "REVERSED"}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.AuthorizeCardTransaction(ctx, req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, sagaCompensated, sagaState(s, "saga-1").status)
+	// The payment no longer counts towards the card's spending limits
+	assert.Zero(t, s.spend.(*memorySpendStore).spent(req.CardId, dayPeriod(testNow)))
+
+	mockCards.AssertExpectations(t)
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_TimeoutCompensates(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1000}
+	userID := "user-abc"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Compensation must not inherit the authorization's expired deadline
+	live := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, NewBalance: 9000, HoldId: "hold-1"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "AUTHORIZED", HoldId: "hold-1", AuthCode: authCode("saga-1")}).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")).Once()
+
+	mockBalance.On("ReleaseHold", live, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{AccountId: userID}, nil).Once()
+	mockTxn.On("UpdateTransaction", live, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "REVERSED"}, nil).Once()
+
+	resp, err := s.AuthorizeCardTransaction(ctx, req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, sagaCompensated, sagaState(s, "saga-1").status)
+
+	mockCards.AssertExpectations(t)
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestRecoverSagas(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+	store := s.sagas.(*memorySagaStore)
+
+	// A saga that crashed after its payment passed every check is resumed
+	store.sagas["saga-resume"] = authSaga{
+		id: "saga-resume", accountID: "user-abc", amount: 1000,
+		step: stepCardClaimed, status: sagaInProgress, transactionID: "txn-1", holdID: "hold-1",
+	}
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-1", Status: "AUTHORIZED", HoldId: "hold-1", AuthCode: authCode("saga-resume")}).
+		Return(&transactionspb.Transaction{Id: "txn-1"}, nil).Once()
+
+	// A saga that crashed while authorizing the debit is rolled back; replaying the debit
+	// with the saga's key reveals the hold that was placed before the crash
+	store.sagas["saga-rollback"] = authSaga{
+		id: "saga-rollback", accountID: "user-abc", amount: 500,
+		step: stepTransactionRecorded, status: sagaInProgress, transactionID: "txn-2",
+	}
+	mockBalance.On("AuthorizeDebit", mock.Anything, &balancepb.AuthorizeDebitRequest{AccountId: "user-abc", Amount: 500, IdempotencyKey: "saga-rollback"}).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-2"}, nil).Once()
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-2"}).
+		Return(&balancepb.BalanceResponse{AccountId: "user-abc"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-2", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-2"}, nil).Once()
+
+	// A saga whose caller was told the authorization failed is rolled back, even though its hold was placed
+	store.sagas["saga-failed"] = authSaga{
+		id: "saga-failed", accountID: "user-abc", amount: 700,
+		step: stepDebitAuthorized, status: sagaCompensating, transactionID: "txn-3", holdID: "hold-3",
+	}
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-3"}).
+		Return(&balancepb.BalanceResponse{AccountId: "user-abc"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-3", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-3"}, nil).Once()
+
+	// A saga that crashed after the hold was placed but before the spending limits were checked
+	// is rolled back
+	store.sagas["saga-unchecked"] = authSaga{
+		id: "saga-unchecked", accountID: "user-abc", amount: 900,
+		step: stepDebitAuthorized, status: sagaInProgress, transactionID: "txn-4", holdID: "hold-4",
+	}
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-4"}).
+		Return(&balancepb.BalanceResponse{AccountId: "user-abc"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-4", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-4"}, nil).Once()
+
+	// A saga that crashed before claiming its virtual card is rolled back, so a single-use card
+	// can't pay twice
+	store.sagas["saga-unclaimed"] = authSaga{
+		id: "saga-unclaimed", cardID: "card-single", accountID: "user-abc", amount: 800,
+		step: stepSpendReserved, status: sagaInProgress, transactionID: "txn-5", holdID: "hold-5",
+	}
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-5"}).
+		Return(&balancepb.BalanceResponse{AccountId: "user-abc"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-5", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-5"}, nil).Once()
+
+	s.recoverSagas(context.Background())
+
+	assert.Equal(t, sagaCompleted, store.sagas["saga-resume"].status)
+	assert.Equal(t, sagaCompensated, store.sagas["saga-unclaimed"].status)
+	assert.Equal(t, sagaCompensated, store.sagas["saga-unchecked"].status)
+	assert.Equal(t, sagaCompensated, store.sagas["saga-rollback"].status)
+	assert.Equal(t, sagaCompensated, store.sagas["saga-failed"].status)
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestReverseAuthorization_Success(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+	store := s.spend.(*memorySpendStore)
+	store.reserve(context.Background(), cardPayment{transactionID: "txn-xyz", cardID: "card-123", at: testNow, amount: 1000}, nil)
+
+	mockTxn.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-xyz"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 1000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}, nil).Once()
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{CurrentBalance: 10000, AvailableBalance: 10000}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "REVERSED"}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.ReverseAuthorization(ctx, &cardprocessingpb.ReversalRequest{TransactionId: "txn-xyz"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "REVERSED", resp.Status)
+	assert.Zero(t, resp.Amount)
+	assert.Zero(t, store.spent("card-123", dayPeriod(testNow)))
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestReverseAuthorization_ByAuthCode(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, &transactionspb.AuthCodeQuery{CardId: "card-123", AuthCode: "K7Q2ZD"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 1000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}, nil).Once()
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(nil, status.Error(codes.FailedPrecondition, "hold is EXPIRED")).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "REVERSED"}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.ReverseAuthorization(ctx, &cardprocessingpb.ReversalRequest{CardId: "card-123", AuthCode: "K7Q2ZD"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-xyz", resp.TransactionId)
+	assert.Equal(t, "REVERSED", resp.Status)
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestReverseAuthorization_ByPanToken(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+
+	mockCards.On("GetCardByPanToken", mock.Anything, &cardspb.GetCardByPanTokenRequest{PanToken: "token-1"}).
+		Return(&cardspb.Card{CardId: "card-123", UserId: "user-abc", Status: "ACTIVE"}, nil).Once()
+	mockTxn.On("GetTransactionByAuthCode", mock.Anything, &transactionspb.AuthCodeQuery{CardId: "card-123", AuthCode: "K7Q2ZD"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 1000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}, nil).Once()
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{CurrentBalance: 10000, AvailableBalance: 10000}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "REVERSED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "REVERSED"}, nil).Once()
+
+	resp, err := s.ReverseAuthorization(context.Background(), &cardprocessingpb.ReversalRequest{PanToken: "token-1", AuthCode: "K7Q2ZD"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "REVERSED", resp.Status)
+	mockCards.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestReverseAuthorization_SettledMeanwhile(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+
+	mockTxn.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-xyz"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 1000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}, nil).Once()
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(nil, status.Error(codes.FailedPrecondition, "hold is CAPTURED")).Once()
+
+	ctx := context.Background()
+	resp, err := s.ReverseAuthorization(ctx, &cardprocessingpb.ReversalRequest{TransactionId: "txn-xyz"})
+
+	// A captured hold is settled money, which has to be refunded instead
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	mockTxn.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything)
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestPartialReversal(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+	store := s.spend.(*memorySpendStore)
+	store.reserve(context.Background(), cardPayment{transactionID: "txn-xyz", cardID: "card-123", at: testNow, amount: 1000}, nil)
+
+	req := &cardprocessingpb.PartialReversalRequest{TransactionId: "txn-xyz", Amount: 300, IdempotencyKey: "rev-1"}
+
+	mockTxn.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-xyz"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 1000, Currency: "GBP", Status: "AUTHORIZED", HoldId: "hold-1"}, nil).Once()
+	mockBalance.On("ReduceHold", mock.Anything, &balancepb.ReduceHoldRequest{HoldId: "hold-1", Amount: req.Amount, IdempotencyKey: "rev-1"}).
+		Return(&balancepb.ReduceHoldResult{RemainingAmount: 700}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Amount: 700}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 700, Status: "AUTHORIZED"}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.PartialReversal(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "AUTHORIZED", resp.Status)
+	assert.Equal(t, int64(700), resp.Amount)
+	assert.Equal(t, int64(700), store.spent("card-123", monthPeriod(testNow)))
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestRefundTransaction_Success(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.RefundRequest{TransactionId: "txn-xyz", Amount: 400, IdempotencyKey: "refund-1"}
+	original := &transactionspb.Transaction{Id: "txn-xyz", AccountId: "user-abc", CardId: "card-123", Amount: 1000, Currency: "GBP", MerchantRaw: "Test Shop", Status: "SETTLED"}
+
+	mockTxn.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-xyz"}).
+		Return(original, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, &transactionspb.TransactionInput{
+		AccountId:             "user-abc",
+		CardId:                "card-123",
+		Amount:                -400,
+		Currency:              "GBP",
+		MerchantRaw:           "Test Shop",
+		Status:                "PENDING",
+		OriginalTransactionId: "txn-xyz",
+		IdempotencyKey:        "refund-1",
+	}).Return(&transactionspb.Transaction{Id: "txn-refund", Amount: -400, Status: "PENDING", OriginalTransactionId: "txn-xyz"}, nil).Once()
+
+	// The credit is retried with the same key after a transient failure
+	creditReq := &balancepb.CreditRequest{AccountId: "user-abc", Amount: 400, Currency: "GBP", IdempotencyKey: "refund-1"}
+	mockBalance.On("CreditAccount", mock.Anything, creditReq).
+		Return((*balancepb.BalanceResponse)(nil), status.Error(codes.Unavailable, "balance unavailable")).Once()
+	mockBalance.On("CreditAccount", mock.Anything, creditReq).
+		Return(&balancepb.BalanceResponse{AccountId: "user-abc", CurrentBalance: 9400}, nil).Once()
+
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-refund", Status: "SETTLED"}).
+		Return(&transactionspb.Transaction{Id: "txn-refund", Status: "SETTLED"}, nil).Once()
+	mockTxn.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-xyz"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Amount: 1000, Currency: "GBP", Status: "PARTIALLY_REFUNDED", RefundedAmount: 400}, nil).Once()
+
+	ctx := context.Background()
+	resp, err := s.RefundTransaction(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-refund", resp.RefundTransactionId)
+	assert.Equal(t, "txn-xyz", resp.OriginalTransactionId)
+	assert.Equal(t, int64(400), resp.Amount)
+	assert.Equal(t, "PARTIALLY_REFUNDED", resp.OriginalStatus)
+
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestRefundTransaction_ExceedsOriginal(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.RefundRequest{TransactionId: "txn-xyz", Amount: 1500, IdempotencyKey: "refund-1"}
+
+	mockTxn.On("GetTransaction", mock.Anything, &transactionspb.TransactionQuery{Id: "txn-xyz"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", AccountId: "user-abc", Amount: 1000, Currency: "GBP", Status: "SETTLED"}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(nil, status.Error(codes.FailedPrecondition, "refund exceeds the 1000 left to refund")).Once()
+
+	ctx := context.Background()
+	resp, err := s.RefundTransaction(ctx, req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	mockBalance.AssertNotCalled(t, "CreditAccount", mock.Anything, mock.Anything)
+
+	mockTxn.AssertExpectations(t)
+}
+
+func TestRefundTransaction_MissingIdempotencyKey(t *testing.T) {
+	s, _, mockBalance, mockTxn := newTestServer(t)
+
+	req := &cardprocessingpb.RefundRequest{TransactionId: "txn-xyz", Amount: 400}
+
+	ctx := context.Background()
+	resp, err := s.RefundTransaction(ctx, req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+	mockBalance.AssertNotCalled(t, "CreditAccount", mock.Anything, mock.Anything)
+}
+
+func TestLoadRiskConfig(t *testing.T) {
+	cfg, err := loadRiskConfig("../../internal/card-processing/risk_rules.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, testRiskConfig, cfg)
+}
+
+func TestRiskEngine(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// approved returns a past approved authorization on card-123, ago before now
+	approved := func(ago time.Duration, amount int64, merchant, country string) riskRecord {
+		return riskRecord{
+			riskInput: riskInput{cardID: "card-123", accountID: "acc-1", amount: amount, currency: "GBP",
+				merchantName: merchant, merchantCountry: country, at: now.Add(-ago)},
+			decision: riskApprove,
+		}
+	}
+	// repeat returns n copies of record
+	repeat := func(n int, record riskRecord) []riskRecord {
+		records := make([]riskRecord, n)
+		for i := range records {
+			records[i] = record
+		}
+		return records
+	}
+
+	tests := []struct {
+		name     string
+		history  []riskRecord
+		in       riskInput
+		rules    []string
+		decision string
+	}{
+		{
+			name:     "ordinary payment",
+			history:  repeat(5, approved(time.Hour, 1500, "Coffee Shop", "GB")),
+			in:       riskInput{amount: 1200, merchantName: "Coffee Shop", merchantCountry: "GB"},
+			decision: riskApprove,
+		},
+		{
+			name:     "velocity",
+			history:  repeat(5, approved(10*time.Second, 1500, "Coffee Shop", "")),
+			in:       riskInput{amount: 1200, merchantName: "Coffee Shop"},
+			rules:    []string{"velocity"},
+			decision: riskApprove,
+		},
+		{
+			name:     "amount anomaly at a new merchant",
+			history:  repeat(5, approved(24*time.Hour, 2000, "Coffee Shop", "")),
+			in:       riskInput{amount: 60000, merchantName: "Jeweller"},
+			rules:    []string{"amount_anomaly", "new_merchant"},
+			decision: riskStepUp,
+		},
+		{
+			name:     "large amount at a known merchant",
+			history:  []riskRecord{approved(24*time.Hour, 80000, "Airline", "")},
+			in:       riskInput{amount: 60000, merchantName: "airline"},
+			decision: riskApprove,
+		},
+		{
+			name:     "country mismatch",
+			history:  []riskRecord{approved(30*time.Minute, 1500, "Coffee Shop", "GB")},
+			in:       riskInput{amount: 1200, merchantName: "Cafe", merchantCountry: "BR"},
+			rules:    []string{"country_mismatch"},
+			decision: riskStepUp,
+		},
+		{
+			name:     "country mismatch after time to travel",
+			history:  []riskRecord{approved(12*time.Hour, 1500, "Coffee Shop", "GB")},
+			in:       riskInput{amount: 1200, merchantName: "Cafe", merchantCountry: "BR"},
+			decision: riskApprove,
+		},
+		{
+			name:     "card testing",
+			history:  repeat(3, approved(time.Minute, 100, "Online Store", "")),
+			in:       riskInput{amount: 50, merchantName: "Online Store"},
+			rules:    []string{"card_testing"},
+			decision: riskDecline,
+		},
+		{
+			name: "card testing with rapid auths in another country",
+			history: append(repeat(3, approved(20*time.Second, 100, "Online Store", "US")),
+				repeat(2, approved(30*time.Second, 100, "Online Store", "US"))...),
+			in:       riskInput{amount: 50, merchantName: "Online Store", merchantCountry: "GB"},
+			rules:    []string{"velocity", "country_mismatch", "card_testing"},
+			decision: riskDecline,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryRiskStore{}
+			// The store holds history oldest first
+			for i := len(tt.history) - 1; i >= 0; i-- {
+				store.records = append(store.records, tt.history[i])
+			}
+			engine := newRiskEngine(testRiskConfig, store, func() string { return "decision-1" })
+			engine.now = func() time.Time { return now }
+
+			in := tt.in
+			in.cardID, in.accountID, in.currency = "card-123", "acc-1", "GBP"
+			record, err := engine.assess(context.Background(), in)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.rules, record.triggeredRules)
+			assert.Equal(t, tt.decision, record.decision)
+			assert.Len(t, store.records, len(tt.history)+1, "the decision is stored")
+			assert.Equal(t, now, store.records[len(store.records)-1].at)
+		})
+	}
+}
+
+func TestAuthorizeCardTransaction_RiskDeclined(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	// Three small authorizations have just been made on the card
+	store := s.risk.store.(*memoryRiskStore)
+	for i := 0; i < 3; i++ {
+		store.records = append(store.records, riskRecord{
+			riskInput: riskInput{cardID: "card-123", accountID: userID, amount: 100, currency: "GBP", at: time.Now()},
+			decision:  riskApprove,
+		})
+	}
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 100, Currency: "GBP", MerchantName: "Online Store"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD, resp.DeclineCode)
+	assert.Equal(t, riskDecline, store.records[3].decision)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+
+	// Nothing is recorded or held for a payment declined as fraud
+	mockCards.AssertExpectations(t)
+	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+}
+
+func TestAuthorizeCardTransaction_RiskStepUp(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	userID := "user-abc"
+
+	// The card was used in the UK half an hour ago
+	s.risk.store.(*memoryRiskStore).records = []riskRecord{{
+		riskInput: riskInput{cardID: "card-123", accountID: userID, amount: 1500, currency: "GBP", merchantCountry: "GB", at: time.Now().Add(-30 * time.Minute)},
+		decision:  riskApprove,
+	}}
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 1000, Currency: "GBP", MerchantName: "Cafe", MerchantCountry: "BR"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, "authentication required", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_AUTHENTICATION_REQUIRED, resp.DeclineCode)
+
+	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+}
+
+func TestCheckControls(t *testing.T) {
+	controls := &cardspb.CardControls{OnlineDisabled: true, AtmDisabled: true, BlockedMccs: []int32{7995, 7801}}
+
+	tests := []struct {
+		name    string
+		channel string
+		mcc     int32
+		code    cardprocessingpb.DeclineCode
+		reason  string
+	}{
+		{"chip and PIN", channelChip, 5411, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"channel not given", "", 0, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"contactless allowed", channelContactless, 5812, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"online disabled", channelOnline, 5411, cardprocessingpb.DeclineCode_DECLINE_CODE_CHANNEL_DISABLED, "online payments disabled"},
+		{"ATM disabled", channelATM, 6011, cardprocessingpb.DeclineCode_DECLINE_CODE_CHANNEL_DISABLED, "atm payments disabled"},
+		{"gambling blocked", channelContactless, 7995, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED, "merchant category 7995 blocked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason := checkControls(controls, &cardprocessingpb.CardAuthRequest{Channel: tt.channel, Mcc: tt.mcc})
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestReserveSpend(t *testing.T) {
+	// Spend on the card this month: 30.00 today, of which 5.00 is still pending, and 50.00 earlier
+	// in the month. A reversed payment doesn't count, nor does another card's spend, and last
+	// month's spend only counts towards the card's lifetime total of 170.00.
+	seed := func(store *memorySpendStore) {
+		for _, payment := range []cardPayment{
+			{transactionID: "txn-6", cardID: "card-123", at: testNow.Add(-time.Minute), amount: 500},
+			{transactionID: "txn-5", cardID: "card-123", at: testNow.Add(-3 * time.Hour), amount: 2500},
+			{transactionID: "txn-4", cardID: "card-456", at: testNow.Add(-4 * time.Hour), amount: 7000},
+			{transactionID: "txn-1", cardID: "card-123", at: time.Date(2025, time.March, 11, 10, 0, 0, 0, time.UTC), amount: 3000},
+			{transactionID: "txn-0", cardID: "card-123", at: time.Date(2025, time.March, 2, 10, 0, 0, 0, time.UTC), amount: 5000},
+			{transactionID: "txn-old", cardID: "card-123", at: time.Date(2025, time.February, 28, 23, 0, 0, 0, time.UTC), amount: 9000},
+		} {
+			store.reserve(context.Background(), payment, nil)
+		}
+		store.release(context.Background(), "txn-1", 0)
+	}
+
+	tests := []struct {
+		name     string
+		controls *cardspb.CardControls
+		card     *cardspb.Card
+		amount   int64
+		reason   string
+	}{
+		{"no limits", &cardspb.CardControls{}, &cardspb.Card{}, 100000, ""},
+		{"within per-transaction limit", &cardspb.CardControls{PerTransactionLimit: 5000}, &cardspb.Card{}, 5000, ""},
+		{"over per-transaction limit", &cardspb.CardControls{PerTransactionLimit: 5000}, &cardspb.Card{}, 5001, "over per-transaction limit"},
+		{"within daily limit", &cardspb.CardControls{DailyLimit: 5000}, &cardspb.Card{}, 2000, ""},
+		{"over daily limit", &cardspb.CardControls{DailyLimit: 5000}, &cardspb.Card{}, 2001, "over daily limit"},
+		{"within monthly limit", &cardspb.CardControls{DailyLimit: 10000, MonthlyLimit: 10000}, &cardspb.Card{}, 2000, ""},
+		{"over monthly limit", &cardspb.CardControls{DailyLimit: 10000, MonthlyLimit: 10000}, &cardspb.Card{}, 2001, "over monthly limit"},
+		{"within spend cap", &cardspb.CardControls{}, &cardspb.Card{SpendCap: 20000}, 3000, ""},
+		{"over spend cap", &cardspb.CardControls{}, &cardspb.Card{SpendCap: 20000}, 3001, "over spend cap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _, _ := newTestServer(t)
+			store := s.spend.(*memorySpendStore)
+			seed(store)
+
+			reason, err := s.reserveSpend(context.Background(), tt.controls, tt.card, "txn-new", "card-123", tt.amount)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.reason, reason)
+
+			// Only a payment within the limits counts towards them
+			spentToday := int64(3000)
+			if reason == "" {
+				spentToday += tt.amount
+			}
+			assert.Equal(t, spentToday, store.spent("card-123", dayPeriod(testNow)))
+		})
+	}
+}
+
+func TestReserveSpend_Release(t *testing.T) {
+	s, _, _, _ := newTestServer(t)
+	store := s.spend.(*memorySpendStore)
+	ctx := context.Background()
+	controls := &cardspb.CardControls{DailyLimit: 1500}
+
+	reason, err := s.reserveSpend(ctx, controls, &cardspb.Card{}, "txn-1", "card-123", 1000)
+	assert.NoError(t, err)
+	assert.Empty(t, reason)
+
+	// A payment counts from when it is reserved, before it is authorized
+	reason, err = s.reserveSpend(ctx, controls, &cardspb.Card{}, "txn-2", "card-123", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "over daily limit", reason)
+
+	// Once part of the first payment is reversed, the second fits; releasing again changes nothing
+	assert.NoError(t, store.release(ctx, "txn-1", 400))
+	assert.NoError(t, store.release(ctx, "txn-1", 400))
+	reason, err = s.reserveSpend(ctx, controls, &cardspb.Card{}, "txn-2", "card-123", 1000)
+	assert.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Equal(t, int64(1400), store.spent("card-123", dayPeriod(testNow)))
+
+	// Reserving a payment again doesn't count it twice
+	reason, err = s.reserveSpend(ctx, controls, &cardspb.Card{}, "txn-2", "card-123", 1000)
+	assert.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Equal(t, int64(1400), store.spent("card-123", dayPeriod(testNow)))
+}
+
+func TestAuthorizeCardTransaction_ControlsDecline(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 2000, Currency: "GBP", MerchantName: "Casino", Channel: channelOnline, Mcc: 7995}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId, BlockedMccs: []int32{7995}}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, "merchant category 7995 blocked", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED, resp.DeclineCode)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+
+	// Nothing is recorded or held for a payment the controls forbid
+	mockCards.AssertExpectations(t)
+	mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+	mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+}
+
+func TestAuthorizeCardTransaction_ByPanToken(t *testing.T) {
+	s, mockCards, _, _ := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	// Card networks identify the card by the vault token of its number
+	req := &cardprocessingpb.CardAuthRequest{PanToken: "token-1", Amount: 2000, Currency: "GBP", MerchantName: "Casino", Mcc: 7995}
+	mockCards.On("GetCardByPanToken", mock.Anything, &cardspb.GetCardByPanTokenRequest{PanToken: "token-1"}).
+		Return(&cardspb.Card{CardId: "card-123", UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: "card-123"}).
+		Return(&cardspb.CardControls{CardId: "card-123", BlockedMccs: []int32{7995}}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED, resp.DeclineCode)
+	assert.Empty(t, req.CardId, "request is not modified")
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+	mockCards.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_PinDecline(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *cardspb.PinStatus
+		err    error
+		code   cardprocessingpb.DeclineCode
+		reason string
+	}{
+		{"incorrect PIN", &cardspb.PinStatus{CardId: "card-123", AttemptsRemaining: 2}, nil, cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN, "incorrect PIN"},
+		{"PIN locked", &cardspb.PinStatus{CardId: "card-123", Locked: true}, nil, cardprocessingpb.DeclineCode_DECLINE_CODE_PIN_LOCKED, "PIN locked"},
+		{"no PIN set", nil, status.Error(codes.NotFound, "card has no PIN"), cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN, "card has no PIN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockCards, mockBalance, mockTxn := newTestServer(t)
+			redisClient, mockRedis := redismock.NewClientMock()
+			s.redisClient = redisClient
+			userID := "user-abc"
+
+			req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 2000, Currency: "GBP", MerchantName: "Shop", Channel: channelChip, Mcc: 5411, PinBlock: "141234A5B6C7D8E9"}
+			mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+				Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+			mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+				Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+			if tt.status != nil {
+				mockCards.On("VerifyPin", mock.Anything, &cardspb.VerifyPinRequest{CardId: req.CardId, PinBlock: req.PinBlock}).Return(tt.status, nil).Once()
+			} else {
+				mockCards.On("VerifyPin", mock.Anything, &cardspb.VerifyPinRequest{CardId: req.CardId, PinBlock: req.PinBlock}).Return(nil, tt.err).Once()
+			}
+			expectDeclined(mockRedis, userID, tt.code)
+
+			resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+			assert.NoError(t, err)
+			assert.False(t, resp.Approved)
+			assert.Equal(t, tt.code, resp.DeclineCode)
+			assert.Equal(t, tt.reason, resp.DeclineReason)
+			assert.NoError(t, mockRedis.ExpectationsWereMet())
+			mockCards.AssertExpectations(t)
+			mockTxn.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+			mockBalance.AssertNotCalled(t, "AuthorizeDebit", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCheckPin_NotEntered(t *testing.T) {
+	s, mockCards, _, _ := newTestServer(t)
+
+	// Online payments never carry a PIN, and one sent anyway is ignored
+	for _, req := range []*cardprocessingpb.CardAuthRequest{
+		{CardId: "card-123", Channel: channelChip},
+		{CardId: "card-123", Channel: channelOnline, PinBlock: "141234A5B6C7D8E9"},
+	} {
+		code, reason, err := s.checkPin(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, code)
+		assert.Empty(t, reason)
+	}
+	mockCards.AssertNotCalled(t, "VerifyPin", mock.Anything, mock.Anything)
+}
+
+func TestCheckPin_Verified(t *testing.T) {
+	s, mockCards, _, _ := newTestServer(t)
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Channel: channelATM, PinBlock: "141234A5B6C7D8E9"}
+	mockCards.On("VerifyPin", mock.Anything, &cardspb.VerifyPinRequest{CardId: req.CardId, PinBlock: req.PinBlock}).
+		Return(&cardspb.PinStatus{CardId: req.CardId, Verified: true, AttemptsRemaining: 3}, nil).Once()
+
+	code, _, err := s.checkPin(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, code)
+	mockCards.AssertExpectations(t)
+}
+
+func TestWithoutPin(t *testing.T) {
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", PinBlock: "141234A5B6C7D8E9"}
+
+	logged := withoutPin(req)
+
+	assert.Empty(t, logged.PinBlock)
+	assert.Equal(t, "card-123", logged.CardId)
+	assert.Equal(t, "141234A5B6C7D8E9", req.PinBlock, "request is not modified")
+}
+
+func TestAuthorizeCardTransaction_LimitExceeded(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	// A 50.00 USD payment converts to 40.00 GBP, which goes over the daily limit after 70.00 spent today
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 5000, Currency: "USD", MerchantName: "Shop"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId, DailyLimit: 10000}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1", Currency: "GBP", ConvertedAmount: 4000, FxRate: "0.8"}, nil).Once()
+	store := s.spend.(*memorySpendStore)
+	store.reserve(context.Background(), cardPayment{transactionID: "txn-1", cardID: req.CardId, at: testNow.Add(-5 * time.Hour), amount: 7000}, nil)
+
+	// The hold is released and the pending transaction marked DECLINED
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "DECLINED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "DECLINED"}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, "over daily limit", resp.DeclineReason)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED, resp.DeclineCode)
+	assert.Equal(t, sagaCompensated, sagaState(s, "saga-1").status)
+	assert.Equal(t, int64(7000), store.spent(req.CardId, dayPeriod(testNow)))
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
 
 	mockCards.AssertExpectations(t)
 	mockBalance.AssertExpectations(t)
 	mockTxn.AssertExpectations(t)
 }
+
+func TestAuthorizeCardTransaction_Recurring(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	userID := "user-abc"
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 999, Currency: "GBP", MerchantId: "merchant-1", MerchantName: "Streaming Co", Recurring: true}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE"}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, mock.AnythingOfType("*transactions.UpdateTransactionRequest")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "AUTHORIZED"}, nil).Once()
+
+	// The merchant is remembered, so it carries over if the card is reissued; failing to doesn't stop the payment
+	mockCards.On("AddRecurringMerchant", mock.Anything, &cardspb.RecurringMerchant{CardId: req.CardId, MerchantId: "merchant-1", MerchantName: "Streaming Co"}).
+		Return(nil, status.Error(codes.Unavailable, "cards unavailable")).Once()
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Approved)
+	mockCards.AssertExpectations(t)
+}
+
+func TestCheckVirtualCard(t *testing.T) {
+	tests := []struct {
+		name       string
+		card       *cardspb.Card
+		merchantID string
+		code       cardprocessingpb.DeclineCode
+		reason     string
+	}{
+		{"ordinary card", &cardspb.Card{}, "", cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"before expiry", &cardspb.Card{ExpiresAt: "2025-03-14T16:00:00Z"}, "", cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"expired", &cardspb.Card{ExpiresAt: "2025-03-14T15:30:00Z"}, "", cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_EXPIRED, "virtual card expired"},
+		{"merchant-locked first use", &cardspb.Card{VirtualType: virtualTypeMerchantLocked}, "merchant-1", cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"merchant-locked same merchant", &cardspb.Card{VirtualType: virtualTypeMerchantLocked, LockedMerchantId: "merchant-1"}, "merchant-1", cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""},
+		{"merchant-locked other merchant", &cardspb.Card{VirtualType: virtualTypeMerchantLocked, LockedMerchantId: "merchant-1"}, "merchant-2", cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, "card locked to another merchant"},
+		{"merchant-locked unknown merchant", &cardspb.Card{VirtualType: virtualTypeMerchantLocked}, "", cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, "merchant not identified"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason := checkVirtualCard(tt.card, &cardprocessingpb.CardAuthRequest{MerchantId: tt.merchantID}, testNow)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestAuthorizeCardTransaction_SingleUse(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	userID := "user-abc"
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 999, Currency: "GBP", MerchantId: "merchant-1", MerchantName: "Shop"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE", VirtualType: virtualTypeSingleUse}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1"}, nil).Once()
+
+	// The card is used up by the payment before it is approved
+	mockCards.On("UseSingleUseCard", mock.Anything, &cardspb.UseSingleUseCardRequest{CardId: req.CardId, TransactionId: "txn-xyz", Actor: actorCardProcessing}).
+		Return(&cardspb.Card{CardId: req.CardId, Status: "USED"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, mock.AnythingOfType("*transactions.UpdateTransactionRequest")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "AUTHORIZED"}, nil).Once()
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Approved)
+	mockCards.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_SingleUseRace(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	// Another payment used the card between reading it and claiming it
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 999, Currency: "GBP", MerchantId: "merchant-1", MerchantName: "Shop"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE", VirtualType: virtualTypeSingleUse}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1"}, nil).Once()
+	mockCards.On("UseSingleUseCard", mock.Anything, &cardspb.UseSingleUseCardRequest{CardId: req.CardId, TransactionId: "txn-xyz", Actor: actorCardProcessing}).
+		Return(nil, status.Error(codes.FailedPrecondition, "card is not an unused single-use card")).Once()
+
+	// The second payment is declined and its hold released
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "DECLINED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "DECLINED"}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED, resp.DeclineCode)
+	assert.Equal(t, sagaCompensated, sagaState(s, "saga-1").status)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_MerchantLockedFirstUse(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	userID := "user-abc"
+
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 999, Currency: "GBP", MerchantId: "merchant-1", MerchantName: "Streaming Co"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE", VirtualType: virtualTypeMerchantLocked}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1"}, nil).Once()
+
+	// The card is locked to the merchant once the payment is approved
+	mockCards.On("LockCardToMerchant", mock.Anything, &cardspb.LockCardToMerchantRequest{CardId: req.CardId, MerchantId: "merchant-1"}).
+		Return(&cardspb.Card{CardId: req.CardId, LockedMerchantId: "merchant-1"}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, mock.AnythingOfType("*transactions.UpdateTransactionRequest")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "AUTHORIZED"}, nil).Once()
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Approved)
+	mockCards.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_MerchantLockedRace(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	// Another merchant locked the card between reading it and locking it
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 999, Currency: "GBP", MerchantId: "merchant-2", MerchantName: "Shop"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE", VirtualType: virtualTypeMerchantLocked}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1"}, nil).Once()
+	mockCards.On("LockCardToMerchant", mock.Anything, &cardspb.LockCardToMerchantRequest{CardId: req.CardId, MerchantId: "merchant-2"}).
+		Return(nil, status.Error(codes.FailedPrecondition, "card is locked to another merchant")).Once()
+
+	// The hold is released, the payment stops counting towards the card's spending and the pending
+	// transaction is marked DECLINED
+	mockBalance.On("ReleaseHold", mock.Anything, &balancepb.HoldID{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "DECLINED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "DECLINED"}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, resp.DeclineCode)
+	assert.Equal(t, sagaCompensated, sagaState(s, "saga-1").status)
+	assert.Equal(t, int64(0), s.spend.(*memorySpendStore).spent(req.CardId, lifetimePeriod))
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+	mockBalance.AssertExpectations(t)
+	mockTxn.AssertExpectations(t)
+}
+
+func TestAuthorizeCardTransaction_MerchantLockedDeclined(t *testing.T) {
+	s, mockCards, mockBalance, mockTxn := newTestServer(t)
+	redisClient, mockRedis := redismock.NewClientMock()
+	s.redisClient = redisClient
+	userID := "user-abc"
+
+	// A payment declined for any other reason leaves the card free for another merchant
+	req := &cardprocessingpb.CardAuthRequest{CardId: "card-123", Amount: 999, Currency: "GBP", MerchantId: "merchant-1", MerchantName: "Shop"}
+	mockCards.On("GetCard", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.Card{CardId: req.CardId, UserId: userID, Status: "ACTIVE", VirtualType: virtualTypeMerchantLocked}, nil).Once()
+	mockCards.On("GetCardControls", mock.Anything, &cardspb.GetCardRequest{CardId: req.CardId}).
+		Return(&cardspb.CardControls{CardId: req.CardId}, nil).Once()
+	mockTxn.On("RecordTransaction", mock.Anything, mock.AnythingOfType("*transactions.TransactionInput")).
+		Return(&transactionspb.Transaction{Id: "txn-xyz"}, nil).Once()
+	mockBalance.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: false, ErrorMessage: "insufficient funds", DeclineReason: balancepb.DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS}, nil).Once()
+	mockTxn.On("UpdateTransaction", mock.Anything, &transactionspb.UpdateTransactionRequest{Id: "txn-xyz", Status: "DECLINED"}).
+		Return(&transactionspb.Transaction{Id: "txn-xyz", Status: "DECLINED"}, nil).Once()
+	expectDeclined(mockRedis, userID, cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS)
+
+	resp, err := s.AuthorizeCardTransaction(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Approved)
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS, resp.DeclineCode)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+	mockCards.AssertNotCalled(t, "LockCardToMerchant", mock.Anything, mock.Anything)
+}
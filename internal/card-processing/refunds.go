@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	balancepb "github.com/sambacha/monzo/v2/balance"
+	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing"
+	transactionspb "github.com/sambacha/monzo/v2/transactions"
+)
+
+// txnSettled is the status a refund transaction is given once the account has been credited
+const txnSettled = "SETTLED"
+
+// ReverseAuthorization cancels an authorization in full, e.g. when a merchant voids a payment
+// before it settles. The hold is released and the transaction marked REVERSED; reversing a
+// transaction that is already REVERSED succeeds without doing anything.
+func (s *server) ReverseAuthorization(ctx context.Context, req *cardprocessingpb.ReversalRequest) (*cardprocessingpb.ReversalReply, error) {
+	log.Printf("Received ReverseAuthorization request: %+v", req)
+
+	txn, err := s.reversalTransaction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	switch txn.GetStatus() {
+	case txnReversed:
+		return &cardprocessingpb.ReversalReply{TransactionId: txn.GetId(), Status: txnReversed}, nil
+	case txnAuthorized:
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "transaction is %s", txn.GetStatus())
+	}
+
+	if txn.GetHoldId() != "" {
+		err := withRetry(ctx, "ReleaseHold", func() error {
+			_, err := s.balanceClient.ReleaseHold(ctx, &balancepb.HoldID{HoldId: txn.GetHoldId()})
+			return err
+		})
+		switch {
+		case err == nil:
+		case status.Code(err) == codes.FailedPrecondition && status.Convert(err).Message() == "hold is CAPTURED":
+			// The transaction settled in the meantime, so it can only be refunded now
+			return nil, status.Errorf(codes.FailedPrecondition, "transaction has settled")
+		case status.Code(err) == codes.FailedPrecondition:
+			// A hold that has already been released or has expired needs no further action
+		default:
+			log.Printf("failed to release hold %s for transaction %s: %v", txn.GetHoldId(), txn.GetId(), err)
+			return nil, status.Errorf(codes.Internal, "failed to reverse authorization")
+		}
+	}
+
+	// Stop counting the payment towards the card's spending limits
+	if err := s.spend.release(ctx, txn.GetId(), 0); err != nil {
+		log.Printf("failed to release spend of transaction %s: %v", txn.GetId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to reverse authorization")
+	}
+
+	if err := s.updateTransactionStatus(ctx, txn.GetId(), txnReversed); err != nil {
+		log.Printf("failed to mark transaction %s REVERSED: %v", txn.GetId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to reverse authorization")
+	}
+
+	log.Printf("Reversed authorization of transaction %s for %d %s", txn.GetId(), txn.GetAmount(), txn.GetCurrency())
+
+	return &cardprocessingpb.ReversalReply{TransactionId: txn.GetId(), Status: txnReversed}, nil
+}
+
+// PartialReversal reduces an authorization, e.g. when a hotel releases part of a deposit.
+// The released amount is returned to the available balance and the transaction stays AUTHORIZED
+// for the remainder, which is what settles.
+func (s *server) PartialReversal(ctx context.Context, req *cardprocessingpb.PartialReversalRequest) (*cardprocessingpb.ReversalReply, error) {
+	log.Printf("Received PartialReversal request: %+v", req)
+
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
+	txn, err := s.getTransaction(ctx, req.GetTransactionId())
+	if err != nil {
+		return nil, err
+	}
+	if txn.GetStatus() != txnAuthorized || txn.GetHoldId() == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "transaction is %s", txn.GetStatus())
+	}
+
+	// The key keeps retries below from reducing the hold twice
+	idempotencyKey := req.GetIdempotencyKey()
+	if idempotencyKey == "" {
+		idempotencyKey = s.newSagaID()
+	}
+	var result *balancepb.ReduceHoldResult
+	err = withRetry(ctx, "ReduceHold", func() error {
+		var err error
+		result, err = s.balanceClient.ReduceHold(ctx, &balancepb.ReduceHoldRequest{
+			HoldId:         txn.GetHoldId(),
+			Amount:         req.GetAmount(),
+			IdempotencyKey: idempotencyKey,
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to reduce hold %s for transaction %s: %v", txn.GetHoldId(), txn.GetId(), err)
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.FailedPrecondition, codes.AlreadyExists:
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse authorization")
+	}
+
+	// Only what is still authorized counts towards the card's spending limits. A payment in a foreign
+	// currency keeps counting its converted amount, which the reversal doesn't change.
+	if txn.GetBillingCurrency() == "" {
+		if err := s.spend.release(ctx, txn.GetId(), result.GetRemainingAmount()); err != nil {
+			log.Printf("failed to release spend of transaction %s: %v", txn.GetId(), err)
+			return nil, status.Errorf(codes.Internal, "failed to reverse authorization")
+		}
+	}
+
+	err = withRetry(ctx, "UpdateTransaction", func() error {
+		_, err := s.transactionsClient.UpdateTransaction(ctx, &transactionspb.UpdateTransactionRequest{
+			Id:     txn.GetId(),
+			Amount: result.GetRemainingAmount(),
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to update amount of transaction %s: %v", txn.GetId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to reverse authorization")
+	}
+
+	log.Printf("Reversed %d %s of transaction %s, %d still authorized", req.GetAmount(), txn.GetCurrency(), txn.GetId(), result.GetRemainingAmount())
+
+	return &cardprocessingpb.ReversalReply{TransactionId: txn.GetId(), Status: txnAuthorized, Amount: result.GetRemainingAmount()}, nil
+}
+
+// RefundTransaction refunds all or part of a settled card transaction. The refund is recorded as
+// its own transaction linked to the original, and the amount credited back to the account.
+// Every step is keyed by the idempotency key, so a retry after a failure completes the refund. The
+// caller must send the key: one made up here would differ on retry and refund the amount twice.
+func (s *server) RefundTransaction(ctx context.Context, req *cardprocessingpb.RefundRequest) (*cardprocessingpb.RefundReply, error) {
+	log.Printf("Received RefundTransaction request: %+v", req)
+
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+	idempotencyKey := req.GetIdempotencyKey()
+	if idempotencyKey == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency_key is required")
+	}
+
+	original, err := s.getTransaction(ctx, req.GetTransactionId())
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Record the refund, which Transactions checks against what is left to refund
+	var refund *transactionspb.Transaction
+	err = withRetry(ctx, "RecordTransaction", func() error {
+		var err error
+		refund, err = s.transactionsClient.RecordTransaction(ctx, &transactionspb.TransactionInput{
+			AccountId:             original.GetAccountId(),
+			CardId:                original.GetCardId(),
+			Amount:                -req.GetAmount(),
+			Currency:              original.GetCurrency(),
+			MerchantId:            original.GetMerchantId(),
+			MerchantRaw:           original.GetMerchantRaw(),
+			Status:                txnPending,
+			OriginalTransactionId: original.GetId(),
+			IdempotencyKey:        idempotencyKey,
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to record refund of transaction %s: %v", original.GetId(), err)
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.FailedPrecondition, codes.AlreadyExists:
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "failed to refund transaction")
+	}
+
+	// 2. Credit the refund back to the account
+	err = withRetry(ctx, "CreditAccount", func() error {
+		_, err := s.balanceClient.CreditAccount(ctx, &balancepb.CreditRequest{
+			AccountId:      original.GetAccountId(),
+			Amount:         req.GetAmount(),
+			Currency:       original.GetCurrency(),
+			IdempotencyKey: idempotencyKey,
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to credit refund %s to account %s: %v", refund.GetId(), original.GetAccountId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to refund transaction")
+	}
+
+	// 3. Settle the refund transaction
+	if err := s.updateTransactionStatus(ctx, refund.GetId(), txnSettled); err != nil {
+		log.Printf("failed to settle refund %s: %v", refund.GetId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to refund transaction")
+	}
+
+	// Report the original's status as it is now the refund has been counted against it
+	original, err = s.getTransaction(ctx, original.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Refunded %d %s of transaction %s as transaction %s", req.GetAmount(), original.GetCurrency(), original.GetId(), refund.GetId())
+
+	return &cardprocessingpb.RefundReply{
+		RefundTransactionId:   refund.GetId(),
+		OriginalTransactionId: original.GetId(),
+		Amount:                req.GetAmount(),
+		OriginalStatus:        original.GetStatus(),
+	}, nil
+}
+
+// getTransaction fetches a transaction, mapping errors to those returned to the caller
+func (s *server) getTransaction(ctx context.Context, transactionID string) (*transactionspb.Transaction, error) {
+	var txn *transactionspb.Transaction
+	err := withRetry(ctx, "GetTransaction", func() error {
+		var err error
+		txn, err = s.transactionsClient.GetTransaction(ctx, &transactionspb.TransactionQuery{Id: transactionID})
+		return err
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "transaction not found")
+		}
+		log.Printf("failed to get transaction %s: %v", transactionID, err)
+		return nil, status.Errorf(codes.Internal, "failed to get transaction")
+	}
+	return txn, nil
+}
+
+// reversalTransaction fetches the transaction a reversal refers to, by its ID or, as card
+// networks refer to it, by the card and the authorization code it was approved with
+func (s *server) reversalTransaction(ctx context.Context, req *cardprocessingpb.ReversalRequest) (*transactionspb.Transaction, error) {
+	if req.GetTransactionId() != "" || req.GetAuthCode() == "" {
+		return s.getTransaction(ctx, req.GetTransactionId())
+	}
+
+	cardID := req.GetCardId()
+	if cardID == "" && req.GetPanToken() != "" {
+		card, err := s.lookupCard(ctx, "", req.GetPanToken())
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil, status.Errorf(codes.NotFound, "card not found")
+			}
+			log.Printf("failed to get card by token for reversal: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to get transaction")
+		}
+		cardID = card.GetCardId()
+	}
+
+	var txn *transactionspb.Transaction
+	err := withRetry(ctx, "GetTransactionByAuthCode", func() error {
+		var err error
+		txn, err = s.transactionsClient.GetTransactionByAuthCode(ctx, &transactionspb.AuthCodeQuery{CardId: cardID, AuthCode: req.GetAuthCode()})
+		return err
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "transaction not found")
+		}
+		log.Printf("failed to get transaction of card %s with auth code %s: %v", cardID, req.GetAuthCode(), err)
+		return nil, status.Errorf(codes.Internal, "failed to get transaction")
+	}
+	return txn, nil
+}
+
+// updateTransactionStatus sets the status of a transaction
+func (s *server) updateTransactionStatus(ctx context.Context, transactionID, txnStatus string) error {
+	return withRetry(ctx, "UpdateTransaction", func() error {
+		_, err := s.transactionsClient.UpdateTransaction(ctx, &transactionspb.UpdateTransactionRequest{
+			Id:     transactionID,
+			Status: txnStatus,
+		})
+		return err
+	})
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing"
+)
+
+// Risk decisions
+const (
+	riskApprove = "APPROVE"
+	riskStepUp  = "STEP_UP" // the cardholder must authenticate before the payment can go through
+	riskDecline = "DECLINE"
+)
+
+// maxRiskScore caps the sum of the scores of the rules an authorization triggers
+const maxRiskScore = 100
+
+// riskHistorySize is how many of an account's most recent decisions the rules look back over
+const riskHistorySize = 200
+
+// riskInput is an authorization as seen by the risk rules
+type riskInput struct {
+	cardID          string
+	accountID       string
+	amount          int64
+	currency        string
+	merchantID      string
+	merchantName    string
+	merchantCountry string
+	at              time.Time
+}
+
+// riskRecord is a past risk decision, which rules compare new authorizations with
+type riskRecord struct {
+	riskInput
+	id             string
+	score          int
+	decision       string
+	triggeredRules []string
+}
+
+// merchantKey identifies the merchant of an authorization, by ID if it has one
+func (in riskInput) merchantKey() string {
+	if in.merchantID != "" {
+		return in.merchantID
+	}
+	return strings.ToLower(strings.TrimSpace(in.merchantName))
+}
+
+// riskRule scores one kind of risk. A rule that isn't triggered scores 0.
+type riskRule interface {
+	name() string
+	score(in riskInput, history []riskRecord) int
+}
+
+// riskStore persists risk decisions
+type riskStore interface {
+	save(ctx context.Context, record *riskRecord) error
+	// recent returns an account's most recent decisions, newest first
+	recent(ctx context.Context, accountID string, limit int) ([]riskRecord, error)
+}
+
+// riskEngine scores authorizations with its rules and decides whether they go ahead
+type riskEngine struct {
+	rules        []riskRule
+	stepUpScore  int // scores from this up need the cardholder to authenticate
+	declineScore int // scores from this up are declined
+	store        riskStore
+	newID        func() string
+	now          func() time.Time
+}
+
+// assess scores an authorization, decides on it and stores the decision
+func (e *riskEngine) assess(ctx context.Context, in riskInput) (*riskRecord, error) {
+	in.at = e.now()
+	history, err := e.store.recent(ctx, in.accountID, riskHistorySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk history of account %s: %w", in.accountID, err)
+	}
+
+	record := &riskRecord{riskInput: in, id: e.newID(), decision: riskApprove}
+	for _, rule := range e.rules {
+		if score := rule.score(in, history); score > 0 {
+			record.score += score
+			record.triggeredRules = append(record.triggeredRules, rule.name())
+		}
+	}
+	if record.score > maxRiskScore {
+		record.score = maxRiskScore
+	}
+	switch {
+	case record.score >= e.declineScore:
+		record.decision = riskDecline
+	case record.score >= e.stepUpScore:
+		record.decision = riskStepUp
+	}
+
+	if err := e.store.save(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to save risk decision for card %s: %w", in.cardID, err)
+	}
+	return record, nil
+}
+
+// riskConfig is the risk engine configuration file. Rules left out are not run.
+type riskConfig struct {
+	StepUpScore     int                  `json:"step_up_score"`
+	DeclineScore    int                  `json:"decline_score"`
+	Velocity        *velocityRule        `json:"velocity"`
+	AmountAnomaly   *amountAnomalyRule   `json:"amount_anomaly"`
+	NewMerchant     *newMerchantRule     `json:"new_merchant"`
+	CountryMismatch *countryMismatchRule `json:"country_mismatch"`
+	CardTesting     *cardTestingRule     `json:"card_testing"`
+}
+
+// loadRiskConfig reads the risk engine configuration from a JSON file
+func loadRiskConfig(path string) (*riskConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg riskConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cfg.StepUpScore <= 0 || cfg.DeclineScore < cfg.StepUpScore {
+		return nil, fmt.Errorf("%s: step_up_score must be positive and no more than decline_score", path)
+	}
+	return &cfg, nil
+}
+
+// newRiskEngine returns an engine running the rules in cfg
+func newRiskEngine(cfg *riskConfig, store riskStore, newID func() string) *riskEngine {
+	e := &riskEngine{
+		stepUpScore:  cfg.StepUpScore,
+		declineScore: cfg.DeclineScore,
+		store:        store,
+		newID:        newID,
+		now:          time.Now,
+	}
+	if cfg.Velocity != nil {
+		e.rules = append(e.rules, cfg.Velocity)
+	}
+	if cfg.AmountAnomaly != nil {
+		e.rules = append(e.rules, cfg.AmountAnomaly)
+	}
+	if cfg.NewMerchant != nil {
+		e.rules = append(e.rules, cfg.NewMerchant)
+	}
+	if cfg.CountryMismatch != nil {
+		e.rules = append(e.rules, cfg.CountryMismatch)
+	}
+	if cfg.CardTesting != nil {
+		e.rules = append(e.rules, cfg.CardTesting)
+	}
+	log.Printf("Risk engine running %d rules, step-up from %d, decline from %d", len(e.rules), e.stepUpScore, e.declineScore)
+	return e
+}
+
+// velocityRule triggers when a card makes more than MaxAuths authorizations within a window
+type velocityRule struct {
+	Score         int `json:"score"`
+	MaxAuths      int `json:"max_auths"`
+	WindowSeconds int `json:"window_seconds"`
+}
+
+func (r *velocityRule) name() string { return "velocity" }
+
+func (r *velocityRule) score(in riskInput, history []riskRecord) int {
+	since := in.at.Add(-time.Duration(r.WindowSeconds) * time.Second)
+	count := 1 // this authorization
+	for _, h := range history {
+		if h.cardID == in.cardID && h.at.After(since) {
+			count++
+		}
+	}
+	if count > r.MaxAuths {
+		return r.Score
+	}
+	return 0
+}
+
+// amountAnomalyRule triggers when an amount is more than Multiplier times the account's average
+// approved amount in the same currency, once the account has MinHistory approved authorizations
+type amountAnomalyRule struct {
+	Score      int     `json:"score"`
+	Multiplier float64 `json:"multiplier"`
+	MinHistory int     `json:"min_history"`
+}
+
+func (r *amountAnomalyRule) name() string { return "amount_anomaly" }
+
+func (r *amountAnomalyRule) score(in riskInput, history []riskRecord) int {
+	var total int64
+	var count int
+	for _, h := range history {
+		if h.decision == riskApprove && h.currency == in.currency {
+			total += h.amount
+			count++
+		}
+	}
+	if count == 0 || count < r.MinHistory {
+		return 0
+	}
+	average := float64(total) / float64(count)
+	if float64(in.amount) > average*r.Multiplier {
+		return r.Score
+	}
+	return 0
+}
+
+// newMerchantRule triggers when an account pays a merchant it hasn't paid before an amount of at
+// least Threshold, in minor units of currency
+type newMerchantRule struct {
+	Score     int   `json:"score"`
+	Threshold int64 `json:"threshold"`
+}
+
+func (r *newMerchantRule) name() string { return "new_merchant" }
+
+func (r *newMerchantRule) score(in riskInput, history []riskRecord) int {
+	merchant := in.merchantKey()
+	if merchant == "" || in.amount < r.Threshold {
+		return 0
+	}
+	for _, h := range history {
+		if h.decision == riskApprove && h.merchantKey() == merchant {
+			return 0
+		}
+	}
+	return r.Score
+}
+
+// countryMismatchRule triggers when a card is used in a different country from one it was used in
+// within a window, too soon to have travelled between them
+type countryMismatchRule struct {
+	Score         int `json:"score"`
+	WindowSeconds int `json:"window_seconds"`
+}
+
+func (r *countryMismatchRule) name() string { return "country_mismatch" }
+
+func (r *countryMismatchRule) score(in riskInput, history []riskRecord) int {
+	if in.merchantCountry == "" {
+		return 0
+	}
+	since := in.at.Add(-time.Duration(r.WindowSeconds) * time.Second)
+	for _, h := range history {
+		if h.cardID == in.cardID && h.at.After(since) && h.merchantCountry != "" && !strings.EqualFold(h.merchantCountry, in.merchantCountry) {
+			return r.Score
+		}
+	}
+	return 0
+}
+
+// cardTestingRule triggers on a burst of small authorizations on a card, as made by fraudsters
+// checking whether stolen card details work. MaxSmallAuths of at most SmallAmount are allowed within a window.
+type cardTestingRule struct {
+	Score         int   `json:"score"`
+	SmallAmount   int64 `json:"small_amount"`
+	MaxSmallAuths int   `json:"max_small_auths"`
+	WindowSeconds int   `json:"window_seconds"`
+}
+
+func (r *cardTestingRule) name() string { return "card_testing" }
+
+func (r *cardTestingRule) score(in riskInput, history []riskRecord) int {
+	if in.amount > r.SmallAmount {
+		return 0
+	}
+	since := in.at.Add(-time.Duration(r.WindowSeconds) * time.Second)
+	count := 1 // this authorization
+	for _, h := range history {
+		if h.cardID == in.cardID && h.at.After(since) && h.amount <= r.SmallAmount {
+			count++
+		}
+	}
+	if count > r.MaxSmallAuths {
+		return r.Score
+	}
+	return 0
+}
+
+// pgRiskStore stores risk decisions in the risk_decisions table
+type pgRiskStore struct {
+	db *sql.DB
+}
+
+func (p *pgRiskStore) save(ctx context.Context, record *riskRecord) error {
+	query := `INSERT INTO risk_decisions (decision_id, card_id, account_id, amount, currency, merchant_id, merchant_name, merchant_country,
+			  score, decision, triggered_rules, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err := p.db.ExecContext(ctx, query, record.id, record.cardID, record.accountID, record.amount, record.currency,
+		record.merchantID, record.merchantName, record.merchantCountry, record.score, record.decision,
+		strings.Join(record.triggeredRules, ","), record.at)
+	return err
+}
+
+func (p *pgRiskStore) recent(ctx context.Context, accountID string, limit int) ([]riskRecord, error) {
+	query := `SELECT decision_id, card_id, account_id, amount, currency, merchant_id, merchant_name, merchant_country,
+			  score, decision, triggered_rules, created_at
+			  FROM risk_decisions WHERE account_id = $1 ORDER BY created_at DESC LIMIT $2`
+	rows, err := p.db.QueryContext(ctx, query, accountID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []riskRecord
+	for rows.Next() {
+		var record riskRecord
+		var triggeredRules string
+		if err := rows.Scan(&record.id, &record.cardID, &record.accountID, &record.amount, &record.currency,
+			&record.merchantID, &record.merchantName, &record.merchantCountry, &record.score, &record.decision,
+			&triggeredRules, &record.at); err != nil {
+			return nil, err
+		}
+		if triggeredRules != "" {
+			record.triggeredRules = strings.Split(triggeredRules, ",")
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// riskDeclineCode returns the decline code for a risk decision other than APPROVE
+func riskDeclineCode(decision string) cardprocessingpb.DeclineCode {
+	if decision == riskStepUp {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_AUTHENTICATION_REQUIRED
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	balancepb "github.com/sambacha/monzo/v2/balance"
+	transactionspb "github.com/sambacha/monzo/v2/transactions"
+)
+
+// Saga steps, recording the last step of an authorization that is known to have completed
+const (
+	stepStarted             = "STARTED"              // saga persisted, transaction may or may not have been recorded
+	stepTransactionRecorded = "TRANSACTION_RECORDED" // PENDING transaction recorded, debit may or may not have been authorized
+	stepDebitAuthorized     = "DEBIT_AUTHORIZED"     // debit outcome known: a hold was placed or the debit was declined
+	stepSpendReserved       = "SPEND_RESERVED"       // payment checked against and counted towards the card's spending limits
+	stepCardClaimed         = "CARD_CLAIMED"         // virtual card, if any, claimed by the payment; only confirmation is left
+	stepConfirmed           = "CONFIRMED"            // transaction marked AUTHORIZED
+)
+
+// Saga statuses
+const (
+	sagaInProgress   = "IN_PROGRESS"
+	sagaCompleted    = "COMPLETED"
+	sagaCompensating = "COMPENSATING"
+	sagaCompensated  = "COMPENSATED"
+)
+
+// Transaction statuses set by the saga
+const (
+	txnPending    = "PENDING"
+	txnAuthorized = "AUTHORIZED"
+	txnDeclined   = "DECLINED"
+	txnReversed   = "REVERSED"
+)
+
+// authorizationTimeout bounds how long an authorization, or the recovery of one saga, may run.
+// Card networks stop waiting for an answer long before this.
+const authorizationTimeout = 20 * time.Second
+
+// compensationTimeout bounds how long rolling back a saga may run. A saga is often rolled back
+// because its authorization ran out of time, so compensation gets a deadline of its own.
+const compensationTimeout = 10 * time.Second
+
+// sagaRecoveryAge is how long a saga must have been idle before recovery picks it up. Every step
+// of a saga updates it and no authorization or recovery outlives authorizationTimeout plus
+// compensationTimeout, so a saga idle for this long has been abandoned by whatever was running it.
+const sagaRecoveryAge = 5 * time.Minute
+
+// sagaRecoveryBatch is the most sagas one recovery run claims. Recovering them one after another
+// takes at most sagaRecoveryBatch * (authorizationTimeout + compensationTimeout), which must stay
+// below sagaRecoveryAge so that the last of them isn't claimed again before it is recovered.
+const sagaRecoveryBatch = 8
+
+// authSaga is the persisted state of a single card authorization
+type authSaga struct {
+	id            string // also used as the idempotency key for every downstream call
+	cardID        string
+	accountID     string
+	amount        int64
+	currency      string
+	merchantID    string
+	merchantName  string
+	step          string
+	status        string
+	transactionID string
+	holdID        string
+	declineReason string
+	// Set when the debit was converted into the account's currency
+	billingCurrency string
+	billingAmount   int64
+	fxRate          string
+	fxFee           int64
+}
+
+// heldAmount returns the amount of the saga's debit in the account's currency
+func (saga *authSaga) heldAmount() int64 {
+	if saga.billingCurrency != "" {
+		return saga.billingAmount
+	}
+	return saga.amount
+}
+
+// sagaStore persists authorization sagas
+type sagaStore interface {
+	create(ctx context.Context, saga *authSaga) error
+	update(ctx context.Context, saga *authSaga) error
+	// claimRecoverable returns up to limit unfinished sagas idle for longer than idleFor, and marks
+	// them as updated so that no other replica claims them until they are idle again
+	claimRecoverable(ctx context.Context, idleFor time.Duration, limit int) ([]*authSaga, error)
+}
+
+// pgSagaStore stores sagas in the authorization_sagas table
+type pgSagaStore struct {
+	db *sql.DB
+}
+
+func (p *pgSagaStore) create(ctx context.Context, saga *authSaga) error {
+	query := `INSERT INTO authorization_sagas (saga_id, card_id, account_id, amount, currency, merchant_id, merchant_name, step, status, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())`
+	_, err := p.db.ExecContext(ctx, query, saga.id, saga.cardID, saga.accountID, saga.amount, saga.currency,
+		saga.merchantID, saga.merchantName, saga.step, saga.status)
+	return err
+}
+
+func (p *pgSagaStore) update(ctx context.Context, saga *authSaga) error {
+	query := `UPDATE authorization_sagas SET step = $1, status = $2, transaction_id = $3, hold_id = $4, decline_reason = $5,
+			  billing_currency = $6, billing_amount = $7, fx_rate = $8, fx_fee = $9, updated_at = NOW() WHERE saga_id = $10`
+	_, err := p.db.ExecContext(ctx, query, saga.step, saga.status, saga.transactionID, saga.holdID, saga.declineReason,
+		saga.billingCurrency, saga.billingAmount, saga.fxRate, saga.fxFee, saga.id)
+	return err
+}
+
+func (p *pgSagaStore) claimRecoverable(ctx context.Context, idleFor time.Duration, limit int) ([]*authSaga, error) {
+	// Rows another replica is claiming are skipped rather than waited for
+	query := `WITH claimed AS (
+				SELECT saga_id FROM authorization_sagas WHERE status IN ($1, $2) AND updated_at < $3
+				ORDER BY created_at LIMIT $4 FOR UPDATE SKIP LOCKED
+			  )
+			  UPDATE authorization_sagas a SET updated_at = NOW() FROM claimed WHERE a.saga_id = claimed.saga_id
+			  RETURNING a.saga_id, a.card_id, a.account_id, a.amount, a.currency, a.merchant_id, a.merchant_name, a.step, a.status,
+			  a.transaction_id, a.hold_id, a.decline_reason, a.billing_currency, a.billing_amount, a.fx_rate, a.fx_fee`
+	rows, err := p.db.QueryContext(ctx, query, sagaInProgress, sagaCompensating, time.Now().Add(-idleFor), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []*authSaga
+	for rows.Next() {
+		var saga authSaga
+		if err := rows.Scan(&saga.id, &saga.cardID, &saga.accountID, &saga.amount, &saga.currency, &saga.merchantID,
+			&saga.merchantName, &saga.step, &saga.status, &saga.transactionID, &saga.holdID, &saga.declineReason,
+			&saga.billingCurrency, &saga.billingAmount, &saga.fxRate, &saga.fxFee); err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, &saga)
+	}
+	return sagas, rows.Err()
+}
+
+// advance moves the saga to the given step and persists it
+func (s *server) advance(ctx context.Context, saga *authSaga, step, sagaStatus string) error {
+	saga.step = step
+	saga.status = sagaStatus
+	if err := s.sagas.update(ctx, saga); err != nil {
+		return fmt.Errorf("failed to persist saga %s at step %s: %w", saga.id, step, err)
+	}
+	return nil
+}
+
+// recordPending records the saga's transaction as PENDING. Retrying with the saga ID as
+// idempotency key returns the transaction recorded by an earlier attempt, if any.
+func (s *server) recordPending(ctx context.Context, saga *authSaga) (*transactionspb.Transaction, error) {
+	recordTxnReq := &transactionspb.TransactionInput{
+		AccountId:      saga.accountID,
+		CardId:         saga.cardID,
+		Amount:         saga.amount,
+		Currency:       saga.currency,
+		MerchantId:     saga.merchantID,
+		MerchantRaw:    saga.merchantName, // Use raw name from auth request
+		Status:         txnPending,
+		IdempotencyKey: saga.id,
+	}
+	var txn *transactionspb.Transaction
+	err := withRetry(ctx, "RecordTransaction", func() error {
+		var err error
+		txn, err = s.transactionsClient.RecordTransaction(ctx, recordTxnReq)
+		return err
+	})
+	return txn, err
+}
+
+// authorizeDebit places a hold for the saga's amount. Retrying with the saga ID as
+// idempotency key returns the result of an earlier attempt, so the account is never debited twice.
+func (s *server) authorizeDebit(ctx context.Context, saga *authSaga) (*balancepb.DebitResult, error) {
+	authorizeDebitReq := &balancepb.AuthorizeDebitRequest{
+		AccountId:      saga.accountID,
+		Amount:         saga.amount,
+		Currency:       saga.currency,
+		IdempotencyKey: saga.id,
+	}
+	var debitResult *balancepb.DebitResult
+	err := withRetry(ctx, "AuthorizeDebit", func() error {
+		var err error
+		debitResult, err = s.balanceClient.AuthorizeDebit(ctx, authorizeDebitReq)
+		return err
+	})
+	return debitResult, err
+}
+
+// confirm marks the saga's transaction AUTHORIZED against its hold and completes the saga
+func (s *server) confirm(ctx context.Context, saga *authSaga) error {
+	err := withRetry(ctx, "UpdateTransaction", func() error {
+		_, err := s.transactionsClient.UpdateTransaction(ctx, &transactionspb.UpdateTransactionRequest{
+			Id:              saga.transactionID,
+			Status:          txnAuthorized,
+			HoldId:          saga.holdID,
+			AuthCode:        authCode(saga.id),
+			BillingCurrency: saga.billingCurrency,
+			BillingAmount:   saga.billingAmount,
+			FxRate:          saga.fxRate,
+			FxFee:           saga.fxFee,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to confirm transaction %s: %w", saga.transactionID, err)
+	}
+	return s.advance(ctx, saga, stepConfirmed, sagaCompleted)
+}
+
+// authCodeAlphabet holds the characters an authorization code is made of
+const authCodeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// authCode returns the six character authorization code for an approved saga. It is derived from
+// the saga ID, so a saga resumed by recovery confirms its transaction with the same code.
+func authCode(sagaID string) string {
+	sum := sha256.Sum256([]byte(sagaID))
+	code := make([]byte, 6)
+	for i := range code {
+		code[i] = authCodeAlphabet[int(sum[i])%len(authCodeAlphabet)]
+	}
+	return string(code)
+}
+
+// compensate rolls back whatever the saga has done so far: it releases any hold and any spend
+// counted towards the card's limits, and marks the transaction DECLINED or REVERSED. Steps whose outcome is unknown are first resolved by replaying
+// them with the saga's idempotency key. On failure the saga is left COMPENSATING for recovery to retry.
+func (s *server) compensate(ctx context.Context, saga *authSaga) error {
+	debitAttempted := saga.step == stepTransactionRecorded || saga.step == stepDebitAuthorized
+	if saga.status != sagaCompensating {
+		if err := s.advance(ctx, saga, saga.step, sagaCompensating); err != nil {
+			return err
+		}
+	}
+
+	// Find out which transaction was recorded, if the saga stopped before saving it
+	if saga.transactionID == "" {
+		txn, err := s.recordPending(ctx, saga)
+		if err != nil {
+			return fmt.Errorf("failed to resolve transaction for saga %s: %w", saga.id, err)
+		}
+		saga.transactionID = txn.GetId()
+		if err := s.advance(ctx, saga, saga.step, sagaCompensating); err != nil {
+			return err
+		}
+	}
+
+	// Find out whether a hold was placed, if the saga stopped while authorizing the debit
+	if debitAttempted && saga.holdID == "" && saga.declineReason == "" {
+		debitResult, err := s.authorizeDebit(ctx, saga)
+		if err != nil {
+			return fmt.Errorf("failed to resolve debit for saga %s: %w", saga.id, err)
+		}
+		saga.holdID = debitResult.GetHoldId()
+		if err := s.advance(ctx, saga, stepDebitAuthorized, sagaCompensating); err != nil {
+			return err
+		}
+	}
+
+	// Credit back the authorized amount
+	if saga.holdID != "" {
+		err := withRetry(ctx, "ReleaseHold", func() error {
+			_, err := s.balanceClient.ReleaseHold(ctx, &balancepb.HoldID{HoldId: saga.holdID})
+			return err
+		})
+		// A hold that has already been released or has expired needs no further action
+		if err != nil && status.Code(err) != codes.FailedPrecondition {
+			return fmt.Errorf("failed to release hold %s for saga %s: %w", saga.holdID, saga.id, err)
+		}
+	}
+
+	// Stop counting the payment towards the card's spending limits
+	if err := s.spend.release(ctx, saga.transactionID, 0); err != nil {
+		return fmt.Errorf("failed to release spend of transaction %s for saga %s: %w", saga.transactionID, saga.id, err)
+	}
+
+	txnStatus := txnReversed
+	if saga.declineReason != "" {
+		txnStatus = txnDeclined
+	}
+	err := withRetry(ctx, "UpdateTransaction", func() error {
+		_, err := s.transactionsClient.UpdateTransaction(ctx, &transactionspb.UpdateTransactionRequest{
+			Id:     saga.transactionID,
+			Status: txnStatus,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction %s %s: %w", saga.transactionID, txnStatus, err)
+	}
+
+	return s.advance(ctx, saga, saga.step, sagaCompensated)
+}
+
+// abandon rolls back a saga that its authorization or recovery is giving up on. It runs even if ctx
+// is done, since running out of time is often why the saga is given up on, and a saga left
+// IN_PROGRESS could be resumed and approved after its caller was told it failed.
+func (s *server) abandon(ctx context.Context, saga *authSaga) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+	return s.compensate(ctx, saga)
+}
+
+// recoverSagas finishes authorizations left half-done by a crash or a failed compensation.
+// Sagas that passed every check before confirmation are resumed; all others are rolled back.
+func (s *server) recoverSagas(ctx context.Context) {
+	sagas, err := s.sagas.claimRecoverable(ctx, sagaRecoveryAge, sagaRecoveryBatch)
+	if err != nil {
+		log.Printf("failed to claim sagas for recovery: %v", err)
+		return
+	}
+
+	for _, saga := range sagas {
+		s.recoverSaga(ctx, saga)
+	}
+}
+
+// recoverSaga resumes or rolls back one claimed saga, within authorizationTimeout plus
+// compensationTimeout so that it is done before the saga could be claimed again. Only a saga still
+// IN_PROGRESS is resumed: an authorization that failed marked its saga COMPENSATING before its
+// caller got the error.
+func (s *server) recoverSaga(ctx context.Context, saga *authSaga) {
+	// A saga that stopped before its spending limits were checked or its virtual card was claimed is
+	// rolled back rather than approved unchecked
+	if saga.status == sagaInProgress && saga.step == stepCardClaimed && saga.holdID != "" {
+		confirmCtx, cancel := context.WithTimeout(ctx, authorizationTimeout)
+		err := s.confirm(confirmCtx, saga)
+		cancel()
+		if err == nil {
+			log.Printf("Resumed saga %s: transaction %s authorized", saga.id, saga.transactionID)
+			return
+		}
+		log.Printf("failed to resume saga %s, rolling back: %v", saga.id, err)
+	}
+
+	if err := s.abandon(ctx, saga); err != nil {
+		log.Printf("failed to roll back saga %s: %v", saga.id, err)
+		return
+	}
+	log.Printf("Rolled back saga %s", saga.id)
+}
+
+// runSagaRecovery recovers half-finished sagas at startup and then periodically until ctx is cancelled
+func (s *server) runSagaRecovery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.recoverSagas(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS authorization_sagas (
+    saga_id UUID PRIMARY KEY, -- also the idempotency key sent to Balance and Transactions
+    card_id TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    amount BIGINT NOT NULL, -- in cents
+    currency TEXT NOT NULL,
+    merchant_id TEXT NOT NULL DEFAULT '',
+    merchant_name TEXT NOT NULL DEFAULT '',
+    step TEXT NOT NULL CHECK (step IN ('STARTED','TRANSACTION_RECORDED','DEBIT_AUTHORIZED','SPEND_RESERVED','CARD_CLAIMED','CONFIRMED')),
+    status TEXT NOT NULL CHECK (status IN ('IN_PROGRESS','COMPLETED','COMPENSATING','COMPENSATED')),
+    transaction_id TEXT NOT NULL DEFAULT '',
+    hold_id TEXT NOT NULL DEFAULT '',
+    decline_reason TEXT NOT NULL DEFAULT '',
+    billing_currency TEXT NOT NULL DEFAULT '', -- set when the debit was converted into the account's currency
+    billing_amount BIGINT NOT NULL DEFAULT 0,
+    fx_rate TEXT NOT NULL DEFAULT '',
+    fx_fee BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Sagas that recovery may need to resume or roll back
+CREATE INDEX IF NOT EXISTS authorization_sagas_recoverable_idx ON authorization_sagas(updated_at) WHERE status IN ('IN_PROGRESS','COMPENSATING');
+
+-- Every risk engine decision, kept for review
+CREATE TABLE IF NOT EXISTS risk_decisions (
+    decision_id UUID PRIMARY KEY,
+    card_id TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    amount BIGINT NOT NULL, -- in cents
+    currency TEXT NOT NULL,
+    merchant_id TEXT NOT NULL DEFAULT '',
+    merchant_name TEXT NOT NULL DEFAULT '',
+    merchant_country TEXT NOT NULL DEFAULT '',
+    score INT NOT NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('APPROVE','STEP_UP','DECLINE')),
+    triggered_rules TEXT NOT NULL DEFAULT '', -- comma-separated names of the rules that scored
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Rules look back over an account's most recent decisions
+CREATE INDEX IF NOT EXISTS risk_decisions_account_idx ON risk_decisions(account_id, created_at DESC);
+
+-- Running totals of what each card has spent, per UTC day (D2025-03-14) and month (M2025-03) and
+-- over its lifetime (ALL), which spending limits and spend caps are checked against
+CREATE TABLE IF NOT EXISTS card_spend (
+    card_id TEXT NOT NULL,
+    period TEXT NOT NULL,
+    amount BIGINT NOT NULL, -- in cents, in the account's currency
+    PRIMARY KEY (card_id, period)
+);
+
+-- The payments that make up the totals, so a declined or reversed one can be taken off again
+CREATE TABLE IF NOT EXISTS card_spend_payments (
+    transaction_id TEXT PRIMARY KEY,
+    card_id TEXT NOT NULL,
+    spent_at TIMESTAMP NOT NULL, -- decides which day and month the payment counts towards
+    amount BIGINT NOT NULL, -- in cents, what still counts after any partial reversal
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
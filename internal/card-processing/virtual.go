@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cardprocessingpb "github.com/sambacha/monzo/v2/card_processing"
+	cardspb "github.com/sambacha/monzo/v2/cards"
+)
+
+// Kinds of virtual card, as Cards gives them in Card.virtual_type
+const (
+	virtualTypeSingleUse      = "single_use"      // USED by its first approved payment
+	virtualTypeMerchantLocked = "merchant_locked" // only pays the merchant that first used it
+)
+
+// actorCardProcessing is who Cards records as changing a card's status when Card-processing does
+const actorCardProcessing = "card-processing"
+
+// checkVirtualCard returns the decline code and reason if the rules a virtual card was created with
+// forbid a payment, or DECLINE_CODE_UNSPECIFIED if they allow it. The spend cap is checked once the
+// payment's amount is known in the account's currency, see reserveSpend.
+func checkVirtualCard(card *cardspb.Card, req *cardprocessingpb.CardAuthRequest, now time.Time) (cardprocessingpb.DeclineCode, string) {
+	if card.GetExpiresAt() != "" {
+		// Cards only stores valid times, so one that can't be read is treated as passed
+		expiresAt, err := time.Parse(time.RFC3339, card.GetExpiresAt())
+		if err != nil || !now.Before(expiresAt) {
+			return cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_EXPIRED, "virtual card expired"
+		}
+	}
+	if card.GetVirtualType() == virtualTypeMerchantLocked {
+		if req.GetMerchantId() == "" {
+			return cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, "merchant not identified"
+		}
+		if locked := card.GetLockedMerchantId(); locked != "" && locked != req.GetMerchantId() {
+			return cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, "card locked to another merchant"
+		}
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, ""
+}
+
+// claimVirtualCard claims a virtual card for a payment that has passed every other check, returning
+// the decline code and reason if another payment got to it first
+func (s *server) claimVirtualCard(ctx context.Context, card *cardspb.Card, req *cardprocessingpb.CardAuthRequest, transactionID string) (cardprocessingpb.DeclineCode, string, error) {
+	switch card.GetVirtualType() {
+	case virtualTypeMerchantLocked:
+		return s.lockToMerchant(ctx, card, req)
+	case virtualTypeSingleUse:
+		return s.useSingleUseCard(ctx, req.GetCardId(), transactionID)
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", nil
+}
+
+// lockToMerchant locks a merchant-locked card that hasn't been used yet to the merchant of req, so a
+// declined payment doesn't lock the card. Of two merchants using it at once only the first to lock
+// it is approved.
+func (s *server) lockToMerchant(ctx context.Context, card *cardspb.Card, req *cardprocessingpb.CardAuthRequest) (cardprocessingpb.DeclineCode, string, error) {
+	if card.GetLockedMerchantId() != "" {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", nil
+	}
+
+	_, err := s.cardsClient.LockCardToMerchant(ctx, &cardspb.LockCardToMerchantRequest{CardId: req.GetCardId(), MerchantId: req.GetMerchantId()})
+	if status.Code(err) == codes.FailedPrecondition {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED, "card locked to another merchant", nil
+	}
+	if err != nil {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", fmt.Errorf("failed to lock card %s to merchant %s: %w", req.GetCardId(), req.GetMerchantId(), err)
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", nil
+}
+
+// useSingleUseCard claims a single-use card for the payment of transactionID. Cards only lets one
+// payment claim the card, so of two payments made with it at once only the first is approved. A
+// payment that then fails to confirm still uses up the card.
+func (s *server) useSingleUseCard(ctx context.Context, cardID, transactionID string) (cardprocessingpb.DeclineCode, string, error) {
+	_, err := s.cardsClient.UseSingleUseCard(ctx, &cardspb.UseSingleUseCardRequest{
+		CardId:        cardID,
+		TransactionId: transactionID,
+		Actor:         actorCardProcessing,
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED, "single-use card already used", nil
+	}
+	if err != nil {
+		return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", fmt.Errorf("failed to use single-use card %s: %w", cardID, err)
+	}
+	return cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, "", nil
+}
@@ -22,13 +22,27 @@ const (
 )
 
 type Card struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                     // e.g., "ACTIVE", "INACTIVE", "FROZEN", "CLOSED"
-	LastFour      string                 `protobuf:"bytes,4,opt,name=last_four,json=lastFour,proto3" json:"last_four,omitempty"` // pan_hash and cvv_hash are not included as per spec security notes
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	CardId   string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	UserId   string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status   string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // e.g., "ACTIVE", "INACTIVE", "FROZEN", "CLOSED", or "USED" for a single-use card that has paid
+	LastFour string                 `protobuf:"bytes,4,opt,name=last_four,json=lastFour,proto3" json:"last_four,omitempty"`
+	// pan_hash and cvv_hash are not included as per spec security notes
+	CardType         string `protobuf:"bytes,5,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"`                            // "physical" or "virtual"
+	ExpiryMonth      int32  `protobuf:"varint,6,opt,name=expiry_month,json=expiryMonth,proto3" json:"expiry_month,omitempty"`                  // 1 to 12
+	ExpiryYear       int32  `protobuf:"varint,7,opt,name=expiry_year,json=expiryYear,proto3" json:"expiry_year,omitempty"`                     // four digits
+	PanToken         string `protobuf:"bytes,8,opt,name=pan_token,json=panToken,proto3" json:"pan_token,omitempty"`                            // vault token of the card number and CVV
+	ReplacesCardId   string `protobuf:"bytes,11,opt,name=replaces_card_id,json=replacesCardId,proto3" json:"replaces_card_id,omitempty"`       // card this one was issued to replace, if any
+	ActivationCode   string `protobuf:"bytes,12,opt,name=activation_code,json=activationCode,proto3" json:"activation_code,omitempty"`         // only in the response to CreateCard and ReissueCard of physical cards, which are issued INACTIVE
+	VirtualType      string `protobuf:"bytes,13,opt,name=virtual_type,json=virtualType,proto3" json:"virtual_type,omitempty"`                  // "single_use" or "merchant_locked" for those kinds of virtual card, empty otherwise
+	SpendCap         int64  `protobuf:"varint,14,opt,name=spend_cap,json=spendCap,proto3" json:"spend_cap,omitempty"`                          // total the card can ever spend, in minor units of the account's currency, 0 for no cap
+	ExpiresAt        string `protobuf:"bytes,15,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                        // RFC 3339 time from which the card declines payments, empty if it only expires with expiry_month and expiry_year
+	LockedMerchantId string `protobuf:"bytes,16,opt,name=locked_merchant_id,json=lockedMerchantId,proto3" json:"locked_merchant_id,omitempty"` // merchant a "merchant_locked" card is locked to, empty until its first payment
+	CreatedAt        string `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                        // RFC 3339
+	Nickname         string `protobuf:"bytes,18,opt,name=nickname,proto3" json:"nickname,omitempty"`                                           // name the cardholder gave the card, empty if none
+	IsDefault        bool   `protobuf:"varint,19,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`                       // the card the cardholder chose to pay with by default; a user has at most one
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Card) Reset() {
@@ -89,17 +103,224 @@ func (x *Card) GetLastFour() string {
 	return ""
 }
 
+func (x *Card) GetCardType() string {
+	if x != nil {
+		return x.CardType
+	}
+	return ""
+}
+
+func (x *Card) GetExpiryMonth() int32 {
+	if x != nil {
+		return x.ExpiryMonth
+	}
+	return 0
+}
+
+func (x *Card) GetExpiryYear() int32 {
+	if x != nil {
+		return x.ExpiryYear
+	}
+	return 0
+}
+
+func (x *Card) GetPanToken() string {
+	if x != nil {
+		return x.PanToken
+	}
+	return ""
+}
+
+func (x *Card) GetReplacesCardId() string {
+	if x != nil {
+		return x.ReplacesCardId
+	}
+	return ""
+}
+
+func (x *Card) GetActivationCode() string {
+	if x != nil {
+		return x.ActivationCode
+	}
+	return ""
+}
+
+func (x *Card) GetVirtualType() string {
+	if x != nil {
+		return x.VirtualType
+	}
+	return ""
+}
+
+func (x *Card) GetSpendCap() int64 {
+	if x != nil {
+		return x.SpendCap
+	}
+	return 0
+}
+
+func (x *Card) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Card) GetLockedMerchantId() string {
+	if x != nil {
+		return x.LockedMerchantId
+	}
+	return ""
+}
+
+func (x *Card) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Card) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *Card) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+// CardControls are the spending controls a cardholder has set on a card. Limits are in minor units
+// of the account's currency, 0 for no limit. A card without controls set has none.
+type CardControls struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	CardId              string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	DailyLimit          int64                  `protobuf:"varint,2,opt,name=daily_limit,json=dailyLimit,proto3" json:"daily_limit,omitempty"`                              // total spend per UTC day
+	MonthlyLimit        int64                  `protobuf:"varint,3,opt,name=monthly_limit,json=monthlyLimit,proto3" json:"monthly_limit,omitempty"`                        // total spend per UTC calendar month
+	PerTransactionLimit int64                  `protobuf:"varint,4,opt,name=per_transaction_limit,json=perTransactionLimit,proto3" json:"per_transaction_limit,omitempty"` // largest single payment
+	BlockedMccs         []int32                `protobuf:"varint,5,rep,packed,name=blocked_mccs,json=blockedMccs,proto3" json:"blocked_mccs,omitempty"`                    // merchant category codes to decline payments at, e.g. 7995 for gambling
+	OnlineDisabled      bool                   `protobuf:"varint,6,opt,name=online_disabled,json=onlineDisabled,proto3" json:"online_disabled,omitempty"`                  // card-not-present payments
+	ContactlessDisabled bool                   `protobuf:"varint,7,opt,name=contactless_disabled,json=contactlessDisabled,proto3" json:"contactless_disabled,omitempty"`
+	AtmDisabled         bool                   `protobuf:"varint,8,opt,name=atm_disabled,json=atmDisabled,proto3" json:"atm_disabled,omitempty"`                   // cash withdrawals
+	MagstripeDisabled   bool                   `protobuf:"varint,9,opt,name=magstripe_disabled,json=magstripeDisabled,proto3" json:"magstripe_disabled,omitempty"` // swiped payments
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CardControls) Reset() {
+	*x = CardControls{}
+	mi := &file_proto_cards_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardControls) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardControls) ProtoMessage() {}
+
+func (x *CardControls) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardControls.ProtoReflect.Descriptor instead.
+func (*CardControls) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CardControls) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardControls) GetDailyLimit() int64 {
+	if x != nil {
+		return x.DailyLimit
+	}
+	return 0
+}
+
+func (x *CardControls) GetMonthlyLimit() int64 {
+	if x != nil {
+		return x.MonthlyLimit
+	}
+	return 0
+}
+
+func (x *CardControls) GetPerTransactionLimit() int64 {
+	if x != nil {
+		return x.PerTransactionLimit
+	}
+	return 0
+}
+
+func (x *CardControls) GetBlockedMccs() []int32 {
+	if x != nil {
+		return x.BlockedMccs
+	}
+	return nil
+}
+
+func (x *CardControls) GetOnlineDisabled() bool {
+	if x != nil {
+		return x.OnlineDisabled
+	}
+	return false
+}
+
+func (x *CardControls) GetContactlessDisabled() bool {
+	if x != nil {
+		return x.ContactlessDisabled
+	}
+	return false
+}
+
+func (x *CardControls) GetAtmDisabled() bool {
+	if x != nil {
+		return x.AtmDisabled
+	}
+	return false
+}
+
+func (x *CardControls) GetMagstripeDisabled() bool {
+	if x != nil {
+		return x.MagstripeDisabled
+	}
+	return false
+}
+
 type CreateCardRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CardType      string                 `protobuf:"bytes,2,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"` // e.g., "physical", "virtual"
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserId   string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CardType string                 `protobuf:"bytes,2,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"` // e.g., "physical", "virtual"
+	// Virtual cards only: a "single_use" card closes after its first approved payment, and a
+	// "merchant_locked" one only pays the merchant that first uses it. Any virtual card can have a
+	// spend cap and expiry.
+	VirtualType   string `protobuf:"bytes,3,opt,name=virtual_type,json=virtualType,proto3" json:"virtual_type,omitempty"`
+	SpendCap      int64  `protobuf:"varint,4,opt,name=spend_cap,json=spendCap,proto3" json:"spend_cap,omitempty"`   // minor units of the account's currency, 0 for no cap
+	ExpiresAt     string `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC 3339, in the future
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateCardRequest) Reset() {
 	*x = CreateCardRequest{}
-	mi := &file_proto_cards_proto_msgTypes[1]
+	mi := &file_proto_cards_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -111,7 +332,7 @@ func (x *CreateCardRequest) String() string {
 func (*CreateCardRequest) ProtoMessage() {}
 
 func (x *CreateCardRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cards_proto_msgTypes[1]
+	mi := &file_proto_cards_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -124,7 +345,7 @@ func (x *CreateCardRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCardRequest.ProtoReflect.Descriptor instead.
 func (*CreateCardRequest) Descriptor() ([]byte, []int) {
-	return file_proto_cards_proto_rawDescGZIP(), []int{1}
+	return file_proto_cards_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateCardRequest) GetUserId() string {
@@ -141,6 +362,27 @@ func (x *CreateCardRequest) GetCardType() string {
 	return ""
 }
 
+func (x *CreateCardRequest) GetVirtualType() string {
+	if x != nil {
+		return x.VirtualType
+	}
+	return ""
+}
+
+func (x *CreateCardRequest) GetSpendCap() int64 {
+	if x != nil {
+		return x.SpendCap
+	}
+	return 0
+}
+
+func (x *CreateCardRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
 type GetCardRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
@@ -150,7 +392,7 @@ type GetCardRequest struct {
 
 func (x *GetCardRequest) Reset() {
 	*x = GetCardRequest{}
-	mi := &file_proto_cards_proto_msgTypes[2]
+	mi := &file_proto_cards_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -162,7 +404,7 @@ func (x *GetCardRequest) String() string {
 func (*GetCardRequest) ProtoMessage() {}
 
 func (x *GetCardRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cards_proto_msgTypes[2]
+	mi := &file_proto_cards_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -175,7 +417,7 @@ func (x *GetCardRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCardRequest.ProtoReflect.Descriptor instead.
 func (*GetCardRequest) Descriptor() ([]byte, []int) {
-	return file_proto_cards_proto_rawDescGZIP(), []int{2}
+	return file_proto_cards_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetCardRequest) GetCardId() string {
@@ -185,29 +427,28 @@ func (x *GetCardRequest) GetCardId() string {
 	return ""
 }
 
-type UpdateCardStatusRequest struct {
+type GetCardByPanTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
-	NewStatus     string                 `protobuf:"bytes,2,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"` // e.g., "ACTIVE", "FROZEN"
+	PanToken      string                 `protobuf:"bytes,1,opt,name=pan_token,json=panToken,proto3" json:"pan_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateCardStatusRequest) Reset() {
-	*x = UpdateCardStatusRequest{}
-	mi := &file_proto_cards_proto_msgTypes[3]
+func (x *GetCardByPanTokenRequest) Reset() {
+	*x = GetCardByPanTokenRequest{}
+	mi := &file_proto_cards_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateCardStatusRequest) String() string {
+func (x *GetCardByPanTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateCardStatusRequest) ProtoMessage() {}
+func (*GetCardByPanTokenRequest) ProtoMessage() {}
 
-func (x *UpdateCardStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cards_proto_msgTypes[3]
+func (x *GetCardByPanTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -218,49 +459,1291 @@ func (x *UpdateCardStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateCardStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateCardStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_cards_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use GetCardByPanTokenRequest.ProtoReflect.Descriptor instead.
+func (*GetCardByPanTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *UpdateCardStatusRequest) GetCardId() string {
+func (x *GetCardByPanTokenRequest) GetPanToken() string {
 	if x != nil {
-		return x.CardId
+		return x.PanToken
 	}
 	return ""
 }
 
-func (x *UpdateCardStatusRequest) GetNewStatus() string {
+type ListCardsByUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Statuses      []string               `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty"`                 // only cards with one of these statuses, e.g. "ACTIVE"; all cards if empty
+	Limit         uint32                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                      // pagination limit, 0 for the default of 50; at most 100
+	BeforeId      string                 `protobuf:"bytes,4,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"` // pagination cursor (card ID), the next_before_id of the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCardsByUserRequest) Reset() {
+	*x = ListCardsByUserRequest{}
+	mi := &file_proto_cards_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCardsByUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCardsByUserRequest) ProtoMessage() {}
+
+func (x *ListCardsByUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[5]
 	if x != nil {
-		return x.NewStatus
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCardsByUserRequest.ProtoReflect.Descriptor instead.
+func (*ListCardsByUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListCardsByUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-var File_proto_cards_proto protoreflect.FileDescriptor
+func (x *ListCardsByUserRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
 
-const file_proto_cards_proto_rawDesc = "" +
-	"\n" +
-	"\x11proto/cards.proto\"m\n" +
-	"\x04Card\x12\x17\n" +
-	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1b\n" +
-	"\tlast_four\x18\x04 \x01(\tR\blastFour\"I\n" +
-	"\x11CreateCardRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tcard_type\x18\x02 \x01(\tR\bcardType\")\n" +
-	"\x0eGetCardRequest\x12\x17\n" +
-	"\acard_id\x18\x01 \x01(\tR\x06cardId\"Q\n" +
-	"\x17UpdateCardStatusRequest\x12\x17\n" +
-	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1d\n" +
+func (x *ListCardsByUserRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCardsByUserRequest) GetBeforeId() string {
+	if x != nil {
+		return x.BeforeId
+	}
+	return ""
+}
+
+type CardList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cards         []*Card                `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`                                     // newest first
+	NextBeforeId  string                 `protobuf:"bytes,2,opt,name=next_before_id,json=nextBeforeId,proto3" json:"next_before_id,omitempty"` // before_id of the next page, empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardList) Reset() {
+	*x = CardList{}
+	mi := &file_proto_cards_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardList) ProtoMessage() {}
+
+func (x *CardList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardList.ProtoReflect.Descriptor instead.
+func (*CardList) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CardList) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+func (x *CardList) GetNextBeforeId() string {
+	if x != nil {
+		return x.NextBeforeId
+	}
+	return ""
+}
+
+type ListVirtualCardsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVirtualCardsRequest) Reset() {
+	*x = ListVirtualCardsRequest{}
+	mi := &file_proto_cards_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVirtualCardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVirtualCardsRequest) ProtoMessage() {}
+
+func (x *ListVirtualCardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVirtualCardsRequest.ProtoReflect.Descriptor instead.
+func (*ListVirtualCardsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListVirtualCardsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type VirtualCards struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cards         []*Card                `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VirtualCards) Reset() {
+	*x = VirtualCards{}
+	mi := &file_proto_cards_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VirtualCards) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtualCards) ProtoMessage() {}
+
+func (x *VirtualCards) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtualCards.ProtoReflect.Descriptor instead.
+func (*VirtualCards) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VirtualCards) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+type LockCardToMerchantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // a "merchant_locked" card
+	MerchantId    string                 `protobuf:"bytes,2,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockCardToMerchantRequest) Reset() {
+	*x = LockCardToMerchantRequest{}
+	mi := &file_proto_cards_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockCardToMerchantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockCardToMerchantRequest) ProtoMessage() {}
+
+func (x *LockCardToMerchantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockCardToMerchantRequest.ProtoReflect.Descriptor instead.
+func (*LockCardToMerchantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LockCardToMerchantRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *LockCardToMerchantRequest) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+type UseSingleUseCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                      // a "single_use" card
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // the payment it is used for, recorded in its status history
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UseSingleUseCardRequest) Reset() {
+	*x = UseSingleUseCardRequest{}
+	mi := &file_proto_cards_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UseSingleUseCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UseSingleUseCardRequest) ProtoMessage() {}
+
+func (x *UseSingleUseCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UseSingleUseCardRequest.ProtoReflect.Descriptor instead.
+func (*UseSingleUseCardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UseSingleUseCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *UseSingleUseCardRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *UseSingleUseCardRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+type SetCardNicknameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"` // at most 40 characters, empty to remove the nickname
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCardNicknameRequest) Reset() {
+	*x = SetCardNicknameRequest{}
+	mi := &file_proto_cards_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCardNicknameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCardNicknameRequest) ProtoMessage() {}
+
+func (x *SetCardNicknameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCardNicknameRequest.ProtoReflect.Descriptor instead.
+func (*SetCardNicknameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetCardNicknameRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *SetCardNicknameRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+// SetDefaultCardRequest makes a card its user's default card, in place of any other
+type SetDefaultCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDefaultCardRequest) Reset() {
+	*x = SetDefaultCardRequest{}
+	mi := &file_proto_cards_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultCardRequest) ProtoMessage() {}
+
+func (x *SetDefaultCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultCardRequest.ProtoReflect.Descriptor instead.
+func (*SetDefaultCardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetDefaultCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type ReissueCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // card to replace
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`               // "LOST", "STOLEN", "DAMAGED" or "EXPIRING"
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`                 // who asked for the card to be replaced, recorded in its status history
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReissueCardRequest) Reset() {
+	*x = ReissueCardRequest{}
+	mi := &file_proto_cards_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReissueCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReissueCardRequest) ProtoMessage() {}
+
+func (x *ReissueCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReissueCardRequest.ProtoReflect.Descriptor instead.
+func (*ReissueCardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReissueCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *ReissueCardRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReissueCardRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+// RecurringMerchant is a merchant holding a card's details to take recurring payments with
+type RecurringMerchant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	MerchantId    string                 `protobuf:"bytes,2,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	MerchantName  string                 `protobuf:"bytes,3,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecurringMerchant) Reset() {
+	*x = RecurringMerchant{}
+	mi := &file_proto_cards_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecurringMerchant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecurringMerchant) ProtoMessage() {}
+
+func (x *RecurringMerchant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecurringMerchant.ProtoReflect.Descriptor instead.
+func (*RecurringMerchant) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RecurringMerchant) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *RecurringMerchant) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *RecurringMerchant) GetMerchantName() string {
+	if x != nil {
+		return x.MerchantName
+	}
+	return ""
+}
+
+type RecurringMerchants struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Merchants     []*RecurringMerchant   `protobuf:"bytes,1,rep,name=merchants,proto3" json:"merchants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecurringMerchants) Reset() {
+	*x = RecurringMerchants{}
+	mi := &file_proto_cards_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecurringMerchants) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecurringMerchants) ProtoMessage() {}
+
+func (x *RecurringMerchants) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecurringMerchants.ProtoReflect.Descriptor instead.
+func (*RecurringMerchants) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RecurringMerchants) GetMerchants() []*RecurringMerchant {
+	if x != nil {
+		return x.Merchants
+	}
+	return nil
+}
+
+// PIN blocks are ISO 9564 format 1, see pkg/pinblock. They are stored in the vault and never logged.
+type SetPinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	PinBlock      string                 `protobuf:"bytes,2,opt,name=pin_block,json=pinBlock,proto3" json:"pin_block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPinRequest) Reset() {
+	*x = SetPinRequest{}
+	mi := &file_proto_cards_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPinRequest) ProtoMessage() {}
+
+func (x *SetPinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPinRequest.ProtoReflect.Descriptor instead.
+func (*SetPinRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetPinRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *SetPinRequest) GetPinBlock() string {
+	if x != nil {
+		return x.PinBlock
+	}
+	return ""
+}
+
+type ChangePinRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CardId          string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	CurrentPinBlock string                 `protobuf:"bytes,2,opt,name=current_pin_block,json=currentPinBlock,proto3" json:"current_pin_block,omitempty"` // a wrong PIN counts towards locking the PIN
+	NewPinBlock     string                 `protobuf:"bytes,3,opt,name=new_pin_block,json=newPinBlock,proto3" json:"new_pin_block,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePinRequest) Reset() {
+	*x = ChangePinRequest{}
+	mi := &file_proto_cards_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePinRequest) ProtoMessage() {}
+
+func (x *ChangePinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePinRequest.ProtoReflect.Descriptor instead.
+func (*ChangePinRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChangePinRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *ChangePinRequest) GetCurrentPinBlock() string {
+	if x != nil {
+		return x.CurrentPinBlock
+	}
+	return ""
+}
+
+func (x *ChangePinRequest) GetNewPinBlock() string {
+	if x != nil {
+		return x.NewPinBlock
+	}
+	return ""
+}
+
+type RevealPinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	ScaToken      string                 `protobuf:"bytes,2,opt,name=sca_token,json=scaToken,proto3" json:"sca_token,omitempty"` // strong customer authentication token for the action "reveal_pin:<card_id>", see pkg/sca
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevealPinRequest) Reset() {
+	*x = RevealPinRequest{}
+	mi := &file_proto_cards_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevealPinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealPinRequest) ProtoMessage() {}
+
+func (x *RevealPinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealPinRequest.ProtoReflect.Descriptor instead.
+func (*RevealPinRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RevealPinRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *RevealPinRequest) GetScaToken() string {
+	if x != nil {
+		return x.ScaToken
+	}
+	return ""
+}
+
+type RevealPinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pin           string                 `protobuf:"bytes,1,opt,name=pin,proto3" json:"pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevealPinResponse) Reset() {
+	*x = RevealPinResponse{}
+	mi := &file_proto_cards_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevealPinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealPinResponse) ProtoMessage() {}
+
+func (x *RevealPinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealPinResponse.ProtoReflect.Descriptor instead.
+func (*RevealPinResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RevealPinResponse) GetPin() string {
+	if x != nil {
+		return x.Pin
+	}
+	return ""
+}
+
+type VerifyPinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	PinBlock      string                 `protobuf:"bytes,2,opt,name=pin_block,json=pinBlock,proto3" json:"pin_block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPinRequest) Reset() {
+	*x = VerifyPinRequest{}
+	mi := &file_proto_cards_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPinRequest) ProtoMessage() {}
+
+func (x *VerifyPinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPinRequest.ProtoReflect.Descriptor instead.
+func (*VerifyPinRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *VerifyPinRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *VerifyPinRequest) GetPinBlock() string {
+	if x != nil {
+		return x.PinBlock
+	}
+	return ""
+}
+
+// PinStatus is the state of a card's PIN after it was set or checked. Three wrong PINs in a row
+// lock it until the cardholder reveals it.
+type PinStatus struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CardId            string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	Verified          bool                   `protobuf:"varint,2,opt,name=verified,proto3" json:"verified,omitempty"` // whether the PIN checked was right
+	Locked            bool                   `protobuf:"varint,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	AttemptsRemaining int32                  `protobuf:"varint,4,opt,name=attempts_remaining,json=attemptsRemaining,proto3" json:"attempts_remaining,omitempty"` // wrong PINs left before the PIN locks
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PinStatus) Reset() {
+	*x = PinStatus{}
+	mi := &file_proto_cards_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinStatus) ProtoMessage() {}
+
+func (x *PinStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinStatus.ProtoReflect.Descriptor instead.
+func (*PinStatus) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PinStatus) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *PinStatus) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *PinStatus) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *PinStatus) GetAttemptsRemaining() int32 {
+	if x != nil {
+		return x.AttemptsRemaining
+	}
+	return 0
+}
+
+// Cards move between statuses as follows; CLOSED is final.
+//
+//	INACTIVE -> ACTIVE (with the card's activation code), CLOSED
+//	ACTIVE   -> FROZEN, CLOSED
+//	FROZEN   -> ACTIVE, CLOSED
+type UpdateCardStatusRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CardId         string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	NewStatus      string                 `protobuf:"bytes,2,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`                // e.g., "ACTIVE", "FROZEN"
+	Reason         string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                                       // why the status is changing, recorded in the card's status history
+	Actor          string                 `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`                                         // who is changing it, e.g. the cardholder's user ID or "support"
+	ActivationCode string                 `protobuf:"bytes,5,opt,name=activation_code,json=activationCode,proto3" json:"activation_code,omitempty"` // required to activate an INACTIVE card
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateCardStatusRequest) Reset() {
+	*x = UpdateCardStatusRequest{}
+	mi := &file_proto_cards_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCardStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCardStatusRequest) ProtoMessage() {}
+
+func (x *UpdateCardStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCardStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCardStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateCardStatusRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *UpdateCardStatusRequest) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *UpdateCardStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *UpdateCardStatusRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *UpdateCardStatusRequest) GetActivationCode() string {
+	if x != nil {
+		return x.ActivationCode
+	}
+	return ""
+}
+
+// CardStatusChange is one change of a card's status
+type CardStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	FromStatus    string                 `protobuf:"bytes,2,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"` // empty when the card was issued
+	ToStatus      string                 `protobuf:"bytes,3,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Actor         string                 `protobuf:"bytes,5,opt,name=actor,proto3" json:"actor,omitempty"`
+	ChangedAt     string                 `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardStatusChange) Reset() {
+	*x = CardStatusChange{}
+	mi := &file_proto_cards_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardStatusChange) ProtoMessage() {}
+
+func (x *CardStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardStatusChange.ProtoReflect.Descriptor instead.
+func (*CardStatusChange) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CardStatusChange) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardStatusChange) GetFromStatus() string {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ""
+}
+
+func (x *CardStatusChange) GetToStatus() string {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ""
+}
+
+func (x *CardStatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CardStatusChange) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *CardStatusChange) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+type CardHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*CardStatusChange    `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardHistory) Reset() {
+	*x = CardHistory{}
+	mi := &file_proto_cards_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardHistory) ProtoMessage() {}
+
+func (x *CardHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cards_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardHistory.ProtoReflect.Descriptor instead.
+func (*CardHistory) Descriptor() ([]byte, []int) {
+	return file_proto_cards_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CardHistory) GetChanges() []*CardStatusChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+var File_proto_cards_proto protoreflect.FileDescriptor
+
+const file_proto_cards_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/cards.proto\"\xbb\x04\n" +
+	"\x04Card\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1b\n" +
+	"\tlast_four\x18\x04 \x01(\tR\blastFour\x12\x1b\n" +
+	"\tcard_type\x18\x05 \x01(\tR\bcardType\x12!\n" +
+	"\fexpiry_month\x18\x06 \x01(\x05R\vexpiryMonth\x12\x1f\n" +
+	"\vexpiry_year\x18\a \x01(\x05R\n" +
+	"expiryYear\x12\x1b\n" +
+	"\tpan_token\x18\b \x01(\tR\bpanToken\x12(\n" +
+	"\x10replaces_card_id\x18\v \x01(\tR\x0ereplacesCardId\x12'\n" +
+	"\x0factivation_code\x18\f \x01(\tR\x0eactivationCode\x12!\n" +
+	"\fvirtual_type\x18\r \x01(\tR\vvirtualType\x12\x1b\n" +
+	"\tspend_cap\x18\x0e \x01(\x03R\bspendCap\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x0f \x01(\tR\texpiresAt\x12,\n" +
+	"\x12locked_merchant_id\x18\x10 \x01(\tR\x10lockedMerchantId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x11 \x01(\tR\tcreatedAt\x12\x1a\n" +
+	"\bnickname\x18\x12 \x01(\tR\bnickname\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\x13 \x01(\bR\tisDefaultJ\x04\b\t\x10\n" +
+	"J\x04\b\n" +
+	"\x10\vR\x03panR\x03cvv\"\xf2\x02\n" +
+	"\fCardControls\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1f\n" +
+	"\vdaily_limit\x18\x02 \x01(\x03R\n" +
+	"dailyLimit\x12#\n" +
+	"\rmonthly_limit\x18\x03 \x01(\x03R\fmonthlyLimit\x122\n" +
+	"\x15per_transaction_limit\x18\x04 \x01(\x03R\x13perTransactionLimit\x12!\n" +
+	"\fblocked_mccs\x18\x05 \x03(\x05R\vblockedMccs\x12'\n" +
+	"\x0fonline_disabled\x18\x06 \x01(\bR\x0eonlineDisabled\x121\n" +
+	"\x14contactless_disabled\x18\a \x01(\bR\x13contactlessDisabled\x12!\n" +
+	"\fatm_disabled\x18\b \x01(\bR\vatmDisabled\x12-\n" +
+	"\x12magstripe_disabled\x18\t \x01(\bR\x11magstripeDisabled\"\xa8\x01\n" +
+	"\x11CreateCardRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tcard_type\x18\x02 \x01(\tR\bcardType\x12!\n" +
+	"\fvirtual_type\x18\x03 \x01(\tR\vvirtualType\x12\x1b\n" +
+	"\tspend_cap\x18\x04 \x01(\x03R\bspendCap\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\")\n" +
+	"\x0eGetCardRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\"7\n" +
+	"\x18GetCardByPanTokenRequest\x12\x1b\n" +
+	"\tpan_token\x18\x01 \x01(\tR\bpanToken\"\x80\x01\n" +
+	"\x16ListCardsByUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bstatuses\x18\x02 \x03(\tR\bstatuses\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\x12\x1b\n" +
+	"\tbefore_id\x18\x04 \x01(\tR\bbeforeId\"M\n" +
+	"\bCardList\x12\x1b\n" +
+	"\x05cards\x18\x01 \x03(\v2\x05.CardR\x05cards\x12$\n" +
+	"\x0enext_before_id\x18\x02 \x01(\tR\fnextBeforeId\"2\n" +
+	"\x17ListVirtualCardsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"+\n" +
+	"\fVirtualCards\x12\x1b\n" +
+	"\x05cards\x18\x01 \x03(\v2\x05.CardR\x05cards\"U\n" +
+	"\x19LockCardToMerchantRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1f\n" +
+	"\vmerchant_id\x18\x02 \x01(\tR\n" +
+	"merchantId\"o\n" +
+	"\x17UseSingleUseCardRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\"M\n" +
+	"\x16SetCardNicknameRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\"0\n" +
+	"\x15SetDefaultCardRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\"[\n" +
+	"\x12ReissueCardRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\"r\n" +
+	"\x11RecurringMerchant\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1f\n" +
+	"\vmerchant_id\x18\x02 \x01(\tR\n" +
+	"merchantId\x12#\n" +
+	"\rmerchant_name\x18\x03 \x01(\tR\fmerchantName\"F\n" +
+	"\x12RecurringMerchants\x120\n" +
+	"\tmerchants\x18\x01 \x03(\v2\x12.RecurringMerchantR\tmerchants\"E\n" +
+	"\rSetPinRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tpin_block\x18\x02 \x01(\tR\bpinBlock\"{\n" +
+	"\x10ChangePinRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12*\n" +
+	"\x11current_pin_block\x18\x02 \x01(\tR\x0fcurrentPinBlock\x12\"\n" +
+	"\rnew_pin_block\x18\x03 \x01(\tR\vnewPinBlock\"H\n" +
+	"\x10RevealPinRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tsca_token\x18\x02 \x01(\tR\bscaToken\"%\n" +
+	"\x11RevealPinResponse\x12\x10\n" +
+	"\x03pin\x18\x01 \x01(\tR\x03pin\"H\n" +
+	"\x10VerifyPinRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tpin_block\x18\x02 \x01(\tR\bpinBlock\"\x87\x01\n" +
+	"\tPinStatus\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1a\n" +
+	"\bverified\x18\x02 \x01(\bR\bverified\x12\x16\n" +
+	"\x06locked\x18\x03 \x01(\bR\x06locked\x12-\n" +
+	"\x12attempts_remaining\x18\x04 \x01(\x05R\x11attemptsRemaining\"\xa8\x01\n" +
+	"\x17UpdateCardStatusRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\x02 \x01(\tR\tnewStatus\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\x12'\n" +
+	"\x0factivation_code\x18\x05 \x01(\tR\x0eactivationCode\"\xb6\x01\n" +
+	"\x10CardStatusChange\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1f\n" +
+	"\vfrom_status\x18\x02 \x01(\tR\n" +
+	"fromStatus\x12\x1b\n" +
+	"\tto_status\x18\x03 \x01(\tR\btoStatus\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05actor\x18\x05 \x01(\tR\x05actor\x12\x1d\n" +
 	"\n" +
-	"new_status\x18\x02 \x01(\tR\tnewStatus2\x88\x01\n" +
+	"changed_at\x18\x06 \x01(\tR\tchangedAt\":\n" +
+	"\vCardHistory\x12+\n" +
+	"\achanges\x18\x01 \x03(\v2\x11.CardStatusChangeR\achanges2\xfa\a\n" +
 	"\x05Cards\x12'\n" +
 	"\n" +
 	"CreateCard\x12\x12.CreateCardRequest\x1a\x05.Card\x12!\n" +
-	"\aGetCard\x12\x0f.GetCardRequest\x1a\x05.Card\x123\n" +
-	"\x10UpdateCardStatus\x12\x18.UpdateCardStatusRequest\x1a\x05.CardB\tZ\a./cardsb\x06proto3"
+	"\aGetCard\x12\x0f.GetCardRequest\x1a\x05.Card\x125\n" +
+	"\x11GetCardByPanToken\x12\x19.GetCardByPanTokenRequest\x1a\x05.Card\x125\n" +
+	"\x0fListCardsByUser\x12\x17.ListCardsByUserRequest\x1a\t.CardList\x123\n" +
+	"\x10UpdateCardStatus\x12\x18.UpdateCardStatusRequest\x1a\x05.Card\x12/\n" +
+	"\x0eGetCardHistory\x12\x0f.GetCardRequest\x1a\f.CardHistory\x12;\n" +
+	"\x10ListVirtualCards\x12\x18.ListVirtualCardsRequest\x1a\r.VirtualCards\x127\n" +
+	"\x12LockCardToMerchant\x12\x1a.LockCardToMerchantRequest\x1a\x05.Card\x123\n" +
+	"\x10UseSingleUseCard\x12\x18.UseSingleUseCardRequest\x1a\x05.Card\x121\n" +
+	"\x0fSetCardNickname\x12\x17.SetCardNicknameRequest\x1a\x05.Card\x12/\n" +
+	"\x0eSetDefaultCard\x12\x16.SetDefaultCardRequest\x1a\x05.Card\x121\n" +
+	"\x0fGetCardControls\x12\x0f.GetCardRequest\x1a\r.CardControls\x122\n" +
+	"\x12UpdateCardControls\x12\r.CardControls\x1a\r.CardControls\x12)\n" +
+	"\vReissueCard\x12\x13.ReissueCardRequest\x1a\x05.Card\x12>\n" +
+	"\x14AddRecurringMerchant\x12\x12.RecurringMerchant\x1a\x12.RecurringMerchant\x12>\n" +
+	"\x16ListRecurringMerchants\x12\x0f.GetCardRequest\x1a\x13.RecurringMerchants\x12$\n" +
+	"\x06SetPin\x12\x0e.SetPinRequest\x1a\n" +
+	".PinStatus\x12*\n" +
+	"\tChangePin\x12\x11.ChangePinRequest\x1a\n" +
+	".PinStatus\x122\n" +
+	"\tRevealPin\x12\x11.RevealPinRequest\x1a\x12.RevealPinResponse\x12*\n" +
+	"\tVerifyPin\x12\x11.VerifyPinRequest\x1a\n" +
+	".PinStatusB\tZ\a./cardsb\x06proto3"
 
 var (
 	file_proto_cards_proto_rawDescOnce sync.Once
@@ -274,25 +1757,84 @@ func file_proto_cards_proto_rawDescGZIP() []byte {
 	return file_proto_cards_proto_rawDescData
 }
 
-var file_proto_cards_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_cards_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_proto_cards_proto_goTypes = []any{
-	(*Card)(nil),                    // 0: Card
-	(*CreateCardRequest)(nil),       // 1: CreateCardRequest
-	(*GetCardRequest)(nil),          // 2: GetCardRequest
-	(*UpdateCardStatusRequest)(nil), // 3: UpdateCardStatusRequest
+	(*Card)(nil),                      // 0: Card
+	(*CardControls)(nil),              // 1: CardControls
+	(*CreateCardRequest)(nil),         // 2: CreateCardRequest
+	(*GetCardRequest)(nil),            // 3: GetCardRequest
+	(*GetCardByPanTokenRequest)(nil),  // 4: GetCardByPanTokenRequest
+	(*ListCardsByUserRequest)(nil),    // 5: ListCardsByUserRequest
+	(*CardList)(nil),                  // 6: CardList
+	(*ListVirtualCardsRequest)(nil),   // 7: ListVirtualCardsRequest
+	(*VirtualCards)(nil),              // 8: VirtualCards
+	(*LockCardToMerchantRequest)(nil), // 9: LockCardToMerchantRequest
+	(*UseSingleUseCardRequest)(nil),   // 10: UseSingleUseCardRequest
+	(*SetCardNicknameRequest)(nil),    // 11: SetCardNicknameRequest
+	(*SetDefaultCardRequest)(nil),     // 12: SetDefaultCardRequest
+	(*ReissueCardRequest)(nil),        // 13: ReissueCardRequest
+	(*RecurringMerchant)(nil),         // 14: RecurringMerchant
+	(*RecurringMerchants)(nil),        // 15: RecurringMerchants
+	(*SetPinRequest)(nil),             // 16: SetPinRequest
+	(*ChangePinRequest)(nil),          // 17: ChangePinRequest
+	(*RevealPinRequest)(nil),          // 18: RevealPinRequest
+	(*RevealPinResponse)(nil),         // 19: RevealPinResponse
+	(*VerifyPinRequest)(nil),          // 20: VerifyPinRequest
+	(*PinStatus)(nil),                 // 21: PinStatus
+	(*UpdateCardStatusRequest)(nil),   // 22: UpdateCardStatusRequest
+	(*CardStatusChange)(nil),          // 23: CardStatusChange
+	(*CardHistory)(nil),               // 24: CardHistory
 }
 var file_proto_cards_proto_depIdxs = []int32{
-	1, // 0: Cards.CreateCard:input_type -> CreateCardRequest
-	2, // 1: Cards.GetCard:input_type -> GetCardRequest
-	3, // 2: Cards.UpdateCardStatus:input_type -> UpdateCardStatusRequest
-	0, // 3: Cards.CreateCard:output_type -> Card
-	0, // 4: Cards.GetCard:output_type -> Card
-	0, // 5: Cards.UpdateCardStatus:output_type -> Card
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: CardList.cards:type_name -> Card
+	0,  // 1: VirtualCards.cards:type_name -> Card
+	14, // 2: RecurringMerchants.merchants:type_name -> RecurringMerchant
+	23, // 3: CardHistory.changes:type_name -> CardStatusChange
+	2,  // 4: Cards.CreateCard:input_type -> CreateCardRequest
+	3,  // 5: Cards.GetCard:input_type -> GetCardRequest
+	4,  // 6: Cards.GetCardByPanToken:input_type -> GetCardByPanTokenRequest
+	5,  // 7: Cards.ListCardsByUser:input_type -> ListCardsByUserRequest
+	22, // 8: Cards.UpdateCardStatus:input_type -> UpdateCardStatusRequest
+	3,  // 9: Cards.GetCardHistory:input_type -> GetCardRequest
+	7,  // 10: Cards.ListVirtualCards:input_type -> ListVirtualCardsRequest
+	9,  // 11: Cards.LockCardToMerchant:input_type -> LockCardToMerchantRequest
+	10, // 12: Cards.UseSingleUseCard:input_type -> UseSingleUseCardRequest
+	11, // 13: Cards.SetCardNickname:input_type -> SetCardNicknameRequest
+	12, // 14: Cards.SetDefaultCard:input_type -> SetDefaultCardRequest
+	3,  // 15: Cards.GetCardControls:input_type -> GetCardRequest
+	1,  // 16: Cards.UpdateCardControls:input_type -> CardControls
+	13, // 17: Cards.ReissueCard:input_type -> ReissueCardRequest
+	14, // 18: Cards.AddRecurringMerchant:input_type -> RecurringMerchant
+	3,  // 19: Cards.ListRecurringMerchants:input_type -> GetCardRequest
+	16, // 20: Cards.SetPin:input_type -> SetPinRequest
+	17, // 21: Cards.ChangePin:input_type -> ChangePinRequest
+	18, // 22: Cards.RevealPin:input_type -> RevealPinRequest
+	20, // 23: Cards.VerifyPin:input_type -> VerifyPinRequest
+	0,  // 24: Cards.CreateCard:output_type -> Card
+	0,  // 25: Cards.GetCard:output_type -> Card
+	0,  // 26: Cards.GetCardByPanToken:output_type -> Card
+	6,  // 27: Cards.ListCardsByUser:output_type -> CardList
+	0,  // 28: Cards.UpdateCardStatus:output_type -> Card
+	24, // 29: Cards.GetCardHistory:output_type -> CardHistory
+	8,  // 30: Cards.ListVirtualCards:output_type -> VirtualCards
+	0,  // 31: Cards.LockCardToMerchant:output_type -> Card
+	0,  // 32: Cards.UseSingleUseCard:output_type -> Card
+	0,  // 33: Cards.SetCardNickname:output_type -> Card
+	0,  // 34: Cards.SetDefaultCard:output_type -> Card
+	1,  // 35: Cards.GetCardControls:output_type -> CardControls
+	1,  // 36: Cards.UpdateCardControls:output_type -> CardControls
+	0,  // 37: Cards.ReissueCard:output_type -> Card
+	14, // 38: Cards.AddRecurringMerchant:output_type -> RecurringMerchant
+	15, // 39: Cards.ListRecurringMerchants:output_type -> RecurringMerchants
+	21, // 40: Cards.SetPin:output_type -> PinStatus
+	21, // 41: Cards.ChangePin:output_type -> PinStatus
+	19, // 42: Cards.RevealPin:output_type -> RevealPinResponse
+	21, // 43: Cards.VerifyPin:output_type -> PinStatus
+	24, // [24:44] is the sub-list for method output_type
+	4,  // [4:24] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_cards_proto_init() }
@@ -306,7 +1848,7 @@ func file_proto_cards_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_cards_proto_rawDesc), len(file_proto_cards_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
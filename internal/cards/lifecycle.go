@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cardspb "github.com/sambacha/monzo/v2/cards/cards"
+)
+
+// actorCards is the actor recorded for status changes Cards makes itself, such as issuing a card
+const actorCards = "cards"
+
+// cardTransitions are the statuses a card of each status can move to. CLOSED is final, and an
+// INACTIVE card is only activated with its activation code, see UpdateCardStatus. Single-use cards
+// only become USED through UseSingleUseCard.
+var cardTransitions = map[string]map[string]bool{
+	"INACTIVE": {"ACTIVE": true, "CLOSED": true},
+	"ACTIVE":   {"FROZEN": true, "CLOSED": true},
+	"FROZEN":   {"ACTIVE": true, "CLOSED": true},
+	"USED":     {"CLOSED": true},
+	"CLOSED":   {},
+}
+
+// checkTransition returns a FailedPrecondition error if a card can't move from one status to another
+func checkTransition(from, to string) error {
+	if !cardTransitions[from][to] {
+		if from == "CLOSED" {
+			return status.Errorf(codes.FailedPrecondition, "card is closed")
+		}
+		return status.Errorf(codes.FailedPrecondition, "card can't move from %s to %s", from, to)
+	}
+	return nil
+}
+
+// maxActivationAttempts is how many wrong activation codes lock a card's activation. A card whose
+// activation is locked can only be replaced.
+const maxActivationAttempts = 3
+
+// hashActivationCode returns the hash of an activation code Cards stores in its place, an
+// HMAC-SHA256 under the activation key: there are only a million codes, so a plain hash would give
+// the codes away to anyone who read the database.
+func (s *server) hashActivationCode(code string) string {
+	mac := hmac.New(sha256.New, s.activationKey)
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// activationCodeMatches reports whether code is the one hashed as hash. Cards issued before
+// activation codes have none, and can't be activated.
+func (s *server) activationCodeMatches(hash, code string) bool {
+	if hash == "" || code == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(s.hashActivationCode(code))) == 1
+}
+
+// recordStatusChange adds a change of a card's status to its history in tx. from is empty when the
+// card is issued.
+func recordStatusChange(ctx context.Context, tx *sql.Tx, cardID, from, to, reason, actor string) error {
+	query := `INSERT INTO card_status_history (card_id, from_status, to_status, reason, actor, changed_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW())`
+	_, err := tx.ExecContext(ctx, query, cardID, sql.NullString{String: from, Valid: from != ""}, to, reason, actor)
+	return err
+}
+
+func (s *server) GetCardHistory(ctx context.Context, req *cardspb.GetCardRequest) (*cardspb.CardHistory, error) {
+	log.Printf("Received GetCardHistory request: %+v", req)
+
+	// Cards issued before their history was recorded have none, so tell them apart from unknown cards
+	if _, err := s.getCard(ctx, "card_id", req.GetCardId()); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT COALESCE(from_status, ''), to_status, reason, actor, changed_at FROM card_status_history
+			  WHERE card_id = $1 ORDER BY id`
+	rows, err := s.db.QueryContext(ctx, query, req.GetCardId())
+	if err != nil {
+		log.Printf("failed to get card history: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get card history")
+	}
+	defer rows.Close()
+
+	history := &cardspb.CardHistory{}
+	for rows.Next() {
+		change := cardspb.CardStatusChange{CardId: req.GetCardId()}
+		var changedAt time.Time
+		if err := rows.Scan(&change.FromStatus, &change.ToStatus, &change.Reason, &change.Actor, &changedAt); err != nil {
+			log.Printf("failed to scan card status change: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to get card history")
+		}
+		change.ChangedAt = changedAt.UTC().Format(time.RFC3339)
+		history.Changes = append(history.Changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to get card history: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get card history")
+	}
+
+	return history, nil
+}
+
+func (s *server) getCardHistoryHandler(c echo.Context) error {
+	req := &cardspb.GetCardRequest{CardId: c.Param("id")}
+
+	history, err := s.GetCardHistory(c.Request().Context(), req)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": status.Convert(err).Message()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
@@ -3,30 +3,69 @@ package main
 import (
 	"context" // Import context
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"net/http" // Import http
 	"os"       // Import the os package
+	"time"
 
 	"github.com/go-redis/redis/v8" // Import redis
 	"github.com/google/uuid"       // Import uuid
 	"github.com/labstack/echo/v4"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"  // Import codes
+	"google.golang.org/grpc/codes" // Import codes
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status" // Import status
 
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	"github.com/manifoldfinance/disco2/v2/pkg/mtls"
+	"github.com/manifoldfinance/disco2/v2/pkg/outbox"
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+
 	// Import generated protobuf code
 	cardspb "github.com/sambacha/monzo/v2/cards/cards"
 )
 
 type server struct {
 	cardspb.UnimplementedCardsServer
-	db          *sql.DB
-	redisClient *redis.Client // Add Redis client
+	db             *sql.DB
+	vaultClient    vaultpb.VaultClient
+	accountsClient accountspb.AccountsClient // carries a card's account link over to its replacement
+	issuer         *issuer
+	scaKey         []byte // key strong customer authentication tokens are signed with
+	activationKey  []byte // key activation codes are hashed with
+	newCardID      func() string
+	now            func() time.Time
 }
 
 func main() {
+	issuingConfigPath := flag.String("issuing-config", "cards/issuing.json", "JSON file with the BIN ranges cards are issued from")
+	scaKeyPath := flag.String("sca-key", "", "file with the secret key strong customer authentication tokens are signed with")
+	activationKeyPath := flag.String("activation-key", "", "file with the secret key activation codes are hashed with")
+	tlsCertPath := flag.String("tls-cert", "", "file with the client certificate Cards presents to the vault")
+	tlsKeyPath := flag.String("tls-key", "", "file with the key of -tls-cert")
+	vaultCAPath := flag.String("vault-ca", "", "file with the CA certificate the vault's certificate is signed with")
+	flag.Parse()
+
+	if *scaKeyPath == "" {
+		log.Fatalf("-sca-key is required")
+	}
+	scaKey, err := os.ReadFile(*scaKeyPath)
+	if err != nil {
+		log.Fatalf("failed to read SCA key: %v", err)
+	}
+	if *activationKeyPath == "" {
+		log.Fatalf("-activation-key is required")
+	}
+	activationKey, err := os.ReadFile(*activationKeyPath)
+	if err != nil {
+		log.Fatalf("failed to read activation key: %v", err)
+	}
+
 	// Database connection setup (placeholder)
 	db, err := sql.Open("postgres", "user=user dbname=cards sslmode=disable")
 	if err != nil {
@@ -58,14 +97,59 @@ func main() {
 	}
 	log.Println("Database schema applied successfully")
 
-	s := &server{db: db}
+	issuingConfig, err := loadIssuingConfig(*issuingConfigPath)
+	if err != nil {
+		log.Fatalf("failed to load issuing config: %v", err)
+	}
+
+	// Set up gRPC client for the Vault service, which holds card numbers. The vault knows Cards by its
+	// client certificate.
+	vaultCreds, err := mtls.ClientCredentials(*tlsCertPath, *tlsKeyPath, *vaultCAPath)
+	if err != nil {
+		log.Fatalf("failed to load vault TLS credentials: %v", err)
+	}
+	vaultConn, err := grpc.Dial("localhost:50060", grpc.WithTransportCredentials(vaultCreds))
+	if err != nil {
+		log.Fatalf("failed to connect to Vault service: %v", err)
+	}
+	defer vaultConn.Close()
+
+	// Set up gRPC client for Accounts service
+	accountsConn, err := grpc.Dial("localhost:50061", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to Accounts service: %v", err)
+	}
+	defer accountsConn.Close()
+
+	s := &server{
+		db:             db,
+		vaultClient:    vaultpb.NewVaultClient(vaultConn),
+		accountsClient: accountspb.NewAccountsClient(accountsConn),
+		issuer:         &issuer{ranges: issuingConfig.BINRanges, now: time.Now},
+		scaKey:         scaKey,
+		activationKey:  activationKey,
+		newCardID:      func() string { return uuid.New().String() },
+		now:            time.Now,
+	}
+
+	// Relay card events written to the outbox
+	go outbox.NewRelay(db, rdb).Run(ctx)
 
 	// Set up Echo HTTP server
 	e := echo.New()
 	// Add HTTP routes here
 	e.POST("/cards", s.createCardHandler)
 	e.GET("/cards/:id", s.getCardHandler)
+	e.GET("/users/:user_id/cards", s.listCardsByUserHandler)
+	e.PUT("/cards/:id/nickname", s.setCardNicknameHandler)
+	e.POST("/cards/:id/default", s.setDefaultCardHandler)
 	e.PATCH("/cards/:id/status", s.updateCardStatusHandler)
+	e.GET("/cards/:id/history", s.getCardHistoryHandler)
+	e.GET("/users/:user_id/virtual-cards", s.listVirtualCardsHandler)
+	e.GET("/cards/:id/controls", s.getCardControlsHandler)
+	e.PUT("/cards/:id/controls", s.updateCardControlsHandler)
+	e.POST("/cards/:id/reissue", s.reissueCardHandler)
+	e.GET("/cards/:id/recurring-merchants", s.listRecurringMerchantsHandler)
 
 	// Set up gRPC server (placeholder)
 	grpcServer := grpc.NewServer()
@@ -94,111 +178,296 @@ func main() {
 func (s *server) CreateCard(ctx context.Context, req *cardspb.CreateCardRequest) (*cardspb.Card, error) {
 	log.Printf("Received CreateCard request: %+v", req)
 
-	cardID := uuid.New().String()
-	status := "ACTIVE" // Default status
+	cardType := req.GetCardType()
+	if cardType == "" {
+		cardType = cardTypePhysical
+	}
+	if cardType != cardTypePhysical && cardType != cardTypeVirtual {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid card type: %s", cardType)
+	}
+	rules, err := virtualRulesFromRequest(req, cardType, s.now())
+	if err != nil {
+		return nil, err
+	}
 
-	query := `INSERT INTO cards (card_id, user_id, status, created_at) VALUES ($1, $2, $3, NOW()) RETURNING card_id, user_id, status, last_four, created_at, updated_at`
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create card")
+	}
+	defer tx.Rollback() // Rollback if not committed
 
-	var createdCard cardspb.Card
-	err := s.db.QueryRowContext(ctx, query, cardID, req.GetUserId(), status).Scan(
-		&createdCard.Id,
-		&createdCard.UserId,
-		&createdCard.Status,
-		&createdCard.LastFour, // last_four will be null initially
-		// created_at and updated_at are not in the proto message, skip scanning them
-	)
+	createdCard, err := s.insertCard(ctx, tx, req.GetUserId(), cardType, rules, "", actorCards)
 	if err != nil {
 		log.Printf("failed to insert card: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to create card")
 	}
 
-	// TODO: Publish "card.created" event to Redis
-	// Event payload could be JSON or protobuf binary
-	eventPayload := fmt.Sprintf(`{"card_id": "%s", "user_id": "%s", "status": "%s"}`, createdCard.GetId(), createdCard.GetUserId(), createdCard.GetStatus())
-	if _, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
-		Stream: "card:created",
-		MaxLen: 0, // No limit
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
-	}).Result(); err != nil {
-		log.Printf("failed to publish card:created event: %v", err)
-		// Depending on requirements, failure to publish event might be critical or just logged
-		// For now, just log the error
-	} else {
-		log.Printf("Published card:created event for card %s", createdCard.GetId())
-	}
-
-	return &createdCard, nil
+	// Enqueue "card:created" event in the same transaction
+	event := &eventspb.CardCreated{
+		CardId: createdCard.GetCardId(),
+		UserId: createdCard.GetUserId(),
+		Status: createdCard.GetStatus(),
+	}
+	if err := events.Enqueue(ctx, tx, events.StreamCardCreated, createdCard.GetCardId(), event); err != nil {
+		log.Printf("failed to enqueue card:created event: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create card")
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create card")
+	}
+
+	return createdCard, nil
+}
+
+// insertCard issues a new card in tx with the virtual card rules given, recording actor as its
+// issuer in its status history. The card's number and CVV are generated by the vault, and Cards
+// only ever has the vault's token for them and the number's last four digits.
+//
+// Virtual cards are ACTIVE straight away. Physical cards are INACTIVE until the cardholder
+// activates them with the activation code returned with the card, of which Cards keeps a hash.
+//
+// A card whose transaction is rolled back leaves an entry in the vault that no card refers to.
+func (s *server) insertCard(ctx context.Context, tx *sql.Tx, userID, cardType string, rules virtualRules, replacesCardID, actor string) (*cardspb.Card, error) {
+	query := `INSERT INTO cards (card_id, user_id, card_type, status, pan_token, last_four, expiry_month, expiry_year, replaces_card_id,
+			  activation_code_hash, virtual_type, spend_cap, expires_at, locked_merchant_id, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())`
+
+	issued, err := s.issuer.issue(cardType)
+	if err != nil {
+		return nil, err
+	}
+	number, err := s.vaultClient.IssueCardNumber(ctx, &vaultpb.IssueCardNumberRequest{
+		Bin:       issued.binRange.BIN,
+		PanLength: int32(issued.binRange.PANLength),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue card number in vault: %w", err)
+	}
+
+	card := &cardspb.Card{
+		CardId:           s.newCardID(),
+		UserId:           userID,
+		Status:           "ACTIVE",
+		LastFour:         number.GetLastFour(),
+		CardType:         cardType,
+		ExpiryMonth:      issued.expiryMonth,
+		ExpiryYear:       issued.expiryYear,
+		PanToken:         number.GetToken(),
+		ReplacesCardId:   replacesCardID,
+		ActivationCode:   issued.activationCode,
+		VirtualType:      rules.virtualType,
+		SpendCap:         rules.spendCap,
+		LockedMerchantId: rules.lockedMerchantID,
+		CreatedAt:        s.now().UTC().Format(time.RFC3339),
+	}
+	if rules.expiresAt.Valid {
+		card.ExpiresAt = rules.expiresAt.Time.Format(time.RFC3339)
+	}
+	var activationCodeHash sql.NullString
+	if issued.activationCode != "" {
+		card.Status = "INACTIVE"
+		activationCodeHash = sql.NullString{String: s.hashActivationCode(issued.activationCode), Valid: true}
+	}
+
+	_, err = tx.ExecContext(ctx, query, card.CardId, userID, cardType, card.Status, card.PanToken, card.LastFour,
+		issued.expiryMonth, issued.expiryYear, sql.NullString{String: replacesCardID, Valid: replacesCardID != ""}, activationCodeHash,
+		sql.NullString{String: rules.virtualType, Valid: rules.virtualType != ""}, rules.spendCap, rules.expiresAt,
+		sql.NullString{String: rules.lockedMerchantID, Valid: rules.lockedMerchantID != ""})
+	if err != nil {
+		return nil, err
+	}
+
+	reason := "issued"
+	if replacesCardID != "" {
+		reason = "issued to replace card " + replacesCardID
+	}
+	if err := recordStatusChange(ctx, tx, card.CardId, "", card.Status, reason, actor); err != nil {
+		return nil, err
+	}
+	return card, nil
 }
 
 func (s *server) GetCard(ctx context.Context, req *cardspb.GetCardRequest) (*cardspb.Card, error) {
 	log.Printf("Received GetCard request: %+v", req)
+	return s.getCard(ctx, "card_id", req.GetCardId())
+}
 
-	query := `SELECT card_id, user_id, status, last_four FROM cards WHERE card_id = $1`
+func (s *server) GetCardByPanToken(ctx context.Context, req *cardspb.GetCardByPanTokenRequest) (*cardspb.Card, error) {
+	log.Printf("Received GetCardByPanToken request: %+v", req)
+	return s.getCard(ctx, "pan_token", req.GetPanToken())
+}
+
+// cardColumns are the columns of cards a Card is read from, see scanCard. Cards issued before card
+// numbers were generated have no number details.
+const cardColumns = `card_id, user_id, status, COALESCE(last_four, ''), card_type, COALESCE(expiry_month, 0), COALESCE(expiry_year, 0),
+			  COALESCE(pan_token, ''), COALESCE(replaces_card_id::text, ''), COALESCE(virtual_type, ''), spend_cap, expires_at,
+			  COALESCE(locked_merchant_id, ''), created_at, COALESCE(nickname, ''), is_default`
 
+// scanCard reads a card selected with cardColumns
+func scanCard(row interface{ Scan(...interface{}) error }) (*cardspb.Card, error) {
 	var card cardspb.Card
-	err := s.db.QueryRowContext(ctx, query, req.GetCardId()).Scan(
-		&card.Id,
+	var expiresAt sql.NullTime
+	var createdAt time.Time
+	err := row.Scan(
+		&card.CardId,
 		&card.UserId,
 		&card.Status,
 		&card.LastFour,
+		&card.CardType,
+		&card.ExpiryMonth,
+		&card.ExpiryYear,
+		&card.PanToken,
+		&card.ReplacesCardId,
+		&card.VirtualType,
+		&card.SpendCap,
+		&expiresAt,
+		&card.LockedMerchantId,
+		&createdAt,
+		&card.Nickname,
+		&card.IsDefault,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		card.ExpiresAt = expiresAt.Time.UTC().Format(time.RFC3339)
+	}
+	card.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return &card, nil
+}
+
+// getCard gets the card whose column, card_id or pan_token, is value
+func (s *server) getCard(ctx context.Context, column, value string) (*cardspb.Card, error) {
+	query := `SELECT ` + cardColumns + ` FROM cards WHERE ` + column + ` = $1`
+
+	card, err := scanCard(s.db.QueryRowContext(ctx, query, value))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Printf("card not found: %s", req.GetCardId())
+			log.Printf("card not found by %s: %s", column, value)
 			return nil, status.Errorf(codes.NotFound, "card not found")
 		}
 		log.Printf("failed to get card: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to get card")
 	}
 
-	return &card, nil
+	return card, nil
 }
 
+// UpdateCardStatus moves a card to a new status, if the card's current status allows it, and
+// records the change in the card's history. Moving a card to the status it already has changes nothing.
 func (s *server) UpdateCardStatus(ctx context.Context, req *cardspb.UpdateCardStatusRequest) (*cardspb.Card, error) {
-	log.Printf("Received UpdateCardStatus request: %+v", req)
+	// The request holds the activation code, so it isn't logged
+	log.Printf("Received UpdateCardStatus request for card %s: %s by %q (%s)", req.GetCardId(), req.GetNewStatus(), req.GetActor(), req.GetReason())
 
-	// Basic validation for status (database check constraint is also there)
-	validStatuses := map[string]bool{"ACTIVE": true, "INACTIVE": true, "FROZEN": true, "CLOSED": true}
-	if !validStatuses[req.GetNewStatus()] {
+	if _, ok := cardTransitions[req.GetNewStatus()]; !ok {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid card status: %s", req.GetNewStatus())
 	}
 
-	query := `UPDATE cards SET status = $1, updated_at = NOW() WHERE card_id = $2 RETURNING card_id, user_id, status, last_four`
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update card status")
+	}
+	defer tx.Rollback() // Rollback if not committed
 
-	var updatedCard cardspb.Card
-	err := s.db.QueryRowContext(ctx, query, req.GetNewStatus(), req.GetCardId()).Scan(
-		&updatedCard.Id,
-		&updatedCard.UserId,
-		&updatedCard.Status,
-		&updatedCard.LastFour,
+	// Lock the card so concurrent changes are checked against each other's outcome
+	var card cardspb.Card
+	var activationCodeHash string
+	var activationFailedAttempts int32
+	query := `SELECT card_id, user_id, status, COALESCE(last_four, ''), COALESCE(activation_code_hash, ''), activation_failed_attempts
+			  FROM cards WHERE card_id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, req.GetCardId()).Scan(
+		&card.CardId,
+		&card.UserId,
+		&card.Status,
+		&card.LastFour,
+		&activationCodeHash,
+		&activationFailedAttempts,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("card not found for update: %s", req.GetCardId())
 			return nil, status.Errorf(codes.NotFound, "card not found")
 		}
+		log.Printf("failed to get card to update status: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update card status")
+	}
+	if card.GetStatus() == req.GetNewStatus() {
+		return &card, nil
+	}
+	if err := checkTransition(card.GetStatus(), req.GetNewStatus()); err != nil {
+		return nil, err
+	}
+	if card.GetStatus() == "INACTIVE" && req.GetNewStatus() == "ACTIVE" {
+		if req.GetActivationCode() == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "activation code required to activate card")
+		}
+		if activationFailedAttempts >= maxActivationAttempts {
+			return nil, status.Errorf(codes.FailedPrecondition, "card activation is locked, the card must be replaced")
+		}
+		if !s.activationCodeMatches(activationCodeHash, req.GetActivationCode()) {
+			return nil, s.activationFailed(ctx, tx, req.GetCardId(), activationFailedAttempts+1)
+		}
+	}
+
+	// Cards never return to INACTIVE, so the activation code isn't needed once they leave it, and
+	// closed cards stop being their user's default
+	update := `UPDATE cards SET status = $2, activation_code_hash = NULL, is_default = is_default AND $2 <> 'CLOSED', updated_at = NOW()
+			   WHERE card_id = $1`
+	if _, err := tx.ExecContext(ctx, update, req.GetCardId(), req.GetNewStatus()); err != nil {
 		log.Printf("failed to update card status: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to update card status")
 	}
+	if err := recordStatusChange(ctx, tx, req.GetCardId(), card.GetStatus(), req.GetNewStatus(), req.GetReason(), req.GetActor()); err != nil {
+		log.Printf("failed to record card status change: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update card status")
+	}
+	card.Status = req.GetNewStatus()
 
-	// TODO: Publish "card.status_changed" event to Redis
-	eventPayload := fmt.Sprintf(`{"card_id": "%s", "user_id": "%s", "new_status": "%s"}`, updatedCard.GetId(), updatedCard.GetUserId(), updatedCard.GetStatus())
-	if _, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
-		Stream: "card:status_changed",
-		MaxLen: 0, // No limit
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
-	}).Result(); err != nil {
-		log.Printf("failed to publish card:status_changed event: %v", err)
-		// Log the error
-	} else {
-		log.Printf("Published card:status_changed event for card %s", updatedCard.GetId())
+	// Enqueue "card:status_changed" event in the same transaction
+	event := &eventspb.CardStatusChanged{
+		CardId:    card.GetCardId(),
+		UserId:    card.GetUserId(),
+		NewStatus: card.GetStatus(),
+	}
+	if err := events.Enqueue(ctx, tx, events.StreamCardStatusChanged, card.GetCardId(), event); err != nil {
+		log.Printf("failed to enqueue card:status_changed event: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update card status")
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update card status")
 	}
 
-	return &updatedCard, nil
+	return &card, nil
+}
+
+// activationFailed records a wrong activation code for a card, the attempts'th in a row, and returns
+// the error to answer with. The attempt is committed, so it counts whatever the caller does next;
+// the last attempt allowed locks the card's activation.
+func (s *server) activationFailed(ctx context.Context, tx *sql.Tx, cardID string, attempts int32) error {
+	update := `UPDATE cards SET activation_failed_attempts = $2, updated_at = NOW() WHERE card_id = $1`
+	if _, err := tx.ExecContext(ctx, update, cardID, attempts); err != nil {
+		log.Printf("failed to record wrong activation code: %v", err)
+		return status.Errorf(codes.Internal, "failed to update card status")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return status.Errorf(codes.Internal, "failed to update card status")
+	}
+
+	if attempts >= maxActivationAttempts {
+		log.Printf("activation of card %s locked after %d wrong codes", cardID, attempts)
+		return status.Errorf(codes.PermissionDenied, "incorrect activation code, card activation is now locked")
+	}
+	log.Printf("incorrect activation code for card %s", cardID)
+	return status.Errorf(codes.PermissionDenied, "incorrect activation code, %d attempts remaining", maxActivationAttempts-attempts)
 }
 
 // Implement HTTP handlers here
@@ -257,15 +526,21 @@ func (s *server) updateCardStatusHandler(c echo.Context) error {
 	cardID := c.Param("id")
 
 	var updateReq struct {
-		Status string `json:"status"`
+		Status         string `json:"status"`
+		Reason         string `json:"reason"`
+		Actor          string `json:"actor"`
+		ActivationCode string `json:"activation_code"`
 	}
 	if err := c.Bind(&updateReq); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
 
 	req := &cardspb.UpdateCardStatusRequest{
-		CardId:    cardID,
-		NewStatus: updateReq.Status,
+		CardId:         cardID,
+		NewStatus:      updateReq.Status,
+		Reason:         updateReq.Reason,
+		Actor:          updateReq.Actor,
+		ActivationCode: updateReq.ActivationCode,
 	}
 
 	card, err := s.UpdateCardStatus(c.Request().Context(), req)
@@ -278,6 +553,10 @@ func (s *server) updateCardStatusHandler(c echo.Context) error {
 				return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
 			case codes.InvalidArgument:
 				return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+			case codes.FailedPrecondition:
+				return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+			case codes.PermissionDenied:
+				return c.JSON(http.StatusForbidden, map[string]string{"error": st.Message()})
 			case codes.Internal:
 				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 			default:
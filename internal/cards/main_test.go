@@ -4,34 +4,200 @@ import (
 	"context"
 	"database/sql"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/go-redis/redismock/v8"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	accountspb "github.com/manifoldfinance/disco2/v2/pkg/pb/accounts"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+	"github.com/manifoldfinance/disco2/v2/pkg/pinblock"
+	"github.com/manifoldfinance/disco2/v2/pkg/sca"
+
 	cardspb "github.com/sambacha/monzo/v2/cards/cards"
 )
 
+// Mock Vault Client
+type mockVaultClient struct{ mock.Mock }
+
+func (m *mockVaultClient) IssueCardNumber(ctx context.Context, in *vaultpb.IssueCardNumberRequest, opts ...grpc.CallOption) (*vaultpb.IssuedCardNumber, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.IssuedCardNumber), args.Error(1)
+}
+
+func (m *mockVaultClient) Tokenize(ctx context.Context, in *vaultpb.TokenizeRequest, opts ...grpc.CallOption) (*vaultpb.Token, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.Token), args.Error(1)
+}
+
+func (m *mockVaultClient) LookupToken(ctx context.Context, in *vaultpb.LookupTokenRequest, opts ...grpc.CallOption) (*vaultpb.Token, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.Token), args.Error(1)
+}
+
+func (m *mockVaultClient) Detokenize(ctx context.Context, in *vaultpb.DetokenizeRequest, opts ...grpc.CallOption) (*vaultpb.CardData, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.CardData), args.Error(1)
+}
+
+func (m *mockVaultClient) RotateKeys(ctx context.Context, in *vaultpb.RotateKeysRequest, opts ...grpc.CallOption) (*vaultpb.RotateKeysResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.RotateKeysResponse), args.Error(1)
+}
+
+func (m *mockVaultClient) SetPinBlock(ctx context.Context, in *vaultpb.SetPinBlockRequest, opts ...grpc.CallOption) (*vaultpb.SetPinBlockResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.SetPinBlockResponse), args.Error(1)
+}
+
+func (m *mockVaultClient) VerifyPinBlock(ctx context.Context, in *vaultpb.VerifyPinBlockRequest, opts ...grpc.CallOption) (*vaultpb.VerifyPinBlockResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vaultpb.VerifyPinBlockResponse), args.Error(1)
+}
+
+// Mock Accounts Client
+type mockAccountsClient struct {
+	mock.Mock
+	accountspb.AccountsClient
+}
+
+func (m *mockAccountsClient) CarryCardLink(ctx context.Context, in *accountspb.CarryCardLinkRequest, opts ...grpc.CallOption) (*accountspb.CarriedCardLink, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*accountspb.CarriedCardLink), args.Error(1)
+}
+
 // Helper function to create a server instance with mocks
-func newTestServer(t *testing.T) (*server, sqlmock.Sqlmock, redismock.ClientMock) {
+func newTestServer(t *testing.T) (*server, sqlmock.Sqlmock) {
 	db, mockDb, err := sqlmock.New()
 	assert.NoError(t, err)
 
-	mockRedis, mockRedisClient := redismock.NewClientMock()
-
 	s := &server{
-		db:          db,
-		redisClient: mockRedisClient,
+		db:             db,
+		vaultClient:    new(mockVaultClient),
+		accountsClient: new(mockAccountsClient),
+		issuer:         &issuer{ranges: testBINRanges, now: func() time.Time { return testNow }},
+		newCardID:      func() string { return "new-card-id" },
+		scaKey:         testSCAKey,
+		activationKey:  []byte("activation-key"),
+		now:            func() time.Time { return testNow },
+	}
+	return s, mockDb
+}
+
+// testNow is the time the test server runs at
+var testNow = time.Date(2025, time.March, 14, 15, 30, 0, 0, time.UTC)
+
+// testSCAKey is the key the test server checks SCA tokens with
+var testSCAKey = []byte("test-sca-key")
+
+// testBINRanges are the ranges the test server issues cards from
+var testBINRanges = []binRange{
+	{CardType: cardTypePhysical, BIN: "45996500", PANLength: 16, ValidityMonths: 48},
+	{CardType: cardTypeVirtual, BIN: "45996510", PANLength: 16, ValidityMonths: 36},
+}
+
+// insertCardQuery is the query new cards are issued with
+const insertCardQuery = `INSERT INTO cards (card_id, user_id, card_type, status, pan_token, last_four, expiry_month, expiry_year, replaces_card_id,
+			  activation_code_hash, virtual_type, spend_cap, expires_at, locked_merchant_id, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())`
+
+// getCardQuery is the query GetCard reads a card with
+const getCardQuery = `SELECT card_id, user_id, status, COALESCE(last_four, ''), card_type, COALESCE(expiry_month, 0), COALESCE(expiry_year, 0),
+			  COALESCE(pan_token, ''), COALESCE(replaces_card_id::text, ''), COALESCE(virtual_type, ''), spend_cap, expires_at,
+			  COALESCE(locked_merchant_id, ''), created_at, COALESCE(nickname, ''), is_default FROM cards WHERE card_id = $1`
+
+// cardColumnNames are the columns cards are read with
+var cardColumnNames = []string{"card_id", "user_id", "status", "last_four", "card_type", "expiry_month", "expiry_year", "pan_token",
+	"replaces_card_id", "virtual_type", "spend_cap", "expires_at", "locked_merchant_id", "created_at", "nickname", "is_default"}
+
+// expectIssueCardNumber expects the vault to issue a 16 digit card number from bin, failing with err if it isn't nil
+func expectIssueCardNumber(s *server, bin, token string, err error) {
+	req := &vaultpb.IssueCardNumberRequest{Bin: bin, PanLength: 16}
+	if err != nil {
+		s.vaultClient.(*mockVaultClient).On("IssueCardNumber", mock.Anything, req).Return(nil, err).Once()
+		return
+	}
+	s.vaultClient.(*mockVaultClient).On("IssueCardNumber", mock.Anything, req).
+		Return(&vaultpb.IssuedCardNumber{Token: token, LastFour: "4321"}, nil).Once()
+}
+
+// expectCarryCardLink expects the account link of a reissued card to be carried over to its replacement
+func expectCarryCardLink(s *server, cardID, replacesCardID string, err error) {
+	req := &accountspb.CarryCardLinkRequest{CardId: cardID, ReplacesCardId: replacesCardID}
+	if err != nil {
+		s.accountsClient.(*mockAccountsClient).On("CarryCardLink", mock.Anything, req).Return(nil, err).Once()
+		return
+	}
+	s.accountsClient.(*mockAccountsClient).On("CarryCardLink", mock.Anything, req).
+		Return(&accountspb.CarriedCardLink{CardId: cardID}, nil).Once()
+}
+
+// expectInsertCard expects a new ordinary card to be issued with the vault token given, and its
+// issue to be recorded in its history. Physical cards are issued INACTIVE with an activation code.
+func expectInsertCard(mockDb sqlmock.Sqlmock, userID, cardType, token string, expiryMonth, expiryYear int64, replacesCardID interface{}) {
+	expectInsertVirtualCard(mockDb, userID, cardType, token, expiryMonth, expiryYear, replacesCardID, nil, int64(0), nil)
+}
+
+// expectInsertVirtualCard expects a new card to be issued with virtual card rules
+func expectInsertVirtualCard(mockDb sqlmock.Sqlmock, userID, cardType, token string, expiryMonth, expiryYear int64, replacesCardID,
+	virtualType, spendCap, expiresAt interface{}) {
+	cardStatus, activationCodeHash := "ACTIVE", interface{}(nil)
+	if cardType == "physical" {
+		cardStatus, activationCodeHash = "INACTIVE", sqlmock.AnyArg()
 	}
-	return s, mockDb, mockRedisClient
+	mockDb.ExpectExec(regexp.QuoteMeta(insertCardQuery)).
+		WithArgs("new-card-id", userID, cardType, cardStatus, token, sqlmock.AnyArg(), expiryMonth, expiryYear, replacesCardID, activationCodeHash,
+			virtualType, spendCap, expiresAt, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectStatusChange(mockDb, "new-card-id", nil, cardStatus)
+}
+
+// expectStatusChange expects a change of a card's status to be recorded in its history
+func expectStatusChange(mockDb sqlmock.Sqlmock, cardID string, from interface{}, to string) {
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO card_status_history (card_id, from_status, to_status, reason, actor, changed_at)`)).
+		WithArgs(cardID, from, to, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// expectCardEvent expects a card event to be enqueued in the outbox
+func expectCardEvent(mockDb sqlmock.Sqlmock, stream, cardID string) {
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs(stream, cardID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 }
 
 func TestCreateCard(t *testing.T) {
-	s, mockDb, mockRedis := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	req := &cardspb.CreateCardRequest{
@@ -39,66 +205,124 @@ func TestCreateCard(t *testing.T) {
 		CardType: "virtual",
 	}
 
-	// Mock DB INSERT query
-	// Use regexp matching for the query because UUID is generated dynamically
-	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO cards (card_id, user_id, status, created_at) VALUES ($1, $2, $3, NOW()) RETURNING card_id, user_id, status, last_four, created_at, updated_at`)).
-		WithArgs(sqlmock.AnyArg(), req.UserId, "ACTIVE"). // Check user_id and status
-		WillReturnRows(sqlmock.NewRows([]string{"card_id", "user_id", "status", "last_four", "created_at", "updated_at"}).
-			AddRow("new-card-id", req.UserId, "ACTIVE", sql.NullString{}, time.Now(), sql.NullTime{}))
+	// Virtual cards are valid for three years, and their number is issued from the virtual card BIN range by the vault
+	mockDb.ExpectBegin()
+	expectIssueCardNumber(s, "45996510", "token-1", nil)
+	expectInsertCard(mockDb, req.UserId, "virtual", "token-1", 3, 2028, nil)
 
-	// Mock Redis XAdd command
-	mockRedis.ExpectXAdd(&redis.XAddArgs{
-		Stream: "card:created",
-		Values: map[string]interface{}{"payload": redismock.AnyValue}, // Check stream name, ignore payload content for simplicity
-	}).SetVal("some-stream-id")
+	// Expect the card:created event in the same transaction
+	expectCardEvent(mockDb, "card:created", "new-card-id")
+	mockDb.ExpectCommit()
 
 	ctx := context.Background()
 	resp, err := s.CreateCard(ctx, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
-	assert.Equal(t, "new-card-id", resp.Id)
+	assert.Equal(t, "new-card-id", resp.CardId)
 	assert.Equal(t, req.UserId, resp.UserId)
 	assert.Equal(t, "ACTIVE", resp.Status)
+	assert.Equal(t, "virtual", resp.CardType)
+	assert.Empty(t, resp.ActivationCode)
+
+	// Only the vault has the card's number and CVV
+	assert.Equal(t, "4321", resp.LastFour)
+	assert.Equal(t, "token-1", resp.PanToken)
+	assert.Equal(t, int32(3), resp.ExpiryMonth)
+	assert.Equal(t, int32(2028), resp.ExpiryYear)
 
 	// Verify that all expectations were met
 	assert.NoError(t, mockDb.ExpectationsWereMet())
-	assert.NoError(t, mockRedis.ExpectationsWereMet())
+	s.vaultClient.(*mockVaultClient).AssertExpectations(t)
+}
+
+func TestCreateCard_Physical(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.CreateCardRequest{UserId: "user-123"}
+
+	mockDb.ExpectBegin()
+	expectIssueCardNumber(s, "45996500", "token-2", nil)
+	expectInsertCard(mockDb, req.UserId, "physical", "token-2", 3, 2029, nil)
+	expectCardEvent(mockDb, "card:created", "new-card-id")
+	mockDb.ExpectCommit()
+
+	resp, err := s.CreateCard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "physical", resp.CardType)
+
+	// Physical cards are activated when they arrive, with the code sent with them
+	assert.Equal(t, "INACTIVE", resp.Status)
+	assert.Len(t, resp.ActivationCode, 6)
+	assert.Equal(t, "token-2", resp.PanToken)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	s.vaultClient.(*mockVaultClient).AssertExpectations(t)
+}
+
+func TestCreateCard_VaultUnavailable(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// No card is issued without a number from the vault
+	mockDb.ExpectBegin()
+	expectIssueCardNumber(s, "45996500", "", status.Error(codes.Unavailable, "connection refused"))
+	mockDb.ExpectRollback()
+
+	resp, err := s.CreateCard(context.Background(), &cardspb.CreateCardRequest{UserId: "user-123"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreateCard_InvalidType(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	resp, err := s.CreateCard(context.Background(), &cardspb.CreateCardRequest{UserId: "user-123", CardType: "metal"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
 }
 
 func TestGetCard_Found(t *testing.T) {
-	s, mockDb, _ := newTestServer(t) // No Redis mock needed for GetCard
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	req := &cardspb.GetCardRequest{CardId: "card-abc"}
 
 	// Mock DB SELECT query
-	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT card_id, user_id, status, last_four FROM cards WHERE card_id = $1`)).
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
 		WithArgs(req.CardId).
-		WillReturnRows(sqlmock.NewRows([]string{"card_id", "user_id", "status", "last_four"}).
-			AddRow(req.CardId, "user-123", "ACTIVE", "1234"))
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow(req.CardId, "user-123", "ACTIVE", "1234", "physical", 9, 2028, "token-1", "", "", 0, nil, "", testNow, "", false))
 
 	ctx := context.Background()
 	resp, err := s.GetCard(ctx, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
-	assert.Equal(t, req.CardId, resp.Id)
+	assert.Equal(t, req.CardId, resp.CardId)
 	assert.Equal(t, "user-123", resp.UserId)
 	assert.Equal(t, "ACTIVE", resp.Status)
 	assert.Equal(t, "1234", resp.LastFour)
+	assert.Equal(t, int32(9), resp.ExpiryMonth)
+	assert.Equal(t, int32(2028), resp.ExpiryYear)
+	assert.Equal(t, "token-1", resp.PanToken)
 
 	assert.NoError(t, mockDb.ExpectationsWereMet())
 }
 
 func TestGetCard_NotFound(t *testing.T) {
-	s, mockDb, _ := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	req := &cardspb.GetCardRequest{CardId: "card-xyz"}
 
 	// Mock DB SELECT query to return no rows
-	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT card_id, user_id, status, last_four FROM cards WHERE card_id = $1`)).
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
 		WithArgs(req.CardId).
 		WillReturnError(sql.ErrNoRows)
 
@@ -114,43 +338,92 @@ func TestGetCard_NotFound(t *testing.T) {
 	assert.NoError(t, mockDb.ExpectationsWereMet())
 }
 
+func TestGetCardByPanToken(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(strings.Replace(getCardQuery, "WHERE card_id", "WHERE pan_token", 1))).
+		WithArgs("token-1").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-abc", "user-123", "ACTIVE", "1234", "physical", 9, 2028, "token-1", "", "", 0, nil, "", testNow, "", false))
+
+	resp, err := s.GetCardByPanToken(context.Background(), &cardspb.GetCardByPanTokenRequest{PanToken: "token-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "card-abc", resp.CardId)
+	assert.Equal(t, "token-1", resp.PanToken)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// lockCardQuery is the query UpdateCardStatus reads and locks a card with
+const lockCardQuery = `SELECT card_id, user_id, status, COALESCE(last_four, ''), COALESCE(activation_code_hash, ''), activation_failed_attempts
+			  FROM cards WHERE card_id = $1 FOR UPDATE`
+
+// expectLockCard expects a card to be read and locked to update its status
+func expectLockCard(mockDb sqlmock.Sqlmock, cardID, cardStatus, activationCodeHash string) {
+	expectLockInactiveCard(mockDb, cardID, cardStatus, activationCodeHash, 0)
+}
+
+// expectLockInactiveCard expects a card to be read and locked, with the wrong activation codes given so far
+func expectLockInactiveCard(mockDb sqlmock.Sqlmock, cardID, cardStatus, activationCodeHash string, failedAttempts int) {
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockCardQuery)).
+		WithArgs(cardID).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "user_id", "status", "last_four", "activation_code_hash", "activation_failed_attempts"}).
+			AddRow(cardID, "user-456", cardStatus, "5678", activationCodeHash, failedAttempts))
+}
+
+// updateStatusQuery is the statement UpdateCardStatus changes a card's status with
+const updateStatusQuery = `UPDATE cards SET status = $2, activation_code_hash = NULL, is_default = is_default AND $2 <> 'CLOSED', updated_at = NOW()
+			   WHERE card_id = $1`
+
+// expectUpdateStatus expects a card's status to be changed, recorded and announced
+func expectUpdateStatus(mockDb sqlmock.Sqlmock, cardID, from, to string) {
+	mockDb.ExpectExec(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(cardID, to).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectStatusChange(mockDb, cardID, from, to)
+	expectCardEvent(mockDb, "card:status_changed", cardID)
+}
+
 func TestUpdateCardStatus_Success(t *testing.T) {
-	s, mockDb, mockRedis := newTestServer(t)
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	req := &cardspb.UpdateCardStatusRequest{
 		CardId:    "card-def",
 		NewStatus: "FROZEN",
+		Reason:    "card misplaced",
+		Actor:     "user-456",
 	}
 
-	// Mock DB UPDATE query
-	mockDb.ExpectQuery(regexp.QuoteMeta(`UPDATE cards SET status = $1, updated_at = NOW() WHERE card_id = $2 RETURNING card_id, user_id, status, last_four`)).
-		WithArgs(req.NewStatus, req.CardId).
-		WillReturnRows(sqlmock.NewRows([]string{"card_id", "user_id", "status", "last_four"}).
-			AddRow(req.CardId, "user-456", req.NewStatus, "5678"))
+	mockDb.ExpectBegin()
+	expectLockCard(mockDb, req.CardId, "ACTIVE", "")
+	mockDb.ExpectExec(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(req.CardId, req.NewStatus).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO card_status_history (card_id, from_status, to_status, reason, actor, changed_at)`)).
+		WithArgs(req.CardId, "ACTIVE", "FROZEN", "card misplaced", "user-456").
+		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	// Mock Redis XAdd command
-	mockRedis.ExpectXAdd(&redis.XAddArgs{
-		Stream: "card:status_changed",
-		Values: map[string]interface{}{"payload": redismock.AnyValue},
-	}).SetVal("some-stream-id")
+	// Expect the card:status_changed event in the same transaction
+	expectCardEvent(mockDb, "card:status_changed", req.CardId)
+	mockDb.ExpectCommit()
 
 	ctx := context.Background()
 	resp, err := s.UpdateCardStatus(ctx, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
-	assert.Equal(t, req.CardId, resp.Id)
+	assert.Equal(t, req.CardId, resp.CardId)
 	assert.Equal(t, "user-456", resp.UserId)
 	assert.Equal(t, req.NewStatus, resp.Status)
 	assert.Equal(t, "5678", resp.LastFour)
 
 	assert.NoError(t, mockDb.ExpectationsWereMet())
-	assert.NoError(t, mockRedis.ExpectationsWereMet())
 }
 
 func TestUpdateCardStatus_InvalidStatus(t *testing.T) {
-	s, _, _ := newTestServer(t) // No DB/Redis interaction expected
+	s, _ := newTestServer(t) // No DB interaction expected
 	defer s.db.Close()
 
 	req := &cardspb.UpdateCardStatusRequest{
@@ -169,7 +442,7 @@ func TestUpdateCardStatus_InvalidStatus(t *testing.T) {
 }
 
 func TestUpdateCardStatus_NotFound(t *testing.T) {
-	s, mockDb, _ := newTestServer(t) // No Redis mock needed if DB fails
+	s, mockDb := newTestServer(t)
 	defer s.db.Close()
 
 	req := &cardspb.UpdateCardStatusRequest{
@@ -177,10 +450,11 @@ func TestUpdateCardStatus_NotFound(t *testing.T) {
 		NewStatus: "FROZEN",
 	}
 
-	// Mock DB UPDATE query to return no rows
-	mockDb.ExpectQuery(regexp.QuoteMeta(`UPDATE cards SET status = $1, updated_at = NOW() WHERE card_id = $2 RETURNING card_id, user_id, status, last_four`)).
-		WithArgs(req.NewStatus, req.CardId).
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockCardQuery)).
+		WithArgs(req.CardId).
 		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectRollback()
 
 	ctx := context.Background()
 	resp, err := s.UpdateCardStatus(ctx, req)
@@ -193,3 +467,1070 @@ func TestUpdateCardStatus_NotFound(t *testing.T) {
 
 	assert.NoError(t, mockDb.ExpectationsWereMet())
 }
+
+func TestUpdateCardStatus_InvalidTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+	}{
+		{"CLOSED", "ACTIVE"},
+		{"CLOSED", "FROZEN"},
+		{"ACTIVE", "INACTIVE"},
+		{"FROZEN", "INACTIVE"},
+		{"INACTIVE", "FROZEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			mockDb.ExpectBegin()
+			expectLockCard(mockDb, "card-def", tt.from, "")
+			mockDb.ExpectRollback()
+
+			resp, err := s.UpdateCardStatus(context.Background(), &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: tt.to})
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateCardStatus_Unchanged(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Freezing a frozen card changes nothing, and records nothing
+	mockDb.ExpectBegin()
+	expectLockCard(mockDb, "card-def", "FROZEN", "")
+	mockDb.ExpectRollback()
+
+	resp, err := s.UpdateCardStatus(context.Background(), &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: "FROZEN"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FROZEN", resp.Status)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateCardStatus_Activate(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: "ACTIVE", Actor: "user-456", ActivationCode: "123456"}
+
+	mockDb.ExpectBegin()
+	expectLockCard(mockDb, req.CardId, "INACTIVE", s.hashActivationCode("123456"))
+	expectUpdateStatus(mockDb, req.CardId, "INACTIVE", "ACTIVE")
+	mockDb.ExpectCommit()
+
+	resp, err := s.UpdateCardStatus(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ACTIVE", resp.Status)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateCardStatus_ActivationCodeMissing(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectBegin()
+	expectLockCard(mockDb, "card-def", "INACTIVE", s.hashActivationCode("123456"))
+	mockDb.ExpectRollback()
+
+	resp, err := s.UpdateCardStatus(context.Background(), &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: "ACTIVE"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateCardStatus_ActivationCodeIncorrect(t *testing.T) {
+	tests := []struct {
+		name           string
+		failedAttempts int
+		message        string
+	}{
+		{"first", 0, "incorrect activation code, 2 attempts remaining"},
+		{"last", maxActivationAttempts - 1, "incorrect activation code, card activation is now locked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			// Wrong codes are committed, so they count
+			mockDb.ExpectBegin()
+			expectLockInactiveCard(mockDb, "card-def", "INACTIVE", s.hashActivationCode("123456"), tt.failedAttempts)
+			mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET activation_failed_attempts = $2, updated_at = NOW() WHERE card_id = $1`)).
+				WithArgs("card-def", int32(tt.failedAttempts+1)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDb.ExpectCommit()
+
+			req := &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: "ACTIVE", ActivationCode: "654321"}
+			resp, err := s.UpdateCardStatus(context.Background(), req)
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+			assert.Equal(t, tt.message, status.Convert(err).Message())
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateCardStatus_ActivationLocked(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Once activation is locked, not even the right code activates the card
+	mockDb.ExpectBegin()
+	expectLockInactiveCard(mockDb, "card-def", "INACTIVE", s.hashActivationCode("123456"), maxActivationAttempts)
+	mockDb.ExpectRollback()
+
+	req := &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: "ACTIVE", ActivationCode: "123456"}
+	resp, err := s.UpdateCardStatus(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestHashActivationCode(t *testing.T) {
+	s, _ := newTestServer(t)
+	defer s.db.Close()
+
+	// Codes are hashed under the activation key, so their hashes can't be matched without it
+	other := &server{activationKey: []byte("other-key")}
+	assert.NotEqual(t, s.hashActivationCode("123456"), other.hashActivationCode("123456"))
+	assert.True(t, s.activationCodeMatches(s.hashActivationCode("123456"), "123456"))
+	assert.False(t, s.activationCodeMatches(other.hashActivationCode("123456"), "123456"))
+}
+
+func TestUpdateCardStatus_CloseInactive(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// A card lost in the post is closed without its activation code
+	mockDb.ExpectBegin()
+	expectLockCard(mockDb, "card-def", "INACTIVE", s.hashActivationCode("123456"))
+	expectUpdateStatus(mockDb, "card-def", "INACTIVE", "CLOSED")
+	mockDb.ExpectCommit()
+
+	resp, err := s.UpdateCardStatus(context.Background(), &cardspb.UpdateCardStatusRequest{CardId: "card-def", NewStatus: "CLOSED", Reason: "never arrived"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CLOSED", resp.Status)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetCardHistory(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
+		WithArgs("card-def").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-def", "user-456", "FROZEN", "5678", "physical", 3, 2029, "token-1", "", "", 0, nil, "", testNow, "", false))
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(from_status, ''), to_status, reason, actor, changed_at FROM card_status_history
+			  WHERE card_id = $1 ORDER BY id`)).
+		WithArgs("card-def").
+		WillReturnRows(sqlmock.NewRows([]string{"from_status", "to_status", "reason", "actor", "changed_at"}).
+			AddRow("", "INACTIVE", "issued", "cards", testNow).
+			AddRow("INACTIVE", "ACTIVE", "", "user-456", testNow.Add(48*time.Hour)).
+			AddRow("ACTIVE", "FROZEN", "card misplaced", "user-456", testNow.Add(72*time.Hour)))
+
+	resp, err := s.GetCardHistory(context.Background(), &cardspb.GetCardRequest{CardId: "card-def"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Changes, 3)
+	assert.Empty(t, resp.Changes[0].FromStatus)
+	assert.Equal(t, "INACTIVE", resp.Changes[0].ToStatus)
+	assert.Equal(t, "2025-03-14T15:30:00Z", resp.Changes[0].ChangedAt)
+	assert.Equal(t, "card-def", resp.Changes[2].CardId)
+	assert.Equal(t, "card misplaced", resp.Changes[2].Reason)
+	assert.Equal(t, "user-456", resp.Changes[2].Actor)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetCardHistory_NotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
+		WithArgs("card-jkl").
+		WillReturnError(sql.ErrNoRows)
+
+	resp, err := s.GetCardHistory(context.Background(), &cardspb.GetCardRequest{CardId: "card-jkl"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreateCard_SingleUse(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.CreateCardRequest{
+		UserId:      "user-123",
+		CardType:    "virtual",
+		VirtualType: "single_use",
+		SpendCap:    5000,
+		ExpiresAt:   "2025-04-01T00:00:00Z",
+	}
+
+	mockDb.ExpectBegin()
+	expectIssueCardNumber(s, "45996510", "token-1", nil)
+	expectInsertVirtualCard(mockDb, req.UserId, "virtual", "token-1", 3, 2028, nil, "single_use", int64(5000), time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	expectCardEvent(mockDb, "card:created", "new-card-id")
+	mockDb.ExpectCommit()
+
+	resp, err := s.CreateCard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ACTIVE", resp.Status)
+	assert.Equal(t, "single_use", resp.VirtualType)
+	assert.Equal(t, int64(5000), resp.SpendCap)
+	assert.Equal(t, "2025-04-01T00:00:00Z", resp.ExpiresAt)
+	assert.Empty(t, resp.LockedMerchantId)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreateCard_InvalidVirtualRules(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *cardspb.CreateCardRequest
+	}{
+		{"physical single-use", &cardspb.CreateCardRequest{CardType: "physical", VirtualType: "single_use"}},
+		{"physical spend cap", &cardspb.CreateCardRequest{CardType: "physical", SpendCap: 5000}},
+		{"unknown virtual type", &cardspb.CreateCardRequest{CardType: "virtual", VirtualType: "multi_use"}},
+		{"negative spend cap", &cardspb.CreateCardRequest{CardType: "virtual", SpendCap: -1}},
+		{"invalid expiry", &cardspb.CreateCardRequest{CardType: "virtual", ExpiresAt: "next week"}},
+		{"expiry passed", &cardspb.CreateCardRequest{CardType: "virtual", ExpiresAt: "2025-03-14T15:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestServer(t) // No DB interaction expected
+			defer s.db.Close()
+
+			tt.req.UserId = "user-123"
+			resp, err := s.CreateCard(context.Background(), tt.req)
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestListVirtualCards(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(strings.Replace(getCardQuery, "WHERE card_id = $1", "WHERE user_id = $1 AND card_type = 'virtual' ORDER BY created_at", 1))).
+		WithArgs("user-123").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-1", "user-123", "CLOSED", "1111", "virtual", 3, 2028, "token-1", "", "single_use", 5000, testNow.Add(24*time.Hour), "", testNow, "", false).
+			AddRow("card-2", "user-123", "ACTIVE", "2222", "virtual", 3, 2028, "token-2", "", "merchant_locked", 0, nil, "merchant-1", testNow, "", false).
+			AddRow("card-3", "user-123", "ACTIVE", "3333", "virtual", 3, 2028, "token-3", "", "merchant_locked", 0, nil, "", testNow, "", false))
+
+	resp, err := s.ListVirtualCards(context.Background(), &cardspb.ListVirtualCardsRequest{UserId: "user-123"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Cards, 3)
+	assert.Equal(t, "2025-03-15T15:30:00Z", resp.Cards[0].ExpiresAt)
+	assert.Equal(t, int64(5000), resp.Cards[0].SpendCap)
+	assert.Equal(t, "merchant-1", resp.Cards[1].LockedMerchantId)
+	assert.Empty(t, resp.Cards[2].LockedMerchantId, "not yet used")
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// lockToMerchantQuery is the query LockCardToMerchant reads and locks a card with
+const lockToMerchantQuery = `SELECT COALESCE(virtual_type, ''), COALESCE(locked_merchant_id, '') FROM cards WHERE card_id = $1 FOR UPDATE`
+
+func TestLockCardToMerchant(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.LockCardToMerchantRequest{CardId: "card-2", MerchantId: "merchant-1"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockToMerchantQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows([]string{"virtual_type", "locked_merchant_id"}).AddRow("merchant_locked", ""))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET locked_merchant_id = $2, updated_at = NOW() WHERE card_id = $1`)).
+		WithArgs(req.CardId, req.MerchantId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-2", "user-123", "ACTIVE", "2222", "virtual", 3, 2028, "token-2", "", "merchant_locked", 0, nil, "merchant-1", testNow, "", false))
+
+	resp, err := s.LockCardToMerchant(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "merchant-1", resp.LockedMerchantId)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestLockCardToMerchant_Refused(t *testing.T) {
+	tests := []struct {
+		name             string
+		virtualType      string
+		lockedMerchantID string
+	}{
+		{"locked to another merchant", "merchant_locked", "merchant-2"},
+		{"not merchant-locked", "single_use", ""},
+		{"ordinary card", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectQuery(regexp.QuoteMeta(lockToMerchantQuery)).
+				WithArgs("card-2").
+				WillReturnRows(sqlmock.NewRows([]string{"virtual_type", "locked_merchant_id"}).AddRow(tt.virtualType, tt.lockedMerchantID))
+			mockDb.ExpectRollback()
+
+			resp, err := s.LockCardToMerchant(context.Background(), &cardspb.LockCardToMerchantRequest{CardId: "card-2", MerchantId: "merchant-1"})
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+// useSingleUseCardQuery is the statement UseSingleUseCard claims a card with
+const useSingleUseCardQuery = `UPDATE cards SET status = 'USED', is_default = FALSE, updated_at = NOW()
+			  WHERE card_id = $1 AND virtual_type = 'single_use' AND status = 'ACTIVE' RETURNING user_id`
+
+func TestUseSingleUseCard(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.UseSingleUseCardRequest{CardId: "card-2", TransactionId: "txn-1", Actor: "card-processing"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(useSingleUseCardQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-123"))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO card_status_history (card_id, from_status, to_status, reason, actor, changed_at)`)).
+		WithArgs(req.CardId, "ACTIVE", "USED", "used for transaction txn-1", "card-processing").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectCardEvent(mockDb, "card:status_changed", req.CardId)
+	mockDb.ExpectCommit()
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-2", "user-123", "USED", "2222", "virtual", 3, 2028, "token-2", "", "single_use", 0, nil, "", testNow, "", false))
+
+	resp, err := s.UseSingleUseCard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USED", resp.Status)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUseSingleUseCard_AlreadyUsed(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Only one payment gets to claim the card: for the other, no card is ACTIVE to update
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(useSingleUseCardQuery)).
+		WithArgs("card-2").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mockDb.ExpectRollback()
+
+	resp, err := s.UseSingleUseCard(context.Background(), &cardspb.UseSingleUseCardRequest{CardId: "card-2", TransactionId: "txn-2"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListCardsByUser(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// A page of two cards asks for a third to tell whether there is another page
+	query := strings.Replace(getCardQuery, "WHERE card_id = $1", `WHERE user_id = $1 AND status IN ($2, $3)
+		AND (created_at, card_id) < (SELECT created_at, card_id FROM cards WHERE card_id = $4 AND user_id = $1)
+		ORDER BY created_at DESC, card_id DESC LIMIT 3`, 1)
+	mockDb.ExpectQuery(regexp.QuoteMeta(strings.Join(strings.Fields(query), " "))).
+		WithArgs("user-123", "ACTIVE", "FROZEN", "card-0").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-3", "user-123", "ACTIVE", "3333", "virtual", 3, 2028, "token-3", "", "", 0, nil, "", testNow, "Online shopping", true).
+			AddRow("card-2", "user-123", "FROZEN", "2222", "physical", 3, 2029, "token-2", "", "", 0, nil, "", testNow, "", false).
+			AddRow("card-1", "user-123", "ACTIVE", "1111", "physical", 3, 2029, "token-1", "", "", 0, nil, "", testNow, "", false))
+
+	req := &cardspb.ListCardsByUserRequest{UserId: "user-123", Statuses: []string{"ACTIVE", "FROZEN"}, Limit: 2, BeforeId: "card-0"}
+	resp, err := s.ListCardsByUser(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Cards, 2)
+	assert.Equal(t, "Online shopping", resp.Cards[0].Nickname)
+	assert.True(t, resp.Cards[0].IsDefault)
+	assert.Equal(t, "card-2", resp.NextBeforeId)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListCardsByUser_LastPage(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`WHERE user_id = $1 ORDER BY created_at DESC, card_id DESC LIMIT 51`)).
+		WithArgs("user-123").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-1", "user-123", "ACTIVE", "1111", "physical", 3, 2029, "token-1", "", "", 0, nil, "", testNow, "", false))
+
+	resp, err := s.ListCardsByUser(context.Background(), &cardspb.ListCardsByUserRequest{UserId: "user-123"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Cards, 1)
+	assert.Empty(t, resp.NextBeforeId)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListCardsByUser_InvalidStatus(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	resp, err := s.ListCardsByUser(context.Background(), &cardspb.ListCardsByUserRequest{UserId: "user-123", Statuses: []string{"LOST"}})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSetCardNickname(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET nickname = $2, updated_at = NOW() WHERE card_id = $1`)).
+		WithArgs("card-abc", "Bills").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
+		WithArgs("card-abc").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-abc", "user-123", "ACTIVE", "1234", "physical", 9, 2028, "token-1", "", "", 0, nil, "", testNow, "Bills", false))
+
+	resp, err := s.SetCardNickname(context.Background(), &cardspb.SetCardNicknameRequest{CardId: "card-abc", Nickname: "  Bills "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bills", resp.Nickname)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestSetCardNickname_NotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// An empty nickname removes it
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET nickname = $2, updated_at = NOW() WHERE card_id = $1`)).
+		WithArgs("card-xyz", nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	resp, err := s.SetCardNickname(context.Background(), &cardspb.SetCardNicknameRequest{CardId: "card-xyz"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestSetCardNickname_TooLong(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	req := &cardspb.SetCardNicknameRequest{CardId: "card-abc", Nickname: strings.Repeat("é", maxNicknameLength+1)}
+	resp, err := s.SetCardNickname(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// lockDefaultCardQuery is the query SetDefaultCard reads and locks a card with
+const lockDefaultCardQuery = `SELECT user_id, status, is_default FROM cards WHERE card_id = $1 FOR UPDATE`
+
+func TestSetDefaultCard(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockDefaultCardQuery)).
+		WithArgs("card-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status", "is_default"}).AddRow("user-123", "FROZEN", false))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET is_default = FALSE, updated_at = NOW() WHERE user_id = $1 AND is_default`)).
+		WithArgs("user-123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET is_default = TRUE, updated_at = NOW() WHERE card_id = $1`)).
+		WithArgs("card-abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardQuery)).
+		WithArgs("card-abc").
+		WillReturnRows(sqlmock.NewRows(cardColumnNames).
+			AddRow("card-abc", "user-123", "FROZEN", "1234", "physical", 9, 2028, "token-1", "", "", 0, nil, "", testNow, "", true))
+
+	resp, err := s.SetDefaultCard(context.Background(), &cardspb.SetDefaultCardRequest{CardId: "card-abc"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.IsDefault)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestSetDefaultCard_Closed(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockDefaultCardQuery)).
+		WithArgs("card-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status", "is_default"}).AddRow("user-123", "CLOSED", false))
+	mockDb.ExpectRollback()
+
+	resp, err := s.SetDefaultCard(context.Background(), &cardspb.SetDefaultCardRequest{CardId: "card-abc"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// getCardControlsQuery is the query GetCardControls reads a card's controls with
+const getCardControlsQuery = `SELECT c.card_id, COALESCE(cc.daily_limit, 0), COALESCE(cc.monthly_limit, 0), COALESCE(cc.per_transaction_limit, 0),
+			  COALESCE(cc.blocked_mccs, ''), COALESCE(cc.online_disabled, FALSE), COALESCE(cc.contactless_disabled, FALSE),
+			  COALESCE(cc.atm_disabled, FALSE), COALESCE(cc.magstripe_disabled, FALSE)
+			  FROM cards c LEFT JOIN card_controls cc ON cc.card_id = c.card_id WHERE c.card_id = $1`
+
+var cardControlsColumns = []string{"card_id", "daily_limit", "monthly_limit", "per_transaction_limit", "blocked_mccs",
+	"online_disabled", "contactless_disabled", "atm_disabled", "magstripe_disabled"}
+
+func TestGetCardControls(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.GetCardRequest{CardId: "card-abc"}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardControlsQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(cardControlsColumns).
+			AddRow(req.CardId, int64(5000), int64(0), int64(2500), "7801,7995", true, false, false, true))
+
+	resp, err := s.GetCardControls(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, req.CardId, resp.CardId)
+	assert.Equal(t, int64(5000), resp.DailyLimit)
+	assert.Equal(t, int64(0), resp.MonthlyLimit)
+	assert.Equal(t, int64(2500), resp.PerTransactionLimit)
+	assert.Equal(t, []int32{7801, 7995}, resp.BlockedMccs)
+	assert.True(t, resp.OnlineDisabled)
+	assert.False(t, resp.ContactlessDisabled)
+	assert.True(t, resp.MagstripeDisabled)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetCardControls_NotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.GetCardRequest{CardId: "card-xyz"}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(getCardControlsQuery)).
+		WithArgs(req.CardId).
+		WillReturnError(sql.ErrNoRows)
+
+	resp, err := s.GetCardControls(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateCardControls(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.CardControls{
+		CardId:         "card-abc",
+		DailyLimit:     5000,
+		BlockedMccs:    []int32{7995, 7801, 7995},
+		OnlineDisabled: true,
+	}
+
+	// Merchant categories are stored sorted, without duplicates
+	mockDb.ExpectQuery(`INSERT INTO card_controls .* SELECT card_id, .* FROM cards WHERE card_id = \$1\s+ON CONFLICT \(card_id\) DO UPDATE`).
+		WithArgs(req.CardId, int64(5000), int64(0), int64(0), "7801,7995", true, false, false, false).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id"}).AddRow(req.CardId))
+
+	resp, err := s.UpdateCardControls(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, req.CardId, resp.CardId)
+	assert.Equal(t, []int32{7801, 7995}, resp.BlockedMccs)
+	assert.True(t, resp.OnlineDisabled)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateCardControls_NotFound(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.CardControls{CardId: "card-xyz", DailyLimit: 5000}
+
+	mockDb.ExpectQuery(`INSERT INTO card_controls`).
+		WillReturnError(sql.ErrNoRows)
+
+	resp, err := s.UpdateCardControls(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestUpdateCardControls_Invalid(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	for _, req := range []*cardspb.CardControls{
+		{CardId: "card-abc", DailyLimit: -1},
+		{CardId: "card-abc", BlockedMccs: []int32{0}},
+		{CardId: "card-abc", BlockedMccs: []int32{10000}},
+	} {
+		resp, err := s.UpdateCardControls(context.Background(), req)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%+v", req)
+	}
+}
+
+// reissueLockQuery is the query ReissueCard reads and locks the card to replace with
+const reissueLockQuery = `SELECT user_id, card_type, status, COALESCE(virtual_type, ''), spend_cap, expires_at, COALESCE(locked_merchant_id, ''),
+			  COALESCE(nickname, ''), is_default FROM cards WHERE card_id = $1 FOR UPDATE`
+
+var reissueLockColumns = []string{"user_id", "card_type", "status", "virtual_type", "spend_cap", "expires_at", "locked_merchant_id",
+	"nickname", "is_default"}
+
+// reissueCloseQuery is the statement ReissueCard closes the card it replaces with
+const reissueCloseQuery = `UPDATE cards SET status = 'CLOSED', is_default = FALSE, updated_at = NOW() WHERE card_id = $1`
+
+func TestReissueCard(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.ReissueCardRequest{CardId: "card-old", Reason: "STOLEN"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(reissueLockQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(reissueLockColumns).AddRow("user-123", "physical", "FROZEN", "", 0, nil, "", "", false))
+	expectIssueCardNumber(s, "45996500", "token-2", nil)
+	expectInsertCard(mockDb, "user-123", "physical", "token-2", 3, 2029, req.CardId)
+	expectCarryCardLink(s, "new-card-id", req.CardId, nil)
+	mockDb.ExpectExec(regexp.QuoteMeta(reissueCloseQuery)).
+		WithArgs(req.CardId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectStatusChange(mockDb, req.CardId, "FROZEN", "CLOSED")
+
+	// Controls and recurring merchants carry over to the new card
+	mockDb.ExpectExec(`INSERT INTO card_controls .* SELECT \$2, .* FROM card_controls WHERE card_id = \$1`).
+		WithArgs(req.CardId, "new-card-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE recurring_merchants SET card_id = $2 WHERE card_id = $1`)).
+		WithArgs(req.CardId, "new-card-id").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	expectCardEvent(mockDb, "card:status_changed", req.CardId)
+	expectCardEvent(mockDb, "card:created", "new-card-id")
+	mockDb.ExpectCommit()
+
+	resp, err := s.ReissueCard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-card-id", resp.CardId)
+	assert.Equal(t, "user-123", resp.UserId)
+	assert.Equal(t, "INACTIVE", resp.Status)
+	assert.Len(t, resp.ActivationCode, 6)
+	assert.Equal(t, req.CardId, resp.ReplacesCardId)
+	assert.Equal(t, "token-2", resp.PanToken)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReissueCard_Closed(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.ReissueCardRequest{CardId: "card-old", Reason: "LOST"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(reissueLockQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(reissueLockColumns).AddRow("user-123", "physical", "CLOSED", "", 0, nil, "", "", false))
+	mockDb.ExpectRollback()
+
+	resp, err := s.ReissueCard(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReissueCard_CarriesNicknameAndDefault(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.ReissueCardRequest{CardId: "card-old", Reason: "DAMAGED"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(reissueLockQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(reissueLockColumns).AddRow("user-123", "virtual", "ACTIVE", "", 0, nil, "", "Subscriptions", true))
+	expectIssueCardNumber(s, "45996510", "token-2", nil)
+	expectInsertCard(mockDb, "user-123", "virtual", "token-2", 3, 2028, req.CardId)
+	expectCarryCardLink(s, "new-card-id", req.CardId, nil)
+	mockDb.ExpectExec(regexp.QuoteMeta(reissueCloseQuery)).
+		WithArgs(req.CardId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectStatusChange(mockDb, req.CardId, "ACTIVE", "CLOSED")
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE cards SET nickname = $2, is_default = $3 WHERE card_id = $1`)).
+		WithArgs("new-card-id", "Subscriptions", true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(`INSERT INTO card_controls`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE recurring_merchants`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectCardEvent(mockDb, "card:status_changed", req.CardId)
+	expectCardEvent(mockDb, "card:created", "new-card-id")
+	mockDb.ExpectCommit()
+
+	resp, err := s.ReissueCard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Subscriptions", resp.Nickname)
+	assert.True(t, resp.IsDefault)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReissueCard_CarryCardLinkFails(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.ReissueCardRequest{CardId: "card-old", Reason: "LOST"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(reissueLockQuery)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows(reissueLockColumns).AddRow("user-123", "physical", "ACTIVE", "", 0, nil, "", "", false))
+	expectIssueCardNumber(s, "45996500", "token-2", nil)
+	expectInsertCard(mockDb, "user-123", "physical", "token-2", 3, 2029, req.CardId)
+	expectCarryCardLink(s, "new-card-id", req.CardId, status.Error(codes.Unavailable, "accounts unavailable"))
+	mockDb.ExpectRollback()
+
+	resp, err := s.ReissueCard(context.Background(), req)
+
+	// The old card stays open, so the user can try again
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestReissueCard_InvalidReason(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	resp, err := s.ReissueCard(context.Background(), &cardspb.ReissueCardRequest{CardId: "card-old", Reason: "BORED"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAddRecurringMerchant(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.RecurringMerchant{CardId: "card-abc", MerchantId: "merchant-1", MerchantName: "Streaming Co"}
+
+	mockDb.ExpectQuery(`INSERT INTO recurring_merchants .* SELECT card_id, \$2, \$3, NOW\(\) FROM cards WHERE card_id = \$1`).
+		WithArgs(req.CardId, req.MerchantId, req.MerchantName).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id"}).AddRow(req.CardId))
+
+	resp, err := s.AddRecurringMerchant(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, req.MerchantId, resp.MerchantId)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestListRecurringMerchants(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT card_id, merchant_id, merchant_name FROM recurring_merchants WHERE card_id = $1 ORDER BY created_at`)).
+		WithArgs("card-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "merchant_id", "merchant_name"}).
+			AddRow("card-abc", "merchant-1", "Streaming Co").
+			AddRow("card-abc", "merchant-2", "Gym"))
+
+	resp, err := s.ListRecurringMerchants(context.Background(), &cardspb.GetCardRequest{CardId: "card-abc"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Merchants, 2)
+	assert.Equal(t, "Gym", resp.Merchants[1].MerchantName)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// lockPinQuery is the query the PIN state of a card is read and locked with
+const lockPinQuery = `SELECT c.user_id, COALESCE(c.pan_token, ''), p.failed_attempts, p.locked FROM card_pins p
+			  JOIN cards c ON c.card_id = p.card_id WHERE p.card_id = $1 FOR UPDATE OF p`
+
+// expectLockPin expects the PIN state of card-abc to be read and locked
+func expectLockPin(mockDb sqlmock.Sqlmock, failedAttempts int32, locked bool) {
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockPinQuery)).
+		WithArgs("card-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "pan_token", "failed_attempts", "locked"}).
+			AddRow("user-123", "token-1", failedAttempts, locked))
+}
+
+// expectUpdatePin expects the PIN state of card-abc to be updated
+func expectUpdatePin(mockDb sqlmock.Sqlmock, failedAttempts int32, locked bool) {
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE card_pins SET failed_attempts = $2, locked = $3, updated_at = NOW() WHERE card_id = $1`)).
+		WithArgs("card-abc", failedAttempts, locked).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// testPinBlock returns a PIN block carrying pin
+func testPinBlock(t *testing.T, pin string) string {
+	block, err := pinblock.Encode(pin)
+	assert.NoError(t, err)
+	return block
+}
+
+func TestSetPin(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.SetPinRequest{CardId: "card-abc", PinBlock: testPinBlock(t, "1234")}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(pan_token, ''), status FROM cards WHERE card_id = $1`)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows([]string{"pan_token", "status"}).AddRow("token-1", "ACTIVE"))
+	mockDb.ExpectExec(`INSERT INTO card_pins .* ON CONFLICT \(card_id\) DO NOTHING`).
+		WithArgs(req.CardId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.vaultClient.(*mockVaultClient).On("SetPinBlock", mock.Anything, &vaultpb.SetPinBlockRequest{Token: "token-1", PinBlock: req.PinBlock}).
+		Return(&vaultpb.SetPinBlockResponse{}, nil).Once()
+	mockDb.ExpectCommit()
+
+	resp, err := s.SetPin(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(maxPinAttempts), resp.AttemptsRemaining)
+	assert.False(t, resp.Locked)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	s.vaultClient.(*mockVaultClient).AssertExpectations(t)
+}
+
+func TestSetPin_AlreadySet(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.SetPinRequest{CardId: "card-abc", PinBlock: testPinBlock(t, "1234")}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(pan_token, ''), status FROM cards WHERE card_id = $1`)).
+		WithArgs(req.CardId).
+		WillReturnRows(sqlmock.NewRows([]string{"pan_token", "status"}).AddRow("token-1", "ACTIVE"))
+	mockDb.ExpectExec(`INSERT INTO card_pins .* ON CONFLICT \(card_id\) DO NOTHING`).
+		WithArgs(req.CardId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectRollback()
+
+	resp, err := s.SetPin(context.Background(), req)
+
+	// Changing a PIN takes the current one
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	s.vaultClient.(*mockVaultClient).AssertNotCalled(t, "SetPinBlock", mock.Anything, mock.Anything)
+}
+
+func TestSetPin_InvalidPinBlock(t *testing.T) {
+	s, _ := newTestServer(t) // No DB interaction expected
+	defer s.db.Close()
+
+	resp, err := s.SetPin(context.Background(), &cardspb.SetPinRequest{CardId: "card-abc", PinBlock: "1234"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestChangePin_Incorrect(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.ChangePinRequest{CardId: "card-abc", CurrentPinBlock: testPinBlock(t, "0000"), NewPinBlock: testPinBlock(t, "4321")}
+
+	mockDb.ExpectBegin()
+	expectLockPin(mockDb, 0, false)
+	s.vaultClient.(*mockVaultClient).On("VerifyPinBlock", mock.Anything, &vaultpb.VerifyPinBlockRequest{Token: "token-1", PinBlock: req.CurrentPinBlock}).
+		Return(&vaultpb.VerifyPinBlockResponse{Match: false}, nil).Once()
+	expectUpdatePin(mockDb, 1, false)
+	mockDb.ExpectCommit() // The wrong PIN counts
+
+	resp, err := s.ChangePin(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "2 attempts remaining")
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	s.vaultClient.(*mockVaultClient).AssertNotCalled(t, "SetPinBlock", mock.Anything, mock.Anything)
+}
+
+func TestVerifyPin(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.VerifyPinRequest{CardId: "card-abc", PinBlock: testPinBlock(t, "1234")}
+
+	// A right PIN clears earlier wrong ones
+	mockDb.ExpectBegin()
+	expectLockPin(mockDb, 2, false)
+	s.vaultClient.(*mockVaultClient).On("VerifyPinBlock", mock.Anything, &vaultpb.VerifyPinBlockRequest{Token: "token-1", PinBlock: req.PinBlock}).
+		Return(&vaultpb.VerifyPinBlockResponse{Match: true}, nil).Once()
+	expectUpdatePin(mockDb, 0, false)
+	mockDb.ExpectCommit()
+
+	resp, err := s.VerifyPin(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Verified)
+	assert.Equal(t, int32(maxPinAttempts), resp.AttemptsRemaining)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestVerifyPin_LocksAfterMaxAttempts(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	req := &cardspb.VerifyPinRequest{CardId: "card-abc", PinBlock: testPinBlock(t, "0000")}
+
+	mockDb.ExpectBegin()
+	expectLockPin(mockDb, maxPinAttempts-1, false)
+	s.vaultClient.(*mockVaultClient).On("VerifyPinBlock", mock.Anything, &vaultpb.VerifyPinBlockRequest{Token: "token-1", PinBlock: req.PinBlock}).
+		Return(&vaultpb.VerifyPinBlockResponse{Match: false}, nil).Once()
+	expectUpdatePin(mockDb, maxPinAttempts, true)
+	expectCardEvent(mockDb, "card:pin_locked", req.CardId)
+	mockDb.ExpectCommit()
+
+	resp, err := s.VerifyPin(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Verified)
+	assert.True(t, resp.Locked)
+	assert.Zero(t, resp.AttemptsRemaining)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestVerifyPin_Locked(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	// Once locked, not even the right PIN is checked
+	mockDb.ExpectBegin()
+	expectLockPin(mockDb, maxPinAttempts, true)
+	mockDb.ExpectRollback()
+
+	resp, err := s.VerifyPin(context.Background(), &cardspb.VerifyPinRequest{CardId: "card-abc", PinBlock: testPinBlock(t, "1234")})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Locked)
+	assert.False(t, resp.Verified)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	s.vaultClient.(*mockVaultClient).AssertNotCalled(t, "VerifyPinBlock", mock.Anything, mock.Anything)
+}
+
+func TestVerifyPin_NoPin(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(lockPinQuery)).
+		WithArgs("card-abc").
+		WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectRollback()
+
+	resp, err := s.VerifyPin(context.Background(), &cardspb.VerifyPinRequest{CardId: "card-abc", PinBlock: testPinBlock(t, "1234")})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRevealPin(t *testing.T) {
+	s, mockDb := newTestServer(t)
+	defer s.db.Close()
+
+	token := sca.Issue(testSCAKey, "user-123", revealPinAction("card-abc"), testNow.Add(5*time.Minute))
+
+	// Revealing a locked PIN unlocks it
+	mockDb.ExpectBegin()
+	expectLockPin(mockDb, maxPinAttempts, true)
+	s.vaultClient.(*mockVaultClient).On("Detokenize", mock.Anything, &vaultpb.DetokenizeRequest{Token: "token-1", Purpose: "pin_reveal"}).
+		Return(&vaultpb.CardData{PinBlock: testPinBlock(t, "1234")}, nil).Once()
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE card_pins SET failed_attempts = 0, locked = FALSE, updated_at = NOW() WHERE card_id = $1`)).
+		WithArgs("card-abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	resp, err := s.RevealPin(context.Background(), &cardspb.RevealPinRequest{CardId: "card-abc", ScaToken: token})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", resp.Pin)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRevealPin_SCAFailed(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"other user", sca.Issue(testSCAKey, "user-456", revealPinAction("card-abc"), testNow.Add(5*time.Minute))},
+		{"other card", sca.Issue(testSCAKey, "user-123", revealPinAction("card-xyz"), testNow.Add(5*time.Minute))},
+		{"expired", sca.Issue(testSCAKey, "user-123", revealPinAction("card-abc"), testNow.Add(-time.Minute))},
+		{"other key", sca.Issue([]byte("other-key"), "user-123", revealPinAction("card-abc"), testNow.Add(5*time.Minute))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockDb := newTestServer(t)
+			defer s.db.Close()
+
+			mockDb.ExpectBegin()
+			expectLockPin(mockDb, 0, false)
+			mockDb.ExpectRollback()
+
+			resp, err := s.RevealPin(context.Background(), &cardspb.RevealPinRequest{CardId: "card-abc", ScaToken: tt.token})
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			assert.NoError(t, mockDb.ExpectationsWereMet())
+			s.vaultClient.(*mockVaultClient).AssertNotCalled(t, "Detokenize", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLoadIssuingConfig(t *testing.T) {
+	cfg, err := loadIssuingConfig("../../internal/cards/issuing.json")
+	assert.NoError(t, err)
+
+	// Every card type can be issued
+	s := &issuer{ranges: cfg.BINRanges, now: time.Now}
+	for _, cardType := range []string{cardTypePhysical, cardTypeVirtual} {
+		issued, err := s.issue(cardType)
+		assert.NoError(t, err, cardType)
+		assert.Equal(t, cardType, issued.binRange.CardType)
+	}
+}
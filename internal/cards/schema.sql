@@ -1,11 +1,80 @@
 CREATE TABLE cards (
     card_id UUID PRIMARY KEY,
     user_id UUID NOT NULL,
-    status TEXT NOT NULL CHECK (status IN ('ACTIVE','INACTIVE','FROZEN','CLOSED')),
-    pan_hash TEXT, -- store hashed PAN or token; allow null if not stored
+    card_type TEXT NOT NULL DEFAULT 'physical' CHECK (card_type IN ('physical','virtual')),
+    status TEXT NOT NULL CHECK (status IN ('ACTIVE','INACTIVE','FROZEN','USED','CLOSED')), -- USED: a single-use card that has paid
+    pan_token TEXT UNIQUE, -- vault token of the PAN and CVV; only the vault stores them
     last_four TEXT,
+    expiry_month INT CHECK (expiry_month BETWEEN 1 AND 12),
+    expiry_year INT,
+    replaces_card_id UUID REFERENCES cards(card_id), -- card this one was reissued to replace
+    activation_code_hash TEXT, -- HMAC-SHA256, under the activation key, of the code an INACTIVE physical card is activated with; cleared once it leaves INACTIVE
+    activation_failed_attempts INT NOT NULL DEFAULT 0, -- wrong activation codes; three lock activation, and the card must be replaced
+    virtual_type TEXT CHECK (virtual_type IN ('single_use','merchant_locked')), -- virtual cards only, NULL for an ordinary card
+    spend_cap BIGINT NOT NULL DEFAULT 0 CHECK (spend_cap >= 0), -- total the card can ever spend, 0 for no cap
+    expires_at TIMESTAMP, -- when a virtual card stops approving payments, ahead of its expiry month
+    locked_merchant_id TEXT, -- merchant a merchant_locked card is locked to, NULL until its first payment
+    nickname TEXT, -- name the cardholder gave the card
+    is_default BOOLEAN NOT NULL DEFAULT FALSE, -- the card the cardholder pays with by default; cleared when it closes
     created_at TIMESTAMP NOT NULL DEFAULT now(),
     updated_at TIMESTAMP
 );
 
-CREATE INDEX cards_user_id_idx ON cards(user_id);
+CREATE INDEX cards_user_id_idx ON cards(user_id, created_at);
+CREATE UNIQUE INDEX cards_user_default_idx ON cards(user_id) WHERE is_default;
+
+-- Every change of a card's status, from when it was issued
+CREATE TABLE card_status_history (
+    id BIGSERIAL PRIMARY KEY, -- change order
+    card_id UUID NOT NULL REFERENCES cards(card_id),
+    from_status TEXT, -- NULL when the card was issued
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '', -- who made the change, e.g. the cardholder's user ID, 'support' or 'cards'
+    changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX card_status_history_card_id_idx ON card_status_history(card_id, id);
+
+-- Spending controls set on a card; a card without a row has none
+CREATE TABLE card_controls (
+    card_id UUID PRIMARY KEY REFERENCES cards(card_id),
+    daily_limit BIGINT NOT NULL DEFAULT 0 CHECK (daily_limit >= 0), -- minor units of the account's currency, 0 for no limit
+    monthly_limit BIGINT NOT NULL DEFAULT 0 CHECK (monthly_limit >= 0),
+    per_transaction_limit BIGINT NOT NULL DEFAULT 0 CHECK (per_transaction_limit >= 0),
+    blocked_mccs TEXT NOT NULL DEFAULT '', -- comma-separated merchant category codes, e.g. '7995,7801'
+    online_disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    contactless_disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    atm_disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    magstripe_disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Merchants holding a card's details for recurring payments, moved to the new card when it is reissued
+CREATE TABLE recurring_merchants (
+    card_id UUID NOT NULL REFERENCES cards(card_id),
+    merchant_id TEXT NOT NULL,
+    merchant_name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (card_id, merchant_id)
+);
+
+-- PIN state of cards that have a PIN; the PIN block itself is only stored, encrypted, in the vault
+CREATE TABLE card_pins (
+    card_id UUID PRIMARY KEY REFERENCES cards(card_id),
+    failed_attempts INT NOT NULL DEFAULT 0, -- wrong PINs in a row
+    locked BOOLEAN NOT NULL DEFAULT FALSE, -- set after three wrong PINs, until the cardholder reveals the PIN
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY, -- publish order
+    stream TEXT NOT NULL, -- Redis stream the event is published to, e.g. 'balance:updated'
+    aggregate_id TEXT NOT NULL, -- entity the event is about; events of one aggregate are published in order
+    payload TEXT NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}', -- extra stream fields published with the payload, e.g. schema_version
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    sent_at TIMESTAMP -- NULL until the relay has published the event
+);
+
+CREATE INDEX outbox_unsent_idx ON outbox(id) WHERE sent_at IS NULL;
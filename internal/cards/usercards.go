@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cardspb "github.com/sambacha/monzo/v2/cards/cards"
+)
+
+// Page sizes of ListCardsByUser
+const (
+	defaultCardsLimit = 50
+	maxCardsLimit     = 100
+)
+
+// maxNicknameLength is the most characters a card's nickname can have
+const maxNicknameLength = 40
+
+// ListCardsByUser lists a user's cards, newest first, optionally only those with some statuses. Pages
+// follow on from the card given as before_id; one that isn't the user's card lists nothing.
+func (s *server) ListCardsByUser(ctx context.Context, req *cardspb.ListCardsByUserRequest) (*cardspb.CardList, error) {
+	log.Printf("Received ListCardsByUser request: %+v", req)
+
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id is required")
+	}
+
+	query := `SELECT ` + cardColumns + ` FROM cards WHERE user_id = $1`
+	args := []interface{}{req.GetUserId()}
+
+	if len(req.GetStatuses()) > 0 {
+		placeholders := make([]string, len(req.GetStatuses()))
+		for i, cardStatus := range req.GetStatuses() {
+			if _, ok := cardTransitions[cardStatus]; !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid card status: %s", cardStatus)
+			}
+			args = append(args, cardStatus)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		query += ` AND status IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+
+	// Add pagination. Cards issued in the same transaction share created_at, so card_id breaks ties.
+	if req.GetBeforeId() != "" {
+		args = append(args, req.GetBeforeId())
+		query += fmt.Sprintf(` AND (created_at, card_id) < (SELECT created_at, card_id FROM cards WHERE card_id = $%d AND user_id = $1)`, len(args))
+	}
+
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultCardsLimit
+	}
+	if limit > maxCardsLimit {
+		limit = maxCardsLimit
+	}
+	// One card more than the page tells whether there is another page
+	query += fmt.Sprintf(` ORDER BY created_at DESC, card_id DESC LIMIT %d`, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to list cards: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list cards")
+	}
+	defer rows.Close()
+
+	cards := &cardspb.CardList{}
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			log.Printf("failed to scan card: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list cards")
+		}
+		cards.Cards = append(cards.Cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to list cards: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list cards")
+	}
+
+	if len(cards.Cards) > limit {
+		cards.Cards = cards.Cards[:limit]
+		cards.NextBeforeId = cards.Cards[limit-1].GetCardId()
+	}
+	return cards, nil
+}
+
+// SetCardNickname names a card, or removes its name if the nickname is empty. Surrounding spaces
+// are dropped.
+func (s *server) SetCardNickname(ctx context.Context, req *cardspb.SetCardNicknameRequest) (*cardspb.Card, error) {
+	log.Printf("Received SetCardNickname request: %+v", req)
+
+	nickname := strings.TrimSpace(req.GetNickname())
+	if utf8.RuneCountInString(nickname) > maxNicknameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "nickname can't be longer than %d characters", maxNicknameLength)
+	}
+
+	query := `UPDATE cards SET nickname = $2, updated_at = NOW() WHERE card_id = $1`
+	result, err := s.db.ExecContext(ctx, query, req.GetCardId(), sql.NullString{String: nickname, Valid: nickname != ""})
+	if err != nil {
+		log.Printf("failed to set card nickname: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set card nickname")
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to set card nickname: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set card nickname")
+	}
+	if n == 0 {
+		log.Printf("card not found to name: %s", req.GetCardId())
+		return nil, status.Errorf(codes.NotFound, "card not found")
+	}
+
+	return s.getCard(ctx, "card_id", req.GetCardId())
+}
+
+// SetDefaultCard makes a card its user's default card, so it is no longer any other card's. Closed
+// cards can't be made the default, and stop being it when they close. Of two cards of a user made
+// the default at once, the unique index on default cards fails the second.
+func (s *server) SetDefaultCard(ctx context.Context, req *cardspb.SetDefaultCardRequest) (*cardspb.Card, error) {
+	log.Printf("Received SetDefaultCard request: %+v", req)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set default card")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Lock the card so it can't close while it becomes the default
+	var userID, cardStatus string
+	var isDefault bool
+	query := `SELECT user_id, status, is_default FROM cards WHERE card_id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, req.GetCardId()).Scan(&userID, &cardStatus, &isDefault)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found to make default: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to get card to make default: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to set default card")
+	}
+	if cardStatus == "CLOSED" || cardStatus == "USED" {
+		return nil, status.Errorf(codes.FailedPrecondition, "card is %s", strings.ToLower(cardStatus))
+	}
+
+	if !isDefault {
+		// The user's previous default goes first, as a user can only have one
+		clearDefault := `UPDATE cards SET is_default = FALSE, updated_at = NOW() WHERE user_id = $1 AND is_default`
+		if _, err := tx.ExecContext(ctx, clearDefault, userID); err != nil {
+			log.Printf("failed to clear default card: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to set default card")
+		}
+		update := `UPDATE cards SET is_default = TRUE, updated_at = NOW() WHERE card_id = $1`
+		if _, err := tx.ExecContext(ctx, update, req.GetCardId()); err != nil {
+			log.Printf("failed to set default card: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to set default card")
+		}
+		if err := tx.Commit(); err != nil {
+			log.Printf("failed to commit transaction: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to set default card")
+		}
+		log.Printf("Card %s is now the default card of user %s", req.GetCardId(), userID)
+	}
+
+	return s.getCard(ctx, "card_id", req.GetCardId())
+}
+
+func (s *server) listCardsByUserHandler(c echo.Context) error {
+	req := &cardspb.ListCardsByUserRequest{
+		UserId:   c.Param("user_id"),
+		Statuses: c.QueryParams()["status"],
+		BeforeId: c.QueryParam("before_id"),
+	}
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.ParseUint(limitStr, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit parameter"})
+		}
+		req.Limit = uint32(limit)
+	}
+
+	cards, err := s.ListCardsByUser(c.Request().Context(), req)
+	if err != nil {
+		return cardErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, cards)
+}
+
+func (s *server) setCardNicknameHandler(c echo.Context) error {
+	var nicknameReq struct {
+		Nickname string `json:"nickname"`
+	}
+	if err := c.Bind(&nicknameReq); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	card, err := s.SetCardNickname(c.Request().Context(), &cardspb.SetCardNicknameRequest{CardId: c.Param("id"), Nickname: nicknameReq.Nickname})
+	if err != nil {
+		return cardErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, card)
+}
+
+func (s *server) setDefaultCardHandler(c echo.Context) error {
+	card, err := s.SetDefaultCard(c.Request().Context(), &cardspb.SetDefaultCardRequest{CardId: c.Param("id")})
+	if err != nil {
+		return cardErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, card)
+}
+
+// cardErrorResponse maps a gRPC error from a Cards method to an HTTP error response
+func cardErrorResponse(c echo.Context, err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": status.Convert(err).Message()})
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": status.Convert(err).Message()})
+	case codes.FailedPrecondition:
+		return c.JSON(http.StatusConflict, map[string]string{"error": status.Convert(err).Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
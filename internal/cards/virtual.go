@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+
+	cardspb "github.com/sambacha/monzo/v2/cards/cards"
+)
+
+// Kinds of virtual card, as given in CreateCardRequest.virtual_type. Card-processing enforces what
+// they allow.
+const (
+	virtualTypeSingleUse      = "single_use"      // USED by its first approved payment, see UseSingleUseCard
+	virtualTypeMerchantLocked = "merchant_locked" // only pays the merchant that first used it
+)
+
+// virtualRules are the rules a virtual card is issued with. The zero value is an ordinary card.
+type virtualRules struct {
+	virtualType      string
+	spendCap         int64 // minor units of the account's currency, 0 for no cap
+	expiresAt        sql.NullTime
+	lockedMerchantID string // carried over to the replacement of a merchant-locked card
+}
+
+// virtualRulesFromRequest checks the virtual card rules of a CreateCard request
+func virtualRulesFromRequest(req *cardspb.CreateCardRequest, cardType string, now time.Time) (virtualRules, error) {
+	var rules virtualRules
+	if req.GetVirtualType() == "" && req.GetSpendCap() == 0 && req.GetExpiresAt() == "" {
+		return rules, nil
+	}
+	if cardType != cardTypeVirtual {
+		return rules, status.Errorf(codes.InvalidArgument, "only virtual cards can be single-use, merchant-locked, capped or expire early")
+	}
+
+	switch req.GetVirtualType() {
+	case "", virtualTypeSingleUse, virtualTypeMerchantLocked:
+		rules.virtualType = req.GetVirtualType()
+	default:
+		return rules, status.Errorf(codes.InvalidArgument, "invalid virtual card type: %s", req.GetVirtualType())
+	}
+	if req.GetSpendCap() < 0 {
+		return rules, status.Errorf(codes.InvalidArgument, "spend_cap can't be negative")
+	}
+	rules.spendCap = req.GetSpendCap()
+	if req.GetExpiresAt() != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.GetExpiresAt())
+		if err != nil {
+			return rules, status.Errorf(codes.InvalidArgument, "expires_at must be an RFC 3339 time")
+		}
+		if !expiresAt.After(now) {
+			return rules, status.Errorf(codes.InvalidArgument, "expires_at must be in the future")
+		}
+		rules.expiresAt = sql.NullTime{Time: expiresAt.UTC(), Valid: true}
+	}
+	return rules, nil
+}
+
+// ListVirtualCards lists a user's virtual cards, including closed ones. Merchant-locked cards show
+// the merchant they're locked to, if they've been used.
+func (s *server) ListVirtualCards(ctx context.Context, req *cardspb.ListVirtualCardsRequest) (*cardspb.VirtualCards, error) {
+	log.Printf("Received ListVirtualCards request: %+v", req)
+
+	query := `SELECT ` + cardColumns + ` FROM cards WHERE user_id = $1 AND card_type = 'virtual' ORDER BY created_at`
+	rows, err := s.db.QueryContext(ctx, query, req.GetUserId())
+	if err != nil {
+		log.Printf("failed to list virtual cards: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list virtual cards")
+	}
+	defer rows.Close()
+
+	cards := &cardspb.VirtualCards{}
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			log.Printf("failed to scan virtual card: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list virtual cards")
+		}
+		cards.Cards = append(cards.Cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to list virtual cards: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list virtual cards")
+	}
+
+	return cards, nil
+}
+
+// LockCardToMerchant locks a merchant-locked card to the merchant using it for the first time. Locking
+// it to the merchant it's already locked to changes nothing, so authorizations can always ask.
+func (s *server) LockCardToMerchant(ctx context.Context, req *cardspb.LockCardToMerchantRequest) (*cardspb.Card, error) {
+	log.Printf("Received LockCardToMerchant request: %+v", req)
+
+	if req.GetMerchantId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "merchant_id is required")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to lock card")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Lock the card so the first of two merchants using it at once wins
+	var virtualType, lockedMerchantID string
+	query := `SELECT COALESCE(virtual_type, ''), COALESCE(locked_merchant_id, '') FROM cards WHERE card_id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, req.GetCardId()).Scan(&virtualType, &lockedMerchantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("card not found to lock: %s", req.GetCardId())
+			return nil, status.Errorf(codes.NotFound, "card not found")
+		}
+		log.Printf("failed to get card to lock: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to lock card")
+	}
+	if virtualType != virtualTypeMerchantLocked {
+		return nil, status.Errorf(codes.FailedPrecondition, "card is not merchant-locked")
+	}
+	if lockedMerchantID != "" && lockedMerchantID != req.GetMerchantId() {
+		return nil, status.Errorf(codes.FailedPrecondition, "card is locked to another merchant")
+	}
+
+	if lockedMerchantID == "" {
+		update := `UPDATE cards SET locked_merchant_id = $2, updated_at = NOW() WHERE card_id = $1`
+		if _, err := tx.ExecContext(ctx, update, req.GetCardId(), req.GetMerchantId()); err != nil {
+			log.Printf("failed to lock card: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to lock card")
+		}
+		if err := tx.Commit(); err != nil {
+			log.Printf("failed to commit transaction: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to lock card")
+		}
+		log.Printf("Card %s locked to merchant %s", req.GetCardId(), req.GetMerchantId())
+	}
+
+	return s.getCard(ctx, "card_id", req.GetCardId())
+}
+
+// UseSingleUseCard marks a single-use card USED by the payment given. The card is claimed with a
+// conditional update from ACTIVE, so of two payments made with it at once only one can use it.
+func (s *server) UseSingleUseCard(ctx context.Context, req *cardspb.UseSingleUseCardRequest) (*cardspb.Card, error) {
+	log.Printf("Received UseSingleUseCard request: %+v", req)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to use card")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	var userID string
+	claim := `UPDATE cards SET status = 'USED', is_default = FALSE, updated_at = NOW()
+			  WHERE card_id = $1 AND virtual_type = 'single_use' AND status = 'ACTIVE' RETURNING user_id`
+	err = tx.QueryRowContext(ctx, claim, req.GetCardId()).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("single-use card %s not claimed for transaction %s", req.GetCardId(), req.GetTransactionId())
+			return nil, status.Errorf(codes.FailedPrecondition, "card is not an unused single-use card")
+		}
+		log.Printf("failed to claim single-use card: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to use card")
+	}
+	if err := recordStatusChange(ctx, tx, req.GetCardId(), "ACTIVE", "USED", "used for transaction "+req.GetTransactionId(), req.GetActor()); err != nil {
+		log.Printf("failed to record card status change: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to use card")
+	}
+
+	event := &eventspb.CardStatusChanged{
+		CardId:    req.GetCardId(),
+		UserId:    userID,
+		NewStatus: "USED",
+	}
+	if err := events.Enqueue(ctx, tx, events.StreamCardStatusChanged, req.GetCardId(), event); err != nil {
+		log.Printf("failed to enqueue card:status_changed event: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to use card")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to use card")
+	}
+	log.Printf("Single-use card %s used for transaction %s", req.GetCardId(), req.GetTransactionId())
+
+	return s.getCard(ctx, "card_id", req.GetCardId())
+}
+
+func (s *server) listVirtualCardsHandler(c echo.Context) error {
+	req := &cardspb.ListVirtualCardsRequest{UserId: c.Param("user_id")}
+
+	cards, err := s.ListVirtualCards(c.Request().Context(), req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, cards)
+}
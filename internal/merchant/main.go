@@ -18,6 +18,9 @@ import (
 	"google.golang.org/grpc/codes"  // Import codes
 	"google.golang.org/grpc/status" // Import status
 
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+
 	// Import generated protobuf code
 	merchantpb "github.com/sambacha/monzo/v2/merchant/merchant"
 )
@@ -270,21 +273,15 @@ func (s *server) UpdateMerchant(ctx context.Context, req *merchantpb.UpdateMerch
 
 	log.Printf("Successfully updated merchant: %s", updatedMerchant.GetMerchantId())
 
-	// Publish "merchant.updated" event to Redis
-	eventPayload := fmt.Sprintf(`{"merchant_id": "%s", "name": "%s", "category": "%s", "logo_url": "%s", "mcc": %d}`,
-		updatedMerchant.GetMerchantId(),
-		updatedMerchant.GetName(),
-		updatedMerchant.GetCategory(),
-		updatedMerchant.GetLogoUrl(),
-		updatedMerchant.GetMcc(),
-	)
-	if _, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
-		Stream: "merchant:updated",
-		MaxLen: 0, // No limit
-		Values: map[string]interface{}{
-			"payload": eventPayload,
-		},
-	}).Result(); err != nil {
+	// Publish "merchant:updated" event to Redis
+	event := &eventspb.MerchantUpdated{
+		MerchantId: updatedMerchant.GetMerchantId(),
+		Name:       updatedMerchant.GetName(),
+		Category:   updatedMerchant.GetCategory(),
+		LogoUrl:    updatedMerchant.GetLogoUrl(),
+		Mcc:        updatedMerchant.GetMcc(),
+	}
+	if _, err := events.Publish(ctx, s.redisClient, events.StreamMerchantUpdated, event); err != nil {
 		log.Printf("failed to publish merchant:updated event: %v", err)
 		// Log the error
 	} else {
@@ -0,0 +1,468 @@
+// Package service contains the business logic for the pots service
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/pots"
+)
+
+// Pot statuses
+const (
+	potOpen   = "OPEN"
+	potClosed = "CLOSED"
+)
+
+// Move directions, as recorded in pot_moves and published in PotMoved events
+const (
+	directionDeposit    = "DEPOSIT"
+	directionWithdrawal = "WITHDRAWAL"
+)
+
+// dateLayout is the format of pot target dates
+const dateLayout = "2006-01-02"
+
+// selectPotQuery selects the columns read by scanPot
+const selectPotQuery = `SELECT pot_id, account_id, name, currency, goal_amount, target_date, status, created_at FROM pots`
+
+// PotsService implements the Pots service functionality.
+//
+// A pot's money is held by the balance service in an account whose ID is the pot's ID, and
+// moves in and out of it through Balance.Transfer, so every move is a single ledger journal.
+// This service keeps the pots' names, goals and status.
+type PotsService struct {
+	pb.UnimplementedPotsServer
+	db      *sql.DB
+	balance balancepb.BalanceClient
+}
+
+// NewPotsService creates a new pots service instance
+func NewPotsService(db *sql.DB, balance balancepb.BalanceClient) *PotsService {
+	return &PotsService{db: db, balance: balance}
+}
+
+// CreatePot opens a pot for an account, in the account's currency
+func (s *PotsService) CreatePot(ctx context.Context, req *pb.CreatePotRequest) (*pb.Pot, error) {
+	log.Printf("Received CreatePot request: %+v", req)
+
+	name := strings.TrimSpace(req.GetName())
+	if req.GetAccountId() == "" || name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "account_id and name are required")
+	}
+	if req.GetGoalAmount() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "goal_amount cannot be negative")
+	}
+	targetDate, err := parseTargetDate(req.GetTargetDate())
+	if err != nil {
+		return nil, err
+	}
+
+	// Pots are held in the account's currency
+	account, err := s.balance.GetBalance(ctx, &balancepb.AccountID{AccountId: req.GetAccountId()})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		log.Printf("failed to get account balance: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create pot")
+	}
+
+	pot := &pb.Pot{
+		Id:         uuid.New().String(),
+		AccountId:  req.GetAccountId(),
+		Name:       name,
+		Currency:   account.GetCurrency(),
+		GoalAmount: req.GetGoalAmount(),
+		TargetDate: req.GetTargetDate(),
+		Status:     potOpen,
+	}
+
+	insertQuery := `INSERT INTO pots (pot_id, account_id, name, currency, goal_amount, target_date, status, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW()) RETURNING created_at`
+	var createdAt time.Time
+	err = s.db.QueryRowContext(ctx, insertQuery, pot.Id, pot.AccountId, pot.Name, pot.Currency, pot.GoalAmount, targetDate, pot.Status).Scan(&createdAt)
+	if err != nil {
+		log.Printf("failed to insert pot: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create pot")
+	}
+	pot.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+
+	log.Printf("Created pot %s (%s) for account %s", pot.Id, pot.Name, pot.AccountId)
+
+	return pot, nil
+}
+
+// GetPot retrieves a pot and its balance
+func (s *PotsService) GetPot(ctx context.Context, req *pb.PotID) (*pb.Pot, error) {
+	log.Printf("Received GetPot request: %+v", req)
+
+	pot, err := s.getPot(ctx, req.GetPotId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadBalance(ctx, pot); err != nil {
+		return nil, err
+	}
+	return pot, nil
+}
+
+// ListPots lists an account's pots, oldest first
+func (s *PotsService) ListPots(ctx context.Context, req *pb.ListPotsRequest) (*pb.PotList, error) {
+	log.Printf("Received ListPots request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetAccountId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id")
+	}
+
+	query := selectPotQuery + ` WHERE account_id = $1`
+	if !req.GetIncludeClosed() {
+		query += ` AND status = 'OPEN'`
+	}
+	query += ` ORDER BY created_at, pot_id`
+
+	rows, err := s.db.QueryContext(ctx, query, req.GetAccountId())
+	if err != nil {
+		log.Printf("failed to query pots: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list pots")
+	}
+	defer rows.Close()
+
+	pots := []*pb.Pot{}
+	for rows.Next() {
+		pot, err := scanPot(rows)
+		if err != nil {
+			log.Printf("failed to scan pot: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list pots")
+		}
+		pots = append(pots, pot)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating pots: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list pots")
+	}
+
+	for _, pot := range pots {
+		if err := s.loadBalance(ctx, pot); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pb.PotList{Pots: pots}, nil
+}
+
+// UpdatePot renames a pot or changes its goal
+func (s *PotsService) UpdatePot(ctx context.Context, req *pb.UpdatePotRequest) (*pb.Pot, error) {
+	log.Printf("Received UpdatePot request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetPotId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pot_id")
+	}
+	if req.GetGoalAmount() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "goal_amount cannot be negative")
+	}
+	if req.GetClearGoal() && (req.GetGoalAmount() != 0 || req.GetTargetDate() != "") {
+		return nil, status.Errorf(codes.InvalidArgument, "clear_goal cannot be combined with goal_amount or target_date")
+	}
+	targetDate, err := parseTargetDate(req.GetTargetDate())
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the update from the fields that are set
+	var sets []string
+	var args []interface{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if name := strings.TrimSpace(req.GetName()); name != "" {
+		set("name", name)
+	}
+	if req.GetGoalAmount() != 0 {
+		set("goal_amount", req.GetGoalAmount())
+	}
+	if targetDate.Valid {
+		set("target_date", targetDate)
+	}
+	if req.GetClearGoal() {
+		set("goal_amount", 0)
+		set("target_date", sql.NullTime{})
+	}
+	if len(sets) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "nothing to update")
+	}
+
+	args = append(args, req.GetPotId())
+	updateQuery := fmt.Sprintf(`UPDATE pots SET %s, updated_at = NOW() WHERE pot_id = $%d AND status = 'OPEN'
+					RETURNING pot_id, account_id, name, currency, goal_amount, target_date, status, created_at`,
+		strings.Join(sets, ", "), len(args))
+
+	pot, err := scanPot(s.db.QueryRowContext(ctx, updateQuery, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Tell a missing pot apart from a closed one
+			if _, err := s.getPot(ctx, req.GetPotId()); err != nil {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.FailedPrecondition, "pot is closed")
+		}
+		log.Printf("failed to update pot: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update pot")
+	}
+	if err := s.loadBalance(ctx, pot); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Updated pot %s", pot.Id)
+
+	return pot, nil
+}
+
+// ClosePot closes a pot, moving any money left in it back to its account first.
+// Closing a pot that is already closed returns it unchanged.
+func (s *PotsService) ClosePot(ctx context.Context, req *pb.PotID) (*pb.Pot, error) {
+	log.Printf("Received ClosePot request: %+v", req)
+
+	pot, err := s.getPot(ctx, req.GetPotId())
+	if err != nil {
+		return nil, err
+	}
+	if pot.Status == potClosed {
+		return pot, nil
+	}
+	if err := s.loadBalance(ctx, pot); err != nil {
+		return nil, err
+	}
+
+	// Sweep the pot back into the account
+	if pot.Balance > 0 {
+		result, err := s.move(ctx, pot, directionWithdrawal, pot.Balance, "close-"+pot.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Success {
+			log.Printf("failed to empty pot %s before closing: %s", pot.Id, result.ErrorMessage)
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to empty pot: %s", result.ErrorMessage)
+		}
+	}
+
+	updateQuery := `UPDATE pots SET status = $1, closed_at = NOW(), updated_at = NOW() WHERE pot_id = $2`
+	if _, err := s.db.ExecContext(ctx, updateQuery, potClosed, pot.Id); err != nil {
+		log.Printf("failed to close pot: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to close pot")
+	}
+	pot.Status = potClosed
+	pot.Balance = 0
+
+	log.Printf("Closed pot %s", pot.Id)
+
+	return pot, nil
+}
+
+// Deposit moves money from the account into the pot
+func (s *PotsService) Deposit(ctx context.Context, req *pb.MoveRequest) (*pb.MoveResult, error) {
+	log.Printf("Received Deposit request: %+v", req)
+	return s.handleMove(ctx, req, directionDeposit)
+}
+
+// Withdraw moves money from the pot back to the account
+func (s *PotsService) Withdraw(ctx context.Context, req *pb.MoveRequest) (*pb.MoveResult, error) {
+	log.Printf("Received Withdraw request: %+v", req)
+	return s.handleMove(ctx, req, directionWithdrawal)
+}
+
+// handleMove validates a move request and moves the money if the pot is open
+func (s *PotsService) handleMove(ctx context.Context, req *pb.MoveRequest, direction string) (*pb.MoveResult, error) {
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
+	pot, err := s.getPot(ctx, req.GetPotId())
+	if err != nil {
+		return nil, err
+	}
+	if pot.Status != potOpen {
+		return nil, status.Errorf(codes.FailedPrecondition, "pot is closed")
+	}
+
+	return s.move(ctx, pot, direction, req.GetAmount(), req.GetIdempotencyKey())
+}
+
+// move transfers amount between the pot and its account in the balance ledger, then records
+// the move and queues a PotMoved event. Retries with the same key move the money once.
+func (s *PotsService) move(ctx context.Context, pot *pb.Pot, direction string, amount int64, key string) (*pb.MoveResult, error) {
+	moveID := key
+	if moveID == "" {
+		moveID = uuid.New().String()
+	}
+
+	transfer := &balancepb.TransferRequest{
+		FromAccountId:  pot.AccountId,
+		ToAccountId:    pot.Id,
+		Amount:         amount,
+		IdempotencyKey: "pots:" + moveID,
+		Description:    "deposit to pot " + pot.Id,
+	}
+	if direction == directionWithdrawal {
+		transfer.FromAccountId, transfer.ToAccountId = pot.Id, pot.AccountId
+		transfer.Description = "withdrawal from pot " + pot.Id
+	}
+
+	result, err := s.balance.Transfer(ctx, transfer)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.AlreadyExists, codes.FailedPrecondition:
+			return nil, err
+		}
+		log.Printf("failed to transfer money for pot %s: %v", pot.Id, err)
+		return nil, status.Errorf(codes.Internal, "failed to move money")
+	}
+	if !result.GetSuccess() {
+		message := result.GetErrorMessage()
+		if direction == directionWithdrawal && message == "account not found" {
+			// Nothing has been deposited into the pot yet
+			message = "insufficient funds"
+		}
+		log.Printf("pot %s move declined: %s", pot.Id, message)
+		return &pb.MoveResult{Success: false, ErrorMessage: message}, nil
+	}
+
+	potBalance, accountBalance := result.GetTo(), result.GetFrom()
+	if direction == directionWithdrawal {
+		potBalance, accountBalance = result.GetFrom(), result.GetTo()
+	}
+	pot.Balance = potBalance.GetCurrentBalance()
+
+	if err := s.recordMove(ctx, pot, moveID, direction, amount, result.GetJournalId()); err != nil {
+		log.Printf("failed to record move %s for pot %s: %v", moveID, pot.Id, err)
+		return nil, status.Errorf(codes.Internal, "failed to move money")
+	}
+
+	log.Printf("Moved %d %s for pot %s (%s)", amount, pot.Currency, pot.Id, direction)
+
+	return &pb.MoveResult{
+		Success:        true,
+		Pot:            pot,
+		AccountBalance: accountBalance.GetAvailableBalance(),
+	}, nil
+}
+
+// recordMove stores a move and queues its PotMoved event, once per move ID
+func (s *PotsService) recordMove(ctx context.Context, pot *pb.Pot, moveID, direction string, amount int64, journalID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	insertQuery := `INSERT INTO pot_moves (move_id, pot_id, direction, amount, journal_id, created_at)
+					VALUES ($1, $2, $3, $4, $5, NOW()) ON CONFLICT (move_id) DO NOTHING`
+	res, err := tx.ExecContext(ctx, insertQuery, moveID, pot.Id, direction, amount, journalID)
+	if err != nil {
+		return fmt.Errorf("failed to insert move: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check inserted move: %w", err)
+	} else if n == 0 {
+		// A retry of a move that was already recorded
+		return nil
+	}
+
+	err = events.Enqueue(ctx, tx, events.StreamPotMoved, pot.Id, &eventspb.PotMoved{
+		MoveId:     moveID,
+		PotId:      pot.Id,
+		AccountId:  pot.AccountId,
+		PotName:    pot.Name,
+		Direction:  direction,
+		Amount:     amount,
+		Currency:   pot.Currency,
+		PotBalance: pot.Balance,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// getPot retrieves a pot without its balance
+func (s *PotsService) getPot(ctx context.Context, potID string) (*pb.Pot, error) {
+	if _, err := uuid.Parse(potID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pot_id")
+	}
+
+	pot, err := scanPot(s.db.QueryRowContext(ctx, selectPotQuery+` WHERE pot_id = $1`, potID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "pot not found")
+		}
+		log.Printf("failed to query pot: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get pot")
+	}
+	return pot, nil
+}
+
+// loadBalance sets the balance of an open pot from the balance service.
+// A pot that has never held money has no balance account yet.
+func (s *PotsService) loadBalance(ctx context.Context, pot *pb.Pot) error {
+	if pot.Status != potOpen {
+		return nil
+	}
+
+	bal, err := s.balance.GetBalance(ctx, &balancepb.AccountID{AccountId: pot.Id})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			pot.Balance = 0
+			return nil
+		}
+		log.Printf("failed to get balance of pot %s: %v", pot.Id, err)
+		return status.Errorf(codes.Internal, "failed to get pot balance")
+	}
+	pot.Balance = bal.GetCurrentBalance()
+	return nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPot reads a pot selected by selectPotQuery
+func scanPot(row scanner) (*pb.Pot, error) {
+	var pot pb.Pot
+	var targetDate sql.NullTime
+	var createdAt time.Time
+	if err := row.Scan(&pot.Id, &pot.AccountId, &pot.Name, &pot.Currency, &pot.GoalAmount, &targetDate, &pot.Status, &createdAt); err != nil {
+		return nil, err
+	}
+	if targetDate.Valid {
+		pot.TargetDate = targetDate.Time.Format(dateLayout)
+	}
+	pot.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return &pot, nil
+}
+
+// parseTargetDate parses an optional YYYY-MM-DD target date
+func parseTargetDate(s string) (sql.NullTime, error) {
+	if s == "" {
+		return sql.NullTime{}, nil
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return sql.NullTime{}, status.Errorf(codes.InvalidArgument, "invalid target_date, expected YYYY-MM-DD")
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/pots"
+)
+
+const (
+	testAccountID = "3b2f6c4e-8d1a-4f5e-9c7b-2a1d0e9f8c7b"
+	testPotID     = "7e9d1c2b-4a3f-4e5d-8b6c-1f0a9e8d7c6b"
+)
+
+// Mock BalanceClient. Only the RPCs the pots service calls are mocked; the embedded
+// interface is nil, so calling any other RPC panics.
+type mockBalanceClient struct {
+	mock.Mock
+	balancepb.BalanceClient
+}
+
+func (m *mockBalanceClient) GetBalance(ctx context.Context, in *balancepb.AccountID, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+func (m *mockBalanceClient) Transfer(ctx context.Context, in *balancepb.TransferRequest, opts ...grpc.CallOption) (*balancepb.TransferResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.TransferResult), args.Error(1)
+}
+
+// Helper function to create a server instance with mocks
+func newTestServer(t *testing.T) (*PotsService, sqlmock.Sqlmock, *mockBalanceClient) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	balanceClient := new(mockBalanceClient)
+	return NewPotsService(db, balanceClient), mockDb, balanceClient
+}
+
+// potColumns are the columns selected by selectPotQuery
+var potColumns = []string{"pot_id", "account_id", "name", "currency", "goal_amount", "target_date", "status", "created_at"}
+
+// expectGetPot sets up the mock expectation for looking up a pot
+func expectGetPot(mockDb sqlmock.Sqlmock, potID, potStatus string) {
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectPotQuery + ` WHERE pot_id = $1`)).
+		WithArgs(potID).
+		WillReturnRows(sqlmock.NewRows(potColumns).
+			AddRow(potID, testAccountID, "Holiday", "GBP", int64(100000), time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), potStatus, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)))
+}
+
+// expectMoveRecorded sets up the mock expectations for recording a move and queueing its pot:moved event
+func expectMoveRecorded(mockDb sqlmock.Sqlmock, moveID, potID, direction string, amount int64, journalID string) {
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO pot_moves (move_id, pot_id, direction, amount, journal_id, created_at)`)).
+		WithArgs(moveID, potID, direction, amount, journalID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs("pot:moved", potID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+}
+
+func TestCreatePot(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	req := &pb.CreatePotRequest{AccountId: testAccountID, Name: " Holiday ", GoalAmount: 100000, TargetDate: "2026-07-01"}
+	createdAt := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testAccountID}).
+		Return(&balancepb.BalanceResponse{AccountId: testAccountID, Currency: "EUR", CurrentBalance: 5000}, nil)
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO pots (pot_id, account_id, name, currency, goal_amount, target_date, status, created_at, updated_at)`)).
+		WithArgs(sqlmock.AnyArg(), testAccountID, "Holiday", "EUR", int64(100000), sqlmock.AnyArg(), "OPEN").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	pot, err := s.CreatePot(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pot.Id)
+	assert.Equal(t, "Holiday", pot.Name)
+	assert.Equal(t, "EUR", pot.Currency)
+	assert.Equal(t, int64(0), pot.Balance)
+	assert.Equal(t, "2026-07-01", pot.TargetDate)
+	assert.Equal(t, "OPEN", pot.Status)
+	assert.Equal(t, "2026-01-05T09:00:00Z", pot.CreatedAt)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestCreatePot_AccountNotFound(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testAccountID}).
+		Return(nil, status.Error(codes.NotFound, "account not found"))
+
+	_, err := s.CreatePot(context.Background(), &pb.CreatePotRequest{AccountId: testAccountID, Name: "Holiday"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.CreatePot(context.Background(), &pb.CreatePotRequest{AccountId: testAccountID, Name: "Holiday", TargetDate: "01/07/2026"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestDeposit(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	req := &pb.MoveRequest{PotId: testPotID, Amount: 2500, IdempotencyKey: "move-1"}
+
+	expectGetPot(mockDb, testPotID, "OPEN")
+	balanceClient.On("Transfer", mock.Anything, &balancepb.TransferRequest{
+		FromAccountId:  testAccountID,
+		ToAccountId:    testPotID,
+		Amount:         2500,
+		IdempotencyKey: "pots:move-1",
+		Description:    "deposit to pot " + testPotID,
+	}).Return(&balancepb.TransferResult{
+		Success:   true,
+		From:      &balancepb.BalanceResponse{AccountId: testAccountID, CurrentBalance: 7500, AvailableBalance: 7000},
+		To:        &balancepb.BalanceResponse{AccountId: testPotID, CurrentBalance: 4000, AvailableBalance: 4000},
+		JournalId: "journal-1",
+	}, nil)
+	expectMoveRecorded(mockDb, "move-1", testPotID, "DEPOSIT", 2500, "journal-1")
+
+	result, err := s.Deposit(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, int64(4000), result.Pot.Balance)
+	assert.Equal(t, int64(7000), result.AccountBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestWithdraw_InsufficientFunds(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	// Nothing has been deposited, so the balance service has no account for the pot yet
+	expectGetPot(mockDb, testPotID, "OPEN")
+	balanceClient.On("Transfer", mock.Anything, mock.AnythingOfType("*balance.TransferRequest")).
+		Return(&balancepb.TransferResult{Success: false, ErrorMessage: "account not found"}, nil)
+
+	result, err := s.Withdraw(context.Background(), &pb.MoveRequest{PotId: testPotID, Amount: 2500})
+
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "insufficient funds", result.ErrorMessage)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestDeposit_ClosedPot(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	expectGetPot(mockDb, testPotID, "CLOSED")
+
+	_, err := s.Deposit(context.Background(), &pb.MoveRequest{PotId: testPotID, Amount: 2500})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestClosePot_SweepsBalance(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	expectGetPot(mockDb, testPotID, "OPEN")
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testPotID}).
+		Return(&balancepb.BalanceResponse{AccountId: testPotID, Currency: "GBP", CurrentBalance: 4000}, nil)
+	balanceClient.On("Transfer", mock.Anything, &balancepb.TransferRequest{
+		FromAccountId:  testPotID,
+		ToAccountId:    testAccountID,
+		Amount:         4000,
+		IdempotencyKey: "pots:close-" + testPotID,
+		Description:    "withdrawal from pot " + testPotID,
+	}).Return(&balancepb.TransferResult{
+		Success:   true,
+		From:      &balancepb.BalanceResponse{AccountId: testPotID},
+		To:        &balancepb.BalanceResponse{AccountId: testAccountID, CurrentBalance: 11500, AvailableBalance: 11500},
+		JournalId: "journal-2",
+	}, nil)
+	expectMoveRecorded(mockDb, "close-"+testPotID, testPotID, "WITHDRAWAL", 4000, "journal-2")
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE pots SET status = $1, closed_at = NOW(), updated_at = NOW() WHERE pot_id = $2`)).
+		WithArgs("CLOSED", testPotID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	pot, err := s.ClosePot(context.Background(), &pb.PotID{PotId: testPotID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CLOSED", pot.Status)
+	assert.Equal(t, int64(0), pot.Balance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestUpdatePot(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`UPDATE pots SET name = $1, goal_amount = $2, target_date = $3, updated_at = NOW() WHERE pot_id = $4 AND status = 'OPEN'`)).
+		WithArgs("Rainy day", int64(0), sqlmock.AnyArg(), testPotID).
+		WillReturnRows(sqlmock.NewRows(potColumns).
+			AddRow(testPotID, testAccountID, "Rainy day", "GBP", int64(0), nil, "OPEN", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)))
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testPotID}).
+		Return(nil, status.Error(codes.NotFound, "account not found"))
+
+	pot, err := s.UpdatePot(context.Background(), &pb.UpdatePotRequest{PotId: testPotID, Name: "Rainy day", ClearGoal: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Rainy day", pot.Name)
+	assert.Equal(t, int64(0), pot.GoalAmount)
+	assert.Empty(t, pot.TargetDate)
+
+	_, err = s.UpdatePot(context.Background(), &pb.UpdatePotRequest{PotId: testPotID})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
+
+func TestListPots(t *testing.T) {
+	s, mockDb, balanceClient := newTestServer(t)
+	defer s.db.Close()
+
+	otherPotID := "0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+	createdAt := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectPotQuery + ` WHERE account_id = $1 AND status = 'OPEN' ORDER BY created_at, pot_id`)).
+		WithArgs(testAccountID).
+		WillReturnRows(sqlmock.NewRows(potColumns).
+			AddRow(testPotID, testAccountID, "Holiday", "GBP", int64(100000), nil, "OPEN", createdAt).
+			AddRow(otherPotID, testAccountID, "Bills", "GBP", int64(0), nil, "OPEN", createdAt.Add(time.Hour)))
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: testPotID}).
+		Return(&balancepb.BalanceResponse{AccountId: testPotID, CurrentBalance: 4000}, nil)
+	balanceClient.On("GetBalance", mock.Anything, &balancepb.AccountID{AccountId: otherPotID}).
+		Return(nil, status.Error(codes.NotFound, "account not found"))
+
+	list, err := s.ListPots(context.Background(), &pb.ListPotsRequest{AccountId: testAccountID})
+
+	assert.NoError(t, err)
+	assert.Len(t, list.Pots, 2)
+	assert.Equal(t, int64(4000), list.Pots[0].Balance)
+	assert.Equal(t, "Bills", list.Pots[1].Name)
+	assert.Equal(t, int64(0), list.Pots[1].Balance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+}
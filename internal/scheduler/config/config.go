@@ -0,0 +1,69 @@
+// Package config provides configuration handling for the scheduler service
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/parsers/dotenv"
+	"github.com/knadh/koanf/providers/env"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
+
+// Config holds the application configuration
+type Config struct {
+	DBDSN     string `koanf:"db_dsn"`
+	RedisAddr string `koanf:"redis_addr"`
+	GRPCPort  string `koanf:"grpc_port"`
+
+	// BalanceAddr is the gRPC address of the balance service, which scheduled payments are debited through
+	BalanceAddr string `koanf:"balance_addr"`
+	// TransactionsAddr is the gRPC address of the transactions service, which scheduled payments are recorded in
+	TransactionsAddr string `koanf:"transactions_addr"`
+
+	// RunInterval is how often due schedules are looked for and run
+	RunInterval time.Duration `koanf:"run_interval"`
+	// RetryAfter is how long a run may stay pending before it is treated as interrupted and retried
+	RetryAfter time.Duration `koanf:"retry_after"`
+}
+
+// Load loads configuration from environment variables with defaults
+func Load() (*Config, error) {
+	k := koanf.New(".")
+
+	// Set default values
+	k.Set("db_dsn", "user=user dbname=scheduler sslmode=disable")
+	k.Set("redis_addr", "localhost:6379")
+	k.Set("grpc_port", ":50059")
+	k.Set("balance_addr", "localhost:50053")
+	k.Set("transactions_addr", "localhost:50052")
+	k.Set("run_interval", "1m")
+	k.Set("retry_after", "5m")
+
+	// Load from .env file if exists (optional)
+	if err := k.Load(file.Provider(".env"), dotenv.Parser()); err != nil {
+		// Ignore error if file doesn't exist
+		if !strings.Contains(err.Error(), "no such file") {
+			return nil, fmt.Errorf("error loading config from .env file: %w", err)
+		}
+	}
+
+	// Load environment variables prefixed with SCHEDULER_
+	// e.g. SCHEDULER_DB_DSN, SCHEDULER_BALANCE_ADDR
+	err := k.Load(env.Provider("SCHEDULER_", ".", func(s string) string {
+		return strings.Replace(strings.ToLower(
+			strings.TrimPrefix(s, "SCHEDULER_")), "_", ".", -1)
+	}), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config from env: %w", err)
+	}
+
+	var cfg Config
+	if err := k.Unmarshal("", &cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshalling config: %w", err)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule frequencies
+const (
+	frequencyOnce           = "ONCE"
+	frequencyWeekly         = "WEEKLY"
+	frequencyMonthly        = "MONTHLY"
+	frequencyLastWorkingDay = "LAST_WORKING_DAY"
+)
+
+// dateLayout is the format of schedule and run dates
+const dateLayout = "2006-01-02"
+
+// validFrequency reports whether frequency is one the scheduler can run
+func validFrequency(frequency string) bool {
+	switch frequency {
+	case frequencyOnce, frequencyWeekly, frequencyMonthly, frequencyLastWorkingDay:
+		return true
+	}
+	return false
+}
+
+// firstRunDate returns the first occurrence of a schedule on or after its start date
+func firstRunDate(frequency string, start time.Time) time.Time {
+	if frequency == frequencyLastWorkingDay {
+		return nextRunDate(frequency, start, start.AddDate(0, 0, -1))
+	}
+	return start
+}
+
+// nextRunDate returns the occurrence of a schedule that follows the occurrence on date, or the
+// zero time for a schedule that runs once. Monthly schedules run on the start date's day of the
+// month, or on the last day of months too short to have it. Working days are Monday to Friday.
+func nextRunDate(frequency string, start, date time.Time) time.Time {
+	switch frequency {
+	case frequencyWeekly:
+		return date.AddDate(0, 0, 7)
+	case frequencyMonthly:
+		next := dayOfMonth(date.Year(), date.Month(), start.Day())
+		if !next.After(date) {
+			next = dayOfMonth(date.Year(), date.Month()+1, start.Day())
+		}
+		return next
+	case frequencyLastWorkingDay:
+		next := lastWorkingDay(date.Year(), date.Month())
+		if !next.After(date) {
+			next = lastWorkingDay(date.Year(), date.Month()+1)
+		}
+		return next
+	default:
+		return time.Time{}
+	}
+}
+
+// dayOfMonth returns the given day of a month, or the month's last day if it is shorter.
+// Months past December roll over into the following year.
+func dayOfMonth(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	if day > last.Day() {
+		return last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// lastWorkingDay returns the last weekday of a month
+func lastWorkingDay(year int, month time.Month) time.Time {
+	day := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// parseDate parses a YYYY-MM-DD date as midnight UTC
+func parseDate(s string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// today returns the current date in UTC
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, err := parseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNextRunDate(t *testing.T) {
+	tests := []struct {
+		frequency string
+		start     string
+		date      string
+		want      string
+	}{
+		{frequency: frequencyOnce, start: "2026-01-15", date: "2026-01-15", want: ""},
+		{frequency: frequencyWeekly, start: "2026-01-15", date: "2026-01-29", want: "2026-02-05"},
+		{frequency: frequencyMonthly, start: "2026-01-15", date: "2026-01-15", want: "2026-02-15"},
+		// Months too short for the start day pay on their last day, and the next month is back on the start day
+		{frequency: frequencyMonthly, start: "2026-01-31", date: "2026-01-31", want: "2026-02-28"},
+		{frequency: frequencyMonthly, start: "2026-01-31", date: "2026-02-28", want: "2026-03-31"},
+		{frequency: frequencyMonthly, start: "2025-12-20", date: "2025-12-20", want: "2026-01-20"},
+		// 31 January 2026 is a Saturday, and 28 February 2026 a Saturday too
+		{frequency: frequencyLastWorkingDay, start: "2026-01-01", date: "2026-01-30", want: "2026-02-27"},
+		{frequency: frequencyLastWorkingDay, start: "2026-01-01", date: "2026-02-27", want: "2026-03-31"},
+	}
+	for _, tt := range tests {
+		got := nextRunDate(tt.frequency, date(tt.start), date(tt.date))
+		if tt.want == "" {
+			assert.True(t, got.IsZero(), "%s after %s", tt.frequency, tt.date)
+			continue
+		}
+		assert.Equal(t, tt.want, got.Format(dateLayout), "%s after %s", tt.frequency, tt.date)
+	}
+}
+
+func TestFirstRunDate(t *testing.T) {
+	assert.Equal(t, "2026-01-15", firstRunDate(frequencyMonthly, date("2026-01-15")).Format(dateLayout))
+	assert.Equal(t, "2026-01-30", firstRunDate(frequencyLastWorkingDay, date("2026-01-15")).Format(dateLayout))
+	assert.Equal(t, "2026-01-30", firstRunDate(frequencyLastWorkingDay, date("2026-01-30")).Format(dateLayout))
+	// Starting after the month's last working day waits for the next month's
+	assert.Equal(t, "2026-02-27", firstRunDate(frequencyLastWorkingDay, date("2026-01-31")).Format(dateLayout))
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/events"
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/scheduler"
+	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
+)
+
+// Run statuses
+const (
+	runPending  = "PENDING"
+	runPaid     = "PAID"
+	runDeclined = "DECLINED"
+	runFailed   = "FAILED"
+)
+
+// Run steps, recording the last step of a run that is known to have completed.
+// An interrupted run is resumed from its step; every call it makes is idempotent.
+const (
+	stepClaimed         = "CLAIMED"          // occurrence claimed, debit may or may not have been authorized
+	stepDebitAuthorized = "DEBIT_AUTHORIZED" // hold placed for the payment, may or may not have been captured
+	stepCaptured        = "CAPTURED"         // payment debited, transaction may or may not have been recorded
+	stepDone            = "DONE"             // run finished with its final status
+)
+
+// transactionSettled is the status scheduled payments are recorded with
+const transactionSettled = "SETTLED"
+
+// scheduleRun is one occurrence of a schedule being paid
+type scheduleRun struct {
+	id            string
+	scheduleID    string
+	accountID     string
+	amount        int64
+	currency      string
+	payee         string
+	runDate       time.Time
+	step          string
+	holdID        string
+	transactionID string
+}
+
+// idempotencyKey is the key of the run's calls to the balance and transactions services
+func (r *scheduleRun) idempotencyKey() string {
+	return "schedule-run:" + r.id
+}
+
+// RunDueSchedules pays every occurrence due on or before the given date, and retries runs that were
+// interrupted. Each occurrence is claimed in the same database transaction that moves its schedule on
+// to the next occurrence, so replicas running this concurrently never pay an occurrence twice.
+func (s *SchedulerService) RunDueSchedules(ctx context.Context, req *pb.RunDueSchedulesRequest) (*pb.RunDueSchedulesResponse, error) {
+	log.Printf("Received RunDueSchedules request: %+v", req)
+
+	date := today()
+	if req.GetDate() != "" {
+		var err error
+		if date, err = parseDate(req.GetDate()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "date: %v", err)
+		}
+	}
+
+	// Resume runs left pending by an earlier attempt
+	pending, err := s.claimPendingRuns(ctx, req.GetLimit())
+	if err != nil {
+		log.Printf("failed to claim pending runs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to run due schedules")
+	}
+	for _, run := range pending {
+		if err := s.execute(ctx, run); err != nil {
+			log.Printf("failed to retry run %s of schedule %s, will retry: %v", run.id, run.scheduleID, err)
+		}
+	}
+
+	var started uint32
+	for req.GetLimit() == 0 || started < req.GetLimit() {
+		run, err := s.claimDueRun(ctx, date)
+		if err != nil {
+			log.Printf("failed to claim due schedule: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to run due schedules")
+		}
+		if run == nil {
+			break
+		}
+		started++
+		if err := s.execute(ctx, run); err != nil {
+			log.Printf("failed to run schedule %s for %s, will retry: %v", run.scheduleID, run.runDate.Format(dateLayout), err)
+		}
+	}
+
+	if started > 0 || len(pending) > 0 {
+		log.Printf("Ran %d due schedule occurrences and retried %d pending runs", started, len(pending))
+	}
+
+	return &pb.RunDueSchedulesResponse{Started: started, Retried: uint32(len(pending))}, nil
+}
+
+// claimDueRun claims the next occurrence due on or before date and moves its schedule on to the
+// following occurrence. It returns nil if nothing is due.
+func (s *SchedulerService) claimDueRun(ctx context.Context, date time.Time) (*scheduleRun, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Skip schedules another replica is claiming
+	query := `SELECT schedule_id, account_id, amount, currency, payee, frequency, start_date, end_date, next_run_date FROM schedules
+			  WHERE status = 'ACTIVE' AND next_run_date <= $1 ORDER BY next_run_date LIMIT 1 FOR UPDATE SKIP LOCKED`
+	run := &scheduleRun{id: uuid.New().String(), step: stepClaimed}
+	var frequency string
+	var start time.Time
+	var end sql.NullTime
+	err = tx.QueryRowContext(ctx, query, date).Scan(&run.scheduleID, &run.accountID, &run.amount, &run.currency, &run.payee,
+		&frequency, &start, &end, &run.runDate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to select due schedule: %w", err)
+	}
+
+	insertQuery := `INSERT INTO schedule_runs (run_id, schedule_id, run_date, step, status, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, NOW(), NOW())`
+	if _, err := tx.ExecContext(ctx, insertQuery, run.id, run.scheduleID, run.runDate, run.step, runPending); err != nil {
+		return nil, fmt.Errorf("failed to insert run: %w", err)
+	}
+
+	next := nextRunDate(frequency, start, run.runDate)
+	if next.IsZero() || (end.Valid && next.After(end.Time)) {
+		// That was the last occurrence
+		updateQuery := `UPDATE schedules SET next_run_date = NULL, status = $1, updated_at = NOW() WHERE schedule_id = $2`
+		if _, err := tx.ExecContext(ctx, updateQuery, scheduleCompleted, run.scheduleID); err != nil {
+			return nil, fmt.Errorf("failed to complete schedule: %w", err)
+		}
+	} else {
+		updateQuery := `UPDATE schedules SET next_run_date = $1, updated_at = NOW() WHERE schedule_id = $2`
+		if _, err := tx.ExecContext(ctx, updateQuery, next, run.scheduleID); err != nil {
+			return nil, fmt.Errorf("failed to advance schedule: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return run, nil
+}
+
+// claimPendingRuns claims runs that have been pending for longer than retryAfter, i.e. whose
+// earlier attempt was interrupted or failed, touching them so other replicas leave them alone
+func (s *SchedulerService) claimPendingRuns(ctx context.Context, limit uint32) ([]*scheduleRun, error) {
+	query := `UPDATE schedule_runs r SET attempts = r.attempts + 1, updated_at = NOW()
+			  FROM schedules s
+			  WHERE s.schedule_id = r.schedule_id AND r.run_id IN (
+				  SELECT run_id FROM schedule_runs WHERE status = 'PENDING' AND updated_at < $1
+				  ORDER BY updated_at LIMIT $2 FOR UPDATE SKIP LOCKED)
+			  RETURNING r.run_id, r.schedule_id, s.account_id, s.amount, s.currency, s.payee, r.run_date, r.step, r.hold_id, r.transaction_id`
+	idleSince := time.Now().Add(-s.retryAfter)
+	limitArg := sql.NullInt64{Int64: int64(limit), Valid: limit > 0}
+
+	rows, err := s.db.QueryContext(ctx, query, idleSince, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*scheduleRun
+	for rows.Next() {
+		var run scheduleRun
+		var holdID, transactionID sql.NullString
+		if err := rows.Scan(&run.id, &run.scheduleID, &run.accountID, &run.amount, &run.currency, &run.payee,
+			&run.runDate, &run.step, &holdID, &transactionID); err != nil {
+			return nil, fmt.Errorf("failed to scan pending run: %w", err)
+		}
+		run.holdID = holdID.String
+		run.transactionID = transactionID.String
+		runs = append(runs, &run)
+	}
+	return runs, rows.Err()
+}
+
+// execute pays a run, resuming from its last completed step. An error leaves the run pending to be retried.
+func (s *SchedulerService) execute(ctx context.Context, run *scheduleRun) error {
+	if run.step == stepClaimed {
+		result, err := s.balanceClient.AuthorizeDebit(ctx, &balancepb.AuthorizeDebitRequest{
+			AccountId:      run.accountID,
+			Amount:         run.amount,
+			Currency:       run.currency,
+			IdempotencyKey: run.idempotencyKey(),
+		})
+		if err != nil {
+			if code := status.Code(err); code == codes.InvalidArgument || code == codes.FailedPrecondition {
+				return s.finish(ctx, run, runFailed, status.Convert(err).Message())
+			}
+			return fmt.Errorf("failed to authorize debit: %w", err)
+		}
+		if !result.GetSuccess() {
+			log.Printf("Scheduled payment %s declined: %s", run.id, result.GetErrorMessage())
+			return s.finish(ctx, run, runDeclined, result.GetErrorMessage())
+		}
+		run.holdID = result.GetHoldId()
+		if err := s.advance(ctx, run, stepDebitAuthorized); err != nil {
+			return err
+		}
+	}
+
+	if run.step == stepDebitAuthorized {
+		_, err := s.balanceClient.CaptureHold(ctx, &balancepb.CaptureHoldRequest{HoldId: run.holdID})
+		if err != nil {
+			st := status.Convert(err)
+			switch {
+			case st.Code() == codes.FailedPrecondition && st.Message() == "hold is CAPTURED":
+				// Captured by an attempt that was interrupted before it recorded the step
+			case st.Code() == codes.FailedPrecondition || st.Code() == codes.NotFound:
+				// The hold was released or expired before it could be captured
+				return s.finish(ctx, run, runFailed, st.Message())
+			default:
+				return fmt.Errorf("failed to capture hold %s: %w", run.holdID, err)
+			}
+		}
+		if err := s.advance(ctx, run, stepCaptured); err != nil {
+			return err
+		}
+	}
+
+	if run.step == stepCaptured {
+		txn, err := s.transactionsClient.RecordTransaction(ctx, &transactionspb.TransactionInput{
+			AccountId:      run.accountID,
+			Amount:         run.amount,
+			Currency:       run.currency,
+			MerchantRaw:    run.payee,
+			Status:         transactionSettled,
+			HoldId:         run.holdID,
+			IdempotencyKey: run.idempotencyKey(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record transaction: %w", err)
+		}
+		run.transactionID = txn.GetId()
+		log.Printf("Scheduled payment %s of schedule %s paid as transaction %s", run.id, run.scheduleID, run.transactionID)
+		return s.finish(ctx, run, runPaid, "")
+	}
+
+	return nil
+}
+
+// advance moves the run to the given step and persists it
+func (s *SchedulerService) advance(ctx context.Context, run *scheduleRun, step string) error {
+	run.step = step
+	query := `UPDATE schedule_runs SET step = $1, hold_id = $2, updated_at = NOW() WHERE run_id = $3`
+	if _, err := s.db.ExecContext(ctx, query, run.step, nullString(run.holdID), run.id); err != nil {
+		return fmt.Errorf("failed to persist run %s at step %s: %w", run.id, step, err)
+	}
+	return nil
+}
+
+// finish records the final status of a run and queues its schedule:run event.
+// Only the first attempt to finish a run queues an event.
+func (s *SchedulerService) finish(ctx context.Context, run *scheduleRun, runStatus, reason string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	run.step = stepDone
+	query := `UPDATE schedule_runs SET step = $1, status = $2, hold_id = $3, transaction_id = $4, decline_reason = $5, updated_at = NOW()
+			  WHERE run_id = $6 AND status = 'PENDING'`
+	res, err := tx.ExecContext(ctx, query, run.step, runStatus, nullString(run.holdID), nullString(run.transactionID), nullString(reason), run.id)
+	if err != nil {
+		return fmt.Errorf("failed to finish run %s: %w", run.id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check finished run %s: %w", run.id, err)
+	} else if n == 0 {
+		// Finished by a concurrent attempt
+		return nil
+	}
+
+	err = events.Enqueue(ctx, tx, events.StreamScheduleRun, run.scheduleID, &eventspb.ScheduledPaymentRun{
+		RunId:         run.id,
+		ScheduleId:    run.scheduleID,
+		AccountId:     run.accountID,
+		Payee:         run.payee,
+		Amount:        run.amount,
+		Currency:      run.currency,
+		RunDate:       run.runDate.Format(dateLayout),
+		Status:        runStatus,
+		DeclineReason: reason,
+		TransactionId: run.transactionID,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// nullString maps an empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,284 @@
+// Package service contains the business logic for the scheduler service
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/scheduler"
+	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
+)
+
+// Schedule statuses
+const (
+	scheduleActive    = "ACTIVE"
+	scheduleCompleted = "COMPLETED"
+	scheduleCancelled = "CANCELLED"
+)
+
+// defaultRetryAfter is how long a run may stay pending before it is retried
+const defaultRetryAfter = 5 * time.Minute
+
+// selectScheduleQuery selects the columns read by scanSchedule
+const selectScheduleQuery = `SELECT schedule_id, account_id, amount, currency, payee, reference, frequency, start_date, end_date, next_run_date, status, created_at FROM schedules`
+
+// SchedulerService implements the Scheduler service functionality
+type SchedulerService struct {
+	pb.UnimplementedSchedulerServer
+	db                 *sql.DB
+	balanceClient      balancepb.BalanceClient
+	transactionsClient transactionspb.TransactionsClient
+	retryAfter         time.Duration
+}
+
+// Option configures optional SchedulerService settings
+type Option func(*SchedulerService)
+
+// WithRetryAfter sets how long a run may stay pending before it is treated as interrupted and retried
+func WithRetryAfter(d time.Duration) Option {
+	return func(s *SchedulerService) {
+		if d > 0 {
+			s.retryAfter = d
+		}
+	}
+}
+
+// NewSchedulerService creates a new scheduler service instance
+func NewSchedulerService(db *sql.DB, balanceClient balancepb.BalanceClient, transactionsClient transactionspb.TransactionsClient, opts ...Option) *SchedulerService {
+	s := &SchedulerService{
+		db:                 db,
+		balanceClient:      balanceClient,
+		transactionsClient: transactionsClient,
+		retryAfter:         defaultRetryAfter,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateSchedule sets up a standing order or a future-dated payment
+func (s *SchedulerService) CreateSchedule(ctx context.Context, req *pb.CreateScheduleRequest) (*pb.Schedule, error) {
+	log.Printf("Received CreateSchedule request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetAccountId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id")
+	}
+	if req.GetAmount() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+	if len(req.GetCurrency()) != 3 {
+		return nil, status.Errorf(codes.InvalidArgument, "currency must be an ISO 4217 code")
+	}
+	payee := strings.TrimSpace(req.GetPayee())
+	if payee == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "payee is required")
+	}
+	if !validFrequency(req.GetFrequency()) {
+		return nil, status.Errorf(codes.InvalidArgument, "frequency must be one of ONCE, WEEKLY, MONTHLY or LAST_WORKING_DAY")
+	}
+	start, err := parseDate(req.GetStartDate())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "start_date: %v", err)
+	}
+	if start.Before(today()) {
+		return nil, status.Errorf(codes.InvalidArgument, "start_date cannot be in the past")
+	}
+	var end sql.NullTime
+	if req.GetEndDate() != "" {
+		t, err := parseDate(req.GetEndDate())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "end_date: %v", err)
+		}
+		if t.Before(start) {
+			return nil, status.Errorf(codes.InvalidArgument, "end_date cannot be before start_date")
+		}
+		end = sql.NullTime{Time: t, Valid: true}
+	}
+
+	next := firstRunDate(req.GetFrequency(), start)
+	if end.Valid && next.After(end.Time) {
+		return nil, status.Errorf(codes.InvalidArgument, "schedule has no payments before end_date")
+	}
+
+	insertQuery := `INSERT INTO schedules (schedule_id, account_id, amount, currency, payee, reference, frequency, start_date, end_date, next_run_date, status, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+					RETURNING schedule_id, account_id, amount, currency, payee, reference, frequency, start_date, end_date, next_run_date, status, created_at`
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx, insertQuery,
+		uuid.New().String(), req.GetAccountId(), req.GetAmount(), strings.ToUpper(req.GetCurrency()), payee, req.GetReference(),
+		req.GetFrequency(), start, end, next, scheduleActive))
+	if err != nil {
+		log.Printf("failed to insert schedule: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create schedule")
+	}
+
+	log.Printf("Created %s schedule %s for account %s, first payment on %s", schedule.Frequency, schedule.Id, schedule.AccountId, schedule.NextRunDate)
+
+	return schedule, nil
+}
+
+// GetSchedule retrieves a schedule
+func (s *SchedulerService) GetSchedule(ctx context.Context, req *pb.ScheduleID) (*pb.Schedule, error) {
+	log.Printf("Received GetSchedule request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetScheduleId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule_id")
+	}
+
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx, selectScheduleQuery+` WHERE schedule_id = $1`, req.GetScheduleId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "schedule not found")
+		}
+		log.Printf("failed to query schedule: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get schedule")
+	}
+	return schedule, nil
+}
+
+// ListSchedules lists an account's schedules, soonest payment first
+func (s *SchedulerService) ListSchedules(ctx context.Context, req *pb.ListSchedulesRequest) (*pb.ScheduleList, error) {
+	log.Printf("Received ListSchedules request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetAccountId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid account_id")
+	}
+
+	query := selectScheduleQuery + ` WHERE account_id = $1`
+	if !req.GetIncludeInactive() {
+		query += ` AND status = 'ACTIVE'`
+	}
+	query += ` ORDER BY next_run_date NULLS LAST, created_at`
+
+	rows, err := s.db.QueryContext(ctx, query, req.GetAccountId())
+	if err != nil {
+		log.Printf("failed to query schedules: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list schedules")
+	}
+	defer rows.Close()
+
+	schedules := []*pb.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			log.Printf("failed to scan schedule: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list schedules")
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating schedules: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list schedules")
+	}
+
+	return &pb.ScheduleList{Schedules: schedules}, nil
+}
+
+// CancelSchedule stops a schedule from making further payments.
+// A payment that has already started still completes.
+func (s *SchedulerService) CancelSchedule(ctx context.Context, req *pb.ScheduleID) (*pb.Schedule, error) {
+	log.Printf("Received CancelSchedule request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetScheduleId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule_id")
+	}
+
+	updateQuery := `UPDATE schedules SET status = $1, next_run_date = NULL, updated_at = NOW() WHERE schedule_id = $2 AND status = 'ACTIVE'
+					RETURNING schedule_id, account_id, amount, currency, payee, reference, frequency, start_date, end_date, next_run_date, status, created_at`
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx, updateQuery, scheduleCancelled, req.GetScheduleId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Tell a missing schedule apart from one that has already finished
+			existing, err := s.GetSchedule(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.FailedPrecondition, "schedule is %s", existing.Status)
+		}
+		log.Printf("failed to cancel schedule: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to cancel schedule")
+	}
+
+	log.Printf("Cancelled schedule %s", schedule.Id)
+
+	return schedule, nil
+}
+
+// ListScheduleRuns lists the payments a schedule has made or attempted, most recent first
+func (s *SchedulerService) ListScheduleRuns(ctx context.Context, req *pb.ListScheduleRunsRequest) (*pb.ScheduleRuns, error) {
+	log.Printf("Received ListScheduleRuns request: %+v", req)
+
+	if _, err := uuid.Parse(req.GetScheduleId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule_id")
+	}
+	limit := req.GetLimit()
+	if limit == 0 || limit > 100 {
+		limit = 100
+	}
+
+	query := `SELECT run_id, schedule_id, run_date, status, decline_reason, transaction_id, created_at, updated_at
+			  FROM schedule_runs WHERE schedule_id = $1 ORDER BY run_date DESC LIMIT $2`
+	rows, err := s.db.QueryContext(ctx, query, req.GetScheduleId(), limit)
+	if err != nil {
+		log.Printf("failed to query schedule runs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list schedule runs")
+	}
+	defer rows.Close()
+
+	runs := []*pb.ScheduleRun{}
+	for rows.Next() {
+		var run pb.ScheduleRun
+		var runDate, createdAt, updatedAt time.Time
+		var declineReason, transactionID sql.NullString
+		if err := rows.Scan(&run.Id, &run.ScheduleId, &runDate, &run.Status, &declineReason, &transactionID, &createdAt, &updatedAt); err != nil {
+			log.Printf("failed to scan schedule run: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list schedule runs")
+		}
+		run.RunDate = runDate.Format(dateLayout)
+		run.DeclineReason = declineReason.String
+		run.TransactionId = transactionID.String
+		run.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+		run.UpdatedAt = updatedAt.UTC().Format(time.RFC3339)
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating schedule runs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list schedule runs")
+	}
+
+	return &pb.ScheduleRuns{Runs: runs}, nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSchedule reads a schedule selected by selectScheduleQuery
+func scanSchedule(row scanner) (*pb.Schedule, error) {
+	var schedule pb.Schedule
+	var start, createdAt time.Time
+	var end, next sql.NullTime
+	err := row.Scan(&schedule.Id, &schedule.AccountId, &schedule.Amount, &schedule.Currency, &schedule.Payee, &schedule.Reference,
+		&schedule.Frequency, &start, &end, &next, &schedule.Status, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	schedule.StartDate = start.Format(dateLayout)
+	if end.Valid {
+		schedule.EndDate = end.Time.Format(dateLayout)
+	}
+	if next.Valid {
+		schedule.NextRunDate = next.Time.Format(dateLayout)
+	}
+	schedule.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return &schedule, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+	pb "github.com/manifoldfinance/disco2/v2/pkg/pb/scheduler"
+	transactionspb "github.com/manifoldfinance/disco2/v2/pkg/pb/transactions"
+)
+
+const (
+	testAccountID  = "3b2f6c4e-8d1a-4f5e-9c7b-2a1d0e9f8c7b"
+	testScheduleID = "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+)
+
+// Mock BalanceClient. Only the RPCs the scheduler calls are mocked; the embedded
+// interface is nil, so calling any other RPC panics.
+type mockBalanceClient struct {
+	mock.Mock
+	balancepb.BalanceClient
+}
+
+func (m *mockBalanceClient) AuthorizeDebit(ctx context.Context, in *balancepb.AuthorizeDebitRequest, opts ...grpc.CallOption) (*balancepb.DebitResult, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.DebitResult), args.Error(1)
+}
+
+func (m *mockBalanceClient) CaptureHold(ctx context.Context, in *balancepb.CaptureHoldRequest, opts ...grpc.CallOption) (*balancepb.BalanceResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*balancepb.BalanceResponse), args.Error(1)
+}
+
+// Mock TransactionsClient, for RecordTransaction only
+type mockTransactionsClient struct {
+	mock.Mock
+	transactionspb.TransactionsClient
+}
+
+func (m *mockTransactionsClient) RecordTransaction(ctx context.Context, in *transactionspb.TransactionInput, opts ...grpc.CallOption) (*transactionspb.Transaction, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*transactionspb.Transaction), args.Error(1)
+}
+
+// Helper function to create a server instance with mocks
+func newTestServer(t *testing.T) (*SchedulerService, sqlmock.Sqlmock, *mockBalanceClient, *mockTransactionsClient) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	balanceClient := new(mockBalanceClient)
+	transactionsClient := new(mockTransactionsClient)
+	return NewSchedulerService(db, balanceClient, transactionsClient), mockDb, balanceClient, transactionsClient
+}
+
+// scheduleColumns are the columns selected by selectScheduleQuery
+var scheduleColumns = []string{"schedule_id", "account_id", "amount", "currency", "payee", "reference", "frequency", "start_date", "end_date", "next_run_date", "status", "created_at"}
+
+// Queries for running schedules
+var (
+	pendingRunsQuery = regexp.QuoteMeta(`UPDATE schedule_runs r SET attempts = r.attempts + 1, updated_at = NOW()`)
+	dueScheduleQuery = regexp.QuoteMeta(`SELECT schedule_id, account_id, amount, currency, payee, frequency, start_date, end_date, next_run_date FROM schedules`)
+	runInsertQuery   = regexp.QuoteMeta(`INSERT INTO schedule_runs (run_id, schedule_id, run_date, step, status, created_at, updated_at)`)
+	runAdvanceQuery  = regexp.QuoteMeta(`UPDATE schedule_runs SET step = $1, hold_id = $2, updated_at = NOW() WHERE run_id = $3`)
+	runFinishQuery   = regexp.QuoteMeta(`UPDATE schedule_runs SET step = $1, status = $2, hold_id = $3, transaction_id = $4, decline_reason = $5, updated_at = NOW()`)
+	outboxQuery      = regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)
+)
+
+// expectNoPendingRuns sets up the mock expectation for finding no interrupted runs to retry
+func expectNoPendingRuns(mockDb sqlmock.Sqlmock) {
+	mockDb.ExpectQuery(pendingRunsQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"run_id", "schedule_id", "account_id", "amount", "currency", "payee", "run_date", "step", "hold_id", "transaction_id"}))
+}
+
+// expectClaim sets up the mock expectations for claiming a monthly schedule's occurrence on runDate
+func expectClaim(mockDb sqlmock.Sqlmock, runDate time.Time, end interface{}) {
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(dueScheduleQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"schedule_id", "account_id", "amount", "currency", "payee", "frequency", "start_date", "end_date", "next_run_date"}).
+			AddRow(testScheduleID, testAccountID, int64(50000), "GBP", "Landlord", "MONTHLY", date("2026-01-31"), end, runDate))
+	mockDb.ExpectExec(runInsertQuery).
+		WithArgs(sqlmock.AnyArg(), testScheduleID, runDate, "CLAIMED", "PENDING").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// expectNothingDue sets up the mock expectation for finding no more due schedules
+func expectNothingDue(mockDb sqlmock.Sqlmock) {
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(dueScheduleQuery).WillReturnRows(sqlmock.NewRows([]string{"schedule_id"}))
+	mockDb.ExpectRollback()
+}
+
+// expectFinish sets up the mock expectations for finishing a run and queueing its schedule:run event
+func expectFinish(mockDb sqlmock.Sqlmock, runStatus string, holdID, transactionID, reason interface{}) {
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(runFinishQuery).
+		WithArgs("DONE", runStatus, holdID, transactionID, reason, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(outboxQuery).
+		WithArgs("schedule:run", testScheduleID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+}
+
+func TestCreateSchedule(t *testing.T) {
+	s, mockDb, _, _ := newTestServer(t)
+	defer s.db.Close()
+
+	start := today().AddDate(0, 0, 3)
+	req := &pb.CreateScheduleRequest{
+		AccountId: testAccountID,
+		Amount:    50000,
+		Currency:  "gbp",
+		Payee:     "Landlord",
+		Frequency: "MONTHLY",
+		StartDate: start.Format(dateLayout),
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO schedules`)).
+		WithArgs(sqlmock.AnyArg(), testAccountID, int64(50000), "GBP", "Landlord", "", "MONTHLY", start, sqlmock.AnyArg(), start, "ACTIVE").
+		WillReturnRows(sqlmock.NewRows(scheduleColumns).
+			AddRow(testScheduleID, testAccountID, int64(50000), "GBP", "Landlord", "", "MONTHLY", start, nil, start, "ACTIVE", time.Now()))
+
+	schedule, err := s.CreateSchedule(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testScheduleID, schedule.Id)
+	assert.Equal(t, start.Format(dateLayout), schedule.NextRunDate)
+	assert.Empty(t, schedule.EndDate)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCreateSchedule_InvalidArgument(t *testing.T) {
+	s, mockDb, _, _ := newTestServer(t)
+	defer s.db.Close()
+
+	tomorrow := today().AddDate(0, 0, 1).Format(dateLayout)
+	yesterday := today().AddDate(0, 0, -1).Format(dateLayout)
+	valid := func() *pb.CreateScheduleRequest {
+		return &pb.CreateScheduleRequest{AccountId: testAccountID, Amount: 100, Currency: "GBP", Payee: "Landlord", Frequency: "WEEKLY", StartDate: tomorrow}
+	}
+
+	for name, mutate := range map[string]func(*pb.CreateScheduleRequest){
+		"amount":     func(r *pb.CreateScheduleRequest) { r.Amount = 0 },
+		"payee":      func(r *pb.CreateScheduleRequest) { r.Payee = " " },
+		"frequency":  func(r *pb.CreateScheduleRequest) { r.Frequency = "DAILY" },
+		"past start": func(r *pb.CreateScheduleRequest) { r.StartDate = yesterday },
+		"end":        func(r *pb.CreateScheduleRequest) { r.EndDate = yesterday },
+	} {
+		req := valid()
+		mutate(req)
+		_, err := s.CreateSchedule(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestCancelSchedule_AlreadyCompleted(t *testing.T) {
+	s, mockDb, _, _ := newTestServer(t)
+	defer s.db.Close()
+
+	start := date("2026-01-31")
+	mockDb.ExpectQuery(regexp.QuoteMeta(`UPDATE schedules SET status = $1, next_run_date = NULL, updated_at = NOW() WHERE schedule_id = $2 AND status = 'ACTIVE'`)).
+		WithArgs("CANCELLED", testScheduleID).
+		WillReturnRows(sqlmock.NewRows(scheduleColumns))
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectScheduleQuery + ` WHERE schedule_id = $1`)).
+		WithArgs(testScheduleID).
+		WillReturnRows(sqlmock.NewRows(scheduleColumns).
+			AddRow(testScheduleID, testAccountID, int64(50000), "GBP", "Landlord", "", "ONCE", start, nil, nil, "COMPLETED", time.Now()))
+
+	_, err := s.CancelSchedule(context.Background(), &pb.ScheduleID{ScheduleId: testScheduleID})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRunDueSchedules_Paid(t *testing.T) {
+	s, mockDb, balanceClient, transactionsClient := newTestServer(t)
+	defer s.db.Close()
+
+	// The occurrence on 31 January moves the schedule on to the last day of February
+	runDate := date("2026-01-31")
+	expectNoPendingRuns(mockDb)
+	expectClaim(mockDb, runDate, nil)
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE schedules SET next_run_date = $1, updated_at = NOW() WHERE schedule_id = $2`)).
+		WithArgs(date("2026-02-28"), testScheduleID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	balanceClient.On("AuthorizeDebit", mock.Anything, mock.MatchedBy(func(req *balancepb.AuthorizeDebitRequest) bool {
+		return req.AccountId == testAccountID && req.Amount == 50000 && req.Currency == "GBP" && req.IdempotencyKey != ""
+	})).Return(&balancepb.DebitResult{Success: true, HoldId: "hold-1"}, nil).Once()
+	mockDb.ExpectExec(runAdvanceQuery).
+		WithArgs("DEBIT_AUTHORIZED", "hold-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	balanceClient.On("CaptureHold", mock.Anything, &balancepb.CaptureHoldRequest{HoldId: "hold-1"}).
+		Return(&balancepb.BalanceResponse{AccountId: testAccountID}, nil).Once()
+	mockDb.ExpectExec(runAdvanceQuery).
+		WithArgs("CAPTURED", "hold-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	transactionsClient.On("RecordTransaction", mock.Anything, mock.MatchedBy(func(req *transactionspb.TransactionInput) bool {
+		return req.MerchantRaw == "Landlord" && req.Status == "SETTLED" && req.HoldId == "hold-1" && req.IdempotencyKey != ""
+	})).Return(&transactionspb.Transaction{Id: "txn-1"}, nil).Once()
+	expectFinish(mockDb, "PAID", "hold-1", "txn-1", nil)
+	expectNothingDue(mockDb)
+
+	resp, err := s.RunDueSchedules(context.Background(), &pb.RunDueSchedulesRequest{Date: "2026-01-31"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.Started)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+	transactionsClient.AssertExpectations(t)
+}
+
+func TestRunDueSchedules_DeclinedLastOccurrence(t *testing.T) {
+	s, mockDb, balanceClient, transactionsClient := newTestServer(t)
+	defer s.db.Close()
+
+	// The schedule ends before the next occurrence, so claiming this one completes it
+	runDate := date("2026-01-31")
+	expectNoPendingRuns(mockDb)
+	expectClaim(mockDb, runDate, date("2026-02-15"))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE schedules SET next_run_date = NULL, status = $1, updated_at = NOW() WHERE schedule_id = $2`)).
+		WithArgs("COMPLETED", testScheduleID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	balanceClient.On("AuthorizeDebit", mock.Anything, mock.AnythingOfType("*balance.AuthorizeDebitRequest")).
+		Return(&balancepb.DebitResult{Success: false, ErrorMessage: "insufficient funds"}, nil).Once()
+	expectFinish(mockDb, "DECLINED", nil, nil, "insufficient funds")
+	expectNothingDue(mockDb)
+
+	resp, err := s.RunDueSchedules(context.Background(), &pb.RunDueSchedulesRequest{Date: "2026-01-31"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.Started)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+	transactionsClient.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+}
+
+func TestRunDueSchedules_ResumesInterruptedRun(t *testing.T) {
+	s, mockDb, balanceClient, transactionsClient := newTestServer(t)
+	defer s.db.Close()
+
+	// An earlier attempt captured the hold but stopped before recording the step
+	mockDb.ExpectQuery(pendingRunsQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"run_id", "schedule_id", "account_id", "amount", "currency", "payee", "run_date", "step", "hold_id", "transaction_id"}).
+			AddRow("run-1", testScheduleID, testAccountID, int64(50000), "GBP", "Landlord", date("2026-01-31"), "DEBIT_AUTHORIZED", "hold-1", nil))
+	balanceClient.On("CaptureHold", mock.Anything, &balancepb.CaptureHoldRequest{HoldId: "hold-1"}).
+		Return(nil, status.Error(codes.FailedPrecondition, "hold is CAPTURED")).Once()
+	mockDb.ExpectExec(runAdvanceQuery).
+		WithArgs("CAPTURED", "hold-1", "run-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	transactionsClient.On("RecordTransaction", mock.Anything, mock.MatchedBy(func(req *transactionspb.TransactionInput) bool {
+		return req.IdempotencyKey == "schedule-run:run-1"
+	})).Return(&transactionspb.Transaction{Id: "txn-1"}, nil).Once()
+	expectFinish(mockDb, "PAID", "hold-1", "txn-1", nil)
+	expectNothingDue(mockDb)
+
+	resp, err := s.RunDueSchedules(context.Background(), &pb.RunDueSchedulesRequest{Date: "2026-02-01"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), resp.Started)
+	assert.Equal(t, uint32(1), resp.Retried)
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	balanceClient.AssertExpectations(t)
+	transactionsClient.AssertExpectations(t)
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS transactions_card_auth_code_idx;
+ALTER TABLE transactions DROP COLUMN IF EXISTS auth_code;
@@ -0,0 +1,4 @@
+-- Authorization code issued when a card authorization is approved; settlement records quote it back
+ALTER TABLE transactions ADD COLUMN auth_code TEXT;
+
+CREATE INDEX transactions_card_auth_code_idx ON transactions(card_id, auth_code) WHERE auth_code IS NOT NULL;
@@ -7,9 +7,41 @@ CREATE TABLE transactions (
     merchant_id UUID, -- optional, can be set after enrichment
     merchant_raw TEXT, -- raw merchant description
     category TEXT, -- optional category
-    status TEXT NOT NULL, -- e.g., 'AUTHORIZED','SETTLED','REVERSED'
+    status TEXT NOT NULL, -- e.g., 'PENDING','AUTHORIZED','DECLINED','SETTLED','PARTIALLY_REFUNDED','REVERSED'
+    hold_id UUID, -- balance hold placed at authorization, captured on settlement
+    billing_currency TEXT, -- set when converted into the account's currency at authorization
+    billing_amount BIGINT, -- converted amount in billing_currency, excluding fx_fee
+    fx_rate NUMERIC, -- units of billing_currency per unit of currency
+    fx_fee BIGINT, -- conversion fee in billing_currency
+    auth_code TEXT, -- authorization code issued on approval, quoted back in settlement files
+    original_transaction_id UUID REFERENCES transactions(id), -- set on a refund, the transaction it refunds
+    refunded_amount BIGINT NOT NULL DEFAULT 0, -- total refunded against this transaction
     created_at TIMESTAMP NOT NULL DEFAULT now()
 );
 
 CREATE INDEX transactions_account_id_idx ON transactions(account_id);
+CREATE INDEX transactions_hold_id_idx ON transactions(hold_id);
+CREATE INDEX transactions_card_auth_code_idx ON transactions(card_id, auth_code) WHERE auth_code IS NOT NULL;
+CREATE INDEX transactions_original_transaction_id_idx ON transactions(original_transaction_id) WHERE original_transaction_id IS NOT NULL;
 -- CREATE INDEX transactions_card_id_idx ON transactions(card_id); -- Optional index
+
+CREATE TABLE idempotency_keys (
+    scope TEXT NOT NULL, -- RPC the key was used with, e.g. 'RecordTransaction'
+    idempotency_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL, -- sha256 of the request, to reject a key reused with a different payload
+    response BYTEA, -- serialized response returned to the first caller
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY, -- publish order
+    stream TEXT NOT NULL, -- Redis stream the event is published to, e.g. 'balance:updated'
+    aggregate_id TEXT NOT NULL, -- entity the event is about; events of one aggregate are published in order
+    payload TEXT NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}', -- extra stream fields published with the payload, e.g. schema_version
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    sent_at TIMESTAMP -- NULL until the relay has published the event
+);
+
+CREATE INDEX outbox_unsent_idx ON outbox(id) WHERE sent_at IS NULL;
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/pan"
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// maxIssueAttempts is how many card numbers IssueCardNumber generates before giving up, should each
+// already belong to another card
+const maxIssueAttempts = 3
+
+// IssueCardNumber generates the number and CVV of a new card from the BIN given and stores them,
+// returning only their token and the number's last four digits. Generating them here means they
+// never exist outside the vault; the cardholder's details are read back with Detokenize by the
+// services granted them, such as card production.
+func (s *server) IssueCardNumber(ctx context.Context, req *vaultpb.IssueCardNumberRequest) (*vaultpb.IssuedCardNumber, error) {
+	log.Printf("Received IssueCardNumber request for BIN %s", req.GetBin())
+
+	for attempt := 1; attempt <= maxIssueAttempts; attempt++ {
+		number, err := pan.Generate(req.GetBin(), int(req.GetPanLength()))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		cvv, err := generateCVV()
+		if err != nil {
+			log.Printf("failed to generate CVV: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to issue card number")
+		}
+
+		token, err := s.storeCardData(ctx, number, cvv)
+		if err == errCardNumberTaken {
+			// Another card already has this number
+			continue
+		}
+		if err != nil {
+			log.Printf("failed to store card data: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to issue card number")
+		}
+		return &vaultpb.IssuedCardNumber{Token: token, LastFour: pan.LastFour(number)}, nil
+	}
+	log.Printf("generated card numbers from BIN %s collided with existing cards %d times", req.GetBin(), maxIssueAttempts)
+	return nil, status.Errorf(codes.Internal, "failed to issue card number")
+}
+
+// generateCVV returns a random three digit card verification value
+func generateCVV() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%03d", n.Int64()), nil
+}
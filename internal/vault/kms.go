@@ -0,0 +1,173 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// keySize is the size of data keys and key-encryption keys, for AES-256
+const keySize = 32
+
+// kms encrypts data keys with key-encryption keys that never leave it. Keys are versioned so
+// they can be rotated; new data keys are always encrypted with the primary, newest, version.
+type kms interface {
+	PrimaryVersion() int32
+	WrapKey(dataKey []byte) (wrapped []byte, version int32, err error)
+	UnwrapKey(wrapped []byte, version int32) ([]byte, error)
+}
+
+// keyFile is the local KMS's key file. Old key versions must stay in it until RotateKeys has
+// re-encrypted everything under them.
+type keyFile struct {
+	Keys []keyVersion `json:"keys"`
+}
+
+type keyVersion struct {
+	Version int32  `json:"version"`
+	Key     []byte `json:"key"` // base64 in the file
+}
+
+// localKMS is a stand-in for a real KMS, holding key-encryption keys read from a key file
+type localKMS struct {
+	keys    map[int32][]byte
+	primary int32
+}
+
+// loadLocalKMS reads the key-encryption keys in the key file at path
+func loadLocalKMS(path string) (*localKMS, error) {
+	f, err := readKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Keys) == 0 {
+		return nil, fmt.Errorf("%s has no keys", path)
+	}
+	k := &localKMS{keys: make(map[int32][]byte, len(f.Keys))}
+	for _, v := range f.Keys {
+		if len(v.Key) != keySize {
+			return nil, fmt.Errorf("%s: key version %d is %d bytes, not %d", path, v.Version, len(v.Key), keySize)
+		}
+		if _, ok := k.keys[v.Version]; ok {
+			return nil, fmt.Errorf("%s: duplicate key version %d", path, v.Version)
+		}
+		k.keys[v.Version] = v.Key
+		if v.Version > k.primary {
+			k.primary = v.Version
+		}
+	}
+	return k, nil
+}
+
+// addKeyVersion generates a new key-encryption key in the key file at path, creating the file if
+// it doesn't exist, and returns its version. The vault uses it once restarted.
+func addKeyVersion(path string) (int32, error) {
+	f, err := readKeyFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = &keyFile{}, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return 0, err
+	}
+	version := int32(1)
+	for _, v := range f.Keys {
+		if v.Version >= version {
+			version = v.Version + 1
+		}
+	}
+	f.Keys = append(f.Keys, keyVersion{Version: version, Key: key})
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func readKeyFile(path string) (*keyFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f keyFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (k *localKMS) PrimaryVersion() int32 {
+	return k.primary
+}
+
+func (k *localKMS) WrapKey(dataKey []byte) ([]byte, int32, error) {
+	wrapped, err := seal(k.keys[k.primary], dataKey, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	return wrapped, k.primary, nil
+}
+
+func (k *localKMS) UnwrapKey(wrapped []byte, version int32) ([]byte, error) {
+	key, ok := k.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("no key version %d", version)
+	}
+	return unseal(key, wrapped, nil)
+}
+
+// newKey generates a random AES-256 key
+func newKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
+
+// seal encrypts and authenticates plaintext with AES-GCM under key, binding it to aad so it
+// can't be opened in another context. The nonce is prepended to the result.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// unseal decrypts what seal encrypted with the same key and aad
+func unseal(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("sealed data too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	vaultpb "github.com/manifoldfinance/disco2/v2/pkg/pb/vault"
+)
+
+// Bounds on how many entries RotateKeys re-encrypts at once
+const (
+	defaultRotateBatchSize = 100
+	maxRotateBatchSize     = 1000
+)
+
+// staleEntry is a vault entry encrypted under a key-encryption key older than the primary
+type staleEntry struct {
+	token      string
+	ciphertext []byte
+	wrappedKey []byte
+	keyVersion int32
+}
+
+// RotateKeys re-encrypts a batch of entries still under older key-encryption keys with new data keys
+// under the primary key. Call it until nothing remains, after which older keys can be removed from
+// the KMS. Entries locked by another rotation are skipped, so batches can run concurrently.
+func (s *server) RotateKeys(ctx context.Context, req *vaultpb.RotateKeysRequest) (*vaultpb.RotateKeysResponse, error) {
+	log.Printf("Received RotateKeys request: %+v", req)
+
+	batchSize := req.GetBatchSize()
+	if batchSize < 0 || batchSize > maxRotateBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch size must be between 0 and %d", maxRotateBatchSize)
+	}
+	if batchSize == 0 {
+		batchSize = defaultRotateBatchSize
+	}
+	primary := s.kms.PrimaryVersion()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	query := `SELECT token, ciphertext, wrapped_key, key_version FROM vault_entries WHERE key_version <> $1
+			  ORDER BY token LIMIT $2 FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, query, primary, batchSize)
+	if err != nil {
+		log.Printf("failed to select entries to rotate: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+	}
+	var stale []staleEntry
+	for rows.Next() {
+		var e staleEntry
+		if err := rows.Scan(&e.token, &e.ciphertext, &e.wrappedKey, &e.keyVersion); err != nil {
+			rows.Close()
+			log.Printf("failed to scan entry to rotate: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+		}
+		stale = append(stale, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to select entries to rotate: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+	}
+
+	update := `UPDATE vault_entries SET ciphertext = $2, wrapped_key = $3, key_version = $4, rotated_at = NOW() WHERE token = $1`
+	for _, e := range stale {
+		data, err := s.openCardData(e.token, e.ciphertext, e.wrappedKey, e.keyVersion)
+		if err != nil {
+			log.Printf("failed to decrypt vault entry %s: %v", e.token, err)
+			return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+		}
+		ciphertext, wrappedKey, keyVersion, err := s.sealCardData(e.token, data)
+		if err != nil {
+			log.Printf("failed to encrypt vault entry %s: %v", e.token, err)
+			return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+		}
+		if _, err := tx.ExecContext(ctx, update, e.token, ciphertext, wrappedKey, keyVersion); err != nil {
+			log.Printf("failed to update vault entry %s: %v", e.token, err)
+			return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+	}
+
+	var remaining int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vault_entries WHERE key_version <> $1`, primary).Scan(&remaining); err != nil {
+		log.Printf("failed to count entries left to rotate: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate keys")
+	}
+
+	log.Printf("Re-encrypted %d vault entries under key version %d, %d remaining", len(stale), primary, remaining)
+	return &vaultpb.RotateKeysResponse{KeyVersion: primary, Reencrypted: int32(len(stale)), Remaining: remaining}, nil
+}
@@ -0,0 +1,25 @@
+-- Card details, each encrypted with its own data key. The data key is stored encrypted with a
+-- key-encryption key held by the KMS; neither card details nor data keys are stored in the clear.
+CREATE TABLE vault_entries (
+    token TEXT PRIMARY KEY, -- what other services know the card details by
+    fingerprint TEXT NOT NULL UNIQUE, -- keyed hash of the card number, to look up its token
+    ciphertext BYTEA NOT NULL, -- card number, CVV and PIN block encrypted with the data key
+    wrapped_key BYTEA NOT NULL, -- data key encrypted with the key-encryption key
+    key_version INT NOT NULL, -- version of the key-encryption key
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    rotated_at TIMESTAMP -- when the entry was last re-encrypted under a newer key
+);
+
+CREATE INDEX vault_entries_key_version_idx ON vault_entries(key_version);
+
+-- Every Detokenize request, whether or not it was granted
+CREATE TABLE detokenize_audit (
+    id BIGSERIAL PRIMARY KEY,
+    token TEXT NOT NULL,
+    caller TEXT NOT NULL,
+    purpose TEXT NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX detokenize_audit_token_idx ON detokenize_audit(token);
@@ -0,0 +1,6 @@
+CREATE TABLE users (
+    user_id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL UNIQUE, -- stored lower-cased
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
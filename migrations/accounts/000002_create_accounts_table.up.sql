@@ -0,0 +1,20 @@
+-- An account's ID is also its account ID in the balance service, which holds the money
+CREATE TABLE accounts (
+    account_id UUID PRIMARY KEY,
+    account_type TEXT NOT NULL CHECK (account_type IN ('personal', 'joint', 'business')),
+    name TEXT NOT NULL DEFAULT '', -- business name of a business account
+    currency CHAR(3) NOT NULL, -- ISO 4217 code
+    status TEXT NOT NULL DEFAULT 'OPEN', -- 'OPEN' or 'CLOSED'
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Who holds which account; a user can hold several accounts and an account have several holders
+CREATE TABLE account_holders (
+    account_id UUID NOT NULL REFERENCES accounts(account_id),
+    user_id UUID NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, user_id)
+);
+
+CREATE INDEX account_holders_user_id_idx ON account_holders(user_id);
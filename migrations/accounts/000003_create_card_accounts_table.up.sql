@@ -0,0 +1,9 @@
+-- The account a card's payments are taken from, for cards linked to one
+CREATE TABLE card_accounts (
+    card_id TEXT PRIMARY KEY, -- card ID in the cards service
+    account_id UUID NOT NULL REFERENCES accounts(account_id),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX card_accounts_account_id_idx ON card_accounts(account_id);
@@ -0,0 +1,53 @@
+CREATE TABLE ledger_entries (
+    entry_id BIGSERIAL PRIMARY KEY,
+    journal_id UUID NOT NULL, -- entries of one posting share a journal and sum to zero
+    account_id TEXT NOT NULL, -- customer account UUID or a system account, e.g. 'system:funding'
+    amount BIGINT NOT NULL, -- positive for credit, negative for debit
+    balance_after BIGINT, -- running balance after this entry; NULL for system accounts
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ledger_entries_account_id_idx ON ledger_entries(account_id, entry_id DESC);
+CREATE INDEX ledger_entries_journal_id_idx ON ledger_entries(journal_id);
+
+-- Ledger entries are immutable: corrections are posted as new journals.
+CREATE FUNCTION ledger_entries_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_no_update
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_immutable();
+
+-- Every journal must balance. Checked at commit so all legs can be inserted first.
+CREATE FUNCTION ledger_journal_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE journal_id = NEW.journal_id) <> 0 THEN
+        RAISE EXCEPTION 'journal % does not balance', NEW.journal_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_journal_balanced();
+
+-- Open the ledger with the balances accounts already hold so that
+-- SUM(amount) per account matches accounts.balance from the start.
+CREATE TEMPORARY TABLE opening_journals AS
+    SELECT account_id::TEXT AS account_id, balance, gen_random_uuid() AS journal_id
+    FROM accounts WHERE balance <> 0;
+
+INSERT INTO ledger_entries (journal_id, account_id, amount, balance_after, description)
+    SELECT journal_id, account_id, balance, balance, 'opening balance' FROM opening_journals;
+
+INSERT INTO ledger_entries (journal_id, account_id, amount, balance_after, description)
+    SELECT journal_id, 'system:opening_balance', -balance, NULL, 'opening balance'
+    FROM opening_journals;
+
+DROP TABLE opening_journals;
@@ -0,0 +1,16 @@
+ALTER TABLE accounts ADD COLUMN held BIGINT NOT NULL DEFAULT 0; -- sum of active holds, in cents
+
+CREATE TABLE holds (
+    hold_id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(account_id),
+    amount BIGINT NOT NULL CHECK (amount > 0), -- in cents
+    status TEXT NOT NULL CHECK (status IN ('ACTIVE','CAPTURED','RELEASED','EXPIRED')),
+    captured_amount BIGINT, -- settled amount, may differ from the held amount
+    journal_id UUID, -- ledger journal the capture was posted in
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX holds_account_id_idx ON holds(account_id);
+CREATE INDEX holds_active_expires_at_idx ON holds(expires_at) WHERE status = 'ACTIVE';
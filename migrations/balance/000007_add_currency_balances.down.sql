@@ -0,0 +1,27 @@
+CREATE OR REPLACE FUNCTION ledger_journal_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE journal_id = NEW.journal_id) <> 0 THEN
+        RAISE EXCEPTION 'journal % does not balance', NEW.journal_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE holds
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS original_currency,
+    DROP COLUMN IF EXISTS original_amount,
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS fx_fee;
+
+-- Only the balance in each account's own currency can be kept
+ALTER TABLE accounts ADD COLUMN balance BIGINT NOT NULL DEFAULT 0, ADD COLUMN held BIGINT NOT NULL DEFAULT 0;
+
+UPDATE accounts a SET balance = b.balance, held = b.held
+    FROM balances b WHERE b.account_id = a.account_id AND b.currency = a.currency;
+
+DROP TABLE IF EXISTS balances;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,37 @@
+-- Accounts hold a balance per currency. Payments in a currency the account holds no
+-- balance in are converted into the account's own currency.
+ALTER TABLE accounts ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'GBP'; -- ISO 4217 code
+
+CREATE TABLE balances (
+    account_id UUID NOT NULL REFERENCES accounts(account_id),
+    currency CHAR(3) NOT NULL, -- ISO 4217 code
+    balance BIGINT NOT NULL DEFAULT 0, -- in minor units of currency
+    held BIGINT NOT NULL DEFAULT 0, -- sum of active holds, in minor units of currency
+    updated_at TIMESTAMP,
+    PRIMARY KEY (account_id, currency)
+);
+
+INSERT INTO balances (account_id, currency, balance, held, updated_at)
+    SELECT account_id, currency, balance, held, updated_at FROM accounts;
+
+ALTER TABLE accounts DROP COLUMN balance, DROP COLUMN held;
+
+ALTER TABLE holds
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'GBP', -- currency of amount, i.e. of the balance held against
+    ADD COLUMN original_currency CHAR(3), -- currency the debit was requested in, when it was converted
+    ADD COLUMN original_amount BIGINT, -- requested amount in minor units of original_currency
+    ADD COLUMN fx_rate NUMERIC, -- units of currency per unit of original_currency
+    ADD COLUMN fx_fee BIGINT; -- conversion fee in minor units of currency, included in amount
+
+ALTER TABLE ledger_entries ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'GBP';
+
+-- Journals balance per currency; conversions pass through the 'system:fx' account.
+CREATE OR REPLACE FUNCTION ledger_journal_balanced() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM ledger_entries WHERE journal_id = NEW.journal_id
+               GROUP BY currency HAVING SUM(amount) <> 0) THEN
+        RAISE EXCEPTION 'journal % does not balance', NEW.journal_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS overdraft_accruals;
+ALTER TABLE accounts DROP COLUMN IF EXISTS overdraft_limit;
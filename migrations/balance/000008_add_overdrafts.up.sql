@@ -0,0 +1,14 @@
+-- An arranged overdraft lets the balance in an account's currency go negative down to -overdraft_limit
+ALTER TABLE accounts ADD COLUMN overdraft_limit BIGINT NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0); -- in minor units of currency
+
+-- Daily overdraft charges, at most one per account and day
+CREATE TABLE overdraft_accruals (
+    account_id UUID NOT NULL REFERENCES accounts(account_id),
+    accrued_on DATE NOT NULL, -- day the account closed overdrawn, in UTC
+    overdrawn BIGINT NOT NULL, -- closing overdrawn amount the interest was worked out on, in minor units of the account's currency
+    interest BIGINT NOT NULL,
+    fee BIGINT NOT NULL,
+    journal_id UUID NOT NULL, -- ledger journal the charges were posted in
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, accrued_on)
+);
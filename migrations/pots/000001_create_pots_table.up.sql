@@ -0,0 +1,15 @@
+-- A pot's ID is also its account ID in the balance service, which holds the money
+CREATE TABLE pots (
+    pot_id UUID PRIMARY KEY,
+    account_id UUID NOT NULL, -- the account that owns the pot and funds it
+    name TEXT NOT NULL,
+    currency CHAR(3) NOT NULL, -- ISO 4217 code, the same as the account's
+    goal_amount BIGINT NOT NULL DEFAULT 0 CHECK (goal_amount >= 0), -- in minor units of currency, 0 if none
+    target_date DATE,
+    status TEXT NOT NULL DEFAULT 'OPEN', -- 'OPEN' or 'CLOSED'
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX pots_account_id_idx ON pots(account_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pot_moves;
@@ -0,0 +1,11 @@
+-- Money moved in or out of a pot, at most once per move
+CREATE TABLE pot_moves (
+    move_id TEXT PRIMARY KEY, -- the idempotency key of the move, or a generated ID
+    pot_id UUID NOT NULL REFERENCES pots(pot_id),
+    direction TEXT NOT NULL, -- 'DEPOSIT' or 'WITHDRAWAL'
+    amount BIGINT NOT NULL CHECK (amount > 0), -- in minor units of the pot's currency
+    journal_id UUID NOT NULL, -- balance ledger journal the move was posted in
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX pot_moves_pot_id_idx ON pot_moves(pot_id);
@@ -0,0 +1,11 @@
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY, -- publish order
+    stream TEXT NOT NULL, -- Redis stream the event is published to, e.g. 'pot:moved'
+    aggregate_id TEXT NOT NULL, -- entity the event is about; events of one aggregate are published in order
+    payload TEXT NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}', -- extra stream fields published with the payload, e.g. schema_version
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    sent_at TIMESTAMP -- NULL until the relay has published the event
+);
+
+CREATE INDEX outbox_unsent_idx ON outbox(id) WHERE sent_at IS NULL;
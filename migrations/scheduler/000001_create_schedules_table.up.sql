@@ -0,0 +1,19 @@
+-- Standing orders and future-dated payments
+CREATE TABLE schedules (
+    schedule_id UUID PRIMARY KEY,
+    account_id UUID NOT NULL, -- account the payments are made from
+    amount BIGINT NOT NULL CHECK (amount > 0), -- in minor units of currency
+    currency CHAR(3) NOT NULL,
+    payee TEXT NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    frequency TEXT NOT NULL, -- 'ONCE', 'WEEKLY', 'MONTHLY' or 'LAST_WORKING_DAY'
+    start_date DATE NOT NULL, -- monthly schedules pay on this day of the month, or the month's last day if it is shorter
+    end_date DATE, -- NULL to continue until cancelled
+    next_run_date DATE, -- next occurrence to claim; NULL once there are no more
+    status TEXT NOT NULL DEFAULT 'ACTIVE', -- 'ACTIVE', 'COMPLETED' or 'CANCELLED'
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX schedules_account_id_idx ON schedules(account_id);
+CREATE INDEX schedules_due_idx ON schedules(next_run_date) WHERE status = 'ACTIVE';
@@ -0,0 +1,17 @@
+-- One row per occurrence of a schedule, so each occurrence is paid at most once
+CREATE TABLE schedule_runs (
+    run_id UUID PRIMARY KEY, -- also the idempotency key of the run's calls to the balance and transactions services
+    schedule_id UUID NOT NULL REFERENCES schedules(schedule_id),
+    run_date DATE NOT NULL, -- the occurrence being paid
+    step TEXT NOT NULL, -- last step known to have completed, see internal/scheduler/service
+    status TEXT NOT NULL, -- 'PENDING', 'PAID', 'DECLINED' or 'FAILED'
+    hold_id TEXT,
+    transaction_id TEXT,
+    decline_reason TEXT,
+    attempts INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(), -- a PENDING run not updated for a while was interrupted and is retried
+    UNIQUE (schedule_id, run_date)
+);
+
+CREATE INDEX schedule_runs_pending_idx ON schedule_runs(updated_at) WHERE status = 'PENDING';
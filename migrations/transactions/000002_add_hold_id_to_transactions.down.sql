@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS transactions_hold_id_idx;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS hold_id;
@@ -0,0 +1,3 @@
+ALTER TABLE transactions ADD COLUMN hold_id UUID; -- balance hold placed at authorization, captured on settlement
+
+CREATE INDEX transactions_hold_id_idx ON transactions(hold_id);
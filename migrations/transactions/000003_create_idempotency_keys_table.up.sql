@@ -0,0 +1,8 @@
+CREATE TABLE idempotency_keys (
+    scope TEXT NOT NULL, -- RPC the key was used with, e.g. 'AuthorizeDebit'
+    idempotency_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL, -- sha256 of the request, to reject a key reused with a different payload
+    response BYTEA, -- serialized response returned to the first caller
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (scope, idempotency_key)
+);
@@ -0,0 +1 @@
+ALTER TABLE outbox ADD COLUMN headers JSONB NOT NULL DEFAULT '{}'; -- extra stream fields published with the payload, e.g. schema_version
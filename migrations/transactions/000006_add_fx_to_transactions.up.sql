@@ -0,0 +1,6 @@
+-- Set when the transaction was converted into the account's currency at authorization
+ALTER TABLE transactions
+    ADD COLUMN billing_currency TEXT, -- currency the account was debited in
+    ADD COLUMN billing_amount BIGINT, -- converted amount in billing_currency minor units, excluding fx_fee
+    ADD COLUMN fx_rate NUMERIC, -- units of billing_currency per unit of currency
+    ADD COLUMN fx_fee BIGINT; -- conversion fee in billing_currency minor units
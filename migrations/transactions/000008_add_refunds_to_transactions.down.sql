@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS transactions_original_transaction_id_idx;
+ALTER TABLE transactions DROP COLUMN IF EXISTS refunded_amount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS original_transaction_id;
@@ -0,0 +1,5 @@
+-- A refund is recorded as its own transaction, linked to the transaction it refunds
+ALTER TABLE transactions ADD COLUMN original_transaction_id UUID REFERENCES transactions(id);
+ALTER TABLE transactions ADD COLUMN refunded_amount BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX transactions_original_transaction_id_idx ON transactions(original_transaction_id) WHERE original_transaction_id IS NOT NULL;
@@ -0,0 +1,48 @@
+// Package declines explains why a card payment was declined in words an account holder
+// understands, for feed items and push notifications.
+//
+// Card-Processing sets a DeclineCode on every declined authorization. Events carry the code by
+// name, so consumers that don't import the card processing API can use Parse to read it.
+package declines
+
+import (
+	cardprocessingpb "github.com/manifoldfinance/disco2/v2/pkg/pb/cardprocessing"
+)
+
+// explanations of each decline code, worded to follow "Your payment was declined."
+var explanations = map[cardprocessingpb.DeclineCode]string{
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN:              "Your card is frozen. Unfreeze it in the app to start using it again.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_CLOSED:              "This card has been closed. Use your replacement card instead.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS:       "You don't have enough money in your account.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED:           "It would take you over a spending limit on your card.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED:         "You've blocked payments to this kind of merchant.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD:          "It looked unusual, so we stopped it to keep your money safe. If it was you, try again.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_INVALID_CURRENCY:         "Your card can't be used to pay in this currency.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_SYSTEM_ERROR:             "Something went wrong on our side. Please try again.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_NOT_FOUND:           "We couldn't find the card that was used.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_INACTIVE:            "Your card isn't active yet. Activate it in the app to start using it.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_AUTHENTICATION_REQUIRED:  "We need to check it's really you. Try again and confirm the payment when asked.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CHANNEL_DISABLED:         "You've turned off this kind of payment for your card. Turn it back on in the app.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_INCORRECT_PIN:            "The PIN entered was wrong. You can view your PIN in the app.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_PIN_LOCKED:               "Your PIN is locked after too many wrong attempts. View your PIN in the app to unlock it.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_EXPIRED:             "This virtual card has passed the expiry date you gave it. Create a new one in the app.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_MERCHANT_LOCKED:          "This virtual card only works with the merchant that first used it. Create a new one for other merchants.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_ACCOUNT_UNAVAILABLE:      "This card isn't linked to an open account. Choose the account it pays from in the app.",
+	cardprocessingpb.DeclineCode_DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED: "It would take you over your arranged overdraft limit.",
+}
+
+// defaultExplanation is given for codes without an explanation of their own
+const defaultExplanation = "Please try again or use another payment method."
+
+// Explanation returns the explanation of code to show the account holder
+func Explanation(code cardprocessingpb.DeclineCode) string {
+	if explanation, ok := explanations[code]; ok {
+		return explanation
+	}
+	return defaultExplanation
+}
+
+// Parse returns the decline code with the given name, or DECLINE_CODE_UNSPECIFIED if there is none
+func Parse(name string) cardprocessingpb.DeclineCode {
+	return cardprocessingpb.DeclineCode(cardprocessingpb.DeclineCode_value[name])
+}
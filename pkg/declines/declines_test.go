@@ -0,0 +1,28 @@
+package declines
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cardprocessingpb "github.com/manifoldfinance/disco2/v2/pkg/pb/cardprocessing"
+)
+
+func TestExplanation(t *testing.T) {
+	// Every code a decline can be given has its own explanation
+	for value, name := range cardprocessingpb.DeclineCode_name {
+		code := cardprocessingpb.DeclineCode(value)
+		if code == cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED {
+			continue
+		}
+		assert.NotEqual(t, defaultExplanation, Explanation(code), name)
+	}
+
+	assert.Equal(t, defaultExplanation, Explanation(cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED))
+}
+
+func TestParse(t *testing.T) {
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_CARD_FROZEN, Parse("DECLINE_CODE_CARD_FROZEN"))
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, Parse("CARD_FROZEN"))
+	assert.Equal(t, cardprocessingpb.DeclineCode_DECLINE_CODE_UNSPECIFIED, Parse(""))
+}
@@ -0,0 +1,171 @@
+// Package events publishes and consumes the typed events defined in api/proto/events.proto.
+//
+// An event is one Redis stream message with three fields: "payload" holds the base64-encoded
+// protobuf message, "type" its message name and "schema_version" the wire format version.
+// Messages without a schema_version were published before events were versioned; their payload
+// is the JSON object the producers used to build by hand, and decodes into the same message.
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/manifoldfinance/disco2/v2/pkg/outbox"
+	"github.com/manifoldfinance/disco2/v2/pkg/streamconsumer"
+)
+
+// SchemaVersion is the wire format version written by this package
+const SchemaVersion = 1
+
+// Stream names
+const (
+	StreamTransactionCreated = "transaction:created"
+	StreamBalanceUpdated     = "balance:updated"
+	StreamCardCreated        = "card:created"
+	StreamCardStatusChanged  = "card:status_changed"
+	StreamFeedItemCreated    = "feed:item.created"
+	StreamMerchantUpdated    = "merchant:updated"
+	StreamPotMoved           = "pot:moved"
+	StreamScheduleRun        = "schedule:run"
+	StreamClearingReview     = "clearing:review"
+	StreamCardAuthDeclined   = "card:auth_declined"
+	StreamCardPinLocked      = "card:pin_locked"
+)
+
+// Stream message fields
+const (
+	payloadField = "payload"
+	typeField    = "type"
+	versionField = "schema_version"
+)
+
+var (
+	// ErrUnsupportedVersion is returned for events written by a newer wire format than this package understands
+	ErrUnsupportedVersion = errors.New("unsupported event schema version")
+	// ErrTypeMismatch is returned when an event is decoded into a message of a different type
+	ErrTypeMismatch = errors.New("event type mismatch")
+)
+
+// legacyDecoder reads pre-versioning JSON payloads, ignoring keys that have no matching field
+var legacyDecoder = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// Encode returns the payload and header fields for event
+func Encode(event proto.Message) (string, map[string]string, error) {
+	b, err := proto.Marshal(event)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	headers := map[string]string{
+		typeField:    typeName(event),
+		versionField: strconv.Itoa(SchemaVersion),
+	}
+	return base64.StdEncoding.EncodeToString(b), headers, nil
+}
+
+// Decode reads the fields of a stream message into event.
+//
+// Fields unknown to event are ignored, so consumers keep working when producers add fields.
+func Decode(values map[string]interface{}, event proto.Message) error {
+	payload, ok := values[payloadField].(string)
+	if !ok {
+		return errors.New("message has no payload field")
+	}
+
+	version, _ := values[versionField].(string)
+	if version == "" {
+		// Published before events were versioned
+		if err := legacyDecoder.Unmarshal([]byte(payload), event); err != nil {
+			return fmt.Errorf("failed to decode legacy payload: %w", err)
+		}
+		return nil
+	}
+
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return fmt.Errorf("invalid schema version %q: %w", version, err)
+	}
+	if v > SchemaVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, v)
+	}
+	if t, _ := values[typeField].(string); t != "" && t != typeName(event) {
+		return fmt.Errorf("%w: got %s, want %s", ErrTypeMismatch, t, typeName(event))
+	}
+
+	b, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	if err := proto.Unmarshal(b, event); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return nil
+}
+
+// Publish adds event to stream directly. Producers that change the database alongside
+// publishing should use Enqueue instead, so the event is only sent if the change commits.
+func Publish(ctx context.Context, redisClient *redis.Client, stream string, event proto.Message) (string, error) {
+	payload, headers, err := Encode(event)
+	if err != nil {
+		return "", err
+	}
+	values := map[string]interface{}{payloadField: payload}
+	for k, v := range headers {
+		values[k] = v
+	}
+
+	id, err := redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to publish %s event: %w", stream, err)
+	}
+	return id, nil
+}
+
+// Enqueue writes event to the outbox within tx, to be published on stream once tx commits
+func Enqueue(ctx context.Context, tx *sql.Tx, stream, aggregateID string, event proto.Message) error {
+	payload, headers, err := Encode(event)
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(ctx, tx, stream, aggregateID, payload, headers)
+}
+
+// Handler processes one event. Returning an error retries the event, see pkg/streamconsumer.
+type Handler[M proto.Message] func(ctx context.Context, event M) error
+
+// Subscribe consumes stream as a member of group, calling handle for each event until ctx is
+// cancelled. Messages that cannot be decoded are dead-lettered without being retried.
+func Subscribe[T any, M interface {
+	*T
+	proto.Message
+}](ctx context.Context, redisClient *redis.Client, stream, group string, handle Handler[M], opts ...streamconsumer.Option) error {
+	return streamconsumer.New(redisClient, stream, group, decodeHandler(handle), opts...).Run(ctx)
+}
+
+// decodeHandler adapts an event handler to a stream message handler
+func decodeHandler[T any, M interface {
+	*T
+	proto.Message
+}](handle Handler[M]) streamconsumer.Handler {
+	return func(ctx context.Context, msg redis.XMessage) error {
+		event := M(new(T))
+		if err := Decode(msg.Values, event); err != nil {
+			return streamconsumer.Permanent(fmt.Errorf("failed to decode message %s: %w", msg.ID, err))
+		}
+		return handle(ctx, event)
+	}
+}
+
+func typeName(event proto.Message) string {
+	return string(event.ProtoReflect().Descriptor().FullName())
+}
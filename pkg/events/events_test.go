@@ -0,0 +1,118 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+
+	eventspb "github.com/manifoldfinance/disco2/v2/pkg/pb/events"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	event := &eventspb.TransactionCreated{
+		Id:          "txn-123",
+		AccountId:   "acc-123",
+		Amount:      1500,
+		Currency:    "GBP",
+		MerchantRaw: `Joe's "Best" Coffee`,
+	}
+
+	payload, headers, err := Encode(event)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", headers["schema_version"])
+	assert.Equal(t, "TransactionCreated", headers["type"])
+
+	values := map[string]interface{}{"payload": payload}
+	for k, v := range headers {
+		values[k] = v
+	}
+	var decoded eventspb.TransactionCreated
+	assert.NoError(t, Decode(values, &decoded))
+	assert.Equal(t, event.GetId(), decoded.GetId())
+	assert.Equal(t, event.GetAmount(), decoded.GetAmount())
+	assert.Equal(t, event.GetMerchantRaw(), decoded.GetMerchantRaw())
+}
+
+func TestDecode_LegacyJSON(t *testing.T) {
+	values := map[string]interface{}{
+		"payload": `{"account_id": "acc-123", "new_balance": 900, "available_balance": 400, "removed_field": true}`,
+	}
+
+	var event eventspb.BalanceUpdated
+	assert.NoError(t, Decode(values, &event))
+	assert.Equal(t, "acc-123", event.GetAccountId())
+	assert.Equal(t, int64(900), event.GetNewBalance())
+	assert.Equal(t, int64(400), event.GetAvailableBalance())
+}
+
+func TestDecode_UnsupportedVersion(t *testing.T) {
+	values := map[string]interface{}{"payload": "", "schema_version": "2", "type": "BalanceUpdated"}
+
+	err := Decode(values, &eventspb.BalanceUpdated{})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestDecode_TypeMismatch(t *testing.T) {
+	payload, headers, err := Encode(&eventspb.CardCreated{CardId: "card-123"})
+	assert.NoError(t, err)
+	values := map[string]interface{}{"payload": payload, "schema_version": headers["schema_version"], "type": headers["type"]}
+
+	err = Decode(values, &eventspb.CardStatusChanged{})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+}
+
+func TestEnqueue(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	event := &eventspb.CardStatusChanged{CardId: "card-123", UserId: "user-123", NewStatus: "FROZEN"}
+	payload, _, err := Encode(event)
+	assert.NoError(t, err)
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs(StreamCardStatusChanged, "card-123", payload, []byte(`{"schema_version":"1","type":"CardStatusChanged"}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, Enqueue(ctx, tx, StreamCardStatusChanged, "card-123", event))
+	assert.NoError(t, tx.Commit())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestDecodeHandler(t *testing.T) {
+	payload, _, err := Encode(&eventspb.FeedItemCreated{FeedItemId: "item-1", AccountId: "acc-123"})
+	assert.NoError(t, err)
+
+	var handled []string
+	handler := decodeHandler(func(ctx context.Context, event *eventspb.FeedItemCreated) error {
+		handled = append(handled, event.GetFeedItemId())
+		if event.GetFeedItemId() == "item-3" {
+			return errors.New("feed unavailable")
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	err = handler(ctx, redis.XMessage{ID: "1-0", Values: map[string]interface{}{"payload": payload, "schema_version": "1", "type": "FeedItemCreated"}})
+	assert.NoError(t, err)
+
+	// Handler errors are retried
+	err = handler(ctx, redis.XMessage{ID: "3-0", Values: map[string]interface{}{"payload": `{"feed_item_id": "item-3", "account_id": "acc-123"}`}})
+	assert.EqualError(t, err, "feed unavailable")
+
+	// Undecodable messages are not passed to the handler
+	err = handler(ctx, redis.XMessage{ID: "2-0", Values: map[string]interface{}{"payload": "not json"}})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"item-1", "item-3"}, handled)
+}
@@ -0,0 +1,86 @@
+// Package idempotency deduplicates retried requests by storing each idempotency key
+// together with a hash of the request and the response it produced.
+//
+// Keys live in an idempotency_keys table in the service's own database and are claimed
+// inside the same transaction that moves the money, so a key is only recorded if the
+// work it guards is committed.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrKeyReused is returned when a key is presented again with a different request payload
+var ErrKeyReused = errors.New("idempotency key reused with a different request")
+
+// Hash returns a stable fingerprint of a request message
+func Hash(req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Claim reserves key within scope for the duration of tx.
+//
+// If the key is new, Claim returns false and the caller should do the work and call Complete
+// before committing. If the key was already used for the same request, the stored response is
+// unmarshalled into resp and Claim returns true; the caller should return resp as-is.
+// A concurrent claim on the same key blocks until the other transaction finishes.
+func Claim(ctx context.Context, tx *sql.Tx, scope, key string, req, resp proto.Message) (bool, error) {
+	requestHash, err := Hash(req)
+	if err != nil {
+		return false, err
+	}
+
+	insertQuery := `INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`
+	result, err := tx.ExecContext(ctx, insertQuery, scope, key, requestHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if claimed == 1 {
+		return false, nil
+	}
+
+	// The key has been used before; replay the stored response if the request matches
+	var storedHash string
+	var response []byte
+	selectQuery := `SELECT request_hash, response FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`
+	if err := tx.QueryRowContext(ctx, selectQuery, scope, key).Scan(&storedHash, &response); err != nil {
+		return false, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+	if storedHash != requestHash {
+		return false, ErrKeyReused
+	}
+	if err := proto.Unmarshal(response, resp); err != nil {
+		return false, fmt.Errorf("failed to unmarshal stored response: %w", err)
+	}
+	return true, nil
+}
+
+// Complete stores the response for a key claimed within tx
+func Complete(ctx context.Context, tx *sql.Tx, scope, key string, resp proto.Message) error {
+	b, err := proto.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	query := `UPDATE idempotency_keys SET response = $1 WHERE scope = $2 AND idempotency_key = $3`
+	if _, err := tx.ExecContext(ctx, query, b, scope, key); err != nil {
+		return fmt.Errorf("failed to store idempotency response: %w", err)
+	}
+	return nil
+}
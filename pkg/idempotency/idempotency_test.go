@@ -0,0 +1,111 @@
+package idempotency
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	balancepb "github.com/manifoldfinance/disco2/v2/pkg/pb/balance"
+)
+
+const (
+	insertQuery = `INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT DO NOTHING`
+	selectQuery = `SELECT request_hash, response FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`
+)
+
+func TestHash_StableAndPayloadSensitive(t *testing.T) {
+	a, err := Hash(&balancepb.CreditRequest{AccountId: "acc-123", Amount: 100, IdempotencyKey: "k"})
+	assert.NoError(t, err)
+	b, err := Hash(&balancepb.CreditRequest{AccountId: "acc-123", Amount: 100, IdempotencyKey: "k"})
+	assert.NoError(t, err)
+	c, err := Hash(&balancepb.CreditRequest{AccountId: "acc-123", Amount: 200, IdempotencyKey: "k"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestClaim_NewKey(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	req := &balancepb.CreditRequest{AccountId: "acc-123", Amount: 100, IdempotencyKey: "k"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(insertQuery)).
+		WithArgs("CreditAccount", "k", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+
+	replayed, err := Claim(ctx, tx, "CreditAccount", "k", req, &balancepb.BalanceResponse{})
+	assert.NoError(t, err)
+	assert.False(t, replayed)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestClaim_Replay(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	req := &balancepb.CreditRequest{AccountId: "acc-123", Amount: 100, IdempotencyKey: "k"}
+	requestHash, err := Hash(req)
+	assert.NoError(t, err)
+	stored, err := proto.Marshal(&balancepb.BalanceResponse{AccountId: "acc-123", CurrentBalance: 100})
+	assert.NoError(t, err)
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(insertQuery)).
+		WithArgs("CreditAccount", "k", requestHash).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+		WithArgs("CreditAccount", "k").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow(requestHash, stored))
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+
+	var resp balancepb.BalanceResponse
+	replayed, err := Claim(ctx, tx, "CreditAccount", "k", req, &resp)
+	assert.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, int64(100), resp.CurrentBalance)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestClaim_KeyReused(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	req := &balancepb.CreditRequest{AccountId: "acc-123", Amount: 100, IdempotencyKey: "k"}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(insertQuery)).
+		WithArgs("CreditAccount", "k", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+		WithArgs("CreditAccount", "k").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow("other", []byte{}))
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+
+	replayed, err := Claim(ctx, tx, "CreditAccount", "k", req, &balancepb.BalanceResponse{})
+	assert.ErrorIs(t, err, ErrKeyReused)
+	assert.False(t, replayed)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,119 @@
+package iso8583
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Client simulates a card network sending messages to an ISO 8583 listener. It sends one
+// message at a time and waits for its response, so it is meant for tests and local development.
+type Client struct {
+	conn net.Conn
+	spec *Spec
+
+	mu   sync.Mutex
+	stan int // system trace audit number of the last message sent
+}
+
+// Dial connects a Client to the listener at addr
+func Dial(addr string, spec *Spec) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn, spec), nil
+}
+
+// NewClient returns a Client that sends messages over conn
+func NewClient(conn net.Conn, spec *Spec) *Client {
+	return &Client{conn: conn, spec: spec}
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Send sends a message and returns the response to it. A message without a system trace audit
+// number or transmission time is given one.
+func (c *Client) Send(m *Message) (*Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !m.Has(FieldSTAN) {
+		c.stan = c.stan%999999 + 1
+		m.Set(FieldSTAN, fmt.Sprintf("%06d", c.stan))
+	}
+	if !m.Has(FieldTransmissionTime) {
+		m.Set(FieldTransmissionTime, time.Now().UTC().Format("0102150405"))
+	}
+
+	b, err := c.spec.Pack(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s message: %w", m.MTI, err)
+	}
+	if err := WriteFrame(c.conn, b); err != nil {
+		return nil, fmt.Errorf("failed to send %s message: %w", m.MTI, err)
+	}
+
+	b, err = ReadFrame(c.conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response to %s message: %w", m.MTI, err)
+	}
+	resp, err := c.spec.Unpack(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack response to %s message: %w", m.MTI, err)
+	}
+	return resp, nil
+}
+
+// Authorize sends a 0100 authorization request for an amount in minor units of an alphabetic
+// currency code, and returns the 0110 response
+func (c *Client) Authorize(cardID string, amount int64, currency, merchantID, merchantName string) (*Message, error) {
+	m, err := authorizationMessage("0100", cardID, amount, currency, merchantID, merchantName)
+	if err != nil {
+		return nil, err
+	}
+	return c.Send(m)
+}
+
+// Purchase sends a 0200 financial request, as for a single message purchase, and returns the 0210 response
+func (c *Client) Purchase(cardID string, amount int64, currency, merchantID, merchantName string) (*Message, error) {
+	m, err := authorizationMessage("0200", cardID, amount, currency, merchantID, merchantName)
+	if err != nil {
+		return nil, err
+	}
+	return c.Send(m)
+}
+
+// Reverse sends a 0400 reversal of the authorization approved with authCode, and returns the 0410 response
+func (c *Client) Reverse(cardID, authCode string, amount int64, currency string) (*Message, error) {
+	m, err := authorizationMessage("0400", cardID, amount, currency, "", "")
+	if err != nil {
+		return nil, err
+	}
+	m.Set(FieldAuthCode, authCode)
+	return c.Send(m)
+}
+
+func authorizationMessage(mti, cardID string, amount int64, currency, merchantID, merchantName string) (*Message, error) {
+	numeric, ok := CurrencyNumeric(currency)
+	if !ok {
+		return nil, fmt.Errorf("unknown currency %s", currency)
+	}
+	m := NewMessage(mti)
+	m.Set(FieldPAN, cardID)
+	m.Set(FieldProcessingCode, "000000")
+	m.Set(FieldAmount, strconv.FormatInt(amount, 10))
+	m.Set(FieldCurrency, numeric)
+	if merchantID != "" {
+		m.Set(FieldMerchantID, merchantID)
+	}
+	if merchantName != "" {
+		m.Set(FieldMerchantName, merchantName)
+	}
+	return m, nil
+}
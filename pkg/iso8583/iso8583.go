@@ -0,0 +1,391 @@
+// Package iso8583 packs and unpacks ISO 8583 card network messages.
+//
+// A message is a four digit message type indicator (MTI), a binary primary bitmap, a secondary
+// bitmap when any field above 64 is present, and then the fields present in ascending order, all
+// ASCII. Which fields exist and how each one is encoded is described by a Spec, which can be loaded
+// from a JSON file to match the processor on the other end. On the wire every message is preceded
+// by its length as a two byte big-endian integer.
+package iso8583
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Length encodings of a field
+const (
+	Fixed  = "FIXED"  // always Length characters
+	LLVar  = "LLVAR"  // two digit length prefix, at most Length characters
+	LLLVar = "LLLVAR" // three digit length prefix, at most Length characters
+)
+
+// Field types
+const (
+	Numeric          = "n"   // digits only; fixed length values are zero-padded on the left
+	Alphanumeric     = "an"  // fixed length values are space-padded on the right
+	AlphanumericSpec = "ans" // may include special characters; padded like an
+)
+
+// Fields used by card authorizations and reversals
+const (
+	FieldPAN              = 2
+	FieldProcessingCode   = 3
+	FieldAmount           = 4
+	FieldTransmissionTime = 7
+	FieldSTAN             = 11
+	FieldLocalTime        = 12
+	FieldLocalDate        = 13
+	FieldMCC              = 18
+	FieldPOSEntryMode     = 22
+	FieldRRN              = 37
+	FieldAuthCode         = 38
+	FieldResponseCode     = 39
+	FieldTerminalID       = 41
+	FieldMerchantID       = 42
+	FieldMerchantName     = 43
+	FieldCurrency         = 49
+	FieldPINData          = 52 // PIN block, never logged
+	FieldOriginalData     = 90
+)
+
+// Response codes carried in field 39 of a response
+const (
+	ResponseApproved           = "00"
+	ResponseDoNotHonour        = "05"
+	ResponseInvalidTransaction = "12"
+	ResponseInvalidAmount      = "13"
+	ResponseInvalidCard        = "14"
+	ResponseNoOriginal         = "25" // the transaction a reversal refers to could not be found
+	ResponseFormatError        = "30"
+	ResponseInsufficientFunds  = "51"
+	ResponseExpiredCard        = "54"
+	ResponseIncorrectPIN       = "55"
+	ResponseNotPermitted       = "57" // transaction not permitted to the cardholder
+	ResponseSuspectedFraud     = "59"
+	ResponseExceedsLimit       = "61" // exceeds an amount limit
+	ResponseRestrictedCard     = "62"
+	ResponsePINTriesExceeded   = "75"
+	ResponseSystemError        = "96"
+	// ResponseAuthenticationRequired asks for the cardholder to authenticate and the payment to be tried again
+	ResponseAuthenticationRequired = "1A"
+)
+
+// maxFrameLength is the largest message the two byte length header can describe
+const maxFrameLength = 0xFFFF
+
+// Field describes how one data element is encoded
+type Field struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`   // n, an or ans
+	Length int    `json:"length"` // exact length of a FIXED field, maximum length of a variable one
+	Prefix string `json:"prefix"` // FIXED, LLVAR or LLLVAR
+}
+
+// Spec describes the fields messages may carry, by field number from 2 to 128
+type Spec struct {
+	Fields map[int]Field `json:"fields"`
+}
+
+// DefaultSpec returns the fields of ISO 8583:1987 used for card authorizations. Field 2 carries
+// the card's identifier, so it allows up to 99 characters rather than the 19 digits of a PAN.
+func DefaultSpec() *Spec {
+	return &Spec{Fields: map[int]Field{
+		FieldPAN:              {Name: "Primary account number", Type: AlphanumericSpec, Length: 99, Prefix: LLVar},
+		FieldProcessingCode:   {Name: "Processing code", Type: Numeric, Length: 6, Prefix: Fixed},
+		FieldAmount:           {Name: "Amount, transaction", Type: Numeric, Length: 12, Prefix: Fixed},
+		FieldTransmissionTime: {Name: "Transmission date and time", Type: Numeric, Length: 10, Prefix: Fixed},
+		FieldSTAN:             {Name: "System trace audit number", Type: Numeric, Length: 6, Prefix: Fixed},
+		FieldLocalTime:        {Name: "Time, local transaction", Type: Numeric, Length: 6, Prefix: Fixed},
+		FieldLocalDate:        {Name: "Date, local transaction", Type: Numeric, Length: 4, Prefix: Fixed},
+		FieldMCC:              {Name: "Merchant category code", Type: Numeric, Length: 4, Prefix: Fixed},
+		FieldPOSEntryMode:     {Name: "Point of service entry mode", Type: Numeric, Length: 3, Prefix: Fixed},
+		FieldRRN:              {Name: "Retrieval reference number", Type: Alphanumeric, Length: 12, Prefix: Fixed},
+		FieldAuthCode:         {Name: "Authorization identification response", Type: Alphanumeric, Length: 6, Prefix: Fixed},
+		FieldResponseCode:     {Name: "Response code", Type: Alphanumeric, Length: 2, Prefix: Fixed},
+		FieldTerminalID:       {Name: "Card acceptor terminal identification", Type: AlphanumericSpec, Length: 8, Prefix: Fixed},
+		FieldMerchantID:       {Name: "Card acceptor identification code", Type: AlphanumericSpec, Length: 15, Prefix: Fixed},
+		FieldMerchantName:     {Name: "Card acceptor name/location", Type: AlphanumericSpec, Length: 40, Prefix: Fixed},
+		FieldCurrency:         {Name: "Currency code, transaction", Type: Numeric, Length: 3, Prefix: Fixed},
+		FieldPINData:          {Name: "Personal identification number data", Type: AlphanumericSpec, Length: 16, Prefix: Fixed},
+		FieldOriginalData:     {Name: "Original data elements", Type: Numeric, Length: 42, Prefix: Fixed},
+	}}
+}
+
+// LoadSpec reads a Spec from a JSON file, e.g. {"fields": {"2": {"type": "n", "length": 19, "prefix": "LLVAR"}}}
+func LoadSpec(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read field spec: %w", err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse field spec %s: %w", path, err)
+	}
+	for n, f := range spec.Fields {
+		if n < 2 || n > 128 {
+			return nil, fmt.Errorf("field spec %s: field %d out of range", path, n)
+		}
+		if err := f.validate(); err != nil {
+			return nil, fmt.Errorf("field spec %s: field %d: %w", path, n, err)
+		}
+	}
+	return &spec, nil
+}
+
+// Message is a single ISO 8583 message. Fields holds the values of the fields present, unpadded.
+type Message struct {
+	MTI    string
+	Fields map[int]string
+}
+
+// NewMessage returns an empty message of the given type
+func NewMessage(mti string) *Message {
+	return &Message{MTI: mti, Fields: map[int]string{}}
+}
+
+// Get returns the value of a field, or "" if it is not present
+func (m *Message) Get(field int) string {
+	return m.Fields[field]
+}
+
+// Set sets the value of a field
+func (m *Message) Set(field int, value string) {
+	if m.Fields == nil {
+		m.Fields = map[int]string{}
+	}
+	m.Fields[field] = value
+}
+
+// Has reports whether a field is present
+func (m *Message) Has(field int) bool {
+	_, ok := m.Fields[field]
+	return ok
+}
+
+// Pack encodes a message according to the spec
+func (s *Spec) Pack(m *Message) ([]byte, error) {
+	if len(m.MTI) != 4 || !isDigits(m.MTI) {
+		return nil, fmt.Errorf("invalid MTI %q", m.MTI)
+	}
+
+	numbers := make([]int, 0, len(m.Fields))
+	for n := range m.Fields {
+		if n < 2 || n > 128 {
+			return nil, fmt.Errorf("field %d out of range", n)
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	bitmap := make([]byte, 8)
+	if len(numbers) > 0 && numbers[len(numbers)-1] > 64 {
+		bitmap = make([]byte, 16)
+		setBit(bitmap, 1)
+	}
+
+	buf := append([]byte(m.MTI), bitmap...)
+	for _, n := range numbers {
+		f, ok := s.Fields[n]
+		if !ok {
+			return nil, fmt.Errorf("field %d is not in the spec", n)
+		}
+		encoded, err := f.encode(m.Fields[n])
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", n, err)
+		}
+		setBit(buf[4:4+len(bitmap)], n)
+		buf = append(buf, encoded...)
+	}
+	return buf, nil
+}
+
+// Unpack decodes a message according to the spec
+func (s *Spec) Unpack(b []byte) (*Message, error) {
+	if len(b) < 12 {
+		return nil, errors.New("message too short")
+	}
+	m := NewMessage(string(b[:4]))
+	if !isDigits(m.MTI) {
+		return nil, fmt.Errorf("invalid MTI %q", m.MTI)
+	}
+
+	bitmap := b[4:12]
+	if bitSet(bitmap, 1) {
+		if len(b) < 20 {
+			return nil, errors.New("message too short for its secondary bitmap")
+		}
+		bitmap = b[4:20]
+	}
+
+	pos := 4 + len(bitmap)
+	for n := 2; n <= len(bitmap)*8; n++ {
+		if !bitSet(bitmap, n) {
+			continue
+		}
+		f, ok := s.Fields[n]
+		if !ok {
+			return nil, fmt.Errorf("field %d is not in the spec", n)
+		}
+		value, size, err := f.decode(b[pos:])
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", n, err)
+		}
+		m.Fields[n] = value
+		pos += size
+	}
+	if pos != len(b) {
+		return nil, fmt.Errorf("%d unexpected bytes after the last field", len(b)-pos)
+	}
+	return m, nil
+}
+
+// ReadFrame reads one length-prefixed message from r
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint16(header[:]))
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+	return b, nil
+}
+
+// WriteFrame writes a message to w, preceded by its length
+func WriteFrame(w io.Writer, b []byte) error {
+	if len(b) > maxFrameLength {
+		return fmt.Errorf("message of %d bytes is too long", len(b))
+	}
+	frame := make([]byte, 2, 2+len(b))
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	_, err := w.Write(append(frame, b...))
+	return err
+}
+
+// currencyCodes maps ISO 4217 numeric currency codes, as used in field 49, to alphabetic ones
+var currencyCodes = map[string]string{
+	"036": "AUD",
+	"124": "CAD",
+	"392": "JPY",
+	"578": "NOK",
+	"752": "SEK",
+	"756": "CHF",
+	"826": "GBP",
+	"840": "USD",
+	"978": "EUR",
+}
+
+// CurrencyAlpha returns the alphabetic code of an ISO 4217 numeric currency code
+func CurrencyAlpha(numeric string) (string, bool) {
+	alpha, ok := currencyCodes[numeric]
+	return alpha, ok
+}
+
+// CurrencyNumeric returns the ISO 4217 numeric code of an alphabetic currency code
+func CurrencyNumeric(alpha string) (string, bool) {
+	for numeric, a := range currencyCodes {
+		if a == alpha {
+			return numeric, true
+		}
+	}
+	return "", false
+}
+
+func (f Field) validate() error {
+	switch f.Prefix {
+	case Fixed, LLVar, LLLVar:
+	default:
+		return fmt.Errorf("unknown prefix %q", f.Prefix)
+	}
+	switch f.Type {
+	case Numeric, Alphanumeric, AlphanumericSpec:
+	default:
+		return fmt.Errorf("unknown type %q", f.Type)
+	}
+	if f.Length <= 0 || (f.Prefix == LLVar && f.Length > 99) || (f.Prefix == LLLVar && f.Length > 999) {
+		return fmt.Errorf("invalid length %d for a %s field", f.Length, f.Prefix)
+	}
+	return nil
+}
+
+// encode returns the wire form of a value, padded or length-prefixed
+func (f Field) encode(value string) (string, error) {
+	if len(value) > f.Length {
+		return "", fmt.Errorf("value of %d characters is longer than %d", len(value), f.Length)
+	}
+	if f.Type == Numeric && !isDigits(value) {
+		return "", fmt.Errorf("value %q is not numeric", value)
+	}
+
+	switch f.Prefix {
+	case LLVar:
+		return fmt.Sprintf("%02d%s", len(value), value), nil
+	case LLLVar:
+		return fmt.Sprintf("%03d%s", len(value), value), nil
+	default:
+		if f.Type == Numeric {
+			return strings.Repeat("0", f.Length-len(value)) + value, nil
+		}
+		return value + strings.Repeat(" ", f.Length-len(value)), nil
+	}
+}
+
+// decode reads a value from the start of b, returning it and the number of bytes it took up.
+// Fixed length alphanumeric values are returned with their padding trimmed.
+func (f Field) decode(b []byte) (string, int, error) {
+	length, prefix := f.Length, 0
+	switch f.Prefix {
+	case LLVar:
+		prefix = 2
+	case LLLVar:
+		prefix = 3
+	}
+	if prefix > 0 {
+		if len(b) < prefix || !isDigits(string(b[:prefix])) {
+			return "", 0, errors.New("invalid length prefix")
+		}
+		length, _ = strconv.Atoi(string(b[:prefix]))
+		if length > f.Length {
+			return "", 0, fmt.Errorf("length %d is longer than %d", length, f.Length)
+		}
+	}
+	if len(b) < prefix+length {
+		return "", 0, errors.New("message ends within the field")
+	}
+
+	value := string(b[prefix : prefix+length])
+	if f.Type == Numeric && !isDigits(value) {
+		return "", 0, fmt.Errorf("value %q is not numeric", value)
+	}
+	if prefix == 0 && f.Type != Numeric {
+		value = strings.TrimRight(value, " ")
+	}
+	return value, prefix + length, nil
+}
+
+// setBit sets bit n, counting from 1, of a bitmap
+func setBit(bitmap []byte, n int) {
+	bitmap[(n-1)/8] |= 0x80 >> uint((n-1)%8)
+}
+
+// bitSet reports whether bit n, counting from 1, of a bitmap is set
+func bitSet(bitmap []byte, n int) bool {
+	return bitmap[(n-1)/8]&(0x80>>uint((n-1)%8)) != 0
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package iso8583
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackUnpack(t *testing.T) {
+	spec := DefaultSpec()
+
+	m := NewMessage("0100")
+	m.Set(FieldPAN, "4000001234567899")
+	m.Set(FieldProcessingCode, "000000")
+	m.Set(FieldAmount, "1234")
+	m.Set(FieldSTAN, "42")
+	m.Set(FieldMerchantName, "Test Shop")
+	m.Set(FieldCurrency, "826")
+
+	b, err := spec.Pack(m)
+	assert.NoError(t, err)
+
+	// Primary bitmap only, with fields 2, 3, 4, 11, 43 and 49 set
+	assert.Equal(t, "0100", string(b[:4]))
+	assert.Equal(t, []byte{0x70, 0x20, 0, 0, 0, 0x20, 0x80, 0}, b[4:12])
+	assert.Equal(t, "164000001234567899"+"000000"+"000000001234"+"000042"+"Test Shop                               "+"826", string(b[12:]))
+
+	unpacked, err := spec.Unpack(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "0100", unpacked.MTI)
+	assert.Equal(t, "4000001234567899", unpacked.Get(FieldPAN))
+	assert.Equal(t, "000000001234", unpacked.Get(FieldAmount))
+	assert.Equal(t, "Test Shop", unpacked.Get(FieldMerchantName))
+	assert.Equal(t, "826", unpacked.Get(FieldCurrency))
+	assert.False(t, unpacked.Has(FieldAuthCode))
+}
+
+func TestPackUnpack_SecondaryBitmap(t *testing.T) {
+	spec := DefaultSpec()
+
+	m := NewMessage("0400")
+	m.Set(FieldAmount, "500")
+	m.Set(FieldOriginalData, "0100000042")
+
+	b, err := spec.Pack(m)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x80|0x10), b[4], "field 1 flags the secondary bitmap")
+	assert.Len(t, b, 4+16+12+42)
+
+	unpacked, err := spec.Unpack(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "000000000000000000000000000000000100000042", unpacked.Get(FieldOriginalData))
+}
+
+func TestPack_Errors(t *testing.T) {
+	spec := DefaultSpec()
+
+	tests := []struct {
+		name  string
+		field int
+		value string
+	}{
+		{name: "not numeric", field: FieldAmount, value: "12.34"},
+		{name: "too long", field: FieldAuthCode, value: "ABCDEFG"},
+		{name: "not in spec", field: 60, value: "x"},
+	}
+	for _, tt := range tests {
+		m := NewMessage("0100")
+		m.Set(tt.field, tt.value)
+		_, err := spec.Pack(m)
+		assert.Error(t, err, tt.name)
+	}
+
+	_, err := spec.Pack(NewMessage("01"))
+	assert.Error(t, err, "short MTI")
+}
+
+func TestUnpack_Errors(t *testing.T) {
+	spec := DefaultSpec()
+
+	m := NewMessage("0100")
+	m.Set(FieldPAN, "4000001234567899")
+	m.Set(FieldAmount, "1234")
+	b, err := spec.Pack(m)
+	assert.NoError(t, err)
+
+	_, err = spec.Unpack(b[:len(b)-1])
+	assert.Error(t, err, "truncated")
+	_, err = spec.Unpack(append(b, '0'))
+	assert.Error(t, err, "trailing bytes")
+	_, err = spec.Unpack([]byte("0100"))
+	assert.Error(t, err, "no bitmap")
+}
+
+func TestLoadSpec(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "spec.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"fields": {
+		"2": {"name": "PAN", "type": "n", "length": 19, "prefix": "LLVAR"},
+		"4": {"name": "Amount", "type": "n", "length": 12, "prefix": "FIXED"}
+	}}`), 0o600))
+	spec, err := LoadSpec(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Field{Name: "PAN", Type: Numeric, Length: 19, Prefix: LLVar}, spec.Fields[2])
+
+	// A PAN must be numeric under this spec
+	m := NewMessage("0100")
+	m.Set(FieldPAN, "card-123")
+	_, err = spec.Pack(m)
+	assert.Error(t, err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"fields": {"2": {"type": "n", "length": 120, "prefix": "LLVAR"}}}`), 0o600))
+	_, err = LoadSpec(invalid)
+	assert.Error(t, err)
+}
+
+func TestClient(t *testing.T) {
+	spec := DefaultSpec()
+	clientConn, serverConn := net.Pipe()
+	defer serverConn.Close()
+
+	// Approve whatever is asked, echoing the trace number
+	go func() {
+		b, err := ReadFrame(serverConn)
+		if err != nil {
+			return
+		}
+		req, err := spec.Unpack(b)
+		if err != nil {
+			return
+		}
+		resp := NewMessage("0110")
+		resp.Set(FieldSTAN, req.Get(FieldSTAN))
+		resp.Set(FieldAuthCode, "K7Q2ZD")
+		resp.Set(FieldResponseCode, ResponseApproved)
+		b, _ = spec.Pack(resp)
+		WriteFrame(serverConn, b)
+	}()
+
+	client := NewClient(clientConn, spec)
+	defer client.Close()
+
+	resp, err := client.Authorize("card-123", 1000, "GBP", "M1", "Test Shop")
+	assert.NoError(t, err)
+	assert.Equal(t, "0110", resp.MTI)
+	assert.Equal(t, "000001", resp.Get(FieldSTAN))
+	assert.Equal(t, ResponseApproved, resp.Get(FieldResponseCode))
+	assert.Equal(t, "K7Q2ZD", resp.Get(FieldAuthCode))
+}
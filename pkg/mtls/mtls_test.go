@@ -0,0 +1,45 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// peerContext returns the context of a call from a peer with the auth info given
+func peerContext(authInfo credentials.AuthInfo) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: authInfo})
+}
+
+func TestPeerIdentity(t *testing.T) {
+	verified := func(commonName string) credentials.TLSInfo {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+		ok   bool
+	}{
+		{"verified certificate", peerContext(verified("card-production")), "card-production", true},
+		{"no peer", context.Background(), "", false},
+		{"no transport security", peerContext(nil), "", false},
+		{"no verified certificate", peerContext(credentials.TLSInfo{}), "", false},
+		{"no common name", peerContext(verified("")), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PeerIdentity(tt.ctx)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
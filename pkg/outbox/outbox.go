@@ -0,0 +1,218 @@
+// Package outbox implements the transactional outbox pattern for Redis stream events.
+//
+// Producers call Enqueue inside the SQL transaction that makes the business change, so an
+// event is stored if and only if the change commits. A Relay then publishes stored events
+// to their Redis streams and marks them sent. Delivery is at least once: an event may be
+// published again if the relay stops between XAdd and marking it sent, so consumers must
+// tolerate duplicates. Events of the same aggregate are published in the order they were enqueued.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Relay defaults
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+	defaultRetention    = 24 * time.Hour
+)
+
+// Enqueue stores an event for stream within tx. aggregateID identifies the entity the event
+// is about (an account, a transaction, a card); events with the same aggregateID are relayed in order.
+// headers are published as extra stream fields next to the payload and may be nil.
+func Enqueue(ctx context.Context, tx *sql.Tx, stream, aggregateID, payload string, headers map[string]string) error {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event headers: %w", stream, err)
+	}
+
+	query := `INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`
+	if _, err := tx.ExecContext(ctx, query, stream, aggregateID, payload, encodedHeaders); err != nil {
+		return fmt.Errorf("failed to enqueue %s event: %w", stream, err)
+	}
+	return nil
+}
+
+// Relay publishes enqueued events to Redis
+type Relay struct {
+	db           *sql.DB
+	redisClient  *redis.Client
+	batchSize    int
+	pollInterval time.Duration
+	retention    time.Duration
+}
+
+// Option configures optional Relay settings
+type Option func(*Relay)
+
+// WithBatchSize sets the maximum number of events published per poll
+func WithBatchSize(n int) Option {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithPollInterval sets how often the relay checks for unsent events
+func WithPollInterval(d time.Duration) Option {
+	return func(r *Relay) {
+		if d > 0 {
+			r.pollInterval = d
+		}
+	}
+}
+
+// WithRetention sets how long sent events are kept before they are purged
+func WithRetention(d time.Duration) Option {
+	return func(r *Relay) {
+		if d > 0 {
+			r.retention = d
+		}
+	}
+}
+
+// NewRelay creates a relay for the outbox table in db
+func NewRelay(db *sql.DB, redisClient *redis.Client, opts ...Option) *Relay {
+	r := &Relay{
+		db:           db,
+		redisClient:  redisClient,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		retention:    defaultRetention,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run publishes events until ctx is cancelled. Sent events older than the retention period are purged hourly.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	purgeTicker := time.NewTicker(time.Hour)
+	defer purgeTicker.Stop()
+
+	for {
+		// Drain the backlog before waiting for the next tick
+		for {
+			sent, err := r.PublishPending(ctx)
+			if err != nil {
+				log.Printf("failed to relay outbox events: %v", err)
+				break
+			}
+			if sent < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-purgeTicker.C:
+			if err := r.Purge(ctx); err != nil {
+				log.Printf("failed to purge outbox: %v", err)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes one batch of unsent events and returns how many were sent.
+//
+// Rows are locked for the duration of the batch, so relays in several replicas of a service
+// take turns rather than publishing the same events concurrently. If an event cannot be
+// published, later events of the same aggregate are held back until the next batch to keep them in order.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	query := fmt.Sprintf(`SELECT id, stream, aggregate_id, payload, headers FROM outbox WHERE sent_at IS NULL ORDER BY id LIMIT %d FOR UPDATE`, r.batchSize)
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select outbox events: %w", err)
+	}
+	type event struct {
+		id          int64
+		stream      string
+		aggregateID string
+		payload     string
+		headers     []byte
+	}
+	var events []event
+	for rows.Next() {
+		var e event
+		if err := rows.Scan(&e.id, &e.stream, &e.aggregateID, &e.payload, &e.headers); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows error during outbox select: %w", err)
+	}
+
+	sent := 0
+	blocked := map[string]bool{}
+	updateQuery := `UPDATE outbox SET sent_at = NOW() WHERE id = $1`
+	for _, e := range events {
+		if blocked[e.aggregateID] {
+			continue
+		}
+		values := map[string]interface{}{}
+		if len(e.headers) > 0 {
+			var headers map[string]string
+			if err := json.Unmarshal(e.headers, &headers); err != nil {
+				return 0, fmt.Errorf("failed to decode headers of outbox event %d: %w", e.id, err)
+			}
+			for k, v := range headers {
+				values[k] = v
+			}
+		}
+		values["payload"] = e.payload
+
+		if _, err := r.redisClient.XAdd(ctx, &redis.XAddArgs{
+			Stream: e.stream,
+			Values: values,
+		}).Result(); err != nil {
+			log.Printf("failed to publish outbox event %d to %s: %v", e.id, e.stream, err)
+			blocked[e.aggregateID] = true
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, updateQuery, e.id); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox event %d sent: %w", e.id, err)
+		}
+		sent++
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+	return sent, nil
+}
+
+// Purge deletes sent events older than the retention period
+func (r *Relay) Purge(ctx context.Context) error {
+	query := `DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < $1`
+	if _, err := r.db.ExecContext(ctx, query, time.Now().Add(-r.retention)); err != nil {
+		return fmt.Errorf("failed to delete sent outbox events: %w", err)
+	}
+	return nil
+}
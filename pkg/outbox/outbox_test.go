@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+const selectQuery = `SELECT id, stream, aggregate_id, payload, headers FROM outbox WHERE sent_at IS NULL ORDER BY id LIMIT 100 FOR UPDATE`
+
+// matchXAdd matches XAdd commands on stream and fields, ignoring field order
+func matchXAdd(expected, actual []interface{}) error {
+	fields := func(args []interface{}) map[interface{}]interface{} {
+		m := map[interface{}]interface{}{}
+		for i := 3; i+1 < len(args); i += 2 {
+			m[args[i]] = args[i+1]
+		}
+		return m
+	}
+	if len(expected) != len(actual) || expected[1] != actual[1] || !reflect.DeepEqual(fields(expected), fields(actual)) {
+		return fmt.Errorf("expected %v, got %v", expected, actual)
+	}
+	return nil
+}
+
+func TestEnqueue(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (stream, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3, $4, NOW())`)).
+		WithArgs("balance:updated", "acc-123", "payload", []byte(`{"schema_version":"1"}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, Enqueue(ctx, tx, "balance:updated", "acc-123", "payload", map[string]string{"schema_version": "1"}))
+	assert.NoError(t, tx.Commit())
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestPublishPending(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	redisClient, mockRedis := redismock.NewClientMock()
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "stream", "aggregate_id", "payload", "headers"}).
+			AddRow(int64(1), "balance:updated", "acc-1", "first", []byte(`{}`)).
+			AddRow(int64(2), "balance:updated", "acc-2", "second", []byte(`{"schema_version":"1"}`)))
+
+	mockRedis.CustomMatch(matchXAdd).ExpectXAdd(&redis.XAddArgs{Stream: "balance:updated", Values: map[string]interface{}{"payload": "first"}}).SetVal("1-0")
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET sent_at = NOW() WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockRedis.CustomMatch(matchXAdd).ExpectXAdd(&redis.XAddArgs{Stream: "balance:updated", Values: map[string]interface{}{"payload": "second", "schema_version": "1"}}).SetVal("2-0")
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET sent_at = NOW() WHERE id = $1`)).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	relay := NewRelay(db, redisClient)
+	sent, err := relay.PublishPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestPublishPending_HoldsBackAggregateAfterFailure(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	redisClient, mockRedis := redismock.NewClientMock()
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "stream", "aggregate_id", "payload", "headers"}).
+			AddRow(int64(1), "card:created", "card-1", "created", []byte(`{}`)).
+			AddRow(int64(2), "card:created", "card-2", "other card", []byte(`{}`)).
+			AddRow(int64(3), "card:status_changed", "card-1", "frozen", []byte(`{}`)))
+
+	// card-1's first event fails, so its later event must wait; card-2 is unaffected
+	mockRedis.CustomMatch(matchXAdd).ExpectXAdd(&redis.XAddArgs{Stream: "card:created", Values: map[string]interface{}{"payload": "created"}}).SetErr(errors.New("redis down"))
+	mockRedis.CustomMatch(matchXAdd).ExpectXAdd(&redis.XAddArgs{Stream: "card:created", Values: map[string]interface{}{"payload": "other card"}}).SetVal("2-0")
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET sent_at = NOW() WHERE id = $1`)).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	relay := NewRelay(db, redisClient)
+	sent, err := relay.PublishPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	assert.NoError(t, mockDb.ExpectationsWereMet())
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
@@ -0,0 +1,82 @@
+// Package pan generates and checks primary account numbers (PANs), the numbers printed on cards.
+//
+// A PAN starts with the issuer's bank identification number (BIN), continues with the account
+// number and ends with a check digit computed with the Luhn algorithm, which catches mistyped
+// digits and most transpositions.
+package pan
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Bounds on the length of a PAN, in digits
+const (
+	MinLength = 12
+	MaxLength = 19
+)
+
+// Valid reports whether pan is all digits, of a valid length, with a correct check digit
+func Valid(pan string) bool {
+	if len(pan) < MinLength || len(pan) > MaxLength || !digits(pan) {
+		return false
+	}
+	return CheckDigit(pan[:len(pan)-1]) == pan[len(pan)-1]
+}
+
+// CheckDigit returns the Luhn check digit to append to payload, which must be all digits
+func CheckDigit(payload string) byte {
+	sum := 0
+	// Every second digit from the right of the payload is doubled, as it will be from the
+	// check digit's left once the check digit is appended
+	for i := 0; i < len(payload); i++ {
+		d := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// Generate returns a random valid PAN of length digits starting with bin
+func Generate(bin string, length int) (string, error) {
+	if !digits(bin) || bin == "" {
+		return "", fmt.Errorf("invalid BIN %q", bin)
+	}
+	if length < MinLength || length > MaxLength || len(bin) >= length {
+		return "", fmt.Errorf("invalid length %d for BIN %s", length, bin)
+	}
+
+	payload := []byte(bin)
+	for len(payload) < length-1 {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate account number: %w", err)
+		}
+		payload = append(payload, byte('0'+d.Int64()))
+	}
+	return string(payload) + string(CheckDigit(string(payload))), nil
+}
+
+// LastFour returns the last four digits of pan, which may be shown to the cardholder
+func LastFour(pan string) string {
+	if len(pan) < 4 {
+		return pan
+	}
+	return pan[len(pan)-4:]
+}
+
+// digits reports whether s is only ASCII digits
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
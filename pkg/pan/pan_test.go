@@ -0,0 +1,60 @@
+package pan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		pan   string
+		valid bool
+	}{
+		{"4111111111111111", true},
+		{"5555555555554444", true},
+		{"378282246310005", true},
+		{"4111111111111112", false}, // wrong check digit
+		{"4222222222222", true},
+		{"41111111111", false},          // too short
+		{"41111111111111111111", false}, // too long
+		{"4111 1111 1111 1111", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, Valid(tt.pan), tt.pan)
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	assert.Equal(t, byte('1'), CheckDigit("411111111111111"))
+	assert.Equal(t, byte('3'), CheckDigit("7992739871"))
+	assert.Equal(t, byte('0'), CheckDigit(""))
+}
+
+func TestGenerate(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		pan, err := Generate("45996500", 16)
+		assert.NoError(t, err)
+		assert.Len(t, pan, 16)
+		assert.True(t, strings.HasPrefix(pan, "45996500"), pan)
+		assert.True(t, Valid(pan), pan)
+		seen[pan] = true
+	}
+	// 10^7 account numbers make a repeat in 100 vanishingly unlikely
+	assert.Len(t, seen, 100)
+
+	_, err := Generate("4599650A", 16)
+	assert.Error(t, err)
+	_, err = Generate("45996500", 8)
+	assert.Error(t, err)
+	_, err = Generate("4599650012345678", 16)
+	assert.Error(t, err)
+}
+
+func TestLastFour(t *testing.T) {
+	assert.Equal(t, "1111", LastFour("4111111111111111"))
+	assert.Equal(t, "12", LastFour("12"))
+}
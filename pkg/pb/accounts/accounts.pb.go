@@ -0,0 +1,998 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/accounts.proto
+
+package accounts
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_proto_accounts_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"` // unique among users
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// A personal account has one holder, a joint account two and a business account any number
+type Account struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`               // also the account's ID in the Balance service
+	AccountType   string                 `protobuf:"bytes,2,opt,name=account_type,json=accountType,proto3" json:"account_type,omitempty"`         // "personal", "joint" or "business"
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                                          // business name of a business account, empty otherwise
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                                  // ISO 4217 code
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                                      // "OPEN" or "CLOSED"
+	HolderUserIds []string               `protobuf:"bytes,6,rep,name=holder_user_ids,json=holderUserIds,proto3" json:"holder_user_ids,omitempty"` // oldest holder first
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`               // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_proto_accounts_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Account) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Account) GetAccountType() string {
+	if x != nil {
+		return x.AccountType
+	}
+	return ""
+}
+
+func (x *Account) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Account) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Account) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Account) GetHolderUserIds() []string {
+	if x != nil {
+		return x.HolderUserIds
+	}
+	return nil
+}
+
+func (x *Account) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountType   string                 `protobuf:"bytes,1,opt,name=account_type,json=accountType,proto3" json:"account_type,omitempty"`         // "personal", "joint" or "business"
+	HolderUserIds []string               `protobuf:"bytes,2,rep,name=holder_user_ids,json=holderUserIds,proto3" json:"holder_user_ids,omitempty"` // one for a personal account, two for a joint account
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`                                  // ISO 4217 code, GBP if empty
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`                                          // required for a business account
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateAccountRequest) GetAccountType() string {
+	if x != nil {
+		return x.AccountType
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetHolderUserIds() []string {
+	if x != nil {
+		return x.HolderUserIds
+	}
+	return nil
+}
+
+func (x *CreateAccountRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountRequest) Reset() {
+	*x = GetAccountRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountRequest) ProtoMessage() {}
+
+func (x *GetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type ListAccountsByUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IncludeClosed bool                   `protobuf:"varint,2,opt,name=include_closed,json=includeClosed,proto3" json:"include_closed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccountsByUserRequest) Reset() {
+	*x = ListAccountsByUserRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccountsByUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccountsByUserRequest) ProtoMessage() {}
+
+func (x *ListAccountsByUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccountsByUserRequest.ProtoReflect.Descriptor instead.
+func (*ListAccountsByUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAccountsByUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListAccountsByUserRequest) GetIncludeClosed() bool {
+	if x != nil {
+		return x.IncludeClosed
+	}
+	return false
+}
+
+type AccountList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      []*Account             `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountList) Reset() {
+	*x = AccountList{}
+	mi := &file_proto_accounts_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountList) ProtoMessage() {}
+
+func (x *AccountList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountList.ProtoReflect.Descriptor instead.
+func (*AccountList) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AccountList) GetAccounts() []*Account {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+type AccountHolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountHolderRequest) Reset() {
+	*x = AccountHolderRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountHolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountHolderRequest) ProtoMessage() {}
+
+func (x *AccountHolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountHolderRequest.ProtoReflect.Descriptor instead.
+func (*AccountHolderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AccountHolderRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AccountHolderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// CardAccount is the account a card's payments are taken from
+type CardAccount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	AccountType   string                 `protobuf:"bytes,3,opt,name=account_type,json=accountType,proto3" json:"account_type,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217 code of the account
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardAccount) Reset() {
+	*x = CardAccount{}
+	mi := &file_proto_accounts_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardAccount) ProtoMessage() {}
+
+func (x *CardAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardAccount.ProtoReflect.Descriptor instead.
+func (*CardAccount) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CardAccount) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardAccount) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CardAccount) GetAccountType() string {
+	if x != nil {
+		return x.AccountType
+	}
+	return ""
+}
+
+func (x *CardAccount) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type LinkCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // the cardholder, who must hold the account
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkCardRequest) Reset() {
+	*x = LinkCardRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkCardRequest) ProtoMessage() {}
+
+func (x *LinkCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkCardRequest.ProtoReflect.Descriptor instead.
+func (*LinkCardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LinkCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *LinkCardRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LinkCardRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+// A replacement card pays from the account the card it replaces was linked to. Cards carries the
+// link over when it reissues a card, so it follows a card through any number of replacements.
+type CarryCardLinkRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CardId         string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // the replacement card
+	ReplacesCardId string                 `protobuf:"bytes,2,opt,name=replaces_card_id,json=replacesCardId,proto3" json:"replaces_card_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CarryCardLinkRequest) Reset() {
+	*x = CarryCardLinkRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarryCardLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarryCardLinkRequest) ProtoMessage() {}
+
+func (x *CarryCardLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarryCardLinkRequest.ProtoReflect.Descriptor instead.
+func (*CarryCardLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CarryCardLinkRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CarryCardLinkRequest) GetReplacesCardId() string {
+	if x != nil {
+		return x.ReplacesCardId
+	}
+	return ""
+}
+
+type CarriedCardLink struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // empty if the replaced card wasn't linked to an account
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarriedCardLink) Reset() {
+	*x = CarriedCardLink{}
+	mi := &file_proto_accounts_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarriedCardLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarriedCardLink) ProtoMessage() {}
+
+func (x *CarriedCardLink) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarriedCardLink.ProtoReflect.Descriptor instead.
+func (*CarriedCardLink) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CarriedCardLink) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CarriedCardLink) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+// A card's payments are taken from the account it was linked to, or that the card it replaces was
+// linked to. An unlinked card uses its holder's oldest open personal or joint account.
+type ResolveAccountForCardRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CardId         string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                           // the cardholder
+	ReplacesCardId string                 `protobuf:"bytes,3,opt,name=replaces_card_id,json=replacesCardId,proto3" json:"replaces_card_id,omitempty"` // card this one was issued to replace, if any
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResolveAccountForCardRequest) Reset() {
+	*x = ResolveAccountForCardRequest{}
+	mi := &file_proto_accounts_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAccountForCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAccountForCardRequest) ProtoMessage() {}
+
+func (x *ResolveAccountForCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_accounts_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAccountForCardRequest.ProtoReflect.Descriptor instead.
+func (*ResolveAccountForCardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_accounts_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResolveAccountForCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *ResolveAccountForCardRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ResolveAccountForCardRequest) GetReplacesCardId() string {
+	if x != nil {
+		return x.ReplacesCardId
+	}
+	return ""
+}
+
+var File_proto_accounts_proto protoreflect.FileDescriptor
+
+const file_proto_accounts_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/accounts.proto\"h\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"=\n" +
+	"\x11CreateUserRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\")\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xda\x01\n" +
+	"\aAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
+	"\faccount_type\x18\x02 \x01(\tR\vaccountType\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12&\n" +
+	"\x0fholder_user_ids\x18\x06 \x03(\tR\rholderUserIds\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\x91\x01\n" +
+	"\x14CreateAccountRequest\x12!\n" +
+	"\faccount_type\x18\x01 \x01(\tR\vaccountType\x12&\n" +
+	"\x0fholder_user_ids\x18\x02 \x03(\tR\rholderUserIds\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\"2\n" +
+	"\x11GetAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"[\n" +
+	"\x19ListAccountsByUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0einclude_closed\x18\x02 \x01(\bR\rincludeClosed\"3\n" +
+	"\vAccountList\x12$\n" +
+	"\baccounts\x18\x01 \x03(\v2\b.AccountR\baccounts\"N\n" +
+	"\x14AccountHolderRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x84\x01\n" +
+	"\vCardAccount\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12!\n" +
+	"\faccount_type\x18\x03 \x01(\tR\vaccountType\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"b\n" +
+	"\x0fLinkCardRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\"Y\n" +
+	"\x14CarryCardLinkRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12(\n" +
+	"\x10replaces_card_id\x18\x02 \x01(\tR\x0ereplacesCardId\"I\n" +
+	"\x0fCarriedCardLink\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\"z\n" +
+	"\x1cResolveAccountForCardRequest\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12(\n" +
+	"\x10replaces_card_id\x18\x03 \x01(\tR\x0ereplacesCardId2\x8d\x04\n" +
+	"\bAccounts\x12'\n" +
+	"\n" +
+	"CreateUser\x12\x12.CreateUserRequest\x1a\x05.User\x12!\n" +
+	"\aGetUser\x12\x0f.GetUserRequest\x1a\x05.User\x120\n" +
+	"\rCreateAccount\x12\x15.CreateAccountRequest\x1a\b.Account\x12*\n" +
+	"\n" +
+	"GetAccount\x12\x12.GetAccountRequest\x1a\b.Account\x12>\n" +
+	"\x12ListAccountsByUser\x12\x1a.ListAccountsByUserRequest\x1a\f.AccountList\x123\n" +
+	"\x10AddAccountHolder\x12\x15.AccountHolderRequest\x1a\b.Account\x126\n" +
+	"\x13RemoveAccountHolder\x12\x15.AccountHolderRequest\x1a\b.Account\x12*\n" +
+	"\bLinkCard\x12\x10.LinkCardRequest\x1a\f.CardAccount\x128\n" +
+	"\rCarryCardLink\x12\x15.CarryCardLinkRequest\x1a\x10.CarriedCardLink\x12D\n" +
+	"\x15ResolveAccountForCard\x12\x1d.ResolveAccountForCardRequest\x1a\f.CardAccountB\fZ\n" +
+	"./accountsb\x06proto3"
+
+var (
+	file_proto_accounts_proto_rawDescOnce sync.Once
+	file_proto_accounts_proto_rawDescData []byte
+)
+
+func file_proto_accounts_proto_rawDescGZIP() []byte {
+	file_proto_accounts_proto_rawDescOnce.Do(func() {
+		file_proto_accounts_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_accounts_proto_rawDesc), len(file_proto_accounts_proto_rawDesc)))
+	})
+	return file_proto_accounts_proto_rawDescData
+}
+
+var file_proto_accounts_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_accounts_proto_goTypes = []any{
+	(*User)(nil),                         // 0: User
+	(*CreateUserRequest)(nil),            // 1: CreateUserRequest
+	(*GetUserRequest)(nil),               // 2: GetUserRequest
+	(*Account)(nil),                      // 3: Account
+	(*CreateAccountRequest)(nil),         // 4: CreateAccountRequest
+	(*GetAccountRequest)(nil),            // 5: GetAccountRequest
+	(*ListAccountsByUserRequest)(nil),    // 6: ListAccountsByUserRequest
+	(*AccountList)(nil),                  // 7: AccountList
+	(*AccountHolderRequest)(nil),         // 8: AccountHolderRequest
+	(*CardAccount)(nil),                  // 9: CardAccount
+	(*LinkCardRequest)(nil),              // 10: LinkCardRequest
+	(*CarryCardLinkRequest)(nil),         // 11: CarryCardLinkRequest
+	(*CarriedCardLink)(nil),              // 12: CarriedCardLink
+	(*ResolveAccountForCardRequest)(nil), // 13: ResolveAccountForCardRequest
+}
+var file_proto_accounts_proto_depIdxs = []int32{
+	3,  // 0: AccountList.accounts:type_name -> Account
+	1,  // 1: Accounts.CreateUser:input_type -> CreateUserRequest
+	2,  // 2: Accounts.GetUser:input_type -> GetUserRequest
+	4,  // 3: Accounts.CreateAccount:input_type -> CreateAccountRequest
+	5,  // 4: Accounts.GetAccount:input_type -> GetAccountRequest
+	6,  // 5: Accounts.ListAccountsByUser:input_type -> ListAccountsByUserRequest
+	8,  // 6: Accounts.AddAccountHolder:input_type -> AccountHolderRequest
+	8,  // 7: Accounts.RemoveAccountHolder:input_type -> AccountHolderRequest
+	10, // 8: Accounts.LinkCard:input_type -> LinkCardRequest
+	11, // 9: Accounts.CarryCardLink:input_type -> CarryCardLinkRequest
+	13, // 10: Accounts.ResolveAccountForCard:input_type -> ResolveAccountForCardRequest
+	0,  // 11: Accounts.CreateUser:output_type -> User
+	0,  // 12: Accounts.GetUser:output_type -> User
+	3,  // 13: Accounts.CreateAccount:output_type -> Account
+	3,  // 14: Accounts.GetAccount:output_type -> Account
+	7,  // 15: Accounts.ListAccountsByUser:output_type -> AccountList
+	3,  // 16: Accounts.AddAccountHolder:output_type -> Account
+	3,  // 17: Accounts.RemoveAccountHolder:output_type -> Account
+	9,  // 18: Accounts.LinkCard:output_type -> CardAccount
+	12, // 19: Accounts.CarryCardLink:output_type -> CarriedCardLink
+	9,  // 20: Accounts.ResolveAccountForCard:output_type -> CardAccount
+	11, // [11:21] is the sub-list for method output_type
+	1,  // [1:11] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_accounts_proto_init() }
+func file_proto_accounts_proto_init() {
+	if File_proto_accounts_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_accounts_proto_rawDesc), len(file_proto_accounts_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_accounts_proto_goTypes,
+		DependencyIndexes: file_proto_accounts_proto_depIdxs,
+		MessageInfos:      file_proto_accounts_proto_msgTypes,
+	}.Build()
+	File_proto_accounts_proto = out.File
+	file_proto_accounts_proto_goTypes = nil
+	file_proto_accounts_proto_depIdxs = nil
+}
@@ -0,0 +1,471 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/accounts.proto
+
+package accounts
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Accounts_CreateUser_FullMethodName            = "/Accounts/CreateUser"
+	Accounts_GetUser_FullMethodName               = "/Accounts/GetUser"
+	Accounts_CreateAccount_FullMethodName         = "/Accounts/CreateAccount"
+	Accounts_GetAccount_FullMethodName            = "/Accounts/GetAccount"
+	Accounts_ListAccountsByUser_FullMethodName    = "/Accounts/ListAccountsByUser"
+	Accounts_AddAccountHolder_FullMethodName      = "/Accounts/AddAccountHolder"
+	Accounts_RemoveAccountHolder_FullMethodName   = "/Accounts/RemoveAccountHolder"
+	Accounts_LinkCard_FullMethodName              = "/Accounts/LinkCard"
+	Accounts_CarryCardLink_FullMethodName         = "/Accounts/CarryCardLink"
+	Accounts_ResolveAccountForCard_FullMethodName = "/Accounts/ResolveAccountForCard"
+)
+
+// AccountsClient is the client API for Accounts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Accounts holds users, their accounts and who holds which. A user can hold several accounts and a
+// joint or business account has several holders. The money in an account is held by the Balance
+// service under the account's ID.
+type AccountsClient interface {
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	ListAccountsByUser(ctx context.Context, in *ListAccountsByUserRequest, opts ...grpc.CallOption) (*AccountList, error)
+	AddAccountHolder(ctx context.Context, in *AccountHolderRequest, opts ...grpc.CallOption) (*Account, error)
+	RemoveAccountHolder(ctx context.Context, in *AccountHolderRequest, opts ...grpc.CallOption) (*Account, error)
+	LinkCard(ctx context.Context, in *LinkCardRequest, opts ...grpc.CallOption) (*CardAccount, error)
+	CarryCardLink(ctx context.Context, in *CarryCardLinkRequest, opts ...grpc.CallOption) (*CarriedCardLink, error)
+	ResolveAccountForCard(ctx context.Context, in *ResolveAccountForCardRequest, opts ...grpc.CallOption) (*CardAccount, error)
+}
+
+type accountsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountsClient(cc grpc.ClientConnInterface) AccountsClient {
+	return &accountsClient{cc}
+}
+
+func (c *accountsClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Accounts_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Accounts_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, Accounts_CreateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, Accounts_GetAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ListAccountsByUser(ctx context.Context, in *ListAccountsByUserRequest, opts ...grpc.CallOption) (*AccountList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountList)
+	err := c.cc.Invoke(ctx, Accounts_ListAccountsByUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) AddAccountHolder(ctx context.Context, in *AccountHolderRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, Accounts_AddAccountHolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) RemoveAccountHolder(ctx context.Context, in *AccountHolderRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, Accounts_RemoveAccountHolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) LinkCard(ctx context.Context, in *LinkCardRequest, opts ...grpc.CallOption) (*CardAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardAccount)
+	err := c.cc.Invoke(ctx, Accounts_LinkCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) CarryCardLink(ctx context.Context, in *CarryCardLinkRequest, opts ...grpc.CallOption) (*CarriedCardLink, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CarriedCardLink)
+	err := c.cc.Invoke(ctx, Accounts_CarryCardLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ResolveAccountForCard(ctx context.Context, in *ResolveAccountForCardRequest, opts ...grpc.CallOption) (*CardAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardAccount)
+	err := c.cc.Invoke(ctx, Accounts_ResolveAccountForCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountsServer is the server API for Accounts service.
+// All implementations must embed UnimplementedAccountsServer
+// for forward compatibility.
+//
+// Accounts holds users, their accounts and who holds which. A user can hold several accounts and a
+// joint or business account has several holders. The money in an account is held by the Balance
+// service under the account's ID.
+type AccountsServer interface {
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	CreateAccount(context.Context, *CreateAccountRequest) (*Account, error)
+	GetAccount(context.Context, *GetAccountRequest) (*Account, error)
+	ListAccountsByUser(context.Context, *ListAccountsByUserRequest) (*AccountList, error)
+	AddAccountHolder(context.Context, *AccountHolderRequest) (*Account, error)
+	RemoveAccountHolder(context.Context, *AccountHolderRequest) (*Account, error)
+	LinkCard(context.Context, *LinkCardRequest) (*CardAccount, error)
+	CarryCardLink(context.Context, *CarryCardLinkRequest) (*CarriedCardLink, error)
+	ResolveAccountForCard(context.Context, *ResolveAccountForCardRequest) (*CardAccount, error)
+	mustEmbedUnimplementedAccountsServer()
+}
+
+// UnimplementedAccountsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAccountsServer struct{}
+
+func (UnimplementedAccountsServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedAccountsServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedAccountsServer) CreateAccount(context.Context, *CreateAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedAccountsServer) GetAccount(context.Context, *GetAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedAccountsServer) ListAccountsByUser(context.Context, *ListAccountsByUserRequest) (*AccountList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccountsByUser not implemented")
+}
+func (UnimplementedAccountsServer) AddAccountHolder(context.Context, *AccountHolderRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAccountHolder not implemented")
+}
+func (UnimplementedAccountsServer) RemoveAccountHolder(context.Context, *AccountHolderRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAccountHolder not implemented")
+}
+func (UnimplementedAccountsServer) LinkCard(context.Context, *LinkCardRequest) (*CardAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkCard not implemented")
+}
+func (UnimplementedAccountsServer) CarryCardLink(context.Context, *CarryCardLinkRequest) (*CarriedCardLink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CarryCardLink not implemented")
+}
+func (UnimplementedAccountsServer) ResolveAccountForCard(context.Context, *ResolveAccountForCardRequest) (*CardAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveAccountForCard not implemented")
+}
+func (UnimplementedAccountsServer) mustEmbedUnimplementedAccountsServer() {}
+func (UnimplementedAccountsServer) testEmbeddedByValue()                  {}
+
+// UnsafeAccountsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountsServer will
+// result in compilation errors.
+type UnsafeAccountsServer interface {
+	mustEmbedUnimplementedAccountsServer()
+}
+
+func RegisterAccountsServer(s grpc.ServiceRegistrar, srv AccountsServer) {
+	// If the following call pancis, it indicates UnimplementedAccountsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Accounts_ServiceDesc, srv)
+}
+
+func _Accounts_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ListAccountsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsByUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ListAccountsByUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_ListAccountsByUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ListAccountsByUser(ctx, req.(*ListAccountsByUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_AddAccountHolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountHolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).AddAccountHolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_AddAccountHolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).AddAccountHolder(ctx, req.(*AccountHolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_RemoveAccountHolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountHolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).RemoveAccountHolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_RemoveAccountHolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).RemoveAccountHolder(ctx, req.(*AccountHolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_LinkCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).LinkCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_LinkCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).LinkCard(ctx, req.(*LinkCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_CarryCardLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CarryCardLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).CarryCardLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_CarryCardLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).CarryCardLink(ctx, req.(*CarryCardLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ResolveAccountForCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAccountForCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ResolveAccountForCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Accounts_ResolveAccountForCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ResolveAccountForCard(ctx, req.(*ResolveAccountForCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Accounts_ServiceDesc is the grpc.ServiceDesc for Accounts service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Accounts_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Accounts",
+	HandlerType: (*AccountsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _Accounts_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _Accounts_GetUser_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _Accounts_CreateAccount_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _Accounts_GetAccount_Handler,
+		},
+		{
+			MethodName: "ListAccountsByUser",
+			Handler:    _Accounts_ListAccountsByUser_Handler,
+		},
+		{
+			MethodName: "AddAccountHolder",
+			Handler:    _Accounts_AddAccountHolder_Handler,
+		},
+		{
+			MethodName: "RemoveAccountHolder",
+			Handler:    _Accounts_RemoveAccountHolder_Handler,
+		},
+		{
+			MethodName: "LinkCard",
+			Handler:    _Accounts_LinkCard_Handler,
+		},
+		{
+			MethodName: "CarryCardLink",
+			Handler:    _Accounts_CarryCardLink_Handler,
+		},
+		{
+			MethodName: "ResolveAccountForCard",
+			Handler:    _Accounts_ResolveAccountForCard_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/accounts.proto",
+}
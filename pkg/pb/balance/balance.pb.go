@@ -21,6 +21,62 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Why Balance declined a debit
+type DebitDeclineReason int32
+
+const (
+	DebitDeclineReason_DEBIT_DECLINE_REASON_UNSPECIFIED            DebitDeclineReason = 0
+	DebitDeclineReason_DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND      DebitDeclineReason = 1
+	DebitDeclineReason_DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS     DebitDeclineReason = 2 // an account without an arranged overdraft
+	DebitDeclineReason_DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT   DebitDeclineReason = 3
+	DebitDeclineReason_DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED DebitDeclineReason = 4 // no exchange rate into the account's currency
+)
+
+// Enum value maps for DebitDeclineReason.
+var (
+	DebitDeclineReason_name = map[int32]string{
+		0: "DEBIT_DECLINE_REASON_UNSPECIFIED",
+		1: "DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND",
+		2: "DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS",
+		3: "DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT",
+		4: "DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED",
+	}
+	DebitDeclineReason_value = map[string]int32{
+		"DEBIT_DECLINE_REASON_UNSPECIFIED":            0,
+		"DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND":      1,
+		"DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS":     2,
+		"DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT":   3,
+		"DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED": 4,
+	}
+)
+
+func (x DebitDeclineReason) Enum() *DebitDeclineReason {
+	p := new(DebitDeclineReason)
+	*p = x
+	return p
+}
+
+func (x DebitDeclineReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DebitDeclineReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_balance_proto_enumTypes[0].Descriptor()
+}
+
+func (DebitDeclineReason) Type() protoreflect.EnumType {
+	return &file_proto_balance_proto_enumTypes[0]
+}
+
+func (x DebitDeclineReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DebitDeclineReason.Descriptor instead.
+func (DebitDeclineReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{0}
+}
+
 type AccountID struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
@@ -65,17 +121,75 @@ func (x *AccountID) GetAccountId() string {
 	return ""
 }
 
+// OpenAccountRequest opens an account with an empty balance. Opening an account that is already
+// open in the same currency changes nothing.
+type OpenAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217 code, GBP if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenAccountRequest) Reset() {
+	*x = OpenAccountRequest{}
+	mi := &file_proto_balance_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenAccountRequest) ProtoMessage() {}
+
+func (x *OpenAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenAccountRequest.ProtoReflect.Descriptor instead.
+func (*OpenAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OpenAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OpenAccountRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type BalanceResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	CurrentBalance int64                  `protobuf:"varint,2,opt,name=current_balance,json=currentBalance,proto3" json:"current_balance,omitempty"` // amount in cents
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AccountId        string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	CurrentBalance   int64                  `protobuf:"varint,2,opt,name=current_balance,json=currentBalance,proto3" json:"current_balance,omitempty"`       // ledger balance in minor units of currency
+	AvailableBalance int64                  `protobuf:"varint,3,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"` // ledger balance minus active holds plus overdraft_limit, in minor units of currency
+	Currency         string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                                          // ISO 4217 code of the balances above; from GetBalance, the account's currency
+	Balances         []*CurrencyBalance     `protobuf:"bytes,5,rep,name=balances,proto3" json:"balances,omitempty"`                                          // every currency the account holds, from GetBalance only
+	OverdraftLimit   int64                  `protobuf:"varint,6,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`       // arranged overdraft on the balance in currency; only the account's currency has one
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *BalanceResponse) Reset() {
 	*x = BalanceResponse{}
-	mi := &file_proto_balance_proto_msgTypes[1]
+	mi := &file_proto_balance_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +201,7 @@ func (x *BalanceResponse) String() string {
 func (*BalanceResponse) ProtoMessage() {}
 
 func (x *BalanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balance_proto_msgTypes[1]
+	mi := &file_proto_balance_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +214,7 @@ func (x *BalanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalanceResponse.ProtoReflect.Descriptor instead.
 func (*BalanceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_balance_proto_rawDescGZIP(), []int{1}
+	return file_proto_balance_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *BalanceResponse) GetAccountId() string {
@@ -117,17 +231,115 @@ func (x *BalanceResponse) GetCurrentBalance() int64 {
 	return 0
 }
 
+func (x *BalanceResponse) GetAvailableBalance() int64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *BalanceResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *BalanceResponse) GetBalances() []*CurrencyBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *BalanceResponse) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+type CurrencyBalance struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Currency         string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`                                          // ISO 4217 code
+	CurrentBalance   int64                  `protobuf:"varint,2,opt,name=current_balance,json=currentBalance,proto3" json:"current_balance,omitempty"`       // in minor units of currency
+	AvailableBalance int64                  `protobuf:"varint,3,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"` // in minor units of currency, including any overdraft_limit
+	OverdraftLimit   int64                  `protobuf:"varint,4,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`       // in minor units of currency
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CurrencyBalance) Reset() {
+	*x = CurrencyBalance{}
+	mi := &file_proto_balance_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyBalance) ProtoMessage() {}
+
+func (x *CurrencyBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyBalance.ProtoReflect.Descriptor instead.
+func (*CurrencyBalance) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CurrencyBalance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CurrencyBalance) GetCurrentBalance() int64 {
+	if x != nil {
+		return x.CurrentBalance
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetAvailableBalance() int64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
 type AuthorizeDebitRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // amount in cents
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                      // amount in minor units of currency
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional, a retry with the same key returns the original result
+	Currency       string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                                   // ISO 4217 code; empty for the account's currency. Converted into the account's currency if the account holds no balance in it
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *AuthorizeDebitRequest) Reset() {
 	*x = AuthorizeDebitRequest{}
-	mi := &file_proto_balance_proto_msgTypes[2]
+	mi := &file_proto_balance_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -139,7 +351,7 @@ func (x *AuthorizeDebitRequest) String() string {
 func (*AuthorizeDebitRequest) ProtoMessage() {}
 
 func (x *AuthorizeDebitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balance_proto_msgTypes[2]
+	mi := &file_proto_balance_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -152,7 +364,7 @@ func (x *AuthorizeDebitRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthorizeDebitRequest.ProtoReflect.Descriptor instead.
 func (*AuthorizeDebitRequest) Descriptor() ([]byte, []int) {
-	return file_proto_balance_proto_rawDescGZIP(), []int{2}
+	return file_proto_balance_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *AuthorizeDebitRequest) GetAccountId() string {
@@ -169,18 +381,38 @@ func (x *AuthorizeDebitRequest) GetAmount() int64 {
 	return 0
 }
 
+func (x *AuthorizeDebitRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *AuthorizeDebitRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type DebitResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // reason if not successful
-	NewBalance    int64                  `protobuf:"varint,3,opt,name=new_balance,json=newBalance,proto3" json:"new_balance,omitempty"`      // new balance if successful
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage    string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`                             // reason if not successful, for people; callers act on decline_reason
+	NewBalance      int64                  `protobuf:"varint,3,opt,name=new_balance,json=newBalance,proto3" json:"new_balance,omitempty"`                                  // new available balance if successful
+	HoldId          string                 `protobuf:"bytes,4,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`                                               // hold placed for the amount, to be captured or released
+	Currency        string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`                                                         // currency of new_balance and of the hold
+	FxRate          string                 `protobuf:"bytes,6,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"`                                               // exchange rate applied when the debit was converted, units of currency per unit of the requested currency; empty otherwise
+	ConvertedAmount int64                  `protobuf:"varint,7,opt,name=converted_amount,json=convertedAmount,proto3" json:"converted_amount,omitempty"`                   // requested amount converted into currency, excluding fx_fee
+	FxFee           int64                  `protobuf:"varint,8,opt,name=fx_fee,json=fxFee,proto3" json:"fx_fee,omitempty"`                                                 // conversion fee in minor units of currency, included in the hold
+	DeclineReason   DebitDeclineReason     `protobuf:"varint,9,opt,name=decline_reason,json=declineReason,proto3,enum=DebitDeclineReason" json:"decline_reason,omitempty"` // set if not successful
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *DebitResult) Reset() {
 	*x = DebitResult{}
-	mi := &file_proto_balance_proto_msgTypes[3]
+	mi := &file_proto_balance_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -192,7 +424,7 @@ func (x *DebitResult) String() string {
 func (*DebitResult) ProtoMessage() {}
 
 func (x *DebitResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balance_proto_msgTypes[3]
+	mi := &file_proto_balance_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -205,7 +437,7 @@ func (x *DebitResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DebitResult.ProtoReflect.Descriptor instead.
 func (*DebitResult) Descriptor() ([]byte, []int) {
-	return file_proto_balance_proto_rawDescGZIP(), []int{3}
+	return file_proto_balance_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *DebitResult) GetSuccess() bool {
@@ -229,17 +461,61 @@ func (x *DebitResult) GetNewBalance() int64 {
 	return 0
 }
 
+func (x *DebitResult) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *DebitResult) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *DebitResult) GetFxRate() string {
+	if x != nil {
+		return x.FxRate
+	}
+	return ""
+}
+
+func (x *DebitResult) GetConvertedAmount() int64 {
+	if x != nil {
+		return x.ConvertedAmount
+	}
+	return 0
+}
+
+func (x *DebitResult) GetFxFee() int64 {
+	if x != nil {
+		return x.FxFee
+	}
+	return 0
+}
+
+func (x *DebitResult) GetDeclineReason() DebitDeclineReason {
+	if x != nil {
+		return x.DeclineReason
+	}
+	return DebitDeclineReason_DEBIT_DECLINE_REASON_UNSPECIFIED
+}
+
 type CreditRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // amount in cents
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                      // amount in minor units of currency
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional, a retry with the same key returns the original result
+	Currency       string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                                   // ISO 4217 code; empty for the account's currency. Converted into the account's currency if the account holds no balance in it
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreditRequest) Reset() {
 	*x = CreditRequest{}
-	mi := &file_proto_balance_proto_msgTypes[4]
+	mi := &file_proto_balance_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -251,7 +527,7 @@ func (x *CreditRequest) String() string {
 func (*CreditRequest) ProtoMessage() {}
 
 func (x *CreditRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balance_proto_msgTypes[4]
+	mi := &file_proto_balance_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -264,7 +540,7 @@ func (x *CreditRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreditRequest.ProtoReflect.Descriptor instead.
 func (*CreditRequest) Descriptor() ([]byte, []int) {
-	return file_proto_balance_proto_rawDescGZIP(), []int{4}
+	return file_proto_balance_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreditRequest) GetAccountId() string {
@@ -281,37 +557,947 @@ func (x *CreditRequest) GetAmount() int64 {
 	return 0
 }
 
-var File_proto_balance_proto protoreflect.FileDescriptor
+func (x *CreditRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
 
-const file_proto_balance_proto_rawDesc = "" +
-	"\n" +
-	"\x13proto/balance.proto\"*\n" +
-	"\tAccountID\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\"Y\n" +
-	"\x0fBalanceResponse\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\x12'\n" +
-	"\x0fcurrent_balance\x18\x02 \x01(\x03R\x0ecurrentBalance\"N\n" +
-	"\x15AuthorizeDebitRequest\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x03R\x06amount\"m\n" +
-	"\vDebitResult\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
-	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12\x1f\n" +
-	"\vnew_balance\x18\x03 \x01(\x03R\n" +
-	"newBalance\"F\n" +
-	"\rCreditRequest\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x03R\x06amount2\xa0\x01\n" +
-	"\aBalance\x12*\n" +
-	"\n" +
-	"GetBalance\x12\n" +
-	".AccountID\x1a\x10.BalanceResponse\x126\n" +
-	"\x0eAuthorizeDebit\x12\x16.AuthorizeDebitRequest\x1a\f.DebitResult\x121\n" +
-	"\rCreditAccount\x12\x0e.CreditRequest\x1a\x10.BalanceResponseB\vZ\t./balanceb\x06proto3"
+func (x *CreditRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type LedgerEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntryId       string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	JournalId     string                 `protobuf:"bytes,2,opt,name=journal_id,json=journalId,proto3" json:"journal_id,omitempty"`           // groups the balanced entries of a single posting
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`           // customer account ID or a system account such as "system:funding"
+	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`                                 // amount in minor units of currency, positive for credit, negative for debit
+	BalanceAfter  int64                  `protobuf:"varint,5,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"` // account balance in currency after this entry was posted
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 string
+	Currency      string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`                    // ISO 4217 code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LedgerEntry) Reset() {
+	*x = LedgerEntry{}
+	mi := &file_proto_balance_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerEntry) ProtoMessage() {}
+
+func (x *LedgerEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerEntry.ProtoReflect.Descriptor instead.
+func (*LedgerEntry) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LedgerEntry) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetJournalId() string {
+	if x != nil {
+		return x.JournalId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *LedgerEntry) GetBalanceAfter() int64 {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return 0
+}
+
+func (x *LedgerEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ListLedgerEntriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         uint32                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                      // pagination limit
+	BeforeId      string                 `protobuf:"bytes,3,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"` // pagination cursor (entry ID)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLedgerEntriesRequest) Reset() {
+	*x = ListLedgerEntriesRequest{}
+	mi := &file_proto_balance_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLedgerEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLedgerEntriesRequest) ProtoMessage() {}
+
+func (x *ListLedgerEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLedgerEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ListLedgerEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListLedgerEntriesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListLedgerEntriesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListLedgerEntriesRequest) GetBeforeId() string {
+	if x != nil {
+		return x.BeforeId
+	}
+	return ""
+}
+
+type LedgerEntries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*LedgerEntry         `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LedgerEntries) Reset() {
+	*x = LedgerEntries{}
+	mi := &file_proto_balance_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerEntries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerEntries) ProtoMessage() {}
+
+func (x *LedgerEntries) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerEntries.ProtoReflect.Descriptor instead.
+func (*LedgerEntries) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LedgerEntries) GetEntries() []*LedgerEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type HoldID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldId        string                 `protobuf:"bytes,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldID) Reset() {
+	*x = HoldID{}
+	mi := &file_proto_balance_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldID) ProtoMessage() {}
+
+func (x *HoldID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldID.ProtoReflect.Descriptor instead.
+func (*HoldID) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HoldID) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+type CaptureHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldId        string                 `protobuf:"bytes,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // settled amount in the currency the debit was authorized in; 0 captures the held amount
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptureHoldRequest) Reset() {
+	*x = CaptureHoldRequest{}
+	mi := &file_proto_balance_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureHoldRequest) ProtoMessage() {}
+
+func (x *CaptureHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureHoldRequest.ProtoReflect.Descriptor instead.
+func (*CaptureHoldRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CaptureHoldRequest) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *CaptureHoldRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type ReduceHoldRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	HoldId         string                 `protobuf:"bytes,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                      // amount to release in the currency the debit was authorized in; must be less than the authorized amount
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional, a retry with the same key returns the original result
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReduceHoldRequest) Reset() {
+	*x = ReduceHoldRequest{}
+	mi := &file_proto_balance_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReduceHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReduceHoldRequest) ProtoMessage() {}
+
+func (x *ReduceHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReduceHoldRequest.ProtoReflect.Descriptor instead.
+func (*ReduceHoldRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReduceHoldRequest) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *ReduceHoldRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ReduceHoldRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type ReduceHoldResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Balance         *BalanceResponse       `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	RemainingAmount int64                  `protobuf:"varint,2,opt,name=remaining_amount,json=remainingAmount,proto3" json:"remaining_amount,omitempty"` // amount still authorized, in the currency the debit was authorized in
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReduceHoldResult) Reset() {
+	*x = ReduceHoldResult{}
+	mi := &file_proto_balance_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReduceHoldResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReduceHoldResult) ProtoMessage() {}
+
+func (x *ReduceHoldResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReduceHoldResult.ProtoReflect.Descriptor instead.
+func (*ReduceHoldResult) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReduceHoldResult) GetBalance() *BalanceResponse {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *ReduceHoldResult) GetRemainingAmount() int64 {
+	if x != nil {
+		return x.RemainingAmount
+	}
+	return 0
+}
+
+type ExpireHoldsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         uint32                 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // maximum number of holds to expire, 0 for no limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireHoldsRequest) Reset() {
+	*x = ExpireHoldsRequest{}
+	mi := &file_proto_balance_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireHoldsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireHoldsRequest) ProtoMessage() {}
+
+func (x *ExpireHoldsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireHoldsRequest.ProtoReflect.Descriptor instead.
+func (*ExpireHoldsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExpireHoldsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ExpireHoldsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expired       uint32                 `protobuf:"varint,1,opt,name=expired,proto3" json:"expired,omitempty"` // number of holds expired
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireHoldsResponse) Reset() {
+	*x = ExpireHoldsResponse{}
+	mi := &file_proto_balance_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireHoldsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireHoldsResponse) ProtoMessage() {}
+
+func (x *ExpireHoldsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireHoldsResponse.ProtoReflect.Descriptor instead.
+func (*ExpireHoldsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExpireHoldsResponse) GetExpired() uint32 {
+	if x != nil {
+		return x.Expired
+	}
+	return 0
+}
+
+type SetOverdraftLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // arranged overdraft in minor units of the account's currency, 0 to remove it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitRequest) Reset() {
+	*x = SetOverdraftLimitRequest{}
+	mi := &file_proto_balance_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitRequest) ProtoMessage() {}
+
+func (x *SetOverdraftLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetOverdraftLimitRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SetOverdraftLimitRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AccrueOverdraftChargesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`    // day to charge for, as YYYY-MM-DD in UTC; empty for yesterday. Must be before today
+	Limit         uint32                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // maximum number of accounts to charge, 0 for no limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccrueOverdraftChargesRequest) Reset() {
+	*x = AccrueOverdraftChargesRequest{}
+	mi := &file_proto_balance_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccrueOverdraftChargesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccrueOverdraftChargesRequest) ProtoMessage() {}
+
+func (x *AccrueOverdraftChargesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccrueOverdraftChargesRequest.ProtoReflect.Descriptor instead.
+func (*AccrueOverdraftChargesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AccrueOverdraftChargesRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *AccrueOverdraftChargesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AccrueOverdraftChargesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Charged       uint32                 `protobuf:"varint,1,opt,name=charged,proto3" json:"charged,omitempty"` // number of accounts charged overdraft interest or fees
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccrueOverdraftChargesResponse) Reset() {
+	*x = AccrueOverdraftChargesResponse{}
+	mi := &file_proto_balance_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccrueOverdraftChargesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccrueOverdraftChargesResponse) ProtoMessage() {}
+
+func (x *AccrueOverdraftChargesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccrueOverdraftChargesResponse.ProtoReflect.Descriptor instead.
+func (*AccrueOverdraftChargesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AccrueOverdraftChargesResponse) GetCharged() uint32 {
+	if x != nil {
+		return x.Charged
+	}
+	return 0
+}
+
+type TransferRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId  string                 `protobuf:"bytes,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	ToAccountId    string                 `protobuf:"bytes,2,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`        // opened in the source account's currency if it does not exist yet
+	Amount         int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`                                      // in minor units of the source account's currency
+	IdempotencyKey string                 `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional, a retry with the same key returns the original result
+	Description    string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`                             // recorded on the ledger entries of both accounts
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_proto_balance_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TransferRequest) GetFromAccountId() string {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type TransferResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // reason if not successful
+	From          *BalanceResponse       `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`                                     // balance of the source account after the transfer
+	To            *BalanceResponse       `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`                                         // balance of the destination account after the transfer
+	JournalId     string                 `protobuf:"bytes,5,opt,name=journal_id,json=journalId,proto3" json:"journal_id,omitempty"`          // ledger journal the transfer was posted in
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResult) Reset() {
+	*x = TransferResult{}
+	mi := &file_proto_balance_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResult) ProtoMessage() {}
+
+func (x *TransferResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balance_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResult.ProtoReflect.Descriptor instead.
+func (*TransferResult) Descriptor() ([]byte, []int) {
+	return file_proto_balance_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TransferResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TransferResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *TransferResult) GetFrom() *BalanceResponse {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TransferResult) GetTo() *BalanceResponse {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TransferResult) GetJournalId() string {
+	if x != nil {
+		return x.JournalId
+	}
+	return ""
+}
+
+var File_proto_balance_proto protoreflect.FileDescriptor
+
+const file_proto_balance_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/balance.proto\"*\n" +
+	"\tAccountID\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"O\n" +
+	"\x12OpenAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xf9\x01\n" +
+	"\x0fBalanceResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12'\n" +
+	"\x0fcurrent_balance\x18\x02 \x01(\x03R\x0ecurrentBalance\x12+\n" +
+	"\x11available_balance\x18\x03 \x01(\x03R\x10availableBalance\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12,\n" +
+	"\bbalances\x18\x05 \x03(\v2\x10.CurrencyBalanceR\bbalances\x12'\n" +
+	"\x0foverdraft_limit\x18\x06 \x01(\x03R\x0eoverdraftLimit\"\xac\x01\n" +
+	"\x0fCurrencyBalance\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12'\n" +
+	"\x0fcurrent_balance\x18\x02 \x01(\x03R\x0ecurrentBalance\x12+\n" +
+	"\x11available_balance\x18\x03 \x01(\x03R\x10availableBalance\x12'\n" +
+	"\x0foverdraft_limit\x18\x04 \x01(\x03R\x0eoverdraftLimit\"\x93\x01\n" +
+	"\x15AuthorizeDebitRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"\xb9\x02\n" +
+	"\vDebitResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12\x1f\n" +
+	"\vnew_balance\x18\x03 \x01(\x03R\n" +
+	"newBalance\x12\x17\n" +
+	"\ahold_id\x18\x04 \x01(\tR\x06holdId\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x17\n" +
+	"\afx_rate\x18\x06 \x01(\tR\x06fxRate\x12)\n" +
+	"\x10converted_amount\x18\a \x01(\x03R\x0fconvertedAmount\x12\x15\n" +
+	"\x06fx_fee\x18\b \x01(\x03R\x05fxFee\x12:\n" +
+	"\x0edecline_reason\x18\t \x01(\x0e2\x13.DebitDeclineReasonR\rdeclineReason\"\x8b\x01\n" +
+	"\rCreditRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"\x80\x02\n" +
+	"\vLedgerEntry\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\x12\x1d\n" +
+	"\n" +
+	"journal_id\x18\x02 \x01(\tR\tjournalId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12#\n" +
+	"\rbalance_after\x18\x05 \x01(\x03R\fbalanceAfter\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\"l\n" +
+	"\x18ListLedgerEntriesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\x12\x1b\n" +
+	"\tbefore_id\x18\x03 \x01(\tR\bbeforeId\"7\n" +
+	"\rLedgerEntries\x12&\n" +
+	"\aentries\x18\x01 \x03(\v2\f.LedgerEntryR\aentries\"!\n" +
+	"\x06HoldID\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\tR\x06holdId\"E\n" +
+	"\x12CaptureHoldRequest\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\tR\x06holdId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"m\n" +
+	"\x11ReduceHoldRequest\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\tR\x06holdId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"i\n" +
+	"\x10ReduceHoldResult\x12*\n" +
+	"\abalance\x18\x01 \x01(\v2\x10.BalanceResponseR\abalance\x12)\n" +
+	"\x10remaining_amount\x18\x02 \x01(\x03R\x0fremainingAmount\"*\n" +
+	"\x12ExpireHoldsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\rR\x05limit\"/\n" +
+	"\x13ExpireHoldsResponse\x12\x18\n" +
+	"\aexpired\x18\x01 \x01(\rR\aexpired\"O\n" +
+	"\x18SetOverdraftLimitRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\"I\n" +
+	"\x1dAccrueOverdraftChargesRequest\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\":\n" +
+	"\x1eAccrueOverdraftChargesResponse\x12\x18\n" +
+	"\acharged\x18\x01 \x01(\rR\acharged\"\xc0\x01\n" +
+	"\x0fTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\tR\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x02 \x01(\tR\vtoAccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"\xb6\x01\n" +
+	"\x0eTransferResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12$\n" +
+	"\x04from\x18\x03 \x01(\v2\x10.BalanceResponseR\x04from\x12 \n" +
+	"\x02to\x18\x04 \x01(\v2\x10.BalanceResponseR\x02to\x12\x1d\n" +
+	"\n" +
+	"journal_id\x18\x05 \x01(\tR\tjournalId*\xf3\x01\n" +
+	"\x12DebitDeclineReason\x12$\n" +
+	" DEBIT_DECLINE_REASON_UNSPECIFIED\x10\x00\x12*\n" +
+	"&DEBIT_DECLINE_REASON_ACCOUNT_NOT_FOUND\x10\x01\x12+\n" +
+	"'DEBIT_DECLINE_REASON_INSUFFICIENT_FUNDS\x10\x02\x12-\n" +
+	")DEBIT_DECLINE_REASON_OVER_OVERDRAFT_LIMIT\x10\x03\x12/\n" +
+	"+DEBIT_DECLINE_REASON_CURRENCY_NOT_SUPPORTED\x10\x042\xb1\x05\n" +
+	"\aBalance\x124\n" +
+	"\vOpenAccount\x12\x13.OpenAccountRequest\x1a\x10.BalanceResponse\x12*\n" +
+	"\n" +
+	"GetBalance\x12\n" +
+	".AccountID\x1a\x10.BalanceResponse\x126\n" +
+	"\x0eAuthorizeDebit\x12\x16.AuthorizeDebitRequest\x1a\f.DebitResult\x121\n" +
+	"\rCreditAccount\x12\x0e.CreditRequest\x1a\x10.BalanceResponse\x12>\n" +
+	"\x11ListLedgerEntries\x12\x19.ListLedgerEntriesRequest\x1a\x0e.LedgerEntries\x124\n" +
+	"\vCaptureHold\x12\x13.CaptureHoldRequest\x1a\x10.BalanceResponse\x12(\n" +
+	"\vReleaseHold\x12\a.HoldID\x1a\x10.BalanceResponse\x123\n" +
+	"\n" +
+	"ReduceHold\x12\x12.ReduceHoldRequest\x1a\x11.ReduceHoldResult\x128\n" +
+	"\vExpireHolds\x12\x13.ExpireHoldsRequest\x1a\x14.ExpireHoldsResponse\x12@\n" +
+	"\x11SetOverdraftLimit\x12\x19.SetOverdraftLimitRequest\x1a\x10.BalanceResponse\x12Y\n" +
+	"\x16AccrueOverdraftCharges\x12\x1e.AccrueOverdraftChargesRequest\x1a\x1f.AccrueOverdraftChargesResponse\x12-\n" +
+	"\bTransfer\x12\x10.TransferRequest\x1a\x0f.TransferResultB\vZ\t./balanceb\x06proto3"
 
 var (
 	file_proto_balance_proto_rawDescOnce sync.Once
@@ -325,26 +1511,68 @@ func file_proto_balance_proto_rawDescGZIP() []byte {
 	return file_proto_balance_proto_rawDescData
 }
 
-var file_proto_balance_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_balance_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_balance_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_proto_balance_proto_goTypes = []any{
-	(*AccountID)(nil),             // 0: AccountID
-	(*BalanceResponse)(nil),       // 1: BalanceResponse
-	(*AuthorizeDebitRequest)(nil), // 2: AuthorizeDebitRequest
-	(*DebitResult)(nil),           // 3: DebitResult
-	(*CreditRequest)(nil),         // 4: CreditRequest
+	(DebitDeclineReason)(0),                // 0: DebitDeclineReason
+	(*AccountID)(nil),                      // 1: AccountID
+	(*OpenAccountRequest)(nil),             // 2: OpenAccountRequest
+	(*BalanceResponse)(nil),                // 3: BalanceResponse
+	(*CurrencyBalance)(nil),                // 4: CurrencyBalance
+	(*AuthorizeDebitRequest)(nil),          // 5: AuthorizeDebitRequest
+	(*DebitResult)(nil),                    // 6: DebitResult
+	(*CreditRequest)(nil),                  // 7: CreditRequest
+	(*LedgerEntry)(nil),                    // 8: LedgerEntry
+	(*ListLedgerEntriesRequest)(nil),       // 9: ListLedgerEntriesRequest
+	(*LedgerEntries)(nil),                  // 10: LedgerEntries
+	(*HoldID)(nil),                         // 11: HoldID
+	(*CaptureHoldRequest)(nil),             // 12: CaptureHoldRequest
+	(*ReduceHoldRequest)(nil),              // 13: ReduceHoldRequest
+	(*ReduceHoldResult)(nil),               // 14: ReduceHoldResult
+	(*ExpireHoldsRequest)(nil),             // 15: ExpireHoldsRequest
+	(*ExpireHoldsResponse)(nil),            // 16: ExpireHoldsResponse
+	(*SetOverdraftLimitRequest)(nil),       // 17: SetOverdraftLimitRequest
+	(*AccrueOverdraftChargesRequest)(nil),  // 18: AccrueOverdraftChargesRequest
+	(*AccrueOverdraftChargesResponse)(nil), // 19: AccrueOverdraftChargesResponse
+	(*TransferRequest)(nil),                // 20: TransferRequest
+	(*TransferResult)(nil),                 // 21: TransferResult
 }
 var file_proto_balance_proto_depIdxs = []int32{
-	0, // 0: Balance.GetBalance:input_type -> AccountID
-	2, // 1: Balance.AuthorizeDebit:input_type -> AuthorizeDebitRequest
-	4, // 2: Balance.CreditAccount:input_type -> CreditRequest
-	1, // 3: Balance.GetBalance:output_type -> BalanceResponse
-	3, // 4: Balance.AuthorizeDebit:output_type -> DebitResult
-	1, // 5: Balance.CreditAccount:output_type -> BalanceResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4,  // 0: BalanceResponse.balances:type_name -> CurrencyBalance
+	0,  // 1: DebitResult.decline_reason:type_name -> DebitDeclineReason
+	8,  // 2: LedgerEntries.entries:type_name -> LedgerEntry
+	3,  // 3: ReduceHoldResult.balance:type_name -> BalanceResponse
+	3,  // 4: TransferResult.from:type_name -> BalanceResponse
+	3,  // 5: TransferResult.to:type_name -> BalanceResponse
+	2,  // 6: Balance.OpenAccount:input_type -> OpenAccountRequest
+	1,  // 7: Balance.GetBalance:input_type -> AccountID
+	5,  // 8: Balance.AuthorizeDebit:input_type -> AuthorizeDebitRequest
+	7,  // 9: Balance.CreditAccount:input_type -> CreditRequest
+	9,  // 10: Balance.ListLedgerEntries:input_type -> ListLedgerEntriesRequest
+	12, // 11: Balance.CaptureHold:input_type -> CaptureHoldRequest
+	11, // 12: Balance.ReleaseHold:input_type -> HoldID
+	13, // 13: Balance.ReduceHold:input_type -> ReduceHoldRequest
+	15, // 14: Balance.ExpireHolds:input_type -> ExpireHoldsRequest
+	17, // 15: Balance.SetOverdraftLimit:input_type -> SetOverdraftLimitRequest
+	18, // 16: Balance.AccrueOverdraftCharges:input_type -> AccrueOverdraftChargesRequest
+	20, // 17: Balance.Transfer:input_type -> TransferRequest
+	3,  // 18: Balance.OpenAccount:output_type -> BalanceResponse
+	3,  // 19: Balance.GetBalance:output_type -> BalanceResponse
+	6,  // 20: Balance.AuthorizeDebit:output_type -> DebitResult
+	3,  // 21: Balance.CreditAccount:output_type -> BalanceResponse
+	10, // 22: Balance.ListLedgerEntries:output_type -> LedgerEntries
+	3,  // 23: Balance.CaptureHold:output_type -> BalanceResponse
+	3,  // 24: Balance.ReleaseHold:output_type -> BalanceResponse
+	14, // 25: Balance.ReduceHold:output_type -> ReduceHoldResult
+	16, // 26: Balance.ExpireHolds:output_type -> ExpireHoldsResponse
+	3,  // 27: Balance.SetOverdraftLimit:output_type -> BalanceResponse
+	19, // 28: Balance.AccrueOverdraftCharges:output_type -> AccrueOverdraftChargesResponse
+	21, // 29: Balance.Transfer:output_type -> TransferResult
+	18, // [18:30] is the sub-list for method output_type
+	6,  // [6:18] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_proto_balance_proto_init() }
@@ -357,13 +1585,14 @@ func file_proto_balance_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_balance_proto_rawDesc), len(file_proto_balance_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_balance_proto_goTypes,
 		DependencyIndexes: file_proto_balance_proto_depIdxs,
+		EnumInfos:         file_proto_balance_proto_enumTypes,
 		MessageInfos:      file_proto_balance_proto_msgTypes,
 	}.Build()
 	File_proto_balance_proto = out.File
@@ -19,18 +19,36 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Balance_GetBalance_FullMethodName     = "/Balance/GetBalance"
-	Balance_AuthorizeDebit_FullMethodName = "/Balance/AuthorizeDebit"
-	Balance_CreditAccount_FullMethodName  = "/Balance/CreditAccount"
+	Balance_OpenAccount_FullMethodName            = "/Balance/OpenAccount"
+	Balance_GetBalance_FullMethodName             = "/Balance/GetBalance"
+	Balance_AuthorizeDebit_FullMethodName         = "/Balance/AuthorizeDebit"
+	Balance_CreditAccount_FullMethodName          = "/Balance/CreditAccount"
+	Balance_ListLedgerEntries_FullMethodName      = "/Balance/ListLedgerEntries"
+	Balance_CaptureHold_FullMethodName            = "/Balance/CaptureHold"
+	Balance_ReleaseHold_FullMethodName            = "/Balance/ReleaseHold"
+	Balance_ReduceHold_FullMethodName             = "/Balance/ReduceHold"
+	Balance_ExpireHolds_FullMethodName            = "/Balance/ExpireHolds"
+	Balance_SetOverdraftLimit_FullMethodName      = "/Balance/SetOverdraftLimit"
+	Balance_AccrueOverdraftCharges_FullMethodName = "/Balance/AccrueOverdraftCharges"
+	Balance_Transfer_FullMethodName               = "/Balance/Transfer"
 )
 
 // BalanceClient is the client API for Balance service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type BalanceClient interface {
+	OpenAccount(ctx context.Context, in *OpenAccountRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
 	GetBalance(ctx context.Context, in *AccountID, opts ...grpc.CallOption) (*BalanceResponse, error)
 	AuthorizeDebit(ctx context.Context, in *AuthorizeDebitRequest, opts ...grpc.CallOption) (*DebitResult, error)
 	CreditAccount(ctx context.Context, in *CreditRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	ListLedgerEntries(ctx context.Context, in *ListLedgerEntriesRequest, opts ...grpc.CallOption) (*LedgerEntries, error)
+	CaptureHold(ctx context.Context, in *CaptureHoldRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	ReleaseHold(ctx context.Context, in *HoldID, opts ...grpc.CallOption) (*BalanceResponse, error)
+	ReduceHold(ctx context.Context, in *ReduceHoldRequest, opts ...grpc.CallOption) (*ReduceHoldResult, error)
+	ExpireHolds(ctx context.Context, in *ExpireHoldsRequest, opts ...grpc.CallOption) (*ExpireHoldsResponse, error)
+	SetOverdraftLimit(ctx context.Context, in *SetOverdraftLimitRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	AccrueOverdraftCharges(ctx context.Context, in *AccrueOverdraftChargesRequest, opts ...grpc.CallOption) (*AccrueOverdraftChargesResponse, error)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResult, error)
 }
 
 type balanceClient struct {
@@ -41,6 +59,16 @@ func NewBalanceClient(cc grpc.ClientConnInterface) BalanceClient {
 	return &balanceClient{cc}
 }
 
+func (c *balanceClient) OpenAccount(ctx context.Context, in *OpenAccountRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceResponse)
+	err := c.cc.Invoke(ctx, Balance_OpenAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *balanceClient) GetBalance(ctx context.Context, in *AccountID, opts ...grpc.CallOption) (*BalanceResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BalanceResponse)
@@ -71,13 +99,102 @@ func (c *balanceClient) CreditAccount(ctx context.Context, in *CreditRequest, op
 	return out, nil
 }
 
+func (c *balanceClient) ListLedgerEntries(ctx context.Context, in *ListLedgerEntriesRequest, opts ...grpc.CallOption) (*LedgerEntries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LedgerEntries)
+	err := c.cc.Invoke(ctx, Balance_ListLedgerEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) CaptureHold(ctx context.Context, in *CaptureHoldRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceResponse)
+	err := c.cc.Invoke(ctx, Balance_CaptureHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) ReleaseHold(ctx context.Context, in *HoldID, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceResponse)
+	err := c.cc.Invoke(ctx, Balance_ReleaseHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) ReduceHold(ctx context.Context, in *ReduceHoldRequest, opts ...grpc.CallOption) (*ReduceHoldResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReduceHoldResult)
+	err := c.cc.Invoke(ctx, Balance_ReduceHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) ExpireHolds(ctx context.Context, in *ExpireHoldsRequest, opts ...grpc.CallOption) (*ExpireHoldsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExpireHoldsResponse)
+	err := c.cc.Invoke(ctx, Balance_ExpireHolds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) SetOverdraftLimit(ctx context.Context, in *SetOverdraftLimitRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceResponse)
+	err := c.cc.Invoke(ctx, Balance_SetOverdraftLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) AccrueOverdraftCharges(ctx context.Context, in *AccrueOverdraftChargesRequest, opts ...grpc.CallOption) (*AccrueOverdraftChargesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccrueOverdraftChargesResponse)
+	err := c.cc.Invoke(ctx, Balance_AccrueOverdraftCharges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferResult)
+	err := c.cc.Invoke(ctx, Balance_Transfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BalanceServer is the server API for Balance service.
 // All implementations must embed UnimplementedBalanceServer
 // for forward compatibility.
 type BalanceServer interface {
+	OpenAccount(context.Context, *OpenAccountRequest) (*BalanceResponse, error)
 	GetBalance(context.Context, *AccountID) (*BalanceResponse, error)
 	AuthorizeDebit(context.Context, *AuthorizeDebitRequest) (*DebitResult, error)
 	CreditAccount(context.Context, *CreditRequest) (*BalanceResponse, error)
+	ListLedgerEntries(context.Context, *ListLedgerEntriesRequest) (*LedgerEntries, error)
+	CaptureHold(context.Context, *CaptureHoldRequest) (*BalanceResponse, error)
+	ReleaseHold(context.Context, *HoldID) (*BalanceResponse, error)
+	ReduceHold(context.Context, *ReduceHoldRequest) (*ReduceHoldResult, error)
+	ExpireHolds(context.Context, *ExpireHoldsRequest) (*ExpireHoldsResponse, error)
+	SetOverdraftLimit(context.Context, *SetOverdraftLimitRequest) (*BalanceResponse, error)
+	AccrueOverdraftCharges(context.Context, *AccrueOverdraftChargesRequest) (*AccrueOverdraftChargesResponse, error)
+	Transfer(context.Context, *TransferRequest) (*TransferResult, error)
 	mustEmbedUnimplementedBalanceServer()
 }
 
@@ -88,6 +205,9 @@ type BalanceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedBalanceServer struct{}
 
+func (UnimplementedBalanceServer) OpenAccount(context.Context, *OpenAccountRequest) (*BalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenAccount not implemented")
+}
 func (UnimplementedBalanceServer) GetBalance(context.Context, *AccountID) (*BalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
 }
@@ -97,6 +217,30 @@ func (UnimplementedBalanceServer) AuthorizeDebit(context.Context, *AuthorizeDebi
 func (UnimplementedBalanceServer) CreditAccount(context.Context, *CreditRequest) (*BalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreditAccount not implemented")
 }
+func (UnimplementedBalanceServer) ListLedgerEntries(context.Context, *ListLedgerEntriesRequest) (*LedgerEntries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLedgerEntries not implemented")
+}
+func (UnimplementedBalanceServer) CaptureHold(context.Context, *CaptureHoldRequest) (*BalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CaptureHold not implemented")
+}
+func (UnimplementedBalanceServer) ReleaseHold(context.Context, *HoldID) (*BalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseHold not implemented")
+}
+func (UnimplementedBalanceServer) ReduceHold(context.Context, *ReduceHoldRequest) (*ReduceHoldResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReduceHold not implemented")
+}
+func (UnimplementedBalanceServer) ExpireHolds(context.Context, *ExpireHoldsRequest) (*ExpireHoldsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExpireHolds not implemented")
+}
+func (UnimplementedBalanceServer) SetOverdraftLimit(context.Context, *SetOverdraftLimitRequest) (*BalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOverdraftLimit not implemented")
+}
+func (UnimplementedBalanceServer) AccrueOverdraftCharges(context.Context, *AccrueOverdraftChargesRequest) (*AccrueOverdraftChargesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccrueOverdraftCharges not implemented")
+}
+func (UnimplementedBalanceServer) Transfer(context.Context, *TransferRequest) (*TransferResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
 func (UnimplementedBalanceServer) mustEmbedUnimplementedBalanceServer() {}
 func (UnimplementedBalanceServer) testEmbeddedByValue()                 {}
 
@@ -118,6 +262,24 @@ func RegisterBalanceServer(s grpc.ServiceRegistrar, srv BalanceServer) {
 	s.RegisterService(&Balance_ServiceDesc, srv)
 }
 
+func _Balance_OpenAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).OpenAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_OpenAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).OpenAccount(ctx, req.(*OpenAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Balance_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AccountID)
 	if err := dec(in); err != nil {
@@ -172,6 +334,150 @@ func _Balance_CreditAccount_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Balance_ListLedgerEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLedgerEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).ListLedgerEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_ListLedgerEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).ListLedgerEntries(ctx, req.(*ListLedgerEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_CaptureHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).CaptureHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_CaptureHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).CaptureHold(ctx, req.(*CaptureHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_ReleaseHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).ReleaseHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_ReleaseHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).ReleaseHold(ctx, req.(*HoldID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_ReduceHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReduceHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).ReduceHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_ReduceHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).ReduceHold(ctx, req.(*ReduceHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_ExpireHolds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireHoldsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).ExpireHolds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_ExpireHolds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).ExpireHolds(ctx, req.(*ExpireHoldsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_SetOverdraftLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOverdraftLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).SetOverdraftLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_SetOverdraftLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).SetOverdraftLimit(ctx, req.(*SetOverdraftLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_AccrueOverdraftCharges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccrueOverdraftChargesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).AccrueOverdraftCharges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_AccrueOverdraftCharges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).AccrueOverdraftCharges(ctx, req.(*AccrueOverdraftChargesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Balance_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Balance_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Balance_ServiceDesc is the grpc.ServiceDesc for Balance service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -179,6 +485,10 @@ var Balance_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "Balance",
 	HandlerType: (*BalanceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "OpenAccount",
+			Handler:    _Balance_OpenAccount_Handler,
+		},
 		{
 			MethodName: "GetBalance",
 			Handler:    _Balance_GetBalance_Handler,
@@ -191,6 +501,38 @@ var Balance_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreditAccount",
 			Handler:    _Balance_CreditAccount_Handler,
 		},
+		{
+			MethodName: "ListLedgerEntries",
+			Handler:    _Balance_ListLedgerEntries_Handler,
+		},
+		{
+			MethodName: "CaptureHold",
+			Handler:    _Balance_CaptureHold_Handler,
+		},
+		{
+			MethodName: "ReleaseHold",
+			Handler:    _Balance_ReleaseHold_Handler,
+		},
+		{
+			MethodName: "ReduceHold",
+			Handler:    _Balance_ReduceHold_Handler,
+		},
+		{
+			MethodName: "ExpireHolds",
+			Handler:    _Balance_ExpireHolds_Handler,
+		},
+		{
+			MethodName: "SetOverdraftLimit",
+			Handler:    _Balance_SetOverdraftLimit_Handler,
+		},
+		{
+			MethodName: "AccrueOverdraftCharges",
+			Handler:    _Balance_AccrueOverdraftCharges_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _Balance_Transfer_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/balance.proto",
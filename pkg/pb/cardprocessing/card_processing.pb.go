@@ -21,15 +21,120 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Why an authorization was declined. Networks are sent a response code mapped from it, and
+// account holders are shown an explanation of it.
+type DeclineCode int32
+
+const (
+	DeclineCode_DECLINE_CODE_UNSPECIFIED              DeclineCode = 0
+	DeclineCode_DECLINE_CODE_CARD_FROZEN              DeclineCode = 1
+	DeclineCode_DECLINE_CODE_CARD_CLOSED              DeclineCode = 2
+	DeclineCode_DECLINE_CODE_INSUFFICIENT_FUNDS       DeclineCode = 3
+	DeclineCode_DECLINE_CODE_LIMIT_EXCEEDED           DeclineCode = 4 // a spending limit on the card
+	DeclineCode_DECLINE_CODE_MERCHANT_BLOCKED         DeclineCode = 5
+	DeclineCode_DECLINE_CODE_SUSPECTED_FRAUD          DeclineCode = 6
+	DeclineCode_DECLINE_CODE_INVALID_CURRENCY         DeclineCode = 7
+	DeclineCode_DECLINE_CODE_SYSTEM_ERROR             DeclineCode = 8
+	DeclineCode_DECLINE_CODE_CARD_NOT_FOUND           DeclineCode = 9
+	DeclineCode_DECLINE_CODE_CARD_INACTIVE            DeclineCode = 10 // not yet activated
+	DeclineCode_DECLINE_CODE_AUTHENTICATION_REQUIRED  DeclineCode = 11 // risky enough that the cardholder must authenticate, e.g. with 3-D Secure
+	DeclineCode_DECLINE_CODE_CHANNEL_DISABLED         DeclineCode = 12 // the cardholder has turned off payments of this kind, e.g. online
+	DeclineCode_DECLINE_CODE_INCORRECT_PIN            DeclineCode = 13
+	DeclineCode_DECLINE_CODE_PIN_LOCKED               DeclineCode = 14 // too many wrong PINs in a row
+	DeclineCode_DECLINE_CODE_CARD_EXPIRED             DeclineCode = 15 // a virtual card past the expiry the cardholder gave it
+	DeclineCode_DECLINE_CODE_MERCHANT_LOCKED          DeclineCode = 16 // a merchant-locked virtual card used at another merchant
+	DeclineCode_DECLINE_CODE_ACCOUNT_UNAVAILABLE      DeclineCode = 17 // the card has no open account to take the payment from
+	DeclineCode_DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED DeclineCode = 18 // the payment would go over the account's arranged overdraft
+)
+
+// Enum value maps for DeclineCode.
+var (
+	DeclineCode_name = map[int32]string{
+		0:  "DECLINE_CODE_UNSPECIFIED",
+		1:  "DECLINE_CODE_CARD_FROZEN",
+		2:  "DECLINE_CODE_CARD_CLOSED",
+		3:  "DECLINE_CODE_INSUFFICIENT_FUNDS",
+		4:  "DECLINE_CODE_LIMIT_EXCEEDED",
+		5:  "DECLINE_CODE_MERCHANT_BLOCKED",
+		6:  "DECLINE_CODE_SUSPECTED_FRAUD",
+		7:  "DECLINE_CODE_INVALID_CURRENCY",
+		8:  "DECLINE_CODE_SYSTEM_ERROR",
+		9:  "DECLINE_CODE_CARD_NOT_FOUND",
+		10: "DECLINE_CODE_CARD_INACTIVE",
+		11: "DECLINE_CODE_AUTHENTICATION_REQUIRED",
+		12: "DECLINE_CODE_CHANNEL_DISABLED",
+		13: "DECLINE_CODE_INCORRECT_PIN",
+		14: "DECLINE_CODE_PIN_LOCKED",
+		15: "DECLINE_CODE_CARD_EXPIRED",
+		16: "DECLINE_CODE_MERCHANT_LOCKED",
+		17: "DECLINE_CODE_ACCOUNT_UNAVAILABLE",
+		18: "DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED",
+	}
+	DeclineCode_value = map[string]int32{
+		"DECLINE_CODE_UNSPECIFIED":              0,
+		"DECLINE_CODE_CARD_FROZEN":              1,
+		"DECLINE_CODE_CARD_CLOSED":              2,
+		"DECLINE_CODE_INSUFFICIENT_FUNDS":       3,
+		"DECLINE_CODE_LIMIT_EXCEEDED":           4,
+		"DECLINE_CODE_MERCHANT_BLOCKED":         5,
+		"DECLINE_CODE_SUSPECTED_FRAUD":          6,
+		"DECLINE_CODE_INVALID_CURRENCY":         7,
+		"DECLINE_CODE_SYSTEM_ERROR":             8,
+		"DECLINE_CODE_CARD_NOT_FOUND":           9,
+		"DECLINE_CODE_CARD_INACTIVE":            10,
+		"DECLINE_CODE_AUTHENTICATION_REQUIRED":  11,
+		"DECLINE_CODE_CHANNEL_DISABLED":         12,
+		"DECLINE_CODE_INCORRECT_PIN":            13,
+		"DECLINE_CODE_PIN_LOCKED":               14,
+		"DECLINE_CODE_CARD_EXPIRED":             15,
+		"DECLINE_CODE_MERCHANT_LOCKED":          16,
+		"DECLINE_CODE_ACCOUNT_UNAVAILABLE":      17,
+		"DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED": 18,
+	}
+)
+
+func (x DeclineCode) Enum() *DeclineCode {
+	p := new(DeclineCode)
+	*p = x
+	return p
+}
+
+func (x DeclineCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeclineCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_card_processing_proto_enumTypes[0].Descriptor()
+}
+
+func (DeclineCode) Type() protoreflect.EnumType {
+	return &file_proto_card_processing_proto_enumTypes[0]
+}
+
+func (x DeclineCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeclineCode.Descriptor instead.
+func (DeclineCode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_card_processing_proto_rawDescGZIP(), []int{0}
+}
+
 type CardAuthRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // amount in cents
-	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
-	MerchantId    string                 `protobuf:"bytes,4,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`       // optional merchant ID
-	MerchantName  string                 `protobuf:"bytes,5,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"` // raw merchant name
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CardId          string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // card to authorize against; card networks give pan_token instead
+	Amount          int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`              // amount in cents
+	Currency        string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	MerchantId      string                 `protobuf:"bytes,4,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`                // optional merchant ID
+	MerchantName    string                 `protobuf:"bytes,5,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"`          // raw merchant name
+	MerchantCountry string                 `protobuf:"bytes,6,opt,name=merchant_country,json=merchantCountry,proto3" json:"merchant_country,omitempty"` // optional ISO 3166-1 alpha-2 country code of the merchant
+	Channel         string                 `protobuf:"bytes,7,opt,name=channel,proto3" json:"channel,omitempty"`                                        // optional, how the card was used: "CHIP", "CONTACTLESS", "MAGSTRIPE", "ONLINE" or "ATM"
+	Mcc             int32                  `protobuf:"varint,8,opt,name=mcc,proto3" json:"mcc,omitempty"`                                               // optional ISO 18245 merchant category code
+	Recurring       bool                   `protobuf:"varint,9,opt,name=recurring,proto3" json:"recurring,omitempty"`                                   // a payment the merchant initiated with card details stored for recurring payments
+	PanToken        string                 `protobuf:"bytes,10,opt,name=pan_token,json=panToken,proto3" json:"pan_token,omitempty"`                     // vault token of the card number, identifies the card when card_id is empty
+	PinBlock        string                 `protobuf:"bytes,11,opt,name=pin_block,json=pinBlock,proto3" json:"pin_block,omitempty"`                     // optional PIN entered for a CHIP or ATM payment, ISO 9564 format 1; never logged
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *CardAuthRequest) Reset() {
@@ -97,10 +202,54 @@ func (x *CardAuthRequest) GetMerchantName() string {
 	return ""
 }
 
+func (x *CardAuthRequest) GetMerchantCountry() string {
+	if x != nil {
+		return x.MerchantCountry
+	}
+	return ""
+}
+
+func (x *CardAuthRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *CardAuthRequest) GetMcc() int32 {
+	if x != nil {
+		return x.Mcc
+	}
+	return 0
+}
+
+func (x *CardAuthRequest) GetRecurring() bool {
+	if x != nil {
+		return x.Recurring
+	}
+	return false
+}
+
+func (x *CardAuthRequest) GetPanToken() string {
+	if x != nil {
+		return x.PanToken
+	}
+	return ""
+}
+
+func (x *CardAuthRequest) GetPinBlock() string {
+	if x != nil {
+		return x.PinBlock
+	}
+	return ""
+}
+
 type CardAuthReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Approved      bool                   `protobuf:"varint,1,opt,name=approved,proto3" json:"approved,omitempty"`
-	DeclineReason string                 `protobuf:"bytes,2,opt,name=decline_reason,json=declineReason,proto3" json:"decline_reason,omitempty"` // reason if not approved
+	DeclineReason string                 `protobuf:"bytes,2,opt,name=decline_reason,json=declineReason,proto3" json:"decline_reason,omitempty"`             // reason if not approved
+	AuthCode      string                 `protobuf:"bytes,3,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"`                            // authorization code if approved, quoted back by the network at settlement
+	DeclineCode   DeclineCode            `protobuf:"varint,4,opt,name=decline_code,json=declineCode,proto3,enum=DeclineCode" json:"decline_code,omitempty"` // set if not approved
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -149,23 +298,408 @@ func (x *CardAuthReply) GetDeclineReason() string {
 	return ""
 }
 
+func (x *CardAuthReply) GetAuthCode() string {
+	if x != nil {
+		return x.AuthCode
+	}
+	return ""
+}
+
+func (x *CardAuthReply) GetDeclineCode() DeclineCode {
+	if x != nil {
+		return x.DeclineCode
+	}
+	return DeclineCode_DECLINE_CODE_UNSPECIFIED
+}
+
+type ReversalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // authorized card transaction to reverse in full
+	CardId        string                 `protobuf:"bytes,2,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                      // with auth_code, identifies the transaction when transaction_id is empty, as card networks do
+	AuthCode      string                 `protobuf:"bytes,3,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"`
+	PanToken      string                 `protobuf:"bytes,4,opt,name=pan_token,json=panToken,proto3" json:"pan_token,omitempty"` // identifies the card instead of card_id, as for CardAuthRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReversalRequest) Reset() {
+	*x = ReversalRequest{}
+	mi := &file_proto_card_processing_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReversalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReversalRequest) ProtoMessage() {}
+
+func (x *ReversalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_card_processing_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReversalRequest.ProtoReflect.Descriptor instead.
+func (*ReversalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_card_processing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReversalRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ReversalRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *ReversalRequest) GetAuthCode() string {
+	if x != nil {
+		return x.AuthCode
+	}
+	return ""
+}
+
+func (x *ReversalRequest) GetPanToken() string {
+	if x != nil {
+		return x.PanToken
+	}
+	return ""
+}
+
+type PartialReversalRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId  string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`    // authorized card transaction to reverse part of
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                      // amount no longer authorized, in the transaction's currency; must be less than the authorized amount
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional, a retry with the same key does not reverse the amount again
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PartialReversalRequest) Reset() {
+	*x = PartialReversalRequest{}
+	mi := &file_proto_card_processing_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartialReversalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartialReversalRequest) ProtoMessage() {}
+
+func (x *PartialReversalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_card_processing_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartialReversalRequest.ProtoReflect.Descriptor instead.
+func (*PartialReversalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_card_processing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PartialReversalRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PartialReversalRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PartialReversalRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type ReversalReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`  // status of the transaction after the reversal, AUTHORIZED or REVERSED
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"` // amount still authorized, 0 once reversed in full
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReversalReply) Reset() {
+	*x = ReversalReply{}
+	mi := &file_proto_card_processing_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReversalReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReversalReply) ProtoMessage() {}
+
+func (x *ReversalReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_card_processing_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReversalReply.ProtoReflect.Descriptor instead.
+func (*ReversalReply) Descriptor() ([]byte, []int) {
+	return file_proto_card_processing_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReversalReply) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ReversalReply) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReversalReply) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type RefundRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId  string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`    // settled card transaction to refund
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                      // amount to refund in the transaction's currency, at most what is left to refund
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // required, a retry with the same key completes and returns the original refund
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RefundRequest) Reset() {
+	*x = RefundRequest{}
+	mi := &file_proto_card_processing_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundRequest) ProtoMessage() {}
+
+func (x *RefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_card_processing_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundRequest.ProtoReflect.Descriptor instead.
+func (*RefundRequest) Descriptor() ([]byte, []int) {
+	return file_proto_card_processing_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RefundRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RefundReply struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	RefundTransactionId   string                 `protobuf:"bytes,1,opt,name=refund_transaction_id,json=refundTransactionId,proto3" json:"refund_transaction_id,omitempty"` // transaction the refund was recorded as
+	OriginalTransactionId string                 `protobuf:"bytes,2,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"`
+	Amount                int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`                                      // amount refunded
+	OriginalStatus        string                 `protobuf:"bytes,4,opt,name=original_status,json=originalStatus,proto3" json:"original_status,omitempty"` // status of the original transaction, PARTIALLY_REFUNDED or REVERSED once refunded in full
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RefundReply) Reset() {
+	*x = RefundReply{}
+	mi := &file_proto_card_processing_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundReply) ProtoMessage() {}
+
+func (x *RefundReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_card_processing_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundReply.ProtoReflect.Descriptor instead.
+func (*RefundReply) Descriptor() ([]byte, []int) {
+	return file_proto_card_processing_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RefundReply) GetRefundTransactionId() string {
+	if x != nil {
+		return x.RefundTransactionId
+	}
+	return ""
+}
+
+func (x *RefundReply) GetOriginalTransactionId() string {
+	if x != nil {
+		return x.OriginalTransactionId
+	}
+	return ""
+}
+
+func (x *RefundReply) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundReply) GetOriginalStatus() string {
+	if x != nil {
+		return x.OriginalStatus
+	}
+	return ""
+}
+
 var File_proto_card_processing_proto protoreflect.FileDescriptor
 
 const file_proto_card_processing_proto_rawDesc = "" +
 	"\n" +
-	"\x1bproto/card_processing.proto\"\xa4\x01\n" +
+	"\x1bproto/card_processing.proto\"\xd3\x02\n" +
 	"\x0fCardAuthRequest\x12\x17\n" +
 	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x1a\n" +
 	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1f\n" +
 	"\vmerchant_id\x18\x04 \x01(\tR\n" +
 	"merchantId\x12#\n" +
-	"\rmerchant_name\x18\x05 \x01(\tR\fmerchantName\"R\n" +
+	"\rmerchant_name\x18\x05 \x01(\tR\fmerchantName\x12)\n" +
+	"\x10merchant_country\x18\x06 \x01(\tR\x0fmerchantCountry\x12\x18\n" +
+	"\achannel\x18\a \x01(\tR\achannel\x12\x10\n" +
+	"\x03mcc\x18\b \x01(\x05R\x03mcc\x12\x1c\n" +
+	"\trecurring\x18\t \x01(\bR\trecurring\x12\x1b\n" +
+	"\tpan_token\x18\n" +
+	" \x01(\tR\bpanToken\x12\x1b\n" +
+	"\tpin_block\x18\v \x01(\tR\bpinBlock\"\xa0\x01\n" +
 	"\rCardAuthReply\x12\x1a\n" +
 	"\bapproved\x18\x01 \x01(\bR\bapproved\x12%\n" +
-	"\x0edecline_reason\x18\x02 \x01(\tR\rdeclineReason2N\n" +
+	"\x0edecline_reason\x18\x02 \x01(\tR\rdeclineReason\x12\x1b\n" +
+	"\tauth_code\x18\x03 \x01(\tR\bauthCode\x12/\n" +
+	"\fdecline_code\x18\x04 \x01(\x0e2\f.DeclineCodeR\vdeclineCode\"\x8b\x01\n" +
+	"\x0fReversalRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x17\n" +
+	"\acard_id\x18\x02 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tauth_code\x18\x03 \x01(\tR\bauthCode\x12\x1b\n" +
+	"\tpan_token\x18\x04 \x01(\tR\bpanToken\"\x80\x01\n" +
+	"\x16PartialReversalRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"f\n" +
+	"\rReversalReply\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\"w\n" +
+	"\rRefundRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\xba\x01\n" +
+	"\vRefundReply\x122\n" +
+	"\x15refund_transaction_id\x18\x01 \x01(\tR\x13refundTransactionId\x126\n" +
+	"\x17original_transaction_id\x18\x02 \x01(\tR\x15originalTransactionId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12'\n" +
+	"\x0foriginal_status\x18\x04 \x01(\tR\x0eoriginalStatus*\x91\x05\n" +
+	"\vDeclineCode\x12\x1c\n" +
+	"\x18DECLINE_CODE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18DECLINE_CODE_CARD_FROZEN\x10\x01\x12\x1c\n" +
+	"\x18DECLINE_CODE_CARD_CLOSED\x10\x02\x12#\n" +
+	"\x1fDECLINE_CODE_INSUFFICIENT_FUNDS\x10\x03\x12\x1f\n" +
+	"\x1bDECLINE_CODE_LIMIT_EXCEEDED\x10\x04\x12!\n" +
+	"\x1dDECLINE_CODE_MERCHANT_BLOCKED\x10\x05\x12 \n" +
+	"\x1cDECLINE_CODE_SUSPECTED_FRAUD\x10\x06\x12!\n" +
+	"\x1dDECLINE_CODE_INVALID_CURRENCY\x10\a\x12\x1d\n" +
+	"\x19DECLINE_CODE_SYSTEM_ERROR\x10\b\x12\x1f\n" +
+	"\x1bDECLINE_CODE_CARD_NOT_FOUND\x10\t\x12\x1e\n" +
+	"\x1aDECLINE_CODE_CARD_INACTIVE\x10\n" +
+	"\x12(\n" +
+	"$DECLINE_CODE_AUTHENTICATION_REQUIRED\x10\v\x12!\n" +
+	"\x1dDECLINE_CODE_CHANNEL_DISABLED\x10\f\x12\x1e\n" +
+	"\x1aDECLINE_CODE_INCORRECT_PIN\x10\r\x12\x1b\n" +
+	"\x17DECLINE_CODE_PIN_LOCKED\x10\x0e\x12\x1d\n" +
+	"\x19DECLINE_CODE_CARD_EXPIRED\x10\x0f\x12 \n" +
+	"\x1cDECLINE_CODE_MERCHANT_LOCKED\x10\x10\x12$\n" +
+	" DECLINE_CODE_ACCOUNT_UNAVAILABLE\x10\x11\x12)\n" +
+	"%DECLINE_CODE_OVERDRAFT_LIMIT_EXCEEDED\x10\x122\xf7\x01\n" +
 	"\x0eCardProcessing\x12<\n" +
-	"\x18AuthorizeCardTransaction\x12\x10.CardAuthRequest\x1a\x0e.CardAuthReplyB\x13Z\x11./card_processingb\x06proto3"
+	"\x18AuthorizeCardTransaction\x12\x10.CardAuthRequest\x1a\x0e.CardAuthReply\x128\n" +
+	"\x14ReverseAuthorization\x12\x10.ReversalRequest\x1a\x0e.ReversalReply\x12:\n" +
+	"\x0fPartialReversal\x12\x17.PartialReversalRequest\x1a\x0e.ReversalReply\x121\n" +
+	"\x11RefundTransaction\x12\x0e.RefundRequest\x1a\f.RefundReplyB\x13Z\x11./card_processingb\x06proto3"
 
 var (
 	file_proto_card_processing_proto_rawDescOnce sync.Once
@@ -179,19 +713,33 @@ func file_proto_card_processing_proto_rawDescGZIP() []byte {
 	return file_proto_card_processing_proto_rawDescData
 }
 
-var file_proto_card_processing_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_card_processing_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_card_processing_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_card_processing_proto_goTypes = []any{
-	(*CardAuthRequest)(nil), // 0: CardAuthRequest
-	(*CardAuthReply)(nil),   // 1: CardAuthReply
+	(DeclineCode)(0),               // 0: DeclineCode
+	(*CardAuthRequest)(nil),        // 1: CardAuthRequest
+	(*CardAuthReply)(nil),          // 2: CardAuthReply
+	(*ReversalRequest)(nil),        // 3: ReversalRequest
+	(*PartialReversalRequest)(nil), // 4: PartialReversalRequest
+	(*ReversalReply)(nil),          // 5: ReversalReply
+	(*RefundRequest)(nil),          // 6: RefundRequest
+	(*RefundReply)(nil),            // 7: RefundReply
 }
 var file_proto_card_processing_proto_depIdxs = []int32{
-	0, // 0: CardProcessing.AuthorizeCardTransaction:input_type -> CardAuthRequest
-	1, // 1: CardProcessing.AuthorizeCardTransaction:output_type -> CardAuthReply
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: CardAuthReply.decline_code:type_name -> DeclineCode
+	1, // 1: CardProcessing.AuthorizeCardTransaction:input_type -> CardAuthRequest
+	3, // 2: CardProcessing.ReverseAuthorization:input_type -> ReversalRequest
+	4, // 3: CardProcessing.PartialReversal:input_type -> PartialReversalRequest
+	6, // 4: CardProcessing.RefundTransaction:input_type -> RefundRequest
+	2, // 5: CardProcessing.AuthorizeCardTransaction:output_type -> CardAuthReply
+	5, // 6: CardProcessing.ReverseAuthorization:output_type -> ReversalReply
+	5, // 7: CardProcessing.PartialReversal:output_type -> ReversalReply
+	7, // 8: CardProcessing.RefundTransaction:output_type -> RefundReply
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_proto_card_processing_proto_init() }
@@ -204,13 +752,14 @@ func file_proto_card_processing_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_card_processing_proto_rawDesc), len(file_proto_card_processing_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_card_processing_proto_goTypes,
 		DependencyIndexes: file_proto_card_processing_proto_depIdxs,
+		EnumInfos:         file_proto_card_processing_proto_enumTypes,
 		MessageInfos:      file_proto_card_processing_proto_msgTypes,
 	}.Build()
 	File_proto_card_processing_proto = out.File
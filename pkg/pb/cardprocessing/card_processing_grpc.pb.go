@@ -20,6 +20,9 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	CardProcessing_AuthorizeCardTransaction_FullMethodName = "/CardProcessing/AuthorizeCardTransaction"
+	CardProcessing_ReverseAuthorization_FullMethodName     = "/CardProcessing/ReverseAuthorization"
+	CardProcessing_PartialReversal_FullMethodName          = "/CardProcessing/PartialReversal"
+	CardProcessing_RefundTransaction_FullMethodName        = "/CardProcessing/RefundTransaction"
 )
 
 // CardProcessingClient is the client API for CardProcessing service.
@@ -27,6 +30,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CardProcessingClient interface {
 	AuthorizeCardTransaction(ctx context.Context, in *CardAuthRequest, opts ...grpc.CallOption) (*CardAuthReply, error)
+	ReverseAuthorization(ctx context.Context, in *ReversalRequest, opts ...grpc.CallOption) (*ReversalReply, error)
+	PartialReversal(ctx context.Context, in *PartialReversalRequest, opts ...grpc.CallOption) (*ReversalReply, error)
+	RefundTransaction(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundReply, error)
 }
 
 type cardProcessingClient struct {
@@ -47,11 +53,44 @@ func (c *cardProcessingClient) AuthorizeCardTransaction(ctx context.Context, in
 	return out, nil
 }
 
+func (c *cardProcessingClient) ReverseAuthorization(ctx context.Context, in *ReversalRequest, opts ...grpc.CallOption) (*ReversalReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReversalReply)
+	err := c.cc.Invoke(ctx, CardProcessing_ReverseAuthorization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardProcessingClient) PartialReversal(ctx context.Context, in *PartialReversalRequest, opts ...grpc.CallOption) (*ReversalReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReversalReply)
+	err := c.cc.Invoke(ctx, CardProcessing_PartialReversal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardProcessingClient) RefundTransaction(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefundReply)
+	err := c.cc.Invoke(ctx, CardProcessing_RefundTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CardProcessingServer is the server API for CardProcessing service.
 // All implementations must embed UnimplementedCardProcessingServer
 // for forward compatibility.
 type CardProcessingServer interface {
 	AuthorizeCardTransaction(context.Context, *CardAuthRequest) (*CardAuthReply, error)
+	ReverseAuthorization(context.Context, *ReversalRequest) (*ReversalReply, error)
+	PartialReversal(context.Context, *PartialReversalRequest) (*ReversalReply, error)
+	RefundTransaction(context.Context, *RefundRequest) (*RefundReply, error)
 	mustEmbedUnimplementedCardProcessingServer()
 }
 
@@ -65,6 +104,15 @@ type UnimplementedCardProcessingServer struct{}
 func (UnimplementedCardProcessingServer) AuthorizeCardTransaction(context.Context, *CardAuthRequest) (*CardAuthReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AuthorizeCardTransaction not implemented")
 }
+func (UnimplementedCardProcessingServer) ReverseAuthorization(context.Context, *ReversalRequest) (*ReversalReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReverseAuthorization not implemented")
+}
+func (UnimplementedCardProcessingServer) PartialReversal(context.Context, *PartialReversalRequest) (*ReversalReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PartialReversal not implemented")
+}
+func (UnimplementedCardProcessingServer) RefundTransaction(context.Context, *RefundRequest) (*RefundReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundTransaction not implemented")
+}
 func (UnimplementedCardProcessingServer) mustEmbedUnimplementedCardProcessingServer() {}
 func (UnimplementedCardProcessingServer) testEmbeddedByValue()                        {}
 
@@ -104,6 +152,60 @@ func _CardProcessing_AuthorizeCardTransaction_Handler(srv interface{}, ctx conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CardProcessing_ReverseAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReversalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardProcessingServer).ReverseAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardProcessing_ReverseAuthorization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardProcessingServer).ReverseAuthorization(ctx, req.(*ReversalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardProcessing_PartialReversal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PartialReversalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardProcessingServer).PartialReversal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardProcessing_PartialReversal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardProcessingServer).PartialReversal(ctx, req.(*PartialReversalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardProcessing_RefundTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardProcessingServer).RefundTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardProcessing_RefundTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardProcessingServer).RefundTransaction(ctx, req.(*RefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CardProcessing_ServiceDesc is the grpc.ServiceDesc for CardProcessing service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +217,18 @@ var CardProcessing_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AuthorizeCardTransaction",
 			Handler:    _CardProcessing_AuthorizeCardTransaction_Handler,
 		},
+		{
+			MethodName: "ReverseAuthorization",
+			Handler:    _CardProcessing_ReverseAuthorization_Handler,
+		},
+		{
+			MethodName: "PartialReversal",
+			Handler:    _CardProcessing_PartialReversal_Handler,
+		},
+		{
+			MethodName: "RefundTransaction",
+			Handler:    _CardProcessing_RefundTransaction_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/card_processing.proto",
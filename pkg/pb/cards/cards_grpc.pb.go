@@ -19,9 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Cards_CreateCard_FullMethodName       = "/Cards/CreateCard"
-	Cards_GetCard_FullMethodName          = "/Cards/GetCard"
-	Cards_UpdateCardStatus_FullMethodName = "/Cards/UpdateCardStatus"
+	Cards_CreateCard_FullMethodName             = "/Cards/CreateCard"
+	Cards_GetCard_FullMethodName                = "/Cards/GetCard"
+	Cards_GetCardByPanToken_FullMethodName      = "/Cards/GetCardByPanToken"
+	Cards_ListCardsByUser_FullMethodName        = "/Cards/ListCardsByUser"
+	Cards_UpdateCardStatus_FullMethodName       = "/Cards/UpdateCardStatus"
+	Cards_GetCardHistory_FullMethodName         = "/Cards/GetCardHistory"
+	Cards_ListVirtualCards_FullMethodName       = "/Cards/ListVirtualCards"
+	Cards_LockCardToMerchant_FullMethodName     = "/Cards/LockCardToMerchant"
+	Cards_UseSingleUseCard_FullMethodName       = "/Cards/UseSingleUseCard"
+	Cards_SetCardNickname_FullMethodName        = "/Cards/SetCardNickname"
+	Cards_SetDefaultCard_FullMethodName         = "/Cards/SetDefaultCard"
+	Cards_GetCardControls_FullMethodName        = "/Cards/GetCardControls"
+	Cards_UpdateCardControls_FullMethodName     = "/Cards/UpdateCardControls"
+	Cards_ReissueCard_FullMethodName            = "/Cards/ReissueCard"
+	Cards_AddRecurringMerchant_FullMethodName   = "/Cards/AddRecurringMerchant"
+	Cards_ListRecurringMerchants_FullMethodName = "/Cards/ListRecurringMerchants"
+	Cards_SetPin_FullMethodName                 = "/Cards/SetPin"
+	Cards_ChangePin_FullMethodName              = "/Cards/ChangePin"
+	Cards_RevealPin_FullMethodName              = "/Cards/RevealPin"
+	Cards_VerifyPin_FullMethodName              = "/Cards/VerifyPin"
 )
 
 // CardsClient is the client API for Cards service.
@@ -30,7 +47,24 @@ const (
 type CardsClient interface {
 	CreateCard(ctx context.Context, in *CreateCardRequest, opts ...grpc.CallOption) (*Card, error)
 	GetCard(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*Card, error)
+	GetCardByPanToken(ctx context.Context, in *GetCardByPanTokenRequest, opts ...grpc.CallOption) (*Card, error)
+	ListCardsByUser(ctx context.Context, in *ListCardsByUserRequest, opts ...grpc.CallOption) (*CardList, error)
 	UpdateCardStatus(ctx context.Context, in *UpdateCardStatusRequest, opts ...grpc.CallOption) (*Card, error)
+	GetCardHistory(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*CardHistory, error)
+	ListVirtualCards(ctx context.Context, in *ListVirtualCardsRequest, opts ...grpc.CallOption) (*VirtualCards, error)
+	LockCardToMerchant(ctx context.Context, in *LockCardToMerchantRequest, opts ...grpc.CallOption) (*Card, error)
+	UseSingleUseCard(ctx context.Context, in *UseSingleUseCardRequest, opts ...grpc.CallOption) (*Card, error)
+	SetCardNickname(ctx context.Context, in *SetCardNicknameRequest, opts ...grpc.CallOption) (*Card, error)
+	SetDefaultCard(ctx context.Context, in *SetDefaultCardRequest, opts ...grpc.CallOption) (*Card, error)
+	GetCardControls(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*CardControls, error)
+	UpdateCardControls(ctx context.Context, in *CardControls, opts ...grpc.CallOption) (*CardControls, error)
+	ReissueCard(ctx context.Context, in *ReissueCardRequest, opts ...grpc.CallOption) (*Card, error)
+	AddRecurringMerchant(ctx context.Context, in *RecurringMerchant, opts ...grpc.CallOption) (*RecurringMerchant, error)
+	ListRecurringMerchants(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*RecurringMerchants, error)
+	SetPin(ctx context.Context, in *SetPinRequest, opts ...grpc.CallOption) (*PinStatus, error)
+	ChangePin(ctx context.Context, in *ChangePinRequest, opts ...grpc.CallOption) (*PinStatus, error)
+	RevealPin(ctx context.Context, in *RevealPinRequest, opts ...grpc.CallOption) (*RevealPinResponse, error)
+	VerifyPin(ctx context.Context, in *VerifyPinRequest, opts ...grpc.CallOption) (*PinStatus, error)
 }
 
 type cardsClient struct {
@@ -61,6 +95,26 @@ func (c *cardsClient) GetCard(ctx context.Context, in *GetCardRequest, opts ...g
 	return out, nil
 }
 
+func (c *cardsClient) GetCardByPanToken(ctx context.Context, in *GetCardByPanTokenRequest, opts ...grpc.CallOption) (*Card, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Card)
+	err := c.cc.Invoke(ctx, Cards_GetCardByPanToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListCardsByUser(ctx context.Context, in *ListCardsByUserRequest, opts ...grpc.CallOption) (*CardList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardList)
+	err := c.cc.Invoke(ctx, Cards_ListCardsByUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cardsClient) UpdateCardStatus(ctx context.Context, in *UpdateCardStatusRequest, opts ...grpc.CallOption) (*Card, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Card)
@@ -71,13 +125,180 @@ func (c *cardsClient) UpdateCardStatus(ctx context.Context, in *UpdateCardStatus
 	return out, nil
 }
 
+func (c *cardsClient) GetCardHistory(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*CardHistory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardHistory)
+	err := c.cc.Invoke(ctx, Cards_GetCardHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListVirtualCards(ctx context.Context, in *ListVirtualCardsRequest, opts ...grpc.CallOption) (*VirtualCards, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VirtualCards)
+	err := c.cc.Invoke(ctx, Cards_ListVirtualCards_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) LockCardToMerchant(ctx context.Context, in *LockCardToMerchantRequest, opts ...grpc.CallOption) (*Card, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Card)
+	err := c.cc.Invoke(ctx, Cards_LockCardToMerchant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UseSingleUseCard(ctx context.Context, in *UseSingleUseCardRequest, opts ...grpc.CallOption) (*Card, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Card)
+	err := c.cc.Invoke(ctx, Cards_UseSingleUseCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) SetCardNickname(ctx context.Context, in *SetCardNicknameRequest, opts ...grpc.CallOption) (*Card, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Card)
+	err := c.cc.Invoke(ctx, Cards_SetCardNickname_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) SetDefaultCard(ctx context.Context, in *SetDefaultCardRequest, opts ...grpc.CallOption) (*Card, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Card)
+	err := c.cc.Invoke(ctx, Cards_SetDefaultCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetCardControls(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*CardControls, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardControls)
+	err := c.cc.Invoke(ctx, Cards_GetCardControls_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateCardControls(ctx context.Context, in *CardControls, opts ...grpc.CallOption) (*CardControls, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardControls)
+	err := c.cc.Invoke(ctx, Cards_UpdateCardControls_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ReissueCard(ctx context.Context, in *ReissueCardRequest, opts ...grpc.CallOption) (*Card, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Card)
+	err := c.cc.Invoke(ctx, Cards_ReissueCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) AddRecurringMerchant(ctx context.Context, in *RecurringMerchant, opts ...grpc.CallOption) (*RecurringMerchant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecurringMerchant)
+	err := c.cc.Invoke(ctx, Cards_AddRecurringMerchant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListRecurringMerchants(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*RecurringMerchants, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecurringMerchants)
+	err := c.cc.Invoke(ctx, Cards_ListRecurringMerchants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) SetPin(ctx context.Context, in *SetPinRequest, opts ...grpc.CallOption) (*PinStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinStatus)
+	err := c.cc.Invoke(ctx, Cards_SetPin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ChangePin(ctx context.Context, in *ChangePinRequest, opts ...grpc.CallOption) (*PinStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinStatus)
+	err := c.cc.Invoke(ctx, Cards_ChangePin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) RevealPin(ctx context.Context, in *RevealPinRequest, opts ...grpc.CallOption) (*RevealPinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevealPinResponse)
+	err := c.cc.Invoke(ctx, Cards_RevealPin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) VerifyPin(ctx context.Context, in *VerifyPinRequest, opts ...grpc.CallOption) (*PinStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinStatus)
+	err := c.cc.Invoke(ctx, Cards_VerifyPin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CardsServer is the server API for Cards service.
 // All implementations must embed UnimplementedCardsServer
 // for forward compatibility.
 type CardsServer interface {
 	CreateCard(context.Context, *CreateCardRequest) (*Card, error)
 	GetCard(context.Context, *GetCardRequest) (*Card, error)
+	GetCardByPanToken(context.Context, *GetCardByPanTokenRequest) (*Card, error)
+	ListCardsByUser(context.Context, *ListCardsByUserRequest) (*CardList, error)
 	UpdateCardStatus(context.Context, *UpdateCardStatusRequest) (*Card, error)
+	GetCardHistory(context.Context, *GetCardRequest) (*CardHistory, error)
+	ListVirtualCards(context.Context, *ListVirtualCardsRequest) (*VirtualCards, error)
+	LockCardToMerchant(context.Context, *LockCardToMerchantRequest) (*Card, error)
+	UseSingleUseCard(context.Context, *UseSingleUseCardRequest) (*Card, error)
+	SetCardNickname(context.Context, *SetCardNicknameRequest) (*Card, error)
+	SetDefaultCard(context.Context, *SetDefaultCardRequest) (*Card, error)
+	GetCardControls(context.Context, *GetCardRequest) (*CardControls, error)
+	UpdateCardControls(context.Context, *CardControls) (*CardControls, error)
+	ReissueCard(context.Context, *ReissueCardRequest) (*Card, error)
+	AddRecurringMerchant(context.Context, *RecurringMerchant) (*RecurringMerchant, error)
+	ListRecurringMerchants(context.Context, *GetCardRequest) (*RecurringMerchants, error)
+	SetPin(context.Context, *SetPinRequest) (*PinStatus, error)
+	ChangePin(context.Context, *ChangePinRequest) (*PinStatus, error)
+	RevealPin(context.Context, *RevealPinRequest) (*RevealPinResponse, error)
+	VerifyPin(context.Context, *VerifyPinRequest) (*PinStatus, error)
 	mustEmbedUnimplementedCardsServer()
 }
 
@@ -94,9 +315,60 @@ func (UnimplementedCardsServer) CreateCard(context.Context, *CreateCardRequest)
 func (UnimplementedCardsServer) GetCard(context.Context, *GetCardRequest) (*Card, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCard not implemented")
 }
+func (UnimplementedCardsServer) GetCardByPanToken(context.Context, *GetCardByPanTokenRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCardByPanToken not implemented")
+}
+func (UnimplementedCardsServer) ListCardsByUser(context.Context, *ListCardsByUserRequest) (*CardList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCardsByUser not implemented")
+}
 func (UnimplementedCardsServer) UpdateCardStatus(context.Context, *UpdateCardStatusRequest) (*Card, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateCardStatus not implemented")
 }
+func (UnimplementedCardsServer) GetCardHistory(context.Context, *GetCardRequest) (*CardHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCardHistory not implemented")
+}
+func (UnimplementedCardsServer) ListVirtualCards(context.Context, *ListVirtualCardsRequest) (*VirtualCards, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVirtualCards not implemented")
+}
+func (UnimplementedCardsServer) LockCardToMerchant(context.Context, *LockCardToMerchantRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockCardToMerchant not implemented")
+}
+func (UnimplementedCardsServer) UseSingleUseCard(context.Context, *UseSingleUseCardRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UseSingleUseCard not implemented")
+}
+func (UnimplementedCardsServer) SetCardNickname(context.Context, *SetCardNicknameRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCardNickname not implemented")
+}
+func (UnimplementedCardsServer) SetDefaultCard(context.Context, *SetDefaultCardRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultCard not implemented")
+}
+func (UnimplementedCardsServer) GetCardControls(context.Context, *GetCardRequest) (*CardControls, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCardControls not implemented")
+}
+func (UnimplementedCardsServer) UpdateCardControls(context.Context, *CardControls) (*CardControls, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCardControls not implemented")
+}
+func (UnimplementedCardsServer) ReissueCard(context.Context, *ReissueCardRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReissueCard not implemented")
+}
+func (UnimplementedCardsServer) AddRecurringMerchant(context.Context, *RecurringMerchant) (*RecurringMerchant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRecurringMerchant not implemented")
+}
+func (UnimplementedCardsServer) ListRecurringMerchants(context.Context, *GetCardRequest) (*RecurringMerchants, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecurringMerchants not implemented")
+}
+func (UnimplementedCardsServer) SetPin(context.Context, *SetPinRequest) (*PinStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPin not implemented")
+}
+func (UnimplementedCardsServer) ChangePin(context.Context, *ChangePinRequest) (*PinStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePin not implemented")
+}
+func (UnimplementedCardsServer) RevealPin(context.Context, *RevealPinRequest) (*RevealPinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevealPin not implemented")
+}
+func (UnimplementedCardsServer) VerifyPin(context.Context, *VerifyPinRequest) (*PinStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyPin not implemented")
+}
 func (UnimplementedCardsServer) mustEmbedUnimplementedCardsServer() {}
 func (UnimplementedCardsServer) testEmbeddedByValue()               {}
 
@@ -154,6 +426,42 @@ func _Cards_GetCard_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cards_GetCardByPanToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardByPanTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetCardByPanToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_GetCardByPanToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetCardByPanToken(ctx, req.(*GetCardByPanTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListCardsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCardsByUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListCardsByUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_ListCardsByUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListCardsByUser(ctx, req.(*ListCardsByUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Cards_UpdateCardStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateCardStatusRequest)
 	if err := dec(in); err != nil {
@@ -172,6 +480,276 @@ func _Cards_UpdateCardStatus_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cards_GetCardHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetCardHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_GetCardHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetCardHistory(ctx, req.(*GetCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListVirtualCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVirtualCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListVirtualCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_ListVirtualCards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListVirtualCards(ctx, req.(*ListVirtualCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_LockCardToMerchant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockCardToMerchantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).LockCardToMerchant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_LockCardToMerchant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).LockCardToMerchant(ctx, req.(*LockCardToMerchantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UseSingleUseCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UseSingleUseCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UseSingleUseCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_UseSingleUseCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UseSingleUseCard(ctx, req.(*UseSingleUseCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_SetCardNickname_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCardNicknameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).SetCardNickname(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_SetCardNickname_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).SetCardNickname(ctx, req.(*SetCardNicknameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_SetDefaultCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).SetDefaultCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_SetDefaultCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).SetDefaultCard(ctx, req.(*SetDefaultCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetCardControls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetCardControls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_GetCardControls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetCardControls(ctx, req.(*GetCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateCardControls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CardControls)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateCardControls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_UpdateCardControls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateCardControls(ctx, req.(*CardControls))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ReissueCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReissueCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ReissueCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_ReissueCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ReissueCard(ctx, req.(*ReissueCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_AddRecurringMerchant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecurringMerchant)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).AddRecurringMerchant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_AddRecurringMerchant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).AddRecurringMerchant(ctx, req.(*RecurringMerchant))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListRecurringMerchants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListRecurringMerchants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_ListRecurringMerchants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListRecurringMerchants(ctx, req.(*GetCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_SetPin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).SetPin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_SetPin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).SetPin(ctx, req.(*SetPinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ChangePin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ChangePin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_ChangePin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ChangePin(ctx, req.(*ChangePinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_RevealPin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealPinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).RevealPin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_RevealPin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).RevealPin(ctx, req.(*RevealPinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_VerifyPin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyPinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).VerifyPin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cards_VerifyPin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).VerifyPin(ctx, req.(*VerifyPinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Cards_ServiceDesc is the grpc.ServiceDesc for Cards service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -187,10 +765,78 @@ var Cards_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCard",
 			Handler:    _Cards_GetCard_Handler,
 		},
+		{
+			MethodName: "GetCardByPanToken",
+			Handler:    _Cards_GetCardByPanToken_Handler,
+		},
+		{
+			MethodName: "ListCardsByUser",
+			Handler:    _Cards_ListCardsByUser_Handler,
+		},
 		{
 			MethodName: "UpdateCardStatus",
 			Handler:    _Cards_UpdateCardStatus_Handler,
 		},
+		{
+			MethodName: "GetCardHistory",
+			Handler:    _Cards_GetCardHistory_Handler,
+		},
+		{
+			MethodName: "ListVirtualCards",
+			Handler:    _Cards_ListVirtualCards_Handler,
+		},
+		{
+			MethodName: "LockCardToMerchant",
+			Handler:    _Cards_LockCardToMerchant_Handler,
+		},
+		{
+			MethodName: "UseSingleUseCard",
+			Handler:    _Cards_UseSingleUseCard_Handler,
+		},
+		{
+			MethodName: "SetCardNickname",
+			Handler:    _Cards_SetCardNickname_Handler,
+		},
+		{
+			MethodName: "SetDefaultCard",
+			Handler:    _Cards_SetDefaultCard_Handler,
+		},
+		{
+			MethodName: "GetCardControls",
+			Handler:    _Cards_GetCardControls_Handler,
+		},
+		{
+			MethodName: "UpdateCardControls",
+			Handler:    _Cards_UpdateCardControls_Handler,
+		},
+		{
+			MethodName: "ReissueCard",
+			Handler:    _Cards_ReissueCard_Handler,
+		},
+		{
+			MethodName: "AddRecurringMerchant",
+			Handler:    _Cards_AddRecurringMerchant_Handler,
+		},
+		{
+			MethodName: "ListRecurringMerchants",
+			Handler:    _Cards_ListRecurringMerchants_Handler,
+		},
+		{
+			MethodName: "SetPin",
+			Handler:    _Cards_SetPin_Handler,
+		},
+		{
+			MethodName: "ChangePin",
+			Handler:    _Cards_ChangePin_Handler,
+		},
+		{
+			MethodName: "RevealPin",
+			Handler:    _Cards_RevealPin_Handler,
+		},
+		{
+			MethodName: "VerifyPin",
+			Handler:    _Cards_VerifyPin_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/cards.proto",
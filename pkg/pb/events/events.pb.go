@@ -0,0 +1,1158 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/events.proto
+
+package events
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Published on "transaction:created" when a transaction is recorded
+type TransactionCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"` // amount in cents
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                        // ISO 8601
+	CardId        string                 `protobuf:"bytes,7,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                // optional
+	MerchantRaw   string                 `protobuf:"bytes,8,opt,name=merchant_raw,json=merchantRaw,proto3" json:"merchant_raw,omitempty"` // raw merchant description
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionCreated) Reset() {
+	*x = TransactionCreated{}
+	mi := &file_proto_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionCreated) ProtoMessage() {}
+
+func (x *TransactionCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionCreated.ProtoReflect.Descriptor instead.
+func (*TransactionCreated) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TransactionCreated) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransactionCreated) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *TransactionCreated) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransactionCreated) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TransactionCreated) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransactionCreated) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *TransactionCreated) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *TransactionCreated) GetMerchantRaw() string {
+	if x != nil {
+		return x.MerchantRaw
+	}
+	return ""
+}
+
+// Published on "balance:updated" whenever an account's balance changes
+type BalanceUpdated struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AccountId        string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	NewBalance       int64                  `protobuf:"varint,2,opt,name=new_balance,json=newBalance,proto3" json:"new_balance,omitempty"`                   // in minor units of currency
+	AvailableBalance int64                  `protobuf:"varint,3,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"` // new_balance less funds held by open authorizations
+	Currency         string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                                          // ISO 4217 code of the balance that changed
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BalanceUpdated) Reset() {
+	*x = BalanceUpdated{}
+	mi := &file_proto_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceUpdated) ProtoMessage() {}
+
+func (x *BalanceUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceUpdated.ProtoReflect.Descriptor instead.
+func (*BalanceUpdated) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BalanceUpdated) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *BalanceUpdated) GetNewBalance() int64 {
+	if x != nil {
+		return x.NewBalance
+	}
+	return 0
+}
+
+func (x *BalanceUpdated) GetAvailableBalance() int64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *BalanceUpdated) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Published on "card:created" when a card is issued
+type CardCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardCreated) Reset() {
+	*x = CardCreated{}
+	mi := &file_proto_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardCreated) ProtoMessage() {}
+
+func (x *CardCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardCreated.ProtoReflect.Descriptor instead.
+func (*CardCreated) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CardCreated) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardCreated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CardCreated) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// Published on "card:status_changed" when a card is frozen, unfrozen or closed
+type CardStatusChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	NewStatus     string                 `protobuf:"bytes,3,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardStatusChanged) Reset() {
+	*x = CardStatusChanged{}
+	mi := &file_proto_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardStatusChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardStatusChanged) ProtoMessage() {}
+
+func (x *CardStatusChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardStatusChanged.ProtoReflect.Descriptor instead.
+func (*CardStatusChanged) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CardStatusChanged) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardStatusChanged) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CardStatusChanged) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+// Published on "feed:item.created" when a feed item is added for an account
+type FeedItemCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FeedItemId    string                 `protobuf:"bytes,1,opt,name=feed_item_id,json=feedItemId,proto3" json:"feed_item_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	TransactionId string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // set for TRANSACTION feed items
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedItemCreated) Reset() {
+	*x = FeedItemCreated{}
+	mi := &file_proto_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedItemCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedItemCreated) ProtoMessage() {}
+
+func (x *FeedItemCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedItemCreated.ProtoReflect.Descriptor instead.
+func (*FeedItemCreated) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FeedItemCreated) GetFeedItemId() string {
+	if x != nil {
+		return x.FeedItemId
+	}
+	return ""
+}
+
+func (x *FeedItemCreated) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *FeedItemCreated) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FeedItemCreated) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+// Published on "merchant:updated" when a merchant's details change
+type MerchantUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MerchantId    string                 `protobuf:"bytes,1,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	LogoUrl       string                 `protobuf:"bytes,4,opt,name=logo_url,json=logoUrl,proto3" json:"logo_url,omitempty"`
+	Mcc           int32                  `protobuf:"varint,5,opt,name=mcc,proto3" json:"mcc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MerchantUpdated) Reset() {
+	*x = MerchantUpdated{}
+	mi := &file_proto_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MerchantUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MerchantUpdated) ProtoMessage() {}
+
+func (x *MerchantUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MerchantUpdated.ProtoReflect.Descriptor instead.
+func (*MerchantUpdated) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MerchantUpdated) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *MerchantUpdated) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MerchantUpdated) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *MerchantUpdated) GetLogoUrl() string {
+	if x != nil {
+		return x.LogoUrl
+	}
+	return ""
+}
+
+func (x *MerchantUpdated) GetMcc() int32 {
+	if x != nil {
+		return x.Mcc
+	}
+	return 0
+}
+
+// Published on "pot:moved" when money is moved between an account and one of its pots
+type PotMoved struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MoveId        string                 `protobuf:"bytes,1,opt,name=move_id,json=moveId,proto3" json:"move_id,omitempty"`
+	PotId         string                 `protobuf:"bytes,2,opt,name=pot_id,json=potId,proto3" json:"pot_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // the account that owns the pot
+	PotName       string                 `protobuf:"bytes,4,opt,name=pot_name,json=potName,proto3" json:"pot_name,omitempty"`
+	Direction     string                 `protobuf:"bytes,5,opt,name=direction,proto3" json:"direction,omitempty"` // "DEPOSIT" into the pot or "WITHDRAWAL" from it
+	Amount        int64                  `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`      // in minor units of currency
+	Currency      string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	PotBalance    int64                  `protobuf:"varint,8,opt,name=pot_balance,json=potBalance,proto3" json:"pot_balance,omitempty"` // pot balance after the move, in minor units of currency
+	Timestamp     string                 `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                      // ISO 8601
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PotMoved) Reset() {
+	*x = PotMoved{}
+	mi := &file_proto_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PotMoved) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PotMoved) ProtoMessage() {}
+
+func (x *PotMoved) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PotMoved.ProtoReflect.Descriptor instead.
+func (*PotMoved) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PotMoved) GetMoveId() string {
+	if x != nil {
+		return x.MoveId
+	}
+	return ""
+}
+
+func (x *PotMoved) GetPotId() string {
+	if x != nil {
+		return x.PotId
+	}
+	return ""
+}
+
+func (x *PotMoved) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PotMoved) GetPotName() string {
+	if x != nil {
+		return x.PotName
+	}
+	return ""
+}
+
+func (x *PotMoved) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *PotMoved) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PotMoved) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PotMoved) GetPotBalance() int64 {
+	if x != nil {
+		return x.PotBalance
+	}
+	return 0
+}
+
+func (x *PotMoved) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// Published on "schedule:run" when a scheduled payment is paid or fails
+type ScheduledPaymentRun struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Payee         string                 `protobuf:"bytes,4,opt,name=payee,proto3" json:"payee,omitempty"`
+	Amount        int64                  `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"` // in minor units of currency
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	RunDate       string                 `protobuf:"bytes,7,opt,name=run_date,json=runDate,proto3" json:"run_date,omitempty"`                    // YYYY-MM-DD, the occurrence that was run
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                                     // "PAID", "DECLINED" or "FAILED"
+	DeclineReason string                 `protobuf:"bytes,9,opt,name=decline_reason,json=declineReason,proto3" json:"decline_reason,omitempty"`  // set if not PAID
+	TransactionId string                 `protobuf:"bytes,10,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // set if PAID
+	Timestamp     string                 `protobuf:"bytes,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                              // ISO 8601
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledPaymentRun) Reset() {
+	*x = ScheduledPaymentRun{}
+	mi := &file_proto_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPaymentRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPaymentRun) ProtoMessage() {}
+
+func (x *ScheduledPaymentRun) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPaymentRun.ProtoReflect.Descriptor instead.
+func (*ScheduledPaymentRun) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScheduledPaymentRun) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetPayee() string {
+	if x != nil {
+		return x.Payee
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ScheduledPaymentRun) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetRunDate() string {
+	if x != nil {
+		return x.RunDate
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetDeclineReason() string {
+	if x != nil {
+		return x.DeclineReason
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ScheduledPaymentRun) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// Published on "clearing:review" for each settlement record that clearing could not settle automatically
+type ClearingRecordUnmatched struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	File             string                 `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`  // settlement file the record was read from
+	Line             int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"` // line number of the record in file
+	NetworkReference string                 `protobuf:"bytes,3,opt,name=network_reference,json=networkReference,proto3" json:"network_reference,omitempty"`
+	CardId           string                 `protobuf:"bytes,4,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	AuthCode         string                 `protobuf:"bytes,5,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"` // empty for force-posts and offline transactions
+	Amount           int64                  `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`                    // settled amount in minor units of currency
+	Currency         string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	MerchantName     string                 `protobuf:"bytes,8,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"`
+	TransactionDate  string                 `protobuf:"bytes,9,opt,name=transaction_date,json=transactionDate,proto3" json:"transaction_date,omitempty"` // YYYY-MM-DD
+	Reason           string                 `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`                                         // why the record needs review
+	TransactionId    string                 `protobuf:"bytes,11,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`      // set if the record matched a transaction that could not be settled
+	Timestamp        string                 `protobuf:"bytes,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                   // ISO 8601
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ClearingRecordUnmatched) Reset() {
+	*x = ClearingRecordUnmatched{}
+	mi := &file_proto_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearingRecordUnmatched) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearingRecordUnmatched) ProtoMessage() {}
+
+func (x *ClearingRecordUnmatched) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearingRecordUnmatched.ProtoReflect.Descriptor instead.
+func (*ClearingRecordUnmatched) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClearingRecordUnmatched) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ClearingRecordUnmatched) GetNetworkReference() string {
+	if x != nil {
+		return x.NetworkReference
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetAuthCode() string {
+	if x != nil {
+		return x.AuthCode
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ClearingRecordUnmatched) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetMerchantName() string {
+	if x != nil {
+		return x.MerchantName
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetTransactionDate() string {
+	if x != nil {
+		return x.TransactionDate
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ClearingRecordUnmatched) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// Published on "card:auth_declined" when a card authorization is declined for a known account
+type CardAuthDeclined struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"` // in minor units of currency
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	MerchantName  string                 `protobuf:"bytes,5,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"`
+	DeclineCode   string                 `protobuf:"bytes,6,opt,name=decline_code,json=declineCode,proto3" json:"decline_code,omitempty"` // name of the card_processing DeclineCode, e.g. "DECLINE_CODE_CARD_FROZEN"
+	DeclineReason string                 `protobuf:"bytes,7,opt,name=decline_reason,json=declineReason,proto3" json:"decline_reason,omitempty"`
+	TransactionId string                 `protobuf:"bytes,8,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // set if the declined transaction was recorded
+	Timestamp     string                 `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                              // ISO 8601
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardAuthDeclined) Reset() {
+	*x = CardAuthDeclined{}
+	mi := &file_proto_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardAuthDeclined) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardAuthDeclined) ProtoMessage() {}
+
+func (x *CardAuthDeclined) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardAuthDeclined.ProtoReflect.Descriptor instead.
+func (*CardAuthDeclined) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CardAuthDeclined) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CardAuthDeclined) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetMerchantName() string {
+	if x != nil {
+		return x.MerchantName
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetDeclineCode() string {
+	if x != nil {
+		return x.DeclineCode
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetDeclineReason() string {
+	if x != nil {
+		return x.DeclineReason
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *CardAuthDeclined) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// Published on "card:pin_locked" when too many wrong PINs in a row lock a card's PIN
+type CardPinLocked struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // ISO 8601
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardPinLocked) Reset() {
+	*x = CardPinLocked{}
+	mi := &file_proto_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardPinLocked) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardPinLocked) ProtoMessage() {}
+
+func (x *CardPinLocked) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardPinLocked.ProtoReflect.Descriptor instead.
+func (*CardPinLocked) Descriptor() ([]byte, []int) {
+	return file_proto_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CardPinLocked) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *CardPinLocked) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CardPinLocked) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+var File_proto_events_proto protoreflect.FileDescriptor
+
+const file_proto_events_proto_rawDesc = "" +
+	"\n" +
+	"\x12proto/events.proto\"\xe9\x01\n" +
+	"\x12TransactionCreated\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\tR\ttimestamp\x12\x17\n" +
+	"\acard_id\x18\a \x01(\tR\x06cardId\x12!\n" +
+	"\fmerchant_raw\x18\b \x01(\tR\vmerchantRaw\"\x99\x01\n" +
+	"\x0eBalanceUpdated\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1f\n" +
+	"\vnew_balance\x18\x02 \x01(\x03R\n" +
+	"newBalance\x12+\n" +
+	"\x11available_balance\x18\x03 \x01(\x03R\x10availableBalance\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"W\n" +
+	"\vCardCreated\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"d\n" +
+	"\x11CardStatusChanged\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\x03 \x01(\tR\tnewStatus\"\x8d\x01\n" +
+	"\x0fFeedItemCreated\x12 \n" +
+	"\ffeed_item_id\x18\x01 \x01(\tR\n" +
+	"feedItemId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\"\x8f\x01\n" +
+	"\x0fMerchantUpdated\x12\x1f\n" +
+	"\vmerchant_id\x18\x01 \x01(\tR\n" +
+	"merchantId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x19\n" +
+	"\blogo_url\x18\x04 \x01(\tR\alogoUrl\x12\x10\n" +
+	"\x03mcc\x18\x05 \x01(\x05R\x03mcc\"\x85\x02\n" +
+	"\bPotMoved\x12\x17\n" +
+	"\amove_id\x18\x01 \x01(\tR\x06moveId\x12\x15\n" +
+	"\x06pot_id\x18\x02 \x01(\tR\x05potId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x19\n" +
+	"\bpot_name\x18\x04 \x01(\tR\apotName\x12\x1c\n" +
+	"\tdirection\x18\x05 \x01(\tR\tdirection\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12\x1f\n" +
+	"\vpot_balance\x18\b \x01(\x03R\n" +
+	"potBalance\x12\x1c\n" +
+	"\ttimestamp\x18\t \x01(\tR\ttimestamp\"\xd5\x02\n" +
+	"\x13ScheduledPaymentRun\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1f\n" +
+	"\vschedule_id\x18\x02 \x01(\tR\n" +
+	"scheduleId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05payee\x18\x04 \x01(\tR\x05payee\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x19\n" +
+	"\brun_date\x18\a \x01(\tR\arunDate\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12%\n" +
+	"\x0edecline_reason\x18\t \x01(\tR\rdeclineReason\x12%\n" +
+	"\x0etransaction_id\x18\n" +
+	" \x01(\tR\rtransactionId\x12\x1c\n" +
+	"\ttimestamp\x18\v \x01(\tR\ttimestamp\"\x85\x03\n" +
+	"\x17ClearingRecordUnmatched\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12+\n" +
+	"\x11network_reference\x18\x03 \x01(\tR\x10networkReference\x12\x17\n" +
+	"\acard_id\x18\x04 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tauth_code\x18\x05 \x01(\tR\bauthCode\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12#\n" +
+	"\rmerchant_name\x18\b \x01(\tR\fmerchantName\x12)\n" +
+	"\x10transaction_date\x18\t \x01(\tR\x0ftransactionDate\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x12%\n" +
+	"\x0etransaction_id\x18\v \x01(\tR\rtransactionId\x12\x1c\n" +
+	"\ttimestamp\x18\f \x01(\tR\ttimestamp\"\xb2\x02\n" +
+	"\x10CardAuthDeclined\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12#\n" +
+	"\rmerchant_name\x18\x05 \x01(\tR\fmerchantName\x12!\n" +
+	"\fdecline_code\x18\x06 \x01(\tR\vdeclineCode\x12%\n" +
+	"\x0edecline_reason\x18\a \x01(\tR\rdeclineReason\x12%\n" +
+	"\x0etransaction_id\x18\b \x01(\tR\rtransactionId\x12\x1c\n" +
+	"\ttimestamp\x18\t \x01(\tR\ttimestamp\"_\n" +
+	"\rCardPinLocked\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestampB\n" +
+	"Z\b./eventsb\x06proto3"
+
+var (
+	file_proto_events_proto_rawDescOnce sync.Once
+	file_proto_events_proto_rawDescData []byte
+)
+
+func file_proto_events_proto_rawDescGZIP() []byte {
+	file_proto_events_proto_rawDescOnce.Do(func() {
+		file_proto_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_events_proto_rawDesc), len(file_proto_events_proto_rawDesc)))
+	})
+	return file_proto_events_proto_rawDescData
+}
+
+var file_proto_events_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_events_proto_goTypes = []any{
+	(*TransactionCreated)(nil),      // 0: TransactionCreated
+	(*BalanceUpdated)(nil),          // 1: BalanceUpdated
+	(*CardCreated)(nil),             // 2: CardCreated
+	(*CardStatusChanged)(nil),       // 3: CardStatusChanged
+	(*FeedItemCreated)(nil),         // 4: FeedItemCreated
+	(*MerchantUpdated)(nil),         // 5: MerchantUpdated
+	(*PotMoved)(nil),                // 6: PotMoved
+	(*ScheduledPaymentRun)(nil),     // 7: ScheduledPaymentRun
+	(*ClearingRecordUnmatched)(nil), // 8: ClearingRecordUnmatched
+	(*CardAuthDeclined)(nil),        // 9: CardAuthDeclined
+	(*CardPinLocked)(nil),           // 10: CardPinLocked
+}
+var file_proto_events_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_events_proto_init() }
+func file_proto_events_proto_init() {
+	if File_proto_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_events_proto_rawDesc), len(file_proto_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_events_proto_goTypes,
+		DependencyIndexes: file_proto_events_proto_depIdxs,
+		MessageInfos:      file_proto_events_proto_msgTypes,
+	}.Build()
+	File_proto_events_proto = out.File
+	file_proto_events_proto_goTypes = nil
+	file_proto_events_proto_depIdxs = nil
+}
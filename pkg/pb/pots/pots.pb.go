@@ -0,0 +1,676 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/pots.proto
+
+package pots
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Pot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // the account that owns the pot
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                        // ISO 4217 code, the same as the account's
+	Balance       int64                  `protobuf:"varint,5,opt,name=balance,proto3" json:"balance,omitempty"`                         // in minor units of currency
+	GoalAmount    int64                  `protobuf:"varint,6,opt,name=goal_amount,json=goalAmount,proto3" json:"goal_amount,omitempty"` // savings goal in minor units of currency, 0 if none
+	TargetDate    string                 `protobuf:"bytes,7,opt,name=target_date,json=targetDate,proto3" json:"target_date,omitempty"`  // date to reach the goal by, YYYY-MM-DD, empty if none
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                            // "OPEN" or "CLOSED"
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`     // ISO 8601
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pot) Reset() {
+	*x = Pot{}
+	mi := &file_proto_pots_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pot) ProtoMessage() {}
+
+func (x *Pot) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pot.ProtoReflect.Descriptor instead.
+func (*Pot) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Pot) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Pot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Pot) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Pot) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Pot) GetGoalAmount() int64 {
+	if x != nil {
+		return x.GoalAmount
+	}
+	return 0
+}
+
+func (x *Pot) GetTargetDate() string {
+	if x != nil {
+		return x.TargetDate
+	}
+	return ""
+}
+
+func (x *Pot) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Pot) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type PotID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PotId         string                 `protobuf:"bytes,1,opt,name=pot_id,json=potId,proto3" json:"pot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PotID) Reset() {
+	*x = PotID{}
+	mi := &file_proto_pots_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PotID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PotID) ProtoMessage() {}
+
+func (x *PotID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PotID.ProtoReflect.Descriptor instead.
+func (*PotID) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PotID) GetPotId() string {
+	if x != nil {
+		return x.PotId
+	}
+	return ""
+}
+
+type CreatePotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	GoalAmount    int64                  `protobuf:"varint,3,opt,name=goal_amount,json=goalAmount,proto3" json:"goal_amount,omitempty"` // optional
+	TargetDate    string                 `protobuf:"bytes,4,opt,name=target_date,json=targetDate,proto3" json:"target_date,omitempty"`  // optional, YYYY-MM-DD
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePotRequest) Reset() {
+	*x = CreatePotRequest{}
+	mi := &file_proto_pots_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePotRequest) ProtoMessage() {}
+
+func (x *CreatePotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePotRequest.ProtoReflect.Descriptor instead.
+func (*CreatePotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreatePotRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CreatePotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreatePotRequest) GetGoalAmount() int64 {
+	if x != nil {
+		return x.GoalAmount
+	}
+	return 0
+}
+
+func (x *CreatePotRequest) GetTargetDate() string {
+	if x != nil {
+		return x.TargetDate
+	}
+	return ""
+}
+
+type ListPotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	IncludeClosed bool                   `protobuf:"varint,2,opt,name=include_closed,json=includeClosed,proto3" json:"include_closed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPotsRequest) Reset() {
+	*x = ListPotsRequest{}
+	mi := &file_proto_pots_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPotsRequest) ProtoMessage() {}
+
+func (x *ListPotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPotsRequest.ProtoReflect.Descriptor instead.
+func (*ListPotsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPotsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListPotsRequest) GetIncludeClosed() bool {
+	if x != nil {
+		return x.IncludeClosed
+	}
+	return false
+}
+
+type PotList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pots          []*Pot                 `protobuf:"bytes,1,rep,name=pots,proto3" json:"pots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PotList) Reset() {
+	*x = PotList{}
+	mi := &file_proto_pots_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PotList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PotList) ProtoMessage() {}
+
+func (x *PotList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PotList.ProtoReflect.Descriptor instead.
+func (*PotList) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PotList) GetPots() []*Pot {
+	if x != nil {
+		return x.Pots
+	}
+	return nil
+}
+
+type UpdatePotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PotId         string                 `protobuf:"bytes,1,opt,name=pot_id,json=potId,proto3" json:"pot_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                // renames the pot if set
+	GoalAmount    int64                  `protobuf:"varint,3,opt,name=goal_amount,json=goalAmount,proto3" json:"goal_amount,omitempty"` // replaces the goal if set
+	TargetDate    string                 `protobuf:"bytes,4,opt,name=target_date,json=targetDate,proto3" json:"target_date,omitempty"`  // replaces the target date if set, YYYY-MM-DD
+	ClearGoal     bool                   `protobuf:"varint,5,opt,name=clear_goal,json=clearGoal,proto3" json:"clear_goal,omitempty"`    // removes the goal and target date
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePotRequest) Reset() {
+	*x = UpdatePotRequest{}
+	mi := &file_proto_pots_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePotRequest) ProtoMessage() {}
+
+func (x *UpdatePotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePotRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdatePotRequest) GetPotId() string {
+	if x != nil {
+		return x.PotId
+	}
+	return ""
+}
+
+func (x *UpdatePotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdatePotRequest) GetGoalAmount() int64 {
+	if x != nil {
+		return x.GoalAmount
+	}
+	return 0
+}
+
+func (x *UpdatePotRequest) GetTargetDate() string {
+	if x != nil {
+		return x.TargetDate
+	}
+	return ""
+}
+
+func (x *UpdatePotRequest) GetClearGoal() bool {
+	if x != nil {
+		return x.ClearGoal
+	}
+	return false
+}
+
+type MoveRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PotId          string                 `protobuf:"bytes,1,opt,name=pot_id,json=potId,proto3" json:"pot_id,omitempty"`
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                      // in minor units of the pot's currency
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional; retries with the same key move the money once
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MoveRequest) Reset() {
+	*x = MoveRequest{}
+	mi := &file_proto_pots_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRequest) ProtoMessage() {}
+
+func (x *MoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRequest.ProtoReflect.Descriptor instead.
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MoveRequest) GetPotId() string {
+	if x != nil {
+		return x.PotId
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *MoveRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type MoveResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`        // reason if not successful
+	Pot            *Pot                   `protobuf:"bytes,3,opt,name=pot,proto3" json:"pot,omitempty"`                                              // the pot after the move
+	AccountBalance int64                  `protobuf:"varint,4,opt,name=account_balance,json=accountBalance,proto3" json:"account_balance,omitempty"` // available balance of the account after the move
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MoveResult) Reset() {
+	*x = MoveResult{}
+	mi := &file_proto_pots_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveResult) ProtoMessage() {}
+
+func (x *MoveResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pots_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveResult.ProtoReflect.Descriptor instead.
+func (*MoveResult) Descriptor() ([]byte, []int) {
+	return file_proto_pots_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MoveResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MoveResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *MoveResult) GetPot() *Pot {
+	if x != nil {
+		return x.Pot
+	}
+	return nil
+}
+
+func (x *MoveResult) GetAccountBalance() int64 {
+	if x != nil {
+		return x.AccountBalance
+	}
+	return 0
+}
+
+var File_proto_pots_proto protoreflect.FileDescriptor
+
+const file_proto_pots_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/pots.proto\"\xf7\x01\n" +
+	"\x03Pot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x18\n" +
+	"\abalance\x18\x05 \x01(\x03R\abalance\x12\x1f\n" +
+	"\vgoal_amount\x18\x06 \x01(\x03R\n" +
+	"goalAmount\x12\x1f\n" +
+	"\vtarget_date\x18\a \x01(\tR\n" +
+	"targetDate\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\x1e\n" +
+	"\x05PotID\x12\x15\n" +
+	"\x06pot_id\x18\x01 \x01(\tR\x05potId\"\x87\x01\n" +
+	"\x10CreatePotRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vgoal_amount\x18\x03 \x01(\x03R\n" +
+	"goalAmount\x12\x1f\n" +
+	"\vtarget_date\x18\x04 \x01(\tR\n" +
+	"targetDate\"W\n" +
+	"\x0fListPotsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12%\n" +
+	"\x0einclude_closed\x18\x02 \x01(\bR\rincludeClosed\"#\n" +
+	"\aPotList\x12\x18\n" +
+	"\x04pots\x18\x01 \x03(\v2\x04.PotR\x04pots\"\x9e\x01\n" +
+	"\x10UpdatePotRequest\x12\x15\n" +
+	"\x06pot_id\x18\x01 \x01(\tR\x05potId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vgoal_amount\x18\x03 \x01(\x03R\n" +
+	"goalAmount\x12\x1f\n" +
+	"\vtarget_date\x18\x04 \x01(\tR\n" +
+	"targetDate\x12\x1d\n" +
+	"\n" +
+	"clear_goal\x18\x05 \x01(\bR\tclearGoal\"e\n" +
+	"\vMoveRequest\x12\x15\n" +
+	"\x06pot_id\x18\x01 \x01(\tR\x05potId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\x8c\x01\n" +
+	"\n" +
+	"MoveResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12\x16\n" +
+	"\x03pot\x18\x03 \x01(\v2\x04.PotR\x03pot\x12'\n" +
+	"\x0faccount_balance\x18\x04 \x01(\x03R\x0eaccountBalance2\xf9\x01\n" +
+	"\x04Pots\x12$\n" +
+	"\tCreatePot\x12\x11.CreatePotRequest\x1a\x04.Pot\x12\x16\n" +
+	"\x06GetPot\x12\x06.PotID\x1a\x04.Pot\x12&\n" +
+	"\bListPots\x12\x10.ListPotsRequest\x1a\b.PotList\x12$\n" +
+	"\tUpdatePot\x12\x11.UpdatePotRequest\x1a\x04.Pot\x12\x18\n" +
+	"\bClosePot\x12\x06.PotID\x1a\x04.Pot\x12$\n" +
+	"\aDeposit\x12\f.MoveRequest\x1a\v.MoveResult\x12%\n" +
+	"\bWithdraw\x12\f.MoveRequest\x1a\v.MoveResultB\bZ\x06./potsb\x06proto3"
+
+var (
+	file_proto_pots_proto_rawDescOnce sync.Once
+	file_proto_pots_proto_rawDescData []byte
+)
+
+func file_proto_pots_proto_rawDescGZIP() []byte {
+	file_proto_pots_proto_rawDescOnce.Do(func() {
+		file_proto_pots_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_pots_proto_rawDesc), len(file_proto_pots_proto_rawDesc)))
+	})
+	return file_proto_pots_proto_rawDescData
+}
+
+var file_proto_pots_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_pots_proto_goTypes = []any{
+	(*Pot)(nil),              // 0: Pot
+	(*PotID)(nil),            // 1: PotID
+	(*CreatePotRequest)(nil), // 2: CreatePotRequest
+	(*ListPotsRequest)(nil),  // 3: ListPotsRequest
+	(*PotList)(nil),          // 4: PotList
+	(*UpdatePotRequest)(nil), // 5: UpdatePotRequest
+	(*MoveRequest)(nil),      // 6: MoveRequest
+	(*MoveResult)(nil),       // 7: MoveResult
+}
+var file_proto_pots_proto_depIdxs = []int32{
+	0, // 0: PotList.pots:type_name -> Pot
+	0, // 1: MoveResult.pot:type_name -> Pot
+	2, // 2: Pots.CreatePot:input_type -> CreatePotRequest
+	1, // 3: Pots.GetPot:input_type -> PotID
+	3, // 4: Pots.ListPots:input_type -> ListPotsRequest
+	5, // 5: Pots.UpdatePot:input_type -> UpdatePotRequest
+	1, // 6: Pots.ClosePot:input_type -> PotID
+	6, // 7: Pots.Deposit:input_type -> MoveRequest
+	6, // 8: Pots.Withdraw:input_type -> MoveRequest
+	0, // 9: Pots.CreatePot:output_type -> Pot
+	0, // 10: Pots.GetPot:output_type -> Pot
+	4, // 11: Pots.ListPots:output_type -> PotList
+	0, // 12: Pots.UpdatePot:output_type -> Pot
+	0, // 13: Pots.ClosePot:output_type -> Pot
+	7, // 14: Pots.Deposit:output_type -> MoveResult
+	7, // 15: Pots.Withdraw:output_type -> MoveResult
+	9, // [9:16] is the sub-list for method output_type
+	2, // [2:9] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_pots_proto_init() }
+func file_proto_pots_proto_init() {
+	if File_proto_pots_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_pots_proto_rawDesc), len(file_proto_pots_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_pots_proto_goTypes,
+		DependencyIndexes: file_proto_pots_proto_depIdxs,
+		MessageInfos:      file_proto_pots_proto_msgTypes,
+	}.Build()
+	File_proto_pots_proto = out.File
+	file_proto_pots_proto_goTypes = nil
+	file_proto_pots_proto_depIdxs = nil
+}
@@ -0,0 +1,355 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/pots.proto
+
+package pots
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Pots_CreatePot_FullMethodName = "/Pots/CreatePot"
+	Pots_GetPot_FullMethodName    = "/Pots/GetPot"
+	Pots_ListPots_FullMethodName  = "/Pots/ListPots"
+	Pots_UpdatePot_FullMethodName = "/Pots/UpdatePot"
+	Pots_ClosePot_FullMethodName  = "/Pots/ClosePot"
+	Pots_Deposit_FullMethodName   = "/Pots/Deposit"
+	Pots_Withdraw_FullMethodName  = "/Pots/Withdraw"
+)
+
+// PotsClient is the client API for Pots service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Pots are savings sub-accounts. Each pot is an account in the Balance ledger with the
+// pot's ID as its account ID, so money only moves in and out of it through ledger journals.
+type PotsClient interface {
+	CreatePot(ctx context.Context, in *CreatePotRequest, opts ...grpc.CallOption) (*Pot, error)
+	GetPot(ctx context.Context, in *PotID, opts ...grpc.CallOption) (*Pot, error)
+	ListPots(ctx context.Context, in *ListPotsRequest, opts ...grpc.CallOption) (*PotList, error)
+	UpdatePot(ctx context.Context, in *UpdatePotRequest, opts ...grpc.CallOption) (*Pot, error)
+	ClosePot(ctx context.Context, in *PotID, opts ...grpc.CallOption) (*Pot, error)
+	Deposit(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResult, error)
+	Withdraw(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResult, error)
+}
+
+type potsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPotsClient(cc grpc.ClientConnInterface) PotsClient {
+	return &potsClient{cc}
+}
+
+func (c *potsClient) CreatePot(ctx context.Context, in *CreatePotRequest, opts ...grpc.CallOption) (*Pot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pot)
+	err := c.cc.Invoke(ctx, Pots_CreatePot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *potsClient) GetPot(ctx context.Context, in *PotID, opts ...grpc.CallOption) (*Pot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pot)
+	err := c.cc.Invoke(ctx, Pots_GetPot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *potsClient) ListPots(ctx context.Context, in *ListPotsRequest, opts ...grpc.CallOption) (*PotList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PotList)
+	err := c.cc.Invoke(ctx, Pots_ListPots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *potsClient) UpdatePot(ctx context.Context, in *UpdatePotRequest, opts ...grpc.CallOption) (*Pot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pot)
+	err := c.cc.Invoke(ctx, Pots_UpdatePot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *potsClient) ClosePot(ctx context.Context, in *PotID, opts ...grpc.CallOption) (*Pot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pot)
+	err := c.cc.Invoke(ctx, Pots_ClosePot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *potsClient) Deposit(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveResult)
+	err := c.cc.Invoke(ctx, Pots_Deposit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *potsClient) Withdraw(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveResult)
+	err := c.cc.Invoke(ctx, Pots_Withdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PotsServer is the server API for Pots service.
+// All implementations must embed UnimplementedPotsServer
+// for forward compatibility.
+//
+// Pots are savings sub-accounts. Each pot is an account in the Balance ledger with the
+// pot's ID as its account ID, so money only moves in and out of it through ledger journals.
+type PotsServer interface {
+	CreatePot(context.Context, *CreatePotRequest) (*Pot, error)
+	GetPot(context.Context, *PotID) (*Pot, error)
+	ListPots(context.Context, *ListPotsRequest) (*PotList, error)
+	UpdatePot(context.Context, *UpdatePotRequest) (*Pot, error)
+	ClosePot(context.Context, *PotID) (*Pot, error)
+	Deposit(context.Context, *MoveRequest) (*MoveResult, error)
+	Withdraw(context.Context, *MoveRequest) (*MoveResult, error)
+	mustEmbedUnimplementedPotsServer()
+}
+
+// UnimplementedPotsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPotsServer struct{}
+
+func (UnimplementedPotsServer) CreatePot(context.Context, *CreatePotRequest) (*Pot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePot not implemented")
+}
+func (UnimplementedPotsServer) GetPot(context.Context, *PotID) (*Pot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPot not implemented")
+}
+func (UnimplementedPotsServer) ListPots(context.Context, *ListPotsRequest) (*PotList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPots not implemented")
+}
+func (UnimplementedPotsServer) UpdatePot(context.Context, *UpdatePotRequest) (*Pot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePot not implemented")
+}
+func (UnimplementedPotsServer) ClosePot(context.Context, *PotID) (*Pot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClosePot not implemented")
+}
+func (UnimplementedPotsServer) Deposit(context.Context, *MoveRequest) (*MoveResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedPotsServer) Withdraw(context.Context, *MoveRequest) (*MoveResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedPotsServer) mustEmbedUnimplementedPotsServer() {}
+func (UnimplementedPotsServer) testEmbeddedByValue()              {}
+
+// UnsafePotsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PotsServer will
+// result in compilation errors.
+type UnsafePotsServer interface {
+	mustEmbedUnimplementedPotsServer()
+}
+
+func RegisterPotsServer(s grpc.ServiceRegistrar, srv PotsServer) {
+	// If the following call pancis, it indicates UnimplementedPotsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Pots_ServiceDesc, srv)
+}
+
+func _Pots_CreatePot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).CreatePot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_CreatePot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).CreatePot(ctx, req.(*CreatePotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pots_GetPot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PotID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).GetPot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_GetPot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).GetPot(ctx, req.(*PotID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pots_ListPots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).ListPots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_ListPots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).ListPots(ctx, req.(*ListPotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pots_UpdatePot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).UpdatePot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_UpdatePot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).UpdatePot(ctx, req.(*UpdatePotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pots_ClosePot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PotID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).ClosePot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_ClosePot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).ClosePot(ctx, req.(*PotID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pots_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_Deposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).Deposit(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pots_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PotsServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pots_Withdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PotsServer).Withdraw(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Pots_ServiceDesc is the grpc.ServiceDesc for Pots service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Pots_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Pots",
+	HandlerType: (*PotsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePot",
+			Handler:    _Pots_CreatePot_Handler,
+		},
+		{
+			MethodName: "GetPot",
+			Handler:    _Pots_GetPot_Handler,
+		},
+		{
+			MethodName: "ListPots",
+			Handler:    _Pots_ListPots_Handler,
+		},
+		{
+			MethodName: "UpdatePot",
+			Handler:    _Pots_UpdatePot_Handler,
+		},
+		{
+			MethodName: "ClosePot",
+			Handler:    _Pots_ClosePot_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _Pots_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _Pots_Withdraw_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/pots.proto",
+}
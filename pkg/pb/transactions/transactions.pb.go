@@ -22,20 +22,28 @@ const (
 )
 
 type Transaction struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	CardId        string                 `protobuf:"bytes,3,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // optional
-	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`              // amount in cents
-	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
-	MerchantId    string                 `protobuf:"bytes,6,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`       // optional
-	MerchantName  string                 `protobuf:"bytes,7,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"` // optional
-	Timestamp     string                 `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                           // ISO 8601 string or similar
-	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`                                 // e.g., "AUTHORIZED", "SETTLED", "REVERSED"
-	MerchantRaw   string                 `protobuf:"bytes,10,opt,name=merchant_raw,json=merchantRaw,proto3" json:"merchant_raw,omitempty"`   // raw merchant description
-	Category      string                 `protobuf:"bytes,11,opt,name=category,proto3" json:"category,omitempty"`                            // optional category
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId             string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	CardId                string                 `protobuf:"bytes,3,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // optional
+	Amount                int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`              // amount in cents
+	Currency              string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	MerchantId            string                 `protobuf:"bytes,6,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`                                     // optional
+	MerchantName          string                 `protobuf:"bytes,7,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"`                               // optional
+	Timestamp             string                 `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                         // ISO 8601 string or similar
+	Status                string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`                                                               // e.g., "AUTHORIZED", "SETTLED", "REVERSED"
+	MerchantRaw           string                 `protobuf:"bytes,10,opt,name=merchant_raw,json=merchantRaw,proto3" json:"merchant_raw,omitempty"`                                 // raw merchant description
+	Category              string                 `protobuf:"bytes,11,opt,name=category,proto3" json:"category,omitempty"`                                                          // optional category
+	HoldId                string                 `protobuf:"bytes,12,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`                                                // optional balance hold backing a card authorization
+	BillingCurrency       string                 `protobuf:"bytes,13,opt,name=billing_currency,json=billingCurrency,proto3" json:"billing_currency,omitempty"`                     // currency the account was debited in, when converted from currency
+	BillingAmount         int64                  `protobuf:"varint,14,opt,name=billing_amount,json=billingAmount,proto3" json:"billing_amount,omitempty"`                          // amount converted into billing_currency, excluding fx_fee
+	FxRate                string                 `protobuf:"bytes,15,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"`                                                // exchange rate applied, units of billing_currency per unit of currency
+	FxFee                 int64                  `protobuf:"varint,16,opt,name=fx_fee,json=fxFee,proto3" json:"fx_fee,omitempty"`                                                  // conversion fee in minor units of billing_currency
+	AuthCode              string                 `protobuf:"bytes,17,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"`                                          // authorization code given to the card network when the authorization was approved
+	OriginalTransactionId string                 `protobuf:"bytes,18,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"` // set on a refund, the transaction it refunds
+	RefundedAmount        int64                  `protobuf:"varint,19,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"`                       // total refunded against this transaction so far
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *Transaction) Reset() {
@@ -145,17 +153,76 @@ func (x *Transaction) GetCategory() string {
 	return ""
 }
 
+func (x *Transaction) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *Transaction) GetBillingCurrency() string {
+	if x != nil {
+		return x.BillingCurrency
+	}
+	return ""
+}
+
+func (x *Transaction) GetBillingAmount() int64 {
+	if x != nil {
+		return x.BillingAmount
+	}
+	return 0
+}
+
+func (x *Transaction) GetFxRate() string {
+	if x != nil {
+		return x.FxRate
+	}
+	return ""
+}
+
+func (x *Transaction) GetFxFee() int64 {
+	if x != nil {
+		return x.FxFee
+	}
+	return 0
+}
+
+func (x *Transaction) GetAuthCode() string {
+	if x != nil {
+		return x.AuthCode
+	}
+	return ""
+}
+
+func (x *Transaction) GetOriginalTransactionId() string {
+	if x != nil {
+		return x.OriginalTransactionId
+	}
+	return ""
+}
+
+func (x *Transaction) GetRefundedAmount() int64 {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return 0
+}
+
 type TransactionInput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	CardId        string                 `protobuf:"bytes,2,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // optional
-	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`              // amount in cents
-	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
-	MerchantId    string                 `protobuf:"bytes,5,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`    // optional
-	MerchantRaw   string                 `protobuf:"bytes,6,opt,name=merchant_raw,json=merchantRaw,proto3" json:"merchant_raw,omitempty"` // raw merchant description
-	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                              // initial status, e.g., "AUTHORIZED"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	AccountId             string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	CardId                string                 `protobuf:"bytes,2,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"` // optional
+	Amount                int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`              // amount in cents
+	Currency              string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	MerchantId            string                 `protobuf:"bytes,5,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`                                     // optional
+	MerchantRaw           string                 `protobuf:"bytes,6,opt,name=merchant_raw,json=merchantRaw,proto3" json:"merchant_raw,omitempty"`                                  // raw merchant description
+	Status                string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                                                               // initial status, e.g., "AUTHORIZED"
+	HoldId                string                 `protobuf:"bytes,8,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`                                                 // optional balance hold backing a card authorization
+	IdempotencyKey        string                 `protobuf:"bytes,9,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`                         // optional, a retry with the same key returns the original transaction
+	OriginalTransactionId string                 `protobuf:"bytes,10,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"` // optional, records a refund of this transaction; amount must be negative
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *TransactionInput) Reset() {
@@ -237,6 +304,27 @@ func (x *TransactionInput) GetStatus() string {
 	return ""
 }
 
+func (x *TransactionInput) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *TransactionInput) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *TransactionInput) GetOriginalTransactionId() string {
+	if x != nil {
+		return x.OriginalTransactionId
+	}
+	return ""
+}
+
 type TransactionQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // query by transaction ID
@@ -281,6 +369,58 @@ func (x *TransactionQuery) GetId() string {
 	return ""
 }
 
+type AuthCodeQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	AuthCode      string                 `protobuf:"bytes,2,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthCodeQuery) Reset() {
+	*x = AuthCodeQuery{}
+	mi := &file_proto_transactions_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthCodeQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthCodeQuery) ProtoMessage() {}
+
+func (x *AuthCodeQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transactions_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthCodeQuery.ProtoReflect.Descriptor instead.
+func (*AuthCodeQuery) Descriptor() ([]byte, []int) {
+	return file_proto_transactions_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AuthCodeQuery) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *AuthCodeQuery) GetAuthCode() string {
+	if x != nil {
+		return x.AuthCode
+	}
+	return ""
+}
+
 type TransactionsQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // query by account ID
@@ -292,7 +432,7 @@ type TransactionsQuery struct {
 
 func (x *TransactionsQuery) Reset() {
 	*x = TransactionsQuery{}
-	mi := &file_proto_transactions_proto_msgTypes[3]
+	mi := &file_proto_transactions_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -304,7 +444,7 @@ func (x *TransactionsQuery) String() string {
 func (*TransactionsQuery) ProtoMessage() {}
 
 func (x *TransactionsQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_transactions_proto_msgTypes[3]
+	mi := &file_proto_transactions_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -317,7 +457,7 @@ func (x *TransactionsQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionsQuery.ProtoReflect.Descriptor instead.
 func (*TransactionsQuery) Descriptor() ([]byte, []int) {
-	return file_proto_transactions_proto_rawDescGZIP(), []int{3}
+	return file_proto_transactions_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *TransactionsQuery) GetAccountId() string {
@@ -350,7 +490,7 @@ type TransactionsList struct {
 
 func (x *TransactionsList) Reset() {
 	*x = TransactionsList{}
-	mi := &file_proto_transactions_proto_msgTypes[4]
+	mi := &file_proto_transactions_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -362,7 +502,7 @@ func (x *TransactionsList) String() string {
 func (*TransactionsList) ProtoMessage() {}
 
 func (x *TransactionsList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_transactions_proto_msgTypes[4]
+	mi := &file_proto_transactions_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,7 +515,7 @@ func (x *TransactionsList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionsList.ProtoReflect.Descriptor instead.
 func (*TransactionsList) Descriptor() ([]byte, []int) {
-	return file_proto_transactions_proto_rawDescGZIP(), []int{4}
+	return file_proto_transactions_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *TransactionsList) GetItems() []*Transaction {
@@ -386,19 +526,26 @@ func (x *TransactionsList) GetItems() []*Transaction {
 }
 
 type UpdateTransactionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                         // transaction ID to update
-	MerchantId    string                 `protobuf:"bytes,2,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`       // optional new merchant ID
-	MerchantName  string                 `protobuf:"bytes,3,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"` // optional new merchant name
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`                             // optional new category
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                                 // optional new status
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                  // transaction ID to update
+	MerchantId      string                 `protobuf:"bytes,2,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`                // optional new merchant ID
+	MerchantName    string                 `protobuf:"bytes,3,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"`          // optional new merchant name
+	Category        string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`                                      // optional new category
+	Status          string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                                          // optional new status
+	HoldId          string                 `protobuf:"bytes,6,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`                            // optional balance hold backing the transaction
+	BillingCurrency string                 `protobuf:"bytes,7,opt,name=billing_currency,json=billingCurrency,proto3" json:"billing_currency,omitempty"` // optional, set with the fields below when the debit was converted
+	BillingAmount   int64                  `protobuf:"varint,8,opt,name=billing_amount,json=billingAmount,proto3" json:"billing_amount,omitempty"`
+	FxRate          string                 `protobuf:"bytes,9,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"`
+	FxFee           int64                  `protobuf:"varint,10,opt,name=fx_fee,json=fxFee,proto3" json:"fx_fee,omitempty"`
+	AuthCode        string                 `protobuf:"bytes,11,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"` // optional authorization code, set when a card authorization is approved
+	Amount          int64                  `protobuf:"varint,12,opt,name=amount,proto3" json:"amount,omitempty"`                    // optional settled amount, when it differs from the authorized amount
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateTransactionRequest) Reset() {
 	*x = UpdateTransactionRequest{}
-	mi := &file_proto_transactions_proto_msgTypes[5]
+	mi := &file_proto_transactions_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -410,7 +557,7 @@ func (x *UpdateTransactionRequest) String() string {
 func (*UpdateTransactionRequest) ProtoMessage() {}
 
 func (x *UpdateTransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_transactions_proto_msgTypes[5]
+	mi := &file_proto_transactions_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -423,7 +570,7 @@ func (x *UpdateTransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTransactionRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTransactionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_transactions_proto_rawDescGZIP(), []int{5}
+	return file_proto_transactions_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateTransactionRequest) GetId() string {
@@ -461,11 +608,60 @@ func (x *UpdateTransactionRequest) GetStatus() string {
 	return ""
 }
 
+func (x *UpdateTransactionRequest) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetBillingCurrency() string {
+	if x != nil {
+		return x.BillingCurrency
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetBillingAmount() int64 {
+	if x != nil {
+		return x.BillingAmount
+	}
+	return 0
+}
+
+func (x *UpdateTransactionRequest) GetFxRate() string {
+	if x != nil {
+		return x.FxRate
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetFxFee() int64 {
+	if x != nil {
+		return x.FxFee
+	}
+	return 0
+}
+
+func (x *UpdateTransactionRequest) GetAuthCode() string {
+	if x != nil {
+		return x.AuthCode
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
 var File_proto_transactions_proto protoreflect.FileDescriptor
 
 const file_proto_transactions_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/transactions.proto\"\xc4\x02\n" +
+	"\x18proto/transactions.proto\"\xdd\x04\n" +
 	"\vTransaction\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -480,7 +676,15 @@ const file_proto_transactions_proto_rawDesc = "" +
 	"\x06status\x18\t \x01(\tR\x06status\x12!\n" +
 	"\fmerchant_raw\x18\n" +
 	" \x01(\tR\vmerchantRaw\x12\x1a\n" +
-	"\bcategory\x18\v \x01(\tR\bcategory\"\xda\x01\n" +
+	"\bcategory\x18\v \x01(\tR\bcategory\x12\x17\n" +
+	"\ahold_id\x18\f \x01(\tR\x06holdId\x12)\n" +
+	"\x10billing_currency\x18\r \x01(\tR\x0fbillingCurrency\x12%\n" +
+	"\x0ebilling_amount\x18\x0e \x01(\x03R\rbillingAmount\x12\x17\n" +
+	"\afx_rate\x18\x0f \x01(\tR\x06fxRate\x12\x15\n" +
+	"\x06fx_fee\x18\x10 \x01(\x03R\x05fxFee\x12\x1b\n" +
+	"\tauth_code\x18\x11 \x01(\tR\bauthCode\x126\n" +
+	"\x17original_transaction_id\x18\x12 \x01(\tR\x15originalTransactionId\x12'\n" +
+	"\x0frefunded_amount\x18\x13 \x01(\x03R\x0erefundedAmount\"\xd4\x02\n" +
 	"\x10TransactionInput\x12\x1d\n" +
 	"\n" +
 	"account_id\x18\x01 \x01(\tR\taccountId\x12\x17\n" +
@@ -490,28 +694,44 @@ const file_proto_transactions_proto_rawDesc = "" +
 	"\vmerchant_id\x18\x05 \x01(\tR\n" +
 	"merchantId\x12!\n" +
 	"\fmerchant_raw\x18\x06 \x01(\tR\vmerchantRaw\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\"\"\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x17\n" +
+	"\ahold_id\x18\b \x01(\tR\x06holdId\x12'\n" +
+	"\x0fidempotency_key\x18\t \x01(\tR\x0eidempotencyKey\x126\n" +
+	"\x17original_transaction_id\x18\n" +
+	" \x01(\tR\x15originalTransactionId\"\"\n" +
 	"\x10TransactionQuery\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"E\n" +
+	"\rAuthCodeQuery\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tauth_code\x18\x02 \x01(\tR\bauthCode\"e\n" +
 	"\x11TransactionsQuery\x12\x1d\n" +
 	"\n" +
 	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\rR\x05limit\x12\x1b\n" +
 	"\tbefore_id\x18\x03 \x01(\tR\bbeforeId\"6\n" +
 	"\x10TransactionsList\x12\"\n" +
-	"\x05items\x18\x01 \x03(\v2\f.TransactionR\x05items\"\xa4\x01\n" +
+	"\x05items\x18\x01 \x03(\v2\f.TransactionR\x05items\"\xf4\x02\n" +
 	"\x18UpdateTransactionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vmerchant_id\x18\x02 \x01(\tR\n" +
 	"merchantId\x12#\n" +
 	"\rmerchant_name\x18\x03 \x01(\tR\fmerchantName\x12\x1a\n" +
 	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status2\xf0\x01\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x17\n" +
+	"\ahold_id\x18\x06 \x01(\tR\x06holdId\x12)\n" +
+	"\x10billing_currency\x18\a \x01(\tR\x0fbillingCurrency\x12%\n" +
+	"\x0ebilling_amount\x18\b \x01(\x03R\rbillingAmount\x12\x17\n" +
+	"\afx_rate\x18\t \x01(\tR\x06fxRate\x12\x15\n" +
+	"\x06fx_fee\x18\n" +
+	" \x01(\x03R\x05fxFee\x12\x1b\n" +
+	"\tauth_code\x18\v \x01(\tR\bauthCode\x12\x16\n" +
+	"\x06amount\x18\f \x01(\x03R\x06amount2\xaa\x02\n" +
 	"\fTransactions\x124\n" +
 	"\x11RecordTransaction\x12\x11.TransactionInput\x1a\f.Transaction\x121\n" +
 	"\x0eGetTransaction\x12\x11.TransactionQuery\x1a\f.Transaction\x129\n" +
 	"\x10ListTransactions\x12\x12.TransactionsQuery\x1a\x11.TransactionsList\x12<\n" +
-	"\x11UpdateTransaction\x12\x19.UpdateTransactionRequest\x1a\f.TransactionB\x10Z\x0e./transactionsb\x06proto3"
+	"\x11UpdateTransaction\x12\x19.UpdateTransactionRequest\x1a\f.Transaction\x128\n" +
+	"\x18GetTransactionByAuthCode\x12\x0e.AuthCodeQuery\x1a\f.TransactionB\x10Z\x0e./transactionsb\x06proto3"
 
 var (
 	file_proto_transactions_proto_rawDescOnce sync.Once
@@ -525,27 +745,30 @@ func file_proto_transactions_proto_rawDescGZIP() []byte {
 	return file_proto_transactions_proto_rawDescData
 }
 
-var file_proto_transactions_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_transactions_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_transactions_proto_goTypes = []any{
 	(*Transaction)(nil),              // 0: Transaction
 	(*TransactionInput)(nil),         // 1: TransactionInput
 	(*TransactionQuery)(nil),         // 2: TransactionQuery
-	(*TransactionsQuery)(nil),        // 3: TransactionsQuery
-	(*TransactionsList)(nil),         // 4: TransactionsList
-	(*UpdateTransactionRequest)(nil), // 5: UpdateTransactionRequest
+	(*AuthCodeQuery)(nil),            // 3: AuthCodeQuery
+	(*TransactionsQuery)(nil),        // 4: TransactionsQuery
+	(*TransactionsList)(nil),         // 5: TransactionsList
+	(*UpdateTransactionRequest)(nil), // 6: UpdateTransactionRequest
 }
 var file_proto_transactions_proto_depIdxs = []int32{
 	0, // 0: TransactionsList.items:type_name -> Transaction
 	1, // 1: Transactions.RecordTransaction:input_type -> TransactionInput
 	2, // 2: Transactions.GetTransaction:input_type -> TransactionQuery
-	3, // 3: Transactions.ListTransactions:input_type -> TransactionsQuery
-	5, // 4: Transactions.UpdateTransaction:input_type -> UpdateTransactionRequest
-	0, // 5: Transactions.RecordTransaction:output_type -> Transaction
-	0, // 6: Transactions.GetTransaction:output_type -> Transaction
-	4, // 7: Transactions.ListTransactions:output_type -> TransactionsList
-	0, // 8: Transactions.UpdateTransaction:output_type -> Transaction
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
+	4, // 3: Transactions.ListTransactions:input_type -> TransactionsQuery
+	6, // 4: Transactions.UpdateTransaction:input_type -> UpdateTransactionRequest
+	3, // 5: Transactions.GetTransactionByAuthCode:input_type -> AuthCodeQuery
+	0, // 6: Transactions.RecordTransaction:output_type -> Transaction
+	0, // 7: Transactions.GetTransaction:output_type -> Transaction
+	5, // 8: Transactions.ListTransactions:output_type -> TransactionsList
+	0, // 9: Transactions.UpdateTransaction:output_type -> Transaction
+	0, // 10: Transactions.GetTransactionByAuthCode:output_type -> Transaction
+	6, // [6:11] is the sub-list for method output_type
+	1, // [1:6] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
 	1, // [1:1] is the sub-list for extension extendee
 	0, // [0:1] is the sub-list for field type_name
@@ -562,7 +785,7 @@ func file_proto_transactions_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_transactions_proto_rawDesc), len(file_proto_transactions_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -131,6 +131,30 @@ func local_request_Transactions_UpdateTransaction_0(ctx context.Context, marshal
 	return msg, metadata, err
 }
 
+func request_Transactions_GetTransactionByAuthCode_0(ctx context.Context, marshaler runtime.Marshaler, client TransactionsClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq AuthCodeQuery
+		metadata runtime.ServerMetadata
+	)
+	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && !errors.Is(err, io.EOF) {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	msg, err := client.GetTransactionByAuthCode(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	return msg, metadata, err
+}
+
+func local_request_Transactions_GetTransactionByAuthCode_0(ctx context.Context, marshaler runtime.Marshaler, server TransactionsServer, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq AuthCodeQuery
+		metadata runtime.ServerMetadata
+	)
+	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && !errors.Is(err, io.EOF) {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	msg, err := server.GetTransactionByAuthCode(ctx, &protoReq)
+	return msg, metadata, err
+}
+
 // RegisterTransactionsHandlerServer registers the http handlers for service Transactions to "mux".
 // UnaryRPC     :call TransactionsServer directly.
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
@@ -217,6 +241,26 @@ func RegisterTransactionsHandlerServer(ctx context.Context, mux *runtime.ServeMu
 		}
 		forward_Transactions_UpdateTransaction_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodPost, pattern_Transactions_GetTransactionByAuthCode_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var stream runtime.ServerTransportStream
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &stream)
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateIncomingContext(ctx, mux, req, "/.Transactions/GetTransactionByAuthCode", runtime.WithHTTPPathPattern("/Transactions/GetTransactionByAuthCode"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := local_request_Transactions_GetTransactionByAuthCode_0(annotatedContext, inboundMarshaler, server, req, pathParams)
+		md.HeaderMD, md.TrailerMD = metadata.Join(md.HeaderMD, stream.Header()), metadata.Join(md.TrailerMD, stream.Trailer())
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_Transactions_GetTransactionByAuthCode_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
 
 	return nil
 }
@@ -325,19 +369,38 @@ func RegisterTransactionsHandlerClient(ctx context.Context, mux *runtime.ServeMu
 		}
 		forward_Transactions_UpdateTransaction_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodPost, pattern_Transactions_GetTransactionByAuthCode_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/.Transactions/GetTransactionByAuthCode", runtime.WithHTTPPathPattern("/Transactions/GetTransactionByAuthCode"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := request_Transactions_GetTransactionByAuthCode_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_Transactions_GetTransactionByAuthCode_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
 	return nil
 }
 
 var (
-	pattern_Transactions_RecordTransaction_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "RecordTransaction"}, ""))
-	pattern_Transactions_GetTransaction_0    = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "GetTransaction"}, ""))
-	pattern_Transactions_ListTransactions_0  = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "ListTransactions"}, ""))
-	pattern_Transactions_UpdateTransaction_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "UpdateTransaction"}, ""))
+	pattern_Transactions_RecordTransaction_0        = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "RecordTransaction"}, ""))
+	pattern_Transactions_GetTransaction_0           = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "GetTransaction"}, ""))
+	pattern_Transactions_ListTransactions_0         = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "ListTransactions"}, ""))
+	pattern_Transactions_UpdateTransaction_0        = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "UpdateTransaction"}, ""))
+	pattern_Transactions_GetTransactionByAuthCode_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"Transactions", "GetTransactionByAuthCode"}, ""))
 )
 
 var (
-	forward_Transactions_RecordTransaction_0 = runtime.ForwardResponseMessage
-	forward_Transactions_GetTransaction_0    = runtime.ForwardResponseMessage
-	forward_Transactions_ListTransactions_0  = runtime.ForwardResponseMessage
-	forward_Transactions_UpdateTransaction_0 = runtime.ForwardResponseMessage
+	forward_Transactions_RecordTransaction_0        = runtime.ForwardResponseMessage
+	forward_Transactions_GetTransaction_0           = runtime.ForwardResponseMessage
+	forward_Transactions_ListTransactions_0         = runtime.ForwardResponseMessage
+	forward_Transactions_UpdateTransaction_0        = runtime.ForwardResponseMessage
+	forward_Transactions_GetTransactionByAuthCode_0 = runtime.ForwardResponseMessage
 )
@@ -19,10 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Transactions_RecordTransaction_FullMethodName = "/Transactions/RecordTransaction"
-	Transactions_GetTransaction_FullMethodName    = "/Transactions/GetTransaction"
-	Transactions_ListTransactions_FullMethodName  = "/Transactions/ListTransactions"
-	Transactions_UpdateTransaction_FullMethodName = "/Transactions/UpdateTransaction"
+	Transactions_RecordTransaction_FullMethodName        = "/Transactions/RecordTransaction"
+	Transactions_GetTransaction_FullMethodName           = "/Transactions/GetTransaction"
+	Transactions_ListTransactions_FullMethodName         = "/Transactions/ListTransactions"
+	Transactions_UpdateTransaction_FullMethodName        = "/Transactions/UpdateTransaction"
+	Transactions_GetTransactionByAuthCode_FullMethodName = "/Transactions/GetTransactionByAuthCode"
 )
 
 // TransactionsClient is the client API for Transactions service.
@@ -33,6 +34,7 @@ type TransactionsClient interface {
 	GetTransaction(ctx context.Context, in *TransactionQuery, opts ...grpc.CallOption) (*Transaction, error)
 	ListTransactions(ctx context.Context, in *TransactionsQuery, opts ...grpc.CallOption) (*TransactionsList, error)
 	UpdateTransaction(ctx context.Context, in *UpdateTransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	GetTransactionByAuthCode(ctx context.Context, in *AuthCodeQuery, opts ...grpc.CallOption) (*Transaction, error)
 }
 
 type transactionsClient struct {
@@ -83,6 +85,16 @@ func (c *transactionsClient) UpdateTransaction(ctx context.Context, in *UpdateTr
 	return out, nil
 }
 
+func (c *transactionsClient) GetTransactionByAuthCode(ctx context.Context, in *AuthCodeQuery, opts ...grpc.CallOption) (*Transaction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, Transactions_GetTransactionByAuthCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TransactionsServer is the server API for Transactions service.
 // All implementations must embed UnimplementedTransactionsServer
 // for forward compatibility.
@@ -91,6 +103,7 @@ type TransactionsServer interface {
 	GetTransaction(context.Context, *TransactionQuery) (*Transaction, error)
 	ListTransactions(context.Context, *TransactionsQuery) (*TransactionsList, error)
 	UpdateTransaction(context.Context, *UpdateTransactionRequest) (*Transaction, error)
+	GetTransactionByAuthCode(context.Context, *AuthCodeQuery) (*Transaction, error)
 	mustEmbedUnimplementedTransactionsServer()
 }
 
@@ -113,6 +126,9 @@ func (UnimplementedTransactionsServer) ListTransactions(context.Context, *Transa
 func (UnimplementedTransactionsServer) UpdateTransaction(context.Context, *UpdateTransactionRequest) (*Transaction, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateTransaction not implemented")
 }
+func (UnimplementedTransactionsServer) GetTransactionByAuthCode(context.Context, *AuthCodeQuery) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionByAuthCode not implemented")
+}
 func (UnimplementedTransactionsServer) mustEmbedUnimplementedTransactionsServer() {}
 func (UnimplementedTransactionsServer) testEmbeddedByValue()                      {}
 
@@ -206,6 +222,24 @@ func _Transactions_UpdateTransaction_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Transactions_GetTransactionByAuthCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthCodeQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionsServer).GetTransactionByAuthCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Transactions_GetTransactionByAuthCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionsServer).GetTransactionByAuthCode(ctx, req.(*AuthCodeQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Transactions_ServiceDesc is the grpc.ServiceDesc for Transactions service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +263,10 @@ var Transactions_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateTransaction",
 			Handler:    _Transactions_UpdateTransaction_Handler,
 		},
+		{
+			MethodName: "GetTransactionByAuthCode",
+			Handler:    _Transactions_GetTransactionByAuthCode_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/transactions.proto",
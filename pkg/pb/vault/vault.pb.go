@@ -0,0 +1,795 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/vault.proto
+
+package vault
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type IssueCardNumberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bin           string                 `protobuf:"bytes,1,opt,name=bin,proto3" json:"bin,omitempty"`                               // BIN the card number starts with
+	PanLength     int32                  `protobuf:"varint,2,opt,name=pan_length,json=panLength,proto3" json:"pan_length,omitempty"` // digits in the card number
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueCardNumberRequest) Reset() {
+	*x = IssueCardNumberRequest{}
+	mi := &file_proto_vault_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCardNumberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCardNumberRequest) ProtoMessage() {}
+
+func (x *IssueCardNumberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCardNumberRequest.ProtoReflect.Descriptor instead.
+func (*IssueCardNumberRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IssueCardNumberRequest) GetBin() string {
+	if x != nil {
+		return x.Bin
+	}
+	return ""
+}
+
+func (x *IssueCardNumberRequest) GetPanLength() int32 {
+	if x != nil {
+		return x.PanLength
+	}
+	return 0
+}
+
+// IssuedCardNumber refers to the number and CVV of a new card, which never leave the vault
+type IssuedCardNumber struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	LastFour      string                 `protobuf:"bytes,2,opt,name=last_four,json=lastFour,proto3" json:"last_four,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssuedCardNumber) Reset() {
+	*x = IssuedCardNumber{}
+	mi := &file_proto_vault_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssuedCardNumber) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssuedCardNumber) ProtoMessage() {}
+
+func (x *IssuedCardNumber) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssuedCardNumber.ProtoReflect.Descriptor instead.
+func (*IssuedCardNumber) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IssuedCardNumber) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *IssuedCardNumber) GetLastFour() string {
+	if x != nil {
+		return x.LastFour
+	}
+	return ""
+}
+
+type TokenizeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pan           string                 `protobuf:"bytes,1,opt,name=pan,proto3" json:"pan,omitempty"`
+	Cvv           string                 `protobuf:"bytes,2,opt,name=cvv,proto3" json:"cvv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenizeRequest) Reset() {
+	*x = TokenizeRequest{}
+	mi := &file_proto_vault_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenizeRequest) ProtoMessage() {}
+
+func (x *TokenizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenizeRequest.ProtoReflect.Descriptor instead.
+func (*TokenizeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TokenizeRequest) GetPan() string {
+	if x != nil {
+		return x.Pan
+	}
+	return ""
+}
+
+func (x *TokenizeRequest) GetCvv() string {
+	if x != nil {
+		return x.Cvv
+	}
+	return ""
+}
+
+type LookupTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pan           string                 `protobuf:"bytes,1,opt,name=pan,proto3" json:"pan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupTokenRequest) Reset() {
+	*x = LookupTokenRequest{}
+	mi := &file_proto_vault_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupTokenRequest) ProtoMessage() {}
+
+func (x *LookupTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupTokenRequest.ProtoReflect.Descriptor instead.
+func (*LookupTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LookupTokenRequest) GetPan() string {
+	if x != nil {
+		return x.Pan
+	}
+	return ""
+}
+
+type Token struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_proto_vault_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Token) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type DetokenizeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Purpose       string                 `protobuf:"bytes,3,opt,name=purpose,proto3" json:"purpose,omitempty"` // why the details are needed, e.g. "emboss"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetokenizeRequest) Reset() {
+	*x = DetokenizeRequest{}
+	mi := &file_proto_vault_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetokenizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetokenizeRequest) ProtoMessage() {}
+
+func (x *DetokenizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetokenizeRequest.ProtoReflect.Descriptor instead.
+func (*DetokenizeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DetokenizeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DetokenizeRequest) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+type CardData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pan           string                 `protobuf:"bytes,1,opt,name=pan,proto3" json:"pan,omitempty"`                           // only for callers granted the card number
+	Cvv           string                 `protobuf:"bytes,2,opt,name=cvv,proto3" json:"cvv,omitempty"`                           // only for callers granted the CVV
+	PinBlock      string                 `protobuf:"bytes,3,opt,name=pin_block,json=pinBlock,proto3" json:"pin_block,omitempty"` // ISO 9564 format 1, only for callers granted the PIN
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardData) Reset() {
+	*x = CardData{}
+	mi := &file_proto_vault_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardData) ProtoMessage() {}
+
+func (x *CardData) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardData.ProtoReflect.Descriptor instead.
+func (*CardData) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CardData) GetPan() string {
+	if x != nil {
+		return x.Pan
+	}
+	return ""
+}
+
+func (x *CardData) GetCvv() string {
+	if x != nil {
+		return x.Cvv
+	}
+	return ""
+}
+
+func (x *CardData) GetPinBlock() string {
+	if x != nil {
+		return x.PinBlock
+	}
+	return ""
+}
+
+type RotateKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchSize     int32                  `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"` // entries to re-encrypt, 0 for the default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeysRequest) Reset() {
+	*x = RotateKeysRequest{}
+	mi := &file_proto_vault_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeysRequest) ProtoMessage() {}
+
+func (x *RotateKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeysRequest.ProtoReflect.Descriptor instead.
+func (*RotateKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RotateKeysRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+type RotateKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyVersion    int32                  `protobuf:"varint,1,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"` // key the entries were re-encrypted under
+	Reencrypted   int32                  `protobuf:"varint,2,opt,name=reencrypted,proto3" json:"reencrypted,omitempty"`
+	Remaining     int64                  `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"` // entries still under older keys
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeysResponse) Reset() {
+	*x = RotateKeysResponse{}
+	mi := &file_proto_vault_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeysResponse) ProtoMessage() {}
+
+func (x *RotateKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeysResponse.ProtoReflect.Descriptor instead.
+func (*RotateKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RotateKeysResponse) GetKeyVersion() int32 {
+	if x != nil {
+		return x.KeyVersion
+	}
+	return 0
+}
+
+func (x *RotateKeysResponse) GetReencrypted() int32 {
+	if x != nil {
+		return x.Reencrypted
+	}
+	return 0
+}
+
+func (x *RotateKeysResponse) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+type SetPinBlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	PinBlock      string                 `protobuf:"bytes,2,opt,name=pin_block,json=pinBlock,proto3" json:"pin_block,omitempty"` // ISO 9564 format 1, see pkg/pinblock
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPinBlockRequest) Reset() {
+	*x = SetPinBlockRequest{}
+	mi := &file_proto_vault_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPinBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPinBlockRequest) ProtoMessage() {}
+
+func (x *SetPinBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPinBlockRequest.ProtoReflect.Descriptor instead.
+func (*SetPinBlockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetPinBlockRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SetPinBlockRequest) GetPinBlock() string {
+	if x != nil {
+		return x.PinBlock
+	}
+	return ""
+}
+
+type SetPinBlockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPinBlockResponse) Reset() {
+	*x = SetPinBlockResponse{}
+	mi := &file_proto_vault_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPinBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPinBlockResponse) ProtoMessage() {}
+
+func (x *SetPinBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPinBlockResponse.ProtoReflect.Descriptor instead.
+func (*SetPinBlockResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{10}
+}
+
+type VerifyPinBlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	PinBlock      string                 `protobuf:"bytes,2,opt,name=pin_block,json=pinBlock,proto3" json:"pin_block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPinBlockRequest) Reset() {
+	*x = VerifyPinBlockRequest{}
+	mi := &file_proto_vault_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPinBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPinBlockRequest) ProtoMessage() {}
+
+func (x *VerifyPinBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPinBlockRequest.ProtoReflect.Descriptor instead.
+func (*VerifyPinBlockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VerifyPinBlockRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *VerifyPinBlockRequest) GetPinBlock() string {
+	if x != nil {
+		return x.PinBlock
+	}
+	return ""
+}
+
+type VerifyPinBlockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Match         bool                   `protobuf:"varint,1,opt,name=match,proto3" json:"match,omitempty"` // whether pin_block carries the card's PIN
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPinBlockResponse) Reset() {
+	*x = VerifyPinBlockResponse{}
+	mi := &file_proto_vault_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPinBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPinBlockResponse) ProtoMessage() {}
+
+func (x *VerifyPinBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vault_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPinBlockResponse.ProtoReflect.Descriptor instead.
+func (*VerifyPinBlockResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vault_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *VerifyPinBlockResponse) GetMatch() bool {
+	if x != nil {
+		return x.Match
+	}
+	return false
+}
+
+var File_proto_vault_proto protoreflect.FileDescriptor
+
+const file_proto_vault_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/vault.proto\"I\n" +
+	"\x16IssueCardNumberRequest\x12\x10\n" +
+	"\x03bin\x18\x01 \x01(\tR\x03bin\x12\x1d\n" +
+	"\n" +
+	"pan_length\x18\x02 \x01(\x05R\tpanLength\"E\n" +
+	"\x10IssuedCardNumber\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1b\n" +
+	"\tlast_four\x18\x02 \x01(\tR\blastFour\"5\n" +
+	"\x0fTokenizeRequest\x12\x10\n" +
+	"\x03pan\x18\x01 \x01(\tR\x03pan\x12\x10\n" +
+	"\x03cvv\x18\x02 \x01(\tR\x03cvv\"&\n" +
+	"\x12LookupTokenRequest\x12\x10\n" +
+	"\x03pan\x18\x01 \x01(\tR\x03pan\"\x1d\n" +
+	"\x05Token\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"Q\n" +
+	"\x11DetokenizeRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x18\n" +
+	"\apurpose\x18\x03 \x01(\tR\apurposeJ\x04\b\x02\x10\x03R\x06caller\"K\n" +
+	"\bCardData\x12\x10\n" +
+	"\x03pan\x18\x01 \x01(\tR\x03pan\x12\x10\n" +
+	"\x03cvv\x18\x02 \x01(\tR\x03cvv\x12\x1b\n" +
+	"\tpin_block\x18\x03 \x01(\tR\bpinBlock\"2\n" +
+	"\x11RotateKeysRequest\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x01 \x01(\x05R\tbatchSize\"u\n" +
+	"\x12RotateKeysResponse\x12\x1f\n" +
+	"\vkey_version\x18\x01 \x01(\x05R\n" +
+	"keyVersion\x12 \n" +
+	"\vreencrypted\x18\x02 \x01(\x05R\vreencrypted\x12\x1c\n" +
+	"\tremaining\x18\x03 \x01(\x03R\tremaining\"G\n" +
+	"\x12SetPinBlockRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1b\n" +
+	"\tpin_block\x18\x02 \x01(\tR\bpinBlock\"\x15\n" +
+	"\x13SetPinBlockResponse\"J\n" +
+	"\x15VerifyPinBlockRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1b\n" +
+	"\tpin_block\x18\x02 \x01(\tR\bpinBlock\".\n" +
+	"\x16VerifyPinBlockResponse\x12\x14\n" +
+	"\x05match\x18\x01 \x01(\bR\x05match2\xf9\x02\n" +
+	"\x05Vault\x12=\n" +
+	"\x0fIssueCardNumber\x12\x17.IssueCardNumberRequest\x1a\x11.IssuedCardNumber\x12$\n" +
+	"\bTokenize\x12\x10.TokenizeRequest\x1a\x06.Token\x12*\n" +
+	"\vLookupToken\x12\x13.LookupTokenRequest\x1a\x06.Token\x12+\n" +
+	"\n" +
+	"Detokenize\x12\x12.DetokenizeRequest\x1a\t.CardData\x125\n" +
+	"\n" +
+	"RotateKeys\x12\x12.RotateKeysRequest\x1a\x13.RotateKeysResponse\x128\n" +
+	"\vSetPinBlock\x12\x13.SetPinBlockRequest\x1a\x14.SetPinBlockResponse\x12A\n" +
+	"\x0eVerifyPinBlock\x12\x16.VerifyPinBlockRequest\x1a\x17.VerifyPinBlockResponseB\tZ\a./vaultb\x06proto3"
+
+var (
+	file_proto_vault_proto_rawDescOnce sync.Once
+	file_proto_vault_proto_rawDescData []byte
+)
+
+func file_proto_vault_proto_rawDescGZIP() []byte {
+	file_proto_vault_proto_rawDescOnce.Do(func() {
+		file_proto_vault_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_vault_proto_rawDesc), len(file_proto_vault_proto_rawDesc)))
+	})
+	return file_proto_vault_proto_rawDescData
+}
+
+var file_proto_vault_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_vault_proto_goTypes = []any{
+	(*IssueCardNumberRequest)(nil), // 0: IssueCardNumberRequest
+	(*IssuedCardNumber)(nil),       // 1: IssuedCardNumber
+	(*TokenizeRequest)(nil),        // 2: TokenizeRequest
+	(*LookupTokenRequest)(nil),     // 3: LookupTokenRequest
+	(*Token)(nil),                  // 4: Token
+	(*DetokenizeRequest)(nil),      // 5: DetokenizeRequest
+	(*CardData)(nil),               // 6: CardData
+	(*RotateKeysRequest)(nil),      // 7: RotateKeysRequest
+	(*RotateKeysResponse)(nil),     // 8: RotateKeysResponse
+	(*SetPinBlockRequest)(nil),     // 9: SetPinBlockRequest
+	(*SetPinBlockResponse)(nil),    // 10: SetPinBlockResponse
+	(*VerifyPinBlockRequest)(nil),  // 11: VerifyPinBlockRequest
+	(*VerifyPinBlockResponse)(nil), // 12: VerifyPinBlockResponse
+}
+var file_proto_vault_proto_depIdxs = []int32{
+	0,  // 0: Vault.IssueCardNumber:input_type -> IssueCardNumberRequest
+	2,  // 1: Vault.Tokenize:input_type -> TokenizeRequest
+	3,  // 2: Vault.LookupToken:input_type -> LookupTokenRequest
+	5,  // 3: Vault.Detokenize:input_type -> DetokenizeRequest
+	7,  // 4: Vault.RotateKeys:input_type -> RotateKeysRequest
+	9,  // 5: Vault.SetPinBlock:input_type -> SetPinBlockRequest
+	11, // 6: Vault.VerifyPinBlock:input_type -> VerifyPinBlockRequest
+	1,  // 7: Vault.IssueCardNumber:output_type -> IssuedCardNumber
+	4,  // 8: Vault.Tokenize:output_type -> Token
+	4,  // 9: Vault.LookupToken:output_type -> Token
+	6,  // 10: Vault.Detokenize:output_type -> CardData
+	8,  // 11: Vault.RotateKeys:output_type -> RotateKeysResponse
+	10, // 12: Vault.SetPinBlock:output_type -> SetPinBlockResponse
+	12, // 13: Vault.VerifyPinBlock:output_type -> VerifyPinBlockResponse
+	7,  // [7:14] is the sub-list for method output_type
+	0,  // [0:7] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_vault_proto_init() }
+func file_proto_vault_proto_init() {
+	if File_proto_vault_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_vault_proto_rawDesc), len(file_proto_vault_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_vault_proto_goTypes,
+		DependencyIndexes: file_proto_vault_proto_depIdxs,
+		MessageInfos:      file_proto_vault_proto_msgTypes,
+	}.Build()
+	File_proto_vault_proto = out.File
+	file_proto_vault_proto_goTypes = nil
+	file_proto_vault_proto_depIdxs = nil
+}
@@ -0,0 +1,357 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/vault.proto
+
+package vault
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Vault_IssueCardNumber_FullMethodName = "/Vault/IssueCardNumber"
+	Vault_Tokenize_FullMethodName        = "/Vault/Tokenize"
+	Vault_LookupToken_FullMethodName     = "/Vault/LookupToken"
+	Vault_Detokenize_FullMethodName      = "/Vault/Detokenize"
+	Vault_RotateKeys_FullMethodName      = "/Vault/RotateKeys"
+	Vault_SetPinBlock_FullMethodName     = "/Vault/SetPinBlock"
+	Vault_VerifyPinBlock_FullMethodName  = "/Vault/VerifyPinBlock"
+)
+
+// VaultClient is the client API for Vault service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Vault holds card numbers, CVVs and PIN blocks, the only service that does. Other services refer to a
+// card's details by an opaque token. Each card's details are encrypted with their own data key,
+// which is in turn encrypted with a key-encryption key held by the KMS (envelope encryption).
+type VaultClient interface {
+	IssueCardNumber(ctx context.Context, in *IssueCardNumberRequest, opts ...grpc.CallOption) (*IssuedCardNumber, error)
+	Tokenize(ctx context.Context, in *TokenizeRequest, opts ...grpc.CallOption) (*Token, error)
+	LookupToken(ctx context.Context, in *LookupTokenRequest, opts ...grpc.CallOption) (*Token, error)
+	Detokenize(ctx context.Context, in *DetokenizeRequest, opts ...grpc.CallOption) (*CardData, error)
+	RotateKeys(ctx context.Context, in *RotateKeysRequest, opts ...grpc.CallOption) (*RotateKeysResponse, error)
+	SetPinBlock(ctx context.Context, in *SetPinBlockRequest, opts ...grpc.CallOption) (*SetPinBlockResponse, error)
+	VerifyPinBlock(ctx context.Context, in *VerifyPinBlockRequest, opts ...grpc.CallOption) (*VerifyPinBlockResponse, error)
+}
+
+type vaultClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVaultClient(cc grpc.ClientConnInterface) VaultClient {
+	return &vaultClient{cc}
+}
+
+func (c *vaultClient) IssueCardNumber(ctx context.Context, in *IssueCardNumberRequest, opts ...grpc.CallOption) (*IssuedCardNumber, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IssuedCardNumber)
+	err := c.cc.Invoke(ctx, Vault_IssueCardNumber_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) Tokenize(ctx context.Context, in *TokenizeRequest, opts ...grpc.CallOption) (*Token, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Token)
+	err := c.cc.Invoke(ctx, Vault_Tokenize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) LookupToken(ctx context.Context, in *LookupTokenRequest, opts ...grpc.CallOption) (*Token, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Token)
+	err := c.cc.Invoke(ctx, Vault_LookupToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) Detokenize(ctx context.Context, in *DetokenizeRequest, opts ...grpc.CallOption) (*CardData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CardData)
+	err := c.cc.Invoke(ctx, Vault_Detokenize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) RotateKeys(ctx context.Context, in *RotateKeysRequest, opts ...grpc.CallOption) (*RotateKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateKeysResponse)
+	err := c.cc.Invoke(ctx, Vault_RotateKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) SetPinBlock(ctx context.Context, in *SetPinBlockRequest, opts ...grpc.CallOption) (*SetPinBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPinBlockResponse)
+	err := c.cc.Invoke(ctx, Vault_SetPinBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) VerifyPinBlock(ctx context.Context, in *VerifyPinBlockRequest, opts ...grpc.CallOption) (*VerifyPinBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyPinBlockResponse)
+	err := c.cc.Invoke(ctx, Vault_VerifyPinBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VaultServer is the server API for Vault service.
+// All implementations must embed UnimplementedVaultServer
+// for forward compatibility.
+//
+// Vault holds card numbers, CVVs and PIN blocks, the only service that does. Other services refer to a
+// card's details by an opaque token. Each card's details are encrypted with their own data key,
+// which is in turn encrypted with a key-encryption key held by the KMS (envelope encryption).
+type VaultServer interface {
+	IssueCardNumber(context.Context, *IssueCardNumberRequest) (*IssuedCardNumber, error)
+	Tokenize(context.Context, *TokenizeRequest) (*Token, error)
+	LookupToken(context.Context, *LookupTokenRequest) (*Token, error)
+	Detokenize(context.Context, *DetokenizeRequest) (*CardData, error)
+	RotateKeys(context.Context, *RotateKeysRequest) (*RotateKeysResponse, error)
+	SetPinBlock(context.Context, *SetPinBlockRequest) (*SetPinBlockResponse, error)
+	VerifyPinBlock(context.Context, *VerifyPinBlockRequest) (*VerifyPinBlockResponse, error)
+	mustEmbedUnimplementedVaultServer()
+}
+
+// UnimplementedVaultServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVaultServer struct{}
+
+func (UnimplementedVaultServer) IssueCardNumber(context.Context, *IssueCardNumberRequest) (*IssuedCardNumber, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueCardNumber not implemented")
+}
+func (UnimplementedVaultServer) Tokenize(context.Context, *TokenizeRequest) (*Token, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Tokenize not implemented")
+}
+func (UnimplementedVaultServer) LookupToken(context.Context, *LookupTokenRequest) (*Token, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupToken not implemented")
+}
+func (UnimplementedVaultServer) Detokenize(context.Context, *DetokenizeRequest) (*CardData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Detokenize not implemented")
+}
+func (UnimplementedVaultServer) RotateKeys(context.Context, *RotateKeysRequest) (*RotateKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateKeys not implemented")
+}
+func (UnimplementedVaultServer) SetPinBlock(context.Context, *SetPinBlockRequest) (*SetPinBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPinBlock not implemented")
+}
+func (UnimplementedVaultServer) VerifyPinBlock(context.Context, *VerifyPinBlockRequest) (*VerifyPinBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyPinBlock not implemented")
+}
+func (UnimplementedVaultServer) mustEmbedUnimplementedVaultServer() {}
+func (UnimplementedVaultServer) testEmbeddedByValue()               {}
+
+// UnsafeVaultServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VaultServer will
+// result in compilation errors.
+type UnsafeVaultServer interface {
+	mustEmbedUnimplementedVaultServer()
+}
+
+func RegisterVaultServer(s grpc.ServiceRegistrar, srv VaultServer) {
+	// If the following call pancis, it indicates UnimplementedVaultServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Vault_ServiceDesc, srv)
+}
+
+func _Vault_IssueCardNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueCardNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).IssueCardNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_IssueCardNumber_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).IssueCardNumber(ctx, req.(*IssueCardNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_Tokenize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).Tokenize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_Tokenize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).Tokenize(ctx, req.(*TokenizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_LookupToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).LookupToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_LookupToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).LookupToken(ctx, req.(*LookupTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_Detokenize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetokenizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).Detokenize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_Detokenize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).Detokenize(ctx, req.(*DetokenizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_RotateKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).RotateKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_RotateKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).RotateKeys(ctx, req.(*RotateKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_SetPinBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPinBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).SetPinBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_SetPinBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).SetPinBlock(ctx, req.(*SetPinBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_VerifyPinBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyPinBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).VerifyPinBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vault_VerifyPinBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).VerifyPinBlock(ctx, req.(*VerifyPinBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Vault_ServiceDesc is the grpc.ServiceDesc for Vault service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Vault_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Vault",
+	HandlerType: (*VaultServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IssueCardNumber",
+			Handler:    _Vault_IssueCardNumber_Handler,
+		},
+		{
+			MethodName: "Tokenize",
+			Handler:    _Vault_Tokenize_Handler,
+		},
+		{
+			MethodName: "LookupToken",
+			Handler:    _Vault_LookupToken_Handler,
+		},
+		{
+			MethodName: "Detokenize",
+			Handler:    _Vault_Detokenize_Handler,
+		},
+		{
+			MethodName: "RotateKeys",
+			Handler:    _Vault_RotateKeys_Handler,
+		},
+		{
+			MethodName: "SetPinBlock",
+			Handler:    _Vault_SetPinBlock_Handler,
+		},
+		{
+			MethodName: "VerifyPinBlock",
+			Handler:    _Vault_VerifyPinBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/vault.proto",
+}
@@ -0,0 +1,58 @@
+package pinblock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	block, err := Encode("1234")
+	assert.NoError(t, err)
+	assert.Len(t, block, 16)
+	assert.Equal(t, "141234", block[:6])
+
+	pin, err := Decode(block)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", pin)
+
+	// The fill is random, so the same PIN gives different blocks
+	other, err := Encode("1234")
+	assert.NoError(t, err)
+	assert.NotEqual(t, block, other)
+
+	for _, pin := range []string{"123", "1234567890123", "12a4", ""} {
+		_, err := Encode(pin)
+		assert.Error(t, err, pin)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		block string
+		pin   string
+		err   error
+	}{
+		{"141234ABCDEF0123", "1234", nil},
+		{"141234abcdef0123", "1234", nil},
+		{"1C123456789012AB", "123456789012", nil},
+		{"041234FFFFFFFFFF", "", ErrInvalid}, // format 0
+		{"131234ABCDEF0123", "", ErrInvalid}, // too short a PIN
+		{"14123AABCDEF0123", "", ErrInvalid}, // not all digits
+		{"141234ABCDEF012G", "", ErrInvalid}, // not hex
+		{"141234ABCDEF012", "", ErrInvalid},  // too short a block
+		{"", "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		pin, err := Decode(tt.block)
+		assert.Equal(t, tt.err, err, tt.block)
+		assert.Equal(t, tt.pin, pin, tt.block)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, Match("141234ABCDEF0123", "1412340000000000"))
+	assert.False(t, Match("141234ABCDEF0123", "1412350000000000"))
+	assert.False(t, Match("141234ABCDEF0123", "15123400000000000"))
+	assert.False(t, Match("", ""))
+}
@@ -0,0 +1,38 @@
+package sca
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	key := []byte("sca-key")
+	now := time.Date(2025, time.March, 14, 15, 30, 0, 0, time.UTC)
+	token := Issue(key, "user-123", "reveal_pin:card-123", now.Add(5*time.Minute))
+
+	tests := []struct {
+		name   string
+		key    []byte
+		token  string
+		userID string
+		action string
+		now    time.Time
+		err    error
+	}{
+		{"valid", key, token, "user-123", "reveal_pin:card-123", now, nil},
+		{"expired", key, token, "user-123", "reveal_pin:card-123", now.Add(5 * time.Minute), ErrExpired},
+		{"other user", key, token, "user-456", "reveal_pin:card-123", now, ErrInvalid},
+		{"other action", key, token, "user-123", "reveal_pin:card-456", now, ErrInvalid},
+		{"other key", []byte("other-key"), token, "user-123", "reveal_pin:card-123", now, ErrInvalid},
+		{"tampered", key, "x" + token, "user-123", "reveal_pin:card-123", now, ErrInvalid},
+		{"no signature", key, "abc", "user-123", "reveal_pin:card-123", now, ErrInvalid},
+		{"empty", key, "", "user-123", "reveal_pin:card-123", now, ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, Verify(tt.key, tt.token, tt.userID, tt.action, tt.now))
+		})
+	}
+}
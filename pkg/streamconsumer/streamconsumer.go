@@ -0,0 +1,423 @@
+// Package streamconsumer consumes Redis streams through consumer groups with at-least-once delivery.
+//
+// Every replica joins the group under its own consumer name. Messages a consumer took but never
+// acknowledged, for example because its replica crashed, are reclaimed by the others with XAUTOCLAIM
+// once they have been idle for a while. A message whose handler keeps failing is retried with
+// exponential backoff; once its attempts are used up it is copied to the "<stream>:dlq" dead-letter
+// stream and acknowledged, so one bad message cannot hold up the group.
+package streamconsumer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Consumer defaults
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 500 * time.Millisecond
+	maxBackoff         = 30 * time.Second
+	defaultMinIdle     = time.Minute
+	defaultConcurrency = 1
+	defaultBatchSize   = 10
+	blockTimeout       = 5 * time.Second // how long a read waits for new messages, bounding shutdown latency
+)
+
+// Fields added to a message when it is dead-lettered, next to its original fields
+const (
+	FieldOriginalID = "dlq_original_id"
+	FieldGroup      = "dlq_group"
+	FieldError      = "dlq_error"
+	FieldAttempts   = "dlq_attempts"
+)
+
+// DeadLetterStream returns the name of the dead-letter stream for stream
+func DeadLetterStream(stream string) string {
+	return stream + ":dlq"
+}
+
+// ConsumerName returns a consumer name unique to this process, e.g. "enrichment-host-1a2b3c4d"
+func ConsumerName(prefix string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%s-%s", prefix, host, hex.EncodeToString(suffix))
+}
+
+// Handler processes one message. Returning an error retries the message; wrap the error
+// with Permanent to dead-letter it straight away.
+type Handler func(ctx context.Context, msg redis.XMessage) error
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one that retrying cannot fix, such as a malformed message
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Consumer reads a stream as one member of a consumer group
+type Consumer struct {
+	redisClient *redis.Client
+	stream      string
+	group       string
+	handler     Handler
+	name        string
+	maxAttempts int
+	backoff     time.Duration
+	minIdle     time.Duration
+	concurrency int
+	claimStart  string // XAUTOCLAIM cursor
+}
+
+// Option configures optional Consumer settings
+type Option func(*Consumer)
+
+// WithConsumerName sets the consumer's name within the group. Names must be unique per replica.
+func WithConsumerName(name string) Option {
+	return func(c *Consumer) {
+		if name != "" {
+			c.name = name
+		}
+	}
+}
+
+// WithMaxAttempts sets how many times a message is handled before it is dead-lettered
+func WithMaxAttempts(n int) Option {
+	return func(c *Consumer) {
+		if n > 0 {
+			c.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry; it doubles with every further attempt
+func WithBackoff(d time.Duration) Option {
+	return func(c *Consumer) {
+		if d > 0 {
+			c.backoff = d
+		}
+	}
+}
+
+// WithMinIdle sets how long a message must have been pending before another consumer reclaims it
+func WithMinIdle(d time.Duration) Option {
+	return func(c *Consumer) {
+		if d > 0 {
+			c.minIdle = d
+		}
+	}
+}
+
+// WithConcurrency sets how many messages are handled at once. With more than one, messages
+// may be handled out of order.
+func WithConcurrency(n int) Option {
+	return func(c *Consumer) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// New creates a consumer that calls handler for each message on stream delivered to group
+func New(redisClient *redis.Client, stream, group string, handler Handler, opts ...Option) *Consumer {
+	c := &Consumer{
+		redisClient: redisClient,
+		stream:      stream,
+		group:       group,
+		handler:     handler,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		minIdle:     defaultMinIdle,
+		concurrency: defaultConcurrency,
+		claimStart:  "0-0",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.name == "" {
+		c.name = ConsumerName(group)
+	}
+	return c
+}
+
+// Name returns the consumer's name within its group
+func (c *Consumer) Name() string {
+	return c.name
+}
+
+type delivery struct {
+	msg        redis.XMessage
+	deliveries int64 // times the group has delivered msg, including this one
+}
+
+// Run consumes messages until ctx is cancelled, creating the group if needed.
+//
+// On cancellation Run stops reading and waits for messages already being handled to finish;
+// their handlers get a context that is not cancelled. Messages read but not yet handled stay
+// pending and are reclaimed by another consumer.
+func (c *Consumer) Run(ctx context.Context) error {
+	if err := c.redisClient.XGroupCreateMkStream(ctx, c.stream, c.group, "0").Err(); err != nil {
+		// Ignore BUSYGROUP error if group already exists
+		if !strings.Contains(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create Redis consumer group %s: %w", c.group, err)
+		}
+	}
+	log.Printf("Consuming stream %s as %s in group %s", c.stream, c.name, c.group)
+
+	handlerCtx := context.WithoutCancel(ctx)
+	deliveries := make(chan delivery)
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range deliveries {
+				c.process(handlerCtx, ctx, d)
+			}
+		}()
+	}
+	defer func() {
+		close(deliveries)
+		wg.Wait()
+	}()
+
+	dispatch := func(batch []delivery) bool {
+		for _, d := range batch {
+			select {
+			case deliveries <- d:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	claimInterval := c.minIdle / 2
+	var lastClaim time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= claimInterval {
+			lastClaim = time.Now()
+			claimed, err := c.reclaim(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("failed to reclaim pending messages from stream %s: %v", c.stream, err)
+			}
+			if !dispatch(claimed) {
+				break
+			}
+		}
+
+		batch, err := c.read(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("error reading from Redis stream %s: %v", c.stream, err)
+			time.Sleep(time.Second) // Wait before retrying
+			continue
+		}
+		if !dispatch(batch) {
+			break
+		}
+	}
+	return ctx.Err()
+}
+
+// read waits for new messages delivered to this consumer
+func (c *Consumer) read(ctx context.Context) ([]delivery, error) {
+	streams, err := c.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.name,
+		Streams:  []string{c.stream, ">"},
+		Count:    defaultBatchSize,
+		Block:    blockTimeout,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil // No new messages before the block timeout
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var batch []delivery
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			batch = append(batch, delivery{msg: msg, deliveries: 1})
+		}
+	}
+	return batch, nil
+}
+
+// reclaim takes over one batch of messages that have been pending in the group for longer than minIdle
+func (c *Consumer) reclaim(ctx context.Context) ([]delivery, error) {
+	msgs, next, err := c.autoClaim(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.claimStart = next
+
+	var batch []delivery
+	for _, msg := range msgs {
+		// XAUTOCLAIM has already counted this delivery
+		pending, err := c.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: c.stream,
+			Group:  c.group,
+			Start:  msg.ID,
+			End:    msg.ID,
+			Count:  1,
+		}).Result()
+		if err != nil {
+			return batch, fmt.Errorf("failed to get delivery count of message %s: %w", msg.ID, err)
+		}
+		d := delivery{msg: msg, deliveries: 1}
+		if len(pending) == 1 {
+			d.deliveries = pending[0].RetryCount
+		}
+		log.Printf("Reclaimed message %s from stream %s (delivery %d)", msg.ID, c.stream, d.deliveries)
+		batch = append(batch, d)
+	}
+	return batch, nil
+}
+
+// autoClaim runs XAUTOCLAIM from the saved cursor. The command is sent raw because Redis 7 adds
+// a third element to the reply, which the client's typed XAutoClaim rejects.
+func (c *Consumer) autoClaim(ctx context.Context) ([]redis.XMessage, string, error) {
+	reply, err := c.redisClient.Do(ctx, "XAUTOCLAIM", c.stream, c.group, c.name,
+		c.minIdle.Milliseconds(), c.claimStart, "COUNT", defaultBatchSize).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) < 2 {
+		return nil, "", fmt.Errorf("unexpected XAUTOCLAIM reply %v", reply)
+	}
+	next, ok := parts[0].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected XAUTOCLAIM cursor %v", parts[0])
+	}
+	entries, _ := parts[1].([]interface{})
+
+	var msgs []redis.XMessage
+	for _, entry := range entries {
+		// Redis 6.2 returns nil for messages deleted from the stream while pending
+		fields, ok := entry.([]interface{})
+		if !ok || len(fields) != 2 {
+			continue
+		}
+		id, _ := fields[0].(string)
+		kv, _ := fields[1].([]interface{})
+		values := make(map[string]interface{}, len(kv)/2)
+		for i := 0; i+1 < len(kv); i += 2 {
+			if k, ok := kv[i].(string); ok {
+				values[k] = kv[i+1]
+			}
+		}
+		msgs = append(msgs, redis.XMessage{ID: id, Values: values})
+	}
+	return msgs, next, nil
+}
+
+// process handles a message, retrying it with backoff, and acknowledges it once it has been
+// handled or dead-lettered. If stop is cancelled while waiting to retry, the message is left pending.
+func (c *Consumer) process(ctx, stop context.Context, d delivery) {
+	if d.deliveries > int64(c.maxAttempts) {
+		c.deadLetter(ctx, d.msg, fmt.Errorf("delivered %d times without being acknowledged", d.deliveries), 0)
+		return
+	}
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		err = c.handle(ctx, d.msg)
+		if err == nil {
+			if err := c.redisClient.XAck(ctx, c.stream, c.group, d.msg.ID).Err(); err != nil {
+				log.Printf("failed to acknowledge message %s: %v", d.msg.ID, err)
+			}
+			return
+		}
+
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt >= c.maxAttempts {
+			break
+		}
+		log.Printf("failed to handle message %s from stream %s (attempt %d of %d): %v", d.msg.ID, c.stream, attempt, c.maxAttempts, err)
+
+		select {
+		case <-stop.Done():
+			return
+		case <-time.After(c.backoffFor(attempt)):
+		}
+	}
+	c.deadLetter(ctx, d.msg, err, attempt)
+}
+
+// handle calls the handler, turning a panic into an error so a bad message cannot crash the consumer
+func (c *Consumer) handle(ctx context.Context, msg redis.XMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return c.handler(ctx, msg)
+}
+
+// backoffFor returns the delay after the given failed attempt
+func (c *Consumer) backoffFor(attempt int) time.Duration {
+	d := c.backoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// deadLetter copies msg to the dead-letter stream with the reason it failed and acknowledges it.
+// If the copy fails the message stays pending and is dead-lettered again when reclaimed.
+func (c *Consumer) deadLetter(ctx context.Context, msg redis.XMessage, cause error, attempts int) {
+	keys := make([]string, 0, len(msg.Values))
+	for k := range msg.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]interface{}, 0, 2*len(keys)+8)
+	for _, k := range keys {
+		values = append(values, k, msg.Values[k])
+	}
+	values = append(values,
+		FieldOriginalID, msg.ID,
+		FieldGroup, c.group,
+		FieldError, cause.Error(),
+		FieldAttempts, strconv.Itoa(attempts),
+	)
+
+	dlq := DeadLetterStream(c.stream)
+	if err := c.redisClient.XAdd(ctx, &redis.XAddArgs{Stream: dlq, Values: values}).Err(); err != nil {
+		log.Printf("failed to dead-letter message %s from stream %s: %v", msg.ID, c.stream, err)
+		return
+	}
+	log.Printf("Moved message %s from stream %s to %s: %v", msg.ID, c.stream, dlq, cause)
+
+	if err := c.redisClient.XAck(ctx, c.stream, c.group, msg.ID).Err(); err != nil {
+		log.Printf("failed to acknowledge message %s: %v", msg.ID, err)
+	}
+}
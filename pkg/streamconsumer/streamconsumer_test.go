@@ -0,0 +1,187 @@
+package streamconsumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConsumer(handler Handler, opts ...Option) (*Consumer, redismock.ClientMock) {
+	redisClient, mockRedis := redismock.NewClientMock()
+	opts = append([]Option{WithConsumerName("test-1"), WithBackoff(time.Millisecond), WithMaxAttempts(3)}, opts...)
+	return New(redisClient, "transaction:created", "test-group", handler, opts...), mockRedis
+}
+
+func TestConsumerName_Unique(t *testing.T) {
+	a := ConsumerName("enrichment")
+	b := ConsumerName("enrichment")
+
+	assert.Contains(t, a, "enrichment-")
+	assert.NotEqual(t, a, b)
+}
+
+func TestProcess_Acknowledges(t *testing.T) {
+	var handled []string
+	c, mockRedis := newTestConsumer(func(ctx context.Context, msg redis.XMessage) error {
+		handled = append(handled, msg.ID)
+		return nil
+	})
+	mockRedis.ExpectXAck("transaction:created", "test-group", "1-0").SetVal(1)
+
+	ctx := context.Background()
+	c.process(ctx, ctx, delivery{msg: redis.XMessage{ID: "1-0"}, deliveries: 1})
+
+	assert.Equal(t, []string{"1-0"}, handled)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestProcess_RetriesThenDeadLetters(t *testing.T) {
+	attempts := 0
+	c, mockRedis := newTestConsumer(func(ctx context.Context, msg redis.XMessage) error {
+		attempts++
+		return errors.New("merchant service unavailable")
+	})
+	mockRedis.ExpectXAdd(&redis.XAddArgs{
+		Stream: "transaction:created:dlq",
+		Values: []interface{}{
+			"payload", "p", "schema_version", "1",
+			"dlq_original_id", "1-0", "dlq_group", "test-group", "dlq_error", "merchant service unavailable", "dlq_attempts", "3",
+		},
+	}).SetVal("5-0")
+	mockRedis.ExpectXAck("transaction:created", "test-group", "1-0").SetVal(1)
+
+	ctx := context.Background()
+	msg := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"schema_version": "1", "payload": "p"}}
+	c.process(ctx, ctx, delivery{msg: msg, deliveries: 1})
+
+	assert.Equal(t, 3, attempts)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestProcess_PanicsAndPermanentErrors(t *testing.T) {
+	attempts := 0
+	c, mockRedis := newTestConsumer(func(ctx context.Context, msg redis.XMessage) error {
+		attempts++
+		panic("malformed payload")
+	})
+	mockRedis.ExpectXAdd(&redis.XAddArgs{
+		Stream: "transaction:created:dlq",
+		Values: []interface{}{"dlq_original_id", "1-0", "dlq_group", "test-group", "dlq_error", "handler panicked: malformed payload", "dlq_attempts", "3"},
+	}).SetVal("5-0")
+	mockRedis.ExpectXAck("transaction:created", "test-group", "1-0").SetVal(1)
+
+	ctx := context.Background()
+	c.process(ctx, ctx, delivery{msg: redis.XMessage{ID: "1-0"}, deliveries: 1})
+	assert.Equal(t, 3, attempts)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+
+	// A Permanent error is dead-lettered after the first attempt
+	attempts = 0
+	c.handler = func(ctx context.Context, msg redis.XMessage) error {
+		attempts++
+		return Permanent(errors.New("unknown schema version"))
+	}
+	mockRedis.ExpectXAdd(&redis.XAddArgs{
+		Stream: "transaction:created:dlq",
+		Values: []interface{}{"dlq_original_id", "2-0", "dlq_group", "test-group", "dlq_error", "unknown schema version", "dlq_attempts", "1"},
+	}).SetVal("6-0")
+	mockRedis.ExpectXAck("transaction:created", "test-group", "2-0").SetVal(1)
+
+	c.process(ctx, ctx, delivery{msg: redis.XMessage{ID: "2-0"}, deliveries: 1})
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestProcess_LeavesMessagePendingOnShutdown(t *testing.T) {
+	c, mockRedis := newTestConsumer(func(ctx context.Context, msg redis.XMessage) error {
+		return errors.New("feed service unavailable")
+	}, WithBackoff(time.Hour))
+
+	ctx := context.Background()
+	stop, cancel := context.WithCancel(ctx)
+	cancel()
+	c.process(ctx, stop, delivery{msg: redis.XMessage{ID: "1-0"}, deliveries: 1})
+
+	// Neither acknowledged nor dead-lettered
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestReclaim(t *testing.T) {
+	c, mockRedis := newTestConsumer(func(ctx context.Context, msg redis.XMessage) error { return nil })
+
+	mockRedis.ExpectDo("XAUTOCLAIM", "transaction:created", "test-group", "test-1", int64(60000), "0-0", "COUNT", 10).
+		SetVal([]interface{}{
+			"7-0",
+			[]interface{}{
+				[]interface{}{"1-0", []interface{}{"payload", "p"}},
+				nil, // deleted while pending
+			},
+			[]interface{}{},
+		})
+	mockRedis.ExpectXPendingExt(&redis.XPendingExtArgs{Stream: "transaction:created", Group: "test-group", Start: "1-0", End: "1-0", Count: 1}).
+		SetVal([]redis.XPendingExt{{ID: "1-0", Consumer: "test-1", RetryCount: 4}})
+
+	claimed, err := c.reclaim(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, "1-0", claimed[0].msg.ID)
+	assert.Equal(t, "p", claimed[0].msg.Values["payload"])
+	assert.Equal(t, int64(4), claimed[0].deliveries)
+	assert.Equal(t, "7-0", c.claimStart)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestProcess_DeadLettersAfterTooManyDeliveries(t *testing.T) {
+	called := false
+	c, mockRedis := newTestConsumer(func(ctx context.Context, msg redis.XMessage) error {
+		called = true
+		return nil
+	})
+	mockRedis.ExpectXAdd(&redis.XAddArgs{
+		Stream: "transaction:created:dlq",
+		Values: []interface{}{"dlq_original_id", "1-0", "dlq_group", "test-group", "dlq_error", "delivered 4 times without being acknowledged", "dlq_attempts", "0"},
+	}).SetVal("5-0")
+	mockRedis.ExpectXAck("transaction:created", "test-group", "1-0").SetVal(1)
+
+	ctx := context.Background()
+	c.process(ctx, ctx, delivery{msg: redis.XMessage{ID: "1-0"}, deliveries: 4})
+
+	assert.False(t, called)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}
+
+func TestRun_FinishesInFlightMessageOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var handlerCtxErr error
+	c, mockRedis := newTestConsumer(func(handlerCtx context.Context, msg redis.XMessage) error {
+		cancel() // shut down while the message is being handled
+		handlerCtxErr = handlerCtx.Err()
+		return nil
+	})
+	mockRedis.MatchExpectationsInOrder(false)
+	mockRedis.ExpectXGroupCreateMkStream("transaction:created", "test-group", "0").SetVal("OK")
+	mockRedis.ExpectDo("XAUTOCLAIM", "transaction:created", "test-group", "test-1", int64(60000), "0-0", "COUNT", 10).
+		SetVal([]interface{}{"0-0", []interface{}{}})
+	mockRedis.ExpectXReadGroup(&redis.XReadGroupArgs{
+		Group:    "test-group",
+		Consumer: "test-1",
+		Streams:  []string{"transaction:created", ">"},
+		Count:    10,
+		Block:    5 * time.Second,
+	}).SetVal([]redis.XStream{{Stream: "transaction:created", Messages: []redis.XMessage{{ID: "1-0"}}}})
+	mockRedis.ExpectXAck("transaction:created", "test-group", "1-0").SetVal(1)
+
+	err := c.Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, handlerCtxErr)
+	assert.NoError(t, mockRedis.ExpectationsWereMet())
+}